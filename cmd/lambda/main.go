@@ -2,8 +2,8 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 
-	"github.com/aws/aws-lambda-go/events"
 	"github.com/aws/aws-lambda-go/lambda"
 
 	lambdaAdapter "parking-lot/pkg/lambda"
@@ -16,16 +16,23 @@ func init() {
 }
 
 func main() {
-	lambda.Start(handler)
+	// Close the registered resources when Lambda shuts the environment down. The runtime only
+	// receives SIGTERM when an extension is registered; without one pending work is flushed on the
+	// next invocation that thaws the environment.
+	lambda.StartWithOptions(handler, lambda.WithEnableSIGTERM(func() {
+		adapter.Cleanup(context.Background())
+	}))
 }
 
-func handler(ctx context.Context, req events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
-	response, err := adapter.ProxyWithContext(ctx, req)
+// handler serves both REST API (payload 1.0) and HTTP API (payload 2.0) events; the adapter picks
+// the path from the event itself
+func handler(ctx context.Context, event json.RawMessage) (any, error) {
+	response, err := adapter.Handle(ctx, event)
 
-	// Ensure we perform cleanup on Lambda cold starts
+	// Export telemetry before the environment is frozen; closing resources is left to shutdown
 	defer func() {
 		if ctx.Err() == nil {
-			adapter.Cleanup(context.Background())
+			adapter.Flush(context.Background())
 		}
 	}()
 
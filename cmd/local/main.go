@@ -3,11 +3,14 @@ package main
 import (
 	"context"
 
+	"parking-lot/internal/logger"
 	lambdaAdapter "parking-lot/pkg/lambda"
 )
 
 func main() {
 	ctx := context.Background()
 	adapter := lambdaAdapter.NewAPIAdapter()
-	adapter.RunLocalServer(ctx)
+	if err := adapter.RunLocalServer(ctx); err != nil {
+		logger.NewLogger().Fatal("Local server failed", logger.Field{Key: "error", Value: err.Error()})
+	}
 }
@@ -16,7 +16,9 @@ provider "aws" {
 resource "aws_dynamodb_table" "parking_tickets" {
   name         = "parkingTickets"
   billing_mode = "PAY_PER_REQUEST"
-  hash_key     = "ticketId"
+  # The composite key puts a plate's tickets in one partition, replacing PlateIndex with TicketIdIndex
+  hash_key     = var.composite_key ? "plate" : "ticketId"
+  range_key    = var.composite_key ? "entryTime" : null
 
   attribute {
     name = "ticketId"
@@ -47,12 +49,52 @@ resource "aws_dynamodb_table" "parking_tickets" {
     name = "charge"
     type = "N"
   }
+
+  attribute {
+    name = "exitTime"
+    type = "S"
+  }
+
+  attribute {
+    name = "sessionId"
+    type = "S"
+  }
+
+  attribute {
+    name = "transponderId"
+    type = "S"
+  }
   
   # Global Secondary Index for plate lookups
-  global_secondary_index {
-    name               = "PlateIndex"
-    hash_key           = "plate"
-    projection_type    = "ALL"
+  dynamic "global_secondary_index" {
+    for_each = var.composite_key ? [] : ["PlateIndex"]
+    content {
+      name            = global_secondary_index.value
+      hash_key        = "plate"
+      projection_type = "ALL"
+    }
+  }
+
+  # Global Secondary Index sorting a plate's tickets by entry time, for plate history and the newest active ticket.
+  # It is separate from PlateIndex so adding it does not rebuild the index entry and exit lookups depend on.
+  dynamic "global_secondary_index" {
+    for_each = var.composite_key ? [] : ["PlateEntryTimeIndex"]
+    content {
+      name            = global_secondary_index.value
+      hash_key        = "plate"
+      range_key       = "entryTime"
+      projection_type = "ALL"
+    }
+  }
+
+  # Global Secondary Index for ticket ID lookups on the composite-key table
+  dynamic "global_secondary_index" {
+    for_each = var.composite_key ? ["TicketIdIndex"] : []
+    content {
+      name            = global_secondary_index.value
+      hash_key        = "ticketId"
+      projection_type = "ALL"
+    }
   }
   
   # Global Secondary Index for parkingLot lookups
@@ -76,6 +118,69 @@ resource "aws_dynamodb_table" "parking_tickets" {
     range_key          = "charge"
     projection_type    = "ALL"
   }
+
+  # Global Secondary Index for exports of exited tickets by exit time; every exited ticket has an exitTime
+  global_secondary_index {
+    name               = "ExitTimeIndex"
+    hash_key           = "status"
+    range_key          = "exitTime"
+    projection_type    = "ALL"
+  }
+
+  # Global Secondary Index for joining a parking session's entry and exit in analytics
+  global_secondary_index {
+    name               = "SessionIndex"
+    hash_key           = "sessionId"
+    projection_type    = "ALL"
+  }
+
+  # Sparse Global Secondary Index for exits by transponder; only tickets entered with one are indexed
+  global_secondary_index {
+    name               = "TransponderIndex"
+    hash_key           = "transponderId"
+    range_key          = "entryTime"
+    projection_type    = "ALL"
+  }
+}
+
+# Append-only event log tickets can be rebuilt from
+resource "aws_dynamodb_table" "parking_events" {
+  name         = "parkingEvents"
+  billing_mode = "PAY_PER_REQUEST"
+  hash_key     = "ticketId"
+  range_key    = "sequence"
+
+  attribute {
+    name = "ticketId"
+    type = "S"
+  }
+
+  attribute {
+    name = "sequence"
+    type = "N"
+  }
+}
+
+# Promo codes that discount the charge at exit
+resource "aws_dynamodb_table" "parking_coupons" {
+  name         = "parkingCoupons"
+  billing_mode = "PAY_PER_REQUEST"
+  hash_key     = "code"
+
+  attribute {
+    name = "code"
+    type = "S"
+  }
+}
+
+# Stream of entry and exit records for real-time analytics
+resource "aws_kinesis_stream" "parking_events" {
+  name             = "parkingEvents"
+  retention_period = 24
+
+  stream_mode_details {
+    stream_mode = "ON_DEMAND"
+  }
 }
 
 # IAM Role for Lambda functions
@@ -100,6 +205,21 @@ resource "aws_iam_role_policy_attachment" "lambda_dynamo_policy" {
   policy_arn = "arn:aws:iam::aws:policy/AmazonDynamoDBFullAccess"
 }
 
+# Allow the Lambda functions to put records on the analytics stream
+resource "aws_iam_role_policy" "lambda_kinesis_policy" {
+  name = "parking_lambda_kinesis"
+  role = aws_iam_role.lambda_role.id
+
+  policy = jsonencode({
+    Version = "2012-10-17"
+    Statement = [{
+      Action   = ["kinesis:PutRecords"]
+      Effect   = "Allow"
+      Resource = aws_kinesis_stream.parking_events.arn
+    }]
+  })
+}
+
 resource "aws_iam_role_policy_attachment" "lambda_log_policy" {
   role       = aws_iam_role.lambda_role.name
   policy_arn = "arn:aws:iam::aws:policy/service-role/AWSLambdaBasicExecutionRole"
@@ -117,7 +237,12 @@ resource "aws_lambda_function" "entry_handler" {
 
   environment {
     variables = {
-      TABLE_NAME = aws_dynamodb_table.parking_tickets.name
+      TABLE_NAME         = aws_dynamodb_table.parking_tickets.name
+      TABLE_KEY_SCHEMA   = var.composite_key ? "composite" : "ticketId"
+      REQUIRE_TABLE_NAME = "true"
+      EVENTS_TABLE_NAME  = aws_dynamodb_table.parking_events.name
+      ADMIN_TOKEN        = var.admin_token
+      KINESIS_STREAM     = aws_kinesis_stream.parking_events.name
     }
   }
 }
@@ -134,11 +259,35 @@ resource "aws_lambda_function" "exit_handler" {
 
   environment {
     variables = {
-      TABLE_NAME = aws_dynamodb_table.parking_tickets.name
+      TABLE_NAME         = aws_dynamodb_table.parking_tickets.name
+      TABLE_KEY_SCHEMA   = var.composite_key ? "composite" : "ticketId"
+      REQUIRE_TABLE_NAME = "true"
+      EVENTS_TABLE_NAME  = aws_dynamodb_table.parking_events.name
+      COUPONS_TABLE_NAME = aws_dynamodb_table.parking_coupons.name
+      KINESIS_STREAM     = aws_kinesis_stream.parking_events.name
     }
   }
 }
 
+# Reconcile each lot's shared occupancy counter on a schedule; the entry function serves the admin API
+resource "aws_cloudwatch_event_rule" "occupancy_reconcile_schedule" {
+  name                = "${var.project_name}-occupancy-reconcile"
+  description         = "Reset each lot's occupancy counter to the spaces of its parked tickets"
+  schedule_expression = var.reconcile_schedule
+}
+
+resource "aws_cloudwatch_event_target" "occupancy_reconcile_target" {
+  rule = aws_cloudwatch_event_rule.occupancy_reconcile_schedule.name
+  arn  = aws_lambda_function.entry_handler.arn
+}
+
+resource "aws_lambda_permission" "events_occupancy_reconcile_permission" {
+  action        = "lambda:InvokeFunction"
+  function_name = aws_lambda_function.entry_handler.function_name
+  principal     = "events.amazonaws.com"
+  source_arn    = aws_cloudwatch_event_rule.occupancy_reconcile_schedule.arn
+}
+
 # API Gateway setup
 resource "aws_api_gateway_rest_api" "parking_api" {
   name        = "Parking Lot API"
@@ -152,12 +301,149 @@ resource "aws_api_gateway_resource" "entry_resource" {
   path_part   = "entry"
 }
 
+resource "aws_api_gateway_resource" "entry_batch_resource" {
+  rest_api_id = aws_api_gateway_rest_api.parking_api.id
+  parent_id   = aws_api_gateway_resource.entry_resource.id
+  path_part   = "batch"
+}
+
 resource "aws_api_gateway_resource" "exit_resource" {
   rest_api_id = aws_api_gateway_rest_api.parking_api.id
   parent_id   = aws_api_gateway_rest_api.parking_api.root_resource_id
   path_part   = "exit"
 }
 
+# Quotes and ticket lookups are served by the exit Lambda
+resource "aws_api_gateway_resource" "quote_resource" {
+  rest_api_id = aws_api_gateway_rest_api.parking_api.id
+  parent_id   = aws_api_gateway_rest_api.parking_api.root_resource_id
+  path_part   = "quote"
+}
+
+resource "aws_api_gateway_resource" "ticket_resource" {
+  rest_api_id = aws_api_gateway_rest_api.parking_api.id
+  parent_id   = aws_api_gateway_rest_api.parking_api.root_resource_id
+  path_part   = "ticket"
+}
+
+resource "aws_api_gateway_resource" "ticket_id_resource" {
+  rest_api_id = aws_api_gateway_rest_api.parking_api.id
+  parent_id   = aws_api_gateway_resource.ticket_resource.id
+  path_part   = "{ticketId}"
+}
+
+# The admin API, the ticket export and plate history are served by the entry Lambda, which holds the admin token
+resource "aws_api_gateway_resource" "export_resource" {
+  rest_api_id = aws_api_gateway_rest_api.parking_api.id
+  parent_id   = aws_api_gateway_rest_api.parking_api.root_resource_id
+  path_part   = "export"
+}
+
+resource "aws_api_gateway_resource" "plate_resource" {
+  rest_api_id = aws_api_gateway_rest_api.parking_api.id
+  parent_id   = aws_api_gateway_rest_api.parking_api.root_resource_id
+  path_part   = "plate"
+}
+
+resource "aws_api_gateway_resource" "plate_param_resource" {
+  rest_api_id = aws_api_gateway_rest_api.parking_api.id
+  parent_id   = aws_api_gateway_resource.plate_resource.id
+  path_part   = "{plate}"
+}
+
+resource "aws_api_gateway_resource" "plate_history_resource" {
+  rest_api_id = aws_api_gateway_rest_api.parking_api.id
+  parent_id   = aws_api_gateway_resource.plate_param_resource.id
+  path_part   = "history"
+}
+
+resource "aws_api_gateway_resource" "admin_resource" {
+  rest_api_id = aws_api_gateway_rest_api.parking_api.id
+  parent_id   = aws_api_gateway_rest_api.parking_api.root_resource_id
+  path_part   = "admin"
+}
+
+resource "aws_api_gateway_resource" "maintenance_resource" {
+  rest_api_id = aws_api_gateway_rest_api.parking_api.id
+  parent_id   = aws_api_gateway_resource.admin_resource.id
+  path_part   = "maintenance"
+}
+
+# Transponder exits and health probes are served by the exit Lambda
+resource "aws_api_gateway_resource" "exit_transponder_resource" {
+  rest_api_id = aws_api_gateway_rest_api.parking_api.id
+  parent_id   = aws_api_gateway_resource.exit_resource.id
+  path_part   = "transponder"
+}
+
+resource "aws_api_gateway_resource" "livez_resource" {
+  rest_api_id = aws_api_gateway_rest_api.parking_api.id
+  parent_id   = aws_api_gateway_rest_api.parking_api.root_resource_id
+  path_part   = "livez"
+}
+
+resource "aws_api_gateway_resource" "readyz_resource" {
+  rest_api_id = aws_api_gateway_rest_api.parking_api.id
+  parent_id   = aws_api_gateway_rest_api.parking_api.root_resource_id
+  path_part   = "readyz"
+}
+
+# Active-ticket lookups, reissues, refunds, revenue and the /admin operations are admin requests,
+# served by the entry Lambda that holds ADMIN_TOKEN
+resource "aws_api_gateway_resource" "plate_active_resource" {
+  rest_api_id = aws_api_gateway_rest_api.parking_api.id
+  parent_id   = aws_api_gateway_resource.plate_param_resource.id
+  path_part   = "active"
+}
+
+resource "aws_api_gateway_resource" "ticket_reissue_resource" {
+  rest_api_id = aws_api_gateway_rest_api.parking_api.id
+  parent_id   = aws_api_gateway_resource.ticket_resource.id
+  path_part   = "reissue"
+}
+
+resource "aws_api_gateway_resource" "ticket_refund_resource" {
+  rest_api_id = aws_api_gateway_rest_api.parking_api.id
+  parent_id   = aws_api_gateway_resource.ticket_id_resource.id
+  path_part   = "refund"
+}
+
+resource "aws_api_gateway_resource" "revenue_resource" {
+  rest_api_id = aws_api_gateway_rest_api.parking_api.id
+  parent_id   = aws_api_gateway_rest_api.parking_api.root_resource_id
+  path_part   = "revenue"
+}
+
+resource "aws_api_gateway_resource" "revenue_outstanding_resource" {
+  rest_api_id = aws_api_gateway_rest_api.parking_api.id
+  parent_id   = aws_api_gateway_resource.revenue_resource.id
+  path_part   = "outstanding"
+}
+
+resource "aws_api_gateway_resource" "block_resource" {
+  rest_api_id = aws_api_gateway_rest_api.parking_api.id
+  parent_id   = aws_api_gateway_resource.admin_resource.id
+  path_part   = "block"
+}
+
+resource "aws_api_gateway_resource" "occupancy_resource" {
+  rest_api_id = aws_api_gateway_rest_api.parking_api.id
+  parent_id   = aws_api_gateway_resource.admin_resource.id
+  path_part   = "occupancy"
+}
+
+resource "aws_api_gateway_resource" "occupancy_reconcile_resource" {
+  rest_api_id = aws_api_gateway_rest_api.parking_api.id
+  parent_id   = aws_api_gateway_resource.occupancy_resource.id
+  path_part   = "reconcile"
+}
+
+resource "aws_api_gateway_resource" "admin_ticket_resource" {
+  rest_api_id = aws_api_gateway_rest_api.parking_api.id
+  parent_id   = aws_api_gateway_resource.admin_resource.id
+  path_part   = "ticket"
+}
+
 # Create POST methods for each resource
 resource "aws_api_gateway_method" "entry_method" {
   rest_api_id      = aws_api_gateway_rest_api.parking_api.id
@@ -172,6 +458,14 @@ resource "aws_api_gateway_method" "entry_method" {
   }
 }
 
+resource "aws_api_gateway_method" "entry_batch_method" {
+  rest_api_id      = aws_api_gateway_rest_api.parking_api.id
+  resource_id      = aws_api_gateway_resource.entry_batch_resource.id
+  http_method      = "POST"
+  authorization    = "NONE"
+  api_key_required = false
+}
+
 resource "aws_api_gateway_method" "exit_method" {
   rest_api_id      = aws_api_gateway_rest_api.parking_api.id
   resource_id      = aws_api_gateway_resource.exit_resource.id
@@ -184,6 +478,198 @@ resource "aws_api_gateway_method" "exit_method" {
   }
 }
 
+resource "aws_api_gateway_method" "quote_method" {
+  rest_api_id      = aws_api_gateway_rest_api.parking_api.id
+  resource_id      = aws_api_gateway_resource.quote_resource.id
+  http_method      = "POST"
+  authorization    = "NONE"
+  api_key_required = false
+
+  request_parameters = {
+    "method.request.querystring.ticketId" = true
+  }
+}
+
+resource "aws_api_gateway_method" "ticket_method" {
+  rest_api_id      = aws_api_gateway_rest_api.parking_api.id
+  resource_id      = aws_api_gateway_resource.ticket_id_resource.id
+  http_method      = "GET"
+  authorization    = "NONE"
+  api_key_required = false
+
+  request_parameters = {
+    "method.request.path.ticketId" = true
+  }
+}
+
+resource "aws_api_gateway_method" "ticket_patch_method" {
+  rest_api_id      = aws_api_gateway_rest_api.parking_api.id
+  resource_id      = aws_api_gateway_resource.ticket_id_resource.id
+  http_method      = "PATCH"
+  authorization    = "NONE"
+  api_key_required = false
+
+  request_parameters = {
+    "method.request.path.ticketId" = true
+  }
+}
+
+resource "aws_api_gateway_method" "export_method" {
+  rest_api_id      = aws_api_gateway_rest_api.parking_api.id
+  resource_id      = aws_api_gateway_resource.export_resource.id
+  http_method      = "GET"
+  authorization    = "NONE"
+  api_key_required = false
+
+  request_parameters = {
+    "method.request.querystring.from"   = true
+    "method.request.querystring.to"     = true
+    "method.request.querystring.format" = false
+  }
+}
+
+resource "aws_api_gateway_method" "plate_history_method" {
+  rest_api_id      = aws_api_gateway_rest_api.parking_api.id
+  resource_id      = aws_api_gateway_resource.plate_history_resource.id
+  http_method      = "GET"
+  authorization    = "NONE"
+  api_key_required = false
+
+  request_parameters = {
+    "method.request.path.plate"        = true
+    "method.request.querystring.limit" = false
+  }
+}
+
+resource "aws_api_gateway_method" "maintenance_method" {
+  rest_api_id      = aws_api_gateway_rest_api.parking_api.id
+  resource_id      = aws_api_gateway_resource.maintenance_resource.id
+  http_method      = "POST"
+  authorization    = "NONE"
+  api_key_required = false
+
+  request_parameters = {
+    "method.request.querystring.enabled" = true
+  }
+}
+
+resource "aws_api_gateway_method" "exit_transponder_method" {
+  rest_api_id      = aws_api_gateway_rest_api.parking_api.id
+  resource_id      = aws_api_gateway_resource.exit_transponder_resource.id
+  http_method      = "POST"
+  authorization    = "NONE"
+  api_key_required = false
+
+  request_parameters = {
+    "method.request.querystring.transponderId" = true
+    "method.request.querystring.couponCode"    = false
+  }
+}
+
+resource "aws_api_gateway_method" "livez_method" {
+  rest_api_id      = aws_api_gateway_rest_api.parking_api.id
+  resource_id      = aws_api_gateway_resource.livez_resource.id
+  http_method      = "GET"
+  authorization    = "NONE"
+  api_key_required = false
+}
+
+resource "aws_api_gateway_method" "readyz_method" {
+  rest_api_id      = aws_api_gateway_rest_api.parking_api.id
+  resource_id      = aws_api_gateway_resource.readyz_resource.id
+  http_method      = "GET"
+  authorization    = "NONE"
+  api_key_required = false
+}
+
+resource "aws_api_gateway_method" "plate_active_method" {
+  rest_api_id      = aws_api_gateway_rest_api.parking_api.id
+  resource_id      = aws_api_gateway_resource.plate_active_resource.id
+  http_method      = "GET"
+  authorization    = "NONE"
+  api_key_required = false
+
+  request_parameters = {
+    "method.request.path.plate" = true
+  }
+}
+
+resource "aws_api_gateway_method" "ticket_reissue_method" {
+  rest_api_id      = aws_api_gateway_rest_api.parking_api.id
+  resource_id      = aws_api_gateway_resource.ticket_reissue_resource.id
+  http_method      = "POST"
+  authorization    = "NONE"
+  api_key_required = false
+
+  request_parameters = {
+    "method.request.querystring.plate" = true
+  }
+}
+
+resource "aws_api_gateway_method" "ticket_refund_method" {
+  rest_api_id      = aws_api_gateway_rest_api.parking_api.id
+  resource_id      = aws_api_gateway_resource.ticket_refund_resource.id
+  http_method      = "POST"
+  authorization    = "NONE"
+  api_key_required = false
+
+  request_parameters = {
+    "method.request.path.ticketId"      = true
+    "method.request.querystring.amount" = false
+  }
+}
+
+resource "aws_api_gateway_method" "revenue_outstanding_method" {
+  rest_api_id      = aws_api_gateway_rest_api.parking_api.id
+  resource_id      = aws_api_gateway_resource.revenue_outstanding_resource.id
+  http_method      = "GET"
+  authorization    = "NONE"
+  api_key_required = false
+
+  request_parameters = {
+    "method.request.querystring.parkingLot" = true
+  }
+}
+
+resource "aws_api_gateway_method" "block_method" {
+  rest_api_id      = aws_api_gateway_rest_api.parking_api.id
+  resource_id      = aws_api_gateway_resource.block_resource.id
+  http_method      = "POST"
+  authorization    = "NONE"
+  api_key_required = false
+
+  request_parameters = {
+    "method.request.querystring.parkingLot" = true
+    "method.request.querystring.spaces"     = true
+    "method.request.querystring.start"      = false
+    "method.request.querystring.end"        = true
+  }
+}
+
+resource "aws_api_gateway_method" "occupancy_reconcile_method" {
+  rest_api_id      = aws_api_gateway_rest_api.parking_api.id
+  resource_id      = aws_api_gateway_resource.occupancy_reconcile_resource.id
+  http_method      = "POST"
+  authorization    = "NONE"
+  api_key_required = false
+
+  request_parameters = {
+    "method.request.querystring.parkingLot" = true
+  }
+}
+
+resource "aws_api_gateway_method" "admin_ticket_method" {
+  rest_api_id      = aws_api_gateway_rest_api.parking_api.id
+  resource_id      = aws_api_gateway_resource.admin_ticket_resource.id
+  http_method      = "DELETE"
+  authorization    = "NONE"
+  api_key_required = false
+
+  request_parameters = {
+    "method.request.querystring.plate" = true
+  }
+}
+
 # Add Lambda integrations
 resource "aws_api_gateway_integration" "entry_integration" {
   rest_api_id             = aws_api_gateway_rest_api.parking_api.id
@@ -194,6 +680,15 @@ resource "aws_api_gateway_integration" "entry_integration" {
   uri                     = aws_lambda_function.entry_handler.invoke_arn
 }
 
+resource "aws_api_gateway_integration" "entry_batch_integration" {
+  rest_api_id             = aws_api_gateway_rest_api.parking_api.id
+  resource_id             = aws_api_gateway_resource.entry_batch_resource.id
+  http_method             = aws_api_gateway_method.entry_batch_method.http_method
+  integration_http_method = "POST"
+  type                    = "AWS_PROXY"
+  uri                     = aws_lambda_function.entry_handler.invoke_arn
+}
+
 resource "aws_api_gateway_integration" "exit_integration" {
   rest_api_id             = aws_api_gateway_rest_api.parking_api.id
   resource_id             = aws_api_gateway_resource.exit_resource.id
@@ -203,6 +698,151 @@ resource "aws_api_gateway_integration" "exit_integration" {
   uri                     = aws_lambda_function.exit_handler.invoke_arn
 }
 
+resource "aws_api_gateway_integration" "quote_integration" {
+  rest_api_id             = aws_api_gateway_rest_api.parking_api.id
+  resource_id             = aws_api_gateway_resource.quote_resource.id
+  http_method             = aws_api_gateway_method.quote_method.http_method
+  integration_http_method = "POST"
+  type                    = "AWS_PROXY"
+  uri                     = aws_lambda_function.exit_handler.invoke_arn
+}
+
+resource "aws_api_gateway_integration" "ticket_integration" {
+  rest_api_id             = aws_api_gateway_rest_api.parking_api.id
+  resource_id             = aws_api_gateway_resource.ticket_id_resource.id
+  http_method             = aws_api_gateway_method.ticket_method.http_method
+  integration_http_method = "POST"
+  type                    = "AWS_PROXY"
+  uri                     = aws_lambda_function.exit_handler.invoke_arn
+}
+
+# Plate corrections are admin requests, served by the entry Lambda that holds ADMIN_TOKEN
+resource "aws_api_gateway_integration" "ticket_patch_integration" {
+  rest_api_id             = aws_api_gateway_rest_api.parking_api.id
+  resource_id             = aws_api_gateway_resource.ticket_id_resource.id
+  http_method             = aws_api_gateway_method.ticket_patch_method.http_method
+  integration_http_method = "POST"
+  type                    = "AWS_PROXY"
+  uri                     = aws_lambda_function.entry_handler.invoke_arn
+}
+
+resource "aws_api_gateway_integration" "export_integration" {
+  rest_api_id             = aws_api_gateway_rest_api.parking_api.id
+  resource_id             = aws_api_gateway_resource.export_resource.id
+  http_method             = aws_api_gateway_method.export_method.http_method
+  integration_http_method = "POST"
+  type                    = "AWS_PROXY"
+  uri                     = aws_lambda_function.entry_handler.invoke_arn
+}
+
+resource "aws_api_gateway_integration" "plate_history_integration" {
+  rest_api_id             = aws_api_gateway_rest_api.parking_api.id
+  resource_id             = aws_api_gateway_resource.plate_history_resource.id
+  http_method             = aws_api_gateway_method.plate_history_method.http_method
+  integration_http_method = "POST"
+  type                    = "AWS_PROXY"
+  uri                     = aws_lambda_function.entry_handler.invoke_arn
+}
+
+resource "aws_api_gateway_integration" "maintenance_integration" {
+  rest_api_id             = aws_api_gateway_rest_api.parking_api.id
+  resource_id             = aws_api_gateway_resource.maintenance_resource.id
+  http_method             = aws_api_gateway_method.maintenance_method.http_method
+  integration_http_method = "POST"
+  type                    = "AWS_PROXY"
+  uri                     = aws_lambda_function.entry_handler.invoke_arn
+}
+
+resource "aws_api_gateway_integration" "exit_transponder_integration" {
+  rest_api_id             = aws_api_gateway_rest_api.parking_api.id
+  resource_id             = aws_api_gateway_resource.exit_transponder_resource.id
+  http_method             = aws_api_gateway_method.exit_transponder_method.http_method
+  integration_http_method = "POST"
+  type                    = "AWS_PROXY"
+  uri                     = aws_lambda_function.exit_handler.invoke_arn
+}
+
+resource "aws_api_gateway_integration" "livez_integration" {
+  rest_api_id             = aws_api_gateway_rest_api.parking_api.id
+  resource_id             = aws_api_gateway_resource.livez_resource.id
+  http_method             = aws_api_gateway_method.livez_method.http_method
+  integration_http_method = "POST"
+  type                    = "AWS_PROXY"
+  uri                     = aws_lambda_function.exit_handler.invoke_arn
+}
+
+resource "aws_api_gateway_integration" "readyz_integration" {
+  rest_api_id             = aws_api_gateway_rest_api.parking_api.id
+  resource_id             = aws_api_gateway_resource.readyz_resource.id
+  http_method             = aws_api_gateway_method.readyz_method.http_method
+  integration_http_method = "POST"
+  type                    = "AWS_PROXY"
+  uri                     = aws_lambda_function.exit_handler.invoke_arn
+}
+
+resource "aws_api_gateway_integration" "plate_active_integration" {
+  rest_api_id             = aws_api_gateway_rest_api.parking_api.id
+  resource_id             = aws_api_gateway_resource.plate_active_resource.id
+  http_method             = aws_api_gateway_method.plate_active_method.http_method
+  integration_http_method = "POST"
+  type                    = "AWS_PROXY"
+  uri                     = aws_lambda_function.entry_handler.invoke_arn
+}
+
+resource "aws_api_gateway_integration" "ticket_reissue_integration" {
+  rest_api_id             = aws_api_gateway_rest_api.parking_api.id
+  resource_id             = aws_api_gateway_resource.ticket_reissue_resource.id
+  http_method             = aws_api_gateway_method.ticket_reissue_method.http_method
+  integration_http_method = "POST"
+  type                    = "AWS_PROXY"
+  uri                     = aws_lambda_function.entry_handler.invoke_arn
+}
+
+resource "aws_api_gateway_integration" "ticket_refund_integration" {
+  rest_api_id             = aws_api_gateway_rest_api.parking_api.id
+  resource_id             = aws_api_gateway_resource.ticket_refund_resource.id
+  http_method             = aws_api_gateway_method.ticket_refund_method.http_method
+  integration_http_method = "POST"
+  type                    = "AWS_PROXY"
+  uri                     = aws_lambda_function.entry_handler.invoke_arn
+}
+
+resource "aws_api_gateway_integration" "revenue_outstanding_integration" {
+  rest_api_id             = aws_api_gateway_rest_api.parking_api.id
+  resource_id             = aws_api_gateway_resource.revenue_outstanding_resource.id
+  http_method             = aws_api_gateway_method.revenue_outstanding_method.http_method
+  integration_http_method = "POST"
+  type                    = "AWS_PROXY"
+  uri                     = aws_lambda_function.entry_handler.invoke_arn
+}
+
+resource "aws_api_gateway_integration" "block_integration" {
+  rest_api_id             = aws_api_gateway_rest_api.parking_api.id
+  resource_id             = aws_api_gateway_resource.block_resource.id
+  http_method             = aws_api_gateway_method.block_method.http_method
+  integration_http_method = "POST"
+  type                    = "AWS_PROXY"
+  uri                     = aws_lambda_function.entry_handler.invoke_arn
+}
+
+resource "aws_api_gateway_integration" "occupancy_reconcile_integration" {
+  rest_api_id             = aws_api_gateway_rest_api.parking_api.id
+  resource_id             = aws_api_gateway_resource.occupancy_reconcile_resource.id
+  http_method             = aws_api_gateway_method.occupancy_reconcile_method.http_method
+  integration_http_method = "POST"
+  type                    = "AWS_PROXY"
+  uri                     = aws_lambda_function.entry_handler.invoke_arn
+}
+
+resource "aws_api_gateway_integration" "admin_ticket_integration" {
+  rest_api_id             = aws_api_gateway_rest_api.parking_api.id
+  resource_id             = aws_api_gateway_resource.admin_ticket_resource.id
+  http_method             = aws_api_gateway_method.admin_ticket_method.http_method
+  integration_http_method = "POST"
+  type                    = "AWS_PROXY"
+  uri                     = aws_lambda_function.entry_handler.invoke_arn
+}
+
 # Grant API Gateway permission to invoke the Lambda functions
 resource "aws_lambda_permission" "api_gateway_entry_permission" {
   action        = "lambda:InvokeFunction"
@@ -211,6 +851,13 @@ resource "aws_lambda_permission" "api_gateway_entry_permission" {
   source_arn    = "${aws_api_gateway_rest_api.parking_api.execution_arn}/*/*/entry"
 }
 
+resource "aws_lambda_permission" "api_gateway_entry_batch_permission" {
+  action        = "lambda:InvokeFunction"
+  function_name = aws_lambda_function.entry_handler.function_name
+  principal     = "apigateway.amazonaws.com"
+  source_arn    = "${aws_api_gateway_rest_api.parking_api.execution_arn}/*/*/entry/batch"
+}
+
 resource "aws_lambda_permission" "api_gateway_exit_permission" {
   action        = "lambda:InvokeFunction"
   function_name = aws_lambda_function.exit_handler.function_name
@@ -218,6 +865,118 @@ resource "aws_lambda_permission" "api_gateway_exit_permission" {
   source_arn    = "${aws_api_gateway_rest_api.parking_api.execution_arn}/*/*/exit"
 }
 
+resource "aws_lambda_permission" "api_gateway_quote_permission" {
+  action        = "lambda:InvokeFunction"
+  function_name = aws_lambda_function.exit_handler.function_name
+  principal     = "apigateway.amazonaws.com"
+  source_arn    = "${aws_api_gateway_rest_api.parking_api.execution_arn}/*/*/quote"
+}
+
+resource "aws_lambda_permission" "api_gateway_ticket_permission" {
+  action        = "lambda:InvokeFunction"
+  function_name = aws_lambda_function.exit_handler.function_name
+  principal     = "apigateway.amazonaws.com"
+  source_arn    = "${aws_api_gateway_rest_api.parking_api.execution_arn}/*/*/ticket/*"
+}
+
+resource "aws_lambda_permission" "api_gateway_ticket_patch_permission" {
+  action        = "lambda:InvokeFunction"
+  function_name = aws_lambda_function.entry_handler.function_name
+  principal     = "apigateway.amazonaws.com"
+  source_arn    = "${aws_api_gateway_rest_api.parking_api.execution_arn}/*/PATCH/ticket/*"
+}
+
+resource "aws_lambda_permission" "api_gateway_export_permission" {
+  action        = "lambda:InvokeFunction"
+  function_name = aws_lambda_function.entry_handler.function_name
+  principal     = "apigateway.amazonaws.com"
+  source_arn    = "${aws_api_gateway_rest_api.parking_api.execution_arn}/*/*/export"
+}
+
+resource "aws_lambda_permission" "api_gateway_plate_history_permission" {
+  action        = "lambda:InvokeFunction"
+  function_name = aws_lambda_function.entry_handler.function_name
+  principal     = "apigateway.amazonaws.com"
+  source_arn    = "${aws_api_gateway_rest_api.parking_api.execution_arn}/*/*/plate/*/history"
+}
+
+resource "aws_lambda_permission" "api_gateway_maintenance_permission" {
+  action        = "lambda:InvokeFunction"
+  function_name = aws_lambda_function.entry_handler.function_name
+  principal     = "apigateway.amazonaws.com"
+  source_arn    = "${aws_api_gateway_rest_api.parking_api.execution_arn}/*/*/admin/maintenance"
+}
+
+resource "aws_lambda_permission" "api_gateway_exit_transponder_permission" {
+  action        = "lambda:InvokeFunction"
+  function_name = aws_lambda_function.exit_handler.function_name
+  principal     = "apigateway.amazonaws.com"
+  source_arn    = "${aws_api_gateway_rest_api.parking_api.execution_arn}/*/*/exit/transponder"
+}
+
+resource "aws_lambda_permission" "api_gateway_livez_permission" {
+  action        = "lambda:InvokeFunction"
+  function_name = aws_lambda_function.exit_handler.function_name
+  principal     = "apigateway.amazonaws.com"
+  source_arn    = "${aws_api_gateway_rest_api.parking_api.execution_arn}/*/*/livez"
+}
+
+resource "aws_lambda_permission" "api_gateway_readyz_permission" {
+  action        = "lambda:InvokeFunction"
+  function_name = aws_lambda_function.exit_handler.function_name
+  principal     = "apigateway.amazonaws.com"
+  source_arn    = "${aws_api_gateway_rest_api.parking_api.execution_arn}/*/*/readyz"
+}
+
+resource "aws_lambda_permission" "api_gateway_plate_active_permission" {
+  action        = "lambda:InvokeFunction"
+  function_name = aws_lambda_function.entry_handler.function_name
+  principal     = "apigateway.amazonaws.com"
+  source_arn    = "${aws_api_gateway_rest_api.parking_api.execution_arn}/*/*/plate/*/active"
+}
+
+resource "aws_lambda_permission" "api_gateway_ticket_reissue_permission" {
+  action        = "lambda:InvokeFunction"
+  function_name = aws_lambda_function.entry_handler.function_name
+  principal     = "apigateway.amazonaws.com"
+  source_arn    = "${aws_api_gateway_rest_api.parking_api.execution_arn}/*/POST/ticket/reissue"
+}
+
+resource "aws_lambda_permission" "api_gateway_ticket_refund_permission" {
+  action        = "lambda:InvokeFunction"
+  function_name = aws_lambda_function.entry_handler.function_name
+  principal     = "apigateway.amazonaws.com"
+  source_arn    = "${aws_api_gateway_rest_api.parking_api.execution_arn}/*/POST/ticket/*/refund"
+}
+
+resource "aws_lambda_permission" "api_gateway_revenue_outstanding_permission" {
+  action        = "lambda:InvokeFunction"
+  function_name = aws_lambda_function.entry_handler.function_name
+  principal     = "apigateway.amazonaws.com"
+  source_arn    = "${aws_api_gateway_rest_api.parking_api.execution_arn}/*/*/revenue/outstanding"
+}
+
+resource "aws_lambda_permission" "api_gateway_block_permission" {
+  action        = "lambda:InvokeFunction"
+  function_name = aws_lambda_function.entry_handler.function_name
+  principal     = "apigateway.amazonaws.com"
+  source_arn    = "${aws_api_gateway_rest_api.parking_api.execution_arn}/*/*/admin/block"
+}
+
+resource "aws_lambda_permission" "api_gateway_occupancy_reconcile_permission" {
+  action        = "lambda:InvokeFunction"
+  function_name = aws_lambda_function.entry_handler.function_name
+  principal     = "apigateway.amazonaws.com"
+  source_arn    = "${aws_api_gateway_rest_api.parking_api.execution_arn}/*/*/admin/occupancy/reconcile"
+}
+
+resource "aws_lambda_permission" "api_gateway_admin_ticket_permission" {
+  action        = "lambda:InvokeFunction"
+  function_name = aws_lambda_function.entry_handler.function_name
+  principal     = "apigateway.amazonaws.com"
+  source_arn    = "${aws_api_gateway_rest_api.parking_api.execution_arn}/*/*/admin/ticket"
+}
+
 # Create a deployment to make the API available
 resource "aws_api_gateway_deployment" "api_deployment" {
   rest_api_id = aws_api_gateway_rest_api.parking_api.id
@@ -225,18 +984,87 @@ resource "aws_api_gateway_deployment" "api_deployment" {
 
   depends_on = [
     aws_api_gateway_integration.entry_integration,
-    aws_api_gateway_integration.exit_integration
+    aws_api_gateway_integration.entry_batch_integration,
+    aws_api_gateway_integration.exit_integration,
+    aws_api_gateway_integration.quote_integration,
+    aws_api_gateway_integration.ticket_integration,
+    aws_api_gateway_integration.ticket_patch_integration,
+    aws_api_gateway_integration.export_integration,
+    aws_api_gateway_integration.plate_history_integration,
+    aws_api_gateway_integration.maintenance_integration,
+    aws_api_gateway_integration.exit_transponder_integration,
+    aws_api_gateway_integration.livez_integration,
+    aws_api_gateway_integration.readyz_integration,
+    aws_api_gateway_integration.plate_active_integration,
+    aws_api_gateway_integration.ticket_reissue_integration,
+    aws_api_gateway_integration.ticket_refund_integration,
+    aws_api_gateway_integration.revenue_outstanding_integration,
+    aws_api_gateway_integration.block_integration,
+    aws_api_gateway_integration.occupancy_reconcile_integration,
+    aws_api_gateway_integration.admin_ticket_integration
   ]
 
   # Force redeployment when resources change
   triggers = {
     redeployment = sha1(jsonencode([
       aws_api_gateway_resource.entry_resource.id,
+      aws_api_gateway_resource.entry_batch_resource.id,
       aws_api_gateway_resource.exit_resource.id,
+      aws_api_gateway_resource.quote_resource.id,
+      aws_api_gateway_resource.ticket_id_resource.id,
+      aws_api_gateway_resource.export_resource.id,
+      aws_api_gateway_resource.plate_history_resource.id,
+      aws_api_gateway_resource.maintenance_resource.id,
+      aws_api_gateway_resource.exit_transponder_resource.id,
+      aws_api_gateway_resource.livez_resource.id,
+      aws_api_gateway_resource.readyz_resource.id,
+      aws_api_gateway_resource.plate_active_resource.id,
+      aws_api_gateway_resource.ticket_reissue_resource.id,
+      aws_api_gateway_resource.ticket_refund_resource.id,
+      aws_api_gateway_resource.revenue_resource.id,
+      aws_api_gateway_resource.revenue_outstanding_resource.id,
+      aws_api_gateway_resource.block_resource.id,
+      aws_api_gateway_resource.occupancy_resource.id,
+      aws_api_gateway_resource.occupancy_reconcile_resource.id,
+      aws_api_gateway_resource.admin_ticket_resource.id,
       aws_api_gateway_method.entry_method.id,
+      aws_api_gateway_method.entry_batch_method.id,
       aws_api_gateway_method.exit_method.id,
+      aws_api_gateway_method.quote_method.id,
+      aws_api_gateway_method.ticket_method.id,
+      aws_api_gateway_method.ticket_patch_method.id,
+      aws_api_gateway_method.export_method.id,
+      aws_api_gateway_method.plate_history_method.id,
+      aws_api_gateway_method.maintenance_method.id,
+      aws_api_gateway_method.exit_transponder_method.id,
+      aws_api_gateway_method.livez_method.id,
+      aws_api_gateway_method.readyz_method.id,
+      aws_api_gateway_method.plate_active_method.id,
+      aws_api_gateway_method.ticket_reissue_method.id,
+      aws_api_gateway_method.ticket_refund_method.id,
+      aws_api_gateway_method.revenue_outstanding_method.id,
+      aws_api_gateway_method.block_method.id,
+      aws_api_gateway_method.occupancy_reconcile_method.id,
+      aws_api_gateway_method.admin_ticket_method.id,
       aws_api_gateway_integration.entry_integration.id,
+      aws_api_gateway_integration.entry_batch_integration.id,
       aws_api_gateway_integration.exit_integration.id,
+      aws_api_gateway_integration.quote_integration.id,
+      aws_api_gateway_integration.ticket_integration.id,
+      aws_api_gateway_integration.ticket_patch_integration.id,
+      aws_api_gateway_integration.export_integration.id,
+      aws_api_gateway_integration.plate_history_integration.id,
+      aws_api_gateway_integration.maintenance_integration.id,
+      aws_api_gateway_integration.exit_transponder_integration.id,
+      aws_api_gateway_integration.livez_integration.id,
+      aws_api_gateway_integration.readyz_integration.id,
+      aws_api_gateway_integration.plate_active_integration.id,
+      aws_api_gateway_integration.ticket_reissue_integration.id,
+      aws_api_gateway_integration.ticket_refund_integration.id,
+      aws_api_gateway_integration.revenue_outstanding_integration.id,
+      aws_api_gateway_integration.block_integration.id,
+      aws_api_gateway_integration.occupancy_reconcile_integration.id,
+      aws_api_gateway_integration.admin_ticket_integration.id,
     ]))
   }
 
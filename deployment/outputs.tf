@@ -21,4 +21,18 @@ output "exit_lambda_name" {
 output "dynamo_table_name" {
   value       = aws_dynamodb_table.parking_tickets.name
   description = "The name of the DynamoDB table"
-} 
\ No newline at end of file
+} 
+output "events_table_name" {
+  value       = aws_dynamodb_table.parking_events.name
+  description = "The name of the DynamoDB event log table"
+}
+
+output "coupons_table_name" {
+  value       = aws_dynamodb_table.parking_coupons.name
+  description = "The name of the DynamoDB promo code table"
+}
+
+output "kinesis_stream_name" {
+  value       = aws_kinesis_stream.parking_events.name
+  description = "The name of the Kinesis stream of entry and exit records"
+}
@@ -8,4 +8,23 @@ variable "project_name" {
   description = "The name of the project"
   type        = string
   default     = "parking-lot"
-} 
\ No newline at end of file
+}
+
+variable "admin_token" {
+  description = "Bearer token for the admin API (e.g. POST /admin/maintenance); the admin API is disabled when empty"
+  type        = string
+  default     = ""
+  sensitive   = true
+}
+
+variable "composite_key" {
+  description = "Key the tickets table by plate and entryTime instead of ticketId; changing it replaces the table"
+  type        = bool
+  default     = false
+}
+
+variable "reconcile_schedule" {
+  description = "EventBridge schedule expression the lots' occupancy counters are reconciled on, e.g. rate(15 minutes)"
+  type        = string
+  default     = "rate(15 minutes)"
+}
@@ -0,0 +1,149 @@
+// Package emf writes CloudWatch Embedded Metric Format (EMF) documents as JSON lines to stdout.
+// On Lambda, CloudWatch Logs extracts the metrics from the function's output, so no metrics
+// endpoint or PutMetricData calls are needed.
+package emf
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Namespace is the CloudWatch namespace the metrics are published under
+const Namespace = "ParkingLot"
+
+// Metric names
+const (
+	EntryCount = "EntryCount"
+	ExitCount  = "ExitCount"
+	Charge     = "Charge"
+)
+
+// Dimension names
+const (
+	DimensionParkingLot  = "ParkingLot"
+	DimensionVehicleType = "VehicleType"
+)
+
+// Unit is a CloudWatch metric unit
+type Unit string
+
+// Units of the emitted metrics
+const (
+	UnitCount Unit = "Count"
+	UnitNone  Unit = "None"
+)
+
+// Metric is a single value of a document
+type Metric struct {
+	Name  string
+	Unit  Unit
+	Value float64
+}
+
+// metadata is the _aws block telling CloudWatch which members of a document are metrics
+type metadata struct {
+	Timestamp         int64             `json:"Timestamp"`
+	CloudWatchMetrics []metricDirective `json:"CloudWatchMetrics"`
+}
+
+// metricDirective lists the metrics of a document and the dimensions they are published with
+type metricDirective struct {
+	Namespace  string             `json:"Namespace"`
+	Dimensions [][]string         `json:"Dimensions"`
+	Metrics    []metricDefinition `json:"Metrics"`
+}
+
+// metricDefinition names a metric member of a document
+type metricDefinition struct {
+	Name string `json:"Name"`
+	Unit Unit   `json:"Unit"`
+}
+
+// Emitter writes one EMF document per call. Its methods are no-ops on a nil Emitter.
+type Emitter struct {
+	mu  sync.Mutex
+	w   io.Writer
+	now func() time.Time
+}
+
+// NewEmitter creates an emitter writing documents to w
+func NewEmitter(w io.Writer) *Emitter {
+	return &Emitter{w: w, now: time.Now}
+}
+
+// NewEmitterFromEnv creates an emitter writing to stdout when EMF_ENABLED is true, and returns nil otherwise
+func NewEmitterFromEnv() *Emitter {
+	if os.Getenv("EMF_ENABLED") != "true" {
+		return nil
+	}
+	return NewEmitter(os.Stdout)
+}
+
+// Entry records a vehicle entering a lot
+func (e *Emitter) Entry(parkingLot int, vehicleType string) {
+	e.Emit(dimensions(parkingLot, vehicleType), Metric{Name: EntryCount, Unit: UnitCount, Value: 1})
+}
+
+// Exit records a vehicle leaving a lot and the charge it paid
+func (e *Emitter) Exit(parkingLot int, vehicleType string, charge float32) {
+	e.Emit(dimensions(parkingLot, vehicleType),
+		Metric{Name: ExitCount, Unit: UnitCount, Value: 1},
+		Metric{Name: Charge, Unit: UnitNone, Value: float64(charge)},
+	)
+}
+
+// Emit writes a document publishing metrics with the given dimensions. Documents are written
+// as a single line each, which is how CloudWatch Logs expects to find them.
+func (e *Emitter) Emit(dimensions map[string]string, metrics ...Metric) {
+	if e == nil {
+		return
+	}
+
+	// Sort the dimension names so every document of a metric publishes the same dimension set
+	names := make([]string, 0, len(dimensions))
+	for name := range dimensions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	document := make(map[string]any, len(dimensions)+len(metrics)+1)
+	directive := metricDirective{
+		Namespace:  Namespace,
+		Dimensions: [][]string{names},
+		Metrics:    make([]metricDefinition, 0, len(metrics)),
+	}
+	for name, value := range dimensions {
+		document[name] = value
+	}
+	for _, metric := range metrics {
+		directive.Metrics = append(directive.Metrics, metricDefinition{Name: metric.Name, Unit: metric.Unit})
+		document[metric.Name] = metric.Value
+	}
+	document["_aws"] = metadata{
+		Timestamp:         e.now().UnixMilli(),
+		CloudWatchMetrics: []metricDirective{directive},
+	}
+
+	line, err := json.Marshal(document)
+	if err != nil {
+		return
+	}
+	line = append(line, '\n')
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	_, _ = e.w.Write(line)
+}
+
+// dimensions returns the dimension values of a lot and vehicle type
+func dimensions(parkingLot int, vehicleType string) map[string]string {
+	return map[string]string{
+		DimensionParkingLot:  strconv.Itoa(parkingLot),
+		DimensionVehicleType: vehicleType,
+	}
+}
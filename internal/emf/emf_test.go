@@ -0,0 +1,77 @@
+package emf
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestExit tests that an exit is written as a single valid EMF line with the _aws metadata block
+func TestExit(t *testing.T) {
+	var buf bytes.Buffer
+	emitter := NewEmitter(&buf)
+	emitter.now = func() time.Time { return time.UnixMilli(1714560300000) }
+
+	emitter.Exit(382, "car", 7.5)
+
+	require.True(t, strings.HasSuffix(buf.String(), "\n"))
+	require.Equal(t, 1, strings.Count(buf.String(), "\n"), "each document must be a single line")
+	assert.JSONEq(t, `{
+		"_aws": {
+			"Timestamp": 1714560300000,
+			"CloudWatchMetrics": [{
+				"Namespace": "ParkingLot",
+				"Dimensions": [["ParkingLot", "VehicleType"]],
+				"Metrics": [
+					{"Name": "ExitCount", "Unit": "Count"},
+					{"Name": "Charge", "Unit": "None"}
+				]
+			}]
+		},
+		"ParkingLot": "382",
+		"VehicleType": "car",
+		"ExitCount": 1,
+		"Charge": 7.5
+	}`, buf.String())
+
+	// Every dimension and metric the metadata names must be a member of the document
+	var document map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &document))
+	directive := document["_aws"].(map[string]any)["CloudWatchMetrics"].([]any)[0].(map[string]any)
+	for _, dimension := range directive["Dimensions"].([]any)[0].([]any) {
+		assert.IsType(t, "", document[dimension.(string)])
+	}
+	for _, metric := range directive["Metrics"].([]any) {
+		assert.IsType(t, float64(0), document[metric.(map[string]any)["Name"].(string)])
+	}
+}
+
+// TestEntry tests that an entry publishes the entry count
+func TestEntry(t *testing.T) {
+	var buf bytes.Buffer
+	NewEmitter(&buf).Entry(7, "large")
+
+	var document map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &document))
+	assert.Equal(t, 1.0, document[EntryCount])
+	assert.Equal(t, "7", document[DimensionParkingLot])
+	assert.Equal(t, "large", document[DimensionVehicleType])
+	assert.Contains(t, document, "_aws")
+}
+
+// TestNewEmitterFromEnv tests that metrics are only emitted when EMF_ENABLED is true
+func TestNewEmitterFromEnv(t *testing.T) {
+	t.Setenv("EMF_ENABLED", "")
+	emitter := NewEmitterFromEnv()
+	assert.Nil(t, emitter)
+	// A nil emitter silently drops metrics
+	emitter.Exit(1, "car", 2.5)
+
+	t.Setenv("EMF_ENABLED", "true")
+	assert.NotNil(t, NewEmitterFromEnv())
+}
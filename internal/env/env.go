@@ -0,0 +1,12 @@
+// Package env reports facts about the environment the service runs in
+package env
+
+import "os"
+
+// executionEnvVar is set by the Lambda runtime, e.g. to AWS_Lambda_provided.al2
+const executionEnvVar = "AWS_EXECUTION_ENV"
+
+// IsLambda reports whether the process runs inside AWS Lambda rather than locally
+func IsLambda() bool {
+	return os.Getenv(executionEnvVar) != ""
+}
@@ -0,0 +1,16 @@
+package env
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestIsLambda tests that Lambda is detected from the runtime's execution environment variable
+func TestIsLambda(t *testing.T) {
+	t.Setenv("AWS_EXECUTION_ENV", "")
+	assert.False(t, IsLambda())
+
+	t.Setenv("AWS_EXECUTION_ENV", "AWS_Lambda_provided.al2")
+	assert.True(t, IsLambda())
+}
@@ -0,0 +1,128 @@
+package handler
+
+import (
+	"context"
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"parking-lot/internal/logger"
+	"parking-lot/internal/service"
+	"parking-lot/server/api"
+)
+
+// PostAdminMaintenance turns maintenance mode on or off for all instances
+func (h *ParkingHandler) PostAdminMaintenance(c *gin.Context, params api.PostAdminMaintenanceParams) {
+	ctx := c.Request.Context()
+	log := h.log.WithContext(ctx).WithFields(logger.Field{Key: "maintenance", Value: params.Enabled})
+
+	if !h.authorizeAdmin(c, log) {
+		return
+	}
+
+	store, ok := h.service.(service.MaintenanceStore)
+	if !ok {
+		log.Error("Service does not support maintenance mode")
+		h.respond(c, http.StatusNotImplemented, api.ErrorResponse{
+			Code:    CodeNotImplemented,
+			Message: "Maintenance mode is not supported",
+		})
+		return
+	}
+
+	if err := store.SetMaintenanceMode(ctx, params.Enabled); err != nil {
+		log.Error("Failed to update maintenance mode", logger.Field{Key: "error", Value: err.Error()})
+		h.respond(c, http.StatusInternalServerError, api.ErrorResponse{
+			Code:    CodeInternalError,
+			Message: "Failed to update maintenance mode",
+		})
+		return
+	}
+
+	// Report the effective state, which stays on while MAINTENANCE_MODE=true
+	enabled, err := store.MaintenanceMode(ctx)
+	if err != nil {
+		enabled = params.Enabled
+	}
+
+	log.Info("Maintenance mode updated", logger.Field{Key: "effective", Value: enabled})
+	h.respond(c, http.StatusOK, api.MaintenanceResponse{Maintenance: enabled})
+}
+
+// AdminKeyHeader carries the admin API key of requests to the /admin routes
+const AdminKeyHeader = "X-Admin-Key"
+
+// adminKeyVerified is the Gin context key set once AdminKeyMiddleware accepted a request's admin key
+const adminKeyVerified = "parking.adminKeyVerified"
+
+// AdminKeyMiddleware guards the /admin route group with ADMIN_API_KEY, which is separate from the
+// ADMIN_TOKEN bearer token and never accepted on public routes. Requests without the key are rejected
+// with 401; accepted ones skip the bearer token check. While ADMIN_API_KEY is unset the group falls
+// back to the bearer token.
+func (h *ParkingHandler) AdminKeyMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if h.adminAPIKey == "" {
+			c.Next()
+			return
+		}
+
+		key := c.GetHeader(AdminKeyHeader)
+		if key == "" || subtle.ConstantTimeCompare([]byte(key), []byte(h.adminAPIKey)) != 1 {
+			h.log.WithContext(c.Request.Context()).Warn("Admin request rejected, invalid admin key",
+				logger.Field{Key: "path", Value: c.Request.URL.Path},
+			)
+			h.abort(c, http.StatusUnauthorized, api.ErrorResponse{
+				Code:    CodeUnauthorized,
+				Message: "Unauthorized",
+			})
+			return
+		}
+
+		c.Set(adminKeyVerified, true)
+		c.Next()
+	}
+}
+
+// authorizeAdmin checks the bearer token of an admin request and answers it when the check fails.
+// Requests already authenticated by AdminKeyMiddleware pass.
+func (h *ParkingHandler) authorizeAdmin(c *gin.Context, log logger.Logger) bool {
+	if c.GetBool(adminKeyVerified) {
+		return true
+	}
+	if h.adminToken == "" {
+		log.Warn("Admin request rejected, ADMIN_TOKEN is not set")
+		h.respond(c, http.StatusForbidden, api.ErrorResponse{
+			Code:    CodeAdminDisabled,
+			Message: "Admin API is disabled",
+		})
+		return false
+	}
+
+	token, found := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+	if !found || subtle.ConstantTimeCompare([]byte(token), []byte(h.adminToken)) != 1 {
+		log.Warn("Admin request rejected, invalid token")
+		h.respond(c, http.StatusUnauthorized, api.ErrorResponse{
+			Code:    CodeUnauthorized,
+			Message: "Unauthorized",
+		})
+		return false
+	}
+	return true
+}
+
+// maintenanceMode reports whether new entries are rejected. A failed lookup is logged
+// and treated as no maintenance so a storage hiccup does not close the lot.
+func (h *ParkingHandler) maintenanceMode(ctx context.Context, log logger.Logger) bool {
+	store, ok := h.service.(service.MaintenanceStore)
+	if !ok {
+		return false
+	}
+	enabled, err := store.MaintenanceMode(ctx)
+	if err != nil {
+		log.Warn("Failed to check maintenance mode", logger.Field{Key: "error", Value: err.Error()})
+		return false
+	}
+	return enabled
+}
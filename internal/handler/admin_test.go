@@ -0,0 +1,144 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"parking-lot/internal/mocks"
+	"parking-lot/internal/model"
+	"parking-lot/server/api"
+)
+
+// maintenanceService adds an in-memory maintenance flag to the mock service
+type maintenanceService struct {
+	*mocks.ParkingService
+	enabled bool
+	err     error
+}
+
+// MaintenanceMode reports the stored flag
+func (m *maintenanceService) MaintenanceMode(ctx context.Context) (bool, error) {
+	return m.enabled, m.err
+}
+
+// SetMaintenanceMode stores the flag
+func (m *maintenanceService) SetMaintenanceMode(ctx context.Context, enabled bool) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.enabled = enabled
+	return nil
+}
+
+// setupAdminRouter registers all routes on a handler using the given service and admin token
+func setupAdminRouter(svc *maintenanceService, adminToken string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	handler := NewParkingHandler(svc)
+	handler.adminToken = adminToken
+
+	router := gin.New()
+	api.RegisterHandlers(router, handler)
+	return router
+}
+
+// TestMaintenanceMode tests that maintenance mode blocks entries while exits keep working
+func TestMaintenanceMode(t *testing.T) {
+	t.Run("Entry blocked", func(t *testing.T) {
+		mockService := new(mocks.ParkingService)
+		router := setupAdminRouter(&maintenanceService{ParkingService: mockService, enabled: true}, "")
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("POST", "/entry?plate=ABC-123&parkingLot=1", nil))
+
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+		assert.JSONEq(t, `{"code":"MAINTENANCE","message":"maintenance"}`, w.Body.String())
+		mockService.AssertNotCalled(t, "ReserveSpaces", mock.Anything, mock.Anything, mock.Anything)
+		mockService.AssertNotCalled(t, "CreateTicket", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Exit allowed", func(t *testing.T) {
+		mockService := new(mocks.ParkingService)
+		router := setupAdminRouter(&maintenanceService{ParkingService: mockService, enabled: true}, "")
+
+		ticketID := uuid.New()
+		entryTime := time.Now().Add(-30 * time.Minute)
+		ticket := &model.ParkingTicket{TicketID: ticketID.String(), Plate: "ABC-123", ParkingLot: 1, EntryTime: entryTime}
+		mockService.On("GetTicket", mock.Anything, ticketID.String()).Return(ticket, true).Once()
+		mockService.On("CalculateChargeDetailed", 1, entryTime).Return(30*time.Minute, 30, float32(5)).Once()
+		mockService.On("UpdateTicket", mock.Anything, ticket).Return(nil).Once()
+		mockService.On("ReleaseSpaces", mock.Anything, 1, 1).Once()
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("POST", "/exit?ticketId="+ticketID.String(), nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("Lookup failure does not block entry", func(t *testing.T) {
+		mockService := new(mocks.ParkingService)
+		router := setupAdminRouter(&maintenanceService{ParkingService: mockService, err: errors.New("throttled")}, "")
+
+		mockService.On("FindActiveTicket", mock.Anything, "ABC-123", 1).Return(nil, nil).Once()
+		mockService.On("FindReentryTicket", mock.Anything, "ABC-123", 1).Return(nil, nil).Once()
+		mockService.On("ReserveSpaces", mock.Anything, 1, 1).Return(nil).Once()
+		mockService.On("CreateTicket", mock.Anything, "ABC-123", 1, 1).Return(uuid.New(), &model.ParkingTicket{}).Once()
+		mockService.On("Rates").Return(model.RateSchedule{}).Once()
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("POST", "/entry?plate=ABC-123&parkingLot=1", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockService.AssertExpectations(t)
+	})
+}
+
+// TestPostAdminMaintenance tests toggling maintenance mode through the admin API
+func TestPostAdminMaintenance(t *testing.T) {
+	tests := []struct {
+		name           string
+		adminToken     string
+		authorization  string
+		storeErr       error
+		expectedStatus int
+		expectedMode   bool
+	}{
+		{name: "Enabled", adminToken: "secret", authorization: "Bearer secret", expectedStatus: http.StatusOK, expectedMode: true},
+		{name: "Wrong token", adminToken: "secret", authorization: "Bearer guess", expectedStatus: http.StatusUnauthorized},
+		{name: "Missing token", adminToken: "secret", expectedStatus: http.StatusUnauthorized},
+		{name: "Admin API disabled", authorization: "Bearer secret", expectedStatus: http.StatusForbidden},
+		{name: "Store failure", adminToken: "secret", authorization: "Bearer secret", storeErr: errors.New("throttled"), expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &maintenanceService{ParkingService: new(mocks.ParkingService), err: tt.storeErr}
+			router := setupAdminRouter(svc, tt.adminToken)
+
+			req := httptest.NewRequest("POST", "/admin/maintenance?enabled=true", nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.Equal(t, tt.expectedMode, svc.enabled)
+			if tt.expectedStatus == http.StatusOK {
+				var response api.MaintenanceResponse
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				assert.True(t, response.Maintenance)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+
+	"parking-lot/internal/logger"
+	"parking-lot/server/api"
+)
+
+// maxBatchEntries is the most entries accepted in one batch request
+const maxBatchEntries = 25
+
+// PostEntryBatch records several vehicle entries, e.g. ones a gate queued while offline. Each entry is
+// processed like POST /entry and reported with its own status; the batch answers 207 as long as its
+// body is valid, even when some or all entries fail.
+func (h *ParkingHandler) PostEntryBatch(c *gin.Context) {
+	ctx, span := tracer.Start(c.Request.Context(), "PostEntryBatch")
+	defer span.End()
+
+	log := h.log.WithContext(ctx)
+
+	var request api.BatchEntryRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Warn("Invalid batch entry body", logger.Field{Key: "error", Value: err.Error()})
+		details := []string{err.Error()}
+		h.respond(c, http.StatusBadRequest, api.ErrorResponse{
+			Code:    CodeValidationFailed,
+			Message: "Invalid request body",
+			Details: &details,
+		})
+		return
+	}
+	if len(request.Entries) == 0 || len(request.Entries) > maxBatchEntries {
+		log.Warn("Invalid batch size", logger.Field{Key: "entries", Value: len(request.Entries)})
+		h.respond(c, http.StatusBadRequest, api.ErrorResponse{
+			Code:    CodeValidationFailed,
+			Message: fmt.Sprintf("entries must hold between 1 and %d entries", maxBatchEntries),
+		})
+		return
+	}
+	span.SetAttributes(attribute.Int("batch.size", len(request.Entries)))
+
+	results := make([]api.BatchEntryResult, len(request.Entries))
+	failed := 0
+	for i, entry := range request.Entries {
+		status, body := h.enter(ctx, api.PostEntryParams{
+			Plate:      entry.Plate,
+			ParkingLot: entry.ParkingLot,
+			Spaces:     entry.Spaces,
+		})
+
+		results[i] = api.BatchEntryResult{Index: i, Status: status}
+		switch body := body.(type) {
+		case api.EntryResponse:
+			results[i].Ticket = &body
+		case api.ErrorResponse:
+			results[i].Error = &body
+			failed++
+		}
+	}
+
+	log.Info("Batch entry processed",
+		logger.Field{Key: "entries", Value: len(request.Entries)},
+		logger.Field{Key: "failed", Value: failed},
+	)
+	h.respond(c, http.StatusMultiStatus, api.BatchEntryResponse{Results: results})
+}
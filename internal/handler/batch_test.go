@@ -0,0 +1,109 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"parking-lot/internal/service"
+	"parking-lot/server/api"
+)
+
+// setupBatchRouter registers all routes on a handler backed by the in-memory service
+func setupBatchRouter(t *testing.T) *gin.Engine {
+	t.Setenv("LOT_CONFIG", `{"1":{"capacity":1}}`)
+	memoryService, err := service.NewMemoryParkingLotService(context.Background())
+	assert.NoError(t, err)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	api.RegisterHandlers(router, NewParkingHandler(memoryService))
+	return router
+}
+
+// TestPostEntryBatch tests that each entry of a batch gets its own status,
+// with failing entries reported next to the ones that succeeded
+func TestPostEntryBatch(t *testing.T) {
+	router := setupBatchRouter(t)
+
+	body := `{"entries":[
+		{"plate":"ABC-123","parkingLot":2},
+		{"plate":"DEF-456","parkingLot":2,"spaces":0},
+		{"plate":"GHI-789","parkingLot":1},
+		{"plate":"JKL-012","parkingLot":1}
+	]}`
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/entry/batch", strings.NewReader(body)))
+
+	assert.Equal(t, http.StatusMultiStatus, w.Code)
+	var response api.BatchEntryResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	if !assert.Len(t, response.Results, 4) {
+		return
+	}
+
+	expected := []struct {
+		status  int
+		message string
+	}{
+		{status: http.StatusOK},
+		{status: http.StatusBadRequest, message: "spaces must be at least 1"},
+		{status: http.StatusOK},
+		{status: http.StatusConflict, message: "Not enough free spaces in parking lot"},
+	}
+	for i, result := range response.Results {
+		assert.Equal(t, i, result.Index)
+		assert.Equal(t, expected[i].status, result.Status)
+		if expected[i].message == "" {
+			assert.Nil(t, result.Error)
+			if assert.NotNil(t, result.Ticket) {
+				assert.NotEmpty(t, result.Ticket.TicketId)
+			}
+			continue
+		}
+		assert.Nil(t, result.Ticket)
+		if assert.NotNil(t, result.Error) {
+			assert.Equal(t, expected[i].message, result.Error.Message)
+		}
+	}
+
+	// Entries in the batch are real: the first vehicle cannot enter again
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/entry?plate=ABC-123&parkingLot=2", nil))
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+// TestPostEntryBatch_InvalidBody tests that malformed, empty and oversized batches are rejected as a whole
+func TestPostEntryBatch_InvalidBody(t *testing.T) {
+	router := setupBatchRouter(t)
+	tooMany := `{"entries":[` + strings.Repeat(`{"plate":"ABC-123","parkingLot":1},`, maxBatchEntries) + `{"plate":"ABC-123","parkingLot":1}]}`
+
+	tests := []struct {
+		name    string
+		body    string
+		message string
+	}{
+		{name: "Not JSON", body: "plate=ABC-123", message: "Invalid request body"},
+		{name: "Missing entries", body: `{}`, message: "entries must hold between 1 and 25 entries"},
+		{name: "Empty entries", body: `{"entries":[]}`, message: "entries must hold between 1 and 25 entries"},
+		{name: "Too many entries", body: tooMany, message: "entries must hold between 1 and 25 entries"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest("POST", "/entry/batch", strings.NewReader(tt.body)))
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			var response api.ErrorResponse
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, tt.message, response.Message)
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"parking-lot/internal/logger"
+	"parking-lot/internal/service"
+	"parking-lot/server/api"
+)
+
+// PostAdminBlock holds back spaces of a lot for an event. While the block is active, entries only
+// get the lot's spaces beyond the blocked ones.
+func (h *ParkingHandler) PostAdminBlock(c *gin.Context, params api.PostAdminBlockParams) {
+	ctx, span := tracer.Start(c.Request.Context(), "BlockSpaces")
+	defer span.End()
+
+	log := h.log.WithContext(ctx).WithFields(
+		logger.Field{Key: "parking_lot", Value: params.ParkingLot},
+		logger.Field{Key: "spaces", Value: params.Spaces},
+	)
+	log.Info("Processing space block")
+
+	if !h.authorizeAdmin(c, log) {
+		return
+	}
+
+	if err := checkParkingLot(params.ParkingLot, h.maxLot); err != nil {
+		log.Warn("Invalid parking lot")
+		h.respond(c, http.StatusBadRequest, api.ErrorResponse{
+			Code:    CodeValidationFailed,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if h.serviceUnavailable() {
+		log.Warn("Storage unavailable, failing fast")
+		h.respondUnavailable(c)
+		return
+	}
+
+	if h.readOnly() {
+		log.Warn("Service is read-only, rejecting space block")
+		h.respondReadOnly(c)
+		return
+	}
+
+	blocker, ok := h.service.(service.SpaceBlocker)
+	if !ok {
+		log.Error("Service does not support space blocks")
+		h.respond(c, http.StatusNotImplemented, api.ErrorResponse{
+			Code:    CodeNotImplemented,
+			Message: "Space blocks are not supported",
+		})
+		return
+	}
+
+	// A block without a start begins now
+	var start time.Time
+	if params.Start != nil {
+		start = *params.Start
+	}
+	block, err := blocker.BlockSpaces(ctx, params.ParkingLot, params.Spaces, start, params.End)
+	if errors.Is(err, service.ErrInvalidBlock) {
+		h.respond(c, http.StatusBadRequest, api.ErrorResponse{
+			Code:    CodeValidationFailed,
+			Message: err.Error(),
+		})
+		return
+	}
+	if errors.Is(err, service.ErrDynamoDBUnavailable) {
+		log.Warn("Storage unavailable, failing fast")
+		h.respondUnavailable(c)
+		return
+	}
+	if err != nil {
+		log.Error("Failed to block spaces", logger.Field{Key: "error", Value: err.Error()})
+		h.respond(c, http.StatusInternalServerError, api.ErrorResponse{
+			Code:    CodeInternalError,
+			Message: "Failed to block spaces",
+		})
+		return
+	}
+
+	blockID, err := uuid.Parse(block.BlockID)
+	if err != nil {
+		log.Error("Block has an invalid ID", logger.Field{Key: "block_id", Value: block.BlockID})
+		h.respond(c, http.StatusInternalServerError, api.ErrorResponse{
+			Code:    CodeInternalError,
+			Message: "Failed to block spaces",
+		})
+		return
+	}
+
+	log.Info("Spaces blocked", logger.Field{Key: "block_id", Value: block.BlockID})
+	h.respond(c, http.StatusOK, api.Block{
+		BlockId:    blockID,
+		ParkingLot: block.ParkingLot,
+		Spaces:     block.Spaces,
+		StartTime:  block.StartTime,
+		EndTime:    block.EndTime,
+	})
+}
@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"parking-lot/server/api"
+)
+
+// TestPostAdminBlock tests that entries are rejected while a block holds back every space of a lot
+func TestPostAdminBlock(t *testing.T) {
+	t.Setenv("DEFAULT_LOT_CAPACITY", "5")
+	router, _ := setupCorrectionRouter(t)
+
+	block := func(start, end time.Time) *httptest.ResponseRecorder {
+		query := url.Values{"parkingLot": {"7"}, "spaces": {"5"}, "end": {end.Format(time.RFC3339)}}
+		if !start.IsZero() {
+			query.Set("start", start.Format(time.RFC3339))
+		}
+		req := httptest.NewRequest("POST", "/admin/block?"+query.Encode(), nil)
+		req.Header.Set("Authorization", "Bearer secret")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	enter := func(plate string) int {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("POST", "/entry?parkingLot=7&plate="+plate, nil))
+		return w.Code
+	}
+
+	// A block that has not started yet holds nothing back
+	w := block(time.Now().Add(time.Hour), time.Now().Add(2*time.Hour))
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, http.StatusOK, enter("ABC-123"))
+
+	w = block(time.Time{}, time.Now().Add(time.Hour))
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var response api.Block
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, 7, response.ParkingLot)
+	assert.Equal(t, 5, response.Spaces)
+	assert.Equal(t, http.StatusConflict, enter("DEF-456"))
+}
+
+// TestPostAdminBlock_Invalid tests that blocks are validated before they are stored
+func TestPostAdminBlock_Invalid(t *testing.T) {
+	router, _ := setupCorrectionRouter(t)
+	end := url.QueryEscape(time.Now().Add(time.Hour).Format(time.RFC3339))
+	past := url.QueryEscape(time.Now().Add(-time.Hour).Format(time.RFC3339))
+
+	tests := []struct {
+		name           string
+		query          string
+		token          string
+		expectedStatus int
+	}{
+		{name: "Missing token", query: "parkingLot=7&spaces=5&end=" + end, expectedStatus: http.StatusUnauthorized},
+		{name: "Lot out of range", query: "parkingLot=0&spaces=5&end=" + end, token: "secret", expectedStatus: http.StatusBadRequest},
+		{name: "No spaces", query: "parkingLot=7&spaces=0&end=" + end, token: "secret", expectedStatus: http.StatusBadRequest},
+		{name: "Already over", query: "parkingLot=7&spaces=5&end=" + past, token: "secret", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/admin/block?"+tt.query, nil)
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code, w.Body.String())
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"time"
+
+	"parking-lot/internal/model"
+	"parking-lot/internal/service"
+	"parking-lot/server/api"
+)
+
+// ChargeCalculator computes the charges of stays. Exits and quotes are billed through it rather than
+// through the service directly, so another pricing algorithm can be swapped in, e.g. to A/B test prices,
+// without changing how tickets are stored.
+type ChargeCalculator interface {
+	// CalculateChargeDetailed returns the exact duration of a stay in a lot up to now,
+	// along with the rounded minutes and the charge
+	CalculateChargeDetailed(parkingLot int, entryTime time.Time) (time.Duration, int, float32)
+}
+
+// SetChargeCalculator replaces the calculator exits and quotes are billed with; nil restores the service's own
+func (h *ParkingHandler) SetChargeCalculator(calculator ChargeCalculator) {
+	if calculator == nil {
+		calculator = h.service
+	}
+	h.calculator = calculator
+}
+
+// itemizedCharge bills a ticket's stay up to now with the calculator, with the pricing experiment arm
+// recorded on the ticket and itemized when the calculator supports it; otherwise the whole charge is
+// reported as the base charge. What earlier exits of a reopened ticket billed is deducted.
+func (h *ParkingHandler) itemizedCharge(ticket *model.ParkingTicket) (time.Duration, int, service.ChargeBreakdown) {
+	duration, minutes, breakdown := h.stayCharge(ticket)
+	return duration, minutes, breakdown.LessPriorCharge(ticket.PriorCharge)
+}
+
+// stayCharge bills a ticket's whole stay since entry up to now with the calculator
+func (h *ParkingHandler) stayCharge(ticket *model.ParkingTicket) (time.Duration, int, service.ChargeBreakdown) {
+	if charger, ok := h.calculator.(service.ExperimentCharger); ok && ticket.PricingArm != "" {
+		return charger.CalculateArmChargeBreakdown(ticket.PricingArm, ticket.ParkingLot, ticket.EntryTime)
+	}
+	if itemizer, ok := h.calculator.(service.ChargeItemizer); ok {
+		return itemizer.CalculateChargeBreakdown(ticket.ParkingLot, ticket.EntryTime)
+	}
+	duration, minutes, charge := h.calculator.CalculateChargeDetailed(ticket.ParkingLot, ticket.EntryTime)
+	return duration, minutes, service.ChargeBreakdown{BaseCharge: charge, Total: charge}
+}
+
+// breakdownResponse maps an itemized charge to its API representation
+func breakdownResponse(breakdown service.ChargeBreakdown) *api.ChargeBreakdown {
+	response := &api.ChargeBreakdown{
+		BaseCharge: breakdown.BaseCharge,
+		Surcharge:  breakdown.Surcharge,
+		Discount:   breakdown.Discount,
+		Total:      breakdown.Total,
+	}
+	if breakdown.PriorCharge > 0 {
+		response.PriorCharge = &breakdown.PriorCharge
+	}
+	return response
+}
@@ -0,0 +1,215 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"parking-lot/internal/mocks"
+	"parking-lot/internal/model"
+	"parking-lot/internal/service"
+	"parking-lot/server/api"
+)
+
+// stubCalculator bills every stay a fixed charge, e.g. for an experimental price list
+type stubCalculator struct {
+	duration time.Duration
+	minutes  int
+	charge   float32
+}
+
+// CalculateChargeDetailed returns the fixed duration, minutes and charge
+func (s stubCalculator) CalculateChargeDetailed(parkingLot int, entryTime time.Time) (time.Duration, int, float32) {
+	return s.duration, s.minutes, s.charge
+}
+
+// TestPostExit_ChargeCalculator tests that an injected calculator's charge is billed verbatim
+func TestPostExit_ChargeCalculator(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(mocks.ParkingService)
+	handler := NewParkingHandler(mockService)
+	handler.SetChargeCalculator(stubCalculator{duration: 50 * time.Minute, minutes: 50, charge: 3.33})
+	router := gin.New()
+	api.RegisterHandlersWithOptions(router, handler, api.GinServerOptions{ErrorHandler: handler.ErrorHandler})
+
+	ticketID := uuid.New()
+	entryTime := time.Now().Add(-50 * time.Minute)
+	ticket := &model.ParkingTicket{TicketID: ticketID.String(), Plate: "ABC-123", ParkingLot: 1, EntryTime: entryTime}
+	mockService.On("GetTicket", mock.Anything, ticketID.String()).Return(ticket, true).Once()
+	mockService.On("UpdateTicket", mock.Anything, mock.MatchedBy(func(updated *model.ParkingTicket) bool {
+		return updated.Charge == 3.33 && updated.DurationMinutes == 50
+	})).Return(nil).Once()
+	mockService.On("ReleaseSpaces", mock.Anything, 1, 1).Once()
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/exit?ticketId="+ticketID.String(), nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response api.ExitResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, float32(3.33), response.Charge)
+	assert.Equal(t, 50, response.ParkedDurationMinutes)
+	// The service's own pricing is never consulted
+	mockService.AssertNotCalled(t, "CalculateChargeDetailed", mock.Anything, mock.Anything)
+	mockService.AssertExpectations(t)
+
+	// Clearing the calculator bills through the service again
+	handler.SetChargeCalculator(nil)
+	assert.Equal(t, ChargeCalculator(mockService), handler.calculator)
+}
+
+// stubItemizer bills every stay a fixed itemized charge
+type stubItemizer struct {
+	stubCalculator
+	breakdown service.ChargeBreakdown
+}
+
+// CalculateChargeBreakdown returns the fixed duration, minutes and itemized charge
+func (s stubItemizer) CalculateChargeBreakdown(parkingLot int, entryTime time.Time) (time.Duration, int, service.ChargeBreakdown) {
+	return s.duration, s.minutes, s.breakdown
+}
+
+// TestPostExit_ChargeBreakdown tests that the exit itemizes a surcharged charge discounted by a coupon
+func TestPostExit_ChargeBreakdown(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	svc := &couponService{
+		ParkingService: new(mocks.ParkingService),
+		coupons:        map[string]model.Coupon{"TWOOFF": {Code: "TWOOFF", AmountOff: 2}},
+	}
+	handler := NewParkingHandler(svc)
+	handler.SetChargeCalculator(stubItemizer{
+		stubCalculator: stubCalculator{duration: time.Hour, minutes: 60, charge: 12.5},
+		breakdown:      service.ChargeBreakdown{BaseCharge: 10, Surcharge: 2.5, Total: 12.5},
+	})
+	router := gin.New()
+	api.RegisterHandlersWithOptions(router, handler, api.GinServerOptions{ErrorHandler: handler.ErrorHandler})
+
+	ticketID := uuid.New()
+	ticket := &model.ParkingTicket{TicketID: ticketID.String(), Plate: "ABC-123", ParkingLot: 1, EntryTime: time.Now().Add(-time.Hour)}
+	svc.On("GetTicket", mock.Anything, ticketID.String()).Return(ticket, true).Once()
+	svc.On("UpdateTicket", mock.Anything, mock.MatchedBy(func(updated *model.ParkingTicket) bool {
+		return updated.Charge == 10.5
+	})).Return(nil).Once()
+	svc.On("ReleaseSpaces", mock.Anything, 1, 1).Once()
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/exit?ticketId="+ticketID.String()+"&couponCode=TWOOFF", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response api.ExitResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, float32(10.5), response.Charge)
+	if assert.NotNil(t, response.ChargeBreakdown) {
+		breakdown := *response.ChargeBreakdown
+		assert.Equal(t, api.ChargeBreakdown{BaseCharge: 10, Surcharge: 2.5, Discount: 2, Total: 10.5}, breakdown)
+		assert.Equal(t, breakdown.Total, breakdown.BaseCharge+breakdown.Surcharge-breakdown.Discount)
+		assert.Equal(t, response.Charge, breakdown.Total)
+	}
+	svc.AssertExpectations(t)
+}
+
+// stubExperimentCharger bills stays of each pricing experiment arm a fixed itemized charge
+type stubExperimentCharger struct {
+	stubItemizer
+	arms map[string]service.ChargeBreakdown
+}
+
+// CalculateArmChargeBreakdown returns the fixed duration, minutes and the arm's itemized charge
+func (s stubExperimentCharger) CalculateArmChargeBreakdown(arm string, parkingLot int, entryTime time.Time) (time.Duration, int, service.ChargeBreakdown) {
+	return s.duration, s.minutes, s.arms[arm]
+}
+
+// TestPostExit_PricingArm tests that the exit bills the pricing experiment arm recorded on the ticket
+func TestPostExit_PricingArm(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(mocks.ParkingService)
+	handler := NewParkingHandler(mockService)
+	handler.SetChargeCalculator(stubExperimentCharger{
+		stubItemizer: stubItemizer{
+			stubCalculator: stubCalculator{duration: time.Hour, minutes: 60, charge: 10},
+			breakdown:      service.ChargeBreakdown{BaseCharge: 10, Total: 10},
+		},
+		arms: map[string]service.ChargeBreakdown{"premium": {BaseCharge: 14, Total: 14}},
+	})
+	router := gin.New()
+	api.RegisterHandlersWithOptions(router, handler, api.GinServerOptions{ErrorHandler: handler.ErrorHandler})
+
+	ticketID := uuid.New()
+	ticket := &model.ParkingTicket{
+		TicketID: ticketID.String(), Plate: "ABC-123", ParkingLot: 1,
+		EntryTime: time.Now().Add(-time.Hour), PricingArm: "premium",
+	}
+	mockService.On("GetTicket", mock.Anything, ticketID.String()).Return(ticket, true).Once()
+	mockService.On("UpdateTicket", mock.Anything, mock.MatchedBy(func(updated *model.ParkingTicket) bool {
+		return updated.Charge == 14 && updated.PricingArm == "premium"
+	})).Return(nil).Once()
+	mockService.On("ReleaseSpaces", mock.Anything, 1, 1).Once()
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/exit?ticketId="+ticketID.String(), nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response api.ExitResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, float32(14), response.Charge)
+	mockService.AssertExpectations(t)
+}
+
+// TestPostExit_ReopenedTicket tests that the exit after a grace re-entry only bills the stay beyond
+// what the first exit charged, so the two exits add up to the charge of the whole stay
+func TestPostExit_ReopenedTicket(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	t.Setenv("GRACE_REENTRY_MINUTES", "15")
+	memoryService, err := service.NewMemoryParkingLotService(context.Background())
+	assert.NoError(t, err)
+	handler := NewParkingHandler(memoryService)
+	router := gin.New()
+	api.RegisterHandlersWithOptions(router, handler, api.GinServerOptions{ErrorHandler: handler.ErrorHandler})
+
+	enter := func() api.EntryResponse {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("POST", "/entry?plate=AGAIN-1&parkingLot=1", nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response api.EntryResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return response
+	}
+	exit := func(ticketID uuid.UUID) api.ExitResponse {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("POST", "/exit?ticketId="+ticketID.String(), nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response api.ExitResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return response
+	}
+
+	entry := enter()
+	handler.SetChargeCalculator(stubCalculator{duration: 50 * time.Minute, minutes: 50, charge: 5})
+	first := exit(entry.TicketId)
+	assert.Equal(t, float32(5), first.Charge)
+
+	// The vehicle returns within the grace window and gets its ticket back
+	assert.Equal(t, entry.TicketId, enter().TicketId)
+
+	handler.SetChargeCalculator(stubCalculator{duration: 90 * time.Minute, minutes: 90, charge: 12.5})
+	second := exit(entry.TicketId)
+	assert.Equal(t, float32(7.5), second.Charge)
+	if assert.NotNil(t, second.ChargeBreakdown) && assert.NotNil(t, second.ChargeBreakdown.PriorCharge) {
+		assert.Equal(t, float32(5), *second.ChargeBreakdown.PriorCharge)
+		assert.Equal(t, float32(12.5), second.ChargeBreakdown.BaseCharge)
+	}
+	assert.Equal(t, float32(12.5), first.Charge+second.Charge)
+
+	ticket, ok := memoryService.GetTicket(context.Background(), entry.TicketId.String())
+	assert.True(t, ok)
+	assert.Equal(t, float32(5), ticket.PriorCharge)
+	assert.Equal(t, float32(7.5), ticket.Charge)
+}
@@ -0,0 +1,54 @@
+package handler
+
+// Error codes are the stable, machine-readable code of every ErrorResponse. Clients branch on
+// the code; the message is meant for people and may change.
+const (
+	// CodeValidationFailed is returned when a request parameter or body is invalid
+	CodeValidationFailed = "VALIDATION_FAILED"
+	// CodeUnauthorized is returned when an admin request lacks a valid bearer token
+	CodeUnauthorized = "UNAUTHORIZED"
+	// CodeAdminDisabled is returned for admin requests when no admin token is configured
+	CodeAdminDisabled = "ADMIN_DISABLED"
+	// CodeTenantNotAllowed is returned when a request names a table outside the tenant allowlist
+	CodeTenantNotAllowed = "TENANT_NOT_ALLOWED"
+	// CodeNotFound is returned for paths no operation is mounted on
+	CodeNotFound = "NOT_FOUND"
+	// CodeTicketNotFound is returned when the ticket in the request does not exist
+	CodeTicketNotFound = "TICKET_NOT_FOUND"
+	// CodeNoActiveTicket is returned when a plate or transponder has no active ticket
+	CodeNoActiveTicket = "NO_ACTIVE_TICKET"
+	// CodeActiveTicketExists is returned when a vehicle already has an active ticket
+	CodeActiveTicketExists = "ACTIVE_TICKET_EXISTS"
+	// CodeTicketExited is returned when a request needs a ticket that has not exited yet
+	CodeTicketExited = "TICKET_EXITED"
+	// CodeTicketConflict is returned when a ticket was updated concurrently
+	CodeTicketConflict = "TICKET_CONFLICT"
+	// CodeTicketTooLarge is returned when a ticket exceeds DynamoDB's item size limit
+	CodeTicketTooLarge = "TICKET_TOO_LARGE"
+	// CodeNotRefundable is returned when refunding a ticket that cannot be refunded
+	CodeNotRefundable = "NOT_REFUNDABLE"
+	// CodeRefundWindowClosed is returned when refunding after the refund window
+	CodeRefundWindowClosed = "REFUND_WINDOW_CLOSED"
+	// CodeUnknownLot is returned when entering a lot that has no configured capacity
+	CodeUnknownLot = "UNKNOWN_LOT"
+	// CodeLotFull is returned when a lot has too few free spaces for an entry
+	CodeLotFull = "LOT_FULL"
+	// CodeNoFreeSpot is returned when every numbered spot in a lot is taken
+	CodeNoFreeSpot = "NO_FREE_SPOT"
+	// CodeOccupancyChanged is returned when a lot's occupancy kept changing while it was reconciled
+	CodeOccupancyChanged = "OCCUPANCY_CHANGED"
+	// CodeLotClosed is returned for entries outside operating hours
+	CodeLotClosed = "LOT_CLOSED"
+	// CodeMaintenance is returned for entries while the lot is under maintenance
+	CodeMaintenance = "MAINTENANCE"
+	// CodeReadOnly is returned for writes while the service is read-only
+	CodeReadOnly = "READ_ONLY"
+	// CodeServiceUnavailable is returned while storage is unreachable or failing fast
+	CodeServiceUnavailable = "SERVICE_UNAVAILABLE"
+	// CodeIndexNotActive is returned by health checks while a secondary index is not active
+	CodeIndexNotActive = "INDEX_NOT_ACTIVE"
+	// CodeNotImplemented is returned when the service does not support an operation
+	CodeNotImplemented = "NOT_IMPLEMENTED"
+	// CodeInternalError is returned when a request fails unexpectedly
+	CodeInternalError = "INTERNAL_ERROR"
+)
@@ -0,0 +1,133 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"parking-lot/internal/mocks"
+	"parking-lot/server/api"
+)
+
+// codeStatus is the HTTP status every error code is answered with
+var codeStatus = map[string]int{
+	CodeValidationFailed:   http.StatusBadRequest,
+	CodeUnauthorized:       http.StatusUnauthorized,
+	CodeAdminDisabled:      http.StatusForbidden,
+	CodeTenantNotAllowed:   http.StatusBadRequest,
+	CodeNotFound:           http.StatusNotFound,
+	CodeTicketNotFound:     http.StatusNotFound,
+	CodeNoActiveTicket:     http.StatusNotFound,
+	CodeActiveTicketExists: http.StatusConflict,
+	CodeTicketExited:       http.StatusConflict,
+	CodeTicketConflict:     http.StatusConflict,
+	CodeTicketTooLarge:     http.StatusBadRequest,
+	CodeNotRefundable:      http.StatusConflict,
+	CodeRefundWindowClosed: http.StatusConflict,
+	CodeUnknownLot:         http.StatusBadRequest,
+	CodeLotFull:            http.StatusConflict,
+	CodeNoFreeSpot:         http.StatusConflict,
+	CodeOccupancyChanged:   http.StatusConflict,
+	CodeLotClosed:          http.StatusForbidden,
+	CodeMaintenance:        http.StatusServiceUnavailable,
+	CodeReadOnly:           http.StatusServiceUnavailable,
+	CodeServiceUnavailable: http.StatusServiceUnavailable,
+	CodeIndexNotActive:     http.StatusServiceUnavailable,
+	CodeNotImplemented:     http.StatusNotImplemented,
+	CodeInternalError:      http.StatusInternalServerError,
+}
+
+// assertErrorCode asserts that a response carries the code and the status paired with it
+func assertErrorCode(t *testing.T, w *httptest.ResponseRecorder, expectedCode string) {
+	t.Helper()
+	var response api.ErrorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, expectedCode, response.Code)
+	assert.Equal(t, codeStatus[expectedCode], w.Code, "status paired with %s", expectedCode)
+	assert.NotEmpty(t, response.Message)
+}
+
+// TestErrorCodes tests that each error path answers with its machine-readable code
+func TestErrorCodes(t *testing.T) {
+	router, memoryService := setupCorrectionRouter(t)
+	ctx := context.Background()
+	_, parked := memoryService.CreateTicket(ctx, "ABC-123", 1, 1)
+	exitedID, _ := memoryService.CreateTicket(ctx, "DEF-456", 1, 1)
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/exit?ticketId="+exitedID.String(), nil))
+	unknownID := uuid.New().String()
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		admin          bool
+		expectedStatus int
+		expectedCode   string
+	}{
+		{name: "Malformed ticket ID", method: "GET", path: "/ticket/not-a-uuid", expectedStatus: http.StatusBadRequest, expectedCode: CodeValidationFailed},
+		{name: "Invalid spaces", method: "POST", path: "/entry?plate=GHI-789&parkingLot=1&spaces=0", expectedStatus: http.StatusBadRequest, expectedCode: CodeValidationFailed},
+		{name: "Invalid parking lot", method: "POST", path: "/entry?plate=GHI-789&parkingLot=0", expectedStatus: http.StatusBadRequest, expectedCode: CodeValidationFailed},
+		{name: "Active ticket exists", method: "POST", path: "/entry?plate=" + parked.Plate + "&parkingLot=1", expectedStatus: http.StatusConflict, expectedCode: CodeActiveTicketExists},
+		{name: "Ticket not found", method: "GET", path: "/ticket/" + unknownID, expectedStatus: http.StatusNotFound, expectedCode: CodeTicketNotFound},
+		{name: "Exit of unknown ticket", method: "POST", path: "/exit?ticketId=" + unknownID, expectedStatus: http.StatusNotFound, expectedCode: CodeTicketNotFound},
+		{name: "Quote of exited ticket", method: "POST", path: "/quote?ticketId=" + exitedID.String(), expectedStatus: http.StatusConflict, expectedCode: CodeTicketExited},
+		{name: "No active transponder ticket", method: "POST", path: "/exit/transponder?transponderId=TRP-9999", expectedStatus: http.StatusNotFound, expectedCode: CodeNoActiveTicket},
+		{name: "Missing admin token", method: "GET", path: "/revenue/outstanding?parkingLot=1", expectedStatus: http.StatusUnauthorized, expectedCode: CodeUnauthorized},
+		{name: "Refund of parked ticket", method: "POST", path: "/ticket/" + parked.TicketID + "/refund", admin: true, expectedStatus: http.StatusConflict, expectedCode: CodeNotRefundable},
+		{name: "No active plate ticket", method: "POST", path: "/ticket/reissue?plate=XYZ-999", admin: true, expectedStatus: http.StatusNotFound, expectedCode: CodeNoActiveTicket},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			if tt.admin {
+				req.Header.Set("Authorization", "Bearer secret")
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assertErrorCode(t, w, tt.expectedCode)
+		})
+	}
+}
+
+// TestErrorCodes_NotImplemented tests that operations a service does not support answer 501
+func TestErrorCodes_NotImplemented(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler := NewParkingHandler(new(mocks.ParkingService))
+	handler.adminToken = "secret"
+	router := gin.New()
+	api.RegisterHandlersWithOptions(router, handler, api.GinServerOptions{ErrorHandler: handler.ErrorHandler})
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+	}{
+		{name: "Maintenance mode", method: "POST", path: "/admin/maintenance?enabled=true"},
+		{name: "Export", method: "GET", path: "/export?from=2024-05-01&to=2024-05-02"},
+		{name: "Plate history", method: "GET", path: "/plate/ABC-123/history"},
+		{name: "Transponder exit", method: "POST", path: "/exit/transponder?transponderId=TRP-0001"},
+		{name: "Outstanding revenue", method: "GET", path: "/revenue/outstanding?parkingLot=1"},
+		{name: "Reissue", method: "POST", path: "/ticket/reissue?plate=ABC-123"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			req.Header.Set("Authorization", "Bearer secret")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assertErrorCode(t, w, CodeNotImplemented)
+		})
+	}
+}
@@ -0,0 +1,176 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+
+	"parking-lot/internal/logger"
+	"parking-lot/internal/model"
+	"parking-lot/internal/service"
+	"parking-lot/server/api"
+)
+
+// maxPlateLength is the longest plate accepted by a correction
+const maxPlateLength = 15
+
+// PatchTicketTicketId corrects a plate mistyped at entry. Only parked vehicles can be corrected;
+// the entry time and charge of the ticket are kept.
+func (h *ParkingHandler) PatchTicketTicketId(c *gin.Context, ticketId openapi_types.UUID) {
+	ctx, span := tracer.Start(c.Request.Context(), "CorrectPlate")
+	defer span.End()
+
+	log := h.log.WithContext(ctx).WithFields(
+		logger.Field{Key: "ticket_id", Value: ticketId},
+	)
+
+	if !h.authorizeAdmin(c, log) {
+		return
+	}
+
+	corrector, ok := h.service.(service.PlateCorrector)
+	if !ok {
+		log.Error("Service does not support plate corrections")
+		h.respond(c, http.StatusNotImplemented, api.ErrorResponse{
+			Code:    CodeNotImplemented,
+			Message: "Plate correction is not supported",
+		})
+		return
+	}
+
+	var request api.PatchTicketTicketIdJSONRequestBody
+	if err := c.ShouldBindJSON(&request); err != nil {
+		log.Warn("Invalid plate correction body", logger.Field{Key: "error", Value: err.Error()})
+		details := []string{err.Error()}
+		h.respond(c, http.StatusBadRequest, api.ErrorResponse{
+			Code:    CodeValidationFailed,
+			Message: "Invalid request body",
+			Details: &details,
+		})
+		return
+	}
+	plate := strings.TrimSpace(request.Plate)
+	if err := validatePlate(plate); err != nil {
+		log.Warn("Invalid corrected plate", logger.Field{Key: "error", Value: err.Error()})
+		h.respond(c, http.StatusBadRequest, api.ErrorResponse{
+			Code:    CodeValidationFailed,
+			Message: err.Error(),
+		})
+		return
+	}
+	log = log.WithFields(logger.Plate(plate))
+	log.Info("Processing plate correction")
+
+	if h.serviceUnavailable() {
+		log.Warn("Storage unavailable, failing fast")
+		h.respondUnavailable(c)
+		return
+	}
+
+	if h.readOnly() {
+		log.Warn("Service is read-only, rejecting plate correction")
+		h.respondReadOnly(c)
+		return
+	}
+
+	ticket, exists := h.service.GetTicket(ctx, ticketId.String())
+	if !exists {
+		log.Warn("Ticket not found")
+		h.respond(c, http.StatusNotFound, api.ErrorResponse{
+			Code:    CodeTicketNotFound,
+			Message: "Ticket not found",
+		})
+		return
+	}
+
+	// An exited ticket has been charged and may already be exported, so it is no longer corrected
+	if ticket.Status == model.TicketStatusOut {
+		log.Warn("Plate correction rejected, vehicle already exited")
+		h.respond(c, http.StatusConflict, api.ErrorResponse{
+			Code:     CodeTicketExited,
+			Message:  "Vehicle has already exited",
+			TicketId: &ticketId,
+		})
+		return
+	}
+
+	if ticket.Plate == plate {
+		log.Info("Plate unchanged, nothing to correct")
+		h.respond(c, http.StatusOK, ticketResponse(ticketId, ticket))
+		return
+	}
+
+	// The corrected plate must not leave the vehicle with two active tickets in the lot
+	existing, err := h.service.FindActiveTicket(ctx, plate, ticket.ParkingLot)
+	if errors.Is(err, service.ErrDynamoDBUnavailable) {
+		log.Warn("Storage unavailable, failing fast")
+		h.respondUnavailable(c)
+		return
+	}
+	if err != nil {
+		// Best effort, as on entry: a failed lookup should not block the correction
+		log.Warn("Failed to check for an active ticket", logger.Field{Key: "error", Value: err.Error()})
+	} else if existing != nil && existing.TicketID != ticket.TicketID {
+		response := api.ErrorResponse{
+			Code:    CodeActiveTicketExists,
+			Message: "Vehicle already has an active ticket in this parking lot",
+		}
+		if existingID, err := uuid.Parse(existing.TicketID); err == nil {
+			response.TicketId = &existingID
+		}
+		log.Warn("Plate correction rejected, plate has an active ticket", logger.Field{Key: "existing_ticket_id", Value: existing.TicketID})
+		h.respond(c, http.StatusConflict, response)
+		return
+	}
+
+	if err := corrector.CorrectPlate(ctx, ticket, plate); err != nil {
+		if errors.Is(err, service.ErrPlateKeyed) {
+			log.Warn("Plate correction rejected, tickets are keyed by plate")
+			h.respond(c, http.StatusNotImplemented, api.ErrorResponse{
+				Code:    CodeNotImplemented,
+				Message: "Plates cannot be corrected when tickets are keyed by plate",
+			})
+			return
+		}
+		if errors.Is(err, service.ErrDynamoDBUnavailable) {
+			log.Warn("Storage unavailable, failing fast")
+			h.respondUnavailable(c)
+			return
+		}
+		if errors.Is(err, service.ErrTicketConflict) {
+			log.Warn("Ticket was updated concurrently, rejecting plate correction")
+			h.respondTicketConflict(c, &ticketId)
+			return
+		}
+		log.Error("Failed to update ticket", logger.Field{Key: "error", Value: err.Error()})
+		h.respond(c, http.StatusInternalServerError, api.ErrorResponse{
+			Code:    CodeInternalError,
+			Message: "Failed to update ticket",
+		})
+		return
+	}
+
+	h.respond(c, http.StatusOK, ticketResponse(ticketId, ticket))
+}
+
+// validatePlate checks a corrected plate: letters, digits, spaces and hyphens only, and not an
+// anonymous placeholder, which only the service generates
+func validatePlate(plate string) error {
+	if plate == "" || len(plate) > maxPlateLength {
+		return fmt.Errorf("plate must hold between 1 and %d characters", maxPlateLength)
+	}
+	for _, r := range plate {
+		if !(r >= 'A' && r <= 'Z' || r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == ' ' || r == '-') {
+			return errors.New("plate may only contain letters, digits, spaces and hyphens")
+		}
+	}
+	if model.IsAnonymousPlate(plate) {
+		return fmt.Errorf("plate must not start with %s", model.AnonymousPlatePrefix)
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"parking-lot/internal/service"
+	"parking-lot/server/api"
+)
+
+// setupCorrectionRouter registers all routes on an admin-enabled handler backed by the in-memory service
+func setupCorrectionRouter(t *testing.T) (*gin.Engine, *service.MemoryParkingLotService) {
+	memoryService, err := service.NewMemoryParkingLotService(context.Background())
+	assert.NoError(t, err)
+
+	gin.SetMode(gin.TestMode)
+	handler := NewParkingHandler(memoryService)
+	handler.adminToken = "secret"
+	router := gin.New()
+	api.RegisterHandlersWithOptions(router, handler, api.GinServerOptions{ErrorHandler: handler.ErrorHandler})
+	return router, memoryService
+}
+
+// correctPlate sends a plate correction for a ticket with the admin token
+func correctPlate(router *gin.Engine, ticketID string, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("PATCH", "/ticket/"+ticketID, strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer secret")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+// TestPatchTicketTicketId tests correcting the plate of a parked vehicle
+func TestPatchTicketTicketId(t *testing.T) {
+	router, memoryService := setupCorrectionRouter(t)
+	ticketID, ticket := memoryService.CreateTicket(context.Background(), "ABC-128", 7, 1)
+
+	w := correctPlate(router, ticketID.String(), `{"plate":" ABC-123 "}`)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response api.TicketResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "ABC-123", response.Plate)
+	assert.Equal(t, "in", response.Status)
+	assert.True(t, ticket.EntryTime.Equal(response.EntryTime))
+
+	stored, _ := memoryService.GetTicket(context.Background(), ticketID.String())
+	assert.Equal(t, "ABC-123", stored.Plate)
+	assert.True(t, ticket.EntryTime.Equal(stored.EntryTime))
+
+	// The vehicle exits under its corrected plate
+	active, err := memoryService.FindActiveTicket(context.Background(), "ABC-123", 7)
+	assert.NoError(t, err)
+	if assert.NotNil(t, active) {
+		assert.Equal(t, ticketID.String(), active.TicketID)
+	}
+}
+
+// TestPatchTicketTicketId_Exited tests that the plate of an exited ticket cannot be corrected
+func TestPatchTicketTicketId_Exited(t *testing.T) {
+	router, memoryService := setupCorrectionRouter(t)
+	ticketID, _ := memoryService.CreateTicket(context.Background(), "ABC-128", 7, 1)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/exit?ticketId="+ticketID.String(), nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = correctPlate(router, ticketID.String(), `{"plate":"ABC-123"}`)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	var response api.ErrorResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "Vehicle has already exited", response.Message)
+
+	stored, _ := memoryService.GetTicket(context.Background(), ticketID.String())
+	assert.Equal(t, "ABC-128", stored.Plate)
+}
+
+// TestPatchTicketTicketId_Rejected tests corrections rejected before the ticket is changed
+func TestPatchTicketTicketId_Rejected(t *testing.T) {
+	router, memoryService := setupCorrectionRouter(t)
+	ticketID, _ := memoryService.CreateTicket(context.Background(), "ABC-128", 7, 1)
+	memoryService.CreateTicket(context.Background(), "XYZ-789", 7, 1)
+
+	tests := []struct {
+		name           string
+		ticketID       string
+		body           string
+		expectedStatus int
+		message        string
+	}{
+		{name: "Invalid body", ticketID: ticketID.String(), body: `plate=ABC-123`, expectedStatus: http.StatusBadRequest, message: "Invalid request body"},
+		{name: "Empty plate", ticketID: ticketID.String(), body: `{"plate":"  "}`, expectedStatus: http.StatusBadRequest, message: "plate must hold between 1 and 15 characters"},
+		{name: "Invalid characters", ticketID: ticketID.String(), body: `{"plate":"ABC;123"}`, expectedStatus: http.StatusBadRequest, message: "plate may only contain letters, digits, spaces and hyphens"},
+		{name: "Anonymous plate", ticketID: ticketID.String(), body: `{"plate":"ANON-1234"}`, expectedStatus: http.StatusBadRequest, message: "plate must not start with ANON-"},
+		{name: "Unknown ticket", ticketID: "00000000-0000-0000-0000-000000000001", body: `{"plate":"ABC-123"}`, expectedStatus: http.StatusNotFound, message: "Ticket not found"},
+		{name: "Plate already parked", ticketID: ticketID.String(), body: `{"plate":"XYZ-789"}`, expectedStatus: http.StatusConflict, message: "Vehicle already has an active ticket in this parking lot"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := correctPlate(router, tt.ticketID, tt.body)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			var response api.ErrorResponse
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, tt.message, response.Message)
+		})
+	}
+
+	t.Run("Missing token", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("PATCH", "/ticket/"+ticketID.String(), strings.NewReader(`{"plate":"ABC-123"}`)))
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	stored, _ := memoryService.GetTicket(context.Background(), ticketID.String())
+	assert.Equal(t, "ABC-128", stored.Plate)
+}
@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"context"
+	"time"
+
+	"parking-lot/internal/logger"
+	"parking-lot/internal/model"
+	"parking-lot/internal/service"
+)
+
+// applyCoupon discounts an exit charge with a promo code and records the code on the ticket.
+// A code that is unknown, expired or cannot be looked up is ignored with a warning, so a bad
+// coupon never blocks an exit; the full charge is returned instead.
+func (h *ParkingHandler) applyCoupon(ctx context.Context, log logger.Logger, ticket *model.ParkingTicket, code string, exitTime time.Time, charge float32) float32 {
+	log = log.WithFields(logger.Field{Key: "coupon_code", Value: code})
+
+	lookup, ok := h.service.(service.CouponLookup)
+	if !ok {
+		log.Warn("Coupons are not supported, ignoring coupon")
+		return charge
+	}
+
+	coupon, err := lookup.Coupon(ctx, code)
+	if err != nil {
+		log.Warn("Failed to look up coupon, ignoring it", logger.Field{Key: "error", Value: err.Error()})
+		return charge
+	}
+	if coupon == nil {
+		log.Warn("Unknown coupon, ignoring it")
+		return charge
+	}
+	if coupon.Expired(exitTime) {
+		log.Warn("Expired coupon, ignoring it", logger.Field{Key: "expires_at", Value: coupon.ExpiresAt})
+		return charge
+	}
+
+	discounted := coupon.Apply(charge)
+	log.Info("Applied coupon",
+		logger.Field{Key: "charge", Value: charge},
+		logger.Field{Key: "discounted_charge", Value: discounted},
+	)
+	ticket.CouponCode = code
+	return discounted
+}
+
+// couponCode returns the promo code applied to a ticket's charge, or nil when there is none
+func couponCode(ticket *model.ParkingTicket) *string {
+	if ticket.CouponCode == "" {
+		return nil
+	}
+	return &ticket.CouponCode
+}
@@ -0,0 +1,97 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"parking-lot/internal/mocks"
+	"parking-lot/internal/model"
+	"parking-lot/server/api"
+)
+
+// couponService adds a fixed set of promo codes to the mock service
+type couponService struct {
+	*mocks.ParkingService
+	coupons map[string]model.Coupon
+}
+
+// Coupon returns the promo code from the fixed set, or nil when it is unknown
+func (s *couponService) Coupon(ctx context.Context, code string) (*model.Coupon, error) {
+	coupon, ok := s.coupons[code]
+	if !ok {
+		return nil, nil
+	}
+	return &coupon, nil
+}
+
+// TestPostExit_Coupon tests discounting the exit charge with promo codes
+func TestPostExit_Coupon(t *testing.T) {
+	expired := time.Now().Add(-time.Hour)
+	valid := time.Now().Add(24 * time.Hour)
+	svc := &couponService{
+		ParkingService: new(mocks.ParkingService),
+		coupons: map[string]model.Coupon{
+			"SPRING20": {Code: "SPRING20", PercentOff: 20, ExpiresAt: &valid},
+			"TWOOFF":   {Code: "TWOOFF", AmountOff: 2},
+			"WINTER50": {Code: "WINTER50", PercentOff: 50, ExpiresAt: &expired},
+		},
+	}
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	handler := NewParkingHandler(svc)
+	api.RegisterHandlersWithOptions(router, handler, api.GinServerOptions{ErrorHandler: handler.ErrorHandler})
+
+	testCases := []struct {
+		name           string
+		code           string
+		expectedCharge float32
+		expectedCoupon string
+	}{
+		{name: "Percent coupon", code: "SPRING20", expectedCharge: 6.0, expectedCoupon: "SPRING20"},
+		{name: "Fixed coupon", code: "TWOOFF", expectedCharge: 5.5, expectedCoupon: "TWOOFF"},
+		{name: "Expired coupon", code: "WINTER50", expectedCharge: 7.5},
+		{name: "Unknown coupon", code: "NOPE", expectedCharge: 7.5},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			svc.ExpectedCalls = nil
+			svc.Calls = nil
+
+			ticketID := uuid.New()
+			entryTime := time.Now().Add(-45 * time.Minute)
+			ticket := &model.ParkingTicket{TicketID: ticketID.String(), Plate: "XYZ-789", ParkingLot: 123, EntryTime: entryTime}
+			svc.On("GetTicket", mock.Anything, ticketID.String()).Return(ticket, true).Once()
+			svc.On("CalculateChargeDetailed", 123, entryTime).Return(45*time.Minute, 45, float32(7.5)).Once()
+			svc.On("UpdateTicket", mock.Anything, mock.MatchedBy(func(updated *model.ParkingTicket) bool {
+				return updated.Charge == tc.expectedCharge && updated.CouponCode == tc.expectedCoupon
+			})).Return(nil).Once()
+			svc.On("ReleaseSpaces", mock.Anything, 123, 1).Once()
+
+			req := httptest.NewRequest("POST", "/exit?ticketId="+ticketID.String()+"&couponCode="+tc.code, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			// Invalid codes are ignored rather than failing the exit
+			assert.Equal(t, http.StatusOK, w.Code)
+			var response api.ExitResponse
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, tc.expectedCharge, response.Charge)
+			if tc.expectedCoupon == "" {
+				assert.Nil(t, response.CouponCode)
+			} else if assert.NotNil(t, response.CouponCode) {
+				assert.Equal(t, tc.expectedCoupon, *response.CouponCode)
+			}
+			svc.AssertExpectations(t)
+		})
+	}
+}
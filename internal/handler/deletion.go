@@ -0,0 +1,88 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"parking-lot/internal/logger"
+	"parking-lot/internal/service"
+	"parking-lot/server/api"
+)
+
+// DeleteAdminTicket deletes the active ticket of a plate created in error, e.g. in the wrong lot.
+// The ticket's spaces are returned to its lot and the deletion is recorded in the event log.
+func (h *ParkingHandler) DeleteAdminTicket(c *gin.Context, params api.DeleteAdminTicketParams) {
+	ctx, span := tracer.Start(c.Request.Context(), "DeleteTicket")
+	defer span.End()
+
+	log := h.log.WithContext(ctx).WithFields(logger.Plate(params.Plate))
+	log.Info("Processing ticket deletion")
+
+	if !h.authorizeAdmin(c, log) {
+		return
+	}
+
+	if h.serviceUnavailable() {
+		log.Warn("Storage unavailable, failing fast")
+		h.respondUnavailable(c)
+		return
+	}
+
+	if h.readOnly() {
+		log.Warn("Service is read-only, rejecting ticket deletion")
+		h.respondReadOnly(c)
+		return
+	}
+
+	deleter, ok := h.service.(service.TicketDeleter)
+	if !ok {
+		log.Error("Service does not support ticket deletion")
+		h.respond(c, http.StatusNotImplemented, api.ErrorResponse{
+			Code:    CodeNotImplemented,
+			Message: "Ticket deletion is not supported",
+		})
+		return
+	}
+
+	ticket, err := deleter.DeleteActiveTicket(ctx, params.Plate)
+	if errors.Is(err, service.ErrNoActiveTicket) {
+		log.Warn("No active ticket for plate")
+		h.respond(c, http.StatusNotFound, api.ErrorResponse{
+			Code:    CodeNoActiveTicket,
+			Message: "No active ticket for plate",
+		})
+		return
+	}
+	if errors.Is(err, service.ErrDynamoDBUnavailable) {
+		log.Warn("Storage unavailable, failing fast")
+		h.respondUnavailable(c)
+		return
+	}
+	if err != nil {
+		log.Error("Failed to delete ticket", logger.Field{Key: "error", Value: err.Error()})
+		h.respond(c, http.StatusInternalServerError, api.ErrorResponse{
+			Code:    CodeInternalError,
+			Message: "Failed to delete ticket",
+		})
+		return
+	}
+
+	ticketID, err := uuid.Parse(ticket.TicketID)
+	if err != nil {
+		log.Error("Deleted ticket has an invalid ID", logger.Field{Key: "ticket_id", Value: ticket.TicketID})
+		h.respond(c, http.StatusInternalServerError, api.ErrorResponse{
+			Code:    CodeInternalError,
+			Message: "Failed to delete ticket",
+		})
+		return
+	}
+
+	log.Info("Ticket deleted",
+		logger.Field{Key: "ticket_id", Value: ticket.TicketID},
+		logger.Field{Key: "parking_lot", Value: ticket.ParkingLot},
+	)
+	h.respond(c, http.StatusOK, ticketResponse(ticketID, ticket))
+}
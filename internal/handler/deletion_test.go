@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"parking-lot/server/api"
+)
+
+// TestDeleteAdminTicket tests deleting a ticket created in error through the admin API
+func TestDeleteAdminTicket(t *testing.T) {
+	router, memoryService := setupCorrectionRouter(t)
+	deleteTicket := func(plate, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("DELETE", "/admin/ticket?plate="+plate, nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/entry?plate=ABC-123&parkingLot=5&spaces=2", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	var entry api.EntryResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &entry))
+
+	t.Run("Missing token", func(t *testing.T) {
+		w := deleteTicket("ABC-123", "")
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		_, ok := memoryService.GetTicket(t.Context(), entry.TicketId.String())
+		assert.True(t, ok)
+	})
+
+	t.Run("Active ticket", func(t *testing.T) {
+		w := deleteTicket("ABC-123", "secret")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response api.TicketResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, entry.TicketId, response.TicketId)
+		assert.Equal(t, "ABC-123", response.Plate)
+		_, ok := memoryService.GetTicket(t.Context(), entry.TicketId.String())
+		assert.False(t, ok)
+		assert.Equal(t, 0, memoryService.OccupancySnapshot()[5])
+	})
+
+	t.Run("No active ticket", func(t *testing.T) {
+		w := deleteTicket("ABC-123", "secret")
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Contains(t, w.Body.String(), CodeNoActiveTicket)
+	})
+}
@@ -0,0 +1,170 @@
+package handler
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"parking-lot/internal/logger"
+	"parking-lot/internal/model"
+	"parking-lot/internal/service"
+	"parking-lot/server/api"
+)
+
+// exportHeader is the header row of a CSV ticket export
+var exportHeader = []string{"plate", "lot", "entryTime", "exitTime", "minutes", "charge"}
+
+// exportFlushRows is how many rows are written between flushes of a streamed export
+const exportFlushRows = 100
+
+// exportTruncatedTrailer reports whether an export stopped at MAX_SCAN_PAGES. The rows are already
+// streamed when that is known, so it is sent as a trailer, which the Lambda adapter returns as a header.
+const exportTruncatedTrailer = "X-Export-Truncated"
+
+// GetExport streams the tickets that exited in a date range as CSV. Rows are flushed as they
+// are listed so large exports are never held in memory.
+func (h *ParkingHandler) GetExport(c *gin.Context, params api.GetExportParams) {
+	ctx, span := tracer.Start(c.Request.Context(), "GetExport")
+	defer span.End()
+
+	from := params.From.Time.UTC()
+	to := params.To.Time.UTC()
+	log := h.log.WithContext(ctx).WithFields(
+		logger.Field{Key: "from", Value: params.From.String()},
+		logger.Field{Key: "to", Value: params.To.String()},
+	)
+	log.Info("Exporting tickets")
+
+	if !h.authorizeAdmin(c, log) {
+		return
+	}
+
+	if params.Format != nil && *params.Format != api.Csv {
+		log.Warn("Unsupported export format", logger.Field{Key: "format", Value: *params.Format})
+		h.respond(c, http.StatusBadRequest, api.ErrorResponse{
+			Code:    CodeValidationFailed,
+			Message: "format must be csv",
+		})
+		return
+	}
+	if from.After(to) {
+		log.Warn("Invalid export range")
+		h.respond(c, http.StatusBadRequest, api.ErrorResponse{
+			Code:    CodeValidationFailed,
+			Message: "from must not be after to",
+		})
+		return
+	}
+
+	if h.serviceUnavailable() {
+		log.Warn("Storage unavailable, failing fast")
+		h.respondUnavailable(c)
+		return
+	}
+
+	lister, ok := h.service.(service.TicketLister)
+	if !ok {
+		log.Error("Service does not support listing tickets")
+		h.respond(c, http.StatusNotImplemented, api.ErrorResponse{
+			Code:    CodeNotImplemented,
+			Message: "Export is not supported",
+		})
+		return
+	}
+
+	// The range is inclusive of whole days, so list up to the start of the day after to
+	until := to.AddDate(0, 0, 1)
+	filename := fmt.Sprintf("tickets-%s-%s.csv", params.From.String(), params.To.String())
+
+	writer := csv.NewWriter(c.Writer)
+	started := false
+	// start sends the headers and the header row; it runs with the first row so a listing
+	// that fails before any ticket is found can still answer with an error
+	start := func() error {
+		started = true
+		c.Header("Trailer", exportTruncatedTrailer)
+		c.Header("Content-Type", "text/csv")
+		c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+		c.Status(http.StatusOK)
+		return writer.Write(exportHeader)
+	}
+
+	rows := 0
+	err := lister.ListExitedTickets(ctx, from, until, func(ticket *model.ParkingTicket) error {
+		if !started {
+			if err := start(); err != nil {
+				return err
+			}
+		}
+		if err := writer.Write(exportRow(ticket)); err != nil {
+			return err
+		}
+		if rows++; rows%exportFlushRows == 0 {
+			writer.Flush()
+			c.Writer.Flush()
+		}
+		return writer.Error()
+	})
+
+	// A truncated listing still streamed every ticket it read, so the export completes and is flagged
+	truncated := errors.Is(err, service.ErrScanTruncated)
+	if truncated {
+		log.Warn("Ticket export stopped at MAX_SCAN_PAGES", logger.Field{Key: "rows", Value: rows})
+		err = nil
+	}
+
+	switch {
+	case err != nil && !started:
+		log.Error("Failed to list tickets", logger.Field{Key: "error", Value: err.Error()})
+		h.respond(c, http.StatusInternalServerError, api.ErrorResponse{
+			Code:    CodeInternalError,
+			Message: "Failed to export tickets",
+		})
+		return
+	case err != nil:
+		// The status line may already be sent, so the truncated export can only be logged
+		writer.Flush()
+		log.Error("Ticket export interrupted",
+			logger.Field{Key: "error", Value: err.Error()},
+			logger.Field{Key: "rows", Value: rows},
+		)
+		return
+	case !started:
+		// An empty range still gets a header row
+		if err := start(); err != nil {
+			log.Error("Failed to write export", logger.Field{Key: "error", Value: err.Error()})
+			return
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		log.Error("Failed to write export", logger.Field{Key: "error", Value: err.Error()})
+		return
+	}
+	c.Writer.Header().Set(exportTruncatedTrailer, strconv.FormatBool(truncated))
+
+	log.Info("Exported tickets", logger.Field{Key: "rows", Value: rows}, logger.Field{Key: "truncated", Value: truncated})
+}
+
+// exportRow formats an exited ticket as a CSV row in exportHeader order
+func exportRow(ticket *model.ParkingTicket) []string {
+	minutes := ticket.DurationMinutes
+	if minutes == 0 && ticket.ExitTime != nil {
+		// Tickets exited before durations were recorded fall back to the timestamps
+		minutes = int(ticket.ExitTime.Sub(ticket.EntryTime).Minutes())
+	}
+	return []string{
+		ticket.Plate,
+		strconv.Itoa(ticket.ParkingLot),
+		ticket.EntryTime.UTC().Format(time.RFC3339),
+		ticket.ExitTime.UTC().Format(time.RFC3339),
+		strconv.Itoa(minutes),
+		strconv.FormatFloat(float64(ticket.Charge), 'f', 2, 32),
+	}
+}
@@ -0,0 +1,141 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"parking-lot/internal/mocks"
+	"parking-lot/internal/model"
+	"parking-lot/internal/service"
+	"parking-lot/server/api"
+)
+
+// listerService serves a fixed set of exited tickets from the mock service
+type listerService struct {
+	*mocks.ParkingService
+	tickets []*model.ParkingTicket
+	err     error
+
+	from, to time.Time
+}
+
+// ListExitedTickets records the requested range, passes every stored ticket to fn and returns err
+func (l *listerService) ListExitedTickets(ctx context.Context, from, to time.Time, fn func(*model.ParkingTicket) error) error {
+	l.from, l.to = from, to
+	for _, ticket := range l.tickets {
+		if err := fn(ticket); err != nil {
+			return err
+		}
+	}
+	return l.err
+}
+
+// setupExportRouter registers all routes on a handler using the given service and admin token
+func setupExportRouter(svc *listerService) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	handler := NewParkingHandler(svc)
+	handler.adminToken = "secret"
+
+	router := gin.New()
+	api.RegisterHandlers(router, handler)
+	return router
+}
+
+// exportRequest builds an authorized export request for the given query
+func exportRequest(query string) *http.Request {
+	req := httptest.NewRequest("GET", "/export?"+query, nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	return req
+}
+
+// TestGetExport tests that exited tickets are streamed as CSV
+func TestGetExport(t *testing.T) {
+	entry := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+	exit := entry.Add(90 * time.Minute)
+	lateExit := time.Date(2024, 5, 2, 18, 30, 0, 0, time.UTC)
+
+	t.Run("Header and rows", func(t *testing.T) {
+		svc := &listerService{ParkingService: new(mocks.ParkingService), tickets: []*model.ParkingTicket{
+			{Plate: "ABC-123", ParkingLot: 1, EntryTime: entry, ExitTime: &exit, Status: model.TicketStatusOut, Charge: 15, DurationMinutes: 90},
+			{Plate: "XYZ-789", ParkingLot: 2, EntryTime: entry, ExitTime: &lateExit, Status: model.TicketStatusOut, Charge: 7.5},
+		}}
+
+		w := httptest.NewRecorder()
+		setupExportRouter(svc).ServeHTTP(w, exportRequest("from=2024-05-01&to=2024-05-02&format=csv"))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "text/csv", w.Header().Get("Content-Type"))
+		assert.Contains(t, w.Header().Get("Content-Disposition"), "tickets-2024-05-01-2024-05-02.csv")
+		lines := strings.Split(strings.TrimSpace(w.Body.String()), "\n")
+		assert.Equal(t, []string{
+			"plate,lot,entryTime,exitTime,minutes,charge",
+			"ABC-123,1,2024-05-01T09:00:00Z,2024-05-01T10:30:00Z,90,15.00",
+			"XYZ-789,2,2024-05-01T09:00:00Z,2024-05-02T18:30:00Z,2010,7.50",
+		}, lines)
+		assert.Equal(t, "false", w.Header().Get("X-Export-Truncated"))
+		// to is inclusive, so the listing runs until the start of the following day
+		assert.Equal(t, time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), svc.from)
+		assert.Equal(t, time.Date(2024, 5, 3, 0, 0, 0, 0, time.UTC), svc.to)
+	})
+
+	t.Run("Empty range", func(t *testing.T) {
+		svc := &listerService{ParkingService: new(mocks.ParkingService)}
+
+		w := httptest.NewRecorder()
+		setupExportRouter(svc).ServeHTTP(w, exportRequest("from=2024-05-01&to=2024-05-01"))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "plate,lot,entryTime,exitTime,minutes,charge\n", w.Body.String())
+	})
+
+	t.Run("Reversed range", func(t *testing.T) {
+		svc := &listerService{ParkingService: new(mocks.ParkingService)}
+
+		w := httptest.NewRecorder()
+		setupExportRouter(svc).ServeHTTP(w, exportRequest("from=2024-05-02&to=2024-05-01"))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.True(t, svc.from.IsZero())
+	})
+
+	t.Run("Listing fails", func(t *testing.T) {
+		svc := &listerService{ParkingService: new(mocks.ParkingService), err: errors.New("boom")}
+
+		w := httptest.NewRecorder()
+		setupExportRouter(svc).ServeHTTP(w, exportRequest("from=2024-05-01&to=2024-05-02"))
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.JSONEq(t, `{"code":"INTERNAL_ERROR","message":"Failed to export tickets"}`, w.Body.String())
+	})
+
+	t.Run("Stopped at MAX_SCAN_PAGES", func(t *testing.T) {
+		svc := &listerService{ParkingService: new(mocks.ParkingService), err: service.ErrScanTruncated, tickets: []*model.ParkingTicket{
+			{Plate: "ABC-123", ParkingLot: 1, EntryTime: entry, ExitTime: &exit, Status: model.TicketStatusOut, Charge: 15, DurationMinutes: 90},
+		}}
+
+		w := httptest.NewRecorder()
+		setupExportRouter(svc).ServeHTTP(w, exportRequest("from=2024-05-01&to=2024-05-02"))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "X-Export-Truncated", w.Header().Get("Trailer"))
+		assert.Equal(t, "true", w.Header().Get("X-Export-Truncated"))
+		assert.Equal(t, "plate,lot,entryTime,exitTime,minutes,charge\nABC-123,1,2024-05-01T09:00:00Z,2024-05-01T10:30:00Z,90,15.00\n", w.Body.String())
+	})
+
+	t.Run("Unauthorized", func(t *testing.T) {
+		svc := &listerService{ParkingService: new(mocks.ParkingService)}
+
+		w := httptest.NewRecorder()
+		setupExportRouter(svc).ServeHTTP(w, httptest.NewRequest("GET", "/export?from=2024-05-01&to=2024-05-02", nil))
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+}
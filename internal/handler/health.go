@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"parking-lot/internal/logger"
+	"parking-lot/internal/service"
+	"parking-lot/server/api"
+)
+
+// readinessTimeout bounds the storage check of a readiness probe, so a hanging DynamoDB
+// fails the probe instead of outlasting the orchestrator's own timeout
+const readinessTimeout = 2 * time.Second
+
+// GetLivez answers the liveness probe. It never touches storage: an unreachable DynamoDB
+// is not fixed by restarting the process, so it only fails readiness.
+func (h *ParkingHandler) GetLivez(c *gin.Context) {
+	h.respond(c, http.StatusOK, api.HealthResponse{Status: "ok"})
+}
+
+// GetReadyz answers the readiness probe, which succeeds only while DynamoDB is reachable and,
+// with CHECK_INDEX=true, the table's secondary indexes are active
+func (h *ParkingHandler) GetReadyz(c *gin.Context) {
+	ctx, span := tracer.Start(c.Request.Context(), "GetReadyz")
+	defer span.End()
+
+	log := h.log.WithContext(ctx)
+
+	if h.serviceUnavailable() {
+		log.Warn("Not ready, storage circuit breaker is open")
+		h.respondUnavailable(c)
+		return
+	}
+
+	if checker, ok := h.service.(service.HealthChecker); ok {
+		ctx, cancel := context.WithTimeout(ctx, readinessTimeout)
+		defer cancel()
+
+		err := checker.HealthCheck(ctx)
+		if errors.Is(err, service.ErrIndexNotActive) {
+			log.Warn("Not ready, secondary index is not active", logger.Field{Key: "error", Value: err.Error()})
+			h.respond(c, http.StatusServiceUnavailable, api.ErrorResponse{
+				Code:    CodeIndexNotActive,
+				Message: "DynamoDB index is not active",
+			})
+			return
+		}
+		if err != nil {
+			log.Warn("Not ready, storage is unreachable", logger.Field{Key: "error", Value: err.Error()})
+			h.respond(c, http.StatusServiceUnavailable, api.ErrorResponse{
+				Code:    CodeServiceUnavailable,
+				Message: "DynamoDB is unreachable",
+			})
+			return
+		}
+	}
+
+	h.respond(c, http.StatusOK, api.HealthResponse{Status: "ok"})
+}
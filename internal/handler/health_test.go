@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"parking-lot/internal/mocks"
+	"parking-lot/internal/service"
+	"parking-lot/server/api"
+)
+
+// healthService adds a health check with a fixed result to the mock service
+type healthService struct {
+	*mocks.ParkingService
+	err error
+}
+
+// HealthCheck returns the fixed result
+func (h *healthService) HealthCheck(ctx context.Context) error {
+	return h.err
+}
+
+// TestHealthProbes tests that liveness ignores DynamoDB while readiness follows it
+func TestHealthProbes(t *testing.T) {
+	testCases := []struct {
+		name          string
+		err           error
+		livezStatus   int
+		readyzStatus  int
+		readyzMessage string
+	}{
+		{name: "Healthy DynamoDB", livezStatus: http.StatusOK, readyzStatus: http.StatusOK},
+		{
+			name:          "Unreachable DynamoDB",
+			err:           errors.New("dial tcp: connection refused"),
+			livezStatus:   http.StatusOK,
+			readyzStatus:  http.StatusServiceUnavailable,
+			readyzMessage: "DynamoDB is unreachable",
+		},
+		{
+			name:          "Index still backfilling",
+			err:           fmt.Errorf("%w: PlateIndex is CREATING", service.ErrIndexNotActive),
+			livezStatus:   http.StatusOK,
+			readyzStatus:  http.StatusServiceUnavailable,
+			readyzMessage: "DynamoDB index is not active",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			svc := &healthService{ParkingService: new(mocks.ParkingService), err: tc.err}
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			handler := NewParkingHandler(svc)
+			api.RegisterHandlersWithOptions(router, handler, api.GinServerOptions{ErrorHandler: handler.ErrorHandler})
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest("GET", "/livez", nil))
+			assert.Equal(t, tc.livezStatus, w.Code)
+			assert.JSONEq(t, `{"status":"ok"}`, w.Body.String())
+
+			w = httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest("GET", "/readyz", nil))
+			assert.Equal(t, tc.readyzStatus, w.Code)
+			if tc.readyzMessage != "" {
+				assert.Contains(t, w.Body.String(), tc.readyzMessage)
+			} else {
+				assert.JSONEq(t, `{"status":"ok"}`, w.Body.String())
+			}
+		})
+	}
+}
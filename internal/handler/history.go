@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+
+	"parking-lot/internal/logger"
+	"parking-lot/internal/service"
+	"parking-lot/server/api"
+)
+
+// GetPlatePlateHistory lists the past parking sessions of a plate, newest first. A plate's history
+// reveals where a vehicle has been, so it is served to staff holding the admin token and to drivers
+// presenting a ticket issued to the plate.
+func (h *ParkingHandler) GetPlatePlateHistory(c *gin.Context, plate string, params api.GetPlatePlateHistoryParams) {
+	ctx, span := tracer.Start(c.Request.Context(), "GetPlateHistory")
+	defer span.End()
+
+	log := h.log.WithContext(ctx).WithFields(logger.Plate(plate))
+	log.Info("Looking up plate history")
+
+	if params.TicketId != nil {
+		if !h.authorizePlateTicket(ctx, c, log, plate, *params.TicketId) {
+			return
+		}
+	} else if !h.authorizeAdmin(c, log) {
+		return
+	}
+
+	limit, err := h.pageLimit(c, params.Limit)
+	if err != nil {
+		log.Warn("Invalid history limit", logger.Field{Key: "limit", Value: *params.Limit})
+		h.respond(c, http.StatusBadRequest, api.ErrorResponse{Code: CodeValidationFailed, Message: err.Error()})
+		return
+	}
+
+	if h.serviceUnavailable() {
+		log.Warn("Storage unavailable, failing fast")
+		h.respondUnavailable(c)
+		return
+	}
+
+	history, ok := h.service.(service.PlateHistory)
+	if !ok {
+		log.Error("Service does not support plate history")
+		h.respond(c, http.StatusNotImplemented, api.ErrorResponse{
+			Code:    CodeNotImplemented,
+			Message: "Plate history is not supported",
+		})
+		return
+	}
+
+	tickets, err := history.PlateHistory(ctx, plate, limit)
+	if errors.Is(err, service.ErrDynamoDBUnavailable) {
+		log.Warn("Storage unavailable, failing fast")
+		h.respondUnavailable(c)
+		return
+	}
+	if err != nil {
+		log.Error("Failed to look up plate history", logger.Field{Key: "error", Value: err.Error()})
+		h.respond(c, http.StatusInternalServerError, api.ErrorResponse{
+			Code:    CodeInternalError,
+			Message: "Failed to look up plate history",
+		})
+		return
+	}
+
+	response := api.PlateHistoryResponse{
+		Plate:    plate,
+		Sessions: make([]api.TicketResponse, 0, len(tickets)),
+	}
+	for _, ticket := range tickets {
+		ticketID, err := uuid.Parse(ticket.TicketID)
+		if err != nil {
+			log.Warn("Skipping ticket with invalid ID", logger.Field{Key: "ticket_id", Value: ticket.TicketID})
+			continue
+		}
+		response.Sessions = append(response.Sessions, ticketResponse(ticketID, ticket))
+	}
+
+	log.Info("Plate history found", logger.Field{Key: "sessions", Value: len(response.Sessions)})
+	h.respond(c, http.StatusOK, response)
+}
+
+// authorizePlateTicket checks that a driver's ticket was issued to the plate and answers the request
+// when it was not. Unknown tickets and tickets of other plates get the same answer, so the check does
+// not reveal which tickets exist.
+func (h *ParkingHandler) authorizePlateTicket(ctx context.Context, c *gin.Context, log logger.Logger, plate string, ticketID openapi_types.UUID) bool {
+	if h.serviceUnavailable() {
+		log.Warn("Storage unavailable, failing fast")
+		h.respondUnavailable(c)
+		return false
+	}
+
+	ticket, exists := h.service.GetTicket(ctx, ticketID.String())
+	if !exists || ticket.Plate != plate {
+		log.Warn("Plate history rejected, ticket not issued to the plate", logger.Field{Key: "ticket_id", Value: ticketID})
+		h.respond(c, http.StatusUnauthorized, api.ErrorResponse{
+			Code:    CodeUnauthorized,
+			Message: "Unauthorized",
+		})
+		return false
+	}
+	return true
+}
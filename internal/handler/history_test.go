@@ -0,0 +1,185 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"parking-lot/internal/mocks"
+	"parking-lot/internal/model"
+	"parking-lot/server/api"
+)
+
+// historyService adds a fixed plate history, newest first, to the mock service
+type historyService struct {
+	*mocks.ParkingService
+	tickets   []*model.ParkingTicket
+	lastLimit int
+}
+
+// PlateHistory returns up to limit tickets of the fixed history
+func (h *historyService) PlateHistory(ctx context.Context, plate string, limit int) ([]*model.ParkingTicket, error) {
+	h.lastLimit = limit
+	if len(h.tickets) > limit {
+		return h.tickets[:limit], nil
+	}
+	return h.tickets, nil
+}
+
+// setupHistoryRouter registers all routes on an admin-enabled handler using the given service
+func setupHistoryRouter(svc *historyService) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	handler := NewParkingHandler(svc)
+	handler.adminToken = "secret"
+	router := gin.New()
+	api.RegisterHandlersWithOptions(router, handler, api.GinServerOptions{ErrorHandler: handler.ErrorHandler})
+	return router
+}
+
+// TestGetPlatePlateHistory tests that a plate's past sessions are returned newest first and limited
+func TestGetPlatePlateHistory(t *testing.T) {
+	entryTime := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+	var tickets []*model.ParkingTicket
+	for i := 3; i > 0; i-- {
+		entry := entryTime.AddDate(0, 0, i)
+		exit := entry.Add(time.Hour)
+		tickets = append(tickets, &model.ParkingTicket{
+			TicketID: uuid.NewString(), Plate: "ABC-123", ParkingLot: i, EntryTime: entry,
+			Status: model.TicketStatusOut, ExitTime: &exit, Charge: float32(i) * 10,
+		})
+	}
+	svc := &historyService{ParkingService: new(mocks.ParkingService), tickets: tickets}
+	router := setupHistoryRouter(svc)
+
+	request := func(query string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/plate/ABC-123/history"+query, nil)
+		req.Header.Set("Authorization", "Bearer secret")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("Default limit", func(t *testing.T) {
+		w := request("")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, defaultPageSize, svc.lastLimit)
+		assert.Equal(t, "10", w.Header().Get(pageLimitHeader))
+		var response api.PlateHistoryResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "ABC-123", response.Plate)
+		if assert.Len(t, response.Sessions, 3) {
+			for i, session := range response.Sessions {
+				assert.Equal(t, tickets[i].TicketID, session.TicketId.String())
+				assert.Equal(t, "out", session.Status)
+				if assert.NotNil(t, session.Charge) {
+					assert.Equal(t, tickets[i].Charge, *session.Charge)
+				}
+			}
+			assert.True(t, response.Sessions[0].EntryTime.After(response.Sessions[1].EntryTime))
+			assert.True(t, response.Sessions[1].EntryTime.After(response.Sessions[2].EntryTime))
+		}
+	})
+
+	t.Run("Limit", func(t *testing.T) {
+		w := request("?limit=2")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, 2, svc.lastLimit)
+		assert.Equal(t, "2", w.Header().Get(pageLimitHeader))
+		var response api.PlateHistoryResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		if assert.Len(t, response.Sessions, 2) {
+			assert.Equal(t, tickets[0].TicketID, response.Sessions[0].TicketId.String())
+			assert.Equal(t, tickets[1].TicketID, response.Sessions[1].TicketId.String())
+		}
+	})
+
+	t.Run("No history", func(t *testing.T) {
+		empty := &historyService{ParkingService: new(mocks.ParkingService)}
+		req := httptest.NewRequest("GET", "/plate/NEW-001/history", nil)
+		req.Header.Set("Authorization", "Bearer secret")
+		w := httptest.NewRecorder()
+		setupHistoryRouter(empty).ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"plate":"NEW-001","sessions":[]}`, w.Body.String())
+	})
+
+	t.Run("Limit over the maximum is clamped", func(t *testing.T) {
+		w := request("?limit=1000000")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, defaultMaxPageSize, svc.lastLimit)
+		assert.Equal(t, "100", w.Header().Get(pageLimitHeader))
+	})
+
+	t.Run("Invalid limit", func(t *testing.T) {
+		w := request("?limit=0")
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "limit must be at least 1")
+	})
+
+	t.Run("Missing token", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/plate/ABC-123/history", nil))
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+}
+
+// TestGetPlatePlateHistory_DriverTicket tests that a ticket issued to the plate authorizes a driver
+// for that plate's history only
+func TestGetPlatePlateHistory_DriverTicket(t *testing.T) {
+	ownTicket, otherTicket, unknownTicket := uuid.New(), uuid.New(), uuid.New()
+	mockService := new(mocks.ParkingService)
+	mockService.On("GetTicket", mock.Anything, ownTicket.String()).
+		Return(&model.ParkingTicket{TicketID: ownTicket.String(), Plate: "ABC-123", Status: model.TicketStatusIn}, true)
+	mockService.On("GetTicket", mock.Anything, otherTicket.String()).
+		Return(&model.ParkingTicket{TicketID: otherTicket.String(), Plate: "XYZ-999", Status: model.TicketStatusOut}, true)
+	mockService.On("GetTicket", mock.Anything, unknownTicket.String()).Return(nil, false)
+
+	exit := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	svc := &historyService{ParkingService: mockService, tickets: []*model.ParkingTicket{{
+		TicketID: uuid.NewString(), Plate: "ABC-123", ParkingLot: 1, EntryTime: exit.Add(-time.Hour),
+		Status: model.TicketStatusOut, ExitTime: &exit, Charge: 10,
+	}}}
+	router := setupHistoryRouter(svc)
+
+	request := func(ticketID uuid.UUID) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/plate/ABC-123/history?ticketId="+ticketID.String(), nil))
+		return w
+	}
+
+	t.Run("Ticket of the plate", func(t *testing.T) {
+		w := request(ownTicket)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response api.PlateHistoryResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Len(t, response.Sessions, 1)
+	})
+
+	t.Run("Ticket of another plate", func(t *testing.T) {
+		w := request(otherTicket)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		assert.Contains(t, w.Body.String(), CodeUnauthorized)
+	})
+
+	t.Run("Unknown ticket", func(t *testing.T) {
+		w := request(unknownTicket)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+}
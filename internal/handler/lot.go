@@ -0,0 +1,41 @@
+package handler
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+
+	"parking-lot/internal/logger"
+)
+
+// defaultMaxLot is the highest lot number accepted unless MAX_LOT is set. It keeps lot numbers
+// within 32 bits so clients and stores with narrower integers than Go's can hold them.
+const defaultMaxLot = math.MaxInt32
+
+// loadMaxLot reads MAX_LOT, the highest accepted lot number. Unset or invalid values fall back
+// to defaultMaxLot; invalid ones are logged.
+func loadMaxLot(log logger.Logger) int {
+	raw := os.Getenv("MAX_LOT")
+	if raw == "" {
+		return defaultMaxLot
+	}
+	maxLot, err := strconv.Atoi(raw)
+	if err != nil || maxLot < 1 || maxLot > defaultMaxLot {
+		log.Warn("Invalid MAX_LOT, using the default",
+			logger.Field{Key: "max_lot", Value: raw},
+			logger.Field{Key: "default", Value: defaultMaxLot},
+		)
+		return defaultMaxLot
+	}
+	return maxLot
+}
+
+// checkParkingLot returns an error when lot falls outside [1, maxLot]. It is shared by single and
+// batch entries; query values too large for an int never get this far, since binding them fails with 400.
+func checkParkingLot(lot, maxLot int) error {
+	if lot < 1 || lot > maxLot {
+		return fmt.Errorf("parkingLot must be between 1 and %d", maxLot)
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"parking-lot/internal/mocks"
+	"parking-lot/internal/model"
+	"parking-lot/server/api"
+)
+
+// TestPostEntry_ParkingLotBounds tests that lot numbers outside [1, MAX_LOT] are rejected with 400
+func TestPostEntry_ParkingLotBounds(t *testing.T) {
+	t.Setenv("MAX_LOT", "1000")
+
+	testCases := []struct {
+		name           string
+		parkingLot     string
+		expectedStatus int
+		expectedError  string
+	}{
+		{name: "In range", parkingLot: "382", expectedStatus: http.StatusOK},
+		{name: "Highest lot", parkingLot: "1000", expectedStatus: http.StatusOK},
+		{name: "Above MAX_LOT", parkingLot: "1001", expectedStatus: http.StatusBadRequest, expectedError: "parkingLot must be between 1 and 1000"},
+		{name: "Zero", parkingLot: "0", expectedStatus: http.StatusBadRequest, expectedError: "parkingLot must be between 1 and 1000"},
+		{name: "Negative", parkingLot: "-5", expectedStatus: http.StatusBadRequest, expectedError: "parkingLot must be between 1 and 1000"},
+		{name: "Overflow", parkingLot: "99999999999999999999", expectedStatus: http.StatusBadRequest, expectedError: "Invalid format for parameter parkingLot"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockService := new(mocks.ParkingService)
+			router := setupTestRouter(mockService)
+			if tc.expectedStatus == http.StatusOK {
+				ticketID := uuid.New()
+				mockService.On("FindActiveTicket", mock.Anything, "ABC-123", mock.Anything).Return(nil, nil).Once()
+				mockService.On("FindReentryTicket", mock.Anything, "ABC-123", mock.Anything).Return(nil, nil).Maybe()
+				mockService.On("ReserveSpaces", mock.Anything, mock.Anything, 1).Return(nil).Once()
+				mockService.On("CreateTicket", mock.Anything, "ABC-123", mock.Anything, 1).
+					Return(ticketID, &model.ParkingTicket{TicketID: ticketID.String()}).Once()
+				mockService.On("Rates").Return(model.RateSchedule{}).Maybe()
+			}
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest("POST", "/entry?plate=ABC-123&parkingLot="+tc.parkingLot, nil))
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+			if tc.expectedError != "" {
+				var response api.ErrorResponse
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				assert.Contains(t, response.Message, tc.expectedError)
+			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+// TestLoadMaxLot tests reading MAX_LOT, falling back to the default for invalid values
+func TestLoadMaxLot(t *testing.T) {
+	log := mocks.NewLogger()
+
+	t.Setenv("MAX_LOT", "")
+	assert.Equal(t, defaultMaxLot, loadMaxLot(log))
+
+	t.Setenv("MAX_LOT", "500")
+	assert.Equal(t, 500, loadMaxLot(log))
+
+	for _, invalid := range []string{"0", "-1", "lots", "99999999999"} {
+		t.Setenv("MAX_LOT", invalid)
+		assert.Equal(t, defaultMaxLot, loadMaxLot(log), invalid)
+	}
+}
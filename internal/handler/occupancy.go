@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"parking-lot/internal/logger"
+	"parking-lot/internal/service"
+	"parking-lot/server/api"
+)
+
+// PostAdminOccupancyReconcile recounts the spaces held by a lot's parked tickets and resets the lot's
+// occupancy counter to that count, correcting drift
+func (h *ParkingHandler) PostAdminOccupancyReconcile(c *gin.Context, params api.PostAdminOccupancyReconcileParams) {
+	ctx, span := tracer.Start(c.Request.Context(), "ReconcileOccupancy")
+	defer span.End()
+
+	log := h.log.WithContext(ctx).WithFields(logger.Field{Key: "parking_lot", Value: params.ParkingLot})
+	log.Info("Processing occupancy reconciliation")
+
+	if !h.authorizeAdmin(c, log) {
+		return
+	}
+
+	if err := checkParkingLot(params.ParkingLot, h.maxLot); err != nil {
+		log.Warn("Invalid parking lot")
+		h.respond(c, http.StatusBadRequest, api.ErrorResponse{
+			Code:    CodeValidationFailed,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if h.serviceUnavailable() {
+		log.Warn("Storage unavailable, failing fast")
+		h.respondUnavailable(c)
+		return
+	}
+
+	reconciler, ok := h.service.(service.OccupancyReconciler)
+	if !ok {
+		log.Error("Service does not support occupancy reconciliation")
+		h.respond(c, http.StatusNotImplemented, api.ErrorResponse{
+			Code:    CodeNotImplemented,
+			Message: "Occupancy reconciliation is not supported",
+		})
+		return
+	}
+
+	occupied, err := reconciler.ReconcileOccupancy(ctx, params.ParkingLot)
+	if errors.Is(err, service.ErrOccupancyNotSettable) {
+		log.Error("Occupancy counter cannot be reconciled")
+		h.respond(c, http.StatusNotImplemented, api.ErrorResponse{
+			Code:    CodeNotImplemented,
+			Message: "Occupancy reconciliation is not supported",
+		})
+		return
+	}
+	if errors.Is(err, service.ErrOccupancyChanged) {
+		log.Warn("Occupancy kept changing, reconciliation abandoned")
+		h.respond(c, http.StatusConflict, api.ErrorResponse{
+			Code:    CodeOccupancyChanged,
+			Message: "Occupancy kept changing while counting; retry later",
+		})
+		return
+	}
+	if errors.Is(err, service.ErrScanTruncated) {
+		log.Error("Too many parked tickets to count", logger.Field{Key: "error", Value: err.Error()})
+		h.respond(c, http.StatusInternalServerError, api.ErrorResponse{
+			Code:    CodeInternalError,
+			Message: "Too many parked tickets to count within MAX_SCAN_PAGES",
+		})
+		return
+	}
+	if errors.Is(err, service.ErrDynamoDBUnavailable) {
+		log.Warn("Storage unavailable, failing fast")
+		h.respondUnavailable(c)
+		return
+	}
+	if err != nil {
+		log.Error("Failed to reconcile occupancy", logger.Field{Key: "error", Value: err.Error()})
+		h.respond(c, http.StatusInternalServerError, api.ErrorResponse{
+			Code:    CodeInternalError,
+			Message: "Failed to reconcile occupancy",
+		})
+		return
+	}
+
+	log.Info("Occupancy reconciled", logger.Field{Key: "occupied", Value: occupied})
+	h.respond(c, http.StatusOK, api.OccupancyResponse{
+		ParkingLot: params.ParkingLot,
+		Occupied:   occupied,
+	})
+}
@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"parking-lot/internal/mocks"
+	"parking-lot/internal/service"
+	"parking-lot/server/api"
+)
+
+// TestPostAdminOccupancyReconcile tests resetting a drifted occupancy counter through the admin API
+func TestPostAdminOccupancyReconcile(t *testing.T) {
+	router, memoryService := setupCorrectionRouter(t)
+	reconcile := func(query, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/admin/occupancy/reconcile?"+query, nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("Drifted counter", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("POST", "/entry?plate=ABC-123&parkingLot=5&spaces=2", nil))
+		require.Equal(t, http.StatusOK, w.Code)
+		// Spaces reserved without a ticket, e.g. by an entry that failed after reserving
+		require.NoError(t, memoryService.ReserveSpaces(context.Background(), 5, 4))
+
+		w = reconcile("parkingLot=5", "secret")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response api.OccupancyResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, api.OccupancyResponse{ParkingLot: 5, Occupied: 2}, response)
+		assert.Equal(t, 2, memoryService.OccupancySnapshot()[5])
+	})
+
+	t.Run("Missing token", func(t *testing.T) {
+		w := reconcile("parkingLot=5", "")
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("Invalid parking lot", func(t *testing.T) {
+		w := reconcile("parkingLot=0", "secret")
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), CodeValidationFailed)
+	})
+}
+
+// unsettableService reconciles with an occupancy counter that cannot be reset
+type unsettableService struct {
+	*mocks.ParkingService
+}
+
+// ReconcileOccupancy fails as a service with a counter that cannot be reset does
+func (s *unsettableService) ReconcileOccupancy(ctx context.Context, parkingLot int) (int, error) {
+	return 0, service.ErrOccupancyNotSettable
+}
+
+// TrackedLots returns no lots
+func (s *unsettableService) TrackedLots() []int {
+	return nil
+}
+
+// TestPostAdminOccupancyReconcile_NotSettable tests that a counter that cannot be reset answers 501
+func TestPostAdminOccupancyReconcile_NotSettable(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler := NewParkingHandler(&unsettableService{ParkingService: new(mocks.ParkingService)})
+	handler.adminToken = "secret"
+	router := gin.New()
+	api.RegisterHandlers(router, handler)
+
+	req := httptest.NewRequest("POST", "/admin/occupancy/reconcile?parkingLot=5", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotImplemented, w.Code)
+	assert.Contains(t, w.Body.String(), CodeNotImplemented)
+}
@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"math"
+
+	"github.com/gin-gonic/gin"
+
+	"parking-lot/internal/logger"
+)
+
+// operationKey is the Gin context key under which entry and exit handlers record what they resolved
+const operationKey = "parking.operation"
+
+// Operations logged by OperationLogMiddleware
+const (
+	operationEntry = "entry"
+	operationExit  = "exit"
+)
+
+// operation is the ticket an entry or exit resolved to
+type operation struct {
+	name       string
+	ticketID   string
+	parkingLot int
+	charge     float32
+}
+
+// recordOperation stores the resolved operation for OperationLogMiddleware
+func recordOperation(c *gin.Context, op operation) {
+	c.Set(operationKey, op)
+}
+
+// OperationLogMiddleware logs a single "Operation completed" line after every successful entry and exit.
+// The line always has the same fields and types, so log-based dashboards can rely on them:
+// operation (string), ticket_id (string), parking_lot (int), charge (float64, 0 for entries)
+// and status (int).
+func (h *ParkingHandler) OperationLogMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		value, ok := c.Get(operationKey)
+		if !ok {
+			return
+		}
+		op := value.(operation)
+		h.log.WithContext(c.Request.Context()).Info("Operation completed",
+			logger.Field{Key: "operation", Value: op.name},
+			logger.Field{Key: "ticket_id", Value: op.ticketID},
+			logger.Field{Key: "parking_lot", Value: op.parkingLot},
+			logger.Field{Key: "charge", Value: math.Round(float64(op.charge)*100) / 100},
+			logger.Field{Key: "status", Value: c.Writer.Status()},
+		)
+	}
+}
@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"parking-lot/internal/mocks"
+	"parking-lot/internal/service"
+	"parking-lot/server/api"
+)
+
+// TestOperationLogMiddleware tests that entries and exits log one completion line with a stable field schema
+func TestOperationLogMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	svc, err := service.NewMemoryParkingLotService(context.Background())
+	require.NoError(t, err)
+	log := mocks.NewLogger()
+	handler := NewParkingHandler(svc)
+	handler.log = log
+
+	router := gin.New()
+	router.Use(handler.OperationLogMiddleware())
+	api.RegisterHandlersWithOptions(router, handler, api.GinServerOptions{ErrorHandler: handler.ErrorHandler})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/entry?plate=OPS-123&parkingLot=7", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	var entry api.EntryResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &entry))
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/exit?ticketId="+entry.TicketId.String(), nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	// Failed requests resolve no ticket and log no completion line
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/entry?plate=OPS-123&parkingLot=0", nil))
+	require.Equal(t, http.StatusBadRequest, w.Code)
+
+	completed := log.Find("Operation completed")
+	require.Len(t, completed, 2)
+	for i, name := range []string{"entry", "exit"} {
+		fields := completed[i].Fields
+		assert.Equal(t, name, fields["operation"])
+		assert.IsType(t, "", fields["ticket_id"])
+		assert.Equal(t, entry.TicketId.String(), fields["ticket_id"])
+		assert.IsType(t, 0, fields["parking_lot"])
+		assert.Equal(t, 7, fields["parking_lot"])
+		assert.IsType(t, float64(0), fields["charge"])
+		assert.Equal(t, http.StatusOK, fields["status"])
+	}
+	assert.Equal(t, float64(0), completed[0].Fields["charge"])
+}
@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"errors"
+	"os"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"parking-lot/internal/logger"
+)
+
+// Page sizes of list endpoints
+const (
+	defaultPageSize    = 10
+	defaultMaxPageSize = 100
+)
+
+// pageLimitHeader reports the page size a list endpoint actually used
+const pageLimitHeader = "X-Page-Limit"
+
+// errInvalidPageLimit is returned for a requested page size below 1
+var errInvalidPageLimit = errors.New("limit must be at least 1")
+
+// loadMaxPageSize reads MAX_PAGE_SIZE, the largest page a list endpoint returns. Unset or invalid
+// values fall back to defaultMaxPageSize; invalid ones are logged.
+func loadMaxPageSize(log logger.Logger) int {
+	raw := os.Getenv("MAX_PAGE_SIZE")
+	if raw == "" {
+		return defaultMaxPageSize
+	}
+	maxPageSize, err := strconv.Atoi(raw)
+	if err != nil || maxPageSize < 1 {
+		log.Warn("Invalid MAX_PAGE_SIZE, using the default",
+			logger.Field{Key: "max_page_size", Value: raw},
+			logger.Field{Key: "default", Value: defaultMaxPageSize},
+		)
+		return defaultMaxPageSize
+	}
+	return maxPageSize
+}
+
+// pageLimit returns the page size to read for a requested limit and reports it in the X-Page-Limit
+// header. An omitted limit uses defaultPageSize and one above MAX_PAGE_SIZE is clamped to it, so
+// a client cannot make a single request read an unbounded number of items.
+func (h *ParkingHandler) pageLimit(c *gin.Context, requested *int) (int, error) {
+	limit := defaultPageSize
+	if requested != nil {
+		limit = *requested
+	}
+	if limit < 1 {
+		return 0, errInvalidPageLimit
+	}
+	if limit > h.maxPageSize {
+		limit = h.maxPageSize
+	}
+	c.Header(pageLimitHeader, strconv.Itoa(limit))
+	return limit, nil
+}
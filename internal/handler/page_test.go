@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"parking-lot/internal/mocks"
+)
+
+// TestLoadMaxPageSize tests that MAX_PAGE_SIZE is read and invalid values fall back to the default
+func TestLoadMaxPageSize(t *testing.T) {
+	tests := []struct {
+		value string
+		want  int
+	}{
+		{"", defaultMaxPageSize},
+		{"25", 25},
+		{"0", defaultMaxPageSize},
+		{"many", defaultMaxPageSize},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			t.Setenv("MAX_PAGE_SIZE", tt.value)
+			assert.Equal(t, tt.want, loadMaxPageSize(mocks.NewLogger()))
+		})
+	}
+}
+
+// TestPageLimit tests that omitted limits use the default page size, larger ones are clamped
+// to MAX_PAGE_SIZE and the effective limit is reported in a header
+func TestPageLimit(t *testing.T) {
+	intPtr := func(i int) *int { return &i }
+	tests := []struct {
+		name      string
+		max       int
+		requested *int
+		want      int
+		wantErr   bool
+	}{
+		{name: "Omitted", max: 100, want: defaultPageSize},
+		{name: "Omitted with a maximum below the default", max: 5, want: 5},
+		{name: "In range", max: 100, requested: intPtr(42), want: 42},
+		{name: "At the maximum", max: 100, requested: intPtr(100), want: 100},
+		{name: "Over the maximum", max: 100, requested: intPtr(1000000), want: 100},
+		{name: "Zero", max: 100, requested: intPtr(0), wantErr: true},
+		{name: "Negative", max: 100, requested: intPtr(-3), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			h := &ParkingHandler{maxPageSize: tt.max}
+
+			limit, err := h.pageLimit(c, tt.requested)
+
+			if tt.wantErr {
+				assert.ErrorIs(t, err, errInvalidPageLimit)
+				assert.Empty(t, w.Header().Get(pageLimitHeader))
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, limit)
+			assert.Equal(t, strconv.Itoa(tt.want), w.Header().Get(pageLimitHeader))
+		})
+	}
+}
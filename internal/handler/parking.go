@@ -1,104 +1,808 @@
 package handler
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
 	"net/http"
+	"os"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
 
+	"parking-lot/internal/emf"
+	"parking-lot/internal/kinesis"
 	"parking-lot/internal/logger"
 	"parking-lot/internal/model"
 	"parking-lot/internal/service"
+	"parking-lot/internal/webhook"
 	"parking-lot/server/api"
 )
 
+// Histogram bucket boundaries for exit charges (dollars) and parked durations (minutes)
+var (
+	chargeBuckets   = []float64{2.5, 5, 10, 20, 40, 80, 160}
+	durationBuckets = []float64{15, 30, 60, 120, 240, 480, 1440}
+)
+
+// tracer creates spans for handled requests; it is a no-op unless telemetry is enabled
+var tracer = otel.Tracer("parking-lot/internal/handler")
+
 // ParkingHandler implements the ServerInterface
 type ParkingHandler struct {
 	service service.ParkingLotServicer
 	log     logger.Logger
+	entries metric.Int64Counter
+	exits   metric.Int64Counter
+	webhook *webhook.Notifier
+	kinesis *kinesis.Producer
+
+	// calculator bills exits and quotes; it is the service unless replaced with SetChargeCalculator
+	calculator ChargeCalculator
+
+	// charges and durations record the distribution of exit charges and parked minutes
+	charges   metric.Float64Histogram
+	durations metric.Float64Histogram
+	// emf writes entry and exit metrics to stdout in CloudWatch Embedded Metric Format; nil when EMF_ENABLED is unset
+	emf *emf.Emitter
+
+	// repeatExitNoContent answers repeated exits with 204 instead of echoing the recorded charge
+	repeatExitNoContent bool
+	// adminToken is the bearer token required by the admin API; empty disables it
+	adminToken string
+	// adminAPIKey guards the /admin route group (ADMIN_API_KEY); empty falls back to adminToken
+	adminAPIKey string
+	// envelope wraps every response in a {data, error, requestId} envelope
+	envelope bool
+	// allowAnonymous issues tickets with a placeholder plate to entries without a plate
+	allowAnonymous bool
+	// maxLot is the highest lot number entries are accepted for (MAX_LOT)
+	maxLot int
+	// maxPageSize is the largest page list endpoints return (MAX_PAGE_SIZE)
+	maxPageSize int
 }
 
 // NewParkingHandler creates a new handler with the given service
 func NewParkingHandler(service service.ParkingLotServicer) *ParkingHandler {
+	log := logger.NewLogger()
+
+	meter := otel.Meter("parking-lot/internal/handler")
+	entries, err := meter.Int64Counter("parking.entries", metric.WithDescription("Number of recorded vehicle entries"))
+	if err != nil {
+		log.Error("Failed to create entries counter", logger.Field{Key: "error", Value: err.Error()})
+	}
+	exits, err := meter.Int64Counter("parking.exits", metric.WithDescription("Number of processed vehicle exits"))
+	if err != nil {
+		log.Error("Failed to create exits counter", logger.Field{Key: "error", Value: err.Error()})
+	}
+	charges, err := meter.Float64Histogram("parking_charge_dollars",
+		metric.WithDescription("Charge per processed vehicle exit in dollars"),
+		metric.WithExplicitBucketBoundaries(chargeBuckets...),
+	)
+	if err != nil {
+		log.Error("Failed to create charge histogram", logger.Field{Key: "error", Value: err.Error()})
+	}
+	durations, err := meter.Float64Histogram("parking_duration_minutes",
+		metric.WithDescription("Parked duration per processed vehicle exit in minutes"),
+		metric.WithExplicitBucketBoundaries(durationBuckets...),
+	)
+	if err != nil {
+		log.Error("Failed to create duration histogram", logger.Field{Key: "error", Value: err.Error()})
+	}
+
 	return &ParkingHandler{
-		service: service,
-		log:     logger.NewLogger(),
+		service:             service,
+		calculator:          service,
+		log:                 log,
+		entries:             entries,
+		exits:               exits,
+		charges:             charges,
+		durations:           durations,
+		webhook:             webhook.NewNotifierFromEnv(log),
+		kinesis:             kinesis.NewProducerFromEnv(log),
+		emf:                 emf.NewEmitterFromEnv(),
+		repeatExitNoContent: os.Getenv("REPEAT_EXIT_NO_CONTENT") == "true",
+		adminToken:          os.Getenv("ADMIN_TOKEN"),
+		adminAPIKey:         os.Getenv("ADMIN_API_KEY"),
+		envelope:            os.Getenv("RESPONSE_ENVELOPE") == "true",
+		allowAnonymous:      os.Getenv("ALLOW_ANONYMOUS") == "true",
+		maxLot:              loadMaxLot(log),
+		maxPageSize:         loadMaxPageSize(log),
 	}
 }
 
+// Closers returns the background side channels that must be flushed before shutdown, i.e. the
+// webhook notifier and the Kinesis producer when they are enabled
+func (h *ParkingHandler) Closers() []io.Closer {
+	var closers []io.Closer
+	if h.webhook != nil {
+		closers = append(closers, h.webhook)
+	}
+	if h.kinesis != nil {
+		closers = append(closers, h.kinesis)
+	}
+	return closers
+}
+
 // PostEntry records a vehicle entry and generates a ticket
 func (h *ParkingHandler) PostEntry(c *gin.Context, params api.PostEntryParams) {
-	ctx := c.Request.Context()
+	ctx, span := tracer.Start(c.Request.Context(), "PostEntry")
+	defer span.End()
+	span.SetAttributes(attribute.Int("parking.lot", params.ParkingLot))
+
+	status, response := h.enter(ctx, params)
+	if entry, ok := response.(api.EntryResponse); ok {
+		recordOperation(c, operation{name: operationEntry, ticketID: entry.TicketId.String(), parkingLot: params.ParkingLot})
+	}
+	h.respond(c, status, response)
+}
+
+// enter records a vehicle entry, returning the status and body to answer with:
+// an EntryResponse on success, an ErrorResponse otherwise
+func (h *ParkingHandler) enter(ctx context.Context, params api.PostEntryParams) (int, any) {
+	spaces := 1
+	if params.Spaces != nil {
+		spaces = *params.Spaces
+	}
+
+	// Cash lots may not capture plates; those entries get a placeholder plate when allowed.
+	// A transponder identifies the vehicle on its own, so its entries may always omit the plate.
+	var plate string
+	if params.Plate != nil {
+		plate = *params.Plate
+	}
+	vehicle := entryVehicle(params)
+	anonymous := plate == "" && (h.allowAnonymous || vehicle.TransponderID != "")
+	if anonymous {
+		plate = anonymousPlate()
+	}
 
 	log := h.log.WithContext(ctx).WithFields(
-		logger.Field{Key: "plate", Value: params.Plate},
+		logger.Plate(plate),
 		logger.Field{Key: "parking_lot", Value: params.ParkingLot},
+		logger.Field{Key: "spaces", Value: spaces},
 	)
 	log.Info("Processing vehicle entry")
 
-	ticketID, _ := h.service.CreateTicket(ctx, params.Plate, params.ParkingLot)
+	if err := checkParkingLot(params.ParkingLot, h.maxLot); err != nil {
+		log.Warn("Invalid parking lot")
+		return http.StatusBadRequest, api.ErrorResponse{
+			Code:    CodeValidationFailed,
+			Message: err.Error(),
+		}
+	}
+
+	if plate == "" {
+		log.Warn("Missing plate")
+		return http.StatusBadRequest, api.ErrorResponse{
+			Code:    CodeValidationFailed,
+			Message: "Query argument plate is required, but not found",
+		}
+	}
+
+	if h.serviceUnavailable() {
+		log.Warn("Storage unavailable, failing fast")
+		return http.StatusServiceUnavailable, unavailableResponse
+	}
+
+	if h.readOnly() {
+		log.Warn("Service is read-only, rejecting entry")
+		return http.StatusServiceUnavailable, readOnlyResponse
+	}
+
+	if spaces < 1 {
+		log.Warn("Invalid number of spaces")
+		return http.StatusBadRequest, api.ErrorResponse{
+			Code:    CodeValidationFailed,
+			Message: "spaces must be at least 1",
+		}
+	}
+
+	if params.ExpectedMinutes != nil && *params.ExpectedMinutes < 1 {
+		log.Warn("Invalid expected stay")
+		return http.StatusBadRequest, api.ErrorResponse{
+			Code:    CodeValidationFailed,
+			Message: "expectedMinutes must be at least 1",
+		}
+	}
+
+	// Stop new entries while the lot is under maintenance; exits keep working
+	if h.maintenanceMode(ctx, log) {
+		log.Warn("Entry rejected during maintenance")
+		return http.StatusServiceUnavailable, api.ErrorResponse{
+			Code:    CodeMaintenance,
+			Message: "maintenance",
+		}
+	}
+
+	// Lots closed overnight stop new entries outside operating hours; exits keep working
+	if hours, ok := h.service.(service.EntryHours); ok && !hours.EntryOpen() {
+		log.Warn("Entry rejected outside operating hours")
+		return http.StatusForbidden, api.ErrorResponse{
+			Code:    CodeLotClosed,
+			Message: "Parking lot is closed for entry",
+		}
+	}
+
+	// Reject a second entry while the vehicle still holds an active ticket in this lot.
+	// A freshly generated anonymous plate has no earlier tickets, so its lookups are skipped.
+	var existing, reentry *model.ParkingTicket
+	var err error
+	if !anonymous {
+		existing, err = h.service.FindActiveTicket(ctx, plate, params.ParkingLot)
+	}
+	if errors.Is(err, service.ErrDynamoDBUnavailable) {
+		log.Warn("Storage unavailable, failing fast")
+		return http.StatusServiceUnavailable, unavailableResponse
+	}
+	if err != nil {
+		// Best effort: a failed lookup should not block entries
+		log.Warn("Failed to check for an active ticket", logger.Field{Key: "error", Value: err.Error()})
+	} else if existing != nil {
+		response := api.ErrorResponse{
+			Code:    CodeActiveTicketExists,
+			Message: "Vehicle already has an active ticket in this parking lot",
+		}
+		if existingID, err := uuid.Parse(existing.TicketID); err == nil {
+			response.TicketId = &existingID
+		}
+		log.Warn("Duplicate entry rejected", logger.Field{Key: "ticket_id", Value: existing.TicketID})
+		return http.StatusConflict, response
+	}
+
+	// A transponder can only be in one lot at a time, since exits look it up
+	if vehicle.TransponderID != "" {
+		existing, err := h.activeTransponder(ctx, vehicle.TransponderID)
+		if errors.Is(err, service.ErrDynamoDBUnavailable) {
+			log.Warn("Storage unavailable, failing fast")
+			return http.StatusServiceUnavailable, unavailableResponse
+		}
+		if err != nil {
+			// Best effort, like the plate check above
+			log.Warn("Failed to check for an active transponder ticket", logger.Field{Key: "error", Value: err.Error()})
+		} else if existing != nil {
+			response := api.ErrorResponse{
+				Code:    CodeActiveTicketExists,
+				Message: "Transponder already has an active ticket",
+			}
+			if existingID, err := uuid.Parse(existing.TicketID); err == nil {
+				response.TicketId = &existingID
+			}
+			log.Warn("Duplicate transponder entry rejected", logger.Field{Key: "ticket_id", Value: existing.TicketID})
+			return http.StatusConflict, response
+		}
+	}
 
-	// Return the ticket ID
+	// A vehicle returning within the grace window resumes its previous ticket
+	if !anonymous {
+		reentry, err = h.service.FindReentryTicket(ctx, plate, params.ParkingLot)
+	}
+	if err != nil {
+		// Best effort: fall back to issuing a new ticket
+		log.Warn("Failed to check for a re-entry ticket", logger.Field{Key: "error", Value: err.Error()})
+		reentry = nil
+	}
+	if reentry != nil {
+		spaces = reentry.Spaces()
+	}
+
+	// Claim the spaces before issuing a ticket
+	if err := h.service.ReserveSpaces(ctx, params.ParkingLot, spaces); err != nil {
+		if errors.Is(err, service.ErrUnknownLot) {
+			log.Warn("Unknown parking lot")
+			return http.StatusBadRequest, api.ErrorResponse{
+				Code:    CodeUnknownLot,
+				Message: "Unknown parking lot",
+			}
+		}
+		if errors.Is(err, service.ErrLotFull) {
+			log.Warn("Parking lot is full")
+			return http.StatusConflict, api.ErrorResponse{
+				Code:    CodeLotFull,
+				Message: "Not enough free spaces in parking lot",
+			}
+		}
+		log.Error("Failed to reserve spaces", logger.Field{Key: "error", Value: err.Error()})
+		return http.StatusInternalServerError, api.ErrorResponse{
+			Code:    CodeInternalError,
+			Message: "Failed to reserve spaces",
+		}
+	}
+
+	var ticketID uuid.UUID
+	var ticket *model.ParkingTicket
+	if reentry != nil {
+		if err := h.service.ReopenTicket(ctx, reentry); err != nil {
+			h.service.ReleaseSpaces(ctx, params.ParkingLot, spaces)
+			if errors.Is(err, service.ErrNoFreeSpot) {
+				log.Warn("No free spot in parking lot")
+				return http.StatusConflict, noFreeSpotResponse
+			}
+			if errors.Is(err, service.ErrTicketConflict) {
+				reentryID, _ := uuid.Parse(reentry.TicketID)
+				log.Warn("Ticket was updated concurrently", logger.Field{Key: "error", Value: err.Error()})
+				return http.StatusConflict, api.ErrorResponse{
+					Code:     CodeTicketConflict,
+					Message:  "Ticket was updated concurrently; retry the request",
+					TicketId: &reentryID,
+				}
+			}
+			log.Error("Failed to reopen ticket", logger.Field{Key: "error", Value: err.Error()})
+			return http.StatusInternalServerError, api.ErrorResponse{
+				Code:    CodeInternalError,
+				Message: "Failed to reopen ticket",
+			}
+		}
+		ticketID, _ = uuid.Parse(reentry.TicketID)
+		ticket = reentry
+		log.Info("Vehicle re-entered within the grace window", logger.Field{Key: "ticket_id", Value: reentry.TicketID})
+	} else {
+		var err error
+		ticketID, ticket, err = h.createTicket(service.WithVehicle(ctx, vehicle), plate, params.ParkingLot, spaces)
+		if errors.Is(err, service.ErrItemTooLarge) {
+			h.service.ReleaseSpaces(ctx, params.ParkingLot, spaces)
+			log.Warn("Ticket too large to store", logger.Field{Key: "error", Value: err.Error()})
+			return http.StatusBadRequest, itemTooLargeResponse(nil)
+		}
+		if errors.Is(err, service.ErrNoFreeSpot) {
+			h.service.ReleaseSpaces(ctx, params.ParkingLot, spaces)
+			log.Warn("No free spot in parking lot")
+			return http.StatusConflict, noFreeSpotResponse
+		}
+		if errors.Is(err, service.ErrDynamoDBUnavailable) {
+			h.service.ReleaseSpaces(ctx, params.ParkingLot, spaces)
+			log.Warn("Storage unavailable, failing fast")
+			return http.StatusServiceUnavailable, unavailableResponse
+		}
+		if err != nil {
+			// The ticket was never stored, so the vehicle must not be let in on it
+			h.service.ReleaseSpaces(ctx, params.ParkingLot, spaces)
+			log.Error("Failed to store ticket", logger.Field{Key: "error", Value: err.Error()})
+			return http.StatusInternalServerError, api.ErrorResponse{
+				Code:    CodeInternalError,
+				Message: "Failed to create ticket",
+			}
+		}
+		if ticket == nil {
+			h.service.ReleaseSpaces(ctx, params.ParkingLot, spaces)
+			log.Warn("Ticket was not created")
+			return http.StatusInternalServerError, api.ErrorResponse{
+				Code:    CodeInternalError,
+				Message: "Failed to create ticket",
+			}
+		}
+	}
+
+	h.webhook.Notify(webhook.Event{
+		Type:       webhook.EventEntry,
+		TicketID:   ticketID.String(),
+		Plate:      plate,
+		ParkingLot: params.ParkingLot,
+		Time:       ticket.EntryTime,
+	})
+	h.kinesis.Put(kinesis.Record{
+		Type:       kinesis.RecordEntry,
+		TicketID:   ticketID.String(),
+		Plate:      plate,
+		ParkingLot: params.ParkingLot,
+		Time:       ticket.EntryTime,
+	})
+
+	// Return the ticket ID along with the rates the kiosk should display
 	response := api.EntryResponse{
-		TicketId: ticketID,
+		TicketId:  ticketID,
+		SessionId: sessionID(ticket),
+		RateInfo:  rateInfo(h.service.Rates()),
+	}
+	if anonymous {
+		response.Plate = &plate
+	}
+	if ticket.SpotNumber > 0 {
+		response.SpotNumber = &ticket.SpotNumber
+	}
+	// Preview the charge of the stay the driver expects; it is not stored on the ticket
+	if estimator, ok := h.service.(service.ChargeEstimator); ok && params.ExpectedMinutes != nil {
+		stay := time.Duration(*params.ExpectedMinutes) * time.Minute
+		estimate := estimator.EstimateCharge(ticket.PricingArm, params.ParkingLot, ticket.EntryTime, stay)
+		response.EstimatedCharge = &estimate
+	}
+
+	if h.entries != nil {
+		h.entries.Add(ctx, 1, metric.WithAttributes(attribute.Int("parking.lot", params.ParkingLot)))
 	}
+	h.emf.Entry(params.ParkingLot, vehicleType(ticket))
 
 	log.Info("Vehicle entry processed successfully",
 		logger.Field{Key: "ticket_id", Value: ticketID.String()},
 	)
-	c.JSON(http.StatusOK, response)
+	return http.StatusOK, response
 }
 
 // PostExit processes a vehicle exit
 func (h *ParkingHandler) PostExit(c *gin.Context, params api.PostExitParams) {
-	ctx := c.Request.Context()
+	ctx, span := tracer.Start(c.Request.Context(), "PostExit")
+	defer span.End()
 
 	log := h.log.WithContext(ctx).WithFields(
 		logger.Field{Key: "ticket_id", Value: params.TicketId},
 	)
 	log.Info("Processing vehicle exit")
 
+	// The all-zero UUID parses fine but is never issued, so do not spend a lookup on it
+	if params.TicketId == uuid.Nil {
+		log.Warn("Missing ticket ID")
+		h.respond(c, http.StatusBadRequest, api.ErrorResponse{
+			Code:    CodeValidationFailed,
+			Message: "ticketId is required",
+		})
+		return
+	}
+
+	if h.serviceUnavailable() {
+		log.Warn("Storage unavailable, failing fast")
+		h.respondUnavailable(c)
+		return
+	}
+
 	ticket, exists := h.service.GetTicket(ctx, params.TicketId.String())
 	if !exists {
 		errorMsg := "Ticket not found"
 		response := api.ErrorResponse{
+			Code:    CodeTicketNotFound,
 			Message: errorMsg,
 		}
 		log.Warn("Ticket not found")
-		c.JSON(http.StatusNotFound, response)
+		h.respond(c, http.StatusNotFound, response)
+		return
+	}
+
+	h.exitTicket(ctx, c, log, ticket, params.TicketId, params.CouponCode)
+}
+
+// exitTicket completes the exit of a looked-up ticket and answers the request. A ticket that
+// already exited is answered with its recorded charge; coupon optionally discounts the charge.
+func (h *ParkingHandler) exitTicket(ctx context.Context, c *gin.Context, log logger.Logger, ticket *model.ParkingTicket, ticketID openapi_types.UUID, coupon *string) {
+	// Exits are idempotent: a retried exit gets the recorded charge instead of a new one
+	if ticket.Status == model.TicketStatusOut {
+		log.Info("Ticket already exited", logger.Field{Key: "charge", Value: ticket.Charge})
+		if h.repeatExitNoContent {
+			c.Status(http.StatusNoContent)
+			return
+		}
+		minutes, charge := h.recordedCharge(ticket)
+		response := api.ExitResponse{
+			Plate:                 ticket.Plate,
+			ParkingLot:            ticket.ParkingLot,
+			ParkedDurationMinutes: minutes,
+			ParkedDurationSeconds: parkedSeconds(ticket),
+			Charge:                charge,
+			AlreadyExited:         true,
+			SessionId:             sessionID(ticket),
+			CouponCode:            couponCode(ticket),
+		}
+		response.Currency, response.ChargeFormatted = h.formatCharge(ticket.ParkingLot, charge)
+		response.Flagged, response.Reason = h.overstay(time.Duration(response.ParkedDurationSeconds * float64(time.Second)))
+		recordOperation(c, operation{name: operationExit, ticketID: ticket.TicketID, parkingLot: ticket.ParkingLot, charge: charge})
+		h.respond(c, http.StatusOK, response)
+		return
+	}
+
+	// Completing an exit writes the ticket, which read-only mode cannot do
+	if h.readOnly() {
+		log.Warn("Service is read-only, rejecting exit")
+		h.respondReadOnly(c)
 		return
 	}
 
 	// Calculate parking duration and charge
-	minutes, charge := h.service.CalculateCharge(ticket.EntryTime)
+	duration, minutes, breakdown := h.itemizedCharge(ticket)
+	exitTime := ticket.EntryTime.Add(duration)
 
 	log.Info("Calculated parking charge",
 		logger.Field{Key: "minutes", Value: minutes},
-		logger.Field{Key: "charge", Value: charge},
+		logger.Field{Key: "charge", Value: breakdown.Total},
 	)
 
-	// Update ticket status and charge
-	ticket.Status = model.TicketStatusOut // Assuming model.TicketStatusOut is defined
+	// Honor a kiosk quote taken within the exit window; stale quotes are recomputed.
+	// The quote is billed as a whole, so it becomes the base charge; it already has the prior charge deducted.
+	if ticket.QuoteTime != nil {
+		if quoted, ok := ticket.QuotedCharge(exitTime, h.service.ExitWindow()); ok {
+			log.Info("Honoring quoted charge", logger.Field{Key: "charge", Value: quoted})
+			breakdown = service.ChargeBreakdown{BaseCharge: quoted, Total: quoted}
+		} else {
+			log.Info("Quote expired, charging recomputed amount", logger.Field{Key: "quoted_charge", Value: ticket.QuoteCharge})
+		}
+	}
+
+	// Discount the charge with a promo code; unknown and expired codes are ignored
+	if coupon != nil && *coupon != "" {
+		discounted := h.applyCoupon(ctx, log, ticket, *coupon, exitTime, breakdown.Total)
+		breakdown.Discount = float32(math.Round(float64(breakdown.Total-discounted)*100) / 100)
+		breakdown.Total = discounted
+	}
+	charge := breakdown.Total
+
+	// Update ticket status and charge. The ticket records the parked duration rounded to the minute;
+	// MINUTES_DISPLAY only shapes the minutes in the response.
+	ticket.Status = model.TicketStatusOut
 	ticket.Charge = charge
+	ticket.DurationMinutes = int(math.Round(duration.Minutes()))
+	ticket.ExitTime = &exitTime
 
 	// Update the ticket in storage
 	if err := h.service.UpdateTicket(ctx, ticket); err != nil {
+		if errors.Is(err, service.ErrDynamoDBUnavailable) {
+			log.Warn("Storage unavailable, failing fast")
+			h.respondUnavailable(c)
+			return
+		}
+		if errors.Is(err, service.ErrItemTooLarge) {
+			log.Warn("Ticket too large to store", logger.Field{Key: "error", Value: err.Error()})
+			h.respondItemTooLarge(c, &ticketID)
+			return
+		}
+		if errors.Is(err, service.ErrTicketConflict) {
+			log.Warn("Ticket was updated concurrently, rejecting exit")
+			h.respondTicketConflict(c, &ticketID)
+			return
+		}
 		errorMsg := "Failed to update ticket"
 		response := api.ErrorResponse{
+			Code:    CodeInternalError,
 			Message: errorMsg,
 		}
 		log.Error("Failed to update ticket", logger.Field{Key: "error", Value: err.Error()})
-		c.JSON(http.StatusInternalServerError, response)
+		h.respond(c, http.StatusInternalServerError, response)
 		return
 	}
 
+	// Free the spaces held by the vehicle, and its numbered spot in lots that assign them
+	h.service.ReleaseSpaces(ctx, ticket.ParkingLot, ticket.Spaces())
+	if releaser, ok := h.service.(service.SpotReleaser); ok {
+		releaser.ReleaseSpot(ctx, ticket)
+	}
+
 	// Create response
 	response := api.ExitResponse{
 		Plate:                 ticket.Plate,
 		ParkingLot:            ticket.ParkingLot,
 		ParkedDurationMinutes: minutes,
+		ParkedDurationSeconds: duration.Seconds(),
 		Charge:                charge,
+		ChargeBreakdown:       breakdownResponse(breakdown),
+		SessionId:             sessionID(ticket),
+		CouponCode:            couponCode(ticket),
 	}
+	response.Currency, response.ChargeFormatted = h.formatCharge(ticket.ParkingLot, charge)
+
+	// Stays over MAX_PARK_DURATION were only charged up to the limit; flag them for enforcement
+	if response.Flagged, response.Reason = h.overstay(duration); response.Flagged != nil {
+		log.Warn("Vehicle exceeded the maximum parking duration",
+			logger.Plate(ticket.Plate),
+			logger.Field{Key: "parking_lot", Value: ticket.ParkingLot},
+			logger.Field{Key: "minutes", Value: minutes},
+		)
+	}
+
+	lotAttr := metric.WithAttributes(attribute.Int("parking.lot", ticket.ParkingLot))
+	if h.exits != nil {
+		h.exits.Add(ctx, 1, lotAttr)
+	}
+	if h.charges != nil {
+		h.charges.Record(ctx, float64(charge), lotAttr)
+	}
+	if h.durations != nil {
+		h.durations.Record(ctx, duration.Minutes(), lotAttr)
+	}
+	h.emf.Exit(ticket.ParkingLot, vehicleType(ticket), charge)
+
+	h.webhook.Notify(webhook.Event{
+		Type:            webhook.EventExit,
+		TicketID:        ticket.TicketID,
+		Plate:           ticket.Plate,
+		ParkingLot:      ticket.ParkingLot,
+		Time:            exitTime,
+		Charge:          &charge,
+		DurationMinutes: &ticket.DurationMinutes,
+	})
+	h.kinesis.Put(kinesis.Record{
+		Type:            kinesis.RecordExit,
+		TicketID:        ticket.TicketID,
+		Plate:           ticket.Plate,
+		ParkingLot:      ticket.ParkingLot,
+		Time:            exitTime,
+		Charge:          &charge,
+		DurationMinutes: &ticket.DurationMinutes,
+	})
 
 	log.Info("Vehicle exit processed successfully")
-	c.JSON(http.StatusOK, response)
+	recordOperation(c, operation{name: operationExit, ticketID: ticket.TicketID, parkingLot: ticket.ParkingLot, charge: charge})
+	h.respond(c, http.StatusOK, response)
+}
+
+// overstay flags a stay longer than the service's maximum parking duration,
+// returning nil values when it is within the limit or there is none
+func (h *ParkingHandler) overstay(duration time.Duration) (*bool, *string) {
+	limiter, ok := h.service.(service.ParkLimit)
+	if !ok {
+		return nil, nil
+	}
+	limit := limiter.MaxParkDuration()
+	if limit <= 0 || duration <= limit {
+		return nil, nil
+	}
+	flagged := true
+	reason := fmt.Sprintf("Parked %d minutes, over the %d minute limit", int(duration.Minutes()), int(limit.Minutes()))
+	return &flagged, &reason
+}
+
+// formatCharge returns the currency of a charge in a lot and the charge formatted for the lot's locale.
+// Lots without a currency use the rate schedule's; nil values are returned when the service has no lot configuration.
+func (h *ParkingHandler) formatCharge(parkingLot int, charge float32) (*string, *string) {
+	configs, ok := h.service.(service.LotConfigs)
+	if !ok {
+		return nil, nil
+	}
+	lot := configs.LotConfig(parkingLot)
+	currency := lot.Currency
+	if currency == "" {
+		currency = h.service.Rates().Currency
+	}
+	locale := lot.Locale
+	if locale == "" {
+		locale = model.DefaultLocale
+	}
+	formatted := model.Money{Amount: charge, Currency: currency}.Format(locale)
+	return &currency, &formatted
+}
+
+// serviceUnavailable reports whether the service is failing fast,
+// e.g. while its DynamoDB circuit breaker is open
+func (h *ParkingHandler) serviceUnavailable() bool {
+	checker, ok := h.service.(interface{ Available() bool })
+	return ok && !checker.Available()
+}
+
+// readOnly reports whether the service has switched to read-only mode after sustained write failures
+func (h *ParkingHandler) readOnly() bool {
+	checker, ok := h.service.(interface{ ReadOnly() bool })
+	return ok && checker.ReadOnly()
+}
+
+// readOnlyResponse is the 503 body of requests that need to write while the service is read-only
+var readOnlyResponse = api.ErrorResponse{Code: CodeReadOnly, Message: "Service is read-only"}
+
+// respondReadOnly answers requests that need to write with 503 while the service is read-only
+func (h *ParkingHandler) respondReadOnly(c *gin.Context) {
+	h.respond(c, http.StatusServiceUnavailable, readOnlyResponse)
+}
+
+// itemTooLargeResponse is the 400 body when a ticket exceeds DynamoDB's item size limit
+func itemTooLargeResponse(ticketID *openapi_types.UUID) api.ErrorResponse {
+	return api.ErrorResponse{
+		Code:     CodeTicketTooLarge,
+		Message:  "Ticket is too large to store; DynamoDB items are limited to 400 KB",
+		TicketId: ticketID,
+	}
+}
+
+// respondItemTooLarge answers with 400 when a ticket exceeds DynamoDB's item size limit,
+// which retrying cannot fix
+func (h *ParkingHandler) respondItemTooLarge(c *gin.Context, ticketID *openapi_types.UUID) {
+	h.respond(c, http.StatusBadRequest, itemTooLargeResponse(ticketID))
+}
+
+// createTicket creates a ticket, also returning why it could not be stored when the service reports it
+func (h *ParkingHandler) createTicket(ctx context.Context, plate string, parkingLot int, spaces int) (uuid.UUID, *model.ParkingTicket, error) {
+	if creator, ok := h.service.(service.TicketCreator); ok {
+		return creator.CreateTicketChecked(ctx, plate, parkingLot, spaces)
+	}
+	ticketID, ticket := h.service.CreateTicket(ctx, plate, parkingLot, spaces)
+	return ticketID, ticket, nil
+}
+
+// entryVehicle returns the optional vehicle description of an entry
+func entryVehicle(params api.PostEntryParams) model.Vehicle {
+	var vehicle model.Vehicle
+	if params.Make != nil {
+		vehicle.Make = strings.TrimSpace(*params.Make)
+	}
+	if params.Model != nil {
+		vehicle.Model = strings.TrimSpace(*params.Model)
+	}
+	if params.Color != nil {
+		vehicle.Color = strings.TrimSpace(*params.Color)
+	}
+	if params.TransponderId != nil {
+		vehicle.TransponderID = strings.TrimSpace(*params.TransponderId)
+	}
+	return vehicle
+}
+
+// anonymousPlate generates the placeholder plate of an anonymous entry, e.g. ANON-3F9A1C2B
+func anonymousPlate() string {
+	return model.AnonymousPlatePrefix + strings.ToUpper(strings.ReplaceAll(uuid.NewString(), "-", "")[:8])
+}
+
+// respondTicketConflict answers with 409 when the ticket was updated by another request since it was read;
+// retrying reads the current ticket
+func (h *ParkingHandler) respondTicketConflict(c *gin.Context, ticketID *openapi_types.UUID) {
+	h.respond(c, http.StatusConflict, api.ErrorResponse{
+		Code:     CodeTicketConflict,
+		Message:  "Ticket was updated concurrently; retry the request",
+		TicketId: ticketID,
+	})
+}
+
+// noFreeSpotResponse is the 409 body of entries when every numbered spot in the lot is taken
+var noFreeSpotResponse = api.ErrorResponse{Code: CodeNoFreeSpot, Message: "No free spot in parking lot"}
+
+// unavailableResponse is the 503 body of requests while storage is failing fast
+var unavailableResponse = api.ErrorResponse{Code: CodeServiceUnavailable, Message: "Service temporarily unavailable"}
+
+// respondUnavailable answers with 503 while storage is failing fast
+func (h *ParkingHandler) respondUnavailable(c *gin.Context) {
+	h.respond(c, http.StatusServiceUnavailable, unavailableResponse)
+}
+
+// recordedCharge returns the parked minutes, shown as MINUTES_DISPLAY selects, and the charge of an
+// exited ticket. The charge is always the one recorded at exit, so a repeated exit never bills again;
+// the minutes are shown for the stay up to the exit time, never up to now.
+func (h *ParkingHandler) recordedCharge(ticket *model.ParkingTicket) (int, float32) {
+	if ticket.ExitTime == nil {
+		return ticket.DurationMinutes, ticket.Charge
+	}
+	if displayer, ok := h.service.(service.MinutesDisplayer); ok {
+		return displayer.DisplayMinutes(ticket.PricingArm, ticket.ParkingLot, ticket.EntryTime, *ticket.ExitTime), ticket.Charge
+	}
+	return int(math.Round(ticket.ExitTime.Sub(ticket.EntryTime).Minutes())), ticket.Charge
+}
+
+// parkedSeconds returns the exact parked duration of an exited ticket,
+// falling back to the recorded minutes for tickets stored without an exit time
+func parkedSeconds(ticket *model.ParkingTicket) float64 {
+	if ticket.ExitTime != nil {
+		return ticket.ExitTime.Sub(ticket.EntryTime).Seconds()
+	}
+	return float64(ticket.DurationMinutes * 60)
+}
+
+// sessionID returns the ticket's session ID, or nil for tickets issued before sessions were introduced
+func sessionID(ticket *model.ParkingTicket) *openapi_types.UUID {
+	id, err := uuid.Parse(ticket.SessionID)
+	if err != nil {
+		return nil
+	}
+	return &id
+}
+
+// vehicleType classifies a ticket's vehicle for metrics by the spaces it occupies:
+// "car" for a single space and "large" for vehicles such as trucks that take more
+func vehicleType(ticket *model.ParkingTicket) string {
+	if ticket.Spaces() > 1 {
+		return "large"
+	}
+	return "car"
+}
+
+// rateInfo converts a rate schedule to its API representation
+func rateInfo(rates model.RateSchedule) *api.RateInfo {
+	info := &api.RateInfo{
+		IncrementMinutes: rates.IncrementMinutes,
+		RatePerIncrement: rates.RatePerIncrement,
+		Currency:         rates.Currency,
+	}
+	if rates.DailyMax > 0 {
+		dailyMax := rates.DailyMax
+		info.DailyMax = &dailyMax
+	}
+	return info
 }
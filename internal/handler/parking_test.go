@@ -1,8 +1,10 @@
 package handler
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -14,9 +16,14 @@ import (
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"go.opentelemetry.io/otel"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
 
+	"parking-lot/internal/emf"
 	"parking-lot/internal/mocks"
 	"parking-lot/internal/model"
+	"parking-lot/internal/service"
 	"parking-lot/server/api"
 )
 
@@ -56,37 +63,7 @@ func setupTestRouter(mockService *mocks.ParkingService) *gin.Engine {
 	gin.SetMode(gin.TestMode)
 	router := gin.New()
 	handler := NewParkingHandler(mockService)
-
-	router.POST("/entry", func(c *gin.Context) {
-		params := api.PostEntryParams{
-			Plate:      c.Query("plate"),
-			ParkingLot: 0, // Will be set from query
-		}
-
-		// Parse the parkingLot parameter
-		if parkingLot := c.Query("parkingLot"); parkingLot != "" {
-			var lot int
-			_, err := fmt.Sscanf(parkingLot, "%d", &lot)
-			if err == nil {
-				params.ParkingLot = lot
-			}
-		}
-
-		handler.PostEntry(c, params)
-	})
-
-	router.POST("/exit", func(c *gin.Context) {
-		params := api.PostExitParams{}
-		ticketIdStr := c.Query("ticketId")
-		if ticketIdStr != "" {
-			ticketId, err := uuid.Parse(ticketIdStr)
-			if err == nil {
-				params.TicketId = ticketId
-			}
-		}
-		handler.PostExit(c, params)
-	})
-
+	api.RegisterHandlersWithOptions(router, handler, api.GinServerOptions{ErrorHandler: handler.ErrorHandler})
 	return router
 }
 
@@ -108,7 +85,11 @@ func TestPostEntry(t *testing.T) {
 	}
 
 	// Setup expectations
-	mockService.On("CreateTicket", mock.Anything, testPlate, testParkingLot).Return(testTicketID, testTicket)
+	mockService.On("FindActiveTicket", mock.Anything, testPlate, testParkingLot).Return(nil, nil)
+	mockService.On("FindReentryTicket", mock.Anything, testPlate, testParkingLot).Return(nil, nil)
+	mockService.On("ReserveSpaces", mock.Anything, testParkingLot, 1).Return(nil)
+	mockService.On("CreateTicket", mock.Anything, testPlate, testParkingLot, 1).Return(testTicketID, testTicket)
+	mockService.On("Rates").Return(model.RateSchedule{IncrementMinutes: 15, RatePerIncrement: 2.5, Currency: "USD"})
 
 	// Create test request
 	req := httptest.NewRequest("POST", "/entry?plate="+testPlate+"&parkingLot="+strconv.Itoa(testParkingLot), nil)
@@ -132,6 +113,376 @@ func TestPostEntry(t *testing.T) {
 	mockService.AssertExpectations(t)
 }
 
+// TestPostEntry_RateInfo tests that the entry response carries the configured rate schedule
+func TestPostEntry_RateInfo(t *testing.T) {
+	mockService := new(mocks.ParkingService)
+	router := setupTestRouter(mockService)
+
+	testTicketID := uuid.New()
+	rates := model.RateSchedule{IncrementMinutes: 30, RatePerIncrement: 4, Currency: "EUR", DailyMax: 40}
+	mockService.On("FindActiveTicket", mock.Anything, "RATE-1", 7).Return(nil, nil).Once()
+	mockService.On("FindReentryTicket", mock.Anything, "RATE-1", 7).Return(nil, nil).Once()
+	mockService.On("ReserveSpaces", mock.Anything, 7, 1).Return(nil).Once()
+	mockService.On("CreateTicket", mock.Anything, "RATE-1", 7, 1).Return(testTicketID, &model.ParkingTicket{}).Once()
+	mockService.On("Rates").Return(rates).Once()
+
+	req := httptest.NewRequest("POST", "/entry?plate=RATE-1&parkingLot=7", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response api.EntryResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	if assert.NotNil(t, response.RateInfo) {
+		assert.Equal(t, 30, response.RateInfo.IncrementMinutes)
+		assert.Equal(t, float32(4), response.RateInfo.RatePerIncrement)
+		assert.Equal(t, "EUR", response.RateInfo.Currency)
+		if assert.NotNil(t, response.RateInfo.DailyMax) {
+			assert.Equal(t, float32(40), *response.RateInfo.DailyMax)
+		}
+	}
+	mockService.AssertExpectations(t)
+}
+
+// TestPostEntry_MultiSpace tests entry of vehicles occupying several spaces
+func TestPostEntry_MultiSpace(t *testing.T) {
+	testPlate := "TRUCK-1"
+	testParkingLot := 456
+
+	t.Run("Lot full", func(t *testing.T) {
+		mockService := new(mocks.ParkingService)
+		router := setupTestRouter(mockService)
+
+		mockService.On("FindActiveTicket", mock.Anything, testPlate, testParkingLot).Return(nil, nil).Once()
+		mockService.On("FindReentryTicket", mock.Anything, testPlate, testParkingLot).Return(nil, nil).Once()
+		mockService.On("ReserveSpaces", mock.Anything, testParkingLot, 2).Return(service.ErrLotFull).Once()
+
+		req := httptest.NewRequest("POST", "/entry?plate="+testPlate+"&parkingLot="+strconv.Itoa(testParkingLot)+"&spaces=2", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		mockService.AssertExpectations(t)
+		mockService.AssertNotCalled(t, "CreateTicket", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Spaces available", func(t *testing.T) {
+		mockService := new(mocks.ParkingService)
+		router := setupTestRouter(mockService)
+
+		testTicketID := uuid.New()
+		testTicket := &model.ParkingTicket{TicketID: testTicketID.String(), SpacesUsed: 2}
+		mockService.On("FindActiveTicket", mock.Anything, testPlate, testParkingLot).Return(nil, nil).Once()
+		mockService.On("FindReentryTicket", mock.Anything, testPlate, testParkingLot).Return(nil, nil).Once()
+		mockService.On("ReserveSpaces", mock.Anything, testParkingLot, 2).Return(nil).Once()
+		mockService.On("CreateTicket", mock.Anything, testPlate, testParkingLot, 2).Return(testTicketID, testTicket).Once()
+		mockService.On("Rates").Return(model.RateSchedule{IncrementMinutes: 15, RatePerIncrement: 2.5, Currency: "USD"}).Once()
+
+		req := httptest.NewRequest("POST", "/entry?plate="+testPlate+"&parkingLot="+strconv.Itoa(testParkingLot)+"&spaces=2", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("Invalid spaces", func(t *testing.T) {
+		mockService := new(mocks.ParkingService)
+		router := setupTestRouter(mockService)
+
+		req := httptest.NewRequest("POST", "/entry?plate="+testPlate+"&parkingLot="+strconv.Itoa(testParkingLot)+"&spaces=0", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockService.AssertExpectations(t)
+	})
+}
+
+// TestPostEntry_DuplicateActiveTicket tests that a vehicle cannot hold two active tickets in a lot
+func TestPostEntry_DuplicateActiveTicket(t *testing.T) {
+	testPlate := "DUP-123"
+	existingID := uuid.New()
+
+	t.Run("No duplicate", func(t *testing.T) {
+		mockService := new(mocks.ParkingService)
+		router := setupTestRouter(mockService)
+
+		newID := uuid.New()
+		mockService.On("FindActiveTicket", mock.Anything, testPlate, 1).Return(nil, nil).Once()
+		mockService.On("FindReentryTicket", mock.Anything, testPlate, 1).Return(nil, nil).Once()
+		mockService.On("ReserveSpaces", mock.Anything, 1, 1).Return(nil).Once()
+		mockService.On("CreateTicket", mock.Anything, testPlate, 1, 1).Return(newID, &model.ParkingTicket{}).Once()
+		mockService.On("Rates").Return(model.RateSchedule{}).Once()
+
+		req := httptest.NewRequest("POST", "/entry?plate="+testPlate+"&parkingLot=1", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("Same lot duplicate", func(t *testing.T) {
+		mockService := new(mocks.ParkingService)
+		router := setupTestRouter(mockService)
+
+		existing := &model.ParkingTicket{TicketID: existingID.String(), Plate: testPlate, ParkingLot: 1, Status: model.TicketStatusIn}
+		mockService.On("FindActiveTicket", mock.Anything, testPlate, 1).Return(existing, nil).Once()
+
+		req := httptest.NewRequest("POST", "/entry?plate="+testPlate+"&parkingLot=1", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+
+		var response api.ErrorResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		if assert.NotNil(t, response.TicketId) {
+			assert.Equal(t, existingID, *response.TicketId)
+		}
+		mockService.AssertExpectations(t)
+		mockService.AssertNotCalled(t, "CreateTicket", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Different lot allowed", func(t *testing.T) {
+		mockService := new(mocks.ParkingService)
+		router := setupTestRouter(mockService)
+
+		newID := uuid.New()
+		mockService.On("FindActiveTicket", mock.Anything, testPlate, 2).Return(nil, nil).Once()
+		mockService.On("FindReentryTicket", mock.Anything, testPlate, 2).Return(nil, nil).Once()
+		mockService.On("ReserveSpaces", mock.Anything, 2, 1).Return(nil).Once()
+		mockService.On("CreateTicket", mock.Anything, testPlate, 2, 1).Return(newID, &model.ParkingTicket{}).Once()
+		mockService.On("Rates").Return(model.RateSchedule{}).Once()
+
+		req := httptest.NewRequest("POST", "/entry?plate="+testPlate+"&parkingLot=2", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockService.AssertExpectations(t)
+	})
+}
+
+// TestPostEntry_UnknownLot tests that entries to unknown lots are rejected in strict mode
+func TestPostEntry_UnknownLot(t *testing.T) {
+	mockService := new(mocks.ParkingService)
+	router := setupTestRouter(mockService)
+
+	mockService.On("FindActiveTicket", mock.Anything, "ABC-123", 383).Return(nil, nil).Once()
+	mockService.On("FindReentryTicket", mock.Anything, "ABC-123", 383).Return(nil, nil).Once()
+	mockService.On("ReserveSpaces", mock.Anything, 383, 1).Return(service.ErrUnknownLot).Once()
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/entry?plate=ABC-123&parkingLot=383", nil))
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.JSONEq(t, `{"code":"UNKNOWN_LOT","message":"Unknown parking lot"}`, w.Body.String())
+	mockService.AssertExpectations(t)
+	mockService.AssertNotCalled(t, "CreateTicket", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestPostEntry_TicketNotCreated tests that entry fails and frees the spaces when no ticket was created
+func TestPostEntry_TicketNotCreated(t *testing.T) {
+	mockService := new(mocks.ParkingService)
+	router := setupTestRouter(mockService)
+
+	mockService.On("FindActiveTicket", mock.Anything, "ABC-123", 1).Return(nil, nil).Once()
+	mockService.On("FindReentryTicket", mock.Anything, "ABC-123", 1).Return(nil, nil).Once()
+	mockService.On("ReserveSpaces", mock.Anything, 1, 1).Return(nil).Once()
+	mockService.On("CreateTicket", mock.Anything, "ABC-123", 1, 1).Return(uuid.Nil, nil).Once()
+	mockService.On("ReleaseSpaces", mock.Anything, 1, 1).Once()
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/entry?plate=ABC-123&parkingLot=1", nil))
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+// TestPostEntry_GraceReentry tests that a vehicle returning within the grace window reuses its ticket
+func TestPostEntry_GraceReentry(t *testing.T) {
+	testPlate := "GRACE-1"
+
+	t.Run("Inside window", func(t *testing.T) {
+		mockService := new(mocks.ParkingService)
+		router := setupTestRouter(mockService)
+
+		originalID := uuid.New()
+		exitTime := time.Now().Add(-5 * time.Minute)
+		exited := &model.ParkingTicket{
+			TicketID: originalID.String(), Plate: testPlate, ParkingLot: 1,
+			EntryTime: time.Now().Add(-time.Hour), Status: model.TicketStatusOut, SpacesUsed: 2, ExitTime: &exitTime,
+		}
+		mockService.On("FindActiveTicket", mock.Anything, testPlate, 1).Return(nil, nil).Once()
+		mockService.On("FindReentryTicket", mock.Anything, testPlate, 1).Return(exited, nil).Once()
+		mockService.On("ReserveSpaces", mock.Anything, 1, 2).Return(nil).Once()
+		mockService.On("ReopenTicket", mock.Anything, exited).Return(nil).Once()
+		mockService.On("Rates").Return(model.RateSchedule{}).Once()
+
+		req := httptest.NewRequest("POST", "/entry?plate="+testPlate+"&parkingLot=1", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response api.EntryResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, originalID, response.TicketId)
+		mockService.AssertNotCalled(t, "CreateTicket", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("Outside window", func(t *testing.T) {
+		mockService := new(mocks.ParkingService)
+		router := setupTestRouter(mockService)
+
+		newID := uuid.New()
+		mockService.On("FindActiveTicket", mock.Anything, testPlate, 1).Return(nil, nil).Once()
+		mockService.On("FindReentryTicket", mock.Anything, testPlate, 1).Return(nil, nil).Once()
+		mockService.On("ReserveSpaces", mock.Anything, 1, 1).Return(nil).Once()
+		mockService.On("CreateTicket", mock.Anything, testPlate, 1, 1).Return(newID, &model.ParkingTicket{}).Once()
+		mockService.On("Rates").Return(model.RateSchedule{}).Once()
+
+		req := httptest.NewRequest("POST", "/entry?plate="+testPlate+"&parkingLot=1", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response api.EntryResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, newID, response.TicketId)
+		mockService.AssertNotCalled(t, "ReopenTicket", mock.Anything, mock.Anything)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("Concurrent update", func(t *testing.T) {
+		mockService := new(mocks.ParkingService)
+		router := setupTestRouter(mockService)
+
+		originalID := uuid.New()
+		exitTime := time.Now().Add(-5 * time.Minute)
+		exited := &model.ParkingTicket{
+			TicketID: originalID.String(), Plate: testPlate, ParkingLot: 1,
+			EntryTime: time.Now().Add(-time.Hour), Status: model.TicketStatusOut, SpacesUsed: 1, ExitTime: &exitTime,
+		}
+		mockService.On("FindActiveTicket", mock.Anything, testPlate, 1).Return(nil, nil).Once()
+		mockService.On("FindReentryTicket", mock.Anything, testPlate, 1).Return(exited, nil).Once()
+		mockService.On("ReserveSpaces", mock.Anything, 1, 1).Return(nil).Once()
+		mockService.On("ReopenTicket", mock.Anything, exited).Return(fmt.Errorf("failed to reopen ticket: %w", service.ErrTicketConflict)).Once()
+		mockService.On("ReleaseSpaces", mock.Anything, 1, 1).Once()
+
+		req := httptest.NewRequest("POST", "/entry?plate="+testPlate+"&parkingLot=1", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		var response api.ErrorResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, CodeTicketConflict, response.Code)
+		assert.Equal(t, &originalID, response.TicketId)
+		mockService.AssertExpectations(t)
+	})
+}
+
+// tooLargeService fails every new ticket with ErrItemTooLarge, as DynamoDB does for items over 400 KB
+type tooLargeService struct {
+	*mocks.ParkingService
+}
+
+// CreateTicketChecked reports the ticket as too large to store
+func (tooLargeService) CreateTicketChecked(ctx context.Context, plate string, parkingLot int, spaces int) (uuid.UUID, *model.ParkingTicket, error) {
+	return uuid.Nil, nil, fmt.Errorf("%w: ValidationException: Item size has exceeded the maximum allowed size", service.ErrItemTooLarge)
+}
+
+// storeFailingService returns every new ticket along with the error that kept it from being stored
+type storeFailingService struct {
+	*mocks.ParkingService
+	err error
+}
+
+// CreateTicketChecked returns an unsaved ticket and the store error
+func (s storeFailingService) CreateTicketChecked(ctx context.Context, plate string, parkingLot int, spaces int) (uuid.UUID, *model.ParkingTicket, error) {
+	ticketID := uuid.New()
+	return ticketID, &model.ParkingTicket{TicketID: ticketID.String(), Plate: plate, ParkingLot: parkingLot}, s.err
+}
+
+// TestPostEntry_StoreFailure tests that an entry whose ticket was not stored fails and frees its spaces
+func TestPostEntry_StoreFailure(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tests := []struct {
+		name         string
+		err          error
+		expectedCode int
+	}{
+		{name: "Store error", err: errors.New("failed to store ticket in DynamoDB: throttled"), expectedCode: http.StatusInternalServerError},
+		{name: "Storage unavailable", err: fmt.Errorf("failed to store ticket in DynamoDB: %w", service.ErrDynamoDBUnavailable), expectedCode: http.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(mocks.ParkingService)
+			mockService.On("FindActiveTicket", mock.Anything, "ABC-123", 1).Return(nil, nil).Once()
+			mockService.On("FindReentryTicket", mock.Anything, "ABC-123", 1).Return(nil, nil).Once()
+			mockService.On("ReserveSpaces", mock.Anything, 1, 1).Return(nil).Once()
+			mockService.On("ReleaseSpaces", mock.Anything, 1, 1).Once()
+			router := gin.New()
+			api.RegisterHandlers(router, NewParkingHandler(storeFailingService{ParkingService: mockService, err: tt.err}))
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest("POST", "/entry?plate=ABC-123&parkingLot=1", nil))
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			assert.NotContains(t, w.Body.String(), "ticketId")
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+// TestItemTooLarge tests that tickets over DynamoDB's item size limit are rejected with 400 instead of 500
+func TestItemTooLarge(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("Entry", func(t *testing.T) {
+		mockService := new(mocks.ParkingService)
+		mockService.On("FindActiveTicket", mock.Anything, "LONG-1", 1).Return(nil, nil).Once()
+		mockService.On("FindReentryTicket", mock.Anything, "LONG-1", 1).Return(nil, nil).Once()
+		mockService.On("ReserveSpaces", mock.Anything, 1, 1).Return(nil).Once()
+		mockService.On("ReleaseSpaces", mock.Anything, 1, 1).Once()
+		router := gin.New()
+		api.RegisterHandlers(router, NewParkingHandler(tooLargeService{mockService}))
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("POST", "/entry?plate=LONG-1&parkingLot=1", nil))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.JSONEq(t, `{"code":"TICKET_TOO_LARGE","message":"Ticket is too large to store; DynamoDB items are limited to 400 KB"}`, w.Body.String())
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("Exit", func(t *testing.T) {
+		mockService := new(mocks.ParkingService)
+		router := setupTestRouter(mockService)
+		ticketID := uuid.New()
+		entryTime := time.Now().Add(-time.Hour)
+		ticket := &model.ParkingTicket{TicketID: ticketID.String(), Plate: "LONG-1", ParkingLot: 1, EntryTime: entryTime}
+		mockService.On("GetTicket", mock.Anything, ticketID.String()).Return(ticket, true).Once()
+		mockService.On("CalculateChargeDetailed", 1, entryTime).Return(time.Hour, 60, float32(10)).Once()
+		mockService.On("UpdateTicket", mock.Anything, ticket).Return(fmt.Errorf("failed to update ticket in DynamoDB: %w", service.ErrItemTooLarge)).Once()
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("POST", "/exit?ticketId="+ticketID.String(), nil))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		var response api.ErrorResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "Ticket is too large to store; DynamoDB items are limited to 400 KB", response.Message)
+		assert.Equal(t, ticketID, *response.TicketId)
+		mockService.AssertNotCalled(t, "ReleaseSpaces", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
 // TestPostExit tests the exit handler functionality
 func TestPostExit(t *testing.T) {
 	// Setup mock service
@@ -155,8 +506,9 @@ func TestPostExit(t *testing.T) {
 	t.Run("Successful exit", func(t *testing.T) {
 		// Setup expectations for successful exit
 		mockService.On("GetTicket", mock.Anything, testTicketID.String()).Return(testTicket, true).Once()
-		mockService.On("CalculateCharge", testEntryTime).Return(45, float32(5.0)).Once()
-		mockService.On("RemoveTicket", mock.Anything, testTicketID.String()).Once()
+		mockService.On("CalculateChargeDetailed", testParkingLot, testEntryTime).Return(45*time.Minute+30*time.Second, 45, float32(5.0)).Once()
+		mockService.On("UpdateTicket", mock.Anything, testTicket).Return(nil).Once()
+		mockService.On("ReleaseSpaces", mock.Anything, testParkingLot, 1).Once()
 
 		// Create test request
 		req := httptest.NewRequest("POST", "/exit?ticketId="+testTicketID.String(), nil)
@@ -177,12 +529,152 @@ func TestPostExit(t *testing.T) {
 		assert.Equal(t, testPlate, response.Plate)
 		assert.Equal(t, testParkingLot, response.ParkingLot)
 		assert.Equal(t, 45, response.ParkedDurationMinutes)
+		assert.Equal(t, 2730.0, response.ParkedDurationSeconds)
 		assert.Equal(t, float32(5.0), response.Charge)
+		assert.False(t, response.AlreadyExited)
 
 		// Verify mock expectations
 		mockService.AssertExpectations(t)
 	})
 
+	// Test case: Repeated exit echoes the recorded charge
+	t.Run("Repeated exit", func(t *testing.T) {
+		mockService.ExpectedCalls = nil
+		mockService.Calls = nil
+
+		repeatTicket := &model.ParkingTicket{
+			TicketID:   testTicketID.String(),
+			Plate:      testPlate,
+			ParkingLot: testParkingLot,
+			EntryTime:  testEntryTime,
+			Status:     model.TicketStatusIn,
+		}
+		mockService.On("GetTicket", mock.Anything, testTicketID.String()).Return(repeatTicket, true).Twice()
+		mockService.On("CalculateChargeDetailed", testParkingLot, testEntryTime).Return(45*time.Minute, 45, float32(7.5)).Once()
+		mockService.On("UpdateTicket", mock.Anything, repeatTicket).Return(nil).Once()
+		mockService.On("ReleaseSpaces", mock.Anything, testParkingLot, 1).Once()
+
+		var responses []api.ExitResponse
+		for i := 0; i < 2; i++ {
+			req := httptest.NewRequest("POST", "/exit?ticketId="+testTicketID.String(), nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			assert.Equal(t, http.StatusOK, w.Code)
+
+			var response api.ExitResponse
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			responses = append(responses, response)
+		}
+
+		// Only the flag tells the repeat apart from the first exit, which also itemizes the charge
+		assert.False(t, responses[0].AlreadyExited)
+		assert.True(t, responses[1].AlreadyExited)
+		assert.NotNil(t, responses[0].ChargeBreakdown)
+		assert.Nil(t, responses[1].ChargeBreakdown)
+		responses[1].AlreadyExited = false
+		responses[0].ChargeBreakdown = nil
+		assert.Equal(t, responses[0], responses[1])
+		assert.Equal(t, float32(7.5), responses[1].Charge)
+		assert.Equal(t, 45, responses[1].ParkedDurationMinutes)
+		assert.Equal(t, 2700.0, responses[1].ParkedDurationSeconds)
+		mockService.AssertExpectations(t)
+		mockService.AssertNumberOfCalls(t, "CalculateChargeDetailed", 1)
+		mockService.AssertNotCalled(t, "CalculateChargeBetween", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	// Test case: The ticket records the real duration while the response shows the displayed minutes
+	t.Run("Displayed minutes", func(t *testing.T) {
+		mockService.ExpectedCalls = nil
+		mockService.Calls = nil
+
+		parked := &model.ParkingTicket{
+			TicketID:   testTicketID.String(),
+			Plate:      testPlate,
+			ParkingLot: testParkingLot,
+			EntryTime:  testEntryTime,
+			Status:     model.TicketStatusIn,
+		}
+		stay := 30*time.Minute + 36*time.Second
+		mockService.On("GetTicket", mock.Anything, testTicketID.String()).Return(parked, true).Once()
+		// MINUTES_DISPLAY=billedIncrements shows the 30.6 minutes as the 45 minutes billed
+		mockService.On("CalculateChargeDetailed", testParkingLot, testEntryTime).Return(stay, 45, float32(7.5)).Once()
+		mockService.On("UpdateTicket", mock.Anything, mock.MatchedBy(func(ticket *model.ParkingTicket) bool {
+			return ticket.DurationMinutes == 31
+		})).Return(nil).Once()
+		mockService.On("ReleaseSpaces", mock.Anything, testParkingLot, 1).Once()
+
+		req := httptest.NewRequest("POST", "/exit?ticketId="+testTicketID.String(), nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response api.ExitResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, 45, response.ParkedDurationMinutes)
+		assert.Equal(t, 31, parked.DurationMinutes)
+		mockService.AssertExpectations(t)
+	})
+
+	// Test case: Receipts for a ticket stored without a charge are never billed again
+	t.Run("Repeated exit without recorded charge", func(t *testing.T) {
+		mockService.ExpectedCalls = nil
+		mockService.Calls = nil
+
+		exitTime := testEntryTime.Add(45 * time.Minute)
+		exited := &model.ParkingTicket{
+			TicketID:   testTicketID.String(),
+			Plate:      testPlate,
+			ParkingLot: testParkingLot,
+			EntryTime:  testEntryTime,
+			Status:     model.TicketStatusOut,
+			ExitTime:   &exitTime,
+		}
+		mockService.On("GetTicket", mock.Anything, testTicketID.String()).Return(exited, true).Times(3)
+
+		var bodies []string
+		for i := 0; i < 3; i++ {
+			req := httptest.NewRequest("POST", "/exit?ticketId="+testTicketID.String(), nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			assert.Equal(t, http.StatusOK, w.Code)
+			bodies = append(bodies, w.Body.String())
+		}
+
+		assert.Equal(t, bodies[0], bodies[1])
+		assert.Equal(t, bodies[0], bodies[2])
+		var response api.ExitResponse
+		assert.NoError(t, json.Unmarshal([]byte(bodies[0]), &response))
+		assert.Equal(t, float32(0), response.Charge)
+		assert.Equal(t, 45, response.ParkedDurationMinutes)
+		assert.True(t, response.AlreadyExited)
+		mockService.AssertExpectations(t)
+		mockService.AssertNotCalled(t, "CalculateChargeDetailed", mock.Anything, mock.Anything)
+		mockService.AssertNotCalled(t, "CalculateChargeBetween", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	// Test case: Repeated exit with no content configured
+	t.Run("Repeated exit no content", func(t *testing.T) {
+		mockService.ExpectedCalls = nil
+		mockService.Calls = nil
+
+		noContentRouter := gin.New()
+		handler := NewParkingHandler(mockService)
+		handler.repeatExitNoContent = true
+		noContentRouter.POST("/exit", func(c *gin.Context) {
+			handler.PostExit(c, api.PostExitParams{TicketId: testTicketID})
+		})
+
+		exited := &model.ParkingTicket{TicketID: testTicketID.String(), Status: model.TicketStatusOut, Charge: 5}
+		mockService.On("GetTicket", mock.Anything, testTicketID.String()).Return(exited, true).Once()
+
+		req := httptest.NewRequest("POST", "/exit?ticketId="+testTicketID.String(), nil)
+		w := httptest.NewRecorder()
+		noContentRouter.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		mockService.AssertNotCalled(t, "CalculateChargeDetailed", mock.Anything, mock.Anything)
+	})
+
 	// Test case: Ticket not found
 	t.Run("Ticket not found", func(t *testing.T) {
 		// Reset mock
@@ -213,4 +705,436 @@ func TestPostExit(t *testing.T) {
 		// Verify mock expectations
 		mockService.AssertExpectations(t)
 	})
+
+	// Test case: Nil UUID
+	t.Run("Nil ticket ID", func(t *testing.T) {
+		// Reset mock
+		mockService.ExpectedCalls = nil
+		mockService.Calls = nil
+
+		// Create test request
+		req := httptest.NewRequest("POST", "/exit?ticketId="+uuid.Nil.String(), nil)
+		w := httptest.NewRecorder()
+
+		// Perform the request
+		router.ServeHTTP(w, req)
+
+		// The nil UUID is rejected without a lookup
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.JSONEq(t, `{"code":"VALIDATION_FAILED","message":"ticketId is required"}`, w.Body.String())
+		mockService.AssertNotCalled(t, "GetTicket", mock.Anything, mock.Anything)
+	})
+
+	// Test case: Concurrent update
+	t.Run("Concurrent update", func(t *testing.T) {
+		// Reset mock
+		mockService.ExpectedCalls = nil
+		mockService.Calls = nil
+
+		// Another request updated the ticket after it was read, so the versioned update is rejected
+		ticket := &model.ParkingTicket{TicketID: testTicketID.String(), Plate: testPlate, ParkingLot: testParkingLot, EntryTime: testEntryTime}
+		mockService.On("GetTicket", mock.Anything, testTicketID.String()).Return(ticket, true).Once()
+		mockService.On("CalculateChargeDetailed", testParkingLot, testEntryTime).Return(45*time.Minute, 45, float32(5.0)).Once()
+		mockService.On("UpdateTicket", mock.Anything, ticket).Return(fmt.Errorf("failed to update ticket: %w", service.ErrTicketConflict)).Once()
+
+		// Create test request
+		req := httptest.NewRequest("POST", "/exit?ticketId="+testTicketID.String(), nil)
+		w := httptest.NewRecorder()
+
+		// Perform the request
+		router.ServeHTTP(w, req)
+
+		// The exit is rejected before any spaces are released
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.JSONEq(t, `{"code":"TICKET_CONFLICT","message":"Ticket was updated concurrently; retry the request","ticketId":"`+testTicketID.String()+`"}`, w.Body.String())
+		mockService.AssertExpectations(t)
+		mockService.AssertNotCalled(t, "ReleaseSpaces", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+// unavailableService reports its storage as unavailable, as when the circuit breaker is open
+type unavailableService struct {
+	*mocks.ParkingService
+}
+
+// Available reports the storage as unavailable
+func (unavailableService) Available() bool { return false }
+
+// TestServiceUnavailable tests that requests fail fast with 503 while storage is unavailable
+func TestServiceUnavailable(t *testing.T) {
+	t.Run("Breaker open", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+		mockService := new(mocks.ParkingService)
+		handler := NewParkingHandler(unavailableService{mockService})
+		router := gin.New()
+		router.POST("/entry", func(c *gin.Context) {
+			plate := "ABC-123"
+			handler.PostEntry(c, api.PostEntryParams{Plate: &plate, ParkingLot: 1})
+		})
+		router.POST("/exit", func(c *gin.Context) {
+			handler.PostExit(c, api.PostExitParams{TicketId: uuid.New()})
+		})
+
+		for _, path := range []string{"/entry", "/exit"} {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest("POST", path, nil))
+			assert.Equal(t, http.StatusServiceUnavailable, w.Code, path)
+		}
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("Breaker opens during entry", func(t *testing.T) {
+		mockService := new(mocks.ParkingService)
+		router := setupTestRouter(mockService)
+
+		mockService.On("FindActiveTicket", mock.Anything, "ABC-123", 1).Return(nil, fmt.Errorf("%w: circuit breaker is open", service.ErrDynamoDBUnavailable)).Once()
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("POST", "/entry?plate=ABC-123&parkingLot=1", nil))
+
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+		mockService.AssertExpectations(t)
+		mockService.AssertNotCalled(t, "ReserveSpaces", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+// hoursService restricts entries to operating hours on top of the mock service
+type hoursService struct {
+	*mocks.ParkingService
+	open bool
+}
+
+// EntryOpen reports whether the lot is inside its operating hours
+func (h hoursService) EntryOpen() bool { return h.open }
+
+// TestPostEntry_OperatingHours tests that entries are refused outside operating hours while exits keep working
+func TestPostEntry_OperatingHours(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("Entry outside hours", func(t *testing.T) {
+		mockService := new(mocks.ParkingService)
+		router := gin.New()
+		api.RegisterHandlers(router, NewParkingHandler(hoursService{ParkingService: mockService}))
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("POST", "/entry?plate=ABC-123&parkingLot=1", nil))
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.JSONEq(t, `{"code":"LOT_CLOSED","message":"Parking lot is closed for entry"}`, w.Body.String())
+		mockService.AssertNotCalled(t, "ReserveSpaces", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Entry inside hours", func(t *testing.T) {
+		mockService := new(mocks.ParkingService)
+		router := gin.New()
+		api.RegisterHandlers(router, NewParkingHandler(hoursService{ParkingService: mockService, open: true}))
+
+		ticketID := uuid.New()
+		ticket := &model.ParkingTicket{TicketID: ticketID.String(), Plate: "ABC-123", ParkingLot: 1, Status: model.TicketStatusIn}
+		mockService.On("FindActiveTicket", mock.Anything, "ABC-123", 1).Return(nil, nil).Once()
+		mockService.On("FindReentryTicket", mock.Anything, "ABC-123", 1).Return(nil, nil).Once()
+		mockService.On("ReserveSpaces", mock.Anything, 1, 1).Return(nil).Once()
+		mockService.On("CreateTicket", mock.Anything, "ABC-123", 1, 1).Return(ticketID, ticket).Once()
+		mockService.On("Rates").Return(model.RateSchedule{}).Maybe()
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("POST", "/entry?plate=ABC-123&parkingLot=1", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("Exit outside hours", func(t *testing.T) {
+		mockService := new(mocks.ParkingService)
+		router := gin.New()
+		api.RegisterHandlers(router, NewParkingHandler(hoursService{ParkingService: mockService}))
+
+		ticketID := uuid.New()
+		entryTime := time.Now().Add(-time.Hour)
+		ticket := &model.ParkingTicket{TicketID: ticketID.String(), Plate: "ABC-123", ParkingLot: 1, EntryTime: entryTime, Status: model.TicketStatusIn}
+		mockService.On("GetTicket", mock.Anything, ticketID.String()).Return(ticket, true).Once()
+		mockService.On("CalculateChargeDetailed", 1, entryTime).Return(time.Hour, 60, float32(10)).Once()
+		mockService.On("UpdateTicket", mock.Anything, mock.Anything).Return(nil).Once()
+		mockService.On("ReleaseSpaces", mock.Anything, 1, 1).Once()
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("POST", "/exit?ticketId="+ticketID.String(), nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockService.AssertExpectations(t)
+	})
+}
+
+// TestPostExit_Histograms tests that exits are recorded in the charge and duration histograms
+func TestPostExit_Histograms(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	previous := otel.GetMeterProvider()
+	otel.SetMeterProvider(provider)
+	defer otel.SetMeterProvider(previous)
+
+	gin.SetMode(gin.TestMode)
+	mockService := new(mocks.ParkingService)
+	handler := NewParkingHandler(mockService)
+	router := gin.New()
+	ticketID := uuid.New()
+	router.POST("/exit", func(c *gin.Context) {
+		handler.PostExit(c, api.PostExitParams{TicketId: ticketID})
+	})
+
+	entryTime := time.Now().Add(-45 * time.Minute)
+	ticket := &model.ParkingTicket{TicketID: ticketID.String(), Plate: "ABC-123", ParkingLot: 1, EntryTime: entryTime}
+	mockService.On("GetTicket", mock.Anything, ticketID.String()).Return(ticket, true).Once()
+	mockService.On("CalculateChargeDetailed", 1, entryTime).Return(45*time.Minute, 45, float32(7.5)).Once()
+	mockService.On("UpdateTicket", mock.Anything, ticket).Return(nil).Once()
+	mockService.On("ReleaseSpaces", mock.Anything, 1, 1).Once()
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/exit", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockService.AssertExpectations(t)
+
+	var rm metricdata.ResourceMetrics
+	assert.NoError(t, reader.Collect(context.Background(), &rm))
+
+	histograms := make(map[string]metricdata.HistogramDataPoint[float64])
+	for _, scope := range rm.ScopeMetrics {
+		for _, m := range scope.Metrics {
+			if data, ok := m.Data.(metricdata.Histogram[float64]); ok && len(data.DataPoints) == 1 {
+				histograms[m.Name] = data.DataPoints[0]
+			}
+		}
+	}
+
+	// 45 minutes falls in the (30, 60] bucket
+	if duration, ok := histograms["parking_duration_minutes"]; assert.True(t, ok) {
+		assert.Equal(t, durationBuckets, duration.Bounds)
+		assert.Equal(t, uint64(1), duration.Count)
+		assert.Equal(t, uint64(1), duration.BucketCounts[2])
+	}
+	// $7.50 falls in the (5, 10] bucket
+	if charge, ok := histograms["parking_charge_dollars"]; assert.True(t, ok) {
+		assert.Equal(t, uint64(1), charge.Count)
+		assert.Equal(t, uint64(1), charge.BucketCounts[2])
+		assert.Equal(t, 7.5, charge.Sum)
+	}
+}
+
+// TestPostExit_EMF tests that an exit is written as an EMF document when EMF is enabled
+func TestPostExit_EMF(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockService := new(mocks.ParkingService)
+	handler := NewParkingHandler(mockService)
+	var buf bytes.Buffer
+	handler.emf = emf.NewEmitter(&buf)
+	router := gin.New()
+	api.RegisterHandlersWithOptions(router, handler, api.GinServerOptions{ErrorHandler: handler.ErrorHandler})
+
+	ticketID := uuid.New()
+	entryTime := time.Now().Add(-45 * time.Minute)
+	ticket := &model.ParkingTicket{TicketID: ticketID.String(), Plate: "TRUCK-1", ParkingLot: 4, EntryTime: entryTime, SpacesUsed: 2}
+	mockService.On("GetTicket", mock.Anything, ticketID.String()).Return(ticket, true).Once()
+	mockService.On("CalculateChargeDetailed", 4, entryTime).Return(45*time.Minute, 45, float32(7.5)).Once()
+	mockService.On("UpdateTicket", mock.Anything, ticket).Return(nil).Once()
+	mockService.On("ReleaseSpaces", mock.Anything, 4, 2).Once()
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/exit?ticketId="+ticketID.String(), nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var document map[string]any
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &document))
+	assert.Contains(t, document, "_aws")
+	assert.Equal(t, "4", document[emf.DimensionParkingLot])
+	assert.Equal(t, "large", document[emf.DimensionVehicleType])
+	assert.Equal(t, 1.0, document[emf.ExitCount])
+	assert.Equal(t, 7.5, document[emf.Charge])
+}
+
+// TestSessionID tests that the session ID stays the same from entry through exit and re-entry
+func TestSessionID(t *testing.T) {
+	t.Setenv("GRACE_REENTRY_MINUTES", "10")
+	memoryService, err := service.NewMemoryParkingLotService(context.Background())
+	assert.NoError(t, err)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	api.RegisterHandlers(router, NewParkingHandler(memoryService))
+
+	post := func(path string) []byte {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("POST", path, nil))
+		assert.Equal(t, http.StatusOK, w.Code, path)
+		return w.Body.Bytes()
+	}
+
+	var entry api.EntryResponse
+	assert.NoError(t, json.Unmarshal(post("/entry?plate=SES-123&parkingLot=1"), &entry))
+	if !assert.NotNil(t, entry.SessionId) {
+		return
+	}
+	assert.NotEqual(t, entry.TicketId, *entry.SessionId)
+
+	var exit api.ExitResponse
+	assert.NoError(t, json.Unmarshal(post("/exit?ticketId="+entry.TicketId.String()), &exit))
+	assert.Equal(t, entry.SessionId, exit.SessionId)
+
+	// Returning within the grace window continues the same session
+	var reentry api.EntryResponse
+	assert.NoError(t, json.Unmarshal(post("/entry?plate=SES-123&parkingLot=1"), &reentry))
+	assert.Equal(t, entry.TicketId, reentry.TicketId)
+	assert.Equal(t, entry.SessionId, reentry.SessionId)
+
+	// A new visit after the first session ended gets a new session
+	var other api.EntryResponse
+	assert.NoError(t, json.Unmarshal(post("/entry?plate=SES-456&parkingLot=1"), &other))
+	assert.NotEqual(t, entry.SessionId, other.SessionId)
+}
+
+// TestPostEntry_Anonymous tests entries without a plate, which get a placeholder plate only when ALLOW_ANONYMOUS=true
+func TestPostEntry_Anonymous(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("Allowed", func(t *testing.T) {
+		t.Setenv("ALLOW_ANONYMOUS", "true")
+		memoryService, err := service.NewMemoryParkingLotService(context.Background())
+		assert.NoError(t, err)
+		router := gin.New()
+		api.RegisterHandlers(router, NewParkingHandler(memoryService))
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("POST", "/entry?parkingLot=1", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var entry api.EntryResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &entry))
+		if !assert.NotNil(t, entry.Plate) {
+			return
+		}
+		assert.Regexp(t, `^ANON-[0-9A-F]{8}$`, *entry.Plate)
+
+		ticket, found := memoryService.GetTicket(context.Background(), entry.TicketId.String())
+		if assert.True(t, found) {
+			assert.True(t, ticket.Anonymous)
+			assert.Equal(t, *entry.Plate, ticket.Plate)
+		}
+
+		// The ticket ID alone is enough to exit
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("POST", "/exit?ticketId="+entry.TicketId.String(), nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+		var exit api.ExitResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &exit))
+		assert.Equal(t, *entry.Plate, exit.Plate)
+
+		// A plate given as usual is not anonymous
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("POST", "/entry?plate=ABC-123&parkingLot=1", nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+		var plated api.EntryResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &plated))
+		assert.Nil(t, plated.Plate)
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		t.Setenv("ALLOW_ANONYMOUS", "")
+		mockService := new(mocks.ParkingService)
+		router := gin.New()
+		api.RegisterHandlers(router, NewParkingHandler(mockService))
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("POST", "/entry?parkingLot=1", nil))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.JSONEq(t, `{"code":"VALIDATION_FAILED","message":"Query argument plate is required, but not found"}`, w.Body.String())
+		mockService.AssertNotCalled(t, "ReserveSpaces", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+// TestPostEntry_SpotNumber tests that a freed spot is reassigned after exit and that a lot
+// without free spots rejects entries with 409
+// TestPostEntry_EstimatedCharge tests that an expected stay previews its charge without billing it
+func TestPostEntry_EstimatedCharge(t *testing.T) {
+	t.Setenv("RATE_INCREMENT_MINUTES", "15")
+	t.Setenv("RATE_PER_INCREMENT", "2.5")
+	memoryService, err := service.NewMemoryParkingLotService(context.Background())
+	assert.NoError(t, err)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	api.RegisterHandlers(router, NewParkingHandler(memoryService))
+
+	enter := func(query string) (int, api.EntryResponse) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("POST", "/entry?parkingLot=1&"+query, nil))
+		var entry api.EntryResponse
+		_ = json.Unmarshal(w.Body.Bytes(), &entry)
+		return w.Code, entry
+	}
+
+	status, entry := enter("plate=EST-1&expectedMinutes=90")
+	assert.Equal(t, http.StatusOK, status)
+	ticket, found := memoryService.GetTicket(context.Background(), entry.TicketId.String())
+	if assert.True(t, found) && assert.NotNil(t, entry.EstimatedCharge) {
+		assert.Equal(t, float32(15), *entry.EstimatedCharge)
+		assert.Equal(t, memoryService.EstimateCharge("", 1, ticket.EntryTime, 90*time.Minute), *entry.EstimatedCharge)
+		// The estimate is not billed
+		assert.Zero(t, ticket.Charge)
+	}
+
+	status, entry = enter("plate=EST-2")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Nil(t, entry.EstimatedCharge)
+
+	status, _ = enter("plate=EST-3&expectedMinutes=0")
+	assert.Equal(t, http.StatusBadRequest, status)
+}
+
+func TestPostEntry_SpotNumber(t *testing.T) {
+	t.Setenv("LOT_CONFIG", `{"1":{"spots":2}}`)
+	memoryService, err := service.NewMemoryParkingLotService(context.Background())
+	assert.NoError(t, err)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	api.RegisterHandlers(router, NewParkingHandler(memoryService))
+
+	enter := func(plate string) (int, api.EntryResponse) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("POST", "/entry?plate="+plate+"&parkingLot=1", nil))
+		var entry api.EntryResponse
+		_ = json.Unmarshal(w.Body.Bytes(), &entry)
+		return w.Code, entry
+	}
+
+	status, first := enter("SPOT-1")
+	assert.Equal(t, http.StatusOK, status)
+	if assert.NotNil(t, first.SpotNumber) {
+		assert.Equal(t, 1, *first.SpotNumber)
+	}
+	status, second := enter("SPOT-2")
+	assert.Equal(t, http.StatusOK, status)
+	if assert.NotNil(t, second.SpotNumber) {
+		assert.Equal(t, 2, *second.SpotNumber)
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/entry?plate=SPOT-3&parkingLot=1", nil))
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.JSONEq(t, `{"code":"NO_FREE_SPOT","message":"No free spot in parking lot"}`, w.Body.String())
+
+	// Exiting frees spot 1 for the waiting vehicle
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/exit?ticketId="+first.TicketId.String(), nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	status, third := enter("SPOT-3")
+	assert.Equal(t, http.StatusOK, status)
+	if assert.NotNil(t, third.SpotNumber) {
+		assert.Equal(t, 1, *third.SpotNumber)
+	}
+	ticket, found := memoryService.GetTicket(context.Background(), third.TicketId.String())
+	if assert.True(t, found) {
+		assert.Equal(t, 1, ticket.SpotNumber)
+	}
 }
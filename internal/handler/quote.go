@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"parking-lot/internal/logger"
+	"parking-lot/internal/model"
+	"parking-lot/internal/service"
+	"parking-lot/server/api"
+)
+
+// PostQuote computes the charge for a parked vehicle and stores it so the exit honors it
+// within the exit window
+func (h *ParkingHandler) PostQuote(c *gin.Context, params api.PostQuoteParams) {
+	ctx, span := tracer.Start(c.Request.Context(), "PostQuote")
+	defer span.End()
+
+	log := h.log.WithContext(ctx).WithFields(
+		logger.Field{Key: "ticket_id", Value: params.TicketId},
+	)
+	log.Info("Quoting parking charge")
+
+	if h.serviceUnavailable() {
+		log.Warn("Storage unavailable, failing fast")
+		h.respondUnavailable(c)
+		return
+	}
+
+	ticket, exists := h.service.GetTicket(ctx, params.TicketId.String())
+	if !exists {
+		log.Warn("Ticket not found")
+		h.respond(c, http.StatusNotFound, api.ErrorResponse{
+			Code:    CodeTicketNotFound,
+			Message: "Ticket not found",
+		})
+		return
+	}
+	if ticket.Status == model.TicketStatusOut {
+		log.Warn("Ticket already exited")
+		h.respond(c, http.StatusConflict, api.ErrorResponse{
+			Code:     CodeTicketExited,
+			Message:  "Ticket already exited",
+			TicketId: &params.TicketId,
+		})
+		return
+	}
+
+	duration, minutes, breakdown := h.itemizedCharge(ticket)
+	charge := breakdown.Total
+	quoteTime := ticket.EntryTime.Add(duration)
+	ticket.QuoteCharge = charge
+	ticket.QuoteTime = &quoteTime
+
+	// While read-only the quote is still shown but not stored, so the exit recomputes the charge
+	stored := !h.readOnly()
+	if !stored {
+		log.Warn("Service is read-only, not storing quote")
+	} else if err := h.service.UpdateTicket(ctx, ticket); errors.Is(err, service.ErrReadOnly) {
+		log.Warn("Service became read-only, not storing quote")
+		stored = false
+	} else if err != nil {
+		if errors.Is(err, service.ErrDynamoDBUnavailable) {
+			log.Warn("Storage unavailable, failing fast")
+			h.respondUnavailable(c)
+			return
+		}
+		if errors.Is(err, service.ErrItemTooLarge) {
+			log.Warn("Ticket too large to store", logger.Field{Key: "error", Value: err.Error()})
+			h.respondItemTooLarge(c, &params.TicketId)
+			return
+		}
+		if errors.Is(err, service.ErrTicketConflict) {
+			log.Warn("Ticket was updated concurrently, rejecting quote")
+			h.respondTicketConflict(c, &params.TicketId)
+			return
+		}
+		log.Error("Failed to store quote", logger.Field{Key: "error", Value: err.Error()})
+		h.respond(c, http.StatusInternalServerError, api.ErrorResponse{
+			Code:    CodeInternalError,
+			Message: "Failed to store quote",
+		})
+		return
+	}
+
+	response := api.QuoteResponse{
+		Charge:                charge,
+		ParkedDurationMinutes: minutes,
+		QuotedAt:              quoteTime,
+	}
+	if window := h.service.ExitWindow(); stored && window > 0 {
+		exitBy := quoteTime.Add(window)
+		response.ExitBy = &exitBy
+	}
+
+	log.Info("Quoted parking charge",
+		logger.Field{Key: "minutes", Value: minutes},
+		logger.Field{Key: "charge", Value: charge},
+	)
+	h.respond(c, http.StatusOK, response)
+}
@@ -0,0 +1,268 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"parking-lot/internal/mocks"
+	"parking-lot/internal/model"
+	"parking-lot/server/api"
+)
+
+// setupQuoteRouter registers all routes on a handler using the given mock service
+func setupQuoteRouter(mockService *mocks.ParkingService) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	api.RegisterHandlers(router, NewParkingHandler(mockService))
+	return router
+}
+
+// TestPostQuote tests quoting the charge for a parked vehicle
+func TestPostQuote(t *testing.T) {
+	ticketID := uuid.New()
+	entryTime := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	t.Run("Quote stored", func(t *testing.T) {
+		mockService := new(mocks.ParkingService)
+		ticket := &model.ParkingTicket{TicketID: ticketID.String(), ParkingLot: 1, EntryTime: entryTime, Status: model.TicketStatusIn}
+		mockService.On("GetTicket", mock.Anything, ticketID.String()).Return(ticket, true).Once()
+		mockService.On("CalculateChargeDetailed", 1, entryTime).Return(45*time.Minute, 45, float32(7.5)).Once()
+		mockService.On("UpdateTicket", mock.Anything, mock.MatchedBy(func(updated *model.ParkingTicket) bool {
+			return updated.QuoteCharge == 7.5 && updated.QuoteTime != nil && updated.QuoteTime.Equal(entryTime.Add(45*time.Minute)) &&
+				updated.Status == model.TicketStatusIn
+		})).Return(nil).Once()
+		mockService.On("ExitWindow").Return(15 * time.Minute).Once()
+
+		w := httptest.NewRecorder()
+		setupQuoteRouter(mockService).ServeHTTP(w, httptest.NewRequest("POST", "/quote?ticketId="+ticketID.String(), nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"charge":7.5,"parkedDurationMinutes":45,"quotedAt":"2024-05-01T10:45:00Z","exitBy":"2024-05-01T11:00:00Z"}`, w.Body.String())
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("Already exited", func(t *testing.T) {
+		mockService := new(mocks.ParkingService)
+		ticket := &model.ParkingTicket{TicketID: ticketID.String(), ParkingLot: 1, EntryTime: entryTime, Status: model.TicketStatusOut}
+		mockService.On("GetTicket", mock.Anything, ticketID.String()).Return(ticket, true).Once()
+
+		w := httptest.NewRecorder()
+		setupQuoteRouter(mockService).ServeHTTP(w, httptest.NewRequest("POST", "/quote?ticketId="+ticketID.String(), nil))
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		var response api.ErrorResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "Ticket already exited", response.Message)
+		mockService.AssertNotCalled(t, "UpdateTicket", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Not found", func(t *testing.T) {
+		mockService := new(mocks.ParkingService)
+		mockService.On("GetTicket", mock.Anything, ticketID.String()).Return(nil, false).Once()
+
+		w := httptest.NewRecorder()
+		setupQuoteRouter(mockService).ServeHTTP(w, httptest.NewRequest("POST", "/quote?ticketId="+ticketID.String(), nil))
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+// TestPostExit_ExitWindow tests that exit honors a fresh kiosk quote and recomputes a stale one
+func TestPostExit_ExitWindow(t *testing.T) {
+	ticketID := uuid.New()
+	entryTime := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	quoteTime := entryTime.Add(30 * time.Minute)
+
+	tests := []struct {
+		name           string
+		parked         time.Duration
+		computedCharge float32
+		expectedCharge float32
+	}{
+		{name: "Inside window honors quote", parked: 40 * time.Minute, computedCharge: 7.5, expectedCharge: 5},
+		{name: "Outside window recomputes", parked: 60 * time.Minute, computedCharge: 10, expectedCharge: 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(mocks.ParkingService)
+			ticket := &model.ParkingTicket{
+				TicketID: ticketID.String(), Plate: "ABC-123", ParkingLot: 1, EntryTime: entryTime,
+				Status: model.TicketStatusIn, QuoteCharge: 5, QuoteTime: &quoteTime,
+			}
+			minutes := int(tt.parked.Minutes())
+			mockService.On("GetTicket", mock.Anything, ticketID.String()).Return(ticket, true).Once()
+			mockService.On("CalculateChargeDetailed", 1, entryTime).Return(tt.parked, minutes, tt.computedCharge).Once()
+			mockService.On("ExitWindow").Return(15 * time.Minute).Once()
+			mockService.On("UpdateTicket", mock.Anything, mock.MatchedBy(func(updated *model.ParkingTicket) bool {
+				return updated.Charge == tt.expectedCharge && updated.Status == model.TicketStatusOut
+			})).Return(nil).Once()
+			mockService.On("ReleaseSpaces", mock.Anything, 1, 1).Once()
+
+			w := httptest.NewRecorder()
+			setupQuoteRouter(mockService).ServeHTTP(w, httptest.NewRequest("POST", "/exit?ticketId="+ticketID.String(), nil))
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			var response api.ExitResponse
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, tt.expectedCharge, response.Charge)
+			assert.Equal(t, minutes, response.ParkedDurationMinutes)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+// parkLimitService gives the mock service a maximum parking duration
+type parkLimitService struct {
+	*mocks.ParkingService
+	limit time.Duration
+}
+
+// MaxParkDuration returns the configured limit
+func (s parkLimitService) MaxParkDuration() time.Duration { return s.limit }
+
+// TestPostExit_MaxParkDuration tests that an exit over the limit is flagged and keeps the capped charge
+func TestPostExit_MaxParkDuration(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ticketID := uuid.New()
+	entryTime := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	mockService := new(mocks.ParkingService)
+	ticket := &model.ParkingTicket{TicketID: ticketID.String(), Plate: "ABC-123", ParkingLot: 1, EntryTime: entryTime, Status: model.TicketStatusIn}
+	mockService.On("GetTicket", mock.Anything, ticketID.String()).Return(ticket, true).Once()
+	// 72 hours parked, but the service only charges the first 48
+	mockService.On("CalculateChargeDetailed", 1, entryTime).Return(72*time.Hour, 72*60, float32(480)).Once()
+	mockService.On("ExitWindow").Return(time.Duration(0)).Maybe()
+	mockService.On("UpdateTicket", mock.Anything, mock.MatchedBy(func(updated *model.ParkingTicket) bool {
+		return updated.Charge == 480 && updated.Status == model.TicketStatusOut
+	})).Return(nil).Once()
+	mockService.On("ReleaseSpaces", mock.Anything, 1, 1).Once()
+
+	router := gin.New()
+	api.RegisterHandlers(router, NewParkingHandler(parkLimitService{mockService, 48 * time.Hour}))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/exit?ticketId="+ticketID.String(), nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response api.ExitResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, float32(480), response.Charge)
+	assert.Equal(t, 72*60, response.ParkedDurationMinutes)
+	if assert.NotNil(t, response.Flagged) && assert.NotNil(t, response.Reason) {
+		assert.True(t, *response.Flagged)
+		assert.Equal(t, "Parked 4320 minutes, over the 2880 minute limit", *response.Reason)
+	}
+	mockService.AssertExpectations(t)
+}
+
+// lotConfigService gives the mock service per-lot configuration
+type lotConfigService struct {
+	*mocks.ParkingService
+	lots map[int]model.LotConfig
+}
+
+// LotConfig returns the configured lot, or the zero configuration
+func (s lotConfigService) LotConfig(parkingLot int) model.LotConfig { return s.lots[parkingLot] }
+
+// TestPostExit_ChargeFormatted tests that the same charge is formatted for each lot's currency and locale,
+// falling back to the rate schedule's currency and the default locale
+func TestPostExit_ChargeFormatted(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	entryTime := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	lots := map[int]model.LotConfig{
+		1: {Currency: "USD", Locale: "en-US"},
+		2: {Currency: "EUR", Locale: "de-DE"},
+	}
+
+	tests := []struct {
+		name       string
+		parkingLot int
+		currency   string
+		formatted  string
+	}{
+		{name: "US lot", parkingLot: 1, currency: "USD", formatted: "$1,234.50"},
+		{name: "German lot", parkingLot: 2, currency: "EUR", formatted: "1.234,50 €"},
+		{name: "Unconfigured lot", parkingLot: 3, currency: "ILS", formatted: "₪1,234.50"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ticketID := uuid.New()
+			mockService := new(mocks.ParkingService)
+			ticket := &model.ParkingTicket{TicketID: ticketID.String(), Plate: "ABC-123", ParkingLot: tt.parkingLot, EntryTime: entryTime, Status: model.TicketStatusIn}
+			mockService.On("GetTicket", mock.Anything, ticketID.String()).Return(ticket, true).Once()
+			mockService.On("CalculateChargeDetailed", tt.parkingLot, entryTime).Return(45*time.Minute, 45, float32(1234.5)).Once()
+			mockService.On("ExitWindow").Return(time.Duration(0)).Maybe()
+			mockService.On("Rates").Return(model.RateSchedule{Currency: "ILS"}).Maybe()
+			mockService.On("UpdateTicket", mock.Anything, mock.Anything).Return(nil).Once()
+			mockService.On("ReleaseSpaces", mock.Anything, tt.parkingLot, 1).Once()
+
+			router := gin.New()
+			api.RegisterHandlers(router, NewParkingHandler(lotConfigService{mockService, lots}))
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest("POST", "/exit?ticketId="+ticketID.String(), nil))
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			var response api.ExitResponse
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, float32(1234.5), response.Charge)
+			if assert.NotNil(t, response.Currency) && assert.NotNil(t, response.ChargeFormatted) {
+				assert.Equal(t, tt.currency, *response.Currency)
+				assert.Equal(t, tt.formatted, *response.ChargeFormatted)
+			}
+		})
+	}
+}
+
+// readOnlyService reports the mock service as read-only, as after sustained write failures
+type readOnlyService struct {
+	*mocks.ParkingService
+}
+
+// ReadOnly reports the service as read-only
+func (readOnlyService) ReadOnly() bool { return true }
+
+// TestReadOnlyMode tests that entries and exits fail fast while quotes keep working without being stored
+func TestReadOnlyMode(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ticketID := uuid.New()
+	entryTime := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	mockService := new(mocks.ParkingService)
+	ticket := &model.ParkingTicket{TicketID: ticketID.String(), ParkingLot: 1, EntryTime: entryTime, Status: model.TicketStatusIn}
+	mockService.On("GetTicket", mock.Anything, ticketID.String()).Return(ticket, true).Twice()
+	mockService.On("CalculateChargeDetailed", 1, entryTime).Return(45*time.Minute, 45, float32(7.5)).Once()
+	mockService.On("ExitWindow").Return(15 * time.Minute).Maybe()
+
+	router := gin.New()
+	api.RegisterHandlers(router, NewParkingHandler(readOnlyService{mockService}))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/entry?plate=ABC-123&parkingLot=1", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.JSONEq(t, `{"code":"READ_ONLY","message":"Service is read-only"}`, w.Body.String())
+
+	// The quote is returned but not stored, so it carries no exit deadline
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/quote?ticketId="+ticketID.String(), nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"charge":7.5,"parkedDurationMinutes":45,"quotedAt":"2024-05-01T10:45:00Z"}`, w.Body.String())
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/exit?ticketId="+ticketID.String(), nil))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+
+	mockService.AssertExpectations(t)
+	mockService.AssertNotCalled(t, "ReserveSpaces", mock.Anything, mock.Anything, mock.Anything)
+	mockService.AssertNotCalled(t, "UpdateTicket", mock.Anything, mock.Anything)
+}
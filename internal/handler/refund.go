@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+
+	"parking-lot/internal/logger"
+	"parking-lot/internal/service"
+	"parking-lot/server/api"
+)
+
+// PostTicketTicketIdRefund refunds part or all of the charge of a ticket that exited within the
+// refund window, e.g. for a driver charged at the barrier after paying at a kiosk
+func (h *ParkingHandler) PostTicketTicketIdRefund(c *gin.Context, ticketId openapi_types.UUID, params api.PostTicketTicketIdRefundParams) {
+	ctx, span := tracer.Start(c.Request.Context(), "RefundTicket")
+	defer span.End()
+
+	log := h.log.WithContext(ctx).WithFields(
+		logger.Field{Key: "ticket_id", Value: ticketId},
+	)
+	log.Info("Processing refund")
+
+	if !h.authorizeAdmin(c, log) {
+		return
+	}
+
+	var amount float32
+	if params.Amount != nil {
+		amount = *params.Amount
+		if amount <= 0 {
+			log.Warn("Invalid refund amount", logger.Field{Key: "amount", Value: amount})
+			h.respond(c, http.StatusBadRequest, api.ErrorResponse{
+				Code:    CodeValidationFailed,
+				Message: service.ErrInvalidRefundAmount.Error(),
+			})
+			return
+		}
+	}
+
+	if h.serviceUnavailable() {
+		log.Warn("Storage unavailable, failing fast")
+		h.respondUnavailable(c)
+		return
+	}
+
+	if h.readOnly() {
+		log.Warn("Service is read-only, rejecting refund")
+		h.respondReadOnly(c)
+		return
+	}
+
+	refunder, ok := h.service.(service.TicketRefunder)
+	if !ok {
+		log.Error("Service does not support refunds")
+		h.respond(c, http.StatusNotImplemented, api.ErrorResponse{
+			Code:    CodeNotImplemented,
+			Message: "Refunds are not supported",
+		})
+		return
+	}
+
+	ticket, exists := h.service.GetTicket(ctx, ticketId.String())
+	if !exists {
+		log.Warn("Ticket not found")
+		h.respond(c, http.StatusNotFound, api.ErrorResponse{
+			Code:    CodeTicketNotFound,
+			Message: "Ticket not found",
+		})
+		return
+	}
+
+	err := refunder.RefundTicket(ctx, ticket, amount)
+	switch {
+	case errors.Is(err, service.ErrNotRefundable), errors.Is(err, service.ErrRefundWindowClosed):
+		log.Warn("Refund rejected", logger.Field{Key: "error", Value: err.Error()})
+		code := CodeNotRefundable
+		if errors.Is(err, service.ErrRefundWindowClosed) {
+			code = CodeRefundWindowClosed
+		}
+		h.respond(c, http.StatusConflict, api.ErrorResponse{
+			Code:     code,
+			Message:  err.Error(),
+			TicketId: &ticketId,
+		})
+		return
+	case errors.Is(err, service.ErrInvalidRefundAmount):
+		log.Warn("Refund rejected", logger.Field{Key: "error", Value: err.Error()})
+		h.respond(c, http.StatusBadRequest, api.ErrorResponse{
+			Code:    CodeValidationFailed,
+			Message: err.Error(),
+		})
+		return
+	case errors.Is(err, service.ErrDynamoDBUnavailable):
+		log.Warn("Storage unavailable, failing fast")
+		h.respondUnavailable(c)
+		return
+	case err != nil:
+		log.Error("Failed to refund ticket", logger.Field{Key: "error", Value: err.Error()})
+		h.respond(c, http.StatusInternalServerError, api.ErrorResponse{
+			Code:    CodeInternalError,
+			Message: "Failed to refund ticket",
+		})
+		return
+	}
+
+	h.respond(c, http.StatusOK, ticketResponse(ticketId, ticket))
+}
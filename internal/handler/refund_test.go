@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"parking-lot/server/api"
+)
+
+// refund sends a refund request for a ticket with the admin token
+func refund(router http.Handler, ticketID string, query string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("POST", "/ticket/"+ticketID+"/refund"+query, nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+// TestPostTicketTicketIdRefund tests refunding part of a charge within the refund window
+func TestPostTicketTicketIdRefund(t *testing.T) {
+	t.Setenv("REFUND_WINDOW_MINUTES", "15")
+	router, memoryService := setupCorrectionRouter(t)
+	ticketID, ticket := memoryService.CreateTicket(context.Background(), "ABC-123", 7, 1)
+	ticket.EntryTime = ticket.EntryTime.Add(-2 * time.Hour)
+	require.NoError(t, memoryService.UpdateTicket(context.Background(), ticket))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/exit?ticketId="+ticketID.String(), nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	exited, _ := memoryService.GetTicket(context.Background(), ticketID.String())
+	require.Greater(t, exited.Charge, float32(1))
+
+	w = refund(router, ticketID.String(), "?amount=1")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response api.TicketResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	if assert.NotNil(t, response.Charge) && assert.NotNil(t, response.RefundAmount) {
+		assert.Equal(t, exited.Charge-1, *response.Charge)
+		assert.Equal(t, float32(1), *response.RefundAmount)
+	}
+
+	stored, _ := memoryService.GetTicket(context.Background(), ticketID.String())
+	assert.Equal(t, exited.Charge-1, stored.Charge)
+	assert.NotNil(t, stored.RefundTime)
+
+	// A ticket is refunded at most once
+	w = refund(router, ticketID.String(), "")
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+// TestPostTicketTicketIdRefund_PastWindow tests that a ticket that exited before the refund window is not refunded
+func TestPostTicketTicketIdRefund_PastWindow(t *testing.T) {
+	t.Setenv("REFUND_WINDOW_MINUTES", "15")
+	router, memoryService := setupCorrectionRouter(t)
+	ticketID, ticket := memoryService.CreateTicket(context.Background(), "ABC-123", 7, 1)
+	ticket.EntryTime = ticket.EntryTime.Add(-2 * time.Hour)
+	require.NoError(t, memoryService.UpdateTicket(context.Background(), ticket))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/exit?ticketId="+ticketID.String(), nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	exited, _ := memoryService.GetTicket(context.Background(), ticketID.String())
+	exitTime := exited.ExitTime.Add(-time.Hour)
+	exited.ExitTime = &exitTime
+	require.NoError(t, memoryService.UpdateTicket(context.Background(), exited))
+
+	w = refund(router, ticketID.String(), "")
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	var response api.ErrorResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "refund window has closed", response.Message)
+
+	stored, _ := memoryService.GetTicket(context.Background(), ticketID.String())
+	assert.Equal(t, exited.Charge, stored.Charge)
+	assert.Nil(t, stored.RefundTime)
+}
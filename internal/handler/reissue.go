@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"parking-lot/internal/logger"
+	"parking-lot/internal/service"
+	"parking-lot/server/api"
+)
+
+// PostTicketReissue replaces a lost printed ticket. The active session of the plate moves to a new
+// ticket ID, keeping its entry time and session ID, and the lost ID stops resolving.
+func (h *ParkingHandler) PostTicketReissue(c *gin.Context, params api.PostTicketReissueParams) {
+	ctx, span := tracer.Start(c.Request.Context(), "ReissueTicket")
+	defer span.End()
+
+	log := h.log.WithContext(ctx).WithFields(logger.Plate(params.Plate))
+	log.Info("Processing ticket reissue")
+
+	if !h.authorizeAdmin(c, log) {
+		return
+	}
+
+	if h.serviceUnavailable() {
+		log.Warn("Storage unavailable, failing fast")
+		h.respondUnavailable(c)
+		return
+	}
+
+	if h.readOnly() {
+		log.Warn("Service is read-only, rejecting ticket reissue")
+		h.respondReadOnly(c)
+		return
+	}
+
+	reissuer, ok := h.service.(service.TicketReissuer)
+	if !ok {
+		log.Error("Service does not support ticket reissues")
+		h.respond(c, http.StatusNotImplemented, api.ErrorResponse{
+			Code:    CodeNotImplemented,
+			Message: "Ticket reissue is not supported",
+		})
+		return
+	}
+
+	ticket, previous, err := reissuer.ReissueTicket(ctx, params.Plate)
+	if errors.Is(err, service.ErrNoActiveTicket) {
+		log.Warn("No active ticket for plate")
+		h.respond(c, http.StatusNotFound, api.ErrorResponse{
+			Code:    CodeNoActiveTicket,
+			Message: "No active ticket for plate",
+		})
+		return
+	}
+	if errors.Is(err, service.ErrTicketConflict) {
+		log.Warn("Ticket exited or was updated concurrently")
+		h.respondTicketConflict(c, nil)
+		return
+	}
+	if errors.Is(err, service.ErrDynamoDBUnavailable) {
+		log.Warn("Storage unavailable, failing fast")
+		h.respondUnavailable(c)
+		return
+	}
+	if err != nil {
+		log.Error("Failed to reissue ticket", logger.Field{Key: "error", Value: err.Error()})
+		h.respond(c, http.StatusInternalServerError, api.ErrorResponse{
+			Code:    CodeInternalError,
+			Message: "Failed to reissue ticket",
+		})
+		return
+	}
+
+	ticketID, err := uuid.Parse(ticket.TicketID)
+	if err != nil {
+		log.Error("Reissued ticket has an invalid ID", logger.Field{Key: "ticket_id", Value: ticket.TicketID})
+		h.respond(c, http.StatusInternalServerError, api.ErrorResponse{
+			Code:    CodeInternalError,
+			Message: "Failed to reissue ticket",
+		})
+		return
+	}
+	previousID, err := uuid.Parse(previous)
+	if err != nil {
+		log.Error("Previous ticket has an invalid ID", logger.Field{Key: "previous_ticket_id", Value: previous})
+		h.respond(c, http.StatusInternalServerError, api.ErrorResponse{
+			Code:    CodeInternalError,
+			Message: "Failed to reissue ticket",
+		})
+		return
+	}
+
+	log.Info("Ticket reissued",
+		logger.Field{Key: "ticket_id", Value: ticket.TicketID},
+		logger.Field{Key: "previous_ticket_id", Value: previous},
+	)
+	h.respond(c, http.StatusOK, api.ReissueResponse{
+		PreviousTicketId: previousID,
+		Ticket:           ticketResponse(ticketID, ticket),
+	})
+}
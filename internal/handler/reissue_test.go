@@ -0,0 +1,99 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"parking-lot/internal/mocks"
+	"parking-lot/internal/model"
+	"parking-lot/internal/service"
+	"parking-lot/server/api"
+)
+
+// TestPostTicketReissue tests that a parked vehicle's session moves to a new ticket ID
+// and the lost ID stops resolving
+func TestPostTicketReissue(t *testing.T) {
+	router, memoryService := setupCorrectionRouter(t)
+	ticketID, ticket := memoryService.CreateTicket(context.Background(), "ABC-123", 7, 1)
+
+	req := httptest.NewRequest("POST", "/ticket/reissue?plate=ABC-123", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response api.ReissueResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, ticketID, response.PreviousTicketId)
+	assert.NotEqual(t, ticketID, response.Ticket.TicketId)
+	assert.Equal(t, "ABC-123", response.Ticket.Plate)
+	assert.Equal(t, "in", response.Ticket.Status)
+	assert.True(t, ticket.EntryTime.Equal(response.Ticket.EntryTime))
+	if assert.NotNil(t, response.Ticket.SessionId) {
+		assert.Equal(t, ticket.SessionID, response.Ticket.SessionId.String())
+	}
+
+	_, exists := memoryService.GetTicket(context.Background(), ticketID.String())
+	assert.False(t, exists)
+	reissued, exists := memoryService.GetTicket(context.Background(), response.Ticket.TicketId.String())
+	if assert.True(t, exists) {
+		assert.True(t, ticket.EntryTime.Equal(reissued.EntryTime))
+	}
+
+	// The vehicle exits with its new ticket
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/exit?ticketId="+response.Ticket.TicketId.String(), nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+// TestPostTicketReissue_NoActiveSession tests that a plate with no parked vehicle gets 404
+func TestPostTicketReissue_NoActiveSession(t *testing.T) {
+	router, memoryService := setupCorrectionRouter(t)
+	ticketID, _ := memoryService.CreateTicket(context.Background(), "ABC-123", 7, 1)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/exit?ticketId="+ticketID.String(), nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	req := httptest.NewRequest("POST", "/ticket/reissue?plate=ABC-123", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	var response api.ErrorResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "No active ticket for plate", response.Message)
+}
+
+// conflictingReissuer fails every reissue as if the ticket exited while it was reissued
+type conflictingReissuer struct {
+	*mocks.ParkingService
+}
+
+// ReissueTicket fails with ErrTicketConflict
+func (conflictingReissuer) ReissueTicket(ctx context.Context, plate string) (*model.ParkingTicket, string, error) {
+	return nil, "", fmt.Errorf("failed to reissue ticket: %w", service.ErrTicketConflict)
+}
+
+// TestPostTicketReissue_Conflict tests that a ticket that exited or changed during the reissue gets 409
+func TestPostTicketReissue_Conflict(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler := NewParkingHandler(conflictingReissuer{ParkingService: new(mocks.ParkingService)})
+	handler.adminToken = "secret"
+	router := gin.New()
+	api.RegisterHandlersWithOptions(router, handler, api.GinServerOptions{ErrorHandler: handler.ErrorHandler})
+
+	req := httptest.NewRequest("POST", "/ticket/reissue?plate=ABC-123", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assertErrorCode(t, w, CodeTicketConflict)
+}
@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"parking-lot/server/api"
+)
+
+// requestIDHeader carries the ID a request is tracked under
+const requestIDHeader = "X-Request-ID"
+
+// responseEnvelope is the body of every response when RESPONSE_ENVELOPE=true: successful responses
+// carry their payload in data, failures carry their ErrorResponse in error
+type responseEnvelope struct {
+	Data      any    `json:"data"`
+	Error     any    `json:"error"`
+	RequestID string `json:"requestId"`
+}
+
+// respond writes body as JSON, wrapped in a responseEnvelope when the envelope format is enabled
+func (h *ParkingHandler) respond(c *gin.Context, status int, body any) {
+	if !h.envelope {
+		c.JSON(status, body)
+		return
+	}
+
+	envelope := responseEnvelope{RequestID: requestID(c)}
+	if status >= http.StatusBadRequest {
+		envelope.Error = body
+	} else {
+		envelope.Data = body
+	}
+	c.JSON(status, envelope)
+}
+
+// abort responds like respond and stops the remaining handlers, for use in middleware
+func (h *ParkingHandler) abort(c *gin.Context, status int, body any) {
+	h.respond(c, status, body)
+	c.Abort()
+}
+
+// NotFound answers requests for paths no operation is mounted on, for use as the router's NoRoute handler
+func (h *ParkingHandler) NotFound(c *gin.Context) {
+	h.respond(c, http.StatusNotFound, api.ErrorResponse{Code: CodeNotFound, Message: "Not Found"})
+}
+
+// Recover answers a request whose handler panicked with a 500, for use with gin.CustomRecovery,
+// which has already logged the panic
+func (h *ParkingHandler) Recover(c *gin.Context, _ any) {
+	h.abort(c, http.StatusInternalServerError, api.ErrorResponse{Code: CodeInternalError, Message: "Internal server error"})
+}
+
+// requestID returns the request ID sent by the client, or the one generated for the response
+func requestID(c *gin.Context) string {
+	if id := c.GetHeader(requestIDHeader); id != "" {
+		return id
+	}
+	return c.Writer.Header().Get(requestIDHeader)
+}
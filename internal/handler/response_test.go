@@ -0,0 +1,120 @@
+package handler
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"parking-lot/internal/mocks"
+	"parking-lot/internal/model"
+	"parking-lot/server/api"
+)
+
+// TestResponseEnvelope tests the flat and enveloped shapes of the same responses
+func TestResponseEnvelope(t *testing.T) {
+	ticketID := uuid.MustParse("123e4567-e89b-12d3-a456-426614174000")
+	entryTime := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	ticketJSON := `{"ticketId":"123e4567-e89b-12d3-a456-426614174000","plate":"ABC-123","parkingLot":1,"entryTime":"2024-05-01T10:00:00Z","status":"in"}`
+
+	tests := []struct {
+		name         string
+		envelope     bool
+		found        bool
+		expectedCode int
+		expectedBody string
+	}{
+		{name: "Flat data", found: true, expectedCode: http.StatusOK, expectedBody: ticketJSON},
+		{name: "Flat error", expectedCode: http.StatusNotFound, expectedBody: `{"code":"TICKET_NOT_FOUND","message":"Ticket not found"}`},
+		{
+			name: "Enveloped data", envelope: true, found: true, expectedCode: http.StatusOK,
+			expectedBody: `{"data":` + ticketJSON + `,"error":null,"requestId":"req-123"}`,
+		},
+		{
+			name: "Enveloped error", envelope: true, expectedCode: http.StatusNotFound,
+			expectedBody: `{"data":null,"error":{"code":"TICKET_NOT_FOUND","message":"Ticket not found"},"requestId":"req-123"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("RESPONSE_ENVELOPE", "")
+			if tt.envelope {
+				t.Setenv("RESPONSE_ENVELOPE", "true")
+			}
+			mockService := new(mocks.ParkingService)
+			if tt.found {
+				mockService.On("GetTicket", mock.Anything, ticketID.String()).Return(&model.ParkingTicket{
+					TicketID: ticketID.String(), Plate: "ABC-123", ParkingLot: 1, EntryTime: entryTime, Status: model.TicketStatusIn,
+				}, true).Once()
+			} else {
+				mockService.On("GetTicket", mock.Anything, ticketID.String()).Return(nil, false).Once()
+			}
+
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			api.RegisterHandlers(router, NewParkingHandler(mockService))
+
+			req := httptest.NewRequest("GET", "/ticket/"+ticketID.String(), nil)
+			req.Header.Set("X-Request-ID", "req-123")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+// TestResponseEnvelope_RouterErrors tests that unknown paths and panics answer in the response format too
+func TestResponseEnvelope_RouterErrors(t *testing.T) {
+	tests := []struct {
+		name         string
+		envelope     bool
+		path         string
+		expectedCode int
+		expectedBody string
+	}{
+		{name: "Flat not found", path: "/nope", expectedCode: http.StatusNotFound, expectedBody: `{"code":"NOT_FOUND","message":"Not Found"}`},
+		{
+			name: "Enveloped not found", envelope: true, path: "/nope", expectedCode: http.StatusNotFound,
+			expectedBody: `{"data":null,"error":{"code":"NOT_FOUND","message":"Not Found"},"requestId":"req-123"}`,
+		},
+		{name: "Flat panic", path: "/panic", expectedCode: http.StatusInternalServerError, expectedBody: `{"code":"INTERNAL_ERROR","message":"Internal server error"}`},
+		{
+			name: "Enveloped panic", envelope: true, path: "/panic", expectedCode: http.StatusInternalServerError,
+			expectedBody: `{"data":null,"error":{"code":"INTERNAL_ERROR","message":"Internal server error"},"requestId":"req-123"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("RESPONSE_ENVELOPE", "")
+			if tt.envelope {
+				t.Setenv("RESPONSE_ENVELOPE", "true")
+			}
+			handler := NewParkingHandler(new(mocks.ParkingService))
+
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			router.Use(gin.CustomRecoveryWithWriter(io.Discard, handler.Recover))
+			router.NoRoute(handler.NotFound)
+			router.GET("/panic", func(c *gin.Context) { panic("boom") })
+
+			req := httptest.NewRequest("GET", tt.path, nil)
+			req.Header.Set("X-Request-ID", "req-123")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+		})
+	}
+}
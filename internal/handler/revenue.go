@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"parking-lot/internal/logger"
+	"parking-lot/internal/service"
+	"parking-lot/server/api"
+)
+
+// GetRevenueOutstanding estimates what the vehicles parked in a lot would be charged if they all
+// exited now, for a live view of the revenue in the lot
+func (h *ParkingHandler) GetRevenueOutstanding(c *gin.Context, params api.GetRevenueOutstandingParams) {
+	ctx, span := tracer.Start(c.Request.Context(), "EstimateOutstandingRevenue")
+	defer span.End()
+
+	log := h.log.WithContext(ctx).WithFields(logger.Field{Key: "parking_lot", Value: params.ParkingLot})
+	log.Info("Processing outstanding revenue request")
+
+	if !h.authorizeAdmin(c, log) {
+		return
+	}
+
+	if err := checkParkingLot(params.ParkingLot, h.maxLot); err != nil {
+		log.Warn("Invalid parking lot")
+		h.respond(c, http.StatusBadRequest, api.ErrorResponse{
+			Code:    CodeValidationFailed,
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if h.serviceUnavailable() {
+		log.Warn("Storage unavailable, failing fast")
+		h.respondUnavailable(c)
+		return
+	}
+
+	estimator, ok := h.service.(service.RevenueEstimator)
+	if !ok {
+		log.Error("Service does not support revenue estimates")
+		h.respond(c, http.StatusNotImplemented, api.ErrorResponse{
+			Code:    CodeNotImplemented,
+			Message: "Revenue estimates are not supported",
+		})
+		return
+	}
+
+	revenue, truncated, err := estimator.EstimatedOutstandingRevenue(ctx, params.ParkingLot)
+	if errors.Is(err, service.ErrDynamoDBUnavailable) {
+		log.Warn("Storage unavailable, failing fast")
+		h.respondUnavailable(c)
+		return
+	}
+	if err != nil {
+		log.Error("Failed to estimate outstanding revenue", logger.Field{Key: "error", Value: err.Error()})
+		h.respond(c, http.StatusInternalServerError, api.ErrorResponse{
+			Code:    CodeInternalError,
+			Message: "Failed to estimate outstanding revenue",
+		})
+		return
+	}
+
+	log.Info("Outstanding revenue estimated",
+		logger.Field{Key: "revenue", Value: revenue},
+		logger.Field{Key: "truncated", Value: truncated},
+	)
+	h.respond(c, http.StatusOK, api.OutstandingRevenueResponse{
+		ParkingLot: params.ParkingLot,
+		Revenue:    revenue,
+		Truncated:  truncated,
+	})
+}
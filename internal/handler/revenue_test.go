@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"parking-lot/server/api"
+)
+
+// TestGetRevenueOutstanding tests the outstanding revenue of a lot and its admin and lot checks
+func TestGetRevenueOutstanding(t *testing.T) {
+	router, memoryService := setupCorrectionRouter(t)
+	memoryService.CreateTicket(context.Background(), "ABC-123", 7, 1)
+
+	tests := []struct {
+		name           string
+		path           string
+		token          string
+		expectedStatus int
+	}{
+		{name: "Estimated", path: "/revenue/outstanding?parkingLot=7", token: "secret", expectedStatus: http.StatusOK},
+		{name: "Missing token", path: "/revenue/outstanding?parkingLot=7", expectedStatus: http.StatusUnauthorized},
+		{name: "Missing lot", path: "/revenue/outstanding", token: "secret", expectedStatus: http.StatusBadRequest},
+		{name: "Lot out of range", path: "/revenue/outstanding?parkingLot=0", token: "secret", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", tt.path, nil)
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code, w.Body.String())
+			if tt.expectedStatus == http.StatusOK {
+				var response api.OutstandingRevenueResponse
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				assert.Equal(t, 7, response.ParkingLot)
+				// A vehicle that just entered already owes its first increment
+				assert.Positive(t, response.Revenue)
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"parking-lot/internal/logger"
+	"parking-lot/internal/service"
+	"parking-lot/server/api"
+)
+
+// TenantMiddleware routes requests carrying the X-Tenant-Table header to that tenant's tickets table.
+// Tables outside the allowlist are rejected with 400; services without tenant support ignore the header.
+func (h *ParkingHandler) TenantMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		router, ok := h.service.(service.TenantRouter)
+		if !ok {
+			c.Next()
+			return
+		}
+
+		tableName := c.GetHeader(service.TenantTableHeader)
+		ctx, err := router.TenantContext(c.Request.Context(), tableName)
+		if err != nil {
+			h.log.WithContext(c.Request.Context()).Warn("Rejected tenant table",
+				logger.Field{Key: "table", Value: tableName},
+				logger.Field{Key: "error", Value: err.Error()},
+			)
+			h.abort(c, http.StatusBadRequest, api.ErrorResponse{
+				Code:    CodeTenantNotAllowed,
+				Message: "Tenant table not allowed",
+			})
+			return
+		}
+
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"parking-lot/internal/mocks"
+	"parking-lot/internal/service"
+	"parking-lot/server/api"
+)
+
+// tenantKey marks contexts routed by tenantService
+type tenantKey struct{}
+
+// tenantService adds tenant routing with a single allowed table to the mock service
+type tenantService struct {
+	*mocks.ParkingService
+}
+
+// TenantContext accepts only the "tenantA" table
+func (tenantService) TenantContext(ctx context.Context, tableName string) (context.Context, error) {
+	if tableName == "" {
+		return ctx, nil
+	}
+	if tableName != "tenantA" {
+		return ctx, service.ErrTableNotAllowed
+	}
+	return context.WithValue(ctx, tenantKey{}, tableName), nil
+}
+
+// TestTenantMiddleware tests that the X-Tenant-Table header selects the table used by the service
+func TestTenantMiddleware(t *testing.T) {
+	tests := []struct {
+		name           string
+		header         string
+		expectedTable  any
+		expectedStatus int
+	}{
+		{name: "Default table", expectedTable: nil, expectedStatus: http.StatusNotFound},
+		{name: "Tenant table", header: "tenantA", expectedTable: "tenantA", expectedStatus: http.StatusNotFound},
+		{name: "Table not allowed", header: "tenantB", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(mocks.ParkingService)
+			handler := NewParkingHandler(tenantService{mockService})
+
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			router.Use(handler.TenantMiddleware())
+			api.RegisterHandlers(router, handler)
+
+			ticketID := uuid.New().String()
+			mockService.On("GetTicket", mock.MatchedBy(func(ctx context.Context) bool {
+				return ctx.Value(tenantKey{}) == tt.expectedTable
+			}), ticketID).Return(nil, false).Maybe()
+
+			req := httptest.NewRequest("GET", "/ticket/"+ticketID, nil)
+			if tt.header != "" {
+				req.Header.Set(service.TenantTableHeader, tt.header)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusBadRequest {
+				assert.JSONEq(t, `{"code":"TENANT_NOT_ALLOWED","message":"Tenant table not allowed"}`, w.Body.String())
+				mockService.AssertNotCalled(t, "GetTicket", mock.Anything, mock.Anything)
+			} else {
+				mockService.AssertNumberOfCalls(t, "GetTicket", 1)
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+
+	"parking-lot/internal/logger"
+	"parking-lot/internal/model"
+	"parking-lot/server/api"
+)
+
+// GetTicketTicketId returns the current state of a ticket
+func (h *ParkingHandler) GetTicketTicketId(c *gin.Context, ticketId openapi_types.UUID) {
+	ctx, span := tracer.Start(c.Request.Context(), "GetTicket")
+	defer span.End()
+
+	log := h.log.WithContext(ctx).WithFields(
+		logger.Field{Key: "ticket_id", Value: ticketId},
+	)
+	log.Info("Looking up ticket")
+
+	if h.serviceUnavailable() {
+		log.Warn("Storage unavailable, failing fast")
+		h.respondUnavailable(c)
+		return
+	}
+
+	ticket, exists := h.service.GetTicket(ctx, ticketId.String())
+	if !exists {
+		log.Warn("Ticket not found")
+		h.respond(c, http.StatusNotFound, api.ErrorResponse{
+			Code:    CodeTicketNotFound,
+			Message: "Ticket not found",
+		})
+		return
+	}
+
+	h.respond(c, http.StatusOK, ticketResponse(ticketId, ticket))
+}
+
+// ticketResponse describes the current state of a ticket. Every response carrying tickets is built
+// with it rather than from the stored model, so attributes such as the version, quote, coupon and
+// spot never reach clients.
+func ticketResponse(ticketId openapi_types.UUID, ticket *model.ParkingTicket) api.TicketResponse {
+	response := api.TicketResponse{
+		TicketId:   ticketId,
+		Plate:      ticket.Plate,
+		ParkingLot: ticket.ParkingLot,
+		EntryTime:  ticket.EntryTime,
+		Status:     string(ticket.Status),
+		ExitTime:   ticket.ExitTime,
+		SessionId:  sessionID(ticket),
+	}
+	if ticket.Status == model.TicketStatusOut {
+		charge := ticket.Charge
+		response.Charge = &charge
+	}
+	if ticket.Make != "" {
+		response.Make = &ticket.Make
+	}
+	if ticket.Model != "" {
+		response.Model = &ticket.Model
+	}
+	if ticket.Color != "" {
+		response.Color = &ticket.Color
+	}
+	if ticket.RefundAmount > 0 {
+		refund := ticket.RefundAmount
+		response.RefundAmount = &refund
+	}
+	if ticket.PriorCharge > 0 {
+		prior := ticket.PriorCharge
+		response.PriorCharge = &prior
+	}
+	return response
+}
+
+// ErrorHandler answers requests the generated wrappers reject, e.g. a malformed ticket ID
+// in the path or query, with an ErrorResponse instead of the generator's default body
+func (h *ParkingHandler) ErrorHandler(c *gin.Context, err error, statusCode int) {
+	h.respond(c, statusCode, api.ErrorResponse{
+		Code:    CodeValidationFailed,
+		Message: err.Error(),
+	})
+}
@@ -0,0 +1,168 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"parking-lot/internal/mocks"
+	"parking-lot/internal/model"
+	"parking-lot/server/api"
+)
+
+// setupTicketRouter registers all routes with the handler's ErrorHandler, as the Lambda adapter does
+func setupTicketRouter(mockService *mocks.ParkingService) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	handler := NewParkingHandler(mockService)
+	router := gin.New()
+	api.RegisterHandlersWithOptions(router, handler, api.GinServerOptions{ErrorHandler: handler.ErrorHandler})
+	return router
+}
+
+// TestGetTicketTicketId tests looking up a ticket by ID
+func TestGetTicketTicketId(t *testing.T) {
+	ticketID := uuid.New()
+	sessionID := uuid.New()
+	entryTime := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	exitTime := entryTime.Add(45 * time.Minute)
+
+	tests := []struct {
+		name           string
+		ticket         *model.ParkingTicket
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "Parked",
+			ticket: &model.ParkingTicket{
+				TicketID: ticketID.String(), Plate: "ABC-123", ParkingLot: 1, EntryTime: entryTime,
+				Status: model.TicketStatusIn, SessionID: sessionID.String(),
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `{"ticketId":"` + ticketID.String() + `","plate":"ABC-123","parkingLot":1,
+				"entryTime":"2024-05-01T10:00:00Z","status":"in","sessionId":"` + sessionID.String() + `"}`,
+		},
+		{
+			name: "Exited",
+			ticket: &model.ParkingTicket{
+				TicketID: ticketID.String(), Plate: "ABC-123", ParkingLot: 1, EntryTime: entryTime,
+				Status: model.TicketStatusOut, Charge: 7.5, ExitTime: &exitTime,
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `{"ticketId":"` + ticketID.String() + `","plate":"ABC-123","parkingLot":1,
+				"entryTime":"2024-05-01T10:00:00Z","status":"out","exitTime":"2024-05-01T10:45:00Z","charge":7.5}`,
+		},
+		{
+			name: "With vehicle",
+			ticket: &model.ParkingTicket{
+				TicketID: ticketID.String(), Plate: "ABC-123", ParkingLot: 1, EntryTime: entryTime,
+				Status: model.TicketStatusIn, Make: "Toyota", Model: "Corolla", Color: "Silver",
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `{"ticketId":"` + ticketID.String() + `","plate":"ABC-123","parkingLot":1,
+				"entryTime":"2024-05-01T10:00:00Z","status":"in","make":"Toyota","model":"Corolla","color":"Silver"}`,
+		},
+		{
+			name:           "Not found",
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"code":"TICKET_NOT_FOUND","message":"Ticket not found"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(mocks.ParkingService)
+			mockService.On("GetTicket", mock.Anything, ticketID.String()).Return(tt.ticket, tt.ticket != nil).Once()
+			router := setupTicketRouter(mockService)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest("GET", "/ticket/"+ticketID.String(), nil))
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			assert.JSONEq(t, tt.expectedBody, w.Body.String())
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+// TestErrorHandler tests that malformed parameters are answered with a structured ErrorResponse
+func TestErrorHandler(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		path   string
+	}{
+		{name: "Path ticket ID", method: "GET", path: "/ticket/not-a-uuid"},
+		{name: "Query ticket ID", method: "POST", path: "/exit?ticketId=not-a-uuid"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(mocks.ParkingService)
+			router := setupTicketRouter(mockService)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			var response api.ErrorResponse
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.True(t, strings.HasPrefix(response.Message, "Invalid format for parameter ticketId"), response.Message)
+			assert.Nil(t, response.TicketId)
+			mockService.AssertNotCalled(t, "GetTicket", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+// TestTicketResponse_StorageFields tests that a ticket's response carries only the fields of the
+// API's TicketResponse, none of the attributes the ticket is stored with
+func TestTicketResponse_StorageFields(t *testing.T) {
+	entryTime := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	exitTime := entryTime.Add(time.Hour)
+	ticketID := uuid.New()
+	ticket := &model.ParkingTicket{
+		TicketID:        ticketID.String(),
+		Plate:           "ABC-123",
+		ParkingLot:      3,
+		EntryTime:       entryTime,
+		ExitTime:        &exitTime,
+		Status:          model.TicketStatusOut,
+		Charge:          8,
+		SpacesUsed:      2,
+		DurationMinutes: 60,
+		SessionID:       uuid.NewString(),
+		QuoteCharge:     7.5,
+		QuoteTime:       &entryTime,
+		SpotNumber:      12,
+		Version:         4,
+		CouponCode:      "SPRING20",
+		Make:            "Toyota",
+		TransponderID:   "TRP-1",
+		RefundAmount:    2,
+		RefundTime:      &exitTime,
+	}
+
+	body, err := json.Marshal(ticketResponse(ticketID, ticket))
+	assert.NoError(t, err)
+	var fields map[string]any
+	assert.NoError(t, json.Unmarshal(body, &fields))
+
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	assert.ElementsMatch(t, []string{
+		"ticketId", "plate", "parkingLot", "entryTime", "exitTime", "status", "charge", "sessionId", "make", "refundAmount",
+	}, keys)
+	for _, internal := range []string{"version", "expiresAt", "spacesUsed", "quoteCharge", "quoteTime", "spotNumber", "couponCode", "transponderId", "refundTime", "durationMinutes"} {
+		assert.NotContains(t, fields, internal)
+	}
+}
@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"sort"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"parking-lot/internal/logger"
+	"parking-lot/internal/service"
+	"parking-lot/server/api"
+)
+
+// GetPlatePlateActive lists the lots a plate is currently parked in, with its active tickets in
+// each. Like a plate's history this tracks a vehicle, so it is only served to staff holding the
+// admin token.
+func (h *ParkingHandler) GetPlatePlateActive(c *gin.Context, plate string) {
+	ctx, span := tracer.Start(c.Request.Context(), "GetPlateActive")
+	defer span.End()
+
+	log := h.log.WithContext(ctx).WithFields(logger.Plate(plate))
+	log.Info("Looking up active sessions of plate")
+
+	if !h.authorizeAdmin(c, log) {
+		return
+	}
+
+	if h.serviceUnavailable() {
+		log.Warn("Storage unavailable, failing fast")
+		h.respondUnavailable(c)
+		return
+	}
+
+	tracker, ok := h.service.(service.PlateTracker)
+	if !ok {
+		log.Error("Service does not support plate tracking")
+		h.respond(c, http.StatusNotImplemented, api.ErrorResponse{
+			Code:    CodeNotImplemented,
+			Message: "Plate tracking is not supported",
+		})
+		return
+	}
+
+	tickets, err := tracker.ActivePlateTickets(ctx, plate)
+	if errors.Is(err, service.ErrDynamoDBUnavailable) {
+		log.Warn("Storage unavailable, failing fast")
+		h.respondUnavailable(c)
+		return
+	}
+	if err != nil {
+		log.Error("Failed to look up active sessions of plate", logger.Field{Key: "error", Value: err.Error()})
+		h.respond(c, http.StatusInternalServerError, api.ErrorResponse{
+			Code:    CodeInternalError,
+			Message: "Failed to look up active sessions",
+		})
+		return
+	}
+
+	// Group the tickets by lot, keeping their newest-first order within each lot
+	sessions := make(map[int][]api.TicketResponse)
+	for _, ticket := range tickets {
+		ticketID, err := uuid.Parse(ticket.TicketID)
+		if err != nil {
+			log.Warn("Skipping ticket with invalid ID", logger.Field{Key: "ticket_id", Value: ticket.TicketID})
+			continue
+		}
+		sessions[ticket.ParkingLot] = append(sessions[ticket.ParkingLot], ticketResponse(ticketID, ticket))
+	}
+
+	response := api.PlateActiveResponse{
+		Plate: plate,
+		Lots:  make([]api.LotSessions, 0, len(sessions)),
+	}
+	for parkingLot, lotSessions := range sessions {
+		response.Lots = append(response.Lots, api.LotSessions{ParkingLot: parkingLot, Sessions: lotSessions})
+	}
+	sort.Slice(response.Lots, func(i, j int) bool { return response.Lots[i].ParkingLot < response.Lots[j].ParkingLot })
+
+	log.Info("Active sessions of plate found", logger.Field{Key: "lots", Value: len(response.Lots)})
+	h.respond(c, http.StatusOK, response)
+}
@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"parking-lot/server/api"
+)
+
+// TestGetPlatePlateActive tests that the active sessions of a plate are grouped by lot
+func TestGetPlatePlateActive(t *testing.T) {
+	router, memoryService := setupCorrectionRouter(t)
+	ctx := context.Background()
+	first, _ := memoryService.CreateTicket(ctx, "ABC-123", 9, 1)
+	second, _ := memoryService.CreateTicket(ctx, "ABC-123", 4, 1)
+	exited, _ := memoryService.CreateTicket(ctx, "ABC-123", 5, 1)
+	memoryService.CreateTicket(ctx, "XYZ-789", 4, 1)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/exit?ticketId="+exited.String(), nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	active := func(plate string) (int, api.PlateActiveResponse) {
+		req := httptest.NewRequest("GET", "/plate/"+plate+"/active", nil)
+		req.Header.Set("Authorization", "Bearer secret")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		var response api.PlateActiveResponse
+		_ = json.Unmarshal(w.Body.Bytes(), &response)
+		return w.Code, response
+	}
+
+	t.Run("Active in two lots", func(t *testing.T) {
+		status, response := active("ABC-123")
+
+		assert.Equal(t, http.StatusOK, status)
+		assert.Equal(t, "ABC-123", response.Plate)
+		if assert.Len(t, response.Lots, 2) {
+			assert.Equal(t, 4, response.Lots[0].ParkingLot)
+			if assert.Len(t, response.Lots[0].Sessions, 1) {
+				assert.Equal(t, second, response.Lots[0].Sessions[0].TicketId)
+			}
+			assert.Equal(t, 9, response.Lots[1].ParkingLot)
+			if assert.Len(t, response.Lots[1].Sessions, 1) {
+				assert.Equal(t, first, response.Lots[1].Sessions[0].TicketId)
+			}
+		}
+	})
+
+	t.Run("Not parked anywhere", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/plate/NONE-1/active", nil)
+		req.Header.Set("Authorization", "Bearer secret")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"plate":"NONE-1","lots":[]}`, w.Body.String())
+	})
+
+	t.Run("Missing token", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/plate/ABC-123/active", nil))
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+}
@@ -0,0 +1,88 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"parking-lot/internal/logger"
+	"parking-lot/internal/model"
+	"parking-lot/internal/service"
+	"parking-lot/server/api"
+)
+
+// PostExitTransponder processes the exit of the parked vehicle entered with a transponder,
+// for gates that read transponders instead of tickets
+func (h *ParkingHandler) PostExitTransponder(c *gin.Context, params api.PostExitTransponderParams) {
+	ctx, span := tracer.Start(c.Request.Context(), "PostExitTransponder")
+	defer span.End()
+
+	log := h.log.WithContext(ctx).WithFields(
+		logger.Field{Key: "transponder_id", Value: params.TransponderId},
+	)
+	log.Info("Processing vehicle exit by transponder")
+
+	if h.serviceUnavailable() {
+		log.Warn("Storage unavailable, failing fast")
+		h.respondUnavailable(c)
+		return
+	}
+
+	lookup, ok := h.service.(service.TransponderLookup)
+	if !ok {
+		log.Error("Service does not support transponder lookups")
+		h.respond(c, http.StatusNotImplemented, api.ErrorResponse{
+			Code:    CodeNotImplemented,
+			Message: "Transponder exits are not supported",
+		})
+		return
+	}
+
+	ticket, err := lookup.FindTransponderTicket(ctx, params.TransponderId)
+	if errors.Is(err, service.ErrDynamoDBUnavailable) {
+		log.Warn("Storage unavailable, failing fast")
+		h.respondUnavailable(c)
+		return
+	}
+	if err != nil {
+		log.Error("Failed to look up transponder", logger.Field{Key: "error", Value: err.Error()})
+		h.respond(c, http.StatusInternalServerError, api.ErrorResponse{
+			Code:    CodeInternalError,
+			Message: "Failed to look up transponder",
+		})
+		return
+	}
+	if ticket == nil {
+		log.Warn("No active ticket for transponder")
+		h.respond(c, http.StatusNotFound, api.ErrorResponse{
+			Code:    CodeNoActiveTicket,
+			Message: "No active ticket for transponder",
+		})
+		return
+	}
+
+	ticketID, err := uuid.Parse(ticket.TicketID)
+	if err != nil {
+		log.Error("Ticket has an invalid ID", logger.Field{Key: "ticket_id", Value: ticket.TicketID})
+		h.respond(c, http.StatusInternalServerError, api.ErrorResponse{
+			Code:    CodeInternalError,
+			Message: "Failed to process exit",
+		})
+		return
+	}
+
+	h.exitTicket(ctx, c, log.WithFields(logger.Field{Key: "ticket_id", Value: ticket.TicketID}), ticket, ticketID, params.CouponCode)
+}
+
+// activeTransponder reports whether a vehicle entered with the transponder is still parked.
+// Services without transponder lookups report none.
+func (h *ParkingHandler) activeTransponder(ctx context.Context, transponderID string) (*model.ParkingTicket, error) {
+	lookup, ok := h.service.(service.TransponderLookup)
+	if !ok {
+		return nil, nil
+	}
+	return lookup.FindTransponderTicket(ctx, transponderID)
+}
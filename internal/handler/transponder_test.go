@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"parking-lot/internal/model"
+	"parking-lot/internal/service"
+	"parking-lot/server/api"
+)
+
+// TestTransponderEntryAndExit tests entering with a transponder and exiting by it
+func TestTransponderEntryAndExit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	svc, err := service.NewMemoryParkingLotService(context.Background())
+	require.NoError(t, err)
+	handler := NewParkingHandler(svc)
+	router := gin.New()
+	api.RegisterHandlersWithOptions(router, handler, api.GinServerOptions{ErrorHandler: handler.ErrorHandler})
+	post := func(target string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("POST", target, nil))
+		return w
+	}
+
+	// Entry with a transponder but no plate gets a placeholder plate
+	w := post("/entry?parkingLot=3&transponderId=TRP-0042")
+	require.Equal(t, http.StatusOK, w.Code)
+	var entry api.EntryResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &entry))
+	if assert.NotNil(t, entry.Plate) {
+		assert.True(t, model.IsAnonymousPlate(*entry.Plate))
+	}
+	stored, ok := svc.GetTicket(context.Background(), entry.TicketId.String())
+	require.True(t, ok)
+	assert.Equal(t, "TRP-0042", stored.TransponderID)
+
+	t.Run("Duplicate transponder entry", func(t *testing.T) {
+		w := post("/entry?plate=ABC-123&parkingLot=4&transponderId=TRP-0042")
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Contains(t, w.Body.String(), entry.TicketId.String())
+	})
+
+	t.Run("Exit by unknown transponder", func(t *testing.T) {
+		w := post("/exit/transponder?transponderId=TRP-9999")
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.JSONEq(t, `{"code":"NO_ACTIVE_TICKET","message":"No active ticket for transponder"}`, w.Body.String())
+	})
+
+	t.Run("Exit without transponder", func(t *testing.T) {
+		w := post("/exit/transponder")
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Exit by transponder", func(t *testing.T) {
+		w := post("/exit/transponder?transponderId=TRP-0042")
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var exit api.ExitResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &exit))
+		assert.Equal(t, 3, exit.ParkingLot)
+		exited, ok := svc.GetTicket(context.Background(), entry.TicketId.String())
+		require.True(t, ok)
+		assert.Equal(t, model.TicketStatusOut, exited.Status)
+
+		// Once exited, the transponder has no active session left
+		w = post("/exit/transponder?transponderId=TRP-0042")
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
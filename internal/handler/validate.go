@@ -0,0 +1,163 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"parking-lot/server/api"
+)
+
+// pathParamPattern matches OpenAPI path parameters, which Gin spells as :name instead of {name}
+var pathParamPattern = regexp.MustCompile(`\{([^}]+)\}`)
+
+func init() {
+	// Accept the same UUIDs as the generated binders, which parse them with uuid.Parse
+	openapi3.DefineStringFormatCallback("uuid", func(value string) error {
+		if _, err := uuid.Parse(value); err != nil {
+			return errors.New("must be a UUID")
+		}
+		return nil
+	})
+}
+
+// RequestValidator returns middleware validating requests to the given spec paths (e.g. "/entry")
+// against the OpenAPI document with kin-openapi's openapi3filter. Requests violating the spec are
+// rejected with 400 and an ErrorResponse listing every violation; routes outside paths pass through
+// untouched. Authentication is left to the handlers.
+//
+// aliases maps a route served under a method the spec does not list to the operation it serves,
+// e.g. "GET /quote" to "POST /quote", so the route is validated like the operation.
+func (h *ParkingHandler) RequestValidator(document []byte, basePath string, aliases map[string]string, paths ...string) (gin.HandlerFunc, error) {
+	doc, err := openapi3.NewLoader().LoadFromData(document)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse OpenAPI document: %w", err)
+	}
+	if err := doc.Validate(context.Background()); err != nil {
+		return nil, fmt.Errorf("invalid OpenAPI document: %w", err)
+	}
+
+	basePath = "/" + strings.Trim(basePath, "/")
+	routes := make(map[string]*routers.Route)
+	addRoute := func(method, path string, route *routers.Route) {
+		path = pathParamPattern.ReplaceAllString(path, ":$1")
+		routes[method+" "+path] = route
+		if basePath != "/" {
+			routes[method+" "+basePath+path] = route
+		}
+	}
+
+	for _, path := range paths {
+		item := doc.Paths.Find(path)
+		if item == nil {
+			return nil, fmt.Errorf("path %s not found in OpenAPI document", path)
+		}
+		for method, operation := range item.Operations() {
+			addRoute(method, path, &routers.Route{Spec: doc, Path: path, PathItem: item, Method: method, Operation: operation})
+		}
+	}
+
+	for alias, target := range aliases {
+		route, ok := routes[pathParamPattern.ReplaceAllString(target, ":$1")]
+		if !ok {
+			return nil, fmt.Errorf("operation %s not found in validated paths", target)
+		}
+		method, path, _ := strings.Cut(alias, " ")
+		addRoute(method, path, route)
+	}
+
+	options := &openapi3filter.Options{
+		MultiError:         true,
+		AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
+	}
+
+	return func(c *gin.Context) {
+		route, ok := routes[c.Request.Method+" "+c.FullPath()]
+		if !ok {
+			c.Next()
+			return
+		}
+
+		pathParams := make(map[string]string, len(c.Params))
+		for _, param := range c.Params {
+			pathParams[param.Key] = param.Value
+		}
+		err := openapi3filter.ValidateRequest(c.Request.Context(), &openapi3filter.RequestValidationInput{
+			Request:    c.Request,
+			PathParams: pathParams,
+			Route:      route,
+			Options:    options,
+		})
+		if err != nil {
+			details := validationDetails(err)
+			h.abort(c, http.StatusBadRequest, api.ErrorResponse{
+				Code:    CodeValidationFailed,
+				Message: "Invalid request parameters",
+				Details: &details,
+			})
+			return
+		}
+		c.Next()
+	}, nil
+}
+
+// validationDetails describes every violation in an openapi3filter error, e.g. "query parameter
+// spaces must be at least 1"
+func validationDetails(err error) []string {
+	var multi openapi3.MultiError
+	if !errors.As(err, &multi) {
+		multi = openapi3.MultiError{err}
+	}
+
+	details := make([]string, 0, len(multi))
+	for _, violation := range multi {
+		var requestErr *openapi3filter.RequestError
+		if !errors.As(violation, &requestErr) || requestErr.Parameter == nil {
+			details = append(details, violation.Error())
+			continue
+		}
+		param := requestErr.Parameter
+		details = append(details, fmt.Sprintf("%s parameter %s %s", param.In, param.Name, violationReason(requestErr)))
+	}
+	return details
+}
+
+// violationReason words why a parameter failed validation
+func violationReason(err *openapi3filter.RequestError) string {
+	var schemaErr *openapi3.SchemaError
+	var parseErr *openapi3filter.ParseError
+	switch {
+	case errors.Is(err.Err, openapi3filter.ErrInvalidRequired):
+		return "is required"
+	case errors.As(err.Err, &parseErr) && parseErr.Kind == openapi3filter.KindInvalidFormat:
+		return "must be " + article(err.Parameter.Schema.Value.Type)
+	case errors.As(err.Err, &schemaErr) && schemaErr.Origin != nil:
+		return schemaErr.Origin.Error()
+	case errors.As(err.Err, &schemaErr):
+		// kin-openapi words numeric bounds as "number must be at least 1"; the parameter already names the value
+		return strings.TrimPrefix(schemaErr.Reason, "number ")
+	default:
+		return err.Error()
+	}
+}
+
+// article prefixes a schema type with its indefinite article, e.g. "an integer";
+// untyped and oneOf schemas have no type and read as "a valid value"
+func article(schemaType string) string {
+	if schemaType == "" {
+		return "a valid value"
+	}
+	if strings.ContainsAny(schemaType[:1], "aeiou") {
+		return "an " + schemaType
+	}
+	return "a " + schemaType
+}
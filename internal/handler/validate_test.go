@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"parking-lot/internal/mocks"
+	"parking-lot/server/api"
+	"parking-lot/spec"
+)
+
+// TestRequestValidator tests validating entry and exit parameters against the OpenAPI spec
+func TestRequestValidator(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	validator, err := NewParkingHandler(new(mocks.ParkingService)).RequestValidator(spec.OpenAPI, "/prod", nil, "/entry", "/exit")
+	assert.NoError(t, err)
+
+	router := gin.New()
+	router.Use(validator)
+	for _, path := range []string{"/entry", "/exit", "/prod/entry", "/quote"} {
+		router.POST(path, func(c *gin.Context) { c.Status(http.StatusNoContent) })
+	}
+
+	tests := []struct {
+		name            string
+		url             string
+		expectedStatus  int
+		expectedDetails []string
+	}{
+		{name: "Valid entry", url: "/entry?plate=ABC-123&parkingLot=1&spaces=2", expectedStatus: http.StatusNoContent},
+		{name: "Valid exit", url: "/exit?ticketId=123e4567-e89b-12d3-a456-426614174000", expectedStatus: http.StatusNoContent},
+		{
+			name:           "Entry violating the spec",
+			url:            "/entry?parkingLot=one&spaces=0",
+			expectedStatus: http.StatusBadRequest,
+			expectedDetails: []string{
+				"query parameter parkingLot must be an integer",
+				"query parameter spaces must be at least 1",
+			},
+		},
+		{
+			name:            "Entry under the base path",
+			url:             "/prod/entry?plate=ABC-123&parkingLot=1&spaces=0",
+			expectedStatus:  http.StatusBadRequest,
+			expectedDetails: []string{"query parameter spaces must be at least 1"},
+		},
+		{
+			name:            "Exit without a ticket ID",
+			url:             "/exit",
+			expectedStatus:  http.StatusBadRequest,
+			expectedDetails: []string{"query parameter ticketId is required"},
+		},
+		{
+			name:            "Exit with a malformed ticket ID",
+			url:             "/exit?ticketId=not-a-uuid",
+			expectedStatus:  http.StatusBadRequest,
+			expectedDetails: []string{"query parameter ticketId must be a UUID"},
+		},
+		{name: "Route not validated", url: "/quote?ticketId=not-a-uuid", expectedStatus: http.StatusNoContent},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest("POST", tt.url, nil))
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedDetails != nil {
+				var response api.ErrorResponse
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				assert.Equal(t, "Invalid request parameters", response.Message)
+				assert.Equal(t, tt.expectedDetails, *response.Details)
+			}
+		})
+	}
+}
+
+// TestRequestValidator_Alias tests that a route served under another method is validated like its operation
+func TestRequestValidator_Alias(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	aliases := map[string]string{"GET /quote": "POST /quote"}
+	validator, err := NewParkingHandler(new(mocks.ParkingService)).RequestValidator(spec.OpenAPI, "/v1", aliases, "/quote")
+	assert.NoError(t, err)
+
+	router := gin.New()
+	router.Use(validator)
+	for _, path := range []string{"/quote", "/v1/quote"} {
+		router.GET(path, func(c *gin.Context) { c.Status(http.StatusNoContent) })
+		router.POST(path, func(c *gin.Context) { c.Status(http.StatusNoContent) })
+	}
+
+	for _, method := range []string{http.MethodGet, http.MethodPost} {
+		for _, path := range []string{"/quote", "/v1/quote"} {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(method, path+"?ticketId=not-a-uuid", nil))
+			assert.Equal(t, http.StatusBadRequest, w.Code, method+" "+path)
+
+			w = httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(method, path+"?ticketId=123e4567-e89b-12d3-a456-426614174000", nil))
+			assert.Equal(t, http.StatusNoContent, w.Code, method+" "+path)
+		}
+	}
+}
+
+// TestRequestValidator_UnknownPath tests that validating a path missing from the spec fails
+func TestRequestValidator_UnknownPath(t *testing.T) {
+	_, err := NewParkingHandler(new(mocks.ParkingService)).RequestValidator(spec.OpenAPI, "", nil, "/missing")
+	assert.Error(t, err)
+
+	_, err = NewParkingHandler(new(mocks.ParkingService)).RequestValidator(spec.OpenAPI, "", map[string]string{"GET /quote": "POST /quote"}, "/entry")
+	assert.Error(t, err)
+}
+
+// TestArticle tests wording schema types, including schemas without a type
+func TestArticle(t *testing.T) {
+	assert.Equal(t, "an integer", article("integer"))
+	assert.Equal(t, "a string", article("string"))
+	assert.Equal(t, "a valid value", article(""))
+}
@@ -0,0 +1,181 @@
+// Package kinesis writes entry and exit records to a Kinesis data stream for real-time analytics
+package kinesis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	awskinesis "github.com/aws/aws-sdk-go-v2/service/kinesis"
+	"github.com/aws/aws-sdk-go-v2/service/kinesis/types"
+
+	"parking-lot/internal/logger"
+)
+
+// putTimeout bounds a single PutRecords call
+const putTimeout = 5 * time.Second
+
+// maxBatchRecords is the most records a single PutRecords call accepts
+const maxBatchRecords = 500
+
+// RecordType identifies what happened to a ticket
+type RecordType string
+
+// Record types written to the stream
+const (
+	RecordEntry RecordType = "entry"
+	RecordExit  RecordType = "exit"
+)
+
+// Record is the JSON data of a stream record
+type Record struct {
+	Type            RecordType `json:"type"`
+	TicketID        string     `json:"ticketId"`
+	Plate           string     `json:"plate"`
+	ParkingLot      int        `json:"parkingLot"`
+	Time            time.Time  `json:"time"`
+	Charge          *float32   `json:"charge,omitempty"`
+	DurationMinutes *int       `json:"durationMinutes,omitempty"`
+}
+
+// Client puts batches of records on a stream, implemented by the Kinesis SDK client
+type Client interface {
+	PutRecords(ctx context.Context, params *awskinesis.PutRecordsInput, optFns ...func(*awskinesis.Options)) (*awskinesis.PutRecordsOutput, error)
+}
+
+// Producer writes records to a stream in the background, partitioned by lot so each lot's records
+// stay in order. Records put while a batch is in flight are sent together in the next batch.
+type Producer struct {
+	stream string
+	client Client
+	log    logger.Logger
+	wg     sync.WaitGroup
+
+	mu      sync.Mutex
+	pending []Record
+	data    [][]byte
+	sending bool
+}
+
+// NewProducer creates a producer writing to stream through client
+func NewProducer(stream string, client Client, log logger.Logger) *Producer {
+	return &Producer{stream: stream, client: client, log: log}
+}
+
+// NewProducerFromEnv creates a producer writing to KINESIS_STREAM with the default AWS configuration.
+// It returns nil when KINESIS_STREAM is unset or the AWS configuration cannot be loaded.
+func NewProducerFromEnv(log logger.Logger) *Producer {
+	stream := os.Getenv("KINESIS_STREAM")
+	if stream == "" {
+		return nil
+	}
+
+	cfg, err := config.LoadDefaultConfig(context.Background())
+	if err != nil {
+		log.Error("Failed to load AWS config, Kinesis records disabled", logger.Field{Key: "error", Value: err.Error()})
+		return nil
+	}
+	return NewProducer(stream, awskinesis.NewFromConfig(cfg), log)
+}
+
+// Put writes the record asynchronously. Failures are logged and never returned, so analytics
+// never hold up an entry or exit. It is safe to call on a nil producer.
+func (p *Producer) Put(record Record) {
+	if p == nil {
+		return
+	}
+
+	data, err := json.Marshal(record)
+	if err != nil {
+		p.log.Error("Failed to marshal Kinesis record", logger.Field{Key: "error", Value: err.Error()})
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.pending = append(p.pending, record)
+	p.data = append(p.data, data)
+	if !p.sending {
+		p.sending = true
+		p.wg.Add(1)
+		go p.send()
+	}
+}
+
+// send puts the pending records in batches until none are left
+func (p *Producer) send() {
+	defer p.wg.Done()
+
+	for {
+		p.mu.Lock()
+		if len(p.pending) == 0 {
+			p.sending = false
+			p.mu.Unlock()
+			return
+		}
+		n := min(len(p.pending), maxBatchRecords)
+		records, data := p.pending[:n], p.data[:n]
+		p.pending, p.data = p.pending[n:], p.data[n:]
+		p.mu.Unlock()
+
+		p.putBatch(records, data)
+	}
+}
+
+// putBatch puts records on the stream in one PutRecords call and logs every record that failed
+func (p *Producer) putBatch(records []Record, data [][]byte) {
+	entries := make([]types.PutRecordsRequestEntry, len(records))
+	for i, record := range records {
+		entries[i] = types.PutRecordsRequestEntry{
+			Data:         data[i],
+			PartitionKey: aws.String(strconv.Itoa(record.ParkingLot)),
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), putTimeout)
+	defer cancel()
+	output, err := p.client.PutRecords(ctx, &awskinesis.PutRecordsInput{
+		StreamName: aws.String(p.stream),
+		Records:    entries,
+	})
+
+	for i, record := range records {
+		failure := err
+		if failure == nil && i < len(output.Records) && output.Records[i].ErrorCode != nil {
+			failure = fmt.Errorf("%s: %s", aws.ToString(output.Records[i].ErrorCode), aws.ToString(output.Records[i].ErrorMessage))
+		}
+		if failure != nil {
+			p.log.Error("Failed to put Kinesis record",
+				logger.Field{Key: "record_type", Value: string(record.Type)},
+				logger.Field{Key: "ticket_id", Value: record.TicketID},
+				logger.Field{Key: "error", Value: failure.Error()},
+			)
+			continue
+		}
+		p.log.Debug("Put Kinesis record",
+			logger.Field{Key: "record_type", Value: string(record.Type)},
+			logger.Field{Key: "ticket_id", Value: record.TicketID},
+		)
+	}
+}
+
+// Wait blocks until all pending records have been written
+func (p *Producer) Wait() {
+	if p == nil {
+		return
+	}
+	p.wg.Wait()
+}
+
+// Close waits for pending records, so none are lost when the process is stopped or frozen.
+// The producer stays usable afterwards.
+func (p *Producer) Close() error {
+	p.Wait()
+	return nil
+}
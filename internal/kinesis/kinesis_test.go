@@ -0,0 +1,129 @@
+package kinesis
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awskinesis "github.com/aws/aws-sdk-go-v2/service/kinesis"
+	"github.com/aws/aws-sdk-go-v2/service/kinesis/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"parking-lot/internal/mocks"
+)
+
+// TestPut tests that the record is put on the stream partitioned by its lot
+func TestPut(t *testing.T) {
+	charge := float32(7.5)
+	minutes := 45
+	exitTime := time.Date(2024, 5, 1, 10, 45, 0, 0, time.UTC)
+
+	client := new(mocks.KinesisClient)
+	client.On("PutRecords", mock.Anything, mock.MatchedBy(func(input *awskinesis.PutRecordsInput) bool {
+		return *input.StreamName == "parking-events" && len(input.Records) == 1 &&
+			*input.Records[0].PartitionKey == "382" &&
+			assert.JSONEq(t, `{
+				"type": "exit",
+				"ticketId": "ticket-1",
+				"plate": "ABC-123",
+				"parkingLot": 382,
+				"time": "2024-05-01T10:45:00Z",
+				"charge": 7.5,
+				"durationMinutes": 45
+			}`, string(input.Records[0].Data))
+	}), mock.Anything).Return(&awskinesis.PutRecordsOutput{Records: []types.PutRecordsResultEntry{{}}}, nil).Once()
+
+	producer := NewProducer("parking-events", client, mocks.NewLogger())
+	producer.Put(Record{
+		Type:            RecordExit,
+		TicketID:        "ticket-1",
+		Plate:           "ABC-123",
+		ParkingLot:      382,
+		Time:            exitTime,
+		Charge:          &charge,
+		DurationMinutes: &minutes,
+	})
+	producer.Wait()
+
+	client.AssertExpectations(t)
+}
+
+// TestPut_Batch tests that records put while a batch is in flight are sent together in the next batch
+func TestPut_Batch(t *testing.T) {
+	started, release := make(chan struct{}), make(chan struct{})
+	client := new(mocks.KinesisClient)
+	client.On("PutRecords", mock.Anything, mock.MatchedBy(func(input *awskinesis.PutRecordsInput) bool {
+		return len(input.Records) == 1
+	}), mock.Anything).Run(func(mock.Arguments) { close(started); <-release }).
+		Return(&awskinesis.PutRecordsOutput{Records: make([]types.PutRecordsResultEntry, 1)}, nil).Once()
+	client.On("PutRecords", mock.Anything, mock.MatchedBy(func(input *awskinesis.PutRecordsInput) bool {
+		return len(input.Records) == 2 && *input.Records[0].PartitionKey == "2" && *input.Records[1].PartitionKey == "3"
+	}), mock.Anything).Return(&awskinesis.PutRecordsOutput{Records: make([]types.PutRecordsResultEntry, 2)}, nil).Once()
+
+	producer := NewProducer("parking-events", client, mocks.NewLogger())
+	producer.Put(Record{Type: RecordEntry, TicketID: "ticket-1", ParkingLot: 1})
+	<-started
+	producer.Put(Record{Type: RecordEntry, TicketID: "ticket-2", ParkingLot: 2})
+	producer.Put(Record{Type: RecordEntry, TicketID: "ticket-3", ParkingLot: 3})
+	close(release)
+	producer.Wait()
+
+	client.AssertExpectations(t)
+}
+
+// TestPut_Failure tests that a failed put is logged rather than returned
+func TestPut_Failure(t *testing.T) {
+	client := new(mocks.KinesisClient)
+	client.On("PutRecords", mock.Anything, mock.Anything, mock.Anything).
+		Return(nil, errors.New("throttled")).Once()
+
+	log := mocks.NewLogger()
+	producer := NewProducer("parking-events", client, log)
+	producer.Put(Record{Type: RecordEntry, TicketID: "ticket-1", ParkingLot: 5})
+	producer.Wait()
+
+	failures := log.Find("Failed to put Kinesis record")
+	if assert.Len(t, failures, 1) {
+		assert.Equal(t, "throttled", failures[0].Fields["error"])
+	}
+}
+
+// TestPut_RecordFailure tests that records the stream rejected within a batch are logged
+func TestPut_RecordFailure(t *testing.T) {
+	client := new(mocks.KinesisClient)
+	client.On("PutRecords", mock.Anything, mock.Anything, mock.Anything).Return(&awskinesis.PutRecordsOutput{
+		FailedRecordCount: aws.Int32(1),
+		Records: []types.PutRecordsResultEntry{{
+			ErrorCode:    aws.String("ProvisionedThroughputExceededException"),
+			ErrorMessage: aws.String("Rate exceeded for shard"),
+		}},
+	}, nil).Once()
+
+	log := mocks.NewLogger()
+	producer := NewProducer("parking-events", client, log)
+	producer.Put(Record{Type: RecordEntry, TicketID: "ticket-1", ParkingLot: 5})
+	producer.Wait()
+
+	failures := log.Find("Failed to put Kinesis record")
+	if assert.Len(t, failures, 1) {
+		assert.Equal(t, "ticket-1", failures[0].Fields["ticket_id"])
+		assert.Equal(t, "ProvisionedThroughputExceededException: Rate exceeded for shard", failures[0].Fields["error"])
+	}
+}
+
+// TestNewProducerFromEnv tests that the producer is only created when KINESIS_STREAM is set
+func TestNewProducerFromEnv(t *testing.T) {
+	t.Setenv("KINESIS_STREAM", "")
+	assert.Nil(t, NewProducerFromEnv(mocks.NewLogger()))
+
+	// A nil producer ignores records
+	var producer *Producer
+	producer.Put(Record{Type: RecordEntry})
+	producer.Wait()
+
+	t.Setenv("KINESIS_STREAM", "parking-events")
+	t.Setenv("AWS_REGION", "us-east-1")
+	assert.NotNil(t, NewProducerFromEnv(mocks.NewLogger()))
+}
@@ -0,0 +1,44 @@
+package logger
+
+import (
+	"net/http"
+	"os"
+	"strings"
+)
+
+// Redacted replaces the value of a sensitive header in logs
+const Redacted = "[REDACTED]"
+
+// defaultSensitiveHeaders are redacted when LOG_REDACT_HEADERS is unset
+const defaultSensitiveHeaders = "Authorization,X-API-Key,X-Admin-Key"
+
+// Headers returns the headers log field with the values of sensitive headers redacted.
+// The sensitive headers are the comma-separated LOG_REDACT_HEADERS, defaulting to Authorization, X-API-Key and X-Admin-Key.
+func Headers(header http.Header) Field {
+	sensitive := SensitiveHeaders()
+	values := make(map[string]string, len(header))
+	for name, value := range header {
+		name = http.CanonicalHeaderKey(name)
+		if sensitive[name] {
+			values[name] = Redacted
+			continue
+		}
+		values[name] = strings.Join(value, ", ")
+	}
+	return Field{Key: "headers", Value: values}
+}
+
+// SensitiveHeaders returns the canonical names of the headers redacted in logs
+func SensitiveHeaders() map[string]bool {
+	list := os.Getenv("LOG_REDACT_HEADERS")
+	if list == "" {
+		list = defaultSensitiveHeaders
+	}
+	sensitive := make(map[string]bool)
+	for _, name := range strings.Split(list, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			sensitive[http.CanonicalHeaderKey(name)] = true
+		}
+	}
+	return sensitive
+}
@@ -3,11 +3,15 @@ package logger
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/rs/zerolog"
+
+	"parking-lot/internal/env"
 )
 
 // Field represents a log field key-value pair
@@ -22,20 +26,76 @@ type Logger interface {
 	Info(msg string, fields ...Field)
 	Warn(msg string, fields ...Field)
 	Error(msg string, fields ...Field)
+	// Fatal logs at fatal level and exits the process with status 1
 	Fatal(msg string, fields ...Field)
 	WithContext(ctx context.Context) Logger
 	WithRequestID(requestID string) Logger
 	WithFields(fields ...Field) Logger
 }
 
+// requestIDKey is the context key of the request ID
+type requestIDKey struct{}
+
+// ContextWithRequestID returns a copy of ctx carrying the request ID, which loggers derived
+// from it with WithContext attach to every line
+func ContextWithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestIDFromContext returns the request ID carried by ctx, or "" when there is none
+func RequestIDFromContext(ctx context.Context) string {
+	if requestID, ok := ctx.Value(requestIDKey{}).(string); ok {
+		return requestID
+	}
+	// Accept IDs stored under the plain string key used before ContextWithRequestID
+	requestID, _ := ctx.Value("requestID").(string)
+	return requestID
+}
+
 type zerologLogger struct {
 	log zerolog.Logger
 }
 
 // NewLogger creates a new logger instance
 func NewLogger() Logger {
+	stdout := newStdoutWriter(os.Stdout)
+
+	// LOG_FILE tees structured JSON logs to a file, e.g. for local debugging
+	out := stdout
+	path := os.Getenv("LOG_FILE")
+	file, first, openErr := openLogFile(path)
+	if file != nil {
+		out = zerolog.MultiLevelWriter(stdout, file)
+	}
+
+	logger := zerolog.New(out).
+		With().
+		Timestamp().
+		Caller().
+		Logger()
+
+	if openErr != nil && first {
+		logger.Warn().Err(openErr).Str("log_file", path).Msg("Failed to open log file, logging to stdout only")
+	}
+
+	return &zerologLogger{log: logger}
+}
+
+// newStdoutWriter returns the writer of the log lines sent to out: human-readable console lines, or
+// newline-delimited JSON when LOG_CONSOLE is false, so tools such as lnav or jq can read them
+func newStdoutWriter(out io.Writer) io.Writer {
+	if os.Getenv("LOG_CONSOLE") == "false" {
+		return out
+	}
+	return newConsoleWriter(out)
+}
+
+// newConsoleWriter creates the human-readable writer of log lines. Inside Lambda the lines end up
+// in CloudWatch, which does not render terminal colors, so they are written without them.
+func newConsoleWriter(out io.Writer) zerolog.ConsoleWriter {
 	consoleWriter := zerolog.ConsoleWriter{
-		Out:        os.Stdout,
+		Out:        out,
+		NoColor:    env.IsLambda(),
 		TimeFormat: time.RFC3339,
 		FormatLevel: func(i interface{}) string {
 			if level, ok := i.(zerolog.Level); ok {
@@ -51,7 +111,7 @@ func NewLogger() Logger {
 	}
 
 	// Set a more readable format for local development
-	if os.Getenv("AWS_EXECUTION_ENV") == "" {
+	if !env.IsLambda() {
 		consoleWriter.FormatLevel = func(i interface{}) string {
 			if level, ok := i.(zerolog.Level); ok {
 				return level.String()
@@ -59,14 +119,41 @@ func NewLogger() Logger {
 			return fmt.Sprintf("%v", i)
 		}
 	}
+	return consoleWriter
+}
 
-	logger := zerolog.New(consoleWriter).
-		With().
-		Timestamp().
-		Caller().
-		Logger()
+var (
+	logFilesMu sync.Mutex
+	// logFiles holds the open log files by path, so every logger appends to the same file;
+	// nil marks a path that failed to open
+	logFiles = make(map[string]*os.File)
+)
 
-	return &zerologLogger{log: logger}
+// openLogFile opens the log file at path for appending, reusing it across loggers. An empty path
+// opens nothing. first reports whether this call was the first to try the path, so a failure
+// is only warned about once.
+func openLogFile(path string) (file *os.File, first bool, err error) {
+	if path == "" {
+		return nil, false, nil
+	}
+
+	logFilesMu.Lock()
+	defer logFilesMu.Unlock()
+
+	if file, ok := logFiles[path]; ok {
+		if file == nil {
+			return nil, false, fmt.Errorf("failed to open log file %s", path)
+		}
+		return file, false, nil
+	}
+
+	file, err = os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		logFiles[path] = nil
+		return nil, true, fmt.Errorf("failed to open log file: %w", err)
+	}
+	logFiles[path] = file
+	return file, true, nil
 }
 
 func (l *zerologLogger) Debug(msg string, fields ...Field) {
@@ -85,13 +172,15 @@ func (l *zerologLogger) Error(msg string, fields ...Field) {
 	l.logWithLevel(zerolog.ErrorLevel, msg, fields...)
 }
 
+// Fatal logs the message and exits the process with status 1
 func (l *zerologLogger) Fatal(msg string, fields ...Field) {
 	l.logWithLevel(zerolog.FatalLevel, msg, fields...)
+	os.Exit(1)
 }
 
 func (l *zerologLogger) WithContext(ctx context.Context) Logger {
 	// Extract request ID from context if available
-	requestID, _ := ctx.Value("requestID").(string)
+	requestID := RequestIDFromContext(ctx)
 	if requestID == "" {
 		requestID = uuid.New().String()
 	}
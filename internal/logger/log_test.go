@@ -1,9 +1,16 @@
 package logger
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -109,3 +116,164 @@ func TestWithFields(t *testing.T) {
 		fieldsLogger.Info("Test message with fields")
 	})
 }
+
+// TestMaskPlate tests plate masking
+func TestMaskPlate(t *testing.T) {
+	assert.Equal(t, "AB****23", MaskPlate("AB123423"))
+	assert.Equal(t, "AB***23", MaskPlate("AB-1-23"))
+	assert.Equal(t, "****", MaskPlate("AB12"))
+	assert.Equal(t, "", MaskPlate(""))
+}
+
+// TestPlate tests the plate field honours LOG_PLATE_MASK
+func TestPlate(t *testing.T) {
+	t.Run("Unmasked by default", func(t *testing.T) {
+		t.Setenv("LOG_PLATE_MASK", "")
+
+		assert.Equal(t, Field{Key: "plate", Value: "AB123423"}, Plate("AB123423"))
+	})
+
+	t.Run("Masked when enabled", func(t *testing.T) {
+		t.Setenv("LOG_PLATE_MASK", "true")
+
+		assert.Equal(t, Field{Key: "plate", Value: "AB****23"}, Plate("AB123423"))
+	})
+}
+
+// TestPath tests the plate segment of plate routes honours LOG_PLATE_MASK
+func TestPath(t *testing.T) {
+	t.Run("Unmasked by default", func(t *testing.T) {
+		t.Setenv("LOG_PLATE_MASK", "")
+
+		assert.Equal(t, Field{Key: "path", Value: "/plate/AB123423/history"}, Path("/plate/AB123423/history"))
+	})
+
+	t.Run("Masked when enabled", func(t *testing.T) {
+		t.Setenv("LOG_PLATE_MASK", "true")
+
+		assert.Equal(t, Field{Key: "path", Value: "/v1/plate/AB****23/active"}, Path("/v1/plate/AB123423/active"))
+		assert.Equal(t, Field{Key: "path", Value: "/ticket/abc"}, Path("/ticket/abc"))
+		assert.Equal(t, Field{Key: "path", Value: "/plate/"}, Path("/plate/"))
+	})
+}
+
+// TestHeaders tests that sensitive headers are redacted, honouring LOG_REDACT_HEADERS
+func TestHeaders(t *testing.T) {
+	header := http.Header{}
+	header.Set("Authorization", "Bearer secret")
+	header.Set("X-API-Key", "key-123")
+	header.Set("X-Session-Token", "session")
+	header.Add("Accept", "text/csv")
+	header.Add("Accept", "application/json")
+
+	t.Run("Default headers", func(t *testing.T) {
+		t.Setenv("LOG_REDACT_HEADERS", "")
+
+		assert.Equal(t, Field{Key: "headers", Value: map[string]string{
+			"Authorization":   Redacted,
+			"X-Api-Key":       Redacted,
+			"X-Session-Token": "session",
+			"Accept":          "text/csv, application/json",
+		}}, Headers(header))
+	})
+
+	t.Run("Configured headers", func(t *testing.T) {
+		t.Setenv("LOG_REDACT_HEADERS", "x-session-token")
+
+		assert.Equal(t, Field{Key: "headers", Value: map[string]string{
+			"Authorization":   "Bearer secret",
+			"X-Api-Key":       "key-123",
+			"X-Session-Token": Redacted,
+			"Accept":          "text/csv, application/json",
+		}}, Headers(header))
+	})
+}
+
+// TestLogFile tests that LOG_FILE tees structured logs to the file
+func TestLogFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "parking.log")
+	t.Setenv("LOG_FILE", path)
+
+	NewLogger().Info("Written to file", Field{Key: "parking_lot", Value: 7})
+
+	content, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	var line map[string]interface{}
+	if assert.NoError(t, json.Unmarshal(bytes.TrimSpace(content), &line)) {
+		assert.Equal(t, "Written to file", line["message"])
+		assert.Equal(t, "info", line["level"])
+		assert.Equal(t, float64(7), line["parking_lot"])
+	}
+}
+
+// TestLogFile_OpenFailure tests that a log file that cannot be opened falls back to stdout
+func TestLogFile_OpenFailure(t *testing.T) {
+	t.Setenv("LOG_FILE", filepath.Join(t.TempDir(), "missing", "parking.log"))
+
+	logger := NewLogger()
+
+	assert.NotNil(t, logger)
+	assert.NotPanics(t, func() {
+		logger.Info("Written to stdout only")
+	})
+}
+
+// TestNewConsoleWriter tests that log lines are colored locally and plain inside Lambda
+func TestNewConsoleWriter(t *testing.T) {
+	tests := []struct {
+		name      string
+		execution string
+		colored   bool
+	}{
+		{name: "Local", execution: "", colored: true},
+		{name: "Lambda", execution: "AWS_Lambda_provided.al2", colored: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("AWS_EXECUTION_ENV", tt.execution)
+			var buf bytes.Buffer
+
+			_, err := newConsoleWriter(&buf).Write([]byte(`{"level":"info","message":"Formatted"}`))
+
+			assert.NoError(t, err)
+			assert.Contains(t, buf.String(), "Formatted")
+			assert.Equal(t, tt.colored, strings.Contains(buf.String(), "\x1b["))
+		})
+	}
+}
+
+// TestNewStdoutWriter tests that LOG_CONSOLE=false writes JSON lines instead of console lines
+func TestNewStdoutWriter(t *testing.T) {
+	tests := []struct {
+		name    string
+		console string
+		json    bool
+	}{
+		{name: "Default", console: "", json: false},
+		{name: "Console enabled", console: "true", json: false},
+		{name: "Console disabled", console: "false", json: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("LOG_CONSOLE", tt.console)
+			var buf bytes.Buffer
+
+			log := zerolog.New(newStdoutWriter(&buf))
+			log.Info().Int("parking_lot", 7).Msg("Written to stdout")
+
+			var line map[string]interface{}
+			err := json.Unmarshal(bytes.TrimSpace(buf.Bytes()), &line)
+			assert.Equal(t, tt.json, err == nil, buf.String())
+			if tt.json {
+				assert.Equal(t, "Written to stdout", line["message"])
+				assert.Equal(t, "info", line["level"])
+				assert.Equal(t, float64(7), line["parking_lot"])
+			} else {
+				assert.Contains(t, buf.String(), "Written to stdout")
+				assert.Contains(t, buf.String(), "parking_lot=")
+			}
+		})
+	}
+}
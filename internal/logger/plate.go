@@ -0,0 +1,41 @@
+package logger
+
+import (
+	"os"
+	"strings"
+)
+
+// Plate returns the plate log field, masked when LOG_PLATE_MASK is enabled.
+// Only the log output is affected; stored tickets and responses keep the full plate.
+func Plate(plate string) Field {
+	if os.Getenv("LOG_PLATE_MASK") == "true" {
+		plate = MaskPlate(plate)
+	}
+	return Field{Key: "plate", Value: plate}
+}
+
+// MaskPlate keeps the first and last two characters of a plate and masks the rest,
+// e.g. AB123423 becomes AB****23. Plates of four characters or fewer are fully masked.
+func MaskPlate(plate string) string {
+	runes := []rune(plate)
+	if len(runes) <= 4 {
+		return strings.Repeat("*", len(runes))
+	}
+	return string(runes[:2]) + strings.Repeat("*", len(runes)-4) + string(runes[len(runes)-2:])
+}
+
+// Path returns the path log field, with the plate segment of plate routes such as
+// /plate/{plate}/history masked when LOG_PLATE_MASK is enabled
+func Path(path string) Field {
+	if os.Getenv("LOG_PLATE_MASK") == "true" {
+		segments := strings.Split(path, "/")
+		for i := 0; i < len(segments)-1; i++ {
+			if segments[i] == "plate" && segments[i+1] != "" {
+				segments[i+1] = MaskPlate(segments[i+1])
+				i++
+			}
+		}
+		path = strings.Join(segments, "/")
+	}
+	return Field{Key: "path", Value: path}
+}
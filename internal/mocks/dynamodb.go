@@ -84,3 +84,30 @@ func (m *DynamoDBClient) BatchGetItem(ctx context.Context, params *dynamodb.Batc
 	}
 	return args.Get(0).(*dynamodb.BatchGetItemOutput), args.Error(1)
 }
+
+// TransactWriteItems mocks the TransactWriteItems method
+func (m *DynamoDBClient) TransactWriteItems(ctx context.Context, params *dynamodb.TransactWriteItemsInput, optFns ...func(*dynamodb.Options)) (*dynamodb.TransactWriteItemsOutput, error) {
+	args := m.Called(ctx, params, optFns)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dynamodb.TransactWriteItemsOutput), args.Error(1)
+}
+
+// CreateTable mocks the CreateTable method
+func (m *DynamoDBClient) CreateTable(ctx context.Context, params *dynamodb.CreateTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.CreateTableOutput, error) {
+	args := m.Called(ctx, params, optFns)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dynamodb.CreateTableOutput), args.Error(1)
+}
+
+// DescribeTable mocks the DescribeTable method
+func (m *DynamoDBClient) DescribeTable(ctx context.Context, params *dynamodb.DescribeTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DescribeTableOutput, error) {
+	args := m.Called(ctx, params, optFns)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dynamodb.DescribeTableOutput), args.Error(1)
+}
@@ -0,0 +1,22 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/service/kinesis"
+	"github.com/stretchr/testify/mock"
+)
+
+// KinesisClient is a mock implementation of the Kinesis client interface
+type KinesisClient struct {
+	mock.Mock
+}
+
+// PutRecords mocks the PutRecords method
+func (m *KinesisClient) PutRecords(ctx context.Context, params *kinesis.PutRecordsInput, optFns ...func(*kinesis.Options)) (*kinesis.PutRecordsOutput, error) {
+	args := m.Called(ctx, params, optFns)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*kinesis.PutRecordsOutput), args.Error(1)
+}
@@ -0,0 +1,95 @@
+// Package mocks provides mock implementations for testing
+package mocks
+
+import (
+	"context"
+	"sync"
+
+	"parking-lot/internal/logger"
+)
+
+// LogEntry is a single message captured by Logger
+type LogEntry struct {
+	Level   string
+	Message string
+	Fields  map[string]interface{}
+}
+
+// Logger is a logger.Logger that records every message for later assertions
+type Logger struct {
+	mu      *sync.Mutex
+	entries *[]LogEntry
+	fields  []logger.Field
+}
+
+// NewLogger creates an empty recording logger
+func NewLogger() *Logger {
+	return &Logger{
+		mu:      &sync.Mutex{},
+		entries: &[]LogEntry{},
+	}
+}
+
+// Entries returns the messages recorded so far by this logger and all loggers derived from it
+func (l *Logger) Entries() []LogEntry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return append([]LogEntry(nil), *l.entries...)
+}
+
+// Find returns the recorded entries with the given message
+func (l *Logger) Find(msg string) []LogEntry {
+	var found []LogEntry
+	for _, entry := range l.Entries() {
+		if entry.Message == msg {
+			found = append(found, entry)
+		}
+	}
+	return found
+}
+
+// Debug records a debug message
+func (l *Logger) Debug(msg string, fields ...logger.Field) { l.record("debug", msg, fields) }
+
+// Info records an info message
+func (l *Logger) Info(msg string, fields ...logger.Field) { l.record("info", msg, fields) }
+
+// Warn records a warning message
+func (l *Logger) Warn(msg string, fields ...logger.Field) { l.record("warn", msg, fields) }
+
+// Error records an error message
+func (l *Logger) Error(msg string, fields ...logger.Field) { l.record("error", msg, fields) }
+
+// Fatal records a fatal message without exiting
+func (l *Logger) Fatal(msg string, fields ...logger.Field) { l.record("fatal", msg, fields) }
+
+// WithContext returns a derived logger carrying the request ID of ctx, or the logger unchanged when there is none
+func (l *Logger) WithContext(ctx context.Context) logger.Logger {
+	if requestID := logger.RequestIDFromContext(ctx); requestID != "" {
+		return l.WithRequestID(requestID)
+	}
+	return l
+}
+
+// WithRequestID returns a derived logger carrying the request ID
+func (l *Logger) WithRequestID(requestID string) logger.Logger {
+	return l.WithFields(logger.Field{Key: "request_id", Value: requestID})
+}
+
+// WithFields returns a derived logger carrying the given fields
+func (l *Logger) WithFields(fields ...logger.Field) logger.Logger {
+	merged := append(append([]logger.Field(nil), l.fields...), fields...)
+	return &Logger{mu: l.mu, entries: l.entries, fields: merged}
+}
+
+func (l *Logger) record(level, msg string, fields []logger.Field) {
+	entry := LogEntry{Level: level, Message: msg, Fields: make(map[string]interface{})}
+	for _, field := range append(append([]logger.Field(nil), l.fields...), fields...) {
+		entry.Fields[field.Key] = field.Value
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	*l.entries = append(*l.entries, entry)
+}
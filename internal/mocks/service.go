@@ -17,8 +17,11 @@ type ParkingService struct {
 }
 
 // CreateTicket mocks the ticket creation
-func (m *ParkingService) CreateTicket(ctx context.Context, plate string, parkingLot int) (uuid.UUID, *model.ParkingTicket) {
-	args := m.Called(ctx, plate, parkingLot)
+func (m *ParkingService) CreateTicket(ctx context.Context, plate string, parkingLot int, spaces int) (uuid.UUID, *model.ParkingTicket) {
+	args := m.Called(ctx, plate, parkingLot, spaces)
+	if args.Get(1) == nil {
+		return args.Get(0).(uuid.UUID), nil
+	}
 	return args.Get(0).(uuid.UUID), args.Get(1).(*model.ParkingTicket)
 }
 
@@ -31,6 +34,30 @@ func (m *ParkingService) GetTicket(ctx context.Context, ticketID string) (*model
 	return args.Get(0).(*model.ParkingTicket), args.Bool(1)
 }
 
+// FindActiveTicket mocks the active ticket lookup
+func (m *ParkingService) FindActiveTicket(ctx context.Context, plate string, parkingLot int) (*model.ParkingTicket, error) {
+	args := m.Called(ctx, plate, parkingLot)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.ParkingTicket), args.Error(1)
+}
+
+// FindReentryTicket mocks the re-entry ticket lookup
+func (m *ParkingService) FindReentryTicket(ctx context.Context, plate string, parkingLot int) (*model.ParkingTicket, error) {
+	args := m.Called(ctx, plate, parkingLot)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.ParkingTicket), args.Error(1)
+}
+
+// ReopenTicket mocks reopening an exited ticket
+func (m *ParkingService) ReopenTicket(ctx context.Context, ticket *model.ParkingTicket) error {
+	args := m.Called(ctx, ticket)
+	return args.Error(0)
+}
+
 // RemoveTicket mocks ticket removal
 func (m *ParkingService) RemoveTicket(ctx context.Context, ticketID string) {
 	m.Called(ctx, ticketID)
@@ -42,8 +69,43 @@ func (m *ParkingService) CalculateCharge(entryTime time.Time) (int, float32) {
 	return args.Int(0), args.Get(1).(float32)
 }
 
+// CalculateChargeDetailed mocks detailed charge calculation
+func (m *ParkingService) CalculateChargeDetailed(parkingLot int, entryTime time.Time) (time.Duration, int, float32) {
+	args := m.Called(parkingLot, entryTime)
+	return args.Get(0).(time.Duration), args.Int(1), args.Get(2).(float32)
+}
+
+// CalculateChargeBetween mocks charge calculation for a finished stay
+func (m *ParkingService) CalculateChargeBetween(parkingLot int, entryTime, exitTime time.Time) (int, float32) {
+	args := m.Called(parkingLot, entryTime, exitTime)
+	return args.Int(0), args.Get(1).(float32)
+}
+
 // UpdateTicket mocks the ticket update
 func (m *ParkingService) UpdateTicket(ctx context.Context, ticket *model.ParkingTicket) error {
 	args := m.Called(ctx, ticket)
 	return args.Error(0)
 }
+
+// ReserveSpaces mocks reserving spaces in a lot
+func (m *ParkingService) ReserveSpaces(ctx context.Context, parkingLot int, spaces int) error {
+	args := m.Called(ctx, parkingLot, spaces)
+	return args.Error(0)
+}
+
+// ReleaseSpaces mocks releasing spaces in a lot
+func (m *ParkingService) ReleaseSpaces(ctx context.Context, parkingLot int, spaces int) {
+	m.Called(ctx, parkingLot, spaces)
+}
+
+// ExitWindow mocks the kiosk quote window lookup
+func (m *ParkingService) ExitWindow() time.Duration {
+	args := m.Called()
+	return args.Get(0).(time.Duration)
+}
+
+// Rates mocks the rate schedule lookup
+func (m *ParkingService) Rates() model.RateSchedule {
+	args := m.Called()
+	return args.Get(0).(model.RateSchedule)
+}
@@ -0,0 +1,32 @@
+package model
+
+import (
+	"math"
+	"time"
+)
+
+// Coupon is a promo code that discounts the charge at exit by a percentage or a fixed amount
+type Coupon struct {
+	Code string `dynamodbav:"code" json:"code"`
+	// PercentOff discounts the charge by a percentage, e.g. 20 for 20% off
+	PercentOff float32 `dynamodbav:"percentOff,omitempty" json:"percentOff,omitempty"`
+	// AmountOff discounts the charge by a fixed amount in the charge's currency
+	AmountOff float32 `dynamodbav:"amountOff,omitempty" json:"amountOff,omitempty"`
+	// ExpiresAt is when the coupon stops being accepted; coupons without one never expire
+	ExpiresAt *time.Time `dynamodbav:"expiresAt,omitempty" json:"expiresAt,omitempty"`
+}
+
+// Expired reports whether the coupon is no longer accepted at now
+func (c Coupon) Expired(now time.Time) bool {
+	return c.ExpiresAt != nil && !now.Before(*c.ExpiresAt)
+}
+
+// Apply returns charge after the discount, rounded to cents. Discounts never make a charge negative.
+func (c Coupon) Apply(charge float32) float32 {
+	discounted := float64(charge)
+	if c.PercentOff > 0 {
+		discounted *= 1 - math.Min(float64(c.PercentOff), 100)/100
+	}
+	discounted = math.Max(discounted-float64(c.AmountOff), 0)
+	return float32(math.Round(discounted*100) / 100)
+}
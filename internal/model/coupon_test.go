@@ -0,0 +1,28 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestCoupon_Apply tests discounting charges by a percentage and a fixed amount
+func TestCoupon_Apply(t *testing.T) {
+	assert.Equal(t, float32(6), Coupon{PercentOff: 20}.Apply(7.5))
+	assert.Equal(t, float32(5.5), Coupon{AmountOff: 2}.Apply(7.5))
+	assert.Equal(t, float32(0.38), Coupon{PercentOff: 25}.Apply(0.5))
+	// Discounts stop at free parking
+	assert.Equal(t, float32(0), Coupon{AmountOff: 10}.Apply(7.5))
+	assert.Equal(t, float32(0), Coupon{PercentOff: 150}.Apply(7.5))
+}
+
+// TestCoupon_Expired tests that coupons expire at their expiry time and coupons without one never do
+func TestCoupon_Expired(t *testing.T) {
+	expiresAt := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	coupon := Coupon{ExpiresAt: &expiresAt}
+
+	assert.False(t, coupon.Expired(expiresAt.Add(-time.Second)))
+	assert.True(t, coupon.Expired(expiresAt))
+	assert.False(t, Coupon{}.Expired(expiresAt))
+}
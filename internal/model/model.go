@@ -1,6 +1,7 @@
 package model
 
 import (
+	"strings"
 	"time"
 )
 
@@ -15,6 +16,9 @@ const (
 	TicketStatusOut TicketStatus = "out"
 )
 
+// AnonymousPlatePrefix starts the placeholder plate of a ticket issued without a plate
+const AnonymousPlatePrefix = "ANON-"
+
 // ParkingTicket represents a parking session
 type ParkingTicket struct {
 	TicketID   string       `dynamodbav:"ticketId" json:"ticketId"`
@@ -23,4 +27,278 @@ type ParkingTicket struct {
 	EntryTime  time.Time    `dynamodbav:"entryTime" json:"entryTime"`
 	Status     TicketStatus `dynamodbav:"status,omitempty" json:"status,omitempty"`
 	Charge     float32      `dynamodbav:"charge,omitempty" json:"charge,omitempty"`
+	SpacesUsed int          `dynamodbav:"spacesUsed,omitempty" json:"spacesUsed,omitempty"`
+	// DurationMinutes is the parked duration recorded at exit
+	DurationMinutes int `dynamodbav:"durationMinutes,omitempty" json:"durationMinutes,omitempty"`
+	// ExitTime is when the vehicle last exited; it is cleared when the ticket is reopened
+	ExitTime *time.Time `dynamodbav:"exitTime,omitempty" json:"exitTime,omitempty"`
+	// SessionID identifies the parking session for analytics joins; it is set at entry and never changes,
+	// including when the ticket is reopened. Tickets issued before sessions were introduced have none.
+	SessionID string `dynamodbav:"sessionId,omitempty" json:"sessionId,omitempty"`
+	// QuoteCharge is the charge last quoted at a kiosk and QuoteTime when it was computed
+	QuoteCharge float32    `dynamodbav:"quoteCharge,omitempty" json:"quoteCharge,omitempty"`
+	QuoteTime   *time.Time `dynamodbav:"quoteTime,omitempty" json:"quoteTime,omitempty"`
+	// Anonymous marks a ticket issued without a plate, whose Plate is a generated placeholder
+	Anonymous bool `dynamodbav:"anonymous,omitempty" json:"anonymous,omitempty"`
+	// SpotNumber is the numbered spot assigned at entry in lots that assign them; it is freed at exit
+	SpotNumber int `dynamodbav:"spotNumber,omitempty" json:"spotNumber,omitempty"`
+	// Version counts the stored updates of the ticket; with VERSIONED_UPDATES an update is only
+	// written while the stored version still matches, so concurrent updates cannot overwrite each other
+	Version int `dynamodbav:"version,omitempty" json:"version,omitempty"`
+	// CouponCode is the promo code that discounted the charge at exit
+	CouponCode string `dynamodbav:"couponCode,omitempty" json:"couponCode,omitempty"`
+	// Make, Model and Color optionally describe the vehicle for security staff; they are set at entry
+	Make  string `dynamodbav:"make,omitempty" json:"make,omitempty"`
+	Model string `dynamodbav:"model,omitempty" json:"model,omitempty"`
+	Color string `dynamodbav:"color,omitempty" json:"color,omitempty"`
+	// TransponderID is the toll-style transponder read at entry, by which the vehicle can also exit
+	TransponderID string `dynamodbav:"transponderId,omitempty" json:"transponderId,omitempty"`
+	// RefundAmount is the part of the charge refunded after exit and RefundTime when it was refunded;
+	// Charge already has the refund deducted
+	RefundAmount float32    `dynamodbav:"refundAmount,omitempty" json:"refundAmount,omitempty"`
+	RefundTime   *time.Time `dynamodbav:"refundTime,omitempty" json:"refundTime,omitempty"`
+	// PricingArm is the pricing experiment arm the plate was assigned at entry; the ticket is billed with its pricing
+	PricingArm string `dynamodbav:"pricingArm,omitempty" json:"pricingArm,omitempty"`
+	// PriorCharge is what earlier exits of the session billed before the ticket was reopened. The stay
+	// is still billed from the original entry, so the next exit only charges the amount beyond it.
+	PriorCharge float32 `dynamodbav:"priorCharge,omitempty" json:"priorCharge,omitempty"`
+}
+
+// Vehicle describes a vehicle beyond its plate; every field is optional
+type Vehicle struct {
+	Make          string
+	Model         string
+	Color         string
+	TransponderID string
+}
+
+// TicketSummary holds the fields of a ticket that lookups by gate and kiosk displays need
+type TicketSummary struct {
+	TicketID   string       `dynamodbav:"ticketId" json:"ticketId"`
+	Plate      string       `dynamodbav:"plate" json:"plate"`
+	ParkingLot int          `dynamodbav:"parkingLot" json:"parkingLot"`
+	Status     TicketStatus `dynamodbav:"status,omitempty" json:"status,omitempty"`
+}
+
+// SetVehicle records the vehicle's make, model, color and transponder on the ticket
+func (t *ParkingTicket) SetVehicle(vehicle Vehicle) {
+	t.Make = vehicle.Make
+	t.Model = vehicle.Model
+	t.Color = vehicle.Color
+	t.TransponderID = vehicle.TransponderID
+}
+
+// IsAnonymousPlate reports whether plate is a placeholder generated for an anonymous entry
+func IsAnonymousPlate(plate string) bool {
+	return strings.HasPrefix(plate, AnonymousPlatePrefix)
+}
+
+// Spaces returns the number of spaces the ticket occupies.
+// Tickets stored before multi-space support have no value and count as one space.
+func (t *ParkingTicket) Spaces() int {
+	if t.SpacesUsed < 1 {
+		return 1
+	}
+	return t.SpacesUsed
+}
+
+// Reopen puts an exited ticket back in the lot, keeping its original entry time
+// so the charge clock continues rather than restarting. What the exit billed, before any
+// refund, moves to PriorCharge so the next exit does not bill that period again. The refund
+// belonged to the previous exit, so it is cleared and the next exit can be refunded in turn.
+func (t *ParkingTicket) Reopen() {
+	t.Status = TicketStatusIn
+	t.PriorCharge += t.Charge + t.RefundAmount
+	t.Charge = 0
+	t.RefundAmount = 0
+	t.RefundTime = nil
+	t.DurationMinutes = 0
+	t.ExitTime = nil
+	t.QuoteCharge = 0
+	t.QuoteTime = nil
+	t.SpotNumber = 0
+	t.CouponCode = ""
+}
+
+// QuotedCharge returns the quoted charge when the quote was taken no more than window before now.
+// Stale quotes, and any quote when window is zero, are not honored.
+func (t *ParkingTicket) QuotedCharge(now time.Time, window time.Duration) (float32, bool) {
+	if t.QuoteTime == nil || window <= 0 || now.Sub(*t.QuoteTime) > window {
+		return 0, false
+	}
+	return t.QuoteCharge, true
+}
+
+// PaymentTime returns when the charge of an exited ticket was paid: at the kiosk when the quote
+// taken there was honored at exit, otherwise at exit. It returns nil while the ticket has not exited.
+func (t *ParkingTicket) PaymentTime(exitWindow time.Duration) *time.Time {
+	if t.ExitTime == nil {
+		return nil
+	}
+	if _, ok := t.QuotedCharge(*t.ExitTime, exitWindow); ok {
+		return t.QuoteTime
+	}
+	return t.ExitTime
+}
+
+// LotConfig holds the per-lot settings of a parking lot
+type LotConfig struct {
+	// Capacity is the number of spaces in the lot; zero means unlimited
+	Capacity int `json:"capacity"`
+	// Spots is the number of numbered spots assigned to entering vehicles; zero assigns none
+	Spots int `json:"spots,omitempty"`
+	// Currency is the ISO 4217 code exit charges are shown in. Charges are not converted, so it must be
+	// the rate schedule's currency; empty uses it too.
+	Currency string `json:"currency,omitempty"`
+	// Locale is the BCP 47 locale exit charges are formatted for, e.g. "de-DE"; empty uses DefaultLocale
+	Locale string `json:"locale,omitempty"`
+}
+
+// Block holds back spaces of a lot from entries during a time window, e.g. for an event
+type Block struct {
+	BlockID    string `dynamodbav:"blockId" json:"blockId"`
+	ParkingLot int    `dynamodbav:"parkingLot" json:"parkingLot"`
+	// Spaces is the number of spaces held back while the block is active
+	Spaces int `dynamodbav:"spaces" json:"spaces"`
+	// StartTime and EndTime bound the window the block is active in; it ends just before EndTime
+	StartTime time.Time `dynamodbav:"startTime" json:"startTime"`
+	EndTime   time.Time `dynamodbav:"endTime" json:"endTime"`
+}
+
+// Active reports whether the block holds back its spaces at t
+func (b Block) Active(t time.Time) bool {
+	return !t.Before(b.StartTime) && t.Before(b.EndTime)
+}
+
+// RateSchedule describes how parking time is billed
+type RateSchedule struct {
+	// IncrementMinutes is the length of a billing increment
+	IncrementMinutes int `json:"incrementMinutes"`
+	// RatePerIncrement is the charge for each started increment
+	RatePerIncrement float32 `json:"ratePerIncrement"`
+	// Currency is the ISO 4217 code charges are expressed in
+	Currency string `json:"currency"`
+	// DailyMax caps the charge for each started day; zero means no cap
+	DailyMax float32 `json:"dailyMax,omitempty"`
+}
+
+// EventType names a change recorded in the ticket event log
+type EventType string
+
+const (
+	// EventTypeEntry records a vehicle entering and its ticket being issued
+	EventTypeEntry EventType = "entry"
+	// EventTypeExit records a vehicle exiting and being charged
+	EventTypeExit EventType = "exit"
+	// EventTypeReentry records a vehicle returning within the grace window and its ticket being reopened
+	EventTypeReentry EventType = "reentry"
+	// EventTypePayment records the charge of an exit being paid, at the exit or at the kiosk
+	EventTypePayment EventType = "payment"
+	// EventTypePlateCorrection records staff correcting a plate mistyped at entry
+	EventTypePlateCorrection EventType = "plateCorrection"
+	// EventTypeRefund records part or all of the charge being refunded after exit
+	EventTypeRefund EventType = "refund"
+	// EventTypeDeletion records staff deleting a ticket created in error; rebuilds skip the ticket
+	EventTypeDeletion EventType = "deletion"
+)
+
+// TicketEvent is an entry of the append-only event log tickets can be rebuilt from
+type TicketEvent struct {
+	TicketID string `dynamodbav:"ticketId" json:"ticketId"`
+	// Sequence orders the events of a ticket; it is the Unix time in nanoseconds the event was recorded
+	Sequence int64     `dynamodbav:"sequence" json:"sequence"`
+	Type     EventType `dynamodbav:"type" json:"type"`
+	Time     time.Time `dynamodbav:"time" json:"time"`
+
+	Plate      string `dynamodbav:"plate,omitempty" json:"plate,omitempty"`
+	ParkingLot int    `dynamodbav:"parkingLot,omitempty" json:"parkingLot,omitempty"`
+	SpacesUsed int    `dynamodbav:"spacesUsed,omitempty" json:"spacesUsed,omitempty"`
+	SessionID  string `dynamodbav:"sessionId,omitempty" json:"sessionId,omitempty"`
+	// Charge is the charge at exit or the amount of a payment or refund
+	Charge          float32 `dynamodbav:"charge,omitempty" json:"charge,omitempty"`
+	DurationMinutes int     `dynamodbav:"durationMinutes,omitempty" json:"durationMinutes,omitempty"`
+	// PriorCharge is what earlier exits of a reopened session billed, carried when the session moves to a new ticket ID
+	PriorCharge float32 `dynamodbav:"priorCharge,omitempty" json:"priorCharge,omitempty"`
+	// PreviousPlate is the plate a plate correction replaced
+	PreviousPlate string `dynamodbav:"previousPlate,omitempty" json:"previousPlate,omitempty"`
+	// CouponCode is the promo code applied at exit
+	CouponCode string `dynamodbav:"couponCode,omitempty" json:"couponCode,omitempty"`
+	// PricingArm is the pricing experiment arm assigned at entry
+	PricingArm string `dynamodbav:"pricingArm,omitempty" json:"pricingArm,omitempty"`
+	// SpotNumber is the numbered spot assigned at entry or re-entry
+	SpotNumber int `dynamodbav:"spotNumber,omitempty" json:"spotNumber,omitempty"`
+	// Make, Model, Color and TransponderID describe the vehicle given at entry
+	Make          string `dynamodbav:"make,omitempty" json:"make,omitempty"`
+	Model         string `dynamodbav:"model,omitempty" json:"model,omitempty"`
+	Color         string `dynamodbav:"color,omitempty" json:"color,omitempty"`
+	TransponderID string `dynamodbav:"transponderId,omitempty" json:"transponderId,omitempty"`
+	// Version is the ticket's version once the change was stored; zero leaves it unchanged
+	Version int `dynamodbav:"version,omitempty" json:"version,omitempty"`
+	// TableName is the tenant tickets table the ticket is stored in; empty for the default table
+	TableName string `dynamodbav:"tableName,omitempty" json:"tableName,omitempty"`
+}
+
+// EntryEvent returns the event recording a newly issued ticket, carrying every field set at entry
+// so the ticket can be rebuilt from it
+func EntryEvent(ticket *ParkingTicket) TicketEvent {
+	return TicketEvent{
+		TicketID:      ticket.TicketID,
+		Type:          EventTypeEntry,
+		Time:          ticket.EntryTime,
+		Plate:         ticket.Plate,
+		ParkingLot:    ticket.ParkingLot,
+		SpacesUsed:    ticket.SpacesUsed,
+		SessionID:     ticket.SessionID,
+		PricingArm:    ticket.PricingArm,
+		SpotNumber:    ticket.SpotNumber,
+		Make:          ticket.Make,
+		Model:         ticket.Model,
+		Color:         ticket.Color,
+		TransponderID: ticket.TransponderID,
+		PriorCharge:   ticket.PriorCharge,
+		Version:       ticket.Version,
+	}
+}
+
+// Apply updates ticket with the change the event records.
+// Payments are kept for auditing and do not change the ticket.
+func (e TicketEvent) Apply(ticket *ParkingTicket) {
+	switch e.Type {
+	case EventTypeEntry:
+		*ticket = ParkingTicket{
+			TicketID:    e.TicketID,
+			Plate:       e.Plate,
+			ParkingLot:  e.ParkingLot,
+			EntryTime:   e.Time,
+			Status:      TicketStatusIn,
+			SpacesUsed:  e.SpacesUsed,
+			SessionID:   e.SessionID,
+			Anonymous:   IsAnonymousPlate(e.Plate),
+			PricingArm:  e.PricingArm,
+			SpotNumber:  e.SpotNumber,
+			PriorCharge: e.PriorCharge,
+		}
+		ticket.SetVehicle(Vehicle{Make: e.Make, Model: e.Model, Color: e.Color, TransponderID: e.TransponderID})
+	case EventTypeExit:
+		exitTime := e.Time
+		ticket.Status = TicketStatusOut
+		ticket.Charge = e.Charge
+		ticket.DurationMinutes = e.DurationMinutes
+		ticket.ExitTime = &exitTime
+		ticket.CouponCode = e.CouponCode
+	case EventTypeReentry:
+		ticket.Reopen()
+		ticket.SpotNumber = e.SpotNumber
+	case EventTypePlateCorrection:
+		ticket.Plate = e.Plate
+		ticket.Anonymous = IsAnonymousPlate(e.Plate)
+	case EventTypeRefund:
+		refundTime := e.Time
+		ticket.Charge -= e.Charge
+		ticket.RefundAmount = e.Charge
+		ticket.RefundTime = &refundTime
+	}
+	if e.Version != 0 {
+		ticket.Version = e.Version
+	}
 }
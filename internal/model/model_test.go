@@ -57,3 +57,89 @@ func TestParkingTicketMarshalUnmarshal(t *testing.T) {
 	assert.Equal(t, status, unmarshaled.Status)
 	assert.Equal(t, charge, unmarshaled.Charge)
 }
+
+// TestParkingTicketVehicleMarshalUnmarshal tests that the optional vehicle fields survive a round trip
+// when set and are left out of the stored item when not
+func TestParkingTicketVehicleMarshalUnmarshal(t *testing.T) {
+	entryTime := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+	vehicle := Vehicle{Make: "Toyota", Model: "Corolla", Color: "Silver"}
+
+	t.Run("With vehicle", func(t *testing.T) {
+		ticket := &ParkingTicket{TicketID: uuid.NewString(), Plate: "ABC-123", ParkingLot: 1, EntryTime: entryTime}
+		ticket.SetVehicle(vehicle)
+
+		attrs, err := attributevalue.MarshalMap(ticket)
+		assert.NoError(t, err)
+		assert.Contains(t, attrs, "make")
+		assert.Contains(t, attrs, "model")
+		assert.Contains(t, attrs, "color")
+
+		unmarshaled := &ParkingTicket{}
+		assert.NoError(t, attributevalue.UnmarshalMap(attrs, unmarshaled))
+		assert.Equal(t, ticket, unmarshaled)
+	})
+
+	t.Run("Without vehicle", func(t *testing.T) {
+		ticket := &ParkingTicket{TicketID: uuid.NewString(), Plate: "ABC-123", ParkingLot: 1, EntryTime: entryTime}
+
+		attrs, err := attributevalue.MarshalMap(ticket)
+		assert.NoError(t, err)
+		assert.NotContains(t, attrs, "make")
+		assert.NotContains(t, attrs, "model")
+		assert.NotContains(t, attrs, "color")
+
+		unmarshaled := &ParkingTicket{}
+		assert.NoError(t, attributevalue.UnmarshalMap(attrs, unmarshaled))
+		assert.Equal(t, ticket, unmarshaled)
+	})
+}
+
+// TestQuotedCharge tests that a kiosk quote is only honored within the exit window
+func TestQuotedCharge(t *testing.T) {
+	quoteTime := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	window := 15 * time.Minute
+
+	tests := []struct {
+		name     string
+		ticket   ParkingTicket
+		now      time.Time
+		window   time.Duration
+		expected bool
+	}{
+		{name: "No quote", ticket: ParkingTicket{}, now: quoteTime, window: window},
+		{name: "Inside window", ticket: ParkingTicket{QuoteCharge: 5, QuoteTime: &quoteTime}, now: quoteTime.Add(10 * time.Minute), window: window, expected: true},
+		{name: "At window end", ticket: ParkingTicket{QuoteCharge: 5, QuoteTime: &quoteTime}, now: quoteTime.Add(window), window: window, expected: true},
+		{name: "Stale", ticket: ParkingTicket{QuoteCharge: 5, QuoteTime: &quoteTime}, now: quoteTime.Add(window + time.Second), window: window},
+		{name: "Window disabled", ticket: ParkingTicket{QuoteCharge: 5, QuoteTime: &quoteTime}, now: quoteTime, window: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			charge, ok := tt.ticket.QuotedCharge(tt.now, tt.window)
+			assert.Equal(t, tt.expected, ok)
+			if tt.expected {
+				assert.Equal(t, float32(5), charge)
+			}
+		})
+	}
+
+	// Re-entering clears the quote of the previous visit
+	ticket := ParkingTicket{Status: TicketStatusOut, QuoteCharge: 5, QuoteTime: &quoteTime}
+	ticket.Reopen()
+	assert.Nil(t, ticket.QuoteTime)
+	assert.Zero(t, ticket.QuoteCharge)
+}
+
+// TestPaymentTime tests that a charge counts as paid at the kiosk only when the exit honored the quote
+func TestPaymentTime(t *testing.T) {
+	quoteTime := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	window := 15 * time.Minute
+	promptExit := quoteTime.Add(10 * time.Minute)
+	lateExit := quoteTime.Add(window + time.Minute)
+
+	assert.Nil(t, (&ParkingTicket{Status: TicketStatusIn}).PaymentTime(window))
+	assert.Equal(t, &promptExit, (&ParkingTicket{ExitTime: &promptExit}).PaymentTime(window))
+	assert.Equal(t, &quoteTime, (&ParkingTicket{ExitTime: &promptExit, QuoteCharge: 5, QuoteTime: &quoteTime}).PaymentTime(window))
+	// A stale quote was recomputed and paid at the exit
+	assert.Equal(t, &lateExit, (&ParkingTicket{ExitTime: &lateExit, QuoteCharge: 5, QuoteTime: &quoteTime}).PaymentTime(window))
+}
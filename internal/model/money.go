@@ -0,0 +1,114 @@
+package model
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/currency"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"golang.org/x/text/number"
+)
+
+// DefaultLocale is the locale charges are formatted in when a lot does not configure one
+const DefaultLocale = "en-US"
+
+// Money is an amount in a currency
+type Money struct {
+	Amount float32
+	// Currency is the ISO 4217 code of the amount, e.g. "EUR"
+	Currency string
+}
+
+// symbolPlacement is where a locale writes the currency symbol relative to the amount,
+// following the CLDR currency pattern of the locale
+type symbolPlacement int
+
+const (
+	// symbolBefore writes the symbol right before the amount, e.g. "$1,234.50" (¤#,##0.00)
+	symbolBefore symbolPlacement = iota
+	// symbolBeforeSpaced writes the symbol and a space before the amount, e.g. "€ 1.234,50" (¤ #,##0.00)
+	symbolBeforeSpaced
+	// symbolAfter writes the symbol after the amount and a space, e.g. "1.234,50 €" (#,##0.00 ¤)
+	symbolAfter
+)
+
+// symbolPlacements holds the CLDR currency pattern of locales that do not put the symbol right
+// before the amount, keyed by language and by language and region where a region differs
+var symbolPlacements = map[string]symbolPlacement{
+	"nl":    symbolBeforeSpaced,
+	"de-CH": symbolBeforeSpaced,
+	"de-LI": symbolBeforeSpaced,
+	"pt":    symbolBeforeSpaced,
+	"de":    symbolAfter,
+	"fr":    symbolAfter,
+	"es":    symbolAfter,
+	"it":    symbolAfter,
+	"pt-PT": symbolAfter,
+	"he":    symbolAfter,
+	"pl":    symbolAfter,
+	"cs":    symbolAfter,
+	"sv":    symbolAfter,
+	"da":    symbolAfter,
+	"nb":    symbolAfter,
+	"fi":    symbolAfter,
+	"ru":    symbolAfter,
+}
+
+// parseLocale returns the language tag of a BCP 47 locale such as "de-DE" or "de_DE"
+func parseLocale(locale string) (language.Tag, bool) {
+	tag, err := language.Parse(locale)
+	return tag, err == nil
+}
+
+// SupportedLocale reports whether charges can be formatted in a locale
+func SupportedLocale(locale string) bool {
+	_, ok := parseLocale(locale)
+	return ok
+}
+
+// placement returns where a locale writes the currency symbol
+func placement(tag language.Tag) symbolPlacement {
+	base, _ := tag.Base()
+	region, _ := tag.Region()
+	if p, ok := symbolPlacements[base.String()+"-"+region.String()]; ok {
+		return p
+	}
+	return symbolPlacements[base.String()]
+}
+
+// Format writes the amount with the currency's symbol where the locale's CLDR currency pattern puts it
+// and with the locale's separators, rounded to the currency's minor unit, e.g. "$1,234.50" in en-US and
+// "1.234,50 €" in de-DE. Unsupported locales fall back to DefaultLocale, and currencies without CLDR
+// data are written with their code.
+func (m Money) Format(locale string) string {
+	tag, ok := parseLocale(locale)
+	if !ok {
+		tag = language.MustParse(DefaultLocale)
+	}
+	printer := message.NewPrinter(tag)
+
+	symbol, scale := m.Currency, 2
+	if unit, err := currency.ParseISO(m.Currency); err == nil {
+		symbol = printer.Sprint(currency.Symbol(unit))
+		scale, _ = currency.Standard.Rounding(unit)
+	}
+
+	switch placement(tag) {
+	case symbolBeforeSpaced:
+		return symbol + " " + printer.Sprint(number.Decimal(m.Amount, number.Scale(scale)))
+	case symbolAfter:
+		return printer.Sprint(number.Decimal(m.Amount, number.Scale(scale))) + " " + symbol
+	}
+
+	// The sign goes before the symbol, and a symbol ending in a letter such as "SEK" is spaced from the digits
+	sign, amount := "", m.Amount
+	if amount < 0 {
+		sign, amount = "-", -amount
+	}
+	if last, _ := utf8.DecodeLastRuneInString(symbol); unicode.IsLetter(last) {
+		symbol += " "
+	}
+	return sign + symbol + strings.TrimSpace(printer.Sprint(number.Decimal(amount, number.Scale(scale))))
+}
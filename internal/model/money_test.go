@@ -0,0 +1,48 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestMoneyFormat tests that the same charge is written with each locale's separators and the currency's symbol
+// where the locale's currency pattern puts it
+func TestMoneyFormat(t *testing.T) {
+	tests := []struct {
+		name     string
+		money    Money
+		locale   string
+		expected string
+	}{
+		{name: "US dollars", money: Money{Amount: 5, Currency: "USD"}, locale: "en-US", expected: "$5.00"},
+		{name: "Euros in Germany", money: Money{Amount: 5, Currency: "EUR"}, locale: "de-DE", expected: "5,00 €"},
+		{name: "Euros in the Netherlands", money: Money{Amount: 5, Currency: "EUR"}, locale: "nl-NL", expected: "€ 5,00"},
+		{name: "Grouping", money: Money{Amount: 1234.5, Currency: "USD"}, locale: "en-US", expected: "$1,234.50"},
+		{name: "Grouping in Germany", money: Money{Amount: 1234.5, Currency: "EUR"}, locale: "de-DE", expected: "1.234,50 €"},
+		{name: "Regional separators", money: Money{Amount: 1234.5, Currency: "CHF"}, locale: "de-CH", expected: "CHF 1’234.50"},
+		{name: "Currency without symbol", money: Money{Amount: 5, Currency: "SEK"}, locale: "en-US", expected: "SEK 5.00"},
+		{name: "Unknown currency", money: Money{Amount: 5, Currency: "XYZ"}, locale: "de-DE", expected: "5,00 XYZ"},
+		{name: "Currency without minor unit", money: Money{Amount: 1500, Currency: "JPY"}, locale: "ja-JP", expected: "￥1,500"},
+		{name: "Rounding", money: Money{Amount: 7.126, Currency: "ILS"}, locale: "he-IL", expected: "7.13 ₪"},
+		{name: "Negative after the amount", money: Money{Amount: -2.5, Currency: "EUR"}, locale: "de-DE", expected: "-2,50 €"},
+		{name: "Negative", money: Money{Amount: -2.5, Currency: "USD"}, locale: "en-US", expected: "-$2.50"},
+		{name: "Underscore separator", money: Money{Amount: 5, Currency: "EUR"}, locale: "fr_FR", expected: "5,00 €"},
+		{name: "Unsupported locale", money: Money{Amount: 5, Currency: "USD"}, locale: "xx-XX", expected: "$5.00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.money.Format(tt.locale))
+		})
+	}
+}
+
+// TestSupportedLocale tests that well-formed locales of known languages are supported
+func TestSupportedLocale(t *testing.T) {
+	assert.True(t, SupportedLocale("en-GB"))
+	assert.True(t, SupportedLocale("de-CH"))
+	assert.True(t, SupportedLocale("DE"))
+	assert.False(t, SupportedLocale("xx-XX"))
+	assert.False(t, SupportedLocale(""))
+}
@@ -0,0 +1,127 @@
+// Package paging turns DynamoDB LastEvaluatedKey values into opaque cursors clients can pass back
+// to continue a listing, and back again
+package paging
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// Version is the cursor format version. It is bumped whenever the key schema or the encoding
+// changes, so cursors issued before the change are rejected instead of resuming at the wrong item.
+const Version = 1
+
+// ErrInvalidCursor is returned for cursors that are malformed, tampered with or from another version
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// cursor is the JSON form of a cursor before base64 encoding
+type cursor struct {
+	Version int                  `json:"v"`
+	Key     map[string]attribute `json:"k"`
+}
+
+// attribute holds one key attribute in DynamoDB JSON style; exactly one field is set.
+// Key attributes can only be strings, numbers or binary.
+type attribute struct {
+	S *string `json:"S,omitempty"`
+	N *string `json:"N,omitempty"`
+	B []byte  `json:"B,omitempty"`
+}
+
+// Encode returns the cursor for a LastEvaluatedKey. An empty key means there are no more pages
+// and encodes to the empty string.
+func Encode(key map[string]types.AttributeValue) (string, error) {
+	if len(key) == 0 {
+		return "", nil
+	}
+
+	c := cursor{Version: Version, Key: make(map[string]attribute, len(key))}
+	for name, value := range key {
+		switch v := value.(type) {
+		case *types.AttributeValueMemberS:
+			c.Key[name] = attribute{S: &v.Value}
+		case *types.AttributeValueMemberN:
+			c.Key[name] = attribute{N: &v.Value}
+		case *types.AttributeValueMemberB:
+			c.Key[name] = attribute{B: v.Value}
+		default:
+			return "", fmt.Errorf("unsupported type %T for key attribute %q", value, name)
+		}
+	}
+
+	data, err := json.Marshal(c)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal cursor: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// Decode returns the ExclusiveStartKey a cursor stands for. The empty string is the first page
+// and decodes to a nil key; anything that Encode could not have produced fails with ErrInvalidCursor.
+func Decode(token string) (map[string]types.AttributeValue, error) {
+	if token == "" {
+		return nil, nil
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, fmt.Errorf("%w: not base64url", ErrInvalidCursor)
+	}
+
+	var c cursor
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&c); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+	if decoder.More() {
+		return nil, fmt.Errorf("%w: trailing data", ErrInvalidCursor)
+	}
+	if c.Version != Version {
+		return nil, fmt.Errorf("%w: version %d is not supported", ErrInvalidCursor, c.Version)
+	}
+	if len(c.Key) == 0 {
+		return nil, fmt.Errorf("%w: empty key", ErrInvalidCursor)
+	}
+
+	key := make(map[string]types.AttributeValue, len(c.Key))
+	for name, attr := range c.Key {
+		value, err := attr.value()
+		if err != nil {
+			return nil, fmt.Errorf("%w: key attribute %q %v", ErrInvalidCursor, name, err)
+		}
+		key[name] = value
+	}
+	return key, nil
+}
+
+// value converts the attribute back to an AttributeValue, checking exactly one type is set
+func (a attribute) value() (types.AttributeValue, error) {
+	set := 0
+	var value types.AttributeValue
+	if a.S != nil {
+		set++
+		value = &types.AttributeValueMemberS{Value: *a.S}
+	}
+	if a.N != nil {
+		if _, err := strconv.ParseFloat(*a.N, 64); err != nil {
+			return nil, fmt.Errorf("is not a number")
+		}
+		set++
+		value = &types.AttributeValueMemberN{Value: *a.N}
+	}
+	if a.B != nil {
+		set++
+		value = &types.AttributeValueMemberB{Value: a.B}
+	}
+	if set != 1 {
+		return nil, fmt.Errorf("must have exactly one of S, N or B")
+	}
+	return value, nil
+}
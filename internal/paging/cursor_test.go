@@ -0,0 +1,114 @@
+package paging
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestRoundTrip tests that decoding a cursor returns the key it was encoded from
+func TestRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		key  map[string]types.AttributeValue
+	}{
+		{
+			name: "Ticket ID key",
+			key:  map[string]types.AttributeValue{"ticketId": &types.AttributeValueMemberS{Value: "123e4567-e89b-12d3-a456-426614174000"}},
+		},
+		{
+			name: "Index key",
+			key: map[string]types.AttributeValue{
+				"ticketId":   &types.AttributeValueMemberS{Value: "123e4567-e89b-12d3-a456-426614174000"},
+				"parkingLot": &types.AttributeValueMemberN{Value: "382"},
+				"entryTime":  &types.AttributeValueMemberS{Value: "2024-05-01T10:00:00Z"},
+			},
+		},
+		{
+			name: "Binary and special characters",
+			key: map[string]types.AttributeValue{
+				"plate": &types.AttributeValueMemberS{Value: "אב-123 \"/+?&="},
+				"hash":  &types.AttributeValueMemberB{Value: []byte{0, 1, 2, 0xfe, 0xff}},
+				"n":     &types.AttributeValueMemberN{Value: "-1.5e3"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := Encode(tt.key)
+			assert.NoError(t, err)
+			assert.NotEmpty(t, token)
+			assert.NotContains(t, token, "=")
+
+			decoded, err := Decode(token)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.key, decoded)
+		})
+	}
+}
+
+// TestEmptyCursor tests that the last page and the first page both use the empty cursor
+func TestEmptyCursor(t *testing.T) {
+	token, err := Encode(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "", token)
+
+	token, err = Encode(map[string]types.AttributeValue{})
+	assert.NoError(t, err)
+	assert.Equal(t, "", token)
+
+	key, err := Decode("")
+	assert.NoError(t, err)
+	assert.Nil(t, key)
+}
+
+// TestEncode_UnsupportedType tests that non-key attribute types are refused
+func TestEncode_UnsupportedType(t *testing.T) {
+	_, err := Encode(map[string]types.AttributeValue{"flag": &types.AttributeValueMemberBOOL{Value: true}})
+
+	assert.ErrorContains(t, err, `unsupported type *types.AttributeValueMemberBOOL for key attribute "flag"`)
+}
+
+// TestDecode_Rejects tests that cursors Encode could not have produced are rejected
+func TestDecode_Rejects(t *testing.T) {
+	valid, err := Encode(map[string]types.AttributeValue{"ticketId": &types.AttributeValueMemberS{Value: "abc"}})
+	assert.NoError(t, err)
+	encode := func(json string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(json))
+	}
+
+	tests := []struct {
+		name   string
+		token  string
+		reason string
+	}{
+		{name: "Not base64", token: "not a cursor!", reason: "not base64url"},
+		{name: "Padded standard base64", token: base64.StdEncoding.EncodeToString([]byte(`{"v":1,"k":{"a":{"S":"b"}}}`)) + "=", reason: "not base64url"},
+		{name: "Truncated", token: valid[:len(valid)-4], reason: "invalid cursor"},
+		{name: "Not JSON", token: encode("ticketId=abc"), reason: "invalid character"},
+		{name: "Old version", token: encode(`{"v":0,"k":{"ticketId":{"S":"abc"}}}`), reason: "version 0 is not supported"},
+		{name: "Future version", token: encode(`{"v":2,"k":{"ticketId":{"S":"abc"}}}`), reason: "version 2 is not supported"},
+		{name: "Missing version", token: encode(`{"k":{"ticketId":{"S":"abc"}}}`), reason: "version 0 is not supported"},
+		{name: "Empty key", token: encode(`{"v":1,"k":{}}`), reason: "empty key"},
+		{name: "Unknown field", token: encode(`{"v":1,"k":{"ticketId":{"S":"abc"}},"admin":true}`), reason: `unknown field "admin"`},
+		{name: "Unknown attribute type", token: encode(`{"v":1,"k":{"ticketId":{"BOOL":true}}}`), reason: `unknown field "BOOL"`},
+		{name: "No attribute type", token: encode(`{"v":1,"k":{"ticketId":{}}}`), reason: "exactly one of S, N or B"},
+		{name: "Two attribute types", token: encode(`{"v":1,"k":{"ticketId":{"S":"abc","N":"1"}}}`), reason: "exactly one of S, N or B"},
+		{name: "Number that is not a number", token: encode(`{"v":1,"k":{"parkingLot":{"N":"1; DROP"}}}`), reason: "is not a number"},
+		{name: "Trailing data", token: encode(`{"v":1,"k":{"ticketId":{"S":"abc"}}}{"v":1}`), reason: "trailing data"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, err := Decode(tt.token)
+
+			assert.ErrorIs(t, err, ErrInvalidCursor)
+			assert.ErrorContains(t, err, tt.reason)
+			assert.Nil(t, key)
+		})
+	}
+}
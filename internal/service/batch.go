@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"parking-lot/internal/logger"
+	"parking-lot/internal/model"
+)
+
+// Maximum number of requests DynamoDB accepts in one batch call
+const (
+	batchGetLimit   = 100
+	batchWriteLimit = 25
+)
+
+// maxUnprocessedRetries bounds how often a batch with unprocessed items is resubmitted
+const maxUnprocessedRetries = 5
+
+// unprocessedBackoff is the wait before resubmitting unprocessed items; it doubles after each attempt
+var unprocessedBackoff = 50 * time.Millisecond
+
+// GetTicketsBatch retrieves many tickets at once, keyed by ticket ID. IDs without a ticket are left out
+// of the result. Lookups are sent in chunks of batchGetLimit and unprocessed keys are retried.
+func (s *ParkingLotService) GetTicketsBatch(ctx context.Context, ids []string) (map[string]*model.ParkingTicket, error) {
+	log := s.log.WithContext(ctx).WithFields(logger.Field{Key: "ticket_count", Value: len(ids)})
+	log.Info("Retrieving tickets in batch")
+
+	// BatchGetItem rejects requests that repeat a key
+	seen := make(map[string]bool, len(ids))
+	keys := make([]map[string]types.AttributeValue, 0, len(ids))
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		keys = append(keys, map[string]types.AttributeValue{
+			"ticketId": &types.AttributeValueMemberS{Value: id},
+		})
+	}
+
+	tableName := s.table(ctx)
+	tickets := make(map[string]*model.ParkingTicket, len(keys))
+
+	// BatchGetItem needs primary keys, which composite-key tables only reveal per ticket
+	if s.compositeKey {
+		for id := range seen {
+			item, err := s.getTicketItem(ctx, id)
+			if err != nil {
+				log.Error("Failed to retrieve tickets in batch", logger.Field{Key: "error", Value: err.Error()})
+				return nil, fmt.Errorf("failed to get tickets: %w", err)
+			}
+			if item == nil {
+				continue
+			}
+			ticket := &model.ParkingTicket{}
+			if err := s.unmarshalMap(item, ticket); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal ticket: %w", err)
+			}
+			tickets[ticket.TicketID] = ticket
+		}
+		log.Info("Retrieved tickets one by one", logger.Field{Key: "found", Value: len(tickets)})
+		return tickets, nil
+	}
+
+	for start := 0; start < len(keys); start += batchGetLimit {
+		end := min(start+batchGetLimit, len(keys))
+		pending := map[string]types.KeysAndAttributes{tableName: {Keys: keys[start:end], ConsistentRead: s.consistentRead()}}
+
+		backoff := unprocessedBackoff
+		for attempt := 0; len(pending) > 0; attempt++ {
+			if attempt > maxUnprocessedRetries {
+				err := fmt.Errorf("failed to get tickets: %d keys still unprocessed", len(pending[tableName].Keys))
+				log.Error("Failed to retrieve tickets in batch", logger.Field{Key: "error", Value: err.Error()})
+				return nil, err
+			}
+			if attempt > 0 {
+				// Unprocessed keys usually mean throttling, so give the table a moment
+				time.Sleep(backoff)
+				backoff *= 2
+			}
+
+			result, err := s.client.BatchGetItem(ctx, &dynamodb.BatchGetItemInput{RequestItems: pending})
+			if err != nil {
+				log.Error("Failed to retrieve tickets in batch", logger.Field{Key: "error", Value: err.Error()})
+				return nil, fmt.Errorf("failed to get tickets: %w", err)
+			}
+
+			for _, item := range result.Responses[tableName] {
+				ticket := &model.ParkingTicket{}
+				if err := s.unmarshalMap(item, ticket); err != nil {
+					return nil, fmt.Errorf("failed to unmarshal ticket: %w", err)
+				}
+				tickets[ticket.TicketID] = ticket
+			}
+			pending = result.UnprocessedKeys
+		}
+	}
+
+	log.Info("Retrieved tickets in batch", logger.Field{Key: "found", Value: len(tickets)})
+	return tickets, nil
+}
@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"parking-lot/internal/logger"
+	"parking-lot/internal/mocks"
+	"parking-lot/internal/model"
+)
+
+// batchKeyIDs returns the ticket IDs requested from the table in a BatchGetItem call
+func batchKeyIDs(input *dynamodb.BatchGetItemInput, tableName string) []string {
+	var ids []string
+	for _, key := range input.RequestItems[tableName].Keys {
+		ids = append(ids, key["ticketId"].(*types.AttributeValueMemberS).Value)
+	}
+	return ids
+}
+
+// TestGetTicketsBatch tests that 150 IDs are fetched in two chunks and unprocessed keys are retried
+func TestGetTicketsBatch(t *testing.T) {
+	defer func(backoff time.Duration) { unprocessedBackoff = backoff }(unprocessedBackoff)
+	unprocessedBackoff = time.Millisecond
+
+	ctx := context.Background()
+	mockClient := new(mocks.DynamoDBClient)
+	service := &ParkingLotService{
+		client:       mockClient,
+		tableName:    "testTable",
+		log:          logger.NewLogger(),
+		unmarshalMap: attributevalue.UnmarshalMap,
+	}
+
+	ids := make([]string, 150)
+	items := make(map[string]map[string]types.AttributeValue, 150)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("ticket-%03d", i)
+		items[ids[i]], _ = attributevalue.MarshalMap(model.ParkingTicket{TicketID: ids[i], ParkingLot: 1, Status: model.TicketStatusIn})
+	}
+	// The last ticket does not exist
+	delete(items, ids[149])
+
+	// respond returns the stored tickets among ids and reports the unprocessed ones as left over
+	respond := func(ids []string, unprocessed []string) *dynamodb.BatchGetItemOutput {
+		out := &dynamodb.BatchGetItemOutput{Responses: map[string][]map[string]types.AttributeValue{}}
+		for _, id := range ids {
+			if item, ok := items[id]; ok {
+				out.Responses["testTable"] = append(out.Responses["testTable"], item)
+			}
+		}
+		if len(unprocessed) > 0 {
+			keys := types.KeysAndAttributes{}
+			for _, id := range unprocessed {
+				keys.Keys = append(keys.Keys, map[string]types.AttributeValue{"ticketId": &types.AttributeValueMemberS{Value: id}})
+			}
+			out.UnprocessedKeys = map[string]types.KeysAndAttributes{"testTable": keys}
+		}
+		return out
+	}
+	var requested [][]string
+	recordRequest := func(args mock.Arguments) {
+		requested = append(requested, batchKeyIDs(args.Get(1).(*dynamodb.BatchGetItemInput), "testTable"))
+	}
+
+	// The last 10 keys of the first chunk are left unprocessed and retried before the second chunk
+	mockClient.On("BatchGetItem", ctx, mock.Anything, mock.Anything).Run(recordRequest).Return(respond(ids[:90], ids[90:100]), nil).Once()
+	mockClient.On("BatchGetItem", ctx, mock.Anything, mock.Anything).Run(recordRequest).Return(respond(ids[90:100], nil), nil).Once()
+	mockClient.On("BatchGetItem", ctx, mock.Anything, mock.Anything).Run(recordRequest).Return(respond(ids[100:], nil), nil).Once()
+
+	// Duplicate IDs are only requested once
+	tickets, err := service.GetTicketsBatch(ctx, append(ids, ids[0]))
+
+	assert.NoError(t, err)
+	assert.Len(t, tickets, 149)
+	assert.Equal(t, ids[0], tickets[ids[0]].TicketID)
+	assert.NotContains(t, tickets, ids[149])
+	if assert.Len(t, requested, 3) {
+		assert.Equal(t, ids[:100], requested[0])
+		assert.Equal(t, ids[90:100], requested[1])
+		assert.Equal(t, ids[100:], requested[2])
+	}
+	mockClient.AssertExpectations(t)
+}
+
+// TestGetTicketsBatch_Errors tests that failed and persistently unprocessed lookups are reported
+func TestGetTicketsBatch_Errors(t *testing.T) {
+	defer func(backoff time.Duration) { unprocessedBackoff = backoff }(unprocessedBackoff)
+	unprocessedBackoff = time.Millisecond
+
+	ctx := context.Background()
+	unprocessed := &dynamodb.BatchGetItemOutput{
+		UnprocessedKeys: map[string]types.KeysAndAttributes{"testTable": {Keys: []map[string]types.AttributeValue{
+			{"ticketId": &types.AttributeValueMemberS{Value: "ticket-1"}},
+		}}},
+	}
+
+	tests := []struct {
+		name        string
+		output      *dynamodb.BatchGetItemOutput
+		err         error
+		expectedErr string
+	}{
+		{name: "Request error", err: errors.New("throttled"), expectedErr: "failed to get tickets: throttled"},
+		{name: "Unprocessed keys", output: unprocessed, expectedErr: "failed to get tickets: 1 keys still unprocessed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := new(mocks.DynamoDBClient)
+			service := &ParkingLotService{client: mockClient, tableName: "testTable", log: logger.NewLogger()}
+			mockClient.On("BatchGetItem", ctx, mock.Anything, mock.Anything).Return(tt.output, tt.err)
+
+			tickets, err := service.GetTicketsBatch(ctx, []string{"ticket-1"})
+
+			assert.EqualError(t, err, tt.expectedErr)
+			assert.Nil(t, tickets)
+		})
+	}
+}
+
+// TestMemoryParkingLotService_GetTicketsBatch tests batch lookups in the in-memory service
+func TestMemoryParkingLotService_GetTicketsBatch(t *testing.T) {
+	ctx := context.Background()
+	service, err := NewMemoryParkingLotService(ctx)
+	assert.NoError(t, err)
+
+	first, _ := service.CreateTicket(ctx, "ABC-123", 1, 1)
+	second, _ := service.CreateTicket(ctx, "XYZ-789", 1, 1)
+
+	tickets, err := service.GetTicketsBatch(ctx, []string{first.String(), second.String(), "missing"})
+
+	assert.NoError(t, err)
+	assert.Len(t, tickets, 2)
+	assert.Equal(t, "XYZ-789", tickets[second.String()].Plate)
+}
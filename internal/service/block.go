@@ -0,0 +1,202 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/google/uuid"
+
+	"parking-lot/internal/logger"
+	"parking-lot/internal/model"
+)
+
+// ErrInvalidBlock is returned for blocks without spaces or whose window is empty or already over
+var ErrInvalidBlock = errors.New("block must hold at least one space and end after it starts and after now")
+
+// BlockStore keeps the space blocks of parking lots
+type BlockStore interface {
+	// Add stores a block
+	Add(ctx context.Context, block model.Block) error
+
+	// Blocks returns the blocks stored for a lot, including ones not active yet or already over
+	Blocks(ctx context.Context, parkingLot int) ([]model.Block, error)
+}
+
+// SpaceBlocker is implemented by services that can hold back spaces of a lot, e.g. for an event
+type SpaceBlocker interface {
+	// BlockSpaces holds back spaces of a lot from entries between start and end; a zero start is now
+	BlockSpaces(ctx context.Context, parkingLot, spaces int, start, end time.Time) (*model.Block, error)
+}
+
+// BlockSpaces validates and stores a block. While it is active, entries only get the spaces the lot
+// has beyond the blocked ones; lots without a capacity are unaffected.
+func (s *ParkingLotService) BlockSpaces(ctx context.Context, parkingLot, spaces int, start, end time.Time) (*model.Block, error) {
+	if start.IsZero() {
+		start = s.now()
+	}
+	log := s.log.WithContext(ctx).WithFields(
+		logger.Field{Key: "parking_lot", Value: parkingLot},
+		logger.Field{Key: "spaces", Value: spaces},
+		logger.Field{Key: "start_time", Value: start.Format(time.RFC3339)},
+		logger.Field{Key: "end_time", Value: end.Format(time.RFC3339)},
+	)
+
+	if spaces < 1 || !end.After(start) || !end.After(s.now()) {
+		log.Warn("Invalid block")
+		return nil, ErrInvalidBlock
+	}
+	if s.blocks == nil {
+		return nil, errors.New("space blocks are not supported")
+	}
+
+	block := model.Block{
+		BlockID:    uuid.New().String(),
+		ParkingLot: parkingLot,
+		Spaces:     spaces,
+		StartTime:  start.UTC(),
+		EndTime:    end.UTC(),
+	}
+	if err := s.blocks.Add(ctx, block); err != nil {
+		log.Error("Failed to store block", logger.Field{Key: "error", Value: err.Error()})
+		return nil, err
+	}
+
+	log.Info("Blocked spaces", logger.Field{Key: "block_id", Value: block.BlockID})
+	return &block, nil
+}
+
+// blockedSpaces returns the number of spaces of a lot held back by blocks active now. A failed
+// lookup is logged and treated as no block so a storage hiccup does not close the lot.
+func (s *ParkingLotService) blockedSpaces(ctx context.Context, log logger.Logger, parkingLot int) int {
+	if s.blocks == nil {
+		return 0
+	}
+	blocks, err := s.blocks.Blocks(ctx, parkingLot)
+	if err != nil {
+		log.Warn("Failed to read space blocks", logger.Field{Key: "error", Value: err.Error()})
+		return 0
+	}
+
+	now := s.now()
+	blocked := 0
+	for _, block := range blocks {
+		if block.Active(now) {
+			blocked += block.Spaces
+		}
+	}
+	return blocked
+}
+
+// MemoryBlocks is an in-memory BlockStore.
+// Blocks are local to the process and are lost on restart.
+type MemoryBlocks struct {
+	mu     sync.Mutex
+	blocks map[int][]model.Block
+}
+
+// NewMemoryBlocks creates an in-memory block store without blocks
+func NewMemoryBlocks() *MemoryBlocks {
+	return &MemoryBlocks{blocks: make(map[int][]model.Block)}
+}
+
+// Add stores a block
+func (m *MemoryBlocks) Add(ctx context.Context, block model.Block) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.blocks[block.ParkingLot] = append(m.blocks[block.ParkingLot], block)
+	return nil
+}
+
+// Blocks returns the blocks of a lot
+func (m *MemoryBlocks) Blocks(ctx context.Context, parkingLot int) ([]model.Block, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]model.Block(nil), m.blocks[parkingLot]...), nil
+}
+
+// blocksItemPrefix starts the key of the config item holding a lot's blocks, e.g. "config#blocks#382"
+const blocksItemPrefix = "config#blocks#"
+
+// blockCacheTTL is how long an instance trusts the blocks it last read, bounding both the extra
+// reads per entry and how long a new block takes to reach other instances
+var blockCacheTTL = 10 * time.Second
+
+// dynamoBlocks is a BlockStore keeping the blocks of each lot as a list in a config item of the
+// tickets table, so every instance shares them
+type dynamoBlocks struct {
+	s *ParkingLotService
+
+	mu        sync.Mutex
+	cached    map[int][]model.Block
+	fetchedAt map[int]time.Time
+}
+
+// Add appends a block to the lot's list
+func (d *dynamoBlocks) Add(ctx context.Context, block model.Block) error {
+	item, err := attributevalue.MarshalMap(block)
+	if err != nil {
+		return fmt.Errorf("failed to marshal block: %w", err)
+	}
+
+	_, err = d.s.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName:        aws.String(d.s.table(ctx)),
+		Key:              d.s.configItemKey(blocksItemPrefix + strconv.Itoa(block.ParkingLot)),
+		UpdateExpression: aws.String("SET blocks = list_append(if_not_exists(blocks, :empty), :block)"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":empty": &types.AttributeValueMemberL{Value: []types.AttributeValue{}},
+			":block": &types.AttributeValueMemberL{Value: []types.AttributeValue{&types.AttributeValueMemberM{Value: item}}},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to store block: %w", err)
+	}
+
+	// Read the list again on the next entry so this instance enforces the block right away
+	d.mu.Lock()
+	delete(d.fetchedAt, block.ParkingLot)
+	d.mu.Unlock()
+	return nil
+}
+
+// Blocks reads the lot's list, at most once per blockCacheTTL
+func (d *dynamoBlocks) Blocks(ctx context.Context, parkingLot int) ([]model.Block, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if fetchedAt, ok := d.fetchedAt[parkingLot]; ok && d.s.now().Sub(fetchedAt) < blockCacheTTL {
+		return d.cached[parkingLot], nil
+	}
+
+	result, err := d.s.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(d.s.table(ctx)),
+		Key:       d.s.configItemKey(blocksItemPrefix + strconv.Itoa(parkingLot)),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read blocks: %w", err)
+	}
+
+	var blocks []model.Block
+	if list, ok := result.Item["blocks"]; ok {
+		if err := attributevalue.Unmarshal(list, &blocks); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal blocks: %w", err)
+		}
+	}
+
+	if d.cached == nil {
+		d.cached = make(map[int][]model.Block)
+		d.fetchedAt = make(map[int]time.Time)
+	}
+	d.cached[parkingLot] = blocks
+	d.fetchedAt[parkingLot] = d.s.now()
+	return blocks, nil
+}
@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"parking-lot/internal/logger"
+	"parking-lot/internal/mocks"
+	"parking-lot/internal/model"
+)
+
+// TestBlockSpaces_Entry tests that entries respect the spaces held back by an active block
+func TestBlockSpaces_Entry(t *testing.T) {
+	ctx := context.Background()
+	parkingLot := 382
+	now := time.Date(2025, 3, 1, 18, 0, 0, 0, time.UTC)
+
+	newService := func() *ParkingLotService {
+		service := &ParkingLotService{
+			ctx:    ctx,
+			log:    logger.NewLogger(),
+			lots:   map[int]model.LotConfig{parkingLot: {Capacity: 10}},
+			blocks: NewMemoryBlocks(),
+			clock:  func() time.Time { return now },
+		}
+		service.occupancy = NewMemoryOccupancy(func(lot int) int {
+			return service.LotConfig(lot).Capacity
+		})
+		return service
+	}
+
+	t.Run("Rejected during a full block and allowed after it expires", func(t *testing.T) {
+		service := newService()
+		_, err := service.BlockSpaces(ctx, parkingLot, 10, time.Time{}, now.Add(2*time.Hour))
+		require.NoError(t, err)
+
+		assert.ErrorIs(t, service.ReserveSpaces(ctx, parkingLot, 1), ErrLotFull)
+
+		service.clock = func() time.Time { return now.Add(2 * time.Hour) }
+		assert.NoError(t, service.ReserveSpaces(ctx, parkingLot, 1))
+	})
+
+	t.Run("Partial block leaves the other spaces", func(t *testing.T) {
+		service := newService()
+		_, err := service.BlockSpaces(ctx, parkingLot, 8, now, now.Add(time.Hour))
+		require.NoError(t, err)
+
+		assert.NoError(t, service.ReserveSpaces(ctx, parkingLot, 2))
+		assert.ErrorIs(t, service.ReserveSpaces(ctx, parkingLot, 1), ErrLotFull)
+	})
+
+	t.Run("Block not started yet", func(t *testing.T) {
+		service := newService()
+		_, err := service.BlockSpaces(ctx, parkingLot, 10, now.Add(time.Hour), now.Add(2*time.Hour))
+		require.NoError(t, err)
+
+		assert.NoError(t, service.ReserveSpaces(ctx, parkingLot, 1))
+	})
+
+	t.Run("Other lots are unaffected", func(t *testing.T) {
+		service := newService()
+		_, err := service.BlockSpaces(ctx, parkingLot+1, 10, now, now.Add(time.Hour))
+		require.NoError(t, err)
+
+		assert.NoError(t, service.ReserveSpaces(ctx, parkingLot, 1))
+	})
+}
+
+// TestBlockSpaces_Invalid tests that blocks without spaces or with an empty or past window are rejected
+func TestBlockSpaces_Invalid(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 3, 1, 18, 0, 0, 0, time.UTC)
+	service := &ParkingLotService{
+		log:    logger.NewLogger(),
+		blocks: NewMemoryBlocks(),
+		clock:  func() time.Time { return now },
+	}
+
+	tests := []struct {
+		name   string
+		spaces int
+		start  time.Time
+		end    time.Time
+	}{
+		{name: "No spaces", spaces: 0, start: now, end: now.Add(time.Hour)},
+		{name: "Ends before it starts", spaces: 5, start: now.Add(time.Hour), end: now},
+		{name: "Already over", spaces: 5, start: now.Add(-2 * time.Hour), end: now.Add(-time.Hour)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			block, err := service.BlockSpaces(ctx, 3, tt.spaces, tt.start, tt.end)
+
+			assert.ErrorIs(t, err, ErrInvalidBlock)
+			assert.Nil(t, block)
+		})
+	}
+}
+
+// TestDynamoBlocks tests that blocks are appended to the lot's config item and read back with a cache
+func TestDynamoBlocks(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 3, 1, 18, 0, 0, 0, time.UTC)
+	mockClient := new(mocks.DynamoDBClient)
+	service := newEventsTestService(mockClient)
+	service.clock = func() time.Time { return now }
+	blocks := &dynamoBlocks{s: service}
+	block := model.Block{BlockID: "b1", ParkingLot: 3, Spaces: 40, StartTime: now, EndTime: now.Add(time.Hour)}
+
+	mockClient.On("UpdateItem", ctx, mock.MatchedBy(func(in *dynamodb.UpdateItemInput) bool {
+		key, _ := in.Key["ticketId"].(*types.AttributeValueMemberS)
+		return key != nil && key.Value == "config#blocks#3" &&
+			*in.UpdateExpression == "SET blocks = list_append(if_not_exists(blocks, :empty), :block)"
+	}), mock.Anything).Return(&dynamodb.UpdateItemOutput{}, nil).Once()
+	require.NoError(t, blocks.Add(ctx, block))
+
+	stored, err := attributevalue.Marshal([]model.Block{block})
+	require.NoError(t, err)
+	mockClient.On("GetItem", ctx, mock.Anything, mock.Anything).Return(&dynamodb.GetItemOutput{
+		Item: map[string]types.AttributeValue{"blocks": stored},
+	}, nil).Once()
+
+	for i := 0; i < 2; i++ {
+		read, err := blocks.Blocks(ctx, 3)
+
+		require.NoError(t, err)
+		if assert.Len(t, read, 1) {
+			assert.Equal(t, "b1", read[0].BlockID)
+			assert.True(t, block.EndTime.Equal(read[0].EndTime))
+		}
+	}
+	mockClient.AssertExpectations(t)
+}
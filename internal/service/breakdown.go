@@ -0,0 +1,50 @@
+package service
+
+import (
+	"math"
+	"time"
+)
+
+// ChargeBreakdown itemizes a charge, so an exit can explain how its total was reached:
+// Total is BaseCharge plus Surcharge less Discount and PriorCharge
+type ChargeBreakdown struct {
+	// BaseCharge is the charge of the stay under the lot's pricing, or the minimum or maximum
+	// charge when one of them replaced it
+	BaseCharge float32
+	// Surcharge is what night and weekend surcharges added to the base charge
+	Surcharge float32
+	// Discount is what a promo code took off
+	Discount float32
+	// PriorCharge is what earlier exits of a reopened ticket already billed for the stay
+	PriorCharge float32
+	// Total is the charge billed
+	Total float32
+}
+
+// ChargeItemizer is implemented by charge calculators that can itemize the charges they compute
+type ChargeItemizer interface {
+	// CalculateChargeBreakdown returns the exact duration of a stay in a lot up to now, along with
+	// the rounded minutes and the itemized charge; its Total is what CalculateChargeDetailed returns
+	CalculateChargeBreakdown(parkingLot int, entryTime time.Time) (time.Duration, int, ChargeBreakdown)
+}
+
+// CalculateChargeBreakdown itemizes the charge CalculateChargeDetailed bills for a stay up to now
+func (s *ParkingLotService) CalculateChargeBreakdown(parkingLot int, entryTime time.Time) (time.Duration, int, ChargeBreakdown) {
+	return s.calculateBreakdown(s.lotPricingStrategy(parkingLot), entryTime, s.now())
+}
+
+// LessPriorCharge deducts what earlier exits of a reopened ticket billed from the total, since the
+// stay is billed from the original entry. The total does not go below zero.
+func (b ChargeBreakdown) LessPriorCharge(prior float32) ChargeBreakdown {
+	if prior <= 0 {
+		return b
+	}
+	b.PriorCharge = roundCents(min(prior, b.Total))
+	b.Total = roundCents(b.Total - b.PriorCharge)
+	return b
+}
+
+// roundCents rounds an amount to whole cents
+func roundCents(amount float32) float32 {
+	return float32(math.Round(float64(amount)*100) / 100)
+}
@@ -0,0 +1,174 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/sony/gobreaker/v2"
+
+	"parking-lot/internal/logger"
+)
+
+// ErrDynamoDBUnavailable is returned without calling DynamoDB while the circuit breaker is open
+var ErrDynamoDBUnavailable = errors.New("DynamoDB is unavailable")
+
+// Default circuit breaker settings
+const (
+	defaultBreakerFailures = 5
+	defaultBreakerCooldown = 30 * time.Second
+)
+
+// newDynamoDBBreaker creates the circuit breaker guarding DynamoDB calls from the environment.
+//
+// DYNAMODB_BREAKER_FAILURES is the number of consecutive failures that opens the breaker
+// (0 disables it) and DYNAMODB_BREAKER_COOLDOWN_SECONDS is how long it stays open before
+// letting a trial request through. It returns nil when the breaker is disabled.
+func newDynamoDBBreaker(log logger.Logger) (*gobreaker.CircuitBreaker[any], error) {
+	failures, err := envInt("DYNAMODB_BREAKER_FAILURES", defaultBreakerFailures)
+	if err != nil {
+		return nil, err
+	}
+	if failures == 0 {
+		return nil, nil
+	}
+	cooldownSeconds, err := envInt("DYNAMODB_BREAKER_COOLDOWN_SECONDS", int(defaultBreakerCooldown/time.Second))
+	if err != nil {
+		return nil, err
+	}
+
+	return newBreaker(uint32(failures), time.Duration(cooldownSeconds)*time.Second, log), nil
+}
+
+// newBreaker creates a circuit breaker that opens after the given number of consecutive failures
+func newBreaker(failures uint32, cooldown time.Duration, log logger.Logger) *gobreaker.CircuitBreaker[any] {
+	return gobreaker.NewCircuitBreaker[any](gobreaker.Settings{
+		Name:    "dynamodb",
+		Timeout: cooldown,
+		ReadyToTrip: func(counts gobreaker.Counts) bool {
+			return counts.ConsecutiveFailures >= failures
+		},
+		IsSuccessful: isBreakerSuccess,
+		IsExcluded: func(err error) bool {
+			return errors.Is(err, context.Canceled)
+		},
+		OnStateChange: func(name string, from, to gobreaker.State) {
+			log.Warn("DynamoDB circuit breaker changed state",
+				logger.Field{Key: "from", Value: from.String()},
+				logger.Field{Key: "to", Value: to.String()},
+			)
+		},
+	})
+}
+
+// isBreakerSuccess reports whether a call reached a healthy DynamoDB.
+// Rejections caused by the request itself do not count towards opening the breaker.
+func isBreakerSuccess(err error) bool {
+	var conditionFailed *types.ConditionalCheckFailedException
+	var notFound *types.ResourceNotFoundException
+	return err == nil || errors.As(err, &conditionFailed) || errors.As(err, &notFound)
+}
+
+// breakerClient wraps a DynamoDBClient so that calls fail fast while the breaker is open
+type breakerClient struct {
+	DynamoDBClient
+	breaker *gobreaker.CircuitBreaker[any]
+}
+
+// newBreakerClient wraps client with the circuit breaker
+func newBreakerClient(client DynamoDBClient, breaker *gobreaker.CircuitBreaker[any]) DynamoDBClient {
+	return &breakerClient{DynamoDBClient: client, breaker: breaker}
+}
+
+// callWithBreaker runs call through the breaker, translating fast-fails to ErrDynamoDBUnavailable
+func callWithBreaker[T any](breaker *gobreaker.CircuitBreaker[any], call func() (T, error)) (T, error) {
+	out, err := breaker.Execute(func() (any, error) {
+		return call()
+	})
+	if errors.Is(err, gobreaker.ErrOpenState) || errors.Is(err, gobreaker.ErrTooManyRequests) {
+		var zero T
+		return zero, fmt.Errorf("%w: %v", ErrDynamoDBUnavailable, err)
+	}
+	result, _ := out.(T)
+	return result, err
+}
+
+// PutItem calls PutItem through the breaker
+func (c *breakerClient) PutItem(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error) {
+	return callWithBreaker(c.breaker, func() (*dynamodb.PutItemOutput, error) {
+		return c.DynamoDBClient.PutItem(ctx, params, optFns...)
+	})
+}
+
+// GetItem calls GetItem through the breaker
+func (c *breakerClient) GetItem(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error) {
+	return callWithBreaker(c.breaker, func() (*dynamodb.GetItemOutput, error) {
+		return c.DynamoDBClient.GetItem(ctx, params, optFns...)
+	})
+}
+
+// DeleteItem calls DeleteItem through the breaker
+func (c *breakerClient) DeleteItem(ctx context.Context, params *dynamodb.DeleteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DeleteItemOutput, error) {
+	return callWithBreaker(c.breaker, func() (*dynamodb.DeleteItemOutput, error) {
+		return c.DynamoDBClient.DeleteItem(ctx, params, optFns...)
+	})
+}
+
+// UpdateItem calls UpdateItem through the breaker
+func (c *breakerClient) UpdateItem(ctx context.Context, params *dynamodb.UpdateItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error) {
+	return callWithBreaker(c.breaker, func() (*dynamodb.UpdateItemOutput, error) {
+		return c.DynamoDBClient.UpdateItem(ctx, params, optFns...)
+	})
+}
+
+// Query calls Query through the breaker
+func (c *breakerClient) Query(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+	return callWithBreaker(c.breaker, func() (*dynamodb.QueryOutput, error) {
+		return c.DynamoDBClient.Query(ctx, params, optFns...)
+	})
+}
+
+// CreateTable calls CreateTable through the breaker
+func (c *breakerClient) CreateTable(ctx context.Context, params *dynamodb.CreateTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.CreateTableOutput, error) {
+	return callWithBreaker(c.breaker, func() (*dynamodb.CreateTableOutput, error) {
+		return c.DynamoDBClient.CreateTable(ctx, params, optFns...)
+	})
+}
+
+// DescribeTable calls DescribeTable through the breaker
+func (c *breakerClient) DescribeTable(ctx context.Context, params *dynamodb.DescribeTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DescribeTableOutput, error) {
+	return callWithBreaker(c.breaker, func() (*dynamodb.DescribeTableOutput, error) {
+		return c.DynamoDBClient.DescribeTable(ctx, params, optFns...)
+	})
+}
+
+// Scan calls Scan through the breaker
+func (c *breakerClient) Scan(ctx context.Context, params *dynamodb.ScanInput, optFns ...func(*dynamodb.Options)) (*dynamodb.ScanOutput, error) {
+	return callWithBreaker(c.breaker, func() (*dynamodb.ScanOutput, error) {
+		return c.DynamoDBClient.Scan(ctx, params, optFns...)
+	})
+}
+
+// BatchWriteItem calls BatchWriteItem through the breaker
+func (c *breakerClient) BatchWriteItem(ctx context.Context, params *dynamodb.BatchWriteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.BatchWriteItemOutput, error) {
+	return callWithBreaker(c.breaker, func() (*dynamodb.BatchWriteItemOutput, error) {
+		return c.DynamoDBClient.BatchWriteItem(ctx, params, optFns...)
+	})
+}
+
+// BatchGetItem calls BatchGetItem through the breaker
+func (c *breakerClient) BatchGetItem(ctx context.Context, params *dynamodb.BatchGetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.BatchGetItemOutput, error) {
+	return callWithBreaker(c.breaker, func() (*dynamodb.BatchGetItemOutput, error) {
+		return c.DynamoDBClient.BatchGetItem(ctx, params, optFns...)
+	})
+}
+
+// TransactWriteItems calls TransactWriteItems through the breaker
+func (c *breakerClient) TransactWriteItems(ctx context.Context, params *dynamodb.TransactWriteItemsInput, optFns ...func(*dynamodb.Options)) (*dynamodb.TransactWriteItemsOutput, error) {
+	return callWithBreaker(c.breaker, func() (*dynamodb.TransactWriteItemsOutput, error) {
+		return c.DynamoDBClient.TransactWriteItems(ctx, params, optFns...)
+	})
+}
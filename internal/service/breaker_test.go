@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"parking-lot/internal/logger"
+	"parking-lot/internal/mocks"
+)
+
+// TestBreakerClient_OpensAfterConsecutiveFailures tests that the breaker fails fast once open
+func TestBreakerClient_OpensAfterConsecutiveFailures(t *testing.T) {
+	ctx := context.Background()
+	mockClient := new(mocks.DynamoDBClient)
+	breaker := newBreaker(3, time.Minute, logger.NewLogger())
+	service := &ParkingLotService{
+		ctx:          ctx,
+		client:       newBreakerClient(mockClient, breaker),
+		tableName:    "testTable",
+		log:          logger.NewLogger(),
+		marshalMap:   attributevalue.MarshalMap,
+		unmarshalMap: attributevalue.UnmarshalMap,
+		breaker:      breaker,
+	}
+
+	mockClient.On("Query", ctx, mock.Anything, mock.Anything).Return(nil, errors.New("throttled")).Times(3)
+
+	for i := 0; i < 3; i++ {
+		_, err := service.FindActiveTicket(ctx, "ABC-123", 1)
+		assert.Error(t, err)
+		assert.NotErrorIs(t, err, ErrDynamoDBUnavailable)
+	}
+	assert.False(t, service.Available())
+
+	// Further calls fail fast without reaching DynamoDB
+	_, err := service.FindActiveTicket(ctx, "ABC-123", 1)
+	assert.ErrorIs(t, err, ErrDynamoDBUnavailable)
+	_, found := service.GetTicket(ctx, "ticket")
+	assert.False(t, found)
+
+	mockClient.AssertNumberOfCalls(t, "Query", 3)
+	mockClient.AssertNotCalled(t, "GetItem", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestBreakerClient_HalfOpenRecovers tests that a successful trial request closes the breaker
+func TestBreakerClient_HalfOpenRecovers(t *testing.T) {
+	ctx := context.Background()
+	mockClient := new(mocks.DynamoDBClient)
+	breaker := newBreaker(1, 10*time.Millisecond, logger.NewLogger())
+	client := newBreakerClient(mockClient, breaker)
+
+	mockClient.On("GetItem", ctx, mock.Anything, mock.Anything).Return(nil, errors.New("timeout")).Once()
+	mockClient.On("GetItem", ctx, mock.Anything, mock.Anything).Return(&dynamodb.GetItemOutput{}, nil).Once()
+
+	_, err := client.GetItem(ctx, &dynamodb.GetItemInput{})
+	assert.EqualError(t, err, "timeout")
+
+	_, err = client.GetItem(ctx, &dynamodb.GetItemInput{})
+	assert.ErrorIs(t, err, ErrDynamoDBUnavailable)
+
+	time.Sleep(20 * time.Millisecond)
+	out, err := client.GetItem(ctx, &dynamodb.GetItemInput{})
+	assert.NoError(t, err)
+	assert.NotNil(t, out)
+	mockClient.AssertExpectations(t)
+}
+
+// TestBreakerClient_RequestErrorsDoNotTrip tests that rejections caused by the request keep the breaker closed
+func TestBreakerClient_RequestErrorsDoNotTrip(t *testing.T) {
+	ctx := context.Background()
+	mockClient := new(mocks.DynamoDBClient)
+	breaker := newBreaker(1, time.Minute, logger.NewLogger())
+	client := newBreakerClient(mockClient, breaker)
+
+	mockClient.On("PutItem", ctx, mock.Anything, mock.Anything).Return(nil, &types.ConditionalCheckFailedException{}).Twice()
+
+	for i := 0; i < 2; i++ {
+		_, err := client.PutItem(ctx, &dynamodb.PutItemInput{})
+		var conditionFailed *types.ConditionalCheckFailedException
+		assert.ErrorAs(t, err, &conditionFailed)
+	}
+	mockClient.AssertExpectations(t)
+}
+
+// TestNewDynamoDBBreaker tests the breaker configuration
+func TestNewDynamoDBBreaker(t *testing.T) {
+	t.Setenv("DYNAMODB_BREAKER_FAILURES", "0")
+	breaker, err := newDynamoDBBreaker(logger.NewLogger())
+	assert.NoError(t, err)
+	assert.Nil(t, breaker)
+
+	t.Setenv("DYNAMODB_BREAKER_FAILURES", "3")
+	t.Setenv("DYNAMODB_BREAKER_COOLDOWN_SECONDS", "soon")
+	_, err = newDynamoDBBreaker(logger.NewLogger())
+	assert.EqualError(t, err, `invalid DYNAMODB_BREAKER_COOLDOWN_SECONDS "soon"`)
+}
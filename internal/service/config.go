@@ -0,0 +1,322 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+	_ "time/tzdata" // Lambda images do not ship the timezone database
+
+	"github.com/aws/aws-sdk-go-v2/config"
+	"golang.org/x/text/currency"
+
+	"parking-lot/internal/model"
+)
+
+// defaultTableName is the tickets table used when TABLE_NAME is unset
+const defaultTableName = "parkingTickets"
+
+// ErrTableNameRequired is returned when REQUIRE_TABLE_NAME=true and TABLE_NAME is unset
+var ErrTableNameRequired = errors.New("TABLE_NAME is required")
+
+// ErrInvalidConfig wraps every error reading the service's settings from the environment, so callers
+// can tell a misconfiguration from AWS being unreachable
+var ErrInvalidConfig = errors.New("invalid configuration")
+
+// invalidConfig marks err as a configuration error
+func invalidConfig(err error) error {
+	return fmt.Errorf("%w: %w", ErrInvalidConfig, err)
+}
+
+// loadTableName reads the tickets table name from TABLE_NAME. It falls back to defaultTableName for
+// development unless REQUIRE_TABLE_NAME=true, which turns a missing name into ErrTableNameRequired.
+func loadTableName() (string, error) {
+	tableName := os.Getenv("TABLE_NAME")
+	if tableName != "" {
+		return tableName, nil
+	}
+	if os.Getenv("REQUIRE_TABLE_NAME") == "true" {
+		return "", ErrTableNameRequired
+	}
+	return defaultTableName, nil
+}
+
+// loadLotConfigs reads the per-lot configuration from the environment.
+//
+// LOT_CONFIG holds a JSON object keyed by lot number, e.g. {"382":{"capacity":120,"spots":100,"currency":"EUR","locale":"de-DE"}}.
+// DEFAULT_LOT_CAPACITY sets the capacity of lots missing from LOT_CONFIG.
+func loadLotConfigs() (map[int]model.LotConfig, model.LotConfig, error) {
+	var defaultLot model.LotConfig
+	if raw := os.Getenv("DEFAULT_LOT_CAPACITY"); raw != "" {
+		capacity, err := strconv.Atoi(raw)
+		if err != nil || capacity < 0 {
+			return nil, defaultLot, fmt.Errorf("invalid DEFAULT_LOT_CAPACITY %q", raw)
+		}
+		defaultLot.Capacity = capacity
+	}
+
+	lots := make(map[int]model.LotConfig)
+	raw := os.Getenv("LOT_CONFIG")
+	if raw == "" {
+		return lots, defaultLot, nil
+	}
+
+	var byKey map[string]model.LotConfig
+	if err := json.Unmarshal([]byte(raw), &byKey); err != nil {
+		return nil, defaultLot, fmt.Errorf("invalid LOT_CONFIG: %w", err)
+	}
+	for key, lot := range byKey {
+		id, err := strconv.Atoi(key)
+		if err != nil {
+			return nil, defaultLot, fmt.Errorf("invalid lot number %q in LOT_CONFIG", key)
+		}
+		if lot.Capacity < 0 {
+			return nil, defaultLot, fmt.Errorf("invalid capacity %d for lot %d", lot.Capacity, id)
+		}
+		if lot.Spots < 0 {
+			return nil, defaultLot, fmt.Errorf("invalid spots %d for lot %d", lot.Spots, id)
+		}
+		if lot.Currency != "" && !validCurrency(lot.Currency) {
+			return nil, defaultLot, fmt.Errorf("invalid currency %q for lot %d", lot.Currency, id)
+		}
+		if lot.Locale != "" && !model.SupportedLocale(lot.Locale) {
+			return nil, defaultLot, fmt.Errorf("unsupported locale %q for lot %d", lot.Locale, id)
+		}
+		lots[id] = lot
+	}
+
+	return lots, defaultLot, nil
+}
+
+// validCurrency reports whether a code is an ISO 4217 currency code written in uppercase, e.g. "EUR"
+func validCurrency(code string) bool {
+	unit, err := currency.ParseISO(code)
+	return err == nil && unit.String() == code
+}
+
+// checkLotCurrencies rejects lots whose currency differs from the rate schedule's. Charges are computed
+// in the schedule's currency and never converted, so a lot's currency can only restate it.
+func checkLotCurrencies(lots map[int]model.LotConfig, rates model.RateSchedule) error {
+	for id, lot := range lots {
+		if lot.Currency != "" && lot.Currency != rates.Currency {
+			return fmt.Errorf("currency %q for lot %d differs from CURRENCY %q; charges are not converted", lot.Currency, id, rates.Currency)
+		}
+	}
+	return nil
+}
+
+// Default billing settings
+const (
+	defaultIncrementMinutes = 15
+	defaultRatePerIncrement = 2.5
+	defaultCurrency         = "USD"
+)
+
+// defaultExitWindowMinutes is how long a kiosk quote is honored at exit unless EXIT_WINDOW_MINUTES is set
+const defaultExitWindowMinutes = 15
+
+// loadRateSchedule reads the billing settings from the environment.
+//
+// RATE_INCREMENT_MINUTES and RATE_PER_INCREMENT set the increment length and price,
+// CURRENCY sets the currency code and DAILY_MAX_CHARGE caps the charge per started day.
+func loadRateSchedule() (model.RateSchedule, error) {
+	incrementMinutes, err := envInt("RATE_INCREMENT_MINUTES", defaultIncrementMinutes)
+	if err != nil {
+		return model.RateSchedule{}, err
+	}
+	if incrementMinutes == 0 {
+		return model.RateSchedule{}, fmt.Errorf("invalid RATE_INCREMENT_MINUTES: must be positive")
+	}
+	ratePerIncrement, err := envFloat("RATE_PER_INCREMENT", defaultRatePerIncrement)
+	if err != nil {
+		return model.RateSchedule{}, err
+	}
+	dailyMax, err := envFloat("DAILY_MAX_CHARGE", 0)
+	if err != nil {
+		return model.RateSchedule{}, err
+	}
+	currency := os.Getenv("CURRENCY")
+	if currency == "" {
+		currency = defaultCurrency
+	}
+
+	return model.RateSchedule{
+		IncrementMinutes: incrementMinutes,
+		RatePerIncrement: float32(ratePerIncrement),
+		Currency:         currency,
+		DailyMax:         float32(dailyMax),
+	}, nil
+}
+
+// loadPricingTiers reads the tiered price list from the environment.
+//
+// PRICING_TIERS holds a JSON array of tiers, e.g.
+// [{"durationMinutes":60,"rate":5},{"rate":3}] for "first hour $5, each additional hour $3".
+// It returns nil when unset so the increment rate schedule applies.
+func loadPricingTiers() (*TieredPricingStrategy, error) {
+	raw := os.Getenv("PRICING_TIERS")
+	if raw == "" {
+		return nil, nil
+	}
+
+	var tiers []PricingTier
+	if err := json.Unmarshal([]byte(raw), &tiers); err != nil {
+		return nil, fmt.Errorf("invalid PRICING_TIERS: %w", err)
+	}
+
+	strategy, err := NewTieredPricingStrategy(tiers)
+	if err != nil {
+		return nil, fmt.Errorf("invalid PRICING_TIERS: %w", err)
+	}
+	return strategy, nil
+}
+
+// loadMaxParkDuration reads MAX_PARK_DURATION, the longest allowed stay, as a Go duration such as
+// "72h" or a number of days such as "3d". It returns zero, meaning no limit, when unset.
+func loadMaxParkDuration() (time.Duration, error) {
+	raw := os.Getenv("MAX_PARK_DURATION")
+	if raw == "" {
+		return 0, nil
+	}
+
+	var limit time.Duration
+	if days, ok := strings.CutSuffix(raw, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, fmt.Errorf("invalid MAX_PARK_DURATION %q", raw)
+		}
+		limit = time.Duration(n) * 24 * time.Hour
+	} else {
+		var err error
+		if limit, err = time.ParseDuration(raw); err != nil {
+			return 0, fmt.Errorf("invalid MAX_PARK_DURATION %q: %w", raw, err)
+		}
+	}
+	if limit <= 0 {
+		return 0, fmt.Errorf("invalid MAX_PARK_DURATION %q: must be positive", raw)
+	}
+	return limit, nil
+}
+
+// loadBillingLocation reads BILLING_TIMEZONE, an IANA timezone name such as "Asia/Jerusalem",
+// defaulting to UTC when unset
+func loadBillingLocation() (*time.Location, error) {
+	name := os.Getenv("BILLING_TIMEZONE")
+	if name == "" {
+		return time.UTC, nil
+	}
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("invalid BILLING_TIMEZONE %q: %w", name, err)
+	}
+	return location, nil
+}
+
+// loadOperatingHours reads the hours entries are accepted from the environment.
+//
+// OPEN_HOUR and CLOSE_HOUR are hours of the day (0-23) in BILLING_TIMEZONE, e.g. 6 and 22 for a lot
+// that closes overnight. It returns nil, accepting entries at any time, unless both are set;
+// equal hours also mean the lot never closes.
+func loadOperatingHours() (*operatingHours, error) {
+	if os.Getenv("OPEN_HOUR") == "" && os.Getenv("CLOSE_HOUR") == "" {
+		return nil, nil
+	}
+	if os.Getenv("OPEN_HOUR") == "" || os.Getenv("CLOSE_HOUR") == "" {
+		return nil, fmt.Errorf("OPEN_HOUR and CLOSE_HOUR must be set together")
+	}
+
+	openHour, err := envInt("OPEN_HOUR", 0)
+	if err != nil {
+		return nil, err
+	}
+	closeHour, err := envInt("CLOSE_HOUR", 0)
+	if err != nil {
+		return nil, err
+	}
+	if openHour > 23 || closeHour > 23 {
+		return nil, fmt.Errorf("invalid operating hours %d-%d: hours must be between 0 and 23", openHour, closeHour)
+	}
+	if openHour == closeHour {
+		return nil, nil
+	}
+	return &operatingHours{open: openHour, close: closeHour}, nil
+}
+
+// loadSurcharges reads the night and weekend surcharges from the environment.
+//
+// NIGHT_SURCHARGE_PCT and WEEKEND_SURCHARGE_PCT are percentages added to the charge for time parked
+// at night or on a Saturday or Sunday in BILLING_TIMEZONE, e.g. 25 for 25%. NIGHT_START_HOUR and
+// NIGHT_END_HOUR (0-23) bound the night, 22 to 6 by default. It returns nil when neither rate is set.
+func loadSurcharges() (*surcharges, error) {
+	night, err := envFloat("NIGHT_SURCHARGE_PCT", 0)
+	if err != nil {
+		return nil, err
+	}
+	weekend, err := envFloat("WEEKEND_SURCHARGE_PCT", 0)
+	if err != nil {
+		return nil, err
+	}
+	startHour, err := envInt("NIGHT_START_HOUR", defaultNightStartHour)
+	if err != nil {
+		return nil, err
+	}
+	endHour, err := envInt("NIGHT_END_HOUR", defaultNightEndHour)
+	if err != nil {
+		return nil, err
+	}
+	if startHour > 23 || endHour > 23 {
+		return nil, fmt.Errorf("invalid night hours %d-%d: hours must be between 0 and 23", startHour, endHour)
+	}
+	if night == 0 && weekend == 0 {
+		return nil, nil
+	}
+	return &surcharges{
+		night:      night / 100,
+		weekend:    weekend / 100,
+		nightHours: operatingHours{open: startHour, close: endHour},
+	}, nil
+}
+
+// awsConfigOptions returns explicit AWS SDK overrides from the environment.
+//
+// AWS_REGION selects the region and AWS_PROFILE the shared config profile, so a deployment
+// can pin its region and developers can switch profiles without relying on the default chain.
+func awsConfigOptions() []func(*config.LoadOptions) error {
+	var opts []func(*config.LoadOptions) error
+	if region := os.Getenv("AWS_REGION"); region != "" {
+		opts = append(opts, config.WithRegion(region))
+	}
+	if profile := os.Getenv("AWS_PROFILE"); profile != "" {
+		opts = append(opts, config.WithSharedConfigProfile(profile))
+	}
+	return opts
+}
+
+// envInt reads a non-negative integer value from the environment, returning def when unset
+func envInt(key string, def int) (int, error) {
+	raw := os.Getenv(key)
+	if raw == "" {
+		return def, nil
+	}
+	value, err := strconv.Atoi(raw)
+	if err != nil || value < 0 {
+		return 0, fmt.Errorf("invalid %s %q", key, raw)
+	}
+	return value, nil
+}
+
+// envFloat reads a non-negative decimal value from the environment, returning def when unset
+func envFloat(key string, def float64) (float64, error) {
+	raw := os.Getenv(key)
+	if raw == "" {
+		return def, nil
+	}
+	value, err := strconv.ParseFloat(raw, 64)
+	if err != nil || value < 0 {
+		return 0, fmt.Errorf("invalid %s %q", key, raw)
+	}
+	return value, nil
+}
@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"parking-lot/internal/logger"
+)
+
+// TestAWSConfigOptions tests that AWS_REGION and AWS_PROFILE are passed to the SDK explicitly
+func TestAWSConfigOptions(t *testing.T) {
+	apply := func(t *testing.T) config.LoadOptions {
+		var opts config.LoadOptions
+		for _, opt := range awsConfigOptions() {
+			require.NoError(t, opt(&opts))
+		}
+		return opts
+	}
+
+	t.Run("Unset", func(t *testing.T) {
+		t.Setenv("AWS_REGION", "")
+		t.Setenv("AWS_PROFILE", "")
+
+		assert.Empty(t, awsConfigOptions())
+	})
+
+	t.Run("Region", func(t *testing.T) {
+		t.Setenv("AWS_REGION", "eu-west-1")
+		t.Setenv("AWS_PROFILE", "")
+
+		opts := apply(t)
+		assert.Equal(t, "eu-west-1", opts.Region)
+		assert.Empty(t, opts.SharedConfigProfile)
+	})
+
+	t.Run("Region and profile", func(t *testing.T) {
+		t.Setenv("AWS_REGION", "us-east-2")
+		t.Setenv("AWS_PROFILE", "parking-dev")
+
+		opts := apply(t)
+		assert.Equal(t, "us-east-2", opts.Region)
+		assert.Equal(t, "parking-dev", opts.SharedConfigProfile)
+	})
+}
+
+// TestExitWindow tests loading how long kiosk quotes are honored
+func TestExitWindow(t *testing.T) {
+	t.Setenv("EXIT_WINDOW_MINUTES", "")
+	service, err := newConfiguredService(context.Background(), logger.NewLogger())
+	assert.NoError(t, err)
+	assert.Equal(t, 15*time.Minute, service.ExitWindow())
+
+	t.Setenv("EXIT_WINDOW_MINUTES", "0")
+	service, err = newConfiguredService(context.Background(), logger.NewLogger())
+	assert.NoError(t, err)
+	assert.Zero(t, service.ExitWindow())
+
+	t.Setenv("EXIT_WINDOW_MINUTES", "soon")
+	_, err = newConfiguredService(context.Background(), logger.NewLogger())
+	assert.Error(t, err)
+}
+
+// TestLoadTableName tests that TABLE_NAME defaults for development unless REQUIRE_TABLE_NAME is set
+func TestLoadTableName(t *testing.T) {
+	tests := []struct {
+		name        string
+		tableName   string
+		require     string
+		expected    string
+		expectedErr error
+	}{
+		{name: "Default", expected: "parkingTickets"},
+		{name: "Set", tableName: "tickets-prod", expected: "tickets-prod"},
+		{name: "Required and set", tableName: "tickets-prod", require: "true", expected: "tickets-prod"},
+		{name: "Required but missing", require: "true", expectedErr: ErrTableNameRequired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("TABLE_NAME", tt.tableName)
+			t.Setenv("REQUIRE_TABLE_NAME", tt.require)
+
+			tableName, err := loadTableName()
+
+			assert.ErrorIs(t, err, tt.expectedErr)
+			assert.Equal(t, tt.expected, tableName)
+		})
+	}
+
+	t.Run("Service creation fails", func(t *testing.T) {
+		t.Setenv("TABLE_NAME", "")
+		t.Setenv("REQUIRE_TABLE_NAME", "true")
+
+		_, err := NewParkingLotService(context.Background())
+
+		assert.ErrorIs(t, err, ErrTableNameRequired)
+		assert.ErrorIs(t, err, ErrInvalidConfig)
+	})
+}
+
+// TestNewParkingLotService_InvalidConfig tests that malformed settings are reported as configuration errors
+func TestNewParkingLotService_InvalidConfig(t *testing.T) {
+	for name, value := range map[string]string{
+		"MIN_CHARGE":                "free",
+		"DYNAMODB_BREAKER_FAILURES": "many",
+		"READ_ONLY_WRITE_FAILURES":  "-",
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv("TABLE_NAME", "tickets")
+			t.Setenv("AWS_REGION", "us-east-1")
+			t.Setenv(name, value)
+
+			_, err := NewParkingLotService(context.Background())
+
+			assert.ErrorIs(t, err, ErrInvalidConfig)
+		})
+	}
+}
+
+// TestLoadLotConfigs_Currency tests that a lot's currency and locale are read from LOT_CONFIG and validated
+func TestLoadLotConfigs_Currency(t *testing.T) {
+	t.Setenv("DEFAULT_LOT_CAPACITY", "")
+	t.Setenv("LOT_CONFIG", `{"382":{"currency":"EUR","locale":"de-DE"}}`)
+
+	lots, _, err := loadLotConfigs()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "EUR", lots[382].Currency)
+	assert.Equal(t, "de-DE", lots[382].Locale)
+
+	t.Setenv("LOT_CONFIG", `{"382":{"currency":"euro"}}`)
+	_, _, err = loadLotConfigs()
+	assert.ErrorContains(t, err, `invalid currency "euro" for lot 382`)
+
+	t.Setenv("LOT_CONFIG", `{"382":{"locale":"xx-XX"}}`)
+	_, _, err = loadLotConfigs()
+	assert.ErrorContains(t, err, `unsupported locale "xx-XX" for lot 382`)
+}
+
+// TestNewConfiguredService_LotCurrency tests that a lot can only restate the rate schedule's currency,
+// since charges are never converted
+func TestNewConfiguredService_LotCurrency(t *testing.T) {
+	t.Setenv("CURRENCY", "EUR")
+	t.Setenv("LOT_CONFIG", `{"382":{"currency":"EUR","locale":"de-DE"}}`)
+	_, err := newConfiguredService(context.Background(), logger.NewLogger())
+	assert.NoError(t, err)
+
+	t.Setenv("LOT_CONFIG", `{"382":{"currency":"USD"}}`)
+	_, err = newConfiguredService(context.Background(), logger.NewLogger())
+	assert.ErrorContains(t, err, `currency "USD" for lot 382 differs from CURRENCY "EUR"`)
+}
@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"parking-lot/internal/logger"
+	"parking-lot/internal/model"
+)
+
+// ErrPlateKeyed is returned when correcting a plate on a tickets table keyed by plate
+// (TABLE_KEY_SCHEMA=composite), where changing the plate would move the ticket to another item
+var ErrPlateKeyed = errors.New("plates cannot be corrected on tables keyed by plate")
+
+// PlateCorrector is implemented by services that can correct a plate mistyped at entry
+type PlateCorrector interface {
+	// CorrectPlate changes the plate of a ticket, keeping its entry time and charge,
+	// and records the correction in the event log
+	CorrectPlate(ctx context.Context, ticket *model.ParkingTicket, plate string) error
+}
+
+// CorrectPlate changes the plate of a ticket stored in DynamoDB
+func (s *ParkingLotService) CorrectPlate(ctx context.Context, ticket *model.ParkingTicket, plate string) error {
+	if s.compositeKey {
+		return ErrPlateKeyed
+	}
+	return s.correctPlate(ctx, ticket, plate, s.putTicket)
+}
+
+// correctPlate stores a ticket with its corrected plate through update and records the correction.
+// The ticket is left unchanged when storing fails.
+func (s *ParkingLotService) correctPlate(ctx context.Context, ticket *model.ParkingTicket, plate string,
+	update func(context.Context, *model.ParkingTicket) error) error {
+	previous := ticket.Plate
+	ticket.Plate = plate
+	ticket.Anonymous = model.IsAnonymousPlate(plate)
+	if err := update(ctx, ticket); err != nil {
+		ticket.Plate = previous
+		ticket.Anonymous = model.IsAnonymousPlate(previous)
+		return err
+	}
+
+	s.log.WithContext(ctx).Info("Corrected plate",
+		logger.Field{Key: "ticket_id", Value: ticket.TicketID},
+		logger.Field{Key: "previous_plate", Value: logger.Plate(previous).Value},
+		logger.Plate(plate),
+	)
+	s.recordEvent(ctx, model.TicketEvent{
+		TicketID:      ticket.TicketID,
+		Type:          model.EventTypePlateCorrection,
+		Time:          s.now(),
+		Plate:         plate,
+		PreviousPlate: previous,
+		Version:       ticket.Version,
+	})
+	return nil
+}
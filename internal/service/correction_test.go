@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"parking-lot/internal/mocks"
+	"parking-lot/internal/model"
+)
+
+// TestCorrectPlate tests that a correction stores the new plate, keeps the entry time,
+// and appends an event that replays to the same ticket
+func TestCorrectPlate(t *testing.T) {
+	ctx := context.Background()
+	mockClient := new(mocks.DynamoDBClient)
+	service := newEventsTestService(mockClient)
+
+	entryTime := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+	ticket := &model.ParkingTicket{
+		TicketID: "t1", Plate: "ABC-128", ParkingLot: 7, EntryTime: entryTime, Status: model.TicketStatusIn, SpacesUsed: 1,
+	}
+
+	mockClient.On("PutItem", ctx, mock.MatchedBy(func(in *dynamodb.PutItemInput) bool {
+		var stored model.ParkingTicket
+		return *in.TableName == "testTable" &&
+			attributevalue.UnmarshalMap(in.Item, &stored) == nil &&
+			stored.Plate == "ABC-123" && stored.EntryTime.Equal(entryTime)
+	}), mock.Anything).Return(&dynamodb.PutItemOutput{}, nil).Once()
+	var recorded model.TicketEvent
+	mockClient.On("PutItem", ctx, mock.MatchedBy(func(in *dynamodb.PutItemInput) bool {
+		return *in.TableName == "testEvents"
+	}), mock.Anything).Run(func(args mock.Arguments) {
+		assert.NoError(t, attributevalue.UnmarshalMap(args.Get(1).(*dynamodb.PutItemInput).Item, &recorded))
+	}).Return(&dynamodb.PutItemOutput{}, nil).Once()
+
+	err := service.CorrectPlate(ctx, ticket, "ABC-123")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "ABC-123", ticket.Plate)
+	assert.Equal(t, model.EventTypePlateCorrection, recorded.Type)
+	assert.Equal(t, "ABC-123", recorded.Plate)
+	assert.Equal(t, "ABC-128", recorded.PreviousPlate)
+
+	replayed := model.ParkingTicket{TicketID: "t1", Plate: "ABC-128", EntryTime: entryTime}
+	recorded.Apply(&replayed)
+	assert.Equal(t, "ABC-123", replayed.Plate)
+	assert.Equal(t, entryTime, replayed.EntryTime)
+	mockClient.AssertExpectations(t)
+}
+
+// TestCorrectPlate_Failed tests that a ticket keeps its plate when storing the correction fails
+func TestCorrectPlate_Failed(t *testing.T) {
+	ctx := context.Background()
+	mockClient := new(mocks.DynamoDBClient)
+	service := newEventsTestService(mockClient)
+	ticket := &model.ParkingTicket{TicketID: "t1", Plate: "ABC-128", Status: model.TicketStatusIn}
+
+	mockClient.On("PutItem", ctx, mock.Anything, mock.Anything).Return(nil, errors.New("boom")).Once()
+
+	err := service.CorrectPlate(ctx, ticket, "ABC-123")
+
+	assert.Error(t, err)
+	assert.Equal(t, "ABC-128", ticket.Plate)
+	mockClient.AssertExpectations(t)
+}
+
+// TestCorrectPlate_CompositeKey tests that corrections are refused when the plate is part of the key
+func TestCorrectPlate_CompositeKey(t *testing.T) {
+	service := newEventsTestService(new(mocks.DynamoDBClient))
+	service.compositeKey = true
+	ticket := &model.ParkingTicket{TicketID: "t1", Plate: "ABC-128", Status: model.TicketStatusIn}
+
+	err := service.CorrectPlate(context.Background(), ticket, "ABC-123")
+
+	assert.ErrorIs(t, err, ErrPlateKeyed)
+	assert.Equal(t, "ABC-128", ticket.Plate)
+}
@@ -0,0 +1,50 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"parking-lot/internal/logger"
+	"parking-lot/internal/model"
+)
+
+// CouponLookup is implemented by services that can look up promo codes
+type CouponLookup interface {
+	// Coupon returns the coupon with the given code, or nil when there is no such coupon
+	Coupon(ctx context.Context, code string) (*model.Coupon, error)
+}
+
+// Coupon reads a promo code from the coupons table (COUPONS_TABLE_NAME).
+// Every code is unknown when no coupons table is configured.
+func (s *ParkingLotService) Coupon(ctx context.Context, code string) (*model.Coupon, error) {
+	if s.couponsTableName == "" {
+		return nil, nil
+	}
+
+	log := s.log.WithContext(ctx).WithFields(logger.Field{Key: "coupon_code", Value: code})
+
+	result, err := s.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(s.couponsTableName),
+		Key: map[string]types.AttributeValue{
+			"code": &types.AttributeValueMemberS{Value: code},
+		},
+	})
+	if err != nil {
+		log.Error("Failed to get coupon", logger.Field{Key: "error", Value: err.Error()})
+		return nil, fmt.Errorf("failed to get coupon: %w", err)
+	}
+	if result.Item == nil {
+		return nil, nil
+	}
+
+	coupon := &model.Coupon{}
+	if err := s.unmarshalMap(result.Item, coupon); err != nil {
+		log.Error("Failed to unmarshal coupon", logger.Field{Key: "error", Value: err.Error()})
+		return nil, fmt.Errorf("failed to unmarshal coupon: %w", err)
+	}
+	return coupon, nil
+}
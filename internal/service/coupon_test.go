@@ -0,0 +1,68 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"parking-lot/internal/logger"
+	"parking-lot/internal/mocks"
+	"parking-lot/internal/model"
+)
+
+// TestCoupon tests looking up promo codes in the coupons table
+func TestCoupon(t *testing.T) {
+	ctx := context.Background()
+	mockClient := new(mocks.DynamoDBClient)
+	service := &ParkingLotService{
+		ctx:              ctx,
+		client:           mockClient,
+		tableName:        "testTable",
+		couponsTableName: "testCoupons",
+		log:              logger.NewLogger(),
+		marshalMap:       attributevalue.MarshalMap,
+		unmarshalMap:     attributevalue.UnmarshalMap,
+	}
+	codeIs := func(code string) interface{} {
+		return mock.MatchedBy(func(in *dynamodb.GetItemInput) bool {
+			return *in.TableName == "testCoupons" && in.Key["code"].(*types.AttributeValueMemberS).Value == code
+		})
+	}
+
+	expiresAt := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	item, err := attributevalue.MarshalMap(&model.Coupon{Code: "SPRING20", PercentOff: 20, ExpiresAt: &expiresAt})
+	require.NoError(t, err)
+	mockClient.On("GetItem", ctx, codeIs("SPRING20"), mock.Anything).Return(&dynamodb.GetItemOutput{Item: item}, nil).Once()
+	mockClient.On("GetItem", ctx, codeIs("NOPE"), mock.Anything).Return(&dynamodb.GetItemOutput{}, nil).Once()
+	mockClient.On("GetItem", ctx, codeIs("BROKEN"), mock.Anything).Return(nil, errors.New("throttled")).Once()
+
+	coupon, err := service.Coupon(ctx, "SPRING20")
+	require.NoError(t, err)
+	if assert.NotNil(t, coupon) {
+		assert.Equal(t, float32(20), coupon.PercentOff)
+		assert.True(t, expiresAt.Equal(*coupon.ExpiresAt))
+	}
+
+	coupon, err = service.Coupon(ctx, "NOPE")
+	assert.NoError(t, err)
+	assert.Nil(t, coupon)
+
+	_, err = service.Coupon(ctx, "BROKEN")
+	assert.Error(t, err)
+	mockClient.AssertExpectations(t)
+
+	// Without a coupons table every code is unknown
+	service.couponsTableName = ""
+	coupon, err = service.Coupon(ctx, "SPRING20")
+	assert.NoError(t, err)
+	assert.Nil(t, coupon)
+	mockClient.AssertNumberOfCalls(t, "GetItem", 3)
+}
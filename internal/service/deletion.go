@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"parking-lot/internal/logger"
+	"parking-lot/internal/model"
+)
+
+// TicketDeleter is implemented by services that can delete a ticket created in error
+type TicketDeleter interface {
+	// DeleteActiveTicket deletes the newest active ticket of a plate, returns its spaces and spot to
+	// the lot and records the deletion in the event log. It returns the deleted ticket, or
+	// ErrNoActiveTicket when the plate is not parked in any lot.
+	DeleteActiveTicket(ctx context.Context, plate string) (*model.ParkingTicket, error)
+}
+
+// DeleteActiveTicket deletes the newest active ticket of a plate from DynamoDB. The delete only
+// succeeds while the stored ticket is still in the lot, so a vehicle exiting meanwhile keeps its ticket.
+func (s *ParkingLotService) DeleteActiveTicket(ctx context.Context, plate string) (*model.ParkingTicket, error) {
+	log := s.log.WithContext(ctx).WithFields(logger.Plate(plate))
+	log.Info("Deleting active ticket")
+
+	ticket, err := s.findActivePlateTicket(ctx, plate)
+	if err != nil {
+		log.Error("Failed to look up active ticket", logger.Field{Key: "error", Value: err.Error()})
+		return nil, err
+	}
+	if ticket == nil {
+		log.Info("No active ticket found")
+		return nil, ErrNoActiveTicket
+	}
+	log = log.WithFields(logger.Field{Key: "ticket_id", Value: ticket.TicketID})
+
+	item, err := s.marshalMap(ticket)
+	if err != nil {
+		log.Error("Failed to marshal ticket for deletion", logger.Field{Key: "error", Value: err.Error()})
+		return nil, fmt.Errorf("failed to marshal ticket for deletion: %w", err)
+	}
+
+	_, err = s.client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName:           aws.String(s.table(ctx)),
+		Key:                 s.itemKey(item),
+		ConditionExpression: aws.String("#status = :status"),
+		ExpressionAttributeNames: map[string]string{
+			"#status": "status", // status is a reserved word
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":status": &types.AttributeValueMemberS{Value: string(model.TicketStatusIn)},
+		},
+	})
+	var conflict *types.ConditionalCheckFailedException
+	if errors.As(err, &conflict) {
+		log.Warn("Ticket left the lot before it was deleted")
+		return nil, ErrNoActiveTicket
+	}
+	if err != nil {
+		log.Error("Failed to delete ticket from DynamoDB", logger.Field{Key: "error", Value: err.Error()})
+		return nil, fmt.Errorf("failed to delete ticket from DynamoDB: %w", err)
+	}
+
+	s.releaseDeleted(ctx, ticket)
+	return ticket, nil
+}
+
+// releaseDeleted returns the spaces and spot of a deleted ticket to its lot and records the deletion
+func (s *ParkingLotService) releaseDeleted(ctx context.Context, ticket *model.ParkingTicket) {
+	s.ReleaseSpaces(ctx, ticket.ParkingLot, ticket.Spaces())
+	s.ReleaseSpot(ctx, ticket)
+
+	s.log.WithContext(ctx).Info("Deleted active ticket",
+		logger.Field{Key: "ticket_id", Value: ticket.TicketID},
+		logger.Field{Key: "parking_lot", Value: ticket.ParkingLot},
+		logger.Plate(ticket.Plate),
+	)
+	s.recordEvent(ctx, model.TicketEvent{
+		TicketID:   ticket.TicketID,
+		Type:       model.EventTypeDeletion,
+		Time:       s.now(),
+		Plate:      ticket.Plate,
+		ParkingLot: ticket.ParkingLot,
+		SpacesUsed: ticket.SpacesUsed,
+	})
+}
+
+// DeleteActiveTicket deletes the newest active ticket of a plate held in memory
+func (m *MemoryParkingLotService) DeleteActiveTicket(ctx context.Context, plate string) (*model.ParkingTicket, error) {
+	m.mu.Lock()
+	var active *model.ParkingTicket
+	for _, ticket := range m.tickets {
+		if ticket.Plate == plate && ticket.Status == model.TicketStatusIn &&
+			(active == nil || ticket.EntryTime.After(active.EntryTime)) {
+			active = ticket
+		}
+	}
+	if active == nil {
+		m.mu.Unlock()
+		return nil, ErrNoActiveTicket
+	}
+	delete(m.tickets, active.TicketID)
+	m.mu.Unlock()
+
+	m.releaseDeleted(ctx, active)
+	return copyTicket(active), nil
+}
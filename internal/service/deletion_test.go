@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"parking-lot/internal/mocks"
+	"parking-lot/internal/model"
+)
+
+// TestDeleteActiveTicket tests that the active ticket is deleted while still in the lot, its spaces
+// are released and the deletion is recorded
+func TestDeleteActiveTicket(t *testing.T) {
+	ctx := context.Background()
+	mockClient := new(mocks.DynamoDBClient)
+	service := newEventsTestService(mockClient)
+	occupancy := NewMemoryOccupancy(func(int) int { return 0 })
+	require.NoError(t, occupancy.Reserve(ctx, 2, 3))
+	service.occupancy = occupancy
+
+	active := &model.ParkingTicket{
+		TicketID: "ticket-id", Plate: "ABC-123", ParkingLot: 2, SpacesUsed: 2,
+		EntryTime: time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC), Status: model.TicketStatusIn,
+	}
+	item, err := attributevalue.MarshalMap(active)
+	require.NoError(t, err)
+
+	mockClient.On("Query", ctx, mock.Anything, mock.Anything).
+		Return(&dynamodb.QueryOutput{Items: []map[string]types.AttributeValue{item}}, nil).Once()
+	mockClient.On("DeleteItem", ctx, mock.MatchedBy(func(in *dynamodb.DeleteItemInput) bool {
+		return in.Key["ticketId"].(*types.AttributeValueMemberS).Value == "ticket-id" &&
+			*in.ConditionExpression == "#status = :status" &&
+			in.ExpressionAttributeValues[":status"].(*types.AttributeValueMemberS).Value == string(model.TicketStatusIn)
+	}), mock.Anything).Return(&dynamodb.DeleteItemOutput{}, nil).Once()
+	mockClient.On("PutItem", ctx, mock.MatchedBy(func(in *dynamodb.PutItemInput) bool {
+		var event model.TicketEvent
+		require.NoError(t, attributevalue.UnmarshalMap(in.Item, &event))
+		return *in.TableName == "testEvents" && event.Type == model.EventTypeDeletion &&
+			event.TicketID == "ticket-id" && event.Plate == "ABC-123" && event.ParkingLot == 2
+	}), mock.Anything).Return(&dynamodb.PutItemOutput{}, nil).Once()
+
+	ticket, err := service.DeleteActiveTicket(ctx, "ABC-123")
+
+	require.NoError(t, err)
+	assert.Equal(t, "ticket-id", ticket.TicketID)
+	assert.Equal(t, map[int]int{2: 1}, occupancy.Snapshot())
+	mockClient.AssertExpectations(t)
+}
+
+// TestDeleteActiveTicket_NoActiveTicket tests that nothing is deleted when the plate is not parked
+// or its vehicle exits before the delete
+func TestDeleteActiveTicket_NoActiveTicket(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Not parked", func(t *testing.T) {
+		mockClient := new(mocks.DynamoDBClient)
+		service := newEventsTestService(mockClient)
+		mockClient.On("Query", ctx, mock.Anything, mock.Anything).Return(&dynamodb.QueryOutput{}, nil).Once()
+
+		_, err := service.DeleteActiveTicket(ctx, "ABC-123")
+
+		assert.ErrorIs(t, err, ErrNoActiveTicket)
+		mockClient.AssertNotCalled(t, "DeleteItem", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Exited meanwhile", func(t *testing.T) {
+		mockClient := new(mocks.DynamoDBClient)
+		service := newEventsTestService(mockClient)
+		item, err := attributevalue.MarshalMap(&model.ParkingTicket{
+			TicketID: "ticket-id", Plate: "ABC-123", ParkingLot: 2, Status: model.TicketStatusIn,
+		})
+		require.NoError(t, err)
+		mockClient.On("Query", ctx, mock.Anything, mock.Anything).
+			Return(&dynamodb.QueryOutput{Items: []map[string]types.AttributeValue{item}}, nil).Once()
+		mockClient.On("DeleteItem", ctx, mock.Anything, mock.Anything).
+			Return(nil, &types.ConditionalCheckFailedException{}).Once()
+
+		_, err = service.DeleteActiveTicket(ctx, "ABC-123")
+
+		assert.ErrorIs(t, err, ErrNoActiveTicket)
+		mockClient.AssertNotCalled(t, "PutItem", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+// TestMemoryDeleteActiveTicket tests deleting a ticket held in memory frees its spaces and spot
+func TestMemoryDeleteActiveTicket(t *testing.T) {
+	ctx := context.Background()
+	t.Setenv("LOT_CONFIG", `{"3":{"capacity":10,"spots":10}}`)
+	service, err := NewMemoryParkingLotService(ctx)
+	require.NoError(t, err)
+	require.NoError(t, service.ReserveSpaces(ctx, 3, 2))
+	_, created := service.CreateTicket(ctx, "ABC-123", 3, 2)
+
+	deleted, err := service.DeleteActiveTicket(ctx, "ABC-123")
+
+	require.NoError(t, err)
+	assert.Equal(t, created.TicketID, deleted.TicketID)
+	_, ok := service.GetTicket(ctx, created.TicketID)
+	assert.False(t, ok)
+	assert.Equal(t, 0, service.OccupancySnapshot()[3])
+	// The freed spot is handed out again
+	_, next := service.CreateTicket(ctx, "DEF-456", 3, 1)
+	assert.Equal(t, created.SpotNumber, next.SpotNumber)
+
+	_, err = service.DeleteActiveTicket(ctx, "ABC-123")
+	assert.ErrorIs(t, err, ErrNoActiveTicket)
+}
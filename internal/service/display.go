@@ -0,0 +1,76 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"time"
+)
+
+// MinutesDisplay selects how the parked minutes returned to clients are rounded
+type MinutesDisplay string
+
+// Ways of displaying the parked minutes (MINUTES_DISPLAY)
+const (
+	// MinutesDisplayRound rounds to the nearest minute, so 30.6 minutes shows as 31
+	MinutesDisplayRound MinutesDisplay = "round"
+	// MinutesDisplayFloor counts whole minutes only, so 30.6 minutes shows as 30
+	MinutesDisplayFloor MinutesDisplay = "floor"
+	// MinutesDisplayBilledIncrements shows the minutes of the increments charged for,
+	// so 30.6 minutes in 15 minute increments shows as 45
+	MinutesDisplayBilledIncrements MinutesDisplay = "billedIncrements"
+)
+
+// blockBilling is implemented by pricing strategies that bill a stay in whole blocks of time
+type blockBilling interface {
+	// BilledMinutes returns the minutes covered by the blocks a stay of minutes is billed for
+	BilledMinutes(minutes float64) float64
+}
+
+// MinutesDisplayer is implemented by services that show the parked minutes of a finished stay as MINUTES_DISPLAY selects
+type MinutesDisplayer interface {
+	// DisplayMinutes returns the minutes shown to clients for a stay in a lot from entryTime to exitTime,
+	// for a ticket assigned the given pricing experiment arm ("" when none)
+	DisplayMinutes(arm string, parkingLot int, entryTime, exitTime time.Time) int
+}
+
+// loadMinutesDisplay reads MINUTES_DISPLAY, defaulting to rounding to the nearest minute
+func loadMinutesDisplay() (MinutesDisplay, error) {
+	switch mode := MinutesDisplay(os.Getenv("MINUTES_DISPLAY")); mode {
+	case "":
+		return MinutesDisplayRound, nil
+	case MinutesDisplayRound, MinutesDisplayFloor, MinutesDisplayBilledIncrements:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("invalid MINUTES_DISPLAY %q: must be %s, %s or %s",
+			mode, MinutesDisplayRound, MinutesDisplayFloor, MinutesDisplayBilledIncrements)
+	}
+}
+
+// displayMinutes turns the parked minutes into the minutes shown to clients. Billed increments
+// are taken from billedMinutes, the stay the charge was computed for, so the shown minutes match the charge.
+func (s *ParkingLotService) displayMinutes(strategy PricingStrategy, totalMinutes, billedMinutes float64) int {
+	switch s.minutesDisplay {
+	case MinutesDisplayFloor:
+		return int(math.Floor(totalMinutes))
+	case MinutesDisplayBilledIncrements:
+		if blocks, ok := strategy.(blockBilling); ok {
+			return int(math.Round(blocks.BilledMinutes(billedMinutes)))
+		}
+		return int(math.Ceil(billedMinutes))
+	default:
+		return int(math.Round(totalMinutes))
+	}
+}
+
+// DisplayMinutes returns the minutes shown for a finished stay, rounded as its exit showed them.
+// Nothing is billed, so receipts of exited tickets never recompute a charge.
+func (s *ParkingLotService) DisplayMinutes(arm string, parkingLot int, entryTime, exitTime time.Time) int {
+	duration := exitTime.Sub(entryTime)
+	tolerances := s.chargeTolerances()
+	if duration < tolerances.zeroChargeThreshold {
+		return 0
+	}
+	billedMinutes := max((s.billedUntil(entryTime, exitTime).Sub(entryTime) - tolerances.boundaryEpsilon).Minutes(), 0)
+	return s.displayMinutes(s.armPricingStrategy(arm, parkingLot), duration.Minutes(), billedMinutes)
+}
@@ -0,0 +1,70 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCalculateCharge_MinutesDisplay tests each way of displaying a stay of 30.6 minutes,
+// which is billed as three 15 minute increments
+func TestCalculateCharge_MinutesDisplay(t *testing.T) {
+	entryTime := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+	exitTime := entryTime.Add(30*time.Minute + 36*time.Second)
+
+	testCases := []struct {
+		display         MinutesDisplay
+		expectedMinutes int
+	}{
+		{display: "", expectedMinutes: 31},
+		{display: MinutesDisplayRound, expectedMinutes: 31},
+		{display: MinutesDisplayFloor, expectedMinutes: 30},
+		{display: MinutesDisplayBilledIncrements, expectedMinutes: 45},
+	}
+
+	for _, tc := range testCases {
+		t.Run(string(tc.display), func(t *testing.T) {
+			service := &ParkingLotService{minutesDisplay: tc.display}
+
+			minutes, charge := service.CalculateChargeBetween(1, entryTime, exitTime)
+
+			assert.Equal(t, tc.expectedMinutes, minutes)
+			assert.Equal(t, float32(7.5), charge)
+			// Receipts of the exited ticket show the minutes its exit showed
+			assert.Equal(t, minutes, service.DisplayMinutes("", 1, entryTime, exitTime))
+		})
+	}
+
+	t.Run("Billed tiers", func(t *testing.T) {
+		tiered, err := NewTieredPricingStrategy([]PricingTier{
+			{DurationMinutes: 20, Rate: 2, BlockMinutes: 20},
+			{Rate: 1, BlockMinutes: 30},
+		})
+		require.NoError(t, err)
+		service := &ParkingLotService{minutesDisplay: MinutesDisplayBilledIncrements, pricing: tiered}
+
+		minutes, charge := service.CalculateChargeBetween(1, entryTime, exitTime)
+
+		assert.Equal(t, 50, minutes)
+		assert.Equal(t, float32(3), charge)
+	})
+}
+
+// TestLoadMinutesDisplay tests reading MINUTES_DISPLAY
+func TestLoadMinutesDisplay(t *testing.T) {
+	t.Setenv("MINUTES_DISPLAY", "")
+	display, err := loadMinutesDisplay()
+	require.NoError(t, err)
+	assert.Equal(t, MinutesDisplayRound, display)
+
+	t.Setenv("MINUTES_DISPLAY", "billedIncrements")
+	display, err = loadMinutesDisplay()
+	require.NoError(t, err)
+	assert.Equal(t, MinutesDisplayBilledIncrements, display)
+
+	t.Setenv("MINUTES_DISPLAY", "ceil")
+	_, err = loadMinutesDisplay()
+	assert.Error(t, err)
+}
@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+
+	"parking-lot/internal/logger"
+	"parking-lot/internal/model"
+)
+
+// recordEvent appends an event to the event log; it is a no-op when no events table is configured.
+// Failures are logged rather than returned since the ticket itself has already been stored.
+func (s *ParkingLotService) recordEvent(ctx context.Context, event model.TicketEvent) {
+	if s.eventsTableName == "" {
+		return
+	}
+
+	log := s.log.WithContext(ctx).WithFields(
+		logger.Field{Key: "ticket_id", Value: event.TicketID},
+		logger.Field{Key: "event_type", Value: string(event.Type)},
+	)
+
+	if event.Sequence == 0 {
+		event.Sequence = time.Now().UnixNano()
+	}
+	// Every tenant shares the event log, so events of tenant tables name theirs for Rebuild
+	if table := s.table(ctx); table != s.tableName {
+		event.TableName = table
+	}
+	item, err := s.marshalMap(event)
+	if err != nil {
+		log.Error("Failed to marshal event", logger.Field{Key: "error", Value: err.Error()})
+		return
+	}
+
+	// The log is append-only, so never overwrite an existing event
+	_, err = s.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:           aws.String(s.eventsTableName),
+		Item:                item,
+		ConditionExpression: aws.String("attribute_not_exists(ticketId)"),
+	})
+	if err != nil {
+		log.Error("Failed to record event", logger.Field{Key: "error", Value: err.Error()})
+		return
+	}
+
+	log.Debug("Recorded event")
+}
+
+// Rebuild reconstructs every ticket from the event log and writes it to the tickets table.
+// It is an admin operation for disaster recovery; tickets are overwritten with their replayed state.
+// Only the events of the request's tickets table are replayed, so with tenant routing each tenant's
+// tickets are rebuilt into its own table.
+func (s *ParkingLotService) Rebuild(ctx context.Context) error {
+	if s.eventsTableName == "" {
+		return fmt.Errorf("event log is not configured")
+	}
+
+	target := s.table(ctx)
+	log := s.log.WithContext(ctx).WithFields(
+		logger.Field{Key: "events_table", Value: s.eventsTableName},
+		logger.Field{Key: "table_name", Value: target},
+	)
+	log.Info("Rebuilding tickets from event log")
+
+	// MAX_SCAN_PAGES does not apply: a ticket is only replayed correctly from all of its events,
+	// which a scan stopped part-way cannot guarantee
+	byTicket := make(map[string][]model.TicketEvent)
+	input := &dynamodb.ScanInput{TableName: aws.String(s.eventsTableName)}
+	for {
+		result, err := s.client.Scan(ctx, input)
+		if err != nil {
+			log.Error("Failed to scan event log", logger.Field{Key: "error", Value: err.Error()})
+			return fmt.Errorf("failed to scan event log: %w", err)
+		}
+
+		for _, item := range result.Items {
+			var event model.TicketEvent
+			if err := s.unmarshalMap(item, &event); err != nil {
+				log.Error("Failed to unmarshal event", logger.Field{Key: "error", Value: err.Error()})
+				return fmt.Errorf("failed to unmarshal event: %w", err)
+			}
+			if s.eventTable(event) != target {
+				continue
+			}
+			byTicket[event.TicketID] = append(byTicket[event.TicketID], event)
+		}
+
+		if len(result.LastEvaluatedKey) == 0 {
+			break
+		}
+		input.ExclusiveStartKey = result.LastEvaluatedKey
+	}
+
+	rebuilt := 0
+	for ticketID, events := range byTicket {
+		sort.Slice(events, func(i, j int) bool { return events[i].Sequence < events[j].Sequence })
+		// A deleted ticket was created in error, so it is not restored
+		if events[len(events)-1].Type == model.EventTypeDeletion {
+			continue
+		}
+
+		ticket := &model.ParkingTicket{TicketID: ticketID}
+		for _, event := range events {
+			event.Apply(ticket)
+		}
+
+		item, err := s.marshalMap(ticket)
+		if err != nil {
+			return fmt.Errorf("failed to marshal ticket %s: %w", ticketID, err)
+		}
+		if _, err := s.client.PutItem(ctx, &dynamodb.PutItemInput{
+			TableName: aws.String(target),
+			Item:      item,
+		}); err != nil {
+			log.Error("Failed to store rebuilt ticket",
+				logger.Field{Key: "ticket_id", Value: ticketID},
+				logger.Field{Key: "error", Value: err.Error()},
+			)
+			return fmt.Errorf("failed to store rebuilt ticket %s: %w", ticketID, err)
+		}
+		rebuilt++
+	}
+
+	log.Info("Rebuilt tickets from event log", logger.Field{Key: "tickets", Value: rebuilt})
+	return nil
+}
+
+// eventTable returns the tickets table an event's ticket is stored in; events recorded without one,
+// including those from before tenant tables were recorded, belong to the default table
+func (s *ParkingLotService) eventTable(event model.TicketEvent) string {
+	if event.TableName == "" {
+		return s.tableName
+	}
+	return event.TableName
+}
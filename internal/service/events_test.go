@@ -0,0 +1,256 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"parking-lot/internal/logger"
+	"parking-lot/internal/mocks"
+	"parking-lot/internal/model"
+)
+
+func newEventsTestService(client DynamoDBClient) *ParkingLotService {
+	return &ParkingLotService{
+		ctx:             context.Background(),
+		client:          client,
+		tableName:       "testTable",
+		eventsTableName: "testEvents",
+		log:             logger.NewLogger(),
+		marshalMap:      attributevalue.MarshalMap,
+		unmarshalMap:    attributevalue.UnmarshalMap,
+	}
+}
+
+func marshalEvent(t *testing.T, event model.TicketEvent) map[string]types.AttributeValue {
+	item, err := attributevalue.MarshalMap(event)
+	require.NoError(t, err)
+	return item
+}
+
+// TestRebuild tests that tickets are replayed from a known sequence of events
+func TestRebuild(t *testing.T) {
+	ctx := context.Background()
+	mockClient := new(mocks.DynamoDBClient)
+	service := newEventsTestService(mockClient)
+
+	entryTime := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+	exitTime := entryTime.Add(time.Hour)
+	events := []model.TicketEvent{
+		// Delivered out of order across pages to check events are sorted by sequence
+		{TicketID: "t1", Sequence: 3, Type: model.EventTypeExit, Time: exitTime, Charge: 10, DurationMinutes: 60},
+		{TicketID: "t1", Sequence: 1, Type: model.EventTypeEntry, Time: entryTime, Plate: "ABC-123", ParkingLot: 7, SpacesUsed: 1},
+		{TicketID: "t2", Sequence: 2, Type: model.EventTypeEntry, Time: entryTime, Plate: "XYZ-789", ParkingLot: 7, SpacesUsed: 2, PricingArm: "premium"},
+		{TicketID: "t1", Sequence: 4, Type: model.EventTypePayment, Time: exitTime, Charge: 10},
+		// A ticket deleted as created in error is not restored
+		{TicketID: "t3", Sequence: 5, Type: model.EventTypeEntry, Time: entryTime, Plate: "DEF-456", ParkingLot: 7, SpacesUsed: 1},
+		{TicketID: "t3", Sequence: 6, Type: model.EventTypeDeletion, Time: exitTime, Plate: "DEF-456", ParkingLot: 7, SpacesUsed: 1},
+	}
+
+	lastKey := map[string]types.AttributeValue{"ticketId": &types.AttributeValueMemberS{Value: "t2"}}
+	mockClient.On("Scan", ctx, mock.MatchedBy(func(in *dynamodb.ScanInput) bool {
+		return *in.TableName == "testEvents" && in.ExclusiveStartKey == nil
+	}), mock.Anything).Return(&dynamodb.ScanOutput{
+		Items:            []map[string]types.AttributeValue{marshalEvent(t, events[0]), marshalEvent(t, events[1])},
+		LastEvaluatedKey: lastKey,
+	}, nil).Once()
+	mockClient.On("Scan", ctx, mock.MatchedBy(func(in *dynamodb.ScanInput) bool {
+		return in.ExclusiveStartKey != nil
+	}), mock.Anything).Return(&dynamodb.ScanOutput{
+		Items: []map[string]types.AttributeValue{
+			marshalEvent(t, events[2]), marshalEvent(t, events[3]), marshalEvent(t, events[5]), marshalEvent(t, events[4]),
+		},
+	}, nil).Once()
+
+	rebuilt := make(map[string]model.ParkingTicket)
+	mockClient.On("PutItem", ctx, mock.MatchedBy(func(in *dynamodb.PutItemInput) bool {
+		return *in.TableName == "testTable"
+	}), mock.Anything).Run(func(args mock.Arguments) {
+		var ticket model.ParkingTicket
+		require.NoError(t, attributevalue.UnmarshalMap(args.Get(1).(*dynamodb.PutItemInput).Item, &ticket))
+		rebuilt[ticket.TicketID] = ticket
+	}).Return(&dynamodb.PutItemOutput{}, nil).Twice()
+
+	err := service.Rebuild(ctx)
+
+	require.NoError(t, err)
+	assert.Equal(t, map[string]model.ParkingTicket{
+		"t1": {
+			TicketID: "t1", Plate: "ABC-123", ParkingLot: 7, EntryTime: entryTime,
+			Status: model.TicketStatusOut, Charge: 10, SpacesUsed: 1, DurationMinutes: 60, ExitTime: &exitTime,
+		},
+		"t2": {
+			TicketID: "t2", Plate: "XYZ-789", ParkingLot: 7, EntryTime: entryTime,
+			Status: model.TicketStatusIn, SpacesUsed: 2, PricingArm: "premium",
+		},
+	}, rebuilt)
+	mockClient.AssertExpectations(t)
+}
+
+// TestRebuild_NotConfigured tests that Rebuild refuses to run without an event log
+func TestRebuild_NotConfigured(t *testing.T) {
+	service := newEventsTestService(new(mocks.DynamoDBClient))
+	service.eventsTableName = ""
+
+	err := service.Rebuild(context.Background())
+
+	assert.EqualError(t, err, "event log is not configured")
+}
+
+// TestCreateTicket_RecordsEntryEvent tests that issuing a ticket appends an entry event
+func TestCreateTicket_RecordsEntryEvent(t *testing.T) {
+	ctx := context.Background()
+	mockClient := new(mocks.DynamoDBClient)
+	service := newEventsTestService(mockClient)
+
+	mockClient.On("PutItem", ctx, mock.MatchedBy(func(in *dynamodb.PutItemInput) bool {
+		return *in.TableName == "testTable"
+	}), mock.Anything).Return(&dynamodb.PutItemOutput{}, nil).Once()
+	mockClient.On("PutItem", ctx, mock.MatchedBy(func(in *dynamodb.PutItemInput) bool {
+		var event model.TicketEvent
+		return *in.TableName == "testEvents" &&
+			in.ConditionExpression != nil &&
+			attributevalue.UnmarshalMap(in.Item, &event) == nil &&
+			event.Type == model.EventTypeEntry && event.Plate == "ABC-123" && event.Sequence > 0
+	}), mock.Anything).Return(&dynamodb.PutItemOutput{}, nil).Once()
+
+	service.CreateTicket(ctx, "ABC-123", 7, 1)
+
+	mockClient.AssertExpectations(t)
+}
+
+// TestUpdateTicket_RecordsExitEvent tests that closing a ticket appends an exit event and the payment of its charge
+func TestUpdateTicket_RecordsExitEvent(t *testing.T) {
+	ctx := context.Background()
+	mockClient := new(mocks.DynamoDBClient)
+	service := newEventsTestService(mockClient)
+
+	mockClient.On("PutItem", ctx, mock.MatchedBy(func(in *dynamodb.PutItemInput) bool {
+		return *in.TableName == "testTable"
+	}), mock.Anything).Return(&dynamodb.PutItemOutput{}, nil).Once()
+	mockClient.On("PutItem", ctx, mock.MatchedBy(func(in *dynamodb.PutItemInput) bool {
+		var event model.TicketEvent
+		return *in.TableName == "testEvents" &&
+			attributevalue.UnmarshalMap(in.Item, &event) == nil &&
+			event.Type == model.EventTypeExit && event.Charge == 7.5 && event.DurationMinutes == 40
+	}), mock.Anything).Return(&dynamodb.PutItemOutput{}, nil).Once()
+	mockClient.On("PutItem", ctx, mock.MatchedBy(func(in *dynamodb.PutItemInput) bool {
+		var event model.TicketEvent
+		return *in.TableName == "testEvents" &&
+			attributevalue.UnmarshalMap(in.Item, &event) == nil &&
+			event.Type == model.EventTypePayment && event.Charge == 7.5
+	}), mock.Anything).Return(&dynamodb.PutItemOutput{}, nil).Once()
+
+	err := service.UpdateTicket(ctx, &model.ParkingTicket{
+		TicketID: "t1", Status: model.TicketStatusOut, Charge: 7.5, DurationMinutes: 40,
+	})
+
+	assert.NoError(t, err)
+	mockClient.AssertExpectations(t)
+}
+
+// TestRebuild_RoundTrip tests that a ticket rebuilt from the events recorded through its entry, grace
+// re-entry, exits and payments equals the stored ticket field by field
+func TestRebuild_RoundTrip(t *testing.T) {
+	entryTime := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+	ctx := WithVehicle(context.Background(), model.Vehicle{Make: "Toyota", Model: "Corolla", Color: "Silver", TransponderID: "TAG-7"})
+	mockClient := new(mocks.DynamoDBClient)
+	service := newEventsTestService(mockClient)
+	service.clock = func() time.Time { return entryTime }
+	service.versionedUpdates = true
+	service.lots = map[int]model.LotConfig{7: {Spots: 3}}
+	service.spots = NewMemorySpots()
+	service.experiment = &pricingExperiment{arms: []ExperimentArm{{Name: "premium", RatePerIncrement: 4}}}
+
+	var stored model.ParkingTicket
+	var events []map[string]types.AttributeValue
+	mockClient.On("PutItem", mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		input := args.Get(1).(*dynamodb.PutItemInput)
+		if *input.TableName == "testEvents" {
+			events = append(events, input.Item)
+			return
+		}
+		stored = model.ParkingTicket{}
+		require.NoError(t, attributevalue.UnmarshalMap(input.Item, &stored))
+	}).Return(&dynamodb.PutItemOutput{}, nil)
+
+	exit := func(ticket *model.ParkingTicket, at time.Time, charge float32) {
+		ticket.Status = model.TicketStatusOut
+		ticket.Charge = charge
+		ticket.DurationMinutes = int(at.Sub(ticket.EntryTime).Minutes())
+		ticket.ExitTime = &at
+		require.NoError(t, service.UpdateTicket(ctx, ticket))
+		service.ReleaseSpot(ctx, ticket)
+	}
+
+	_, ticket, err := service.CreateTicketChecked(ctx, "ABC-123", 7, 2)
+	require.NoError(t, err)
+	exit(ticket, entryTime.Add(time.Hour), 8)
+	service.clock = func() time.Time { return entryTime.Add(70 * time.Minute) }
+	require.NoError(t, service.ReopenTicket(ctx, ticket))
+	exit(ticket, entryTime.Add(2*time.Hour), 6)
+
+	var payments int
+	for _, item := range events {
+		var event model.TicketEvent
+		require.NoError(t, attributevalue.UnmarshalMap(item, &event))
+		if event.Type == model.EventTypePayment {
+			payments++
+		}
+	}
+	assert.Equal(t, 2, payments)
+
+	recorded := stored
+	mockClient.On("Scan", ctx, mock.Anything, mock.Anything).Return(&dynamodb.ScanOutput{Items: events}, nil).Once()
+	require.NoError(t, service.Rebuild(ctx))
+
+	assert.Equal(t, "Toyota", recorded.Make)
+	assert.Equal(t, "TAG-7", recorded.TransponderID)
+	assert.Equal(t, "premium", recorded.PricingArm)
+	assert.Equal(t, 1, recorded.SpotNumber)
+	assert.Equal(t, 3, recorded.Version)
+	assert.Equal(t, float32(8), recorded.PriorCharge)
+	assert.Equal(t, recorded, stored)
+}
+
+// TestRebuild_TenantTables tests that events record their tenant table and that a rebuild only
+// replays the events of the table it runs for, into that table
+func TestRebuild_TenantTables(t *testing.T) {
+	mockClient := new(mocks.DynamoDBClient)
+	service := newEventsTestService(mockClient)
+	service.multiTenant = true
+	service.tenantTables = map[string]bool{"tenantA": true}
+	tenantCtx, err := service.TenantContext(context.Background(), "tenantA")
+	require.NoError(t, err)
+
+	var events []map[string]types.AttributeValue
+	rebuilt := make(map[string][]string)
+	mockClient.On("PutItem", mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		input := args.Get(1).(*dynamodb.PutItemInput)
+		if *input.TableName == "testEvents" {
+			events = append(events, input.Item)
+			return
+		}
+		var ticket model.ParkingTicket
+		require.NoError(t, attributevalue.UnmarshalMap(input.Item, &ticket))
+		rebuilt[*input.TableName] = append(rebuilt[*input.TableName], ticket.TicketID)
+	}).Return(&dynamodb.PutItemOutput{}, nil)
+
+	entryTime := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+	service.recordEvent(context.Background(), model.TicketEvent{TicketID: "default", Type: model.EventTypeEntry, Time: entryTime, Plate: "ABC-123", ParkingLot: 1})
+	service.recordEvent(tenantCtx, model.TicketEvent{TicketID: "tenant", Type: model.EventTypeEntry, Time: entryTime, Plate: "XYZ-789", ParkingLot: 1})
+	mockClient.On("Scan", mock.Anything, mock.Anything, mock.Anything).Return(&dynamodb.ScanOutput{Items: events}, nil).Twice()
+
+	require.NoError(t, service.Rebuild(context.Background()))
+	require.NoError(t, service.Rebuild(tenantCtx))
+
+	assert.Equal(t, map[string][]string{"testTable": {"default"}, "tenantA": {"tenant"}}, rebuilt)
+	mockClient.AssertExpectations(t)
+}
@@ -0,0 +1,123 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"time"
+)
+
+// ExperimentArm is one pricing strategy of a pricing experiment. An arm either bills the increment
+// rate schedule at its own rate per increment or bills its own tiered price list.
+type ExperimentArm struct {
+	// Name identifies the arm and is recorded on the tickets it bills
+	Name string `json:"name"`
+	// RatePerIncrement replaces the rate per increment of the rate schedule
+	RatePerIncrement float32 `json:"ratePerIncrement,omitempty"`
+	// Tiers replace the rate schedule with a tiered price list
+	Tiers []PricingTier `json:"tiers,omitempty"`
+}
+
+// pricingExperiment splits plates between the arms of a pricing A/B test
+type pricingExperiment struct {
+	arms []ExperimentArm
+	// tiered holds the validated price lists of arms with tiers
+	tiered map[string]*TieredPricingStrategy
+}
+
+// ExperimentCharger is implemented by services that can bill stays with the arm of a pricing experiment
+type ExperimentCharger interface {
+	// CalculateArmChargeBreakdown itemizes the charge of a stay in a lot up to now like
+	// CalculateChargeBreakdown, billed with the named arm's pricing instead of the lot's
+	CalculateArmChargeBreakdown(arm string, parkingLot int, entryTime time.Time) (time.Duration, int, ChargeBreakdown)
+}
+
+// loadPricingExperiment reads the arms of the pricing experiment from the environment.
+//
+// PRICING_EXPERIMENTS holds a JSON array of arms, e.g.
+// [{"name":"control","ratePerIncrement":2.5},{"name":"tiered","tiers":[{"durationMinutes":60,"rate":5},{"rate":3}]}].
+// It returns nil when unset so every plate is billed with the lot's pricing.
+func loadPricingExperiment() (*pricingExperiment, error) {
+	raw := os.Getenv("PRICING_EXPERIMENTS")
+	if raw == "" {
+		return nil, nil
+	}
+
+	var arms []ExperimentArm
+	if err := json.Unmarshal([]byte(raw), &arms); err != nil {
+		return nil, fmt.Errorf("invalid PRICING_EXPERIMENTS: %w", err)
+	}
+	if len(arms) == 0 {
+		return nil, fmt.Errorf("invalid PRICING_EXPERIMENTS: at least one arm is required")
+	}
+
+	experiment := &pricingExperiment{arms: arms, tiered: make(map[string]*TieredPricingStrategy)}
+	names := make(map[string]bool, len(arms))
+	for i, arm := range arms {
+		if arm.Name == "" {
+			return nil, fmt.Errorf("invalid PRICING_EXPERIMENTS: arm %d: name is required", i+1)
+		}
+		if names[arm.Name] {
+			return nil, fmt.Errorf("invalid PRICING_EXPERIMENTS: arm %q is defined more than once", arm.Name)
+		}
+		names[arm.Name] = true
+
+		switch {
+		case len(arm.Tiers) > 0 && arm.RatePerIncrement != 0:
+			return nil, fmt.Errorf("invalid PRICING_EXPERIMENTS: arm %q sets both ratePerIncrement and tiers", arm.Name)
+		case len(arm.Tiers) > 0:
+			strategy, err := NewTieredPricingStrategy(arm.Tiers)
+			if err != nil {
+				return nil, fmt.Errorf("invalid PRICING_EXPERIMENTS: arm %q: %w", arm.Name, err)
+			}
+			experiment.tiered[arm.Name] = strategy
+		case arm.RatePerIncrement <= 0:
+			return nil, fmt.Errorf("invalid PRICING_EXPERIMENTS: arm %q needs a positive ratePerIncrement or tiers", arm.Name)
+		}
+	}
+
+	return experiment, nil
+}
+
+// arm assigns a plate to an arm by hashing it, so a plate lands in the same arm on every visit
+// for as long as the arms stay the same
+func (e *pricingExperiment) arm(plate string) string {
+	hash := fnv.New32a()
+	hash.Write([]byte(plate))
+	return e.arms[hash.Sum32()%uint32(len(e.arms))].Name
+}
+
+// pricingArm returns the experiment arm a plate is billed with, or "" when no experiment runs
+func (s *ParkingLotService) pricingArm(plate string) string {
+	if s.experiment == nil {
+		return ""
+	}
+	return s.experiment.arm(plate)
+}
+
+// armPricingStrategy returns the strategy of an experiment arm in a lot. Arms that are no longer
+// configured, and lots billed at UNKNOWN_LOT_RATE, keep the lot's strategy.
+func (s *ParkingLotService) armPricingStrategy(arm string, parkingLot int) PricingStrategy {
+	strategy := s.lotPricingStrategy(parkingLot)
+	if s.experiment == nil || (s.unknownLotRate > 0 && !s.KnownLot(parkingLot)) {
+		return strategy
+	}
+
+	if tiered, ok := s.experiment.tiered[arm]; ok {
+		return tiered
+	}
+	for _, candidate := range s.experiment.arms {
+		if candidate.Name == arm && candidate.RatePerIncrement > 0 {
+			rates := s.Rates()
+			rates.RatePerIncrement = candidate.RatePerIncrement
+			return IncrementPricingStrategy{Rates: rates}
+		}
+	}
+	return strategy
+}
+
+// CalculateArmChargeBreakdown itemizes the charge of a stay up to now billed with an experiment arm
+func (s *ParkingLotService) CalculateArmChargeBreakdown(arm string, parkingLot int, entryTime time.Time) (time.Duration, int, ChargeBreakdown) {
+	return s.calculateBreakdown(s.armPricingStrategy(arm, parkingLot), entryTime, s.now())
+}
@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPricingExperiment tests that a plate always lands in the same arm and is billed with its pricing
+func TestPricingExperiment(t *testing.T) {
+	ctx := context.Background()
+	t.Setenv("LOT_CONFIG", `{"1":{"capacity":100}}`)
+	t.Setenv("INCREMENT_MINUTES", "15")
+	t.Setenv("PRICING_EXPERIMENTS", `[{"name":"control","ratePerIncrement":2},{"name":"premium","ratePerIncrement":4}]`)
+	service, err := NewMemoryParkingLotService(ctx)
+	require.NoError(t, err)
+	entry := time.Date(2025, 3, 3, 9, 0, 0, 0, time.UTC)
+	service.clock = func() time.Time { return entry }
+
+	// Find a plate in each arm
+	plates := make(map[string]string)
+	for i := 0; len(plates) < 2; i++ {
+		plate := fmt.Sprintf("EXP-%03d", i)
+		arm := service.pricingArm(plate)
+		if _, ok := plates[arm]; !ok {
+			plates[arm] = plate
+		}
+	}
+
+	expected := map[string]float32{"control": 4, "premium": 8}
+	for arm, plate := range plates {
+		t.Run(arm, func(t *testing.T) {
+			// The same plate maps to the same arm on every visit
+			for range 3 {
+				_, ticket := service.CreateTicket(ctx, plate, 1, 1)
+				require.NotNil(t, ticket)
+				assert.Equal(t, arm, ticket.PricingArm)
+			}
+
+			service.clock = func() time.Time { return entry.Add(30 * time.Minute) }
+			defer func() { service.clock = func() time.Time { return entry } }()
+			_, minutes, breakdown := service.CalculateArmChargeBreakdown(arm, 1, entry)
+
+			assert.Equal(t, 30, minutes)
+			assert.Equal(t, expected[arm], breakdown.Total)
+			assert.Equal(t, expected[arm], service.EstimateCharge(arm, 1, entry, 30*time.Minute))
+		})
+	}
+
+	t.Run("Outstanding revenue", func(t *testing.T) {
+		service.clock = func() time.Time { return entry.Add(30 * time.Minute) }
+		defer func() { service.clock = func() time.Time { return entry } }()
+
+		// Each arm parked three tickets above, each billed with its own arm
+		revenue, _, err := service.EstimatedOutstandingRevenue(ctx, 1)
+		require.NoError(t, err)
+		assert.Equal(t, 3*expected["control"]+3*expected["premium"], revenue)
+	})
+
+	t.Run("Unknown arm", func(t *testing.T) {
+		service.clock = func() time.Time { return entry.Add(30 * time.Minute) }
+		defer func() { service.clock = func() time.Time { return entry } }()
+
+		// Tickets of arms removed from the experiment keep the lot's pricing
+		_, _, breakdown := service.CalculateArmChargeBreakdown("retired", 1, entry)
+		_, _, lot := service.CalculateChargeBreakdown(1, entry)
+		assert.Equal(t, lot, breakdown)
+	})
+}
+
+// TestPricingExperiment_Disabled tests that tickets record no arm when no experiment runs
+func TestPricingExperiment_Disabled(t *testing.T) {
+	ctx := context.Background()
+	t.Setenv("PRICING_EXPERIMENTS", "")
+	service, err := NewMemoryParkingLotService(ctx)
+	require.NoError(t, err)
+
+	_, ticket := service.CreateTicket(ctx, "ABC-123", 1, 1)
+
+	require.NotNil(t, ticket)
+	assert.Empty(t, ticket.PricingArm)
+}
+
+// TestLoadPricingExperiment tests reading PRICING_EXPERIMENTS from the environment
+func TestLoadPricingExperiment(t *testing.T) {
+	t.Run("Unset", func(t *testing.T) {
+		t.Setenv("PRICING_EXPERIMENTS", "")
+
+		experiment, err := loadPricingExperiment()
+
+		require.NoError(t, err)
+		assert.Nil(t, experiment)
+	})
+
+	t.Run("Tiered arm", func(t *testing.T) {
+		t.Setenv("PRICING_EXPERIMENTS", `[{"name":"tiered","tiers":[{"durationMinutes":60,"rate":5},{"rate":3}]}]`)
+
+		experiment, err := loadPricingExperiment()
+
+		require.NoError(t, err)
+		assert.Equal(t, "tiered", experiment.arm("ABC-123"))
+		assert.Equal(t, float32(8), experiment.tiered["tiered"].Charge(90))
+	})
+
+	tests := []struct {
+		name     string
+		raw      string
+		expected string
+	}{
+		{name: "Malformed JSON", raw: `{"name":"a"}`, expected: "invalid PRICING_EXPERIMENTS"},
+		{name: "No arms", raw: `[]`, expected: "invalid PRICING_EXPERIMENTS: at least one arm is required"},
+		{name: "Missing name", raw: `[{"ratePerIncrement":2}]`, expected: "invalid PRICING_EXPERIMENTS: arm 1: name is required"},
+		{name: "Duplicate name", raw: `[{"name":"a","ratePerIncrement":2},{"name":"a","ratePerIncrement":3}]`, expected: `invalid PRICING_EXPERIMENTS: arm "a" is defined more than once`},
+		{name: "No pricing", raw: `[{"name":"a"}]`, expected: `invalid PRICING_EXPERIMENTS: arm "a" needs a positive ratePerIncrement or tiers`},
+		{name: "Both pricings", raw: `[{"name":"a","ratePerIncrement":2,"tiers":[{"rate":3}]}]`, expected: `invalid PRICING_EXPERIMENTS: arm "a" sets both ratePerIncrement and tiers`},
+		{name: "Invalid tiers", raw: `[{"name":"a","tiers":[{"rate":-1}]}]`, expected: `invalid PRICING_EXPERIMENTS: arm "a": tier 1: rate must not be negative`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("PRICING_EXPERIMENTS", tt.raw)
+
+			_, err := loadPricingExperiment()
+
+			assert.ErrorContains(t, err, tt.expected)
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"parking-lot/internal/logger"
+	"parking-lot/internal/model"
+)
+
+// TicketLister is implemented by services that can list exited tickets, e.g. for finance exports
+type TicketLister interface {
+	// ListExitedTickets calls fn for every ticket that exited in [from, to), stopping at the first error fn returns.
+	// It returns ErrScanTruncated when listing stopped at MAX_SCAN_PAGES with tickets left unread.
+	ListExitedTickets(ctx context.Context, from, to time.Time, fn func(*model.ParkingTicket) error) error
+}
+
+// exitTimeKeyMargin widens the exitTime key range of an export query. Exit times are stored as
+// RFC 3339 strings in the offset they were taken in, which sort by wall-clock time, so an exit in
+// [from, to) sorts between from and to shifted by up to the largest UTC offset, 14h, plus an hour
+// for bounds truncated to whole seconds.
+const exitTimeKeyMargin = 15 * time.Hour
+
+// exitedIn reports whether the ticket exited in [from, to)
+func exitedIn(ticket *model.ParkingTicket, from, to time.Time) bool {
+	return ticket.Status == model.TicketStatusOut && ticket.ExitTime != nil &&
+		!ticket.ExitTime.Before(from) && ticket.ExitTime.Before(to)
+}
+
+// ListExitedTickets queries the exit time index page by page and calls fn for every ticket that exited
+// in [from, to), so callers can stream results without holding the whole set. Exit times are stored
+// as formatted strings that do not sort reliably across offsets, so the key range is widened by
+// exitTimeKeyMargin and the exact range is checked here. Reading stops after MAX_SCAN_PAGES pages with
+// ErrScanTruncated, once fn has seen every ticket read so far.
+func (s *ParkingLotService) ListExitedTickets(ctx context.Context, from, to time.Time, fn func(*model.ParkingTicket) error) error {
+	log := s.log.WithContext(ctx).WithFields(
+		logger.Field{Key: "from", Value: from},
+		logger.Field{Key: "to", Value: to},
+	)
+	log.Info("Listing exited tickets")
+
+	input := &dynamodb.QueryInput{
+		TableName:              aws.String(s.table(ctx)),
+		IndexName:              aws.String(exitTimeIndexName),
+		KeyConditionExpression: aws.String("#status = :status AND exitTime BETWEEN :from AND :to"),
+		ExpressionAttributeNames: map[string]string{
+			"#status": "status", // status is a reserved word
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":status": &types.AttributeValueMemberS{Value: string(model.TicketStatusOut)},
+			":from":   &types.AttributeValueMemberS{Value: from.UTC().Add(-exitTimeKeyMargin).Format(time.RFC3339)},
+			":to":     &types.AttributeValueMemberS{Value: to.UTC().Add(exitTimeKeyMargin).Format(time.RFC3339)},
+		},
+	}
+
+	listed := 0
+	for pages := 1; ; pages++ {
+		result, err := s.client.Query(ctx, input)
+		if err != nil {
+			log.Error("Failed to query exit time index", logger.Field{Key: "error", Value: err.Error()})
+			return fmt.Errorf("failed to query exit time index: %w", err)
+		}
+
+		for _, item := range result.Items {
+			ticket := &model.ParkingTicket{}
+			if err := s.unmarshalMap(item, ticket); err != nil {
+				return fmt.Errorf("failed to unmarshal ticket: %w", err)
+			}
+			if !exitedIn(ticket, from, to) {
+				continue
+			}
+			if err := fn(ticket); err != nil {
+				return err
+			}
+			listed++
+		}
+
+		if len(result.LastEvaluatedKey) == 0 {
+			break
+		}
+		if s.maxScanPages > 0 && pages >= s.maxScanPages {
+			log.Warn("Stopped listing exited tickets at MAX_SCAN_PAGES",
+				logger.Field{Key: "pages", Value: pages},
+				logger.Field{Key: "tickets", Value: listed},
+			)
+			return ErrScanTruncated
+		}
+		input.ExclusiveStartKey = result.LastEvaluatedKey
+	}
+
+	log.Info("Listed exited tickets", logger.Field{Key: "tickets", Value: listed})
+	return nil
+}
+
+// ListExitedTickets calls fn for every stored ticket that exited in [from, to), earliest exit first
+func (m *MemoryParkingLotService) ListExitedTickets(ctx context.Context, from, to time.Time, fn func(*model.ParkingTicket) error) error {
+	m.mu.Lock()
+	var exited []*model.ParkingTicket
+	for _, ticket := range m.tickets {
+		if exitedIn(ticket, from, to) {
+			exited = append(exited, copyTicket(ticket))
+		}
+	}
+	m.mu.Unlock()
+
+	sort.Slice(exited, func(i, j int) bool { return exited[i].ExitTime.Before(*exited[j].ExitTime) })
+	for _, ticket := range exited {
+		if err := fn(ticket); err != nil {
+			return err
+		}
+	}
+	return nil
+}
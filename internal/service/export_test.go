@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"parking-lot/internal/logger"
+	"parking-lot/internal/mocks"
+	"parking-lot/internal/model"
+)
+
+// TestListExitedTickets tests that exited tickets are listed across query pages and filtered to the range
+func TestListExitedTickets(t *testing.T) {
+	ctx := context.Background()
+	mockClient := new(mocks.DynamoDBClient)
+	service := &ParkingLotService{
+		client:       mockClient,
+		tableName:    "testTable",
+		log:          logger.NewLogger(),
+		unmarshalMap: attributevalue.UnmarshalMap,
+	}
+
+	from := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 1)
+	item := func(plate string, exit time.Time) map[string]types.AttributeValue {
+		ticket := model.ParkingTicket{TicketID: plate, Plate: plate, Status: model.TicketStatusOut, ExitTime: &exit}
+		marshaled, _ := attributevalue.MarshalMap(ticket)
+		return marshaled
+	}
+
+	isStatusQuery := mock.MatchedBy(func(input *dynamodb.QueryInput) bool {
+		status, ok := input.ExpressionAttributeValues[":status"].(*types.AttributeValueMemberS)
+		lower, _ := input.ExpressionAttributeValues[":from"].(*types.AttributeValueMemberS)
+		upper, _ := input.ExpressionAttributeValues[":to"].(*types.AttributeValueMemberS)
+		return *input.IndexName == exitTimeIndexName && ok && status.Value == string(model.TicketStatusOut) &&
+			lower != nil && lower.Value == "2024-04-30T09:00:00Z" && upper != nil && upper.Value == "2024-05-02T15:00:00Z"
+	})
+	lastKey := map[string]types.AttributeValue{"ticketId": &types.AttributeValueMemberS{Value: "B"}}
+	mockClient.On("Query", ctx, isStatusQuery, mock.Anything).Return(&dynamodb.QueryOutput{
+		Items:            []map[string]types.AttributeValue{item("A", from.Add(time.Hour)), item("B", from.Add(-time.Minute))},
+		LastEvaluatedKey: lastKey,
+	}, nil).Once()
+	mockClient.On("Query", ctx, mock.MatchedBy(func(input *dynamodb.QueryInput) bool {
+		return input.ExclusiveStartKey != nil
+	}), mock.Anything).Return(&dynamodb.QueryOutput{
+		Items: []map[string]types.AttributeValue{item("C", to.Add(-time.Second)), item("D", to)},
+	}, nil).Once()
+
+	var plates []string
+	err := service.ListExitedTickets(ctx, from, to, func(ticket *model.ParkingTicket) error {
+		plates = append(plates, ticket.Plate)
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"A", "C"}, plates)
+	mockClient.AssertExpectations(t)
+}
+
+// TestListExitedTickets_MaxScanPages tests that listing stops at MAX_SCAN_PAGES after passing on the tickets read
+func TestListExitedTickets_MaxScanPages(t *testing.T) {
+	ctx := context.Background()
+	mockClient := new(mocks.DynamoDBClient)
+	service := &ParkingLotService{
+		client:       mockClient,
+		tableName:    "testTable",
+		log:          logger.NewLogger(),
+		unmarshalMap: attributevalue.UnmarshalMap,
+		maxScanPages: 1,
+	}
+
+	from := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	exit := from.Add(time.Hour)
+	item, _ := attributevalue.MarshalMap(model.ParkingTicket{TicketID: "A", Plate: "A", Status: model.TicketStatusOut, ExitTime: &exit})
+	mockClient.On("Query", ctx, mock.Anything, mock.Anything).Return(&dynamodb.QueryOutput{
+		Items:            []map[string]types.AttributeValue{item},
+		LastEvaluatedKey: map[string]types.AttributeValue{"ticketId": &types.AttributeValueMemberS{Value: "A"}},
+	}, nil).Once()
+
+	var plates []string
+	err := service.ListExitedTickets(ctx, from, from.AddDate(0, 0, 1), func(ticket *model.ParkingTicket) error {
+		plates = append(plates, ticket.Plate)
+		return nil
+	})
+
+	assert.ErrorIs(t, err, ErrScanTruncated)
+	assert.Equal(t, []string{"A"}, plates)
+	mockClient.AssertExpectations(t)
+}
+
+// TestMemoryParkingLotService_ListExitedTickets tests that only exited tickets are listed, earliest exit first
+func TestMemoryParkingLotService_ListExitedTickets(t *testing.T) {
+	ctx := context.Background()
+	service, err := NewMemoryParkingLotService(ctx)
+	assert.NoError(t, err)
+
+	first, _ := service.CreateTicket(ctx, "ABC-123", 1, 1)
+	second, _ := service.CreateTicket(ctx, "XYZ-789", 1, 1)
+	_, _ = service.CreateTicket(ctx, "PARKED-1", 1, 1)
+	for _, id := range []string{second.String(), first.String()} {
+		ticket, _ := service.GetTicket(ctx, id)
+		ticket.Status = model.TicketStatusOut
+		exitTime := time.Now()
+		ticket.ExitTime = &exitTime
+		assert.NoError(t, service.UpdateTicket(ctx, ticket))
+		time.Sleep(time.Millisecond)
+	}
+
+	var plates []string
+	err = service.ListExitedTickets(ctx, time.Now().Add(-time.Hour), time.Now().Add(time.Hour), func(ticket *model.ParkingTicket) error {
+		plates = append(plates, ticket.Plate)
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"XYZ-789", "ABC-123"}, plates)
+}
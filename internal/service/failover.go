@@ -0,0 +1,63 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"os"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/smithy-go"
+
+	"parking-lot/internal/logger"
+)
+
+// newFallbackClient creates a client for the replica of the tickets table in FALLBACK_REGION,
+// which assumes the table is a DynamoDB global table. It returns nil when FALLBACK_REGION is unset.
+// The client has no circuit breaker of its own, so it is still tried while the primary's breaker is open.
+func newFallbackClient(cfg aws.Config, log logger.Logger) DynamoDBClient {
+	region := os.Getenv("FALLBACK_REGION")
+	if region == "" {
+		return nil
+	}
+	log.Info("Reading from fallback region when the primary fails", logger.Field{Key: "fallback_region", Value: region})
+
+	fallbackCfg := cfg.Copy()
+	fallbackCfg.Region = region
+	return newTracedClient(dynamodb.NewFromConfig(fallbackCfg))
+}
+
+// shouldFailOver reports whether a failed primary read may succeed against the fallback region.
+// Server faults, network errors and an open circuit breaker are regional; requests the primary
+// rejected as invalid would be rejected by the replica too, and canceled requests are not retried.
+func shouldFailOver(ctx context.Context, err error) bool {
+	if ctx.Err() != nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if errors.Is(err, ErrDynamoDBUnavailable) {
+		return true
+	}
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.ErrorFault() == smithy.FaultServer
+	}
+	return true
+}
+
+// readWithFailover runs a read against the primary region and, when it fails with a regional error
+// and FALLBACK_REGION is set, against the fallback region. Writes never fail over.
+func readWithFailover[T any](ctx context.Context, s *ParkingLotService, read func(DynamoDBClient) (T, error)) (T, error) {
+	result, err := read(s.client)
+	if err == nil || s.fallbackClient == nil || !shouldFailOver(ctx, err) {
+		return result, err
+	}
+
+	log := s.log.WithContext(ctx)
+	log.Warn("Primary read failed, retrying in the fallback region", logger.Field{Key: "error", Value: err.Error()})
+	result, fallbackErr := read(s.fallbackClient)
+	if fallbackErr != nil {
+		log.Error("Fallback read failed", logger.Field{Key: "error", Value: fallbackErr.Error()})
+		return result, errors.Join(err, fallbackErr)
+	}
+	return result, nil
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/smithy-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"parking-lot/internal/logger"
+	"parking-lot/internal/mocks"
+	"parking-lot/internal/model"
+)
+
+// TestGetTicket_Failover tests that a ticket read failing in the primary region is retried
+// against the fallback region only when the failure is regional
+func TestGetTicket_Failover(t *testing.T) {
+	ctx := context.Background()
+	newService := func() (*ParkingLotService, *mocks.DynamoDBClient, *mocks.DynamoDBClient) {
+		primary, fallback := new(mocks.DynamoDBClient), new(mocks.DynamoDBClient)
+		return &ParkingLotService{
+			ctx:            ctx,
+			client:         primary,
+			fallbackClient: fallback,
+			tableName:      "testTable",
+			log:            logger.NewLogger(),
+			marshalMap:     attributevalue.MarshalMap,
+			unmarshalMap:   attributevalue.UnmarshalMap,
+		}, primary, fallback
+	}
+	item, err := attributevalue.MarshalMap(&model.ParkingTicket{TicketID: "t1", Plate: "ABC-123", ParkingLot: 1})
+	require.NoError(t, err)
+
+	t.Run("Primary fails, fallback succeeds", func(t *testing.T) {
+		service, primary, fallback := newService()
+		serverFault := &smithy.GenericAPIError{Code: "InternalServerError", Fault: smithy.FaultServer}
+		primary.On("GetItem", ctx, mock.Anything, mock.Anything).Return(nil, serverFault).Once()
+		fallback.On("GetItem", ctx, mock.Anything, mock.Anything).Return(&dynamodb.GetItemOutput{Item: item}, nil).Once()
+
+		ticket, ok := service.GetTicket(ctx, "t1")
+
+		require.True(t, ok)
+		assert.Equal(t, "ABC-123", ticket.Plate)
+		primary.AssertExpectations(t)
+		fallback.AssertExpectations(t)
+	})
+
+	t.Run("Both regions fail", func(t *testing.T) {
+		service, primary, fallback := newService()
+		primary.On("GetItem", ctx, mock.Anything, mock.Anything).Return(nil, ErrDynamoDBUnavailable).Once()
+		fallback.On("GetItem", ctx, mock.Anything, mock.Anything).Return(nil, errors.New("connection refused")).Once()
+
+		_, err := service.getTicketItem(ctx, "t1")
+
+		require.Error(t, err)
+		assert.ErrorIs(t, err, ErrDynamoDBUnavailable)
+		fallback.AssertExpectations(t)
+	})
+
+	t.Run("Client fault does not fail over", func(t *testing.T) {
+		service, primary, fallback := newService()
+		clientFault := &smithy.GenericAPIError{Code: "ValidationException", Fault: smithy.FaultClient}
+		primary.On("GetItem", ctx, mock.Anything, mock.Anything).Return(nil, clientFault).Once()
+
+		_, err := service.getTicketItem(ctx, "t1")
+
+		require.Error(t, err)
+		fallback.AssertNotCalled(t, "GetItem", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"parking-lot/internal/logger"
+)
+
+// ErrIndexNotActive is returned by health checks while a secondary index the service queries is
+// missing or still being created or backfilled
+var ErrIndexNotActive = errors.New("secondary index is not active")
+
+// HealthChecker is implemented by services that can check their storage is reachable
+type HealthChecker interface {
+	// HealthCheck returns an error when the storage cannot be reached
+	HealthCheck(ctx context.Context) error
+}
+
+// HealthCheck describes the tickets table, which fails while DynamoDB is unreachable
+// or the table does not exist. With CHECK_INDEX=true it also fails with ErrIndexNotActive
+// until the secondary indexes are active, since queries against them fail while they backfill.
+func (s *ParkingLotService) HealthCheck(ctx context.Context) error {
+	out, err := s.client.DescribeTable(ctx, &dynamodb.DescribeTableInput{
+		TableName: aws.String(s.tableName),
+	})
+	if err != nil {
+		s.log.WithContext(ctx).Warn("Health check failed",
+			logger.Field{Key: "table_name", Value: s.tableName},
+			logger.Field{Key: "error", Value: err.Error()},
+		)
+		return fmt.Errorf("failed to describe table: %w", err)
+	}
+	if s.checkIndexes {
+		return s.checkIndexStatus(ctx, out.Table)
+	}
+	return nil
+}
+
+// checkIndexStatus returns ErrIndexNotActive unless every secondary index the tickets table is
+// created with is active and done backfilling
+func (s *ParkingLotService) checkIndexStatus(ctx context.Context, table *types.TableDescription) error {
+	statuses := make(map[string]types.GlobalSecondaryIndexDescription)
+	if table != nil {
+		for _, index := range table.GlobalSecondaryIndexes {
+			statuses[aws.ToString(index.IndexName)] = index
+		}
+	}
+
+	for _, required := range ticketsTableInput(s.tableName, s.compositeKey).GlobalSecondaryIndexes {
+		name := aws.ToString(required.IndexName)
+		index, ok := statuses[name]
+		status := string(index.IndexStatus)
+		if !ok {
+			status = "missing"
+		} else if aws.ToBool(index.Backfilling) {
+			status = "backfilling"
+		}
+		if status != string(types.IndexStatusActive) {
+			s.log.WithContext(ctx).Warn("Health check failed, secondary index is not active",
+				logger.Field{Key: "table_name", Value: s.tableName},
+				logger.Field{Key: "index_name", Value: name},
+				logger.Field{Key: "index_status", Value: status},
+			)
+			return fmt.Errorf("%w: %s is %s", ErrIndexNotActive, name, status)
+		}
+	}
+	return nil
+}
+
+// HealthCheck always succeeds since tickets are held in memory
+func (m *MemoryParkingLotService) HealthCheck(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"parking-lot/internal/logger"
+	"parking-lot/internal/mocks"
+)
+
+// TestHealthCheck tests that the health check follows whether the tickets table can be described
+func TestHealthCheck(t *testing.T) {
+	ctx := context.Background()
+	mockClient := new(mocks.DynamoDBClient)
+	service := &ParkingLotService{
+		ctx:       ctx,
+		client:    mockClient,
+		tableName: "testTable",
+		log:       logger.NewLogger(),
+	}
+	tableIs := mock.MatchedBy(func(in *dynamodb.DescribeTableInput) bool {
+		return *in.TableName == "testTable"
+	})
+
+	mockClient.On("DescribeTable", ctx, tableIs, mock.Anything).Return(&dynamodb.DescribeTableOutput{}, nil).Once()
+	assert.NoError(t, service.HealthCheck(ctx))
+
+	mockClient.On("DescribeTable", ctx, tableIs, mock.Anything).Return(nil, errors.New("connection refused")).Once()
+	assert.ErrorContains(t, service.HealthCheck(ctx), "connection refused")
+	mockClient.AssertExpectations(t)
+
+	// Tickets held in memory are always reachable
+	memoryService, err := NewMemoryParkingLotService(ctx)
+	require.NoError(t, err)
+	assert.NoError(t, memoryService.HealthCheck(ctx))
+}
+
+// TestHealthCheck_Indexes tests that with CHECK_INDEX=true the health check fails until every
+// secondary index is active
+func TestHealthCheck_Indexes(t *testing.T) {
+	ctx := context.Background()
+	mockClient := new(mocks.DynamoDBClient)
+	service := &ParkingLotService{
+		ctx:          ctx,
+		client:       mockClient,
+		tableName:    "testTable",
+		log:          logger.NewLogger(),
+		checkIndexes: true,
+	}
+	describe := func(plateIndex types.IndexStatus, backfilling bool) *dynamodb.DescribeTableOutput {
+		table := &types.TableDescription{TableStatus: types.TableStatusActive}
+		for _, index := range ticketsTableInput("testTable", false).GlobalSecondaryIndexes {
+			description := types.GlobalSecondaryIndexDescription{IndexName: index.IndexName, IndexStatus: types.IndexStatusActive}
+			if aws.ToString(index.IndexName) == plateIndexName {
+				description.IndexStatus = plateIndex
+				description.Backfilling = aws.Bool(backfilling)
+			}
+			table.GlobalSecondaryIndexes = append(table.GlobalSecondaryIndexes, description)
+		}
+		return &dynamodb.DescribeTableOutput{Table: table}
+	}
+
+	mockClient.On("DescribeTable", ctx, mock.Anything, mock.Anything).Return(describe(types.IndexStatusCreating, false), nil).Once()
+	err := service.HealthCheck(ctx)
+	assert.ErrorIs(t, err, ErrIndexNotActive)
+	assert.ErrorContains(t, err, "PlateIndex is CREATING")
+
+	mockClient.On("DescribeTable", ctx, mock.Anything, mock.Anything).Return(describe(types.IndexStatusActive, true), nil).Once()
+	assert.ErrorIs(t, service.HealthCheck(ctx), ErrIndexNotActive)
+
+	mockClient.On("DescribeTable", ctx, mock.Anything, mock.Anything).Return(describe(types.IndexStatusActive, false), nil).Once()
+	assert.NoError(t, service.HealthCheck(ctx))
+
+	// An index the table was never given is reported as missing
+	mockClient.On("DescribeTable", ctx, mock.Anything, mock.Anything).Return(&dynamodb.DescribeTableOutput{
+		Table: &types.TableDescription{TableStatus: types.TableStatusActive},
+	}, nil).Once()
+	assert.ErrorContains(t, service.HealthCheck(ctx), "is missing")
+	mockClient.AssertExpectations(t)
+}
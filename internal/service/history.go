@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"parking-lot/internal/logger"
+	"parking-lot/internal/model"
+)
+
+// PlateHistory is implemented by services that can list the past parking sessions of a plate
+type PlateHistory interface {
+	// PlateHistory returns up to limit exited tickets of a plate across all lots, newest entry first
+	PlateHistory(ctx context.Context, plate string, limit int) ([]*model.ParkingTicket, error)
+}
+
+// PlateHistory queries PlateEntryTimeIndex, which sorts a plate's tickets by entry time, newest first.
+// Exited tickets are kept rather than deleted, so the index holds the plate's whole history;
+// parked vehicles are filtered out and pages are read until limit tickets are found.
+func (s *ParkingLotService) PlateHistory(ctx context.Context, plate string, limit int) ([]*model.ParkingTicket, error) {
+	log := s.log.WithContext(ctx).WithFields(
+		logger.Plate(plate),
+		logger.Field{Key: "limit", Value: limit},
+	)
+	log.Info("Looking up plate history")
+
+	input := &dynamodb.QueryInput{
+		TableName:              aws.String(s.table(ctx)),
+		IndexName:              aws.String(plateEntryTimeIndexName),
+		KeyConditionExpression: aws.String("plate = :plate"),
+		FilterExpression:       aws.String("#status = :status"),
+		ExpressionAttributeNames: map[string]string{
+			"#status": "status", // status is a reserved word
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":plate":  &types.AttributeValueMemberS{Value: plate},
+			":status": &types.AttributeValueMemberS{Value: string(model.TicketStatusOut)},
+		},
+		ScanIndexForward: aws.Bool(false),
+	}
+	if s.compositeKey {
+		// Plate is the partition key of composite-key tables, so query the table itself
+		input.IndexName = nil
+	}
+
+	// MAX_SCAN_PAGES does not apply, since only one plate's tickets are read, not a lot's
+	var tickets []*model.ParkingTicket
+	for len(tickets) < limit {
+		result, err := s.client.Query(ctx, input)
+		if err != nil {
+			log.Error("Failed to query plate index", logger.Field{Key: "error", Value: err.Error()})
+			return nil, fmt.Errorf("failed to query plate index: %w", err)
+		}
+
+		for _, item := range result.Items {
+			ticket := &model.ParkingTicket{}
+			if err := s.unmarshalMap(item, ticket); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal ticket: %w", err)
+			}
+			if ticket.Status != model.TicketStatusOut {
+				continue
+			}
+			tickets = append(tickets, ticket)
+			if len(tickets) == limit {
+				break
+			}
+		}
+
+		if len(result.LastEvaluatedKey) == 0 {
+			break
+		}
+		input.ExclusiveStartKey = result.LastEvaluatedKey
+	}
+
+	log.Info("Found plate history", logger.Field{Key: "tickets", Value: len(tickets)})
+	return tickets, nil
+}
+
+// PlateHistory returns the exited tickets of a plate held in memory, newest entry first
+func (m *MemoryParkingLotService) PlateHistory(ctx context.Context, plate string, limit int) ([]*model.ParkingTicket, error) {
+	m.mu.Lock()
+	var tickets []*model.ParkingTicket
+	for _, ticket := range m.tickets {
+		if ticket.Plate == plate && ticket.Status == model.TicketStatusOut {
+			tickets = append(tickets, copyTicket(ticket))
+		}
+	}
+	m.mu.Unlock()
+
+	sort.Slice(tickets, func(i, j int) bool { return tickets[i].EntryTime.After(tickets[j].EntryTime) })
+	if len(tickets) > limit {
+		tickets = tickets[:limit]
+	}
+	return tickets, nil
+}
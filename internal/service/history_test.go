@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"parking-lot/internal/logger"
+	"parking-lot/internal/mocks"
+	"parking-lot/internal/model"
+)
+
+// TestPlateHistory tests that the plate index is read newest first, skipping parked vehicles
+// and stopping once limit exited tickets are found
+func TestPlateHistory(t *testing.T) {
+	ctx := context.Background()
+	mockClient := new(mocks.DynamoDBClient)
+	service := &ParkingLotService{
+		ctx:          ctx,
+		client:       mockClient,
+		tableName:    "testTable",
+		log:          logger.NewLogger(),
+		marshalMap:   attributevalue.MarshalMap,
+		unmarshalMap: attributevalue.UnmarshalMap,
+	}
+	marshal := func(ticketID string, status model.TicketStatus) map[string]types.AttributeValue {
+		item, err := attributevalue.MarshalMap(&model.ParkingTicket{TicketID: ticketID, Plate: "ABC-123", Status: status})
+		require.NoError(t, err)
+		return item
+	}
+
+	lastKey := map[string]types.AttributeValue{"ticketId": &types.AttributeValueMemberS{Value: "t3"}}
+	mockClient.On("Query", ctx, mock.MatchedBy(func(in *dynamodb.QueryInput) bool {
+		return *in.IndexName == plateEntryTimeIndexName && !*in.ScanIndexForward &&
+			in.ExpressionAttributeValues[":plate"].(*types.AttributeValueMemberS).Value == "ABC-123" &&
+			in.ExclusiveStartKey == nil
+	}), mock.Anything).Return(&dynamodb.QueryOutput{
+		Items:            []map[string]types.AttributeValue{marshal("parked", model.TicketStatusIn), marshal("t3", model.TicketStatusOut)},
+		LastEvaluatedKey: lastKey,
+	}, nil).Once()
+	mockClient.On("Query", ctx, mock.MatchedBy(func(in *dynamodb.QueryInput) bool {
+		return in.ExclusiveStartKey != nil
+	}), mock.Anything).Return(&dynamodb.QueryOutput{
+		Items:            []map[string]types.AttributeValue{marshal("t2", model.TicketStatusOut), marshal("t1", model.TicketStatusOut)},
+		LastEvaluatedKey: map[string]types.AttributeValue{"ticketId": &types.AttributeValueMemberS{Value: "t1"}},
+	}, nil).Once()
+
+	tickets, err := service.PlateHistory(ctx, "ABC-123", 2)
+
+	require.NoError(t, err)
+	if assert.Len(t, tickets, 2) {
+		assert.Equal(t, "t3", tickets[0].TicketID)
+		assert.Equal(t, "t2", tickets[1].TicketID)
+	}
+	mockClient.AssertExpectations(t)
+}
+
+// TestMemoryParkingLotService_PlateHistory tests that past sessions are listed newest first up to the limit
+func TestMemoryParkingLotService_PlateHistory(t *testing.T) {
+	ctx := context.Background()
+	service, err := NewMemoryParkingLotService(ctx)
+	require.NoError(t, err)
+
+	now := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+	service.clock = func() time.Time { return now }
+
+	var sessions []string
+	for i := 0; i < 3; i++ {
+		now = now.Add(24 * time.Hour)
+		_, ticket := service.CreateTicket(ctx, "ABC-123", i+1, 1)
+		exitTime := now.Add(time.Hour)
+		ticket.Status = model.TicketStatusOut
+		ticket.ExitTime = &exitTime
+		require.NoError(t, service.UpdateTicket(ctx, ticket))
+		sessions = append(sessions, ticket.TicketID)
+	}
+	// Neither a parked session nor another plate is history
+	service.CreateTicket(ctx, "ABC-123", 1, 1)
+	service.CreateTicket(ctx, "XYZ-789", 1, 1)
+
+	history, err := service.PlateHistory(ctx, "ABC-123", 10)
+	require.NoError(t, err)
+	if assert.Len(t, history, 3) {
+		assert.Equal(t, sessions[2], history[0].TicketID)
+		assert.Equal(t, sessions[1], history[1].TicketID)
+		assert.Equal(t, sessions[0], history[2].TicketID)
+	}
+
+	limited, err := service.PlateHistory(ctx, "ABC-123", 2)
+	require.NoError(t, err)
+	if assert.Len(t, limited, 2) {
+		assert.Equal(t, sessions[2], limited[0].TicketID)
+		assert.Equal(t, sessions[1], limited[1].TicketID)
+	}
+}
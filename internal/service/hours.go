@@ -0,0 +1,41 @@
+package service
+
+import "time"
+
+// EntryHours is implemented by services that only accept entries during operating hours
+type EntryHours interface {
+	// EntryOpen reports whether entries are accepted right now
+	EntryOpen() bool
+}
+
+// operatingHours is a daily window, such as the hours entries are accepted in, as hours of the day in
+// the billing timezone. The window runs from open until close and wraps past midnight when close is earlier than open.
+type operatingHours struct {
+	open  int
+	close int
+}
+
+// contains reports whether the hour of the day falls inside the window
+func (h operatingHours) contains(hour int) bool {
+	if h.open <= h.close {
+		return hour >= h.open && hour < h.close
+	}
+	return hour >= h.open || hour < h.close
+}
+
+// EntryOpen reports whether entries are accepted at the current time; always true when
+// OPEN_HOUR and CLOSE_HOUR are unset
+func (s *ParkingLotService) EntryOpen() bool {
+	if s.hours == nil {
+		return true
+	}
+	return s.hours.contains(s.now().In(s.billingLocation()).Hour())
+}
+
+// billingLocation returns the timezone billing and operating hours are evaluated in
+func (s *ParkingLotService) billingLocation() *time.Location {
+	if s.location != nil {
+		return s.location
+	}
+	return time.UTC
+}
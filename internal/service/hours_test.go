@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"parking-lot/internal/logger"
+)
+
+// TestEntryOpen tests accepting entries inside and outside the operating window
+func TestEntryOpen(t *testing.T) {
+	jerusalem, err := time.LoadLocation("Asia/Jerusalem")
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		hours    *operatingHours
+		location *time.Location
+		now      time.Time
+		expected bool
+	}{
+		{name: "No operating hours", now: time.Date(2024, 5, 1, 3, 0, 0, 0, time.UTC), expected: true},
+		{name: "Inside daytime window", hours: &operatingHours{open: 6, close: 22}, now: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC), expected: true},
+		{name: "At opening hour", hours: &operatingHours{open: 6, close: 22}, now: time.Date(2024, 5, 1, 6, 0, 0, 0, time.UTC), expected: true},
+		{name: "At closing hour", hours: &operatingHours{open: 6, close: 22}, now: time.Date(2024, 5, 1, 22, 0, 0, 0, time.UTC), expected: false},
+		{name: "Overnight", hours: &operatingHours{open: 6, close: 22}, now: time.Date(2024, 5, 1, 3, 0, 0, 0, time.UTC), expected: false},
+		{name: "Inside window wrapping midnight", hours: &operatingHours{open: 20, close: 4}, now: time.Date(2024, 5, 1, 1, 0, 0, 0, time.UTC), expected: true},
+		{name: "Outside window wrapping midnight", hours: &operatingHours{open: 20, close: 4}, now: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC), expected: false},
+		// 04:30 UTC is 07:30 in Jerusalem during daylight saving time
+		{name: "Billing timezone", hours: &operatingHours{open: 6, close: 22}, location: jerusalem, now: time.Date(2024, 5, 1, 4, 30, 0, 0, time.UTC), expected: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &ParkingLotService{
+				hours:    tt.hours,
+				location: tt.location,
+				clock:    func() time.Time { return tt.now },
+			}
+			assert.Equal(t, tt.expected, service.EntryOpen())
+		})
+	}
+}
+
+// TestLoadOperatingHours tests reading OPEN_HOUR, CLOSE_HOUR and BILLING_TIMEZONE
+func TestLoadOperatingHours(t *testing.T) {
+	tests := []struct {
+		name      string
+		open      string
+		close     string
+		timezone  string
+		expected  *operatingHours
+		expectErr bool
+	}{
+		{name: "Unset"},
+		{name: "Daytime", open: "6", close: "22", expected: &operatingHours{open: 6, close: 22}},
+		{name: "Never closes", open: "0", close: "0"},
+		{name: "Only one set", open: "6", expectErr: true},
+		{name: "Out of range", open: "6", close: "24", expectErr: true},
+		{name: "Unknown timezone", timezone: "Mars/Olympus", expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("OPEN_HOUR", tt.open)
+			t.Setenv("CLOSE_HOUR", tt.close)
+			t.Setenv("BILLING_TIMEZONE", tt.timezone)
+
+			service, err := newConfiguredService(context.Background(), logger.NewLogger())
+
+			if tt.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, service.hours)
+			assert.Equal(t, time.UTC, service.billingLocation())
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// IDGenerator creates the IDs of new parking tickets
+type IDGenerator interface {
+	// NewID returns a new unique ticket ID
+	NewID() string
+}
+
+// UUIDGenerator generates random UUID ticket IDs
+type UUIDGenerator struct{}
+
+// NewID returns a new random UUID
+func (UUIDGenerator) NewID() string {
+	return uuid.New().String()
+}
+
+// newTicketID returns a ticket ID from the configured generator, defaulting to random UUIDs.
+// The API still exposes ticket IDs as UUIDs, so generated IDs must parse as one.
+func (s *ParkingLotService) newTicketID() (uuid.UUID, error) {
+	if s.ids == nil {
+		return uuid.New(), nil
+	}
+	id := s.ids.NewID()
+	ticketID, err := uuid.Parse(id)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("failed to parse generated ticket ID %q: %w", id, err)
+	}
+	return ticketID, nil
+}
@@ -0,0 +1,44 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/aws/smithy-go"
+	"github.com/google/uuid"
+
+	"parking-lot/internal/model"
+)
+
+// ErrItemTooLarge is returned when a ticket exceeds DynamoDB's 400 KB item size limit,
+// e.g. because of an extremely long plate
+var ErrItemTooLarge = errors.New("ticket exceeds the DynamoDB item size limit")
+
+// TicketCreator is implemented by services that report why a new ticket could not be stored
+type TicketCreator interface {
+	// CreateTicketChecked creates a ticket like CreateTicket and also returns the storage error,
+	// which is ErrItemTooLarge when the ticket can never be stored
+	CreateTicketChecked(ctx context.Context, plate string, parkingLot int, spaces int) (uuid.UUID, *model.ParkingTicket, error)
+}
+
+// isItemTooLarge reports whether err is DynamoDB rejecting an item for its size. DynamoDB reports
+// this as a generic ValidationException, so the message tells it apart from other validation failures.
+func isItemTooLarge(err error) bool {
+	var apiErr smithy.APIError
+	if !errors.As(err, &apiErr) || apiErr.ErrorCode() != "ValidationException" {
+		return false
+	}
+	message := strings.ToLower(apiErr.ErrorMessage())
+	return strings.Contains(message, "item size") && strings.Contains(message, "exceeded")
+}
+
+// wrapItemTooLarge translates an item size ValidationException into ErrItemTooLarge, keeping the
+// original error text, and returns any other error unchanged
+func wrapItemTooLarge(err error) error {
+	if isItemTooLarge(err) {
+		return fmt.Errorf("%w: %v", ErrItemTooLarge, err)
+	}
+	return err
+}
@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/smithy-go"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"parking-lot/internal/logger"
+	"parking-lot/internal/mocks"
+	"parking-lot/internal/model"
+)
+
+// itemSizeError is the error DynamoDB returns for an item over 400 KB
+var itemSizeError = &smithy.GenericAPIError{
+	Code:    "ValidationException",
+	Message: "Item size has exceeded the maximum allowed size",
+}
+
+// TestIsItemTooLarge tests telling item size errors apart from other validation errors
+func TestIsItemTooLarge(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{name: "Item size", err: itemSizeError, expected: true},
+		{name: "Wrapped item size", err: errors.Join(errors.New("operation error"), itemSizeError), expected: true},
+		{name: "Other validation error", err: &smithy.GenericAPIError{Code: "ValidationException", Message: "One or more parameter values were invalid"}},
+		{name: "Other API error", err: &smithy.GenericAPIError{Code: "ThrottlingException", Message: "Item size has exceeded the maximum allowed size"}},
+		{name: "Plain error", err: errors.New("boom")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, isItemTooLarge(tt.err))
+		})
+	}
+}
+
+// TestItemTooLarge tests that an oversized ticket is reported as ErrItemTooLarge on create and update
+func TestItemTooLarge(t *testing.T) {
+	ctx := context.Background()
+	mockClient := new(mocks.DynamoDBClient)
+	service := &ParkingLotService{
+		client:       mockClient,
+		tableName:    "testTable",
+		log:          logger.NewLogger(),
+		marshalMap:   attributevalue.MarshalMap,
+		unmarshalMap: attributevalue.UnmarshalMap,
+	}
+	mockClient.On("PutItem", ctx, mock.Anything, mock.Anything).Return(nil, itemSizeError)
+
+	ticketID, ticket, err := service.CreateTicketChecked(ctx, "ABC-123", 1, 1)
+	assert.ErrorIs(t, err, ErrItemTooLarge)
+	assert.Equal(t, uuid.Nil, ticketID)
+	assert.Nil(t, ticket)
+
+	// Unlike other store failures, an oversized ticket is not returned best effort
+	_, ticket = service.CreateTicket(ctx, "ABC-123", 1, 1)
+	assert.Nil(t, ticket)
+
+	err = service.UpdateTicket(ctx, &model.ParkingTicket{TicketID: "ticket-1", EntryTime: time.Now(), Status: model.TicketStatusIn})
+	assert.ErrorIs(t, err, ErrItemTooLarge)
+}
@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// Key schemas of the tickets table, selected with TABLE_KEY_SCHEMA
+const (
+	// keySchemaTicketID keys tickets by ticketId alone; plate lookups go through PlateIndex
+	keySchemaTicketID = "ticketId"
+	// keySchemaComposite keys tickets by plate and entryTime, so a plate's tickets share a partition
+	// and plate lookups query the table itself; ticket ID lookups go through TicketIdIndex
+	keySchemaComposite = "composite"
+)
+
+// ticketIDIndexName is the global secondary index keyed by ticket ID on composite-key tables
+const ticketIDIndexName = "TicketIdIndex"
+
+// loadCompositeKey reads TABLE_KEY_SCHEMA and reports whether the tickets table uses the composite key
+func loadCompositeKey() (bool, error) {
+	switch schema := os.Getenv("TABLE_KEY_SCHEMA"); schema {
+	case "", keySchemaTicketID:
+		return false, nil
+	case keySchemaComposite:
+		return true, nil
+	default:
+		return false, fmt.Errorf("invalid TABLE_KEY_SCHEMA %q: must be %q or %q", schema, keySchemaTicketID, keySchemaComposite)
+	}
+}
+
+// itemKey returns the primary key of a stored ticket item, which must hold the key attributes
+func (s *ParkingLotService) itemKey(item map[string]types.AttributeValue) map[string]types.AttributeValue {
+	if s.compositeKey {
+		return map[string]types.AttributeValue{
+			"plate":     item["plate"],
+			"entryTime": item["entryTime"],
+		}
+	}
+	return map[string]types.AttributeValue{
+		"ticketId": item["ticketId"],
+	}
+}
+
+// configItemKey returns the primary key of a config item such as the maintenance flag.
+// On composite-key tables the name fills both key attributes; no plate contains '#', so it never
+// collides with a ticket.
+func (s *ParkingLotService) configItemKey(name string) map[string]types.AttributeValue {
+	if s.compositeKey {
+		return map[string]types.AttributeValue{
+			"plate":     &types.AttributeValueMemberS{Value: name},
+			"entryTime": &types.AttributeValueMemberS{Value: name},
+		}
+	}
+	return map[string]types.AttributeValue{
+		"ticketId": &types.AttributeValueMemberS{Value: name},
+	}
+}
+
+// consistentRead returns the ConsistentRead setting for reads from the tickets table; nil leaves
+// DynamoDB's eventually consistent default
+func (s *ParkingLotService) consistentRead() *bool {
+	if !s.consistentReads {
+		return nil
+	}
+	return aws.Bool(true)
+}
+
+// projection returns a ProjectionExpression reading only the given attributes, with a placeholder
+// name for each so reserved words such as status can be projected; no attributes reads the whole item
+func projection(attributes []string) (*string, map[string]string) {
+	if len(attributes) == 0 {
+		return nil, nil
+	}
+	placeholders := make([]string, len(attributes))
+	names := make(map[string]string, len(attributes))
+	for i, attribute := range attributes {
+		placeholders[i] = "#p" + strconv.Itoa(i)
+		names[placeholders[i]] = attribute
+	}
+	return aws.String(strings.Join(placeholders, ", ")), names
+}
+
+// getTicketItem fetches the stored item of a ticket, returning nil when there is none.
+// When attributes are given only those are read, which costs less for large items.
+// Composite-key tables cannot get an item by ticket ID, so they query TicketIdIndex instead;
+// index queries are always eventually consistent.
+func (s *ParkingLotService) getTicketItem(ctx context.Context, ticketID string, attributes ...string) (map[string]types.AttributeValue, error) {
+	projectionExpression, names := projection(attributes)
+	if !s.compositeKey {
+		input := &dynamodb.GetItemInput{
+			TableName: aws.String(s.table(ctx)),
+			Key: map[string]types.AttributeValue{
+				"ticketId": &types.AttributeValueMemberS{Value: ticketID},
+			},
+			ConsistentRead:           s.consistentRead(),
+			ProjectionExpression:     projectionExpression,
+			ExpressionAttributeNames: names,
+		}
+		result, err := readWithFailover(ctx, s, func(client DynamoDBClient) (*dynamodb.GetItemOutput, error) {
+			return client.GetItem(ctx, input)
+		})
+		if err != nil {
+			return nil, err
+		}
+		return result.Item, nil
+	}
+
+	input := &dynamodb.QueryInput{
+		TableName:              aws.String(s.table(ctx)),
+		IndexName:              aws.String(ticketIDIndexName),
+		KeyConditionExpression: aws.String("ticketId = :ticketId"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":ticketId": &types.AttributeValueMemberS{Value: ticketID},
+		},
+		ProjectionExpression:     projectionExpression,
+		ExpressionAttributeNames: names,
+	}
+	result, err := readWithFailover(ctx, s, func(client DynamoDBClient) (*dynamodb.QueryOutput, error) {
+		return client.Query(ctx, input)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(result.Items) == 0 {
+		return nil, nil
+	}
+	return result.Items[0], nil
+}
@@ -0,0 +1,130 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"parking-lot/internal/logger"
+	"parking-lot/internal/mocks"
+	"parking-lot/internal/model"
+)
+
+// TestCompositeKey_RoundTrip tests storing, finding, updating and removing a ticket on a table keyed by plate and entryTime
+func TestCompositeKey_RoundTrip(t *testing.T) {
+	ctx := context.Background()
+	mockClient := new(mocks.DynamoDBClient)
+	service := &ParkingLotService{
+		ctx:          ctx,
+		client:       mockClient,
+		tableName:    "testTable",
+		log:          logger.NewLogger(),
+		marshalMap:   attributevalue.MarshalMap,
+		unmarshalMap: attributevalue.UnmarshalMap,
+		compositeKey: true,
+	}
+
+	// The mock table keeps the last stored item
+	var stored map[string]types.AttributeValue
+	store := func(args mock.Arguments) { stored = args.Get(1).(*dynamodb.PutItemInput).Item }
+	mockClient.On("PutItem", ctx, mock.Anything, mock.Anything).Run(store).Return(&dynamodb.PutItemOutput{}, nil).Twice()
+
+	ticketID, ticket := service.CreateTicket(ctx, "ABC-123", 7, 1)
+	assert.NotNil(t, ticket)
+	assert.Equal(t, "ABC-123", stored["plate"].(*types.AttributeValueMemberS).Value)
+	assert.NotEmpty(t, stored["entryTime"].(*types.AttributeValueMemberS).Value)
+
+	// Ticket ID lookups query TicketIdIndex
+	byTicketID := mock.MatchedBy(func(input *dynamodb.QueryInput) bool {
+		id, ok := input.ExpressionAttributeValues[":ticketId"].(*types.AttributeValueMemberS)
+		return input.IndexName != nil && *input.IndexName == ticketIDIndexName && ok && id.Value == ticketID.String()
+	})
+	mockClient.On("Query", ctx, byTicketID, mock.Anything).Return(&dynamodb.QueryOutput{Items: []map[string]types.AttributeValue{stored}}, nil).Twice()
+
+	found, ok := service.GetTicket(ctx, ticketID.String())
+	assert.True(t, ok)
+	assert.Equal(t, ticket.Plate, found.Plate)
+	assert.True(t, ticket.EntryTime.Equal(found.EntryTime))
+
+	// Plate lookups query the table itself
+	byPlate := mock.MatchedBy(func(input *dynamodb.QueryInput) bool {
+		_, ok := input.ExpressionAttributeValues[":plate"]
+		return input.IndexName == nil && ok
+	})
+	mockClient.On("Query", ctx, byPlate, mock.Anything).Return(&dynamodb.QueryOutput{Items: []map[string]types.AttributeValue{stored}}, nil).Once()
+
+	active, err := service.FindActiveTicket(ctx, "ABC-123", 7)
+	assert.NoError(t, err)
+	assert.Equal(t, ticketID.String(), active.TicketID)
+
+	// Updates overwrite the item under the same plate and entry time
+	found.Status = model.TicketStatusOut
+	assert.NoError(t, service.UpdateTicket(ctx, found))
+	assert.Equal(t, "out", stored["status"].(*types.AttributeValueMemberS).Value)
+
+	// Removal deletes by plate and entry time
+	mockClient.On("DeleteItem", ctx, mock.MatchedBy(func(input *dynamodb.DeleteItemInput) bool {
+		plate, ok := input.Key["plate"].(*types.AttributeValueMemberS)
+		_, hasTicketID := input.Key["ticketId"]
+		return ok && plate.Value == "ABC-123" && input.Key["entryTime"] != nil && !hasTicketID
+	}), mock.Anything).Return(&dynamodb.DeleteItemOutput{}, nil).Once()
+
+	service.RemoveTicket(ctx, ticketID.String())
+
+	mockClient.AssertExpectations(t)
+	mockClient.AssertNotCalled(t, "GetItem", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestLoadCompositeKey tests reading TABLE_KEY_SCHEMA
+func TestLoadCompositeKey(t *testing.T) {
+	for schema, expected := range map[string]bool{"": false, "ticketId": false, "composite": true} {
+		t.Setenv("TABLE_KEY_SCHEMA", schema)
+		composite, err := loadCompositeKey()
+		assert.NoError(t, err)
+		assert.Equal(t, expected, composite, schema)
+	}
+
+	t.Setenv("TABLE_KEY_SCHEMA", "plate")
+	_, err := loadCompositeKey()
+	assert.Error(t, err)
+}
+
+// TestTicketsTableInput_CompositeKey tests the composite-key table definition
+func TestTicketsTableInput_CompositeKey(t *testing.T) {
+	input := ticketsTableInput("testTable", true)
+
+	assert.Equal(t, "plate", *input.KeySchema[0].AttributeName)
+	assert.Equal(t, types.KeyTypeHash, input.KeySchema[0].KeyType)
+	assert.Equal(t, "entryTime", *input.KeySchema[1].AttributeName)
+	assert.Equal(t, types.KeyTypeRange, input.KeySchema[1].KeyType)
+
+	var indexes []string
+	for _, index := range input.GlobalSecondaryIndexes {
+		indexes = append(indexes, *index.IndexName)
+	}
+	assert.Contains(t, indexes, ticketIDIndexName)
+	assert.NotContains(t, indexes, plateIndexName)
+	assert.NotContains(t, indexes, plateEntryTimeIndexName)
+}
+
+// TestTicketsTableInput_PlateIndexes tests that PlateIndex stays keyed by plate alone, with the
+// entry time sort kept in its own index
+func TestTicketsTableInput_PlateIndexes(t *testing.T) {
+	indexes := make(map[string][]types.KeySchemaElement)
+	for _, index := range ticketsTableInput("testTable", false).GlobalSecondaryIndexes {
+		indexes[*index.IndexName] = index.KeySchema
+	}
+
+	if assert.Len(t, indexes[plateIndexName], 1) {
+		assert.Equal(t, "plate", *indexes[plateIndexName][0].AttributeName)
+	}
+	if assert.Len(t, indexes[plateEntryTimeIndexName], 2) {
+		assert.Equal(t, "plate", *indexes[plateEntryTimeIndexName][0].AttributeName)
+		assert.Equal(t, "entryTime", *indexes[plateEntryTimeIndexName][1].AttributeName)
+	}
+}
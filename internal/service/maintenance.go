@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"parking-lot/internal/logger"
+)
+
+// MaintenanceStore is implemented by services that can stop new entries during lot maintenance
+type MaintenanceStore interface {
+	// MaintenanceMode reports whether new entries are currently rejected
+	MaintenanceMode(ctx context.Context) (bool, error)
+	// SetMaintenanceMode turns maintenance mode on or off for every instance
+	SetMaintenanceMode(ctx context.Context, enabled bool) error
+}
+
+// maintenanceItemKey is the key of the config item holding the shared maintenance flag.
+// It is not a UUID, so it can never collide with or be fetched as a ticket.
+const maintenanceItemKey = "config#maintenance"
+
+// maintenanceCacheTTL is how long an instance trusts the flag it last read,
+// bounding both the extra reads per entry and how long a toggle takes to reach other instances
+var maintenanceCacheTTL = 10 * time.Second
+
+// maintenanceCache remembers the last stored maintenance flag
+type maintenanceCache struct {
+	mu        sync.Mutex
+	enabled   bool
+	fetchedAt time.Time
+}
+
+// MaintenanceMode reports whether new entries are rejected, either because MAINTENANCE_MODE=true
+// or because the shared flag was turned on through the admin API
+func (s *ParkingLotService) MaintenanceMode(ctx context.Context) (bool, error) {
+	if s.maintenanceForced {
+		return true, nil
+	}
+
+	s.maintenance.mu.Lock()
+	defer s.maintenance.mu.Unlock()
+
+	if !s.maintenance.fetchedAt.IsZero() && s.now().Sub(s.maintenance.fetchedAt) < maintenanceCacheTTL {
+		return s.maintenance.enabled, nil
+	}
+
+	result, err := s.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(s.tableName),
+		Key:       s.configItemKey(maintenanceItemKey),
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to read maintenance mode: %w", err)
+	}
+
+	enabled := false
+	if flag, ok := result.Item["enabled"].(*types.AttributeValueMemberBOOL); ok {
+		enabled = flag.Value
+	}
+	s.maintenance.enabled = enabled
+	s.maintenance.fetchedAt = s.now()
+	return enabled, nil
+}
+
+// SetMaintenanceMode stores the shared maintenance flag. Other instances pick it up
+// within maintenanceCacheTTL; MAINTENANCE_MODE=true keeps maintenance on regardless.
+func (s *ParkingLotService) SetMaintenanceMode(ctx context.Context, enabled bool) error {
+	log := s.log.WithContext(ctx).WithFields(logger.Field{Key: "maintenance", Value: enabled})
+
+	item := s.configItemKey(maintenanceItemKey)
+	item["enabled"] = &types.AttributeValueMemberBOOL{Value: enabled}
+	item["updatedAt"] = &types.AttributeValueMemberS{Value: s.now().UTC().Format(time.RFC3339)}
+
+	_, err := s.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(s.tableName),
+		Item:      item,
+	})
+	if err != nil {
+		log.Error("Failed to store maintenance mode", logger.Field{Key: "error", Value: err.Error()})
+		return fmt.Errorf("failed to store maintenance mode: %w", err)
+	}
+
+	s.maintenance.mu.Lock()
+	s.maintenance.enabled = enabled
+	s.maintenance.fetchedAt = s.now()
+	s.maintenance.mu.Unlock()
+
+	log.Info("Maintenance mode updated")
+	return nil
+}
@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"parking-lot/internal/logger"
+	"parking-lot/internal/mocks"
+)
+
+// TestMaintenanceMode tests reading the shared maintenance flag
+func TestMaintenanceMode(t *testing.T) {
+	ctx := context.Background()
+	isMaintenanceKey := mock.MatchedBy(func(input *dynamodb.GetItemInput) bool {
+		key, ok := input.Key["ticketId"].(*types.AttributeValueMemberS)
+		return ok && key.Value == maintenanceItemKey
+	})
+
+	t.Run("Forced by environment", func(t *testing.T) {
+		mockClient := new(mocks.DynamoDBClient)
+		service := &ParkingLotService{client: mockClient, tableName: "testTable", log: logger.NewLogger(), maintenanceForced: true}
+
+		enabled, err := service.MaintenanceMode(ctx)
+
+		assert.NoError(t, err)
+		assert.True(t, enabled)
+		mockClient.AssertNotCalled(t, "GetItem", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Stored flag is cached", func(t *testing.T) {
+		now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+		mockClient := new(mocks.DynamoDBClient)
+		service := &ParkingLotService{client: mockClient, tableName: "testTable", log: logger.NewLogger(), clock: func() time.Time { return now }}
+
+		mockClient.On("GetItem", ctx, isMaintenanceKey, mock.Anything).Return(&dynamodb.GetItemOutput{
+			Item: map[string]types.AttributeValue{
+				"ticketId": &types.AttributeValueMemberS{Value: maintenanceItemKey},
+				"enabled":  &types.AttributeValueMemberBOOL{Value: true},
+			},
+		}, nil).Once()
+		mockClient.On("GetItem", ctx, isMaintenanceKey, mock.Anything).Return(&dynamodb.GetItemOutput{}, nil).Once()
+
+		for i := 0; i < 2; i++ {
+			enabled, err := service.MaintenanceMode(ctx)
+			assert.NoError(t, err)
+			assert.True(t, enabled)
+		}
+		mockClient.AssertNumberOfCalls(t, "GetItem", 1)
+
+		// Once the cache expires, a missing item means no maintenance
+		now = now.Add(maintenanceCacheTTL)
+		enabled, err := service.MaintenanceMode(ctx)
+		assert.NoError(t, err)
+		assert.False(t, enabled)
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run("Read failure", func(t *testing.T) {
+		mockClient := new(mocks.DynamoDBClient)
+		service := &ParkingLotService{client: mockClient, tableName: "testTable", log: logger.NewLogger()}
+		mockClient.On("GetItem", ctx, isMaintenanceKey, mock.Anything).Return(nil, errors.New("throttled")).Once()
+
+		_, err := service.MaintenanceMode(ctx)
+
+		assert.EqualError(t, err, "failed to read maintenance mode: throttled")
+	})
+}
+
+// TestSetMaintenanceMode tests storing the shared maintenance flag
+func TestSetMaintenanceMode(t *testing.T) {
+	ctx := context.Background()
+	mockClient := new(mocks.DynamoDBClient)
+	service := &ParkingLotService{client: mockClient, tableName: "testTable", log: logger.NewLogger()}
+
+	mockClient.On("PutItem", ctx, mock.MatchedBy(func(input *dynamodb.PutItemInput) bool {
+		key, ok := input.Item["ticketId"].(*types.AttributeValueMemberS)
+		flag, isBool := input.Item["enabled"].(*types.AttributeValueMemberBOOL)
+		return ok && key.Value == maintenanceItemKey && isBool && flag.Value
+	}), mock.Anything).Return(&dynamodb.PutItemOutput{}, nil).Once()
+
+	assert.NoError(t, service.SetMaintenanceMode(ctx, true))
+
+	// The writing instance sees the new flag without another read
+	enabled, err := service.MaintenanceMode(ctx)
+	assert.NoError(t, err)
+	assert.True(t, enabled)
+	mockClient.AssertExpectations(t)
+	mockClient.AssertNotCalled(t, "GetItem", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestMemoryParkingLotService_MaintenanceMode tests the in-memory maintenance flag
+func TestMemoryParkingLotService_MaintenanceMode(t *testing.T) {
+	ctx := context.Background()
+	service, err := NewMemoryParkingLotService(ctx)
+	assert.NoError(t, err)
+
+	enabled, err := service.MaintenanceMode(ctx)
+	assert.NoError(t, err)
+	assert.False(t, enabled)
+
+	assert.NoError(t, service.SetMaintenanceMode(ctx, true))
+	enabled, _ = service.MaintenanceMode(ctx)
+	assert.True(t, enabled)
+}
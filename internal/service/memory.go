@@ -0,0 +1,271 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+
+	"github.com/google/uuid"
+
+	"parking-lot/internal/logger"
+	"parking-lot/internal/model"
+)
+
+// MemoryParkingLotService keeps tickets in memory instead of DynamoDB.
+// It is meant for local development; tickets are lost when the process exits.
+type MemoryParkingLotService struct {
+	*ParkingLotService
+
+	mu      sync.Mutex
+	tickets map[string]*model.ParkingTicket
+
+	// snapshotPath is the file tickets are saved to on shutdown and loaded from on start; empty disables it
+	snapshotPath string
+}
+
+// NewMemoryParkingLotService creates an in-memory service using the lot and billing settings from the environment
+func NewMemoryParkingLotService(ctx context.Context) (*MemoryParkingLotService, error) {
+	return NewMemoryParkingLotServiceWithLogger(ctx, logger.NewLogger())
+}
+
+// NewMemoryParkingLotServiceWithLogger creates an in-memory service that logs to log
+func NewMemoryParkingLotServiceWithLogger(ctx context.Context, log logger.Logger) (*MemoryParkingLotService, error) {
+	s, err := newConfiguredService(ctx, log)
+	if err != nil {
+		return nil, err
+	}
+
+	s.spots = NewMemorySpots()
+	s.blocks = NewMemoryBlocks()
+	s.occupancy = NewMemoryOccupancy(func(parkingLot int) int {
+		return s.LotConfig(parkingLot).Capacity
+	})
+
+	m := &MemoryParkingLotService{
+		ParkingLotService: s,
+		tickets:           make(map[string]*model.ParkingTicket),
+		snapshotPath:      os.Getenv("INMEM_SNAPSHOT_PATH"),
+	}
+
+	// Pick up the sessions saved when the previous server stopped; a bad snapshot is not fatal
+	if m.snapshotPath != "" {
+		if _, err := m.LoadSnapshot(ctx, m.snapshotPath); err != nil {
+			log.Warn("Ignoring in-memory snapshot, starting empty",
+				logger.Field{Key: "path", Value: m.snapshotPath},
+				logger.Field{Key: "error", Value: err.Error()},
+			)
+		}
+	}
+	return m, nil
+}
+
+// CreateTicket generates a new parking ticket and stores it in memory
+func (m *MemoryParkingLotService) CreateTicket(ctx context.Context, plate string, parkingLot int, spaces int) (uuid.UUID, *model.ParkingTicket) {
+	ticketID, ticket, err := m.CreateTicketChecked(ctx, plate, parkingLot, spaces)
+	if err != nil {
+		m.log.WithContext(ctx).Error("Failed to create ticket", logger.Field{Key: "error", Value: err.Error()})
+		return uuid.Nil, nil
+	}
+	return ticketID, ticket
+}
+
+// CreateTicketChecked creates a ticket in memory; only generating its ID or allocating a spot can fail
+func (m *MemoryParkingLotService) CreateTicketChecked(ctx context.Context, plate string, parkingLot int, spaces int) (uuid.UUID, *model.ParkingTicket, error) {
+	ticketID, err := m.newTicketID()
+	if err != nil {
+		return uuid.Nil, nil, fmt.Errorf("failed to generate ticket ID: %w", err)
+	}
+	ticket := &model.ParkingTicket{
+		TicketID:   ticketID.String(),
+		Plate:      plate,
+		ParkingLot: parkingLot,
+		EntryTime:  m.now(),
+		Status:     model.TicketStatusIn,
+		SpacesUsed: spaces,
+		SessionID:  uuid.New().String(),
+		Anonymous:  model.IsAnonymousPlate(plate),
+		PricingArm: m.pricingArm(plate),
+	}
+	describeVehicle(ctx, ticket)
+	if err := m.assignSpot(ctx, m.log.WithContext(ctx), ticket); err != nil {
+		return uuid.Nil, nil, fmt.Errorf("failed to allocate spot: %w", err)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.tickets[ticket.TicketID] = copyTicket(ticket)
+	return ticketID, ticket, nil
+}
+
+// GetTicket retrieves a ticket by ID
+func (m *MemoryParkingLotService) GetTicket(ctx context.Context, ticketID string) (*model.ParkingTicket, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	ticket, ok := m.tickets[ticketID]
+	if !ok {
+		return nil, false
+	}
+	return copyTicket(ticket), true
+}
+
+// FindActiveTicket returns the ticket still in the given lot for a plate
+func (m *MemoryParkingLotService) FindActiveTicket(ctx context.Context, plate string, parkingLot int) (*model.ParkingTicket, error) {
+	return m.find(plate, parkingLot, model.TicketStatusIn, nil), nil
+}
+
+// FindReentryTicket returns a ticket for a plate that exited the given lot within the grace window
+func (m *MemoryParkingLotService) FindReentryTicket(ctx context.Context, plate string, parkingLot int) (*model.ParkingTicket, error) {
+	if m.graceReentry <= 0 {
+		return nil, nil
+	}
+
+	cutoff := m.now().Add(-m.graceReentry)
+	return m.find(plate, parkingLot, model.TicketStatusOut, func(ticket *model.ParkingTicket) bool {
+		return ticket.ExitTime != nil && !ticket.ExitTime.Before(cutoff)
+	}), nil
+}
+
+// ReopenTicket puts an exited ticket back in the lot
+func (m *MemoryParkingLotService) ReopenTicket(ctx context.Context, ticket *model.ParkingTicket) error {
+	ticket.Reopen()
+	if err := m.assignSpot(ctx, m.log.WithContext(ctx), ticket); err != nil {
+		return fmt.Errorf("failed to allocate spot: %w", err)
+	}
+	return m.UpdateTicket(ctx, ticket)
+}
+
+// CorrectPlate changes the plate of a ticket stored in memory
+func (m *MemoryParkingLotService) CorrectPlate(ctx context.Context, ticket *model.ParkingTicket, plate string) error {
+	return m.correctPlate(ctx, ticket, plate, m.UpdateTicket)
+}
+
+// UpdateTicket replaces a stored ticket, checking its version like the DynamoDB service
+// when VERSIONED_UPDATES is enabled
+func (m *MemoryParkingLotService) UpdateTicket(ctx context.Context, ticket *model.ParkingTicket) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.versionedUpdates {
+		if stored, ok := m.tickets[ticket.TicketID]; ok && stored.Version != ticket.Version {
+			return fmt.Errorf("failed to update ticket: %w", ErrTicketConflict)
+		}
+		ticket.Version++
+	}
+	m.tickets[ticket.TicketID] = copyTicket(ticket)
+	return nil
+}
+
+// RemoveTicket deletes a ticket
+func (m *MemoryParkingLotService) RemoveTicket(ctx context.Context, ticketID string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.tickets, ticketID)
+}
+
+// GetTicketsBatch retrieves many tickets at once, keyed by ticket ID
+func (m *MemoryParkingLotService) GetTicketsBatch(ctx context.Context, ids []string) (map[string]*model.ParkingTicket, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	tickets := make(map[string]*model.ParkingTicket, len(ids))
+	for _, id := range ids {
+		if ticket, ok := m.tickets[id]; ok {
+			tickets[id] = copyTicket(ticket)
+		}
+	}
+	return tickets, nil
+}
+
+// MaintenanceMode reports whether new entries are rejected
+func (m *MemoryParkingLotService) MaintenanceMode(ctx context.Context) (bool, error) {
+	m.maintenance.mu.Lock()
+	defer m.maintenance.mu.Unlock()
+
+	return m.maintenanceForced || m.maintenance.enabled, nil
+}
+
+// SetMaintenanceMode turns maintenance mode on or off for this process
+func (m *MemoryParkingLotService) SetMaintenanceMode(ctx context.Context, enabled bool) error {
+	m.maintenance.mu.Lock()
+	defer m.maintenance.mu.Unlock()
+
+	m.maintenance.enabled = enabled
+	return nil
+}
+
+// ResetLot deletes every ticket in a lot and frees its spaces, under the same guard as the DynamoDB service
+func (m *MemoryParkingLotService) ResetLot(ctx context.Context, parkingLot int) error {
+	if !destructiveAdminEnabled() {
+		return ErrDestructiveAdminDisabled
+	}
+
+	m.mu.Lock()
+	spaces := 0
+	var parked []*model.ParkingTicket
+	for id, ticket := range m.tickets {
+		if ticket.ParkingLot != parkingLot {
+			continue
+		}
+		if ticket.Status == model.TicketStatusIn {
+			spaces += ticket.Spaces()
+			parked = append(parked, ticket)
+		}
+		delete(m.tickets, id)
+	}
+	m.mu.Unlock()
+
+	for _, ticket := range parked {
+		m.ReleaseSpot(ctx, ticket)
+	}
+	if spaces > 0 {
+		m.ReleaseSpaces(ctx, parkingLot, spaces)
+	}
+	return nil
+}
+
+// Snapshot returns the tickets of vehicles still in a lot, oldest entry first
+func (m *MemoryParkingLotService) Snapshot() []model.ParkingTicket {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var active []model.ParkingTicket
+	for _, ticket := range m.tickets {
+		if ticket.Status == model.TicketStatusIn {
+			active = append(active, *copyTicket(ticket))
+		}
+	}
+	sort.Slice(active, func(i, j int) bool { return active[i].EntryTime.Before(active[j].EntryTime) })
+	return active
+}
+
+// find returns a copy of the first ticket for a plate in a lot with the given status that also satisfies match
+func (m *MemoryParkingLotService) find(plate string, parkingLot int, status model.TicketStatus, match func(*model.ParkingTicket) bool) *model.ParkingTicket {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, ticket := range m.tickets {
+		if ticket.Plate == plate && ticket.ParkingLot == parkingLot && ticket.Status == status && (match == nil || match(ticket)) {
+			return copyTicket(ticket)
+		}
+	}
+	return nil
+}
+
+// copyTicket copies a ticket so callers cannot change the stored one
+func copyTicket(ticket *model.ParkingTicket) *model.ParkingTicket {
+	c := *ticket
+	if ticket.ExitTime != nil {
+		exitTime := *ticket.ExitTime
+		c.ExitTime = &exitTime
+	}
+	if ticket.RefundTime != nil {
+		refundTime := *ticket.RefundTime
+		c.RefundTime = &refundTime
+	}
+	return &c
+}
@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"parking-lot/internal/model"
+)
+
+// TestMemoryParkingLotService_Snapshot tests that the snapshot lists created but not exited tickets
+func TestMemoryParkingLotService_Snapshot(t *testing.T) {
+	ctx := context.Background()
+	service, err := NewMemoryParkingLotService(ctx)
+	require.NoError(t, err)
+
+	_, first := service.CreateTicket(ctx, "ABC-123", 1, 1)
+	_, exited := service.CreateTicket(ctx, "XYZ-789", 1, 1)
+	_, second := service.CreateTicket(ctx, "LMN-456", 2, 2)
+
+	exitTime := time.Now()
+	exited.Status = model.TicketStatusOut
+	exited.ExitTime = &exitTime
+	require.NoError(t, service.UpdateTicket(ctx, exited))
+
+	snapshot := service.Snapshot()
+
+	if assert.Len(t, snapshot, 2) {
+		assert.Equal(t, first.TicketID, snapshot[0].TicketID)
+		assert.Equal(t, second.TicketID, snapshot[1].TicketID)
+	}
+}
+
+// TestMemoryParkingLotService_Tickets tests storing, finding and reopening tickets in memory
+func TestMemoryParkingLotService_Tickets(t *testing.T) {
+	ctx := context.Background()
+	service, err := NewMemoryParkingLotService(ctx)
+	require.NoError(t, err)
+	service.graceReentry = 10 * time.Minute
+
+	ticketID, ticket := service.CreateTicket(ctx, "ABC-123", 1, 1)
+
+	stored, ok := service.GetTicket(ctx, ticketID.String())
+	require.True(t, ok)
+	assert.Equal(t, ticket, stored)
+
+	active, err := service.FindActiveTicket(ctx, "ABC-123", 1)
+	require.NoError(t, err)
+	assert.Equal(t, ticket.TicketID, active.TicketID)
+
+	// Changing a returned ticket does not change the stored one until it is updated
+	exitTime := time.Now()
+	stored.Status = model.TicketStatusOut
+	stored.ExitTime = &exitTime
+	active, err = service.FindActiveTicket(ctx, "ABC-123", 1)
+	require.NoError(t, err)
+	assert.NotNil(t, active)
+
+	require.NoError(t, service.UpdateTicket(ctx, stored))
+	active, err = service.FindActiveTicket(ctx, "ABC-123", 1)
+	require.NoError(t, err)
+	assert.Nil(t, active)
+
+	reentry, err := service.FindReentryTicket(ctx, "ABC-123", 1)
+	require.NoError(t, err)
+	require.NotNil(t, reentry)
+	require.NoError(t, service.ReopenTicket(ctx, reentry))
+	assert.Len(t, service.Snapshot(), 1)
+
+	service.RemoveTicket(ctx, ticketID.String())
+	_, ok = service.GetTicket(ctx, ticketID.String())
+	assert.False(t, ok)
+}
+
+// TestMemoryParkingLotService_Vehicle tests that a vehicle described at entry is stored on the ticket
+func TestMemoryParkingLotService_Vehicle(t *testing.T) {
+	ctx := context.Background()
+	service, err := NewMemoryParkingLotService(ctx)
+	require.NoError(t, err)
+
+	vehicle := model.Vehicle{Make: "Toyota", Model: "Corolla", Color: "Silver"}
+	described, _ := service.CreateTicket(WithVehicle(ctx, vehicle), "ABC-123", 1, 1)
+	plain, _ := service.CreateTicket(ctx, "XYZ-789", 1, 1)
+
+	stored, ok := service.GetTicket(ctx, described.String())
+	require.True(t, ok)
+	assert.Equal(t, "Toyota", stored.Make)
+	assert.Equal(t, "Corolla", stored.Model)
+	assert.Equal(t, "Silver", stored.Color)
+
+	stored, ok = service.GetTicket(ctx, plain.String())
+	require.True(t, ok)
+	assert.Empty(t, stored.Make)
+	assert.Empty(t, stored.Model)
+	assert.Empty(t, stored.Color)
+}
@@ -0,0 +1,282 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// ErrLotFull is returned when a lot does not have enough free spaces for an entry
+var ErrLotFull = errors.New("parking lot is full")
+
+// OccupancyCounter tracks how many spaces are in use in each parking lot
+type OccupancyCounter interface {
+	// Reserve claims spaces in a lot, failing with ErrLotFull when not enough remain
+	Reserve(ctx context.Context, parkingLot, spaces int) error
+
+	// Release returns previously reserved spaces to a lot
+	Release(ctx context.Context, parkingLot, spaces int) error
+
+	// Occupied returns the number of spaces currently in use in a lot
+	Occupied(ctx context.Context, parkingLot int) (int, error)
+}
+
+// MemoryOccupancy is an in-memory OccupancyCounter, safe for concurrent entries and exits.
+// Counters are local to the process and are lost on restart.
+type MemoryOccupancy struct {
+	mu       sync.Mutex
+	occupied map[int]int
+	capacity func(parkingLot int) int
+}
+
+// NewMemoryOccupancy creates an in-memory counter using capacity to look up
+// the size of each lot. A capacity of zero means the lot is unlimited.
+func NewMemoryOccupancy(capacity func(parkingLot int) int) *MemoryOccupancy {
+	return &MemoryOccupancy{
+		occupied: make(map[int]int),
+		capacity: capacity,
+	}
+}
+
+// heldReserver is implemented by occupancy counters that can reserve spaces while some of a lot's
+// capacity is held back, e.g. by a block
+type heldReserver interface {
+	// ReserveHeld claims spaces in a lot as Reserve does, with held spaces unavailable
+	ReserveHeld(ctx context.Context, parkingLot, spaces, held int) error
+}
+
+// Reserve claims spaces in a lot
+func (m *MemoryOccupancy) Reserve(ctx context.Context, parkingLot, spaces int) error {
+	return m.ReserveHeld(ctx, parkingLot, spaces, 0)
+}
+
+// ReserveHeld claims spaces in a lot, counting held spaces as taken. Lots without a capacity
+// stay unlimited.
+func (m *MemoryOccupancy) ReserveHeld(ctx context.Context, parkingLot, spaces, held int) error {
+	if spaces < 1 {
+		return fmt.Errorf("invalid number of spaces: %d", spaces)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if capacity := m.capacity(parkingLot); capacity > 0 && capacity-held-m.occupied[parkingLot] < spaces {
+		return ErrLotFull
+	}
+	m.occupied[parkingLot] += spaces
+	return nil
+}
+
+// Release returns spaces to a lot, never dropping the counter below zero
+func (m *MemoryOccupancy) Release(ctx context.Context, parkingLot, spaces int) error {
+	if spaces < 1 {
+		return fmt.Errorf("invalid number of spaces: %d", spaces)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.occupied[parkingLot] -= spaces
+	if m.occupied[parkingLot] < 0 {
+		m.occupied[parkingLot] = 0
+	}
+	return nil
+}
+
+// Occupied returns the number of spaces in use in a lot
+func (m *MemoryOccupancy) Occupied(ctx context.Context, parkingLot int) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.occupied[parkingLot], nil
+}
+
+// SetOccupied sets the spaces in use in a lot to occupied, but only while expected are in use,
+// reporting whether it did. The check only sees this process's entries and exits.
+func (m *MemoryOccupancy) SetOccupied(ctx context.Context, parkingLot, expected, occupied int) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.occupied[parkingLot] != expected {
+		return false, nil
+	}
+	m.occupied[parkingLot] = occupied
+	return true, nil
+}
+
+// Snapshot returns the spaces in use per lot, omitting empty lots
+func (m *MemoryOccupancy) Snapshot() map[int]int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	snapshot := make(map[int]int, len(m.occupied))
+	for parkingLot, occupied := range m.occupied {
+		if occupied > 0 {
+			snapshot[parkingLot] = occupied
+		}
+	}
+	return snapshot
+}
+
+// occupancyItemPrefix starts the key of the config item holding a lot's spaces in use, e.g. "config#occupancy#382"
+const occupancyItemPrefix = "config#occupancy#"
+
+// occupancyReleaseAttempts bounds how often a release is retried when entries keep changing the counter
+const occupancyReleaseAttempts = 5
+
+// occupancyNames maps the placeholder of the counter attribute in occupancy item expressions
+var occupancyNames = map[string]string{"#occupied": "occupied"}
+
+// dynamoOccupancy is an OccupancyCounter keeping the spaces in use of each lot in a config item of the
+// tickets table, so the entry and exit functions of every instance share one counter per lot
+type dynamoOccupancy struct {
+	s *ParkingLotService
+}
+
+// Reserve claims spaces in a lot
+func (d *dynamoOccupancy) Reserve(ctx context.Context, parkingLot, spaces int) error {
+	return d.ReserveHeld(ctx, parkingLot, spaces, 0)
+}
+
+// ReserveHeld adds spaces to the lot's counter with a conditional update that only succeeds while
+// they fit next to the held spaces. Lots without a capacity stay unlimited.
+func (d *dynamoOccupancy) ReserveHeld(ctx context.Context, parkingLot, spaces, held int) error {
+	if spaces < 1 {
+		return fmt.Errorf("invalid number of spaces: %d", spaces)
+	}
+
+	input := &dynamodb.UpdateItemInput{
+		TableName:                aws.String(d.s.table(ctx)),
+		Key:                      d.s.configItemKey(occupancyItemPrefix + strconv.Itoa(parkingLot)),
+		UpdateExpression:         aws.String("ADD #occupied :spaces"),
+		ExpressionAttributeNames: occupancyNames,
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":spaces": &types.AttributeValueMemberN{Value: strconv.Itoa(spaces)},
+		},
+	}
+	if capacity := d.s.LotConfig(parkingLot).Capacity; capacity > 0 {
+		limit := capacity - held - spaces
+		if limit < 0 {
+			return ErrLotFull
+		}
+		input.ConditionExpression = aws.String("attribute_not_exists(#occupied) OR #occupied <= :limit")
+		input.ExpressionAttributeValues[":limit"] = &types.AttributeValueMemberN{Value: strconv.Itoa(limit)}
+	}
+
+	_, err := d.s.client.UpdateItem(ctx, input)
+	var conflict *types.ConditionalCheckFailedException
+	if errors.As(err, &conflict) {
+		return ErrLotFull
+	}
+	if err != nil {
+		return fmt.Errorf("failed to reserve spaces: %w", err)
+	}
+	return nil
+}
+
+// Release subtracts spaces from the lot's counter while at least that many are in use, and
+// otherwise resets it to zero, so the counter never drops below zero
+func (d *dynamoOccupancy) Release(ctx context.Context, parkingLot, spaces int) error {
+	if spaces < 1 {
+		return fmt.Errorf("invalid number of spaces: %d", spaces)
+	}
+
+	key := d.s.configItemKey(occupancyItemPrefix + strconv.Itoa(parkingLot))
+	values := map[string]types.AttributeValue{
+		":spaces":   &types.AttributeValueMemberN{Value: strconv.Itoa(spaces)},
+		":released": &types.AttributeValueMemberN{Value: strconv.Itoa(-spaces)},
+	}
+	var conflict *types.ConditionalCheckFailedException
+	for attempt := 0; attempt < occupancyReleaseAttempts; attempt++ {
+		_, err := d.s.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+			TableName:                 aws.String(d.s.table(ctx)),
+			Key:                       key,
+			UpdateExpression:          aws.String("ADD #occupied :released"),
+			ConditionExpression:       aws.String("#occupied >= :spaces"),
+			ExpressionAttributeNames:  occupancyNames,
+			ExpressionAttributeValues: values,
+		})
+		if err == nil {
+			return nil
+		}
+		if !errors.As(err, &conflict) {
+			return fmt.Errorf("failed to release spaces: %w", err)
+		}
+
+		// Fewer spaces are in use than are released, e.g. after the counter was reset
+		_, err = d.s.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+			TableName:                aws.String(d.s.table(ctx)),
+			Key:                      key,
+			UpdateExpression:         aws.String("SET #occupied = :zero"),
+			ConditionExpression:      aws.String("attribute_not_exists(#occupied) OR #occupied < :spaces"),
+			ExpressionAttributeNames: occupancyNames,
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":spaces": values[":spaces"],
+				":zero":   &types.AttributeValueMemberN{Value: "0"},
+			},
+		})
+		if err == nil {
+			return nil
+		}
+		if !errors.As(err, &conflict) {
+			return fmt.Errorf("failed to release spaces: %w", err)
+		}
+	}
+	return fmt.Errorf("failed to release spaces: still contended after %d attempts", occupancyReleaseAttempts)
+}
+
+// SetOccupied replaces the lot's counter with a conditional update that only succeeds while it still
+// holds expected, so entries and exits of any instance since it was read are never overwritten
+func (d *dynamoOccupancy) SetOccupied(ctx context.Context, parkingLot, expected, occupied int) (bool, error) {
+	condition := "#occupied = :expected"
+	if expected == 0 {
+		// A lot nobody entered yet has no counter item, which counts as zero
+		condition = "attribute_not_exists(#occupied) OR " + condition
+	}
+	_, err := d.s.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName:                aws.String(d.s.table(ctx)),
+		Key:                      d.s.configItemKey(occupancyItemPrefix + strconv.Itoa(parkingLot)),
+		UpdateExpression:         aws.String("SET #occupied = :occupied"),
+		ConditionExpression:      aws.String(condition),
+		ExpressionAttributeNames: occupancyNames,
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":expected": &types.AttributeValueMemberN{Value: strconv.Itoa(expected)},
+			":occupied": &types.AttributeValueMemberN{Value: strconv.Itoa(occupied)},
+		},
+	})
+	var conflict *types.ConditionalCheckFailedException
+	if errors.As(err, &conflict) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to set occupancy: %w", err)
+	}
+	return true, nil
+}
+
+// Occupied reads the lot's counter; a lot without a counter item has no spaces in use
+func (d *dynamoOccupancy) Occupied(ctx context.Context, parkingLot int) (int, error) {
+	result, err := d.s.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName:      aws.String(d.s.table(ctx)),
+		Key:            d.s.configItemKey(occupancyItemPrefix + strconv.Itoa(parkingLot)),
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to read occupancy: %w", err)
+	}
+	value, ok := result.Item["occupied"].(*types.AttributeValueMemberN)
+	if !ok {
+		return 0, nil
+	}
+	occupied, err := strconv.Atoi(value.Value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid occupancy %q: %w", value.Value, err)
+	}
+	return occupied, nil
+}
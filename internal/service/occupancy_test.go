@@ -0,0 +1,296 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"parking-lot/internal/logger"
+	"parking-lot/internal/mocks"
+	"parking-lot/internal/model"
+)
+
+// TestReserveSpaces_MultiSpace tests reserving several spaces for an oversized vehicle
+func TestReserveSpaces_MultiSpace(t *testing.T) {
+	ctx := context.Background()
+	parkingLot := 382
+
+	newService := func(capacity, occupied int) *ParkingLotService {
+		service := &ParkingLotService{
+			ctx:  ctx,
+			log:  logger.NewLogger(),
+			lots: map[int]model.LotConfig{parkingLot: {Capacity: capacity}},
+		}
+		service.occupancy = NewMemoryOccupancy(func(lot int) int {
+			return service.LotConfig(lot).Capacity
+		})
+		if occupied > 0 {
+			assert.NoError(t, service.occupancy.Reserve(ctx, parkingLot, occupied))
+		}
+		return service
+	}
+
+	t.Run("Only one free space", func(t *testing.T) {
+		service := newService(5, 4)
+
+		err := service.ReserveSpaces(ctx, parkingLot, 2)
+
+		assert.ErrorIs(t, err, ErrLotFull)
+		occupied, _ := service.occupancy.Occupied(ctx, parkingLot)
+		assert.Equal(t, 4, occupied)
+	})
+
+	t.Run("Three free spaces", func(t *testing.T) {
+		service := newService(5, 2)
+
+		err := service.ReserveSpaces(ctx, parkingLot, 2)
+
+		assert.NoError(t, err)
+		occupied, _ := service.occupancy.Occupied(ctx, parkingLot)
+		assert.Equal(t, 4, occupied)
+	})
+
+	t.Run("Release returns spaces", func(t *testing.T) {
+		service := newService(5, 4)
+
+		service.ReleaseSpaces(ctx, parkingLot, 2)
+
+		occupied, _ := service.occupancy.Occupied(ctx, parkingLot)
+		assert.Equal(t, 2, occupied)
+	})
+}
+
+// TestReserveSpaces_StrictLots tests that STRICT_LOTS rejects lots missing from LOT_CONFIG
+func TestReserveSpaces_StrictLots(t *testing.T) {
+	ctx := context.Background()
+	lots := map[int]model.LotConfig{382: {Capacity: 10}}
+
+	t.Run("Strict rejects unknown lot", func(t *testing.T) {
+		service := &ParkingLotService{ctx: ctx, log: logger.NewLogger(), lots: lots, strictLots: true}
+
+		assert.ErrorIs(t, service.ReserveSpaces(ctx, 383, 1), ErrUnknownLot)
+		assert.NoError(t, service.ReserveSpaces(ctx, 382, 1))
+	})
+
+	t.Run("Lenient accepts unknown lot", func(t *testing.T) {
+		service := &ParkingLotService{ctx: ctx, log: logger.NewLogger(), lots: lots}
+
+		assert.NoError(t, service.ReserveSpaces(ctx, 383, 1))
+	})
+}
+
+// TestMemoryOccupancy_Unlimited tests that lots without a capacity never fill up
+func TestMemoryOccupancy_Unlimited(t *testing.T) {
+	ctx := context.Background()
+	occupancy := NewMemoryOccupancy(func(int) int { return 0 })
+
+	assert.NoError(t, occupancy.Reserve(ctx, 1, 1000))
+	assert.Error(t, occupancy.Reserve(ctx, 1, 0))
+
+	assert.NoError(t, occupancy.Release(ctx, 1, 2000))
+	occupied, err := occupancy.Occupied(ctx, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, occupied)
+}
+
+// TestDynamoOccupancy_Reserve tests that entries add to the lot's shared counter only while their spaces fit
+func TestDynamoOccupancy_Reserve(t *testing.T) {
+	ctx := context.Background()
+	mockClient := new(mocks.DynamoDBClient)
+	occupancy := &dynamoOccupancy{s: &ParkingLotService{
+		client:     mockClient,
+		tableName:  "testTable",
+		log:        logger.NewLogger(),
+		lots:       map[int]model.LotConfig{7: {Capacity: 10}},
+		defaultLot: model.LotConfig{},
+	}}
+
+	reserves := func(parkingLot, spaces, limit string) interface{} {
+		return mock.MatchedBy(func(input *dynamodb.UpdateItemInput) bool {
+			key, ok := input.Key["ticketId"].(*types.AttributeValueMemberS)
+			if !ok || key.Value != "config#occupancy#"+parkingLot || *input.UpdateExpression != "ADD #occupied :spaces" ||
+				input.ExpressionAttributeValues[":spaces"].(*types.AttributeValueMemberN).Value != spaces {
+				return false
+			}
+			if limit == "" {
+				return input.ConditionExpression == nil
+			}
+			return *input.ConditionExpression == "attribute_not_exists(#occupied) OR #occupied <= :limit" &&
+				input.ExpressionAttributeValues[":limit"].(*types.AttributeValueMemberN).Value == limit
+		})
+	}
+
+	// Two spaces fit while at most 8 of the 10 are in use, or 5 with 3 held back
+	mockClient.On("UpdateItem", ctx, reserves("7", "2", "8"), mock.Anything).Return(&dynamodb.UpdateItemOutput{}, nil).Once()
+	assert.NoError(t, occupancy.Reserve(ctx, 7, 2))
+	mockClient.On("UpdateItem", ctx, reserves("7", "2", "5"), mock.Anything).
+		Return(nil, &types.ConditionalCheckFailedException{}).Once()
+	assert.ErrorIs(t, occupancy.ReserveHeld(ctx, 7, 2, 3), ErrLotFull)
+
+	// More spaces than the lot has are rejected without an update
+	assert.ErrorIs(t, occupancy.Reserve(ctx, 7, 11), ErrLotFull)
+	assert.Error(t, occupancy.Reserve(ctx, 7, 0))
+
+	// Lots without a capacity are unlimited
+	mockClient.On("UpdateItem", ctx, reserves("3", "1", ""), mock.Anything).Return(&dynamodb.UpdateItemOutput{}, nil).Once()
+	assert.NoError(t, occupancy.Reserve(ctx, 3, 1))
+
+	mockClient.AssertExpectations(t)
+	mockClient.AssertNumberOfCalls(t, "UpdateItem", 3)
+}
+
+// TestDynamoOccupancy_Release tests that exits subtract from the lot's shared counter without taking it below zero
+func TestDynamoOccupancy_Release(t *testing.T) {
+	ctx := context.Background()
+	mockClient := new(mocks.DynamoDBClient)
+	occupancy := &dynamoOccupancy{s: &ParkingLotService{client: mockClient, tableName: "testTable", log: logger.NewLogger()}}
+
+	releases := mock.MatchedBy(func(input *dynamodb.UpdateItemInput) bool {
+		return *input.UpdateExpression == "ADD #occupied :released" && *input.ConditionExpression == "#occupied >= :spaces" &&
+			input.ExpressionAttributeValues[":released"].(*types.AttributeValueMemberN).Value == "-2"
+	})
+	resets := mock.MatchedBy(func(input *dynamodb.UpdateItemInput) bool {
+		return *input.UpdateExpression == "SET #occupied = :zero"
+	})
+
+	mockClient.On("UpdateItem", ctx, releases, mock.Anything).Return(&dynamodb.UpdateItemOutput{}, nil).Once()
+	assert.NoError(t, occupancy.Release(ctx, 7, 2))
+	mockClient.AssertNotCalled(t, "UpdateItem", ctx, resets, mock.Anything)
+
+	// Only one space is counted, so the counter is reset to zero instead
+	mockClient.On("UpdateItem", ctx, releases, mock.Anything).Return(nil, &types.ConditionalCheckFailedException{}).Once()
+	mockClient.On("UpdateItem", ctx, resets, mock.Anything).Return(&dynamodb.UpdateItemOutput{}, nil).Once()
+	assert.NoError(t, occupancy.Release(ctx, 7, 2))
+
+	mockClient.AssertExpectations(t)
+}
+
+// TestDynamoOccupancy_Occupied tests reading the lot's shared counter
+func TestDynamoOccupancy_Occupied(t *testing.T) {
+	ctx := context.Background()
+	mockClient := new(mocks.DynamoDBClient)
+	occupancy := &dynamoOccupancy{s: &ParkingLotService{client: mockClient, tableName: "testTable", log: logger.NewLogger()}}
+
+	isOccupancyKey := func(parkingLot string) interface{} {
+		return mock.MatchedBy(func(input *dynamodb.GetItemInput) bool {
+			key, ok := input.Key["ticketId"].(*types.AttributeValueMemberS)
+			return ok && key.Value == "config#occupancy#"+parkingLot && *input.ConsistentRead
+		})
+	}
+	mockClient.On("GetItem", ctx, isOccupancyKey("7"), mock.Anything).Return(&dynamodb.GetItemOutput{Item: map[string]types.AttributeValue{
+		"occupied": &types.AttributeValueMemberN{Value: "4"},
+	}}, nil).Once()
+	mockClient.On("GetItem", ctx, isOccupancyKey("8"), mock.Anything).Return(&dynamodb.GetItemOutput{}, nil).Once()
+
+	occupied, err := occupancy.Occupied(ctx, 7)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, occupied)
+	occupied, err = occupancy.Occupied(ctx, 8)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, occupied)
+	mockClient.AssertExpectations(t)
+}
+
+// TestMemoryOccupancy_Snapshot tests the per-lot snapshot of occupied spaces
+func TestMemoryOccupancy_Snapshot(t *testing.T) {
+	ctx := context.Background()
+	counter := NewMemoryOccupancy(func(int) int { return 0 })
+
+	assert.NoError(t, counter.Reserve(ctx, 1, 2))
+	assert.NoError(t, counter.Reserve(ctx, 2, 1))
+	assert.NoError(t, counter.Release(ctx, 2, 1))
+
+	assert.Equal(t, map[int]int{1: 2}, counter.Snapshot())
+}
+
+// TestMemoryOccupancy_Concurrent fires many concurrent entries and exits at one lot and checks the count
+// is exact: no reservation is lost and the lot is never overfilled. Run with -race to also catch data races.
+func TestMemoryOccupancy_Concurrent(t *testing.T) {
+	ctx := context.Background()
+	const capacity = 64
+	const vehicles = 500
+	counter := NewMemoryOccupancy(func(int) int { return capacity })
+
+	// Every vehicle tries to enter at once; exactly capacity of them get in
+	var admitted, full atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < vehicles; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			switch err := counter.Reserve(ctx, 1, 1); {
+			case err == nil:
+				admitted.Add(1)
+			case errors.Is(err, ErrLotFull):
+				full.Add(1)
+			default:
+				t.Errorf("unexpected error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.EqualValues(t, capacity, admitted.Load())
+	assert.EqualValues(t, vehicles-capacity, full.Load())
+	occupied, err := counter.Occupied(ctx, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, capacity, occupied)
+
+	// Half the parked vehicles leave while others keep entering and leaving again
+	for i := 0; i < capacity/2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, counter.Release(ctx, 1, 1))
+		}()
+	}
+	for i := 0; i < vehicles; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if counter.Reserve(ctx, 1, 1) == nil {
+				assert.NoError(t, counter.Release(ctx, 1, 1))
+			}
+		}()
+	}
+	wg.Wait()
+
+	occupied, err = counter.Occupied(ctx, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, capacity/2, occupied)
+}
+
+// TestMemoryParkingLotService_ConcurrentOccupancy tests concurrent entries and exits through the
+// in-memory service used for local development
+func TestMemoryParkingLotService_ConcurrentOccupancy(t *testing.T) {
+	t.Setenv("LOT_CONFIG", `{"1":{"capacity":10}}`)
+	t.Setenv("DEFAULT_LOT_CAPACITY", "")
+	ctx := context.Background()
+	service, err := NewMemoryParkingLotService(ctx)
+	assert.NoError(t, err)
+
+	var admitted atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if service.ReserveSpaces(ctx, 1, 1) != nil {
+				return
+			}
+			admitted.Add(1)
+			service.ReleaseSpaces(ctx, 1, 1)
+		}()
+	}
+	wg.Wait()
+
+	assert.Positive(t, admitted.Load())
+	assert.Empty(t, service.OccupancySnapshot())
+}
@@ -0,0 +1,23 @@
+package service
+
+import "time"
+
+// ParkLimit is implemented by services that limit how long a vehicle may park
+type ParkLimit interface {
+	// MaxParkDuration returns the longest stay before an exit is flagged; zero means no limit
+	MaxParkDuration() time.Duration
+}
+
+// MaxParkDuration returns the longest stay set by MAX_PARK_DURATION. Longer stays are only billed
+// up to the limit and flagged at exit for enforcement.
+func (s *ParkingLotService) MaxParkDuration() time.Duration {
+	return s.maxParkDuration
+}
+
+// billedUntil returns the end of the billed part of a stay, which stops at MAX_PARK_DURATION
+func (s *ParkingLotService) billedUntil(entryTime, now time.Time) time.Time {
+	if s.maxParkDuration > 0 && now.Sub(entryTime) > s.maxParkDuration {
+		return entryTime.Add(s.maxParkDuration)
+	}
+	return now
+}
@@ -0,0 +1,62 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"parking-lot/internal/model"
+)
+
+// TestCalculateCharge_MaxParkDuration tests that stays over the limit are only billed up to it
+func TestCalculateCharge_MaxParkDuration(t *testing.T) {
+	now := time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+	service := &ParkingLotService{
+		rates:           model.RateSchedule{IncrementMinutes: 60, RatePerIncrement: 2},
+		maxParkDuration: 48 * time.Hour,
+		clock:           func() time.Time { return now },
+	}
+
+	duration, minutes, charge := service.CalculateChargeDetailed(1, now.Add(-72*time.Hour))
+
+	// The real duration is reported, but only 48 hours are charged
+	assert.Equal(t, 72*time.Hour, duration)
+	assert.Equal(t, 72*60, minutes)
+	assert.Equal(t, float32(96), charge)
+
+	_, _, charge = service.CalculateChargeDetailed(1, now.Add(-10*time.Hour))
+	assert.Equal(t, float32(20), charge)
+}
+
+// TestLoadMaxParkDuration tests reading MAX_PARK_DURATION as a Go duration or a number of days
+func TestLoadMaxParkDuration(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     string
+		expected  time.Duration
+		expectErr bool
+	}{
+		{name: "Unset"},
+		{name: "Duration", value: "36h", expected: 36 * time.Hour},
+		{name: "Days", value: "3d", expected: 72 * time.Hour},
+		{name: "Zero", value: "0d", expectErr: true},
+		{name: "Negative", value: "-1h", expectErr: true},
+		{name: "Invalid", value: "three days", expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("MAX_PARK_DURATION", tt.value)
+
+			limit, err := loadMaxParkDuration()
+
+			if tt.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, limit)
+		})
+	}
+}
@@ -2,9 +2,10 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"math"
 	"os"
+	"strconv"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -13,6 +14,7 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 	"github.com/google/uuid"
+	"github.com/sony/gobreaker/v2"
 
 	"parking-lot/internal/logger"
 	"parking-lot/internal/model"
@@ -20,12 +22,23 @@ import (
 
 // ParkingLotServicer defines the interface for parking lot operations
 type ParkingLotServicer interface {
-	// CreateTicket generates a new parking ticket
-	CreateTicket(ctx context.Context, plate string, parkingLot int) (uuid.UUID, *model.ParkingTicket)
+	// CreateTicket generates a new parking ticket for a vehicle occupying the given number of spaces
+	// and returns a nil ticket when the request context is already canceled
+	CreateTicket(ctx context.Context, plate string, parkingLot int, spaces int) (uuid.UUID, *model.ParkingTicket)
 
 	// GetTicket retrieves a ticket by ID
 	GetTicket(ctx context.Context, ticketID string) (*model.ParkingTicket, bool)
 
+	// FindActiveTicket looks up a ticket still in the lot for a plate, returning nil when there is none
+	FindActiveTicket(ctx context.Context, plate string, parkingLot int) (*model.ParkingTicket, error)
+
+	// FindReentryTicket looks up a ticket for a plate that exited the lot within the grace window,
+	// returning nil when there is none or re-entry is disabled
+	FindReentryTicket(ctx context.Context, plate string, parkingLot int) (*model.ParkingTicket, error)
+
+	// ReopenTicket puts an exited ticket back in the lot for a vehicle re-entering within the grace window
+	ReopenTicket(ctx context.Context, ticket *model.ParkingTicket) error
+
 	// UpdateTicket updates an existing parking ticket
 	UpdateTicket(ctx context.Context, ticket *model.ParkingTicket) error
 
@@ -34,16 +47,138 @@ type ParkingLotServicer interface {
 
 	// CalculateCharge calculates parking fee
 	CalculateCharge(entryTime time.Time) (int, float32)
+
+	// CalculateChargeDetailed calculates the parking fee in a lot and also returns the exact parked duration
+	CalculateChargeDetailed(parkingLot int, entryTime time.Time) (time.Duration, int, float32)
+
+	// CalculateChargeBetween calculates the parking fee of a stay in a lot that ended at exitTime,
+	// independent of the current time
+	CalculateChargeBetween(parkingLot int, entryTime, exitTime time.Time) (int, float32)
+
+	// Rates returns the rate schedule used to bill parking time
+	Rates() model.RateSchedule
+
+	// ExitWindow returns how long a charge quoted at a kiosk is honored at exit; zero means never
+	ExitWindow() time.Duration
+
+	// ReserveSpaces claims spaces in a lot, returning ErrLotFull when not enough remain
+	// and ErrUnknownLot for lots missing from LOT_CONFIG when STRICT_LOTS is enabled
+	ReserveSpaces(ctx context.Context, parkingLot int, spaces int) error
+
+	// ReleaseSpaces returns spaces to a lot when a vehicle exits
+	ReleaseSpaces(ctx context.Context, parkingLot int, spaces int)
 }
 
+// ErrUnknownLot is returned when STRICT_LOTS is enabled and a lot is missing from LOT_CONFIG
+var ErrUnknownLot = errors.New("unknown parking lot")
+
+// ErrTicketConflict is returned by UpdateTicket and ReopenTicket when VERSIONED_UPDATES is enabled
+// and the ticket was updated by another request since it was read
+var ErrTicketConflict = errors.New("ticket was updated concurrently")
+
+// plateIndexName is the global secondary index keyed by plate
+const plateIndexName = "PlateIndex"
+
+// plateEntryTimeIndexName is the global secondary index keyed by plate and sorted by entryTime
+const plateEntryTimeIndexName = "PlateEntryTimeIndex"
+
+// sessionIndexName is the global secondary index keyed by session ID
+const sessionIndexName = "SessionIndex"
+
+// parkingLotIndexName is the global secondary index keyed by parking lot
+const parkingLotIndexName = "ParkingLotIndex"
+
+// statusIndexName is the global secondary index keyed by status and sorted by charge
+const statusIndexName = "StatusIndex"
+
+// exitTimeIndexName is the global secondary index keyed by status and sorted by exitTime. Unlike
+// StatusIndex's charge, exitTime is set on every exited ticket, so $0 and refunded exits are indexed too.
+const exitTimeIndexName = "ExitTimeIndex"
+
 // ParkingLotService handles parking lot operations with DynamoDB storage
 type ParkingLotService struct {
 	ctx          context.Context
 	client       DynamoDBClient
 	tableName    string
 	log          logger.Logger
+	lots         map[int]model.LotConfig
+	defaultLot   model.LotConfig
+	occupancy    OccupancyCounter
+	minCharge    float32
+	rates        model.RateSchedule
+	pricing      PricingStrategy
 	marshalMap   func(interface{}) (map[string]types.AttributeValue, error)
 	unmarshalMap func(map[string]types.AttributeValue, interface{}) error
+
+	// fallbackClient reads the tickets table's replica in FALLBACK_REGION when a primary read fails; nil disables it
+	fallbackClient DynamoDBClient
+	// eventsTableName is the append-only event log; empty disables it
+	eventsTableName string
+	// couponsTableName holds the promo codes accepted at exit; empty disables coupons
+	couponsTableName string
+	// graceReentry is how long after exiting a vehicle may return on the same ticket; zero disables it
+	graceReentry time.Duration
+	// exitWindow is how long a kiosk quote is honored at exit; zero always recomputes the charge
+	exitWindow time.Duration
+	// refundWindow is how long after payment a charge may still be refunded; zero disables refunds
+	refundWindow time.Duration
+	// reconcileInterval is how often the occupancy counters are reconciled with the parked tickets;
+	// zero leaves them to the admin endpoint
+	reconcileInterval time.Duration
+	// maxScanPages bounds the pages read by queries that aggregate a whole lot; zero reads every page
+	maxScanPages int
+	// maxParkDuration caps the billed stay and flags longer ones at exit; zero disables it
+	maxParkDuration time.Duration
+	// tolerances absorb clock imprecision when billing; nil uses the defaults
+	tolerances *chargeTolerances
+	// maxSessionCharge caps any single charge as a safety net against bad data (MAX_SESSION_CHARGE); zero disables it
+	maxSessionCharge float32
+	// minutesDisplay selects how returned parked minutes are rounded; empty rounds to the nearest minute
+	minutesDisplay MinutesDisplay
+	// spots allocates numbered spots in lots configured with them; nil assigns none
+	spots SpotManager
+	// blocks holds back spaces of lots during events; nil blocks none
+	blocks BlockStore
+	// marshalFallback retries a failed ticket marshal without the optional fields
+	marshalFallback bool
+	// clock returns the current time; nil uses time.Now
+	clock func() time.Time
+	// ids generates ticket IDs; nil uses random UUIDs
+	ids IDGenerator
+	// location is the billing timezone; nil uses UTC
+	location *time.Location
+	// hours restricts entries to operating hours; nil accepts entries at any time
+	hours *operatingHours
+	// surcharges raise charges for night and weekend parking; nil disables them
+	surcharges *surcharges
+	// experiment assigns plates to the arms of a pricing A/B test (PRICING_EXPERIMENTS); nil disables it
+	experiment *pricingExperiment
+	// compositeKey keys the tickets table by plate and entryTime instead of ticketId
+	compositeKey bool
+	// consistentReads makes ticket lookups strongly consistent (CONSISTENT_READS=true)
+	consistentReads bool
+	// checkIndexes fails health checks until every secondary index of the tickets table is active (CHECK_INDEX=true)
+	checkIndexes bool
+	// readRetryDelay is how long GetTicket waits before reading a missing ticket again (READ_RETRY=true); zero disables it
+	readRetryDelay time.Duration
+	// versionedUpdates makes UpdateTicket and ReopenTicket fail with ErrTicketConflict when the stored ticket
+	// was updated since it was read (VERSIONED_UPDATES=true)
+	versionedUpdates bool
+	// breaker guards DynamoDB calls; nil when disabled
+	breaker *gobreaker.CircuitBreaker[any]
+	// writeBreaker switches the service to read-only mode after sustained write failures; nil when disabled
+	writeBreaker *gobreaker.CircuitBreaker[any]
+	// strictLots rejects entries to lots missing from LOT_CONFIG
+	strictLots bool
+	// unknownLotRate replaces the rate per increment for lots missing from LOT_CONFIG; zero disables it
+	unknownLotRate float32
+	// maintenanceForced keeps maintenance mode on (MAINTENANCE_MODE=true)
+	maintenanceForced bool
+	// maintenance caches the shared maintenance flag
+	maintenance maintenanceCache
+	// multiTenant lets requests select a tickets table from tenantTables (MULTI_TENANT=true)
+	multiTenant  bool
+	tenantTables map[string]bool
 }
 
 // DynamoDBClient defines the interface for DynamoDB operations
@@ -52,22 +187,34 @@ type DynamoDBClient interface {
 	PutItem(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error)
 	GetItem(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error)
 	DeleteItem(ctx context.Context, params *dynamodb.DeleteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DeleteItemOutput, error)
+	UpdateItem(ctx context.Context, params *dynamodb.UpdateItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error)
+	Query(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error)
+	CreateTable(ctx context.Context, params *dynamodb.CreateTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.CreateTableOutput, error)
+	DescribeTable(ctx context.Context, params *dynamodb.DescribeTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DescribeTableOutput, error)
+	Scan(ctx context.Context, params *dynamodb.ScanInput, optFns ...func(*dynamodb.Options)) (*dynamodb.ScanOutput, error)
+	BatchWriteItem(ctx context.Context, params *dynamodb.BatchWriteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.BatchWriteItemOutput, error)
+	BatchGetItem(ctx context.Context, params *dynamodb.BatchGetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.BatchGetItemOutput, error)
+	TransactWriteItems(ctx context.Context, params *dynamodb.TransactWriteItemsInput, optFns ...func(*dynamodb.Options)) (*dynamodb.TransactWriteItemsOutput, error)
 	// Add other DynamoDB methods as needed
 }
 
 // NewParkingLotService creates a new service instance with DynamoDB
 func NewParkingLotService(ctx context.Context) (*ParkingLotService, error) {
-	// Initialize logger
-	log := logger.NewLogger().WithContext(ctx)
+	return NewParkingLotServiceWithLogger(ctx, logger.NewLogger())
+}
 
+// NewParkingLotServiceWithLogger creates a new service instance with DynamoDB that logs to log.
+// Each call derives a request logger from it with WithContext, so service logs carry the request ID
+// the caller stored in the request context with logger.ContextWithRequestID.
+func NewParkingLotServiceWithLogger(ctx context.Context, log logger.Logger) (*ParkingLotService, error) {
 	// Get table name from environment variable
-	tableName := os.Getenv("TABLE_NAME")
-	if tableName == "" {
-		tableName = "parkingTickets" // Default table name
+	tableName, err := loadTableName()
+	if err != nil {
+		return nil, invalidConfig(err)
 	}
 
-	// Load AWS configuration
-	cfg, err := config.LoadDefaultConfig(ctx)
+	// Load AWS configuration, honouring explicit region and profile overrides
+	cfg, err := config.LoadDefaultConfig(ctx, awsConfigOptions()...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load AWS config: %w", err)
 	}
@@ -84,61 +231,367 @@ func NewParkingLotService(ctx context.Context) (*ParkingLotService, error) {
 		)
 	}
 
-	// Create DynamoDB client
-	client := dynamodb.NewFromConfig(cfg)
+	s, err := newConfiguredService(ctx, log)
+	if err != nil {
+		return nil, invalidConfig(err)
+	}
 
-	return &ParkingLotService{
-		ctx:          ctx,
-		client:       client,
-		tableName:    tableName,
-		log:          log,
-		marshalMap:   attributevalue.MarshalMap,
-		unmarshalMap: attributevalue.UnmarshalMap,
-	}, nil
+	// Guard DynamoDB with a circuit breaker so sustained failures fail fast
+	breaker, err := newDynamoDBBreaker(log)
+	if err != nil {
+		return nil, invalidConfig(err)
+	}
+
+	// Switch to read-only mode when writes keep failing while reads work
+	writeBreaker, err := newWriteBreaker(log)
+	if err != nil {
+		return nil, invalidConfig(err)
+	}
+
+	// Create DynamoDB client, traced when telemetry is enabled
+	var client DynamoDBClient = dynamodb.NewFromConfig(cfg)
+	if breaker != nil {
+		client = newBreakerClient(client, breaker)
+	}
+	if writeBreaker != nil {
+		client = newReadOnlyClient(client, writeBreaker)
+	}
+	s.client = newTracedClient(client)
+	s.fallbackClient = newFallbackClient(cfg, log)
+	s.spots = &dynamoSpots{s: s}
+	s.blocks = &dynamoBlocks{s: s}
+	s.occupancy = &dynamoOccupancy{s: s}
+	s.breaker = breaker
+	s.writeBreaker = writeBreaker
+	s.tableName = tableName
+	s.eventsTableName = os.Getenv("EVENTS_TABLE_NAME")
+	s.couponsTableName = os.Getenv("COUPONS_TABLE_NAME")
+	s.marshalFallback = os.Getenv("MARSHAL_FALLBACK") == "true"
+	s.marshalMap = attributevalue.MarshalMap
+	s.unmarshalMap = attributevalue.UnmarshalMap
+
+	// Create the table on first run against DynamoDB Local
+	if autoCreateTableEnabled() {
+		if err := s.EnsureTable(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	return s, nil
 }
 
-// CreateTicket generates a new parking ticket and stores it in DynamoDB
-func (s *ParkingLotService) CreateTicket(ctx context.Context, plate string, parkingLot int) (uuid.UUID, *model.ParkingTicket) {
+// newConfiguredService creates a service with the lot, billing and occupancy settings
+// read from the environment but no storage
+func newConfiguredService(ctx context.Context, log logger.Logger) (*ParkingLotService, error) {
+	// Load per-lot configuration
+	lots, defaultLot, err := loadLotConfigs()
+	if err != nil {
+		return nil, err
+	}
+
+	// Load the minimum charge for any non-zero stay
+	minCharge, err := envFloat("MIN_CHARGE", 0)
+	if err != nil {
+		return nil, err
+	}
+
+	// Load the billing rates
+	rates, err := loadRateSchedule()
+	if err != nil {
+		return nil, err
+	}
+	if err := checkLotCurrencies(lots, rates); err != nil {
+		return nil, err
+	}
+
+	// Load tiered pricing, which replaces the increment schedule when configured
+	tiered, err := loadPricingTiers()
+	if err != nil {
+		return nil, err
+	}
+
+	// Load the pricing experiment, whose arms replace the lot's pricing for the plates assigned to them
+	experiment, err := loadPricingExperiment()
+	if err != nil {
+		return nil, err
+	}
+
+	// Load the rate charged in lots missing from LOT_CONFIG
+	unknownLotRate, err := envFloat("UNKNOWN_LOT_RATE", 0)
+	if err != nil {
+		return nil, err
+	}
+
+	// Load the free re-entry window
+	graceMinutes, err := envInt("GRACE_REENTRY_MINUTES", 0)
+	if err != nil {
+		return nil, err
+	}
+
+	// Load how long a kiosk quote is honored at exit
+	exitWindowMinutes, err := envInt("EXIT_WINDOW_MINUTES", defaultExitWindowMinutes)
+	if err != nil {
+		return nil, err
+	}
+
+	// Load how long after payment a charge may be refunded
+	refundWindowMinutes, err := envInt("REFUND_WINDOW_MINUTES", 0)
+	if err != nil {
+		return nil, err
+	}
+
+	// Load how often occupancy counters are reconciled
+	reconcileMinutes, err := envInt("RECONCILE_INTERVAL_MINUTES", 0)
+	if err != nil {
+		return nil, err
+	}
+
+	// Load the bound on pages read by lot-wide queries
+	maxScanPages, err := envInt("MAX_SCAN_PAGES", 0)
+	if err != nil {
+		return nil, err
+	}
+
+	// Load the tolerances applied when billing
+	tolerances, err := loadChargeTolerances()
+	if err != nil {
+		return nil, err
+	}
+
+	// Load the longest allowed stay
+	maxParkDuration, err := loadMaxParkDuration()
+	if err != nil {
+		return nil, err
+	}
+
+	// Load the highest charge of any single session
+	maxSessionCharge, err := envFloat("MAX_SESSION_CHARGE", 0)
+	if err != nil {
+		return nil, err
+	}
+
+	// Load how parked minutes are shown to clients
+	minutesDisplay, err := loadMinutesDisplay()
+	if err != nil {
+		return nil, err
+	}
+
+	// Load the timezone billing and operating hours are evaluated in
+	location, err := loadBillingLocation()
+	if err != nil {
+		return nil, err
+	}
+
+	// Load the hours entries are accepted
+	hours, err := loadOperatingHours()
+	if err != nil {
+		return nil, err
+	}
+
+	// Load the night and weekend surcharges
+	surcharges, err := loadSurcharges()
+	if err != nil {
+		return nil, err
+	}
+
+	// Load the key schema of the tickets table
+	compositeKey, err := loadCompositeKey()
+	if err != nil {
+		return nil, err
+	}
+
+	// Load the delay before a missing ticket is read again
+	readRetryDelay, err := loadReadRetryDelay()
+	if err != nil {
+		return nil, err
+	}
+
+	s := &ParkingLotService{
+		ctx:             ctx,
+		log:             log,
+		lots:            lots,
+		defaultLot:      defaultLot,
+		minCharge:       float32(minCharge),
+		rates:           rates,
+		graceReentry:    time.Duration(graceMinutes) * time.Minute,
+		exitWindow:      time.Duration(exitWindowMinutes) * time.Minute,
+		refundWindow:    time.Duration(refundWindowMinutes) * time.Minute,
+		maxParkDuration: maxParkDuration,
+		tolerances:      tolerances,
+		minutesDisplay:  minutesDisplay,
+		location:        location,
+		hours:           hours,
+		surcharges:      surcharges,
+		experiment:      experiment,
+		compositeKey:    compositeKey,
+		readRetryDelay:  readRetryDelay,
+
+		strictLots:        os.Getenv("STRICT_LOTS") == "true",
+		unknownLotRate:    float32(unknownLotRate),
+		maxSessionCharge:  float32(maxSessionCharge),
+		maintenanceForced: os.Getenv("MAINTENANCE_MODE") == "true",
+		consistentReads:   os.Getenv("CONSISTENT_READS") == "true",
+		checkIndexes:      os.Getenv("CHECK_INDEX") == "true",
+		versionedUpdates:  os.Getenv("VERSIONED_UPDATES") == "true",
+		multiTenant:       os.Getenv("MULTI_TENANT") == "true",
+		tenantTables:      loadTenantTables(),
+		reconcileInterval: time.Duration(reconcileMinutes) * time.Minute,
+		maxScanPages:      maxScanPages,
+	}
+	if tiered != nil {
+		s.pricing = tiered
+	}
+	return s, nil
+}
+
+// Available reports whether DynamoDB calls are being attempted, i.e. the circuit breaker is not open
+func (s *ParkingLotService) Available() bool {
+	return s.breaker == nil || s.breaker.State() != gobreaker.StateOpen
+}
+
+// LotConfigs is implemented by services with per-lot configuration
+type LotConfigs interface {
+	// LotConfig returns the configuration of a parking lot
+	LotConfig(parkingLot int) model.LotConfig
+}
+
+// LotConfig returns the configuration of a parking lot, falling back to the defaults
+// for lots that are not explicitly configured
+func (s *ParkingLotService) LotConfig(parkingLot int) model.LotConfig {
+	if lot, ok := s.lots[parkingLot]; ok {
+		return lot
+	}
+	return s.defaultLot
+}
+
+// KnownLot reports whether the lot is configured in LOT_CONFIG
+func (s *ParkingLotService) KnownLot(parkingLot int) bool {
+	_, ok := s.lots[parkingLot]
+	return ok
+}
+
+// CreateTicket generates a new parking ticket and stores it in DynamoDB. Storing is best effort,
+// except that a ticket DynamoDB can never store is not returned.
+func (s *ParkingLotService) CreateTicket(ctx context.Context, plate string, parkingLot int, spaces int) (uuid.UUID, *model.ParkingTicket) {
+	ticketID, ticket, err := s.CreateTicketChecked(ctx, plate, parkingLot, spaces)
+	if errors.Is(err, ErrItemTooLarge) {
+		return uuid.Nil, nil
+	}
+	return ticketID, ticket
+}
+
+// CreateTicketChecked generates a new parking ticket and stores it in DynamoDB, also returning
+// why storing failed. The ticket is still returned when only the store failed.
+func (s *ParkingLotService) CreateTicketChecked(ctx context.Context, plate string, parkingLot int, spaces int) (uuid.UUID, *model.ParkingTicket, error) {
 	log := s.log.WithContext(ctx).WithFields(
-		logger.Field{Key: "plate", Value: plate},
+		logger.Plate(plate),
 		logger.Field{Key: "parking_lot", Value: parkingLot},
+		logger.Field{Key: "spaces", Value: spaces},
 	)
 	log.Info("Creating parking ticket")
 
+	// Do not spend a DynamoDB call on a request that is already canceled
+	if err := ctx.Err(); err != nil {
+		log.Warn("Request canceled, skipping DynamoDB call", logger.Field{Key: "error", Value: err.Error()})
+		return uuid.Nil, nil, fmt.Errorf("failed to create ticket: %w", err)
+	}
+
 	// Generate a unique ticket ID
-	ticketID := uuid.New()
+	ticketID, err := s.newTicketID()
+	if err != nil {
+		log.Error("Failed to generate ticket ID", logger.Field{Key: "error", Value: err.Error()})
+		return uuid.Nil, nil, fmt.Errorf("failed to generate ticket ID: %w", err)
+	}
 
 	// Create the ticket
 	ticket := &model.ParkingTicket{
 		TicketID:   ticketID.String(),
 		Plate:      plate,
 		ParkingLot: parkingLot,
-		EntryTime:  time.Now(),
+		EntryTime:  s.now(),
 		Status:     model.TicketStatusIn,
 		Charge:     0.0,
+		SpacesUsed: spaces,
+		SessionID:  uuid.New().String(),
+		Anonymous:  model.IsAnonymousPlate(plate),
+		PricingArm: s.pricingArm(plate),
+	}
+	describeVehicle(ctx, ticket)
+
+	// Claim a numbered spot in lots that assign them
+	if err := s.assignSpot(ctx, log, ticket); err != nil {
+		return uuid.Nil, nil, fmt.Errorf("failed to allocate spot: %w", err)
 	}
 
 	// Marshal the ticket for DynamoDB
-	item, err := s.marshalMap(ticket)
+	item, err := s.marshalTicket(log, ticket)
 	if err != nil {
-		// Log error and return the ticket anyway (best effort)
+		// Log error and return the ticket anyway (best effort); it was never stored, so its spot is free again
+		s.ReleaseSpot(ctx, ticket)
 		log.Error("Failed to marshal ticket", logger.Field{Key: "error", Value: err.Error()})
-		return ticketID, ticket
+		return ticketID, ticket, fmt.Errorf("failed to marshal ticket: %w", err)
 	}
 
 	// Store the ticket in DynamoDB
 	_, err = s.client.PutItem(ctx, &dynamodb.PutItemInput{
-		TableName: aws.String(s.tableName),
+		TableName: aws.String(s.table(ctx)),
 		Item:      item,
 	})
+	if isItemTooLarge(err) {
+		s.ReleaseSpot(ctx, ticket)
+		log.Error("Ticket is too large to store", logger.Field{Key: "error", Value: err.Error()})
+		return uuid.Nil, nil, wrapItemTooLarge(err)
+	}
 	if err != nil {
-		// Log error and return the ticket anyway (best effort)
+		// Log error and return the ticket anyway (best effort); it was never stored, so its spot is free again
+		s.ReleaseSpot(ctx, ticket)
 		log.Error("Failed to store ticket in DynamoDB", logger.Field{Key: "error", Value: err.Error()})
-	} else {
-		log.Info("Successfully stored ticket in DynamoDB", logger.Field{Key: "ticket_id", Value: ticketID.String()})
+		return ticketID, ticket, fmt.Errorf("failed to store ticket in DynamoDB: %w", err)
 	}
 
-	return ticketID, ticket
+	log.Info("Successfully stored ticket in DynamoDB", logger.Field{Key: "ticket_id", Value: ticketID.String()})
+	s.recordEvent(ctx, model.EntryEvent(ticket))
+
+	return ticketID, ticket, nil
+}
+
+// droppableTicketFields are the descriptive fields of a new ticket that no lookup, exit or bill
+// depends on, by attribute name, with how to clear them
+var droppableTicketFields = []struct {
+	name  string
+	clear func(*model.ParkingTicket)
+}{
+	{"make", func(t *model.ParkingTicket) { t.Make = "" }},
+	{"model", func(t *model.ParkingTicket) { t.Model = "" }},
+	{"color", func(t *model.ParkingTicket) { t.Color = "" }},
+}
+
+// marshalTicket marshals a new ticket for DynamoDB. When enabled, a failed marshal is retried
+// without each descriptive field in turn, so an exotic vehicle description does not lose the ticket.
+// Only the field that failed is dropped, from ticket as well so the caller sees what was stored;
+// when no single descriptive field is to blame the marshal error is returned.
+func (s *ParkingLotService) marshalTicket(log logger.Logger, ticket *model.ParkingTicket) (map[string]types.AttributeValue, error) {
+	item, err := s.marshalMap(ticket)
+	if err == nil || !s.marshalFallback {
+		return item, err
+	}
+
+	for _, field := range droppableTicketFields {
+		trimmed := *ticket
+		field.clear(&trimmed)
+		if trimmed == *ticket {
+			continue
+		}
+		if item, retryErr := s.marshalMap(&trimmed); retryErr == nil {
+			log.Warn("Failed to marshal ticket, dropped an optional field",
+				logger.Field{Key: "field", Value: field.name},
+				logger.Field{Key: "error", Value: err.Error()},
+			)
+			field.clear(ticket)
+			return item, nil
+		}
+	}
+	return nil, err
 }
 
 // GetTicket retrieves a ticket by ID from DynamoDB
@@ -146,54 +599,228 @@ func (s *ParkingLotService) GetTicket(ctx context.Context, ticketID string) (*mo
 	log := s.log.WithContext(ctx).WithFields(logger.Field{Key: "ticket_id", Value: ticketID})
 	log.Info("Retrieving ticket")
 
-	// Create the key for DynamoDB query
-	key := map[string]types.AttributeValue{
-		"ticketId": &types.AttributeValueMemberS{Value: ticketID},
+	if err := ctx.Err(); err != nil {
+		log.Warn("Request canceled, skipping DynamoDB call", logger.Field{Key: "error", Value: err.Error()})
+		return nil, false
 	}
 
 	// Get the item from DynamoDB
-	result, err := s.client.GetItem(ctx, &dynamodb.GetItemInput{
-		TableName: aws.String(s.tableName),
-		Key:       key,
-	})
+	item, err := s.getTicketItem(ctx, ticketID)
+	// An eventually consistent read may miss a ticket created moments ago, so give it one more try
+	if err == nil && item == nil && s.readRetryDelay > 0 && (!s.consistentReads || s.compositeKey) {
+		item, err = s.retryTicketRead(ctx, log, ticketID)
+	}
 	if err != nil {
 		log.Error("Failed to retrieve ticket from DynamoDB", logger.Field{Key: "error", Value: err.Error()})
 		return nil, false
 	}
 
 	// Check if item exists
-	if result.Item == nil {
+	if item == nil {
 		log.Warn("Ticket not found")
 		return nil, false
 	}
 
 	// Unmarshal the item into a ticket
 	ticket := &model.ParkingTicket{}
-	if err := s.unmarshalMap(result.Item, ticket); err != nil {
+	if err := s.unmarshalMap(item, ticket); err != nil {
 		log.Error("Failed to unmarshal ticket", logger.Field{Key: "error", Value: err.Error()})
 		return nil, false
 	}
 
 	log.Info("Successfully retrieved ticket",
-		logger.Field{Key: "plate", Value: ticket.Plate},
+		logger.Plate(ticket.Plate),
 		logger.Field{Key: "parking_lot", Value: ticket.ParkingLot},
 	)
 	return ticket, true
 }
 
+// FindActiveTicket queries the plate index for a ticket that is still in the given lot
+func (s *ParkingLotService) FindActiveTicket(ctx context.Context, plate string, parkingLot int) (*model.ParkingTicket, error) {
+	log := s.log.WithContext(ctx).WithFields(
+		logger.Plate(plate),
+		logger.Field{Key: "parking_lot", Value: parkingLot},
+	)
+	log.Info("Looking up active ticket")
+
+	ticket, err := s.findPlateTicket(ctx, plate, parkingLot, model.TicketStatusIn, nil)
+	if err != nil {
+		log.Error("Failed to look up active ticket", logger.Field{Key: "error", Value: err.Error()})
+		return nil, err
+	}
+	if ticket == nil {
+		log.Info("No active ticket found")
+		return nil, nil
+	}
+
+	log.Info("Found active ticket", logger.Field{Key: "ticket_id", Value: ticket.TicketID})
+	return ticket, nil
+}
+
+// FindReentryTicket queries the plate index for a ticket that exited the given lot within the grace window
+func (s *ParkingLotService) FindReentryTicket(ctx context.Context, plate string, parkingLot int) (*model.ParkingTicket, error) {
+	if s.graceReentry <= 0 {
+		return nil, nil
+	}
+
+	log := s.log.WithContext(ctx).WithFields(
+		logger.Plate(plate),
+		logger.Field{Key: "parking_lot", Value: parkingLot},
+	)
+	log.Info("Looking up ticket for re-entry")
+
+	cutoff := s.now().Add(-s.graceReentry)
+	ticket, err := s.findPlateTicket(ctx, plate, parkingLot, model.TicketStatusOut, func(ticket *model.ParkingTicket) bool {
+		return ticket.ExitTime != nil && !ticket.ExitTime.Before(cutoff)
+	})
+	if err != nil {
+		log.Error("Failed to look up ticket for re-entry", logger.Field{Key: "error", Value: err.Error()})
+		return nil, err
+	}
+	if ticket == nil {
+		log.Info("No ticket exited within the grace window")
+		return nil, nil
+	}
+
+	log.Info("Found ticket exited within the grace window", logger.Field{Key: "ticket_id", Value: ticket.TicketID})
+	return ticket, nil
+}
+
+// findPlateTicket returns the first ticket for a plate in a lot with the given status that also satisfies match
+func (s *ParkingLotService) findPlateTicket(ctx context.Context, plate string, parkingLot int, status model.TicketStatus, match func(*model.ParkingTicket) bool) (*model.ParkingTicket, error) {
+	input := &dynamodb.QueryInput{
+		TableName:              aws.String(s.table(ctx)),
+		IndexName:              aws.String(plateIndexName),
+		KeyConditionExpression: aws.String("plate = :plate"),
+		FilterExpression:       aws.String("parkingLot = :parkingLot AND #status = :status"),
+		ExpressionAttributeNames: map[string]string{
+			"#status": "status", // status is a reserved word
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":plate":      &types.AttributeValueMemberS{Value: plate},
+			":parkingLot": &types.AttributeValueMemberN{Value: strconv.Itoa(parkingLot)},
+			":status":     &types.AttributeValueMemberS{Value: string(status)},
+		},
+	}
+	if s.compositeKey {
+		// Plate is the partition key of composite-key tables, so query the table itself
+		input.IndexName = nil
+	}
+
+	// Filters apply after the key lookup, so keep paging until a match is found
+	for {
+		result, err := s.client.Query(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query plate index: %w", err)
+		}
+
+		for _, item := range result.Items {
+			ticket := &model.ParkingTicket{}
+			if err := s.unmarshalMap(item, ticket); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal ticket: %w", err)
+			}
+			if ticket.ParkingLot == parkingLot && ticket.Status == status && (match == nil || match(ticket)) {
+				return ticket, nil
+			}
+		}
+
+		if len(result.LastEvaluatedKey) == 0 {
+			return nil, nil
+		}
+		input.ExclusiveStartKey = result.LastEvaluatedKey
+	}
+}
+
+// ReopenTicket puts an exited ticket back in the lot and stores it, keeping the original entry time
+func (s *ParkingLotService) ReopenTicket(ctx context.Context, ticket *model.ParkingTicket) error {
+	log := s.log.WithContext(ctx).WithFields(
+		logger.Field{Key: "ticket_id", Value: ticket.TicketID},
+		logger.Plate(ticket.Plate),
+		logger.Field{Key: "parking_lot", Value: ticket.ParkingLot},
+	)
+	log.Info("Reopening parking ticket")
+
+	ticket.Reopen()
+
+	// The spot held before was freed at exit, so the returning vehicle gets a new one
+	if err := s.assignSpot(ctx, log, ticket); err != nil {
+		return fmt.Errorf("failed to allocate spot: %w", err)
+	}
+
+	// Like UpdateTicket, versioned updates only reopen the ticket while it is at the version it was read at
+	expected := ticket.Version
+	if s.versionedUpdates {
+		ticket.Version++
+	}
+
+	item, err := s.marshalMap(ticket)
+	if err != nil {
+		ticket.Version = expected
+		s.ReleaseSpot(ctx, ticket)
+		log.Error("Failed to marshal ticket for reopen", logger.Field{Key: "error", Value: err.Error()})
+		return fmt.Errorf("failed to marshal ticket for reopen: %w", err)
+	}
+
+	input := &dynamodb.PutItemInput{
+		TableName: aws.String(s.table(ctx)),
+		Item:      item,
+	}
+	if s.versionedUpdates {
+		input.ConditionExpression, input.ExpressionAttributeNames, input.ExpressionAttributeValues = versionCondition(expected)
+	}
+	_, err = s.client.PutItem(ctx, input)
+	var conflict *types.ConditionalCheckFailedException
+	if errors.As(err, &conflict) {
+		ticket.Version = expected
+		s.ReleaseSpot(ctx, ticket)
+		log.Warn("Ticket was updated concurrently", logger.Field{Key: "version", Value: expected})
+		return fmt.Errorf("failed to reopen ticket: %w", ErrTicketConflict)
+	}
+	if err != nil {
+		ticket.Version = expected
+		s.ReleaseSpot(ctx, ticket)
+		log.Error("Failed to reopen ticket in DynamoDB", logger.Field{Key: "error", Value: err.Error()})
+		return fmt.Errorf("failed to reopen ticket in DynamoDB: %w", err)
+	}
+
+	log.Info("Successfully reopened ticket")
+	s.recordEvent(ctx, model.TicketEvent{
+		TicketID:   ticket.TicketID,
+		Type:       model.EventTypeReentry,
+		Time:       s.now(),
+		SpotNumber: ticket.SpotNumber,
+		Version:    ticket.Version,
+	})
+	return nil
+}
+
 // RemoveTicket removes a ticket from DynamoDB
 func (s *ParkingLotService) RemoveTicket(ctx context.Context, ticketID string) {
 	log := s.log.WithContext(ctx).WithFields(logger.Field{Key: "ticket_id", Value: ticketID})
 	log.Info("Removing ticket")
 
+	if err := ctx.Err(); err != nil {
+		log.Warn("Request canceled, skipping DynamoDB call", logger.Field{Key: "error", Value: err.Error()})
+		return
+	}
+
 	// Create the key for DynamoDB deletion
 	key := map[string]types.AttributeValue{
 		"ticketId": &types.AttributeValueMemberS{Value: ticketID},
 	}
+	if s.compositeKey {
+		// The composite key is only known from the stored ticket
+		item, err := s.getTicketItem(ctx, ticketID)
+		if err != nil || item == nil {
+			log.Warn("Ticket to remove not found")
+			return
+		}
+		key = s.itemKey(item)
+	}
 
 	// Delete the item from DynamoDB
 	_, err := s.client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
-		TableName: aws.String(s.tableName),
+		TableName: aws.String(s.table(ctx)),
 		Key:       key,
 	})
 	if err != nil {
@@ -204,72 +831,311 @@ func (s *ParkingLotService) RemoveTicket(ctx context.Context, ticketID string) {
 	}
 }
 
+// Rates returns the rate schedule, filling in defaults for unset values
+func (s *ParkingLotService) Rates() model.RateSchedule {
+	rates := s.rates
+	if rates.IncrementMinutes <= 0 {
+		rates.IncrementMinutes = defaultIncrementMinutes
+	}
+	if rates.RatePerIncrement == 0 {
+		rates.RatePerIncrement = defaultRatePerIncrement
+	}
+	if rates.Currency == "" {
+		rates.Currency = defaultCurrency
+	}
+	return rates
+}
+
+// ExitWindow returns how long a kiosk quote is honored at exit
+func (s *ParkingLotService) ExitWindow() time.Duration {
+	return s.exitWindow
+}
+
+// pricingStrategy returns the configured strategy, defaulting to the increment rate schedule
+func (s *ParkingLotService) pricingStrategy() PricingStrategy {
+	if s.pricing != nil {
+		return s.pricing
+	}
+	return IncrementPricingStrategy{Rates: s.Rates()}
+}
+
+// lotPricingStrategy returns the strategy for a lot. Lots missing from LOT_CONFIG are billed
+// at UNKNOWN_LOT_RATE per increment when it is set, so typo'd lot numbers do not get default prices.
+func (s *ParkingLotService) lotPricingStrategy(parkingLot int) PricingStrategy {
+	if s.unknownLotRate > 0 && !s.KnownLot(parkingLot) {
+		rates := s.Rates()
+		rates.RatePerIncrement = s.unknownLotRate
+		return IncrementPricingStrategy{Rates: rates}
+	}
+	return s.pricingStrategy()
+}
+
+// now returns the current time from the configured clock
+func (s *ParkingLotService) now() time.Time {
+	if s.clock != nil {
+		return s.clock()
+	}
+	return time.Now()
+}
+
 // CalculateCharge calculates parking fee
 func (s *ParkingLotService) CalculateCharge(entryTime time.Time) (int, float32) {
-	duration := time.Since(entryTime)
+	_, minutes, charge := s.calculateCharge(s.pricingStrategy(), entryTime, s.now())
+	return minutes, charge
+}
+
+// CalculateChargeBetween calculates the parking fee of a stay in a lot that ended at exitTime, billed with
+// the lot's pricing like the exit was. Unlike CalculateCharge it does not read the clock, so the charge
+// of an exited ticket is the same every time it is computed.
+func (s *ParkingLotService) CalculateChargeBetween(parkingLot int, entryTime, exitTime time.Time) (int, float32) {
+	_, minutes, charge := s.calculateCharge(s.lotPricingStrategy(parkingLot), entryTime, exitTime)
+	return minutes, charge
+}
+
+// CalculateChargeDetailed calculates the parking fee in a lot, returning the exact parked duration
+// along with the rounded minutes and the charge
+func (s *ParkingLotService) CalculateChargeDetailed(parkingLot int, entryTime time.Time) (time.Duration, int, float32) {
+	return s.calculateCharge(s.lotPricingStrategy(parkingLot), entryTime, s.now())
+}
+
+// ChargeEstimator is implemented by services that can preview the charge of an expected stay
+type ChargeEstimator interface {
+	// EstimateCharge returns what a stay of the given length from entryTime in a lot would be charged
+	// for a ticket assigned the given pricing experiment arm ("" when none)
+	EstimateCharge(arm string, parkingLot int, entryTime time.Time, stay time.Duration) float32
+}
+
+// EstimateCharge bills a stay of the given length with the arm's pricing strategy in the lot, as the exit
+// of such a stay would be billed. Nothing is recorded; the ticket is billed for its actual stay at exit.
+func (s *ParkingLotService) EstimateCharge(arm string, parkingLot int, entryTime time.Time, stay time.Duration) float32 {
+	_, _, charge := s.calculateCharge(s.armPricingStrategy(arm, parkingLot), entryTime, entryTime.Add(stay))
+	return charge
+}
+
+// calculateCharge bills the time from entryTime to exitTime with the given strategy
+func (s *ParkingLotService) calculateCharge(strategy PricingStrategy, entryTime, exitTime time.Time) (time.Duration, int, float32) {
+	duration, minutes, breakdown := s.calculateBreakdown(strategy, entryTime, exitTime)
+	return duration, minutes, breakdown.Total
+}
+
+// calculateBreakdown bills the time from entryTime to exitTime with the given strategy, itemizing
+// the charge into the strategy's base charge and the surcharges added to it
+func (s *ParkingLotService) calculateBreakdown(strategy PricingStrategy, entryTime, exitTime time.Time) (time.Duration, int, ChargeBreakdown) {
+	duration := exitTime.Sub(entryTime)
 	totalMinutes := duration.Minutes() // Get duration as float64 for precision
 
-	// Threshold for zero charge: 1 microsecond in minutes.
-	// (1 microsecond = 1e-6 seconds). (1e-6 seconds) / 60 seconds/minute.
-	const zeroChargeThresholdMinutes = (1.0e-6) / 60.0
-	if totalMinutes < zeroChargeThresholdMinutes {
-		return 0, 0.0
+	tolerances := s.chargeTolerances()
+	if duration < tolerances.zeroChargeThreshold {
+		return duration, 0, ChargeBreakdown{}
 	}
 
-	// Epsilon to handle floating point inaccuracies at 15-minute boundaries.
-	// If entryTime was exactly 15 mins ago, time.Since(entryTime) might yield 15.000...01 minutes.
-	// Subtracting this epsilon helps ensure it's treated as 15 minutes (1st increment)
-	// and not pushed into the 2nd increment.
-	// 1 millisecond = 0.001 seconds. (0.001 seconds) / 60 seconds/minute.
-	const boundaryEpsilonMinutes = 0.001 / 60.0
-
-	adjustedMinutes := totalMinutes - boundaryEpsilonMinutes
-	// Ensure adjustedMinutes doesn't become negative if totalMinutes is very small but above zeroChargeThreshold.
+	// Stays over MAX_PARK_DURATION are only billed up to the limit. The boundary epsilon keeps a stay
+	// measured a hair over an increment boundary in the increment it ends.
+	billedEnd := s.billedUntil(entryTime, exitTime)
+	adjustedMinutes := (billedEnd.Sub(entryTime) - tolerances.boundaryEpsilon).Minutes()
+	// Ensure adjustedMinutes doesn't become negative if the stay is very short but above the zero-charge threshold
 	if adjustedMinutes < 0 {
 		adjustedMinutes = 0
 	}
 
-	numberOf15MinIncrements := math.Ceil(adjustedMinutes / 15.0)
+	// Every component is rounded to cents and the total is their sum, so the itemized lines always add up
+	base := roundCents(strategy.Charge(adjustedMinutes))
+	surcharge := roundCents(s.applySurcharges(base, entryTime, billedEnd) - base)
+
+	// The daily maximum caps the surcharged charge too, so surcharges are cut back to fit under it
+	if increment, ok := strategy.(IncrementPricingStrategy); ok {
+		if limit := roundCents(increment.dailyCap(adjustedMinutes)); limit > 0 && base+surcharge > limit {
+			surcharge = roundCents(max(limit-base, 0))
+		}
+	}
+	charge := roundCents(base + surcharge)
+	breakdown := ChargeBreakdown{BaseCharge: base, Surcharge: surcharge}
 
-	// If totalMinutes was positive (>= zeroChargeThresholdMinutes) but numberOf15MinIncrements became 0
-	// (due to adjustedMinutes becoming <= 0), it should still count as 1 increment.
-	// This handles cases where zeroChargeThresholdMinutes < totalMinutes <= boundaryEpsilonMinutes.
-	if numberOf15MinIncrements == 0 && totalMinutes >= zeroChargeThresholdMinutes {
-		numberOf15MinIncrements = 1
+	// Apply the configured floor; zero-length stays were already returned above. The floor and the
+	// cap below replace the computed charge, so they leave nothing to itemize as a surcharge.
+	if charge < s.minCharge {
+		charge = roundCents(s.minCharge)
+		breakdown = ChargeBreakdown{BaseCharge: charge}
 	}
 
-	charge := float32(numberOf15MinIncrements * 2.5)
-	return int(math.Round(totalMinutes)), charge
+	// No real stay should cost this much, so a larger charge points at bad data such as an entry
+	// time from 1970; charge the cap and log an error so the ticket gets looked at
+	if s.maxSessionCharge > 0 && charge > s.maxSessionCharge {
+		s.log.Error("Charge exceeds MAX_SESSION_CHARGE, clamping",
+			logger.Field{Key: "entry_time", Value: entryTime},
+			logger.Field{Key: "exit_time", Value: exitTime},
+			logger.Field{Key: "charge", Value: charge},
+			logger.Field{Key: "max_session_charge", Value: s.maxSessionCharge},
+		)
+		charge = roundCents(s.maxSessionCharge)
+		breakdown = ChargeBreakdown{BaseCharge: charge}
+	}
+
+	breakdown.Total = charge
+	return duration, s.displayMinutes(strategy, totalMinutes, adjustedMinutes), breakdown
 }
 
-// UpdateTicket updates an existing parking ticket in DynamoDB
+// UpdateTicket updates an existing parking ticket in DynamoDB. Storing an exited ticket records
+// its exit and the payment of its charge in the event log.
 func (s *ParkingLotService) UpdateTicket(ctx context.Context, ticket *model.ParkingTicket) error {
+	if err := s.putTicket(ctx, ticket); err != nil {
+		return err
+	}
+
+	if ticket.Status == model.TicketStatusOut {
+		exitTime := s.now()
+		if ticket.ExitTime != nil {
+			exitTime = *ticket.ExitTime
+		}
+		s.recordEvent(ctx, model.TicketEvent{
+			TicketID:        ticket.TicketID,
+			Type:            model.EventTypeExit,
+			Time:            exitTime,
+			Charge:          ticket.Charge,
+			DurationMinutes: ticket.DurationMinutes,
+			CouponCode:      ticket.CouponCode,
+			Version:         ticket.Version,
+		})
+		if ticket.Charge > 0 {
+			paid := exitTime
+			if ticket.ExitTime != nil {
+				paid = *ticket.PaymentTime(s.exitWindow)
+			}
+			s.recordEvent(ctx, model.TicketEvent{
+				TicketID: ticket.TicketID,
+				Type:     model.EventTypePayment,
+				Time:     paid,
+				Charge:   ticket.Charge,
+			})
+		}
+	}
+	return nil
+}
+
+// putTicket overwrites a stored ticket in DynamoDB without recording events, for updates such as
+// plate corrections that record their own
+func (s *ParkingLotService) putTicket(ctx context.Context, ticket *model.ParkingTicket) error {
 	log := s.log.WithContext(ctx).WithFields(
 		logger.Field{Key: "ticket_id", Value: ticket.TicketID},
-		logger.Field{Key: "plate", Value: ticket.Plate},
+		logger.Plate(ticket.Plate),
 		logger.Field{Key: "parking_lot", Value: ticket.ParkingLot},
 		logger.Field{Key: "status", Value: string(ticket.Status)},
 		logger.Field{Key: "charge", Value: ticket.Charge},
 	)
 	log.Info("Updating parking ticket")
 
+	if err := ctx.Err(); err != nil {
+		log.Warn("Request canceled, skipping DynamoDB call", logger.Field{Key: "error", Value: err.Error()})
+		return fmt.Errorf("failed to update ticket: %w", err)
+	}
+
+	// With versioned updates the ticket is only written while the stored version is the one it was read at
+	expected := ticket.Version
+	if s.versionedUpdates {
+		ticket.Version++
+	}
+
 	// Marshal the ticket for DynamoDB
 	item, err := s.marshalMap(ticket)
 	if err != nil {
+		ticket.Version = expected
 		log.Error("Failed to marshal ticket for update", logger.Field{Key: "error", Value: err.Error()})
 		return fmt.Errorf("failed to marshal ticket for update: %w", err)
 	}
 
-	// Update the ticket in DynamoDB
-	_, err = s.client.PutItem(ctx, &dynamodb.PutItemInput{
-		TableName: aws.String(s.tableName),
-		Item:      item, // PutItem will overwrite the existing item with the same key
-	})
+	// Update the ticket in DynamoDB; without versioned updates PutItem overwrites the item with the same key
+	input := &dynamodb.PutItemInput{
+		TableName: aws.String(s.table(ctx)),
+		Item:      item,
+	}
+	if s.versionedUpdates {
+		input.ConditionExpression, input.ExpressionAttributeNames, input.ExpressionAttributeValues = versionCondition(expected)
+	}
+	_, err = s.client.PutItem(ctx, input)
+	var conflict *types.ConditionalCheckFailedException
+	if errors.As(err, &conflict) {
+		ticket.Version = expected
+		log.Warn("Ticket was updated concurrently", logger.Field{Key: "version", Value: expected})
+		return fmt.Errorf("failed to update ticket: %w", ErrTicketConflict)
+	}
 	if err != nil {
+		ticket.Version = expected
 		log.Error("Failed to update ticket in DynamoDB", logger.Field{Key: "error", Value: err.Error()})
-		return fmt.Errorf("failed to update ticket in DynamoDB: %w", err)
+		return fmt.Errorf("failed to update ticket in DynamoDB: %w", wrapItemTooLarge(err))
 	}
 
 	log.Info("Successfully updated ticket in DynamoDB")
 	return nil
 }
+
+// versionCondition returns the condition that the stored ticket is still at the expected version.
+// Tickets that were never updated with versioning store no version, which counts as zero.
+func versionCondition(expected int) (*string, map[string]string, map[string]types.AttributeValue) {
+	names := map[string]string{"#version": "version"}
+	if expected == 0 {
+		return aws.String("attribute_not_exists(#version)"), names, nil
+	}
+	return aws.String("#version = :version"), names, map[string]types.AttributeValue{
+		":version": &types.AttributeValueMemberN{Value: strconv.Itoa(expected)},
+	}
+}
+
+// ReserveSpaces claims spaces in a lot for an entering vehicle
+func (s *ParkingLotService) ReserveSpaces(ctx context.Context, parkingLot int, spaces int) error {
+	if s.strictLots && !s.KnownLot(parkingLot) {
+		return ErrUnknownLot
+	}
+	if s.occupancy == nil {
+		return nil
+	}
+
+	log := s.log.WithContext(ctx).WithFields(
+		logger.Field{Key: "parking_lot", Value: parkingLot},
+		logger.Field{Key: "spaces", Value: spaces},
+	)
+
+	var err error
+	held, ok := s.occupancy.(heldReserver)
+	if blocked := s.blockedSpaces(ctx, log, parkingLot); blocked > 0 && ok {
+		log = log.WithFields(logger.Field{Key: "blocked_spaces", Value: blocked})
+		err = held.ReserveHeld(ctx, parkingLot, spaces, blocked)
+	} else {
+		err = s.occupancy.Reserve(ctx, parkingLot, spaces)
+	}
+	if err != nil {
+		log.Warn("Failed to reserve spaces", logger.Field{Key: "error", Value: err.Error()})
+		return err
+	}
+
+	log.Info("Reserved spaces")
+	return nil
+}
+
+// OccupancySnapshot returns the spaces in use per lot as counted by this process,
+// or nil when occupancy is not tracked in memory
+func (s *ParkingLotService) OccupancySnapshot() map[int]int {
+	counter, ok := s.occupancy.(*MemoryOccupancy)
+	if !ok {
+		return nil
+	}
+	return counter.Snapshot()
+}
+
+// ReleaseSpaces returns spaces to a lot for an exiting vehicle
+func (s *ParkingLotService) ReleaseSpaces(ctx context.Context, parkingLot int, spaces int) {
+	if s.occupancy == nil {
+		return
+	}
+
+	log := s.log.WithContext(ctx).WithFields(
+		logger.Field{Key: "parking_lot", Value: parkingLot},
+		logger.Field{Key: "spaces", Value: spaces},
+	)
+
+	if err := s.occupancy.Release(ctx, parkingLot, spaces); err != nil {
+		log.Error("Failed to release spaces", logger.Field{Key: "error", Value: err.Error()})
+		return
+	}
+
+	log.Info("Released spaces")
+}
@@ -6,12 +6,14 @@ import (
 	"testing"
 	"time"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 
 	"parking-lot/internal/logger"
 	"parking-lot/internal/mocks"
@@ -38,7 +40,7 @@ func TestCreateTicket(t *testing.T) {
 	service.client.(*mocks.DynamoDBClient).On("PutItem", ctx, mock.Anything, mock.Anything).Return(&dynamodb.PutItemOutput{}, nil).Once()
 
 	// Call the function
-	ticketID, ticket := service.CreateTicket(ctx, plate, parkingLot)
+	ticketID, ticket := service.CreateTicket(ctx, plate, parkingLot, 1)
 
 	// Assertions
 	assert.NotNil(t, ticketID)
@@ -48,10 +50,87 @@ func TestCreateTicket(t *testing.T) {
 	assert.WithinDuration(t, time.Now(), ticket.EntryTime, 2*time.Second)
 	assert.Equal(t, model.TicketStatusIn, ticket.Status)
 	assert.Equal(t, float32(0.0), ticket.Charge)
+	assert.Equal(t, 1, ticket.SpacesUsed)
+	assert.NotEmpty(t, ticket.SessionID)
+	assert.NotEqual(t, ticket.TicketID, ticket.SessionID)
 
 	service.client.(*mocks.DynamoDBClient).AssertExpectations(t)
 }
 
+// fixedIDGenerator returns the same ticket ID every time
+type fixedIDGenerator string
+
+func (g fixedIDGenerator) NewID() string { return string(g) }
+
+// TestCreateTicket_IDGenerator tests that tickets take their ID from the configured generator
+func TestCreateTicket_IDGenerator(t *testing.T) {
+	const fixedID = "00000000-0000-4000-8000-000000000001"
+
+	t.Run("Fixed ID", func(t *testing.T) {
+		ctx := context.Background()
+		mockClient := new(mocks.DynamoDBClient)
+		service := &ParkingLotService{
+			client:     mockClient,
+			tableName:  "testTable",
+			log:        logger.NewLogger(),
+			marshalMap: attributevalue.MarshalMap,
+			ids:        fixedIDGenerator(fixedID),
+		}
+		mockClient.On("PutItem", ctx, mock.MatchedBy(func(input *dynamodb.PutItemInput) bool {
+			ticketID, ok := input.Item["ticketId"].(*types.AttributeValueMemberS)
+			return ok && ticketID.Value == fixedID
+		}), mock.Anything).Return(&dynamodb.PutItemOutput{}, nil).Once()
+
+		ticketID, ticket := service.CreateTicket(ctx, "ABC-123", 1, 1)
+
+		assert.Equal(t, fixedID, ticketID.String())
+		assert.Equal(t, fixedID, ticket.TicketID)
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run("Non-UUID ID", func(t *testing.T) {
+		mockClient := new(mocks.DynamoDBClient)
+		service := &ParkingLotService{client: mockClient, tableName: "testTable", log: logger.NewLogger(), ids: fixedIDGenerator("ABC123")}
+
+		ticketID, ticket := service.CreateTicket(context.Background(), "ABC-123", 1, 1)
+
+		assert.Equal(t, uuid.Nil, ticketID)
+		assert.Nil(t, ticket)
+		mockClient.AssertNotCalled(t, "PutItem", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+// TestCreateTicket_MaskedPlateLog tests that LOG_PLATE_MASK masks logs but not the stored plate
+func TestCreateTicket_MaskedPlateLog(t *testing.T) {
+	t.Setenv("LOG_PLATE_MASK", "true")
+
+	ctx := context.Background()
+	mockClient := new(mocks.DynamoDBClient)
+	log := mocks.NewLogger()
+	service := &ParkingLotService{
+		ctx:          ctx,
+		client:       mockClient,
+		tableName:    "testTable",
+		log:          log,
+		marshalMap:   attributevalue.MarshalMap,
+		unmarshalMap: attributevalue.UnmarshalMap,
+	}
+
+	mockClient.On("PutItem", ctx, mock.MatchedBy(func(input *dynamodb.PutItemInput) bool {
+		plate, ok := input.Item["plate"].(*types.AttributeValueMemberS)
+		return ok && plate.Value == "AB123423"
+	}), mock.Anything).Return(&dynamodb.PutItemOutput{}, nil).Once()
+
+	_, ticket := service.CreateTicket(ctx, "AB123423", 123, 1)
+
+	assert.Equal(t, "AB123423", ticket.Plate)
+	entries := log.Find("Creating parking ticket")
+	if assert.Len(t, entries, 1) {
+		assert.Equal(t, "AB****23", entries[0].Fields["plate"])
+	}
+	mockClient.AssertExpectations(t)
+}
+
 // TestCreateTicket_MarshalError tests the ticket creation with Marshal error
 func TestCreateTicket_MarshalError(t *testing.T) {
 	ctx := context.Background()
@@ -66,11 +145,96 @@ func TestCreateTicket_MarshalError(t *testing.T) {
 		},
 		unmarshalMap: attributevalue.UnmarshalMap,
 	}
-	id, ticket := service.CreateTicket(ctx, "PLATE", 1)
+	id, ticket := service.CreateTicket(ctx, "PLATE", 1, 1)
 	assert.NotNil(t, id)
 	assert.NotNil(t, ticket)
 }
 
+// TestCreateTicket_MarshalFallback tests that a failed marshal is retried without only the field
+// that failed, so a multi-space ticket with a spot still frees both at exit
+func TestCreateTicket_MarshalFallback(t *testing.T) {
+	ctx := WithVehicle(context.Background(), model.Vehicle{Make: "exotic", Color: "red", TransponderID: "TAG-1"})
+	mockClient := new(mocks.DynamoDBClient)
+	log := mocks.NewLogger()
+	spots := NewMemorySpots()
+	occupancy := NewMemoryOccupancy(func(int) int { return 0 })
+	service := &ParkingLotService{
+		ctx:       ctx,
+		client:    mockClient,
+		tableName: "testTable",
+		log:       log,
+		lots:      map[int]model.LotConfig{1: {Spots: 1}},
+		spots:     spots,
+		occupancy: occupancy,
+		clock:     func() time.Time { return time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC) },
+		marshalMap: func(in interface{}) (map[string]types.AttributeValue, error) {
+			if ticket, ok := in.(*model.ParkingTicket); ok && ticket.Make == "exotic" {
+				return nil, fmt.Errorf("marshal error")
+			}
+			return attributevalue.MarshalMap(in)
+		},
+		unmarshalMap:    attributevalue.UnmarshalMap,
+		marshalFallback: true,
+	}
+
+	var stored map[string]types.AttributeValue
+	mockClient.On("PutItem", ctx, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		stored = args.Get(1).(*dynamodb.PutItemInput).Item
+	}).Return(&dynamodb.PutItemOutput{}, nil).Once()
+
+	assert.NoError(t, service.ReserveSpaces(ctx, 1, 2))
+	_, ticket, err := service.CreateTicketChecked(ctx, "PLATE", 1, 2)
+	require.NoError(t, err)
+
+	var storedTicket model.ParkingTicket
+	require.NoError(t, attributevalue.UnmarshalMap(stored, &storedTicket))
+	assert.Equal(t, *ticket, storedTicket)
+	assert.Equal(t, 2, storedTicket.SpacesUsed)
+	assert.Equal(t, 1, storedTicket.SpotNumber)
+	assert.Equal(t, "TAG-1", storedTicket.TransponderID)
+	assert.Equal(t, "red", storedTicket.Color)
+	assert.Empty(t, storedTicket.Make)
+	assert.Len(t, log.Find("Failed to marshal ticket, dropped an optional field"), 1)
+
+	// Exiting the stored ticket frees every space it took and its spot
+	service.ReleaseSpaces(ctx, storedTicket.ParkingLot, storedTicket.Spaces())
+	service.ReleaseSpot(ctx, &storedTicket)
+	assert.Equal(t, 0, service.OccupancySnapshot()[1])
+	spot, err := spots.Allocate(ctx, 1, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, spot)
+	mockClient.AssertExpectations(t)
+}
+
+// TestCreateTicket_MarshalFallbackFails tests that a marshal failure no descriptive field explains
+// fails the entry and frees its spot
+func TestCreateTicket_MarshalFallbackFails(t *testing.T) {
+	ctx := context.Background()
+	mockClient := new(mocks.DynamoDBClient)
+	spots := NewMemorySpots()
+	service := &ParkingLotService{
+		ctx:       ctx,
+		client:    mockClient,
+		tableName: "testTable",
+		log:       logger.NewLogger(),
+		lots:      map[int]model.LotConfig{1: {Spots: 1}},
+		spots:     spots,
+		marshalMap: func(interface{}) (map[string]types.AttributeValue, error) {
+			return nil, fmt.Errorf("marshal error")
+		},
+		unmarshalMap:    attributevalue.UnmarshalMap,
+		marshalFallback: true,
+	}
+
+	_, _, err := service.CreateTicketChecked(WithVehicle(ctx, model.Vehicle{Make: "Volvo"}), "PLATE", 1, 2)
+
+	assert.Error(t, err)
+	spot, err := spots.Allocate(ctx, 1, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, spot)
+	mockClient.AssertNotCalled(t, "PutItem", mock.Anything, mock.Anything, mock.Anything)
+}
+
 // TestCreateTicket_PutItemError tests the ticket creation with PutItem error
 func TestCreateTicket_PutItemError(t *testing.T) {
 	ctx := context.Background()
@@ -84,7 +248,7 @@ func TestCreateTicket_PutItemError(t *testing.T) {
 		unmarshalMap: attributevalue.UnmarshalMap,
 	}
 	mockClient.On("PutItem", ctx, mock.Anything, mock.Anything).Return(nil, fmt.Errorf("put error"))
-	id, ticket := service.CreateTicket(ctx, "PLATE", 1)
+	id, ticket := service.CreateTicket(ctx, "PLATE", 1, 1)
 	assert.NotNil(t, id)
 	assert.NotNil(t, ticket)
 }
@@ -156,6 +320,40 @@ func TestGetTicket(t *testing.T) {
 	})
 }
 
+// TestGetTicket_ConsistentReads tests that CONSISTENT_READS is passed through to GetItem
+func TestGetTicket_ConsistentReads(t *testing.T) {
+	tests := []struct {
+		name     string
+		enabled  string
+		expected *bool
+	}{
+		{name: "Disabled", enabled: "", expected: nil},
+		{name: "Enabled", enabled: "true", expected: aws.Bool(true)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("CONSISTENT_READS", tt.enabled)
+			ctx := context.Background()
+			service, err := newConfiguredService(ctx, logger.NewLogger())
+			assert.NoError(t, err)
+			mockClient := new(mocks.DynamoDBClient)
+			service.client = mockClient
+			service.tableName = "testTable"
+			service.unmarshalMap = attributevalue.UnmarshalMap
+
+			mockClient.On("GetItem", ctx, mock.MatchedBy(func(input *dynamodb.GetItemInput) bool {
+				return assert.ObjectsAreEqual(tt.expected, input.ConsistentRead)
+			}), mock.Anything).Return(&dynamodb.GetItemOutput{}, nil).Once()
+
+			_, found := service.GetTicket(ctx, "ticket-1")
+
+			assert.False(t, found)
+			mockClient.AssertExpectations(t)
+		})
+	}
+}
+
 // TestGetTicket_GetItemError tests error handling in GetTicket
 func TestGetTicket_GetItemError(t *testing.T) {
 	ctx := context.Background()
@@ -195,6 +393,209 @@ func TestGetTicket_UnmarshalError(t *testing.T) {
 	assert.Nil(t, ticket)
 }
 
+// TestFindActiveTicket tests looking up an active ticket through the plate index
+func TestFindActiveTicket(t *testing.T) {
+	ctx := context.Background()
+	plate := "DUP-123"
+
+	newService := func(mockClient *mocks.DynamoDBClient) *ParkingLotService {
+		return &ParkingLotService{
+			ctx:          ctx,
+			client:       mockClient,
+			tableName:    "testTable",
+			log:          logger.NewLogger(),
+			marshalMap:   attributevalue.MarshalMap,
+			unmarshalMap: attributevalue.UnmarshalMap,
+		}
+	}
+	marshal := func(ticket *model.ParkingTicket) map[string]types.AttributeValue {
+		item, err := attributevalue.MarshalMap(ticket)
+		assert.NoError(t, err)
+		return item
+	}
+
+	t.Run("No active ticket", func(t *testing.T) {
+		mockClient := new(mocks.DynamoDBClient)
+		mockClient.On("Query", ctx, mock.Anything, mock.Anything).Return(&dynamodb.QueryOutput{}, nil).Once()
+
+		ticket, err := newService(mockClient).FindActiveTicket(ctx, plate, 1)
+
+		assert.NoError(t, err)
+		assert.Nil(t, ticket)
+	})
+
+	t.Run("Active ticket in same lot", func(t *testing.T) {
+		mockClient := new(mocks.DynamoDBClient)
+		active := &model.ParkingTicket{TicketID: "active", Plate: plate, ParkingLot: 1, Status: model.TicketStatusIn}
+		mockClient.On("Query", ctx, mock.MatchedBy(func(in *dynamodb.QueryInput) bool {
+			return *in.IndexName == plateIndexName &&
+				in.ExpressionAttributeValues[":parkingLot"].(*types.AttributeValueMemberN).Value == "1"
+		}), mock.Anything).Return(&dynamodb.QueryOutput{Items: []map[string]types.AttributeValue{marshal(active)}}, nil).Once()
+
+		ticket, err := newService(mockClient).FindActiveTicket(ctx, plate, 1)
+
+		assert.NoError(t, err)
+		if assert.NotNil(t, ticket) {
+			assert.Equal(t, "active", ticket.TicketID)
+		}
+	})
+
+	t.Run("Active ticket in different lot", func(t *testing.T) {
+		mockClient := new(mocks.DynamoDBClient)
+		other := &model.ParkingTicket{TicketID: "other", Plate: plate, ParkingLot: 2, Status: model.TicketStatusIn}
+		mockClient.On("Query", ctx, mock.Anything, mock.Anything).Return(&dynamodb.QueryOutput{Items: []map[string]types.AttributeValue{marshal(other)}}, nil).Once()
+
+		ticket, err := newService(mockClient).FindActiveTicket(ctx, plate, 1)
+
+		assert.NoError(t, err)
+		assert.Nil(t, ticket)
+	})
+
+	t.Run("Query error", func(t *testing.T) {
+		mockClient := new(mocks.DynamoDBClient)
+		mockClient.On("Query", ctx, mock.Anything, mock.Anything).Return(nil, fmt.Errorf("query error")).Once()
+
+		ticket, err := newService(mockClient).FindActiveTicket(ctx, plate, 1)
+
+		assert.Error(t, err)
+		assert.Nil(t, ticket)
+	})
+}
+
+// TestFindReentryTicket tests the lookup of tickets exited within the grace window
+func TestFindReentryTicket(t *testing.T) {
+	ctx := context.Background()
+	plate := "RE-123"
+
+	newService := func(mockClient *mocks.DynamoDBClient) *ParkingLotService {
+		return &ParkingLotService{
+			ctx:          ctx,
+			client:       mockClient,
+			tableName:    "testTable",
+			log:          logger.NewLogger(),
+			marshalMap:   attributevalue.MarshalMap,
+			unmarshalMap: attributevalue.UnmarshalMap,
+			graceReentry: 10 * time.Minute,
+		}
+	}
+	exitedAgo := func(ago time.Duration) map[string]types.AttributeValue {
+		exitTime := time.Now().Add(-ago)
+		item, err := attributevalue.MarshalMap(&model.ParkingTicket{
+			TicketID: "exited", Plate: plate, ParkingLot: 1, Status: model.TicketStatusOut, ExitTime: &exitTime,
+		})
+		assert.NoError(t, err)
+		return item
+	}
+
+	t.Run("Inside window", func(t *testing.T) {
+		mockClient := new(mocks.DynamoDBClient)
+		mockClient.On("Query", ctx, mock.MatchedBy(func(in *dynamodb.QueryInput) bool {
+			return in.ExpressionAttributeValues[":status"].(*types.AttributeValueMemberS).Value == string(model.TicketStatusOut)
+		}), mock.Anything).Return(&dynamodb.QueryOutput{Items: []map[string]types.AttributeValue{exitedAgo(5 * time.Minute)}}, nil).Once()
+
+		ticket, err := newService(mockClient).FindReentryTicket(ctx, plate, 1)
+
+		assert.NoError(t, err)
+		if assert.NotNil(t, ticket) {
+			assert.Equal(t, "exited", ticket.TicketID)
+		}
+	})
+
+	t.Run("Outside window", func(t *testing.T) {
+		mockClient := new(mocks.DynamoDBClient)
+		mockClient.On("Query", ctx, mock.Anything, mock.Anything).Return(&dynamodb.QueryOutput{Items: []map[string]types.AttributeValue{exitedAgo(15 * time.Minute)}}, nil).Once()
+
+		ticket, err := newService(mockClient).FindReentryTicket(ctx, plate, 1)
+
+		assert.NoError(t, err)
+		assert.Nil(t, ticket)
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		mockClient := new(mocks.DynamoDBClient)
+		service := newService(mockClient)
+		service.graceReentry = 0
+
+		ticket, err := service.FindReentryTicket(ctx, plate, 1)
+
+		assert.NoError(t, err)
+		assert.Nil(t, ticket)
+		mockClient.AssertNotCalled(t, "Query", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+// TestReopenTicket tests that a reopened ticket keeps its entry time and clears the exit
+func TestReopenTicket(t *testing.T) {
+	ctx := context.Background()
+	mockClient := new(mocks.DynamoDBClient)
+	service := &ParkingLotService{
+		ctx:          ctx,
+		client:       mockClient,
+		tableName:    "testTable",
+		log:          logger.NewLogger(),
+		marshalMap:   attributevalue.MarshalMap,
+		unmarshalMap: attributevalue.UnmarshalMap,
+	}
+
+	entryTime := time.Now().Add(-time.Hour)
+	exitTime := time.Now().Add(-5 * time.Minute)
+	ticket := &model.ParkingTicket{
+		TicketID: "exited", EntryTime: entryTime, Status: model.TicketStatusOut,
+		Charge: 10, DurationMinutes: 55, ExitTime: &exitTime,
+	}
+	mockClient.On("PutItem", ctx, mock.MatchedBy(func(in *dynamodb.PutItemInput) bool {
+		_, hasExitTime := in.Item["exitTime"]
+		return in.Item["status"].(*types.AttributeValueMemberS).Value == string(model.TicketStatusIn) && !hasExitTime
+	}), mock.Anything).Return(&dynamodb.PutItemOutput{}, nil).Once()
+
+	err := service.ReopenTicket(ctx, ticket)
+
+	assert.NoError(t, err)
+	assert.Equal(t, model.TicketStatusIn, ticket.Status)
+	assert.Equal(t, entryTime, ticket.EntryTime)
+	assert.Zero(t, ticket.Charge)
+	assert.Nil(t, ticket.ExitTime)
+	mockClient.AssertExpectations(t)
+}
+
+// TestReopenTicket_Versioned tests that reopening is conditioned on the version the ticket was read at
+// and reports a stale version as ErrTicketConflict
+func TestReopenTicket_Versioned(t *testing.T) {
+	ctx := context.Background()
+	mockClient := new(mocks.DynamoDBClient)
+	service := &ParkingLotService{
+		ctx:              ctx,
+		client:           mockClient,
+		tableName:        "testTable",
+		log:              logger.NewLogger(),
+		marshalMap:       attributevalue.MarshalMap,
+		unmarshalMap:     attributevalue.UnmarshalMap,
+		versionedUpdates: true,
+	}
+	exitTime := time.Now().Add(-5 * time.Minute)
+	conditioned := func(version string, stored string) interface{} {
+		return mock.MatchedBy(func(input *dynamodb.PutItemInput) bool {
+			item, ok := input.Item["version"].(*types.AttributeValueMemberN)
+			expected, hasExpected := input.ExpressionAttributeValues[":version"].(*types.AttributeValueMemberN)
+			return ok && item.Value == stored && input.ConditionExpression != nil &&
+				*input.ConditionExpression == "#version = :version" && hasExpected && expected.Value == version
+		})
+	}
+
+	ticket := &model.ParkingTicket{TicketID: "exited", Status: model.TicketStatusOut, ExitTime: &exitTime, Version: 3}
+	mockClient.On("PutItem", ctx, conditioned("3", "4"), mock.Anything).Return(&dynamodb.PutItemOutput{}, nil).Once()
+	assert.NoError(t, service.ReopenTicket(ctx, ticket))
+	assert.Equal(t, 4, ticket.Version)
+
+	// Another request updated the ticket after it was read at version 3
+	stale := &model.ParkingTicket{TicketID: "exited", Status: model.TicketStatusOut, ExitTime: &exitTime, Version: 3}
+	mockClient.On("PutItem", ctx, conditioned("3", "4"), mock.Anything).
+		Return(nil, &types.ConditionalCheckFailedException{}).Once()
+	assert.ErrorIs(t, service.ReopenTicket(ctx, stale), ErrTicketConflict)
+	assert.Equal(t, 3, stale.Version)
+	mockClient.AssertExpectations(t)
+}
+
 // TestRemoveTicket tests the ticket removal functionality
 func TestRemoveTicket(t *testing.T) {
 	// Setup
@@ -320,6 +721,129 @@ func TestUpdateTicket_PutItemError(t *testing.T) {
 	mockClient.AssertCalled(t, "PutItem", ctx, mock.AnythingOfType("*dynamodb.PutItemInput"), mock.Anything)
 }
 
+// TestUpdateTicket_Versioned tests that versioned updates are conditioned on the version the ticket
+// was read at, increment it on success and report a stale version as ErrTicketConflict
+func TestUpdateTicket_Versioned(t *testing.T) {
+	ctx := context.Background()
+	mockClient := new(mocks.DynamoDBClient)
+	service := &ParkingLotService{
+		ctx:              ctx,
+		client:           mockClient,
+		tableName:        "testTable",
+		log:              logger.NewLogger(),
+		marshalMap:       attributevalue.MarshalMap,
+		unmarshalMap:     attributevalue.UnmarshalMap,
+		versionedUpdates: true,
+	}
+	conditioned := func(condition string, version string, stored string) interface{} {
+		return mock.MatchedBy(func(input *dynamodb.PutItemInput) bool {
+			item, ok := input.Item["version"].(*types.AttributeValueMemberN)
+			if !ok || item.Value != stored || *input.ConditionExpression != condition {
+				return false
+			}
+			expected, ok := input.ExpressionAttributeValues[":version"].(*types.AttributeValueMemberN)
+			return version == "" && !ok || ok && expected.Value == version
+		})
+	}
+
+	// A ticket never updated with versioning has no stored version
+	ticket := &model.ParkingTicket{TicketID: "test-id", Plate: "ABC-123", ParkingLot: 1, Status: model.TicketStatusIn}
+	mockClient.On("PutItem", ctx, conditioned("attribute_not_exists(#version)", "", "1"), mock.Anything).Return(&dynamodb.PutItemOutput{}, nil).Once()
+	assert.NoError(t, service.UpdateTicket(ctx, ticket))
+	assert.Equal(t, 1, ticket.Version)
+
+	mockClient.On("PutItem", ctx, conditioned("#version = :version", "1", "2"), mock.Anything).Return(&dynamodb.PutItemOutput{}, nil).Once()
+	assert.NoError(t, service.UpdateTicket(ctx, ticket))
+	assert.Equal(t, 2, ticket.Version)
+
+	// Another request updated the ticket after it was read at version 2
+	mockClient.On("PutItem", ctx, conditioned("#version = :version", "2", "3"), mock.Anything).
+		Return(nil, &types.ConditionalCheckFailedException{}).Once()
+	err := service.UpdateTicket(ctx, ticket)
+	assert.ErrorIs(t, err, ErrTicketConflict)
+	assert.Equal(t, 2, ticket.Version)
+	mockClient.AssertExpectations(t)
+}
+
+// TestUpdateTicket_Unversioned tests that updates overwrite unconditionally without VERSIONED_UPDATES
+func TestUpdateTicket_Unversioned(t *testing.T) {
+	ctx := context.Background()
+	mockClient := new(mocks.DynamoDBClient)
+	service := &ParkingLotService{
+		ctx:          ctx,
+		client:       mockClient,
+		tableName:    "testTable",
+		log:          logger.NewLogger(),
+		marshalMap:   attributevalue.MarshalMap,
+		unmarshalMap: attributevalue.UnmarshalMap,
+	}
+
+	mockClient.On("PutItem", ctx, mock.MatchedBy(func(input *dynamodb.PutItemInput) bool {
+		_, versioned := input.Item["version"]
+		return input.ConditionExpression == nil && !versioned
+	}), mock.Anything).Return(&dynamodb.PutItemOutput{}, nil).Once()
+
+	ticket := &model.ParkingTicket{TicketID: "test-id"}
+	assert.NoError(t, service.UpdateTicket(ctx, ticket))
+	assert.Zero(t, ticket.Version)
+	mockClient.AssertExpectations(t)
+}
+
+// TestMemoryParkingLotService_VersionedUpdates tests that a stale copy of a ticket cannot overwrite a newer update
+func TestMemoryParkingLotService_VersionedUpdates(t *testing.T) {
+	t.Setenv("VERSIONED_UPDATES", "true")
+	ctx := context.Background()
+	service, err := NewMemoryParkingLotService(ctx)
+	assert.NoError(t, err)
+
+	_, created := service.CreateTicket(ctx, "ABC-123", 1, 1)
+	first, _ := service.GetTicket(ctx, created.TicketID)
+	second, _ := service.GetTicket(ctx, created.TicketID)
+
+	first.Charge = 5
+	assert.NoError(t, service.UpdateTicket(ctx, first))
+
+	second.Charge = 7
+	assert.ErrorIs(t, service.UpdateTicket(ctx, second), ErrTicketConflict)
+
+	stored, _ := service.GetTicket(ctx, created.TicketID)
+	assert.Equal(t, float32(5), stored.Charge)
+	assert.Equal(t, 1, stored.Version)
+}
+
+// TestCanceledContext tests that a canceled request does not reach DynamoDB
+func TestCanceledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	mockClient := new(mocks.DynamoDBClient)
+	service := &ParkingLotService{
+		ctx:          context.Background(),
+		client:       mockClient,
+		tableName:    "testTable",
+		log:          logger.NewLogger(),
+		marshalMap:   attributevalue.MarshalMap,
+		unmarshalMap: attributevalue.UnmarshalMap,
+	}
+
+	ticketID, ticket := service.CreateTicket(ctx, "ABC-123", 1, 1)
+	assert.Equal(t, uuid.Nil, ticketID)
+	assert.Nil(t, ticket)
+
+	ticket, found := service.GetTicket(ctx, uuid.New().String())
+	assert.False(t, found)
+	assert.Nil(t, ticket)
+
+	err := service.UpdateTicket(ctx, &model.ParkingTicket{TicketID: uuid.New().String()})
+	assert.ErrorIs(t, err, context.Canceled)
+
+	service.RemoveTicket(ctx, uuid.New().String())
+
+	// No expectations were set, so any DynamoDB call would have panicked
+	mockClient.AssertExpectations(t)
+	assert.Empty(t, mockClient.Calls)
+}
+
 // TestCalculateCharge tests the charge calculation logic
 func TestCalculateCharge(t *testing.T) {
 	// Setup
@@ -443,6 +967,9 @@ func TestCalculateCharge(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			// Simulate the entry time by subtracting the duration from the current time
 			entryTime := time.Now().Add(-tc.duration)
+			// Pin the clock just past the end of the stay, under the zero-charge threshold, so the
+			// result does not depend on how long the test takes to reach the calculation
+			service.clock = func() time.Time { return entryTime.Add(tc.duration + 500*time.Nanosecond) }
 
 			minutes, charge := service.CalculateCharge(entryTime)
 
@@ -459,6 +986,213 @@ func TestCalculateCharge(t *testing.T) {
 	}
 }
 
+// TestCalculateCharge_MinCharge tests the configurable minimum charge
+func TestCalculateCharge_MinCharge(t *testing.T) {
+	testCases := []struct {
+		name           string
+		minCharge      float32
+		duration       time.Duration
+		expectedCharge float32
+	}{
+		{
+			name:           "Floor exceeds first increment",
+			minCharge:      5.0,
+			duration:       1 * time.Minute,
+			expectedCharge: 5.0,
+		},
+		{
+			name:           "Floor below computed charge",
+			minCharge:      1.0,
+			duration:       20 * time.Minute,
+			expectedCharge: 5.0,
+		},
+		{
+			name:           "Zero duration stays free",
+			minCharge:      5.0,
+			duration:       0,
+			expectedCharge: 0.0,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			now := time.Now()
+			service := &ParkingLotService{minCharge: tc.minCharge, clock: func() time.Time { return now }}
+
+			_, charge := service.CalculateCharge(now.Add(-tc.duration))
+
+			assert.Equal(t, tc.expectedCharge, charge)
+		})
+	}
+}
+
+// TestCalculateCharge_MaxSessionCharge tests that a charge from an ancient entry time is clamped
+// to MAX_SESSION_CHARGE and logged as an error, while ordinary charges pass untouched
+func TestCalculateCharge_MaxSessionCharge(t *testing.T) {
+	log := mocks.NewLogger()
+	service := &ParkingLotService{log: log, maxSessionCharge: 500}
+	exitTime := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	_, charge := service.CalculateChargeBetween(1, time.Unix(0, 0).UTC(), exitTime)
+
+	assert.Equal(t, float32(500), charge)
+	entries := log.Find("Charge exceeds MAX_SESSION_CHARGE, clamping")
+	if assert.Len(t, entries, 1) {
+		assert.Equal(t, "error", entries[0].Level)
+		assert.Greater(t, entries[0].Fields["charge"], float32(500))
+	}
+
+	_, charge = service.CalculateChargeBetween(1, exitTime.Add(-time.Hour), exitTime)
+
+	assert.Equal(t, float32(10), charge)
+	assert.Len(t, log.Find("Charge exceeds MAX_SESSION_CHARGE, clamping"), 1)
+}
+
+// TestCalculateChargeDetailed tests that the exact duration is the clock delta
+func TestCalculateChargeDetailed(t *testing.T) {
+	entryTime := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+	exitTime := entryTime.Add(44*time.Minute + 29*time.Second + 250*time.Millisecond)
+	service := &ParkingLotService{clock: func() time.Time { return exitTime }}
+
+	duration, minutes, charge := service.CalculateChargeDetailed(1, entryTime)
+
+	assert.Equal(t, exitTime.Sub(entryTime), duration)
+	assert.Equal(t, 2669.25, duration.Seconds())
+	assert.Equal(t, 44, minutes)
+	assert.Equal(t, float32(7.5), charge)
+
+	// CalculateCharge keeps returning the rounded minutes
+	minutes, charge = service.CalculateCharge(entryTime)
+	assert.Equal(t, 44, minutes)
+	assert.Equal(t, float32(7.5), charge)
+}
+
+// TestCalculateChargeBetween tests that a finished stay is billed up to its exit time whatever the clock reads
+func TestCalculateChargeBetween(t *testing.T) {
+	entryTime := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+	exitTime := entryTime.Add(44 * time.Minute)
+	now := exitTime
+	service := &ParkingLotService{clock: func() time.Time { return now }}
+
+	minutes, charge := service.CalculateChargeBetween(1, entryTime, exitTime)
+	assert.Equal(t, 44, minutes)
+	assert.Equal(t, float32(7.5), charge)
+
+	// Hours later the same stay still costs the same
+	now = exitTime.Add(5 * time.Hour)
+	for i := 0; i < 3; i++ {
+		again, againCharge := service.CalculateChargeBetween(1, entryTime, exitTime)
+		assert.Equal(t, minutes, again)
+		assert.Equal(t, charge, againCharge)
+	}
+	_, live := service.CalculateCharge(entryTime)
+	assert.Greater(t, live, charge)
+
+	// Lots missing from LOT_CONFIG were billed at UNKNOWN_LOT_RATE at exit, and still are
+	service.lots = map[int]model.LotConfig{1: {}}
+	service.unknownLotRate = 10
+	_, charge = service.CalculateChargeBetween(4, entryTime, exitTime)
+	assert.Equal(t, float32(30), charge)
+}
+
+// TestEstimateCharge tests that an expected stay is billed with the lot's pricing strategy
+func TestEstimateCharge(t *testing.T) {
+	entryTime := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+	rates := model.RateSchedule{IncrementMinutes: 15, RatePerIncrement: 2.5}
+	service := &ParkingLotService{
+		rates:          rates,
+		lots:           map[int]model.LotConfig{3: {}},
+		unknownLotRate: 10,
+		clock:          func() time.Time { return entryTime },
+	}
+
+	for _, stay := range []time.Duration{time.Minute, 15 * time.Minute, 90 * time.Minute, 5 * time.Hour} {
+		expected := IncrementPricingStrategy{Rates: rates}.Charge(stay.Minutes())
+		assert.Equal(t, expected, service.EstimateCharge("", 3, entryTime, stay), stay)
+	}
+
+	// Lots missing from LOT_CONFIG are estimated at UNKNOWN_LOT_RATE, as they are billed
+	assert.Equal(t, float32(60), service.EstimateCharge("", 4, entryTime, 90*time.Minute))
+}
+
+// TestCalculateCharge_RateSchedule tests charging with a configured rate schedule
+func TestCalculateCharge_RateSchedule(t *testing.T) {
+	testCases := []struct {
+		name           string
+		rates          model.RateSchedule
+		duration       time.Duration
+		expectedCharge float32
+	}{
+		{
+			name:           "30 minute increments",
+			rates:          model.RateSchedule{IncrementMinutes: 30, RatePerIncrement: 4},
+			duration:       45 * time.Minute,
+			expectedCharge: 8.0,
+		},
+		{
+			name:           "Daily max caps a single day",
+			rates:          model.RateSchedule{DailyMax: 30},
+			duration:       10 * time.Hour,
+			expectedCharge: 30.0,
+		},
+		{
+			name:           "Daily max applies per started day",
+			rates:          model.RateSchedule{DailyMax: 30},
+			duration:       25 * time.Hour,
+			expectedCharge: 40.0, // one capped day + 4 increments
+		},
+		{
+			name:           "Daily max below threshold has no effect",
+			rates:          model.RateSchedule{DailyMax: 30},
+			duration:       1 * time.Hour,
+			expectedCharge: 10.0,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			service := &ParkingLotService{rates: tc.rates}
+
+			_, charge := service.CalculateCharge(time.Now().Add(-tc.duration))
+
+			assert.Equal(t, tc.expectedCharge, charge)
+		})
+	}
+}
+
+// TestCalculateChargeDetailed_UnknownLotRate tests billing lots missing from LOT_CONFIG at UNKNOWN_LOT_RATE
+func TestCalculateChargeDetailed_UnknownLotRate(t *testing.T) {
+	entryTime := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+	exitTime := entryTime.Add(45 * time.Minute)
+	lots := map[int]model.LotConfig{382: {Capacity: 10}}
+
+	testCases := []struct {
+		name           string
+		unknownLotRate float32
+		parkingLot     int
+		expectedCharge float32
+	}{
+		{name: "Known lot uses the rate schedule", unknownLotRate: 10, parkingLot: 382, expectedCharge: 7.5},
+		{name: "Unknown lot uses the fallback rate", unknownLotRate: 10, parkingLot: 328, expectedCharge: 30},
+		{name: "Unknown lot without a fallback rate", parkingLot: 328, expectedCharge: 7.5},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			service := &ParkingLotService{
+				lots:           lots,
+				unknownLotRate: tc.unknownLotRate,
+				clock:          func() time.Time { return exitTime },
+			}
+
+			_, minutes, charge := service.CalculateChargeDetailed(tc.parkingLot, entryTime)
+
+			assert.Equal(t, 45, minutes)
+			assert.Equal(t, tc.expectedCharge, charge)
+		})
+	}
+}
+
 // For testing purposes
 var unmarshalMap = func(item map[string]interface{}, out interface{}) error {
 	// This would be replaced with the actual DynamoDB unmarshalling in tests
@@ -0,0 +1,168 @@
+package service
+
+import (
+	"fmt"
+	"math"
+
+	"parking-lot/internal/model"
+)
+
+// PricingStrategy turns a parked duration into a charge.
+// Charge is only called for non-zero stays, so strategies bill at least one block.
+type PricingStrategy interface {
+	Charge(minutes float64) float32
+}
+
+// IncrementPricingStrategy bills every started increment at a flat rate,
+// optionally capping each started day at the daily maximum
+type IncrementPricingStrategy struct {
+	Rates model.RateSchedule
+}
+
+// Charge calculates the charge for the given number of minutes
+func (p IncrementPricingStrategy) Charge(minutes float64) float32 {
+	incrementMinutes := float64(p.Rates.IncrementMinutes)
+	ratePerIncrement := float64(p.Rates.RatePerIncrement)
+
+	numberOfIncrements := math.Max(math.Ceil(minutes/incrementMinutes), 1)
+	charge := float32(numberOfIncrements * ratePerIncrement)
+
+	// Cap every started day at the daily maximum
+	if p.Rates.DailyMax > 0 {
+		const minutesPerDay = 24 * 60
+		dailyMax := float64(p.Rates.DailyMax)
+
+		fullDays := math.Floor(minutes / minutesPerDay)
+		fullDayCharge := math.Min(math.Ceil(minutesPerDay/incrementMinutes)*ratePerIncrement, dailyMax)
+		remainingIncrements := math.Ceil((minutes - fullDays*minutesPerDay) / incrementMinutes)
+		if fullDays == 0 && remainingIncrements == 0 {
+			remainingIncrements = 1
+		}
+		remainingCharge := math.Min(remainingIncrements*ratePerIncrement, dailyMax)
+
+		charge = float32(fullDays*fullDayCharge + remainingCharge)
+	}
+
+	return charge
+}
+
+// dailyCap returns the most a stay of the given number of minutes may be charged, the daily
+// maximum for every started day, or 0 when the schedule sets no daily maximum
+func (p IncrementPricingStrategy) dailyCap(minutes float64) float32 {
+	if p.Rates.DailyMax <= 0 {
+		return 0
+	}
+	const minutesPerDay = 24 * 60
+	startedDays := math.Max(math.Ceil(minutes/minutesPerDay), 1)
+	return float32(startedDays * float64(p.Rates.DailyMax))
+}
+
+// PricingTier is one step of a tiered price list
+type PricingTier struct {
+	// DurationMinutes is where the tier ends, measured from entry.
+	// It must increase from tier to tier; the last tier may omit it and always covers the rest of the stay.
+	DurationMinutes int `json:"durationMinutes"`
+	// Rate is charged for every started block within the tier
+	Rate float32 `json:"rate"`
+	// BlockMinutes is the length of a billing block, one hour when omitted
+	BlockMinutes int `json:"blockMinutes,omitempty"`
+}
+
+// TieredPricingStrategy bills each part of a stay at the rate of the tier it falls in,
+// e.g. "first hour $5, each additional hour $3"
+type TieredPricingStrategy struct {
+	Tiers []PricingTier
+}
+
+// NewTieredPricingStrategy validates tiers and creates the strategy
+func NewTieredPricingStrategy(tiers []PricingTier) (*TieredPricingStrategy, error) {
+	if len(tiers) == 0 {
+		return nil, fmt.Errorf("at least one pricing tier is required")
+	}
+
+	previous := 0
+	for i, tier := range tiers {
+		last := i == len(tiers)-1
+		if tier.Rate < 0 {
+			return nil, fmt.Errorf("tier %d: rate must not be negative", i+1)
+		}
+		if tier.BlockMinutes < 0 {
+			return nil, fmt.Errorf("tier %d: blockMinutes must not be negative", i+1)
+		}
+		if tier.DurationMinutes == 0 && last {
+			continue
+		}
+		if tier.DurationMinutes <= previous {
+			return nil, fmt.Errorf("tier %d: durationMinutes %d must be greater than %d", i+1, tier.DurationMinutes, previous)
+		}
+		previous = tier.DurationMinutes
+	}
+
+	return &TieredPricingStrategy{Tiers: tiers}, nil
+}
+
+// Charge sums the charges of every tier the stay reaches
+func (p *TieredPricingStrategy) Charge(minutes float64) float32 {
+	var charge float64
+	start := 0.0
+
+	for i, tier := range p.Tiers {
+		end := math.Inf(1)
+		if i < len(p.Tiers)-1 {
+			end = float64(tier.DurationMinutes)
+		}
+
+		blockMinutes := float64(tier.BlockMinutes)
+		if blockMinutes == 0 {
+			blockMinutes = 60
+		}
+
+		minutesInTier := math.Min(minutes, end) - start
+		if minutesInTier <= 0 {
+			break
+		}
+		charge += math.Ceil(minutesInTier/blockMinutes) * float64(tier.Rate)
+		start = end
+	}
+
+	// Any non-zero stay bills the first block
+	if charge == 0 && len(p.Tiers) > 0 {
+		charge = float64(p.Tiers[0].Rate)
+	}
+
+	return float32(charge)
+}
+
+// BilledMinutes returns the minutes covered by the increments a stay is billed for, e.g. 45 for 31 minutes
+// in 15 minute increments
+func (p IncrementPricingStrategy) BilledMinutes(minutes float64) float64 {
+	incrementMinutes := float64(p.Rates.IncrementMinutes)
+	return math.Max(math.Ceil(minutes/incrementMinutes), 1) * incrementMinutes
+}
+
+// BilledMinutes returns the minutes covered by the blocks a stay is billed for in every tier it reaches
+func (p *TieredPricingStrategy) BilledMinutes(minutes float64) float64 {
+	var billed float64
+	start := 0.0
+
+	for i, tier := range p.Tiers {
+		end := math.Inf(1)
+		if i < len(p.Tiers)-1 {
+			end = float64(tier.DurationMinutes)
+		}
+
+		blockMinutes := float64(tier.BlockMinutes)
+		if blockMinutes == 0 {
+			blockMinutes = 60
+		}
+
+		minutesInTier := math.Min(minutes, end) - start
+		if minutesInTier <= 0 {
+			break
+		}
+		billed += math.Ceil(minutesInTier/blockMinutes) * blockMinutes
+		start = end
+	}
+
+	return billed
+}
@@ -0,0 +1,125 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestTieredPricingStrategy_TwoTiers tests "first hour $5, each additional hour $3"
+func TestTieredPricingStrategy_TwoTiers(t *testing.T) {
+	strategy, err := NewTieredPricingStrategy([]PricingTier{
+		{DurationMinutes: 60, Rate: 5},
+		{Rate: 3},
+	})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name           string
+		duration       time.Duration
+		expectedCharge float32
+	}{
+		{name: "Within first tier", duration: 30 * time.Minute, expectedCharge: 5.0},
+		{name: "Exactly first tier", duration: 60 * time.Minute, expectedCharge: 5.0},
+		{name: "Into second tier", duration: 90 * time.Minute, expectedCharge: 8.0},
+		{name: "Two hours", duration: 2 * time.Hour, expectedCharge: 8.0},
+		{name: "Spanning several second tier blocks", duration: 150 * time.Minute, expectedCharge: 11.0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			service := &ParkingLotService{pricing: strategy}
+
+			_, charge := service.CalculateCharge(time.Now().Add(-tc.duration))
+
+			assert.Equal(t, tc.expectedCharge, charge)
+		})
+	}
+}
+
+// TestTieredPricingStrategy_BlockMinutes tests tiers billed in custom blocks
+func TestTieredPricingStrategy_BlockMinutes(t *testing.T) {
+	strategy, err := NewTieredPricingStrategy([]PricingTier{
+		{DurationMinutes: 30, Rate: 0, BlockMinutes: 30},
+		{DurationMinutes: 120, Rate: 2, BlockMinutes: 15},
+		{Rate: 10},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, float32(0), strategy.Charge(20))
+	assert.Equal(t, float32(4), strategy.Charge(60))
+	assert.Equal(t, float32(22), strategy.Charge(150))
+}
+
+// TestNewTieredPricingStrategy_Validation tests rejection of bad tier lists
+func TestNewTieredPricingStrategy_Validation(t *testing.T) {
+	testCases := []struct {
+		name  string
+		tiers []PricingTier
+		err   string
+	}{
+		{name: "No tiers", tiers: nil, err: "at least one pricing tier is required"},
+		{
+			name:  "Decreasing duration",
+			tiers: []PricingTier{{DurationMinutes: 60, Rate: 5}, {DurationMinutes: 30, Rate: 3}, {Rate: 1}},
+			err:   "tier 2: durationMinutes 30 must be greater than 60",
+		},
+		{
+			name:  "Open tier before the last",
+			tiers: []PricingTier{{Rate: 5}, {DurationMinutes: 60, Rate: 3}},
+			err:   "tier 1: durationMinutes 0 must be greater than 0",
+		},
+		{
+			name:  "Negative rate",
+			tiers: []PricingTier{{DurationMinutes: 60, Rate: -1}},
+			err:   "tier 1: rate must not be negative",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := NewTieredPricingStrategy(tc.tiers)
+
+			assert.EqualError(t, err, tc.err)
+		})
+	}
+}
+
+// TestLoadPricingTiers tests reading PRICING_TIERS from the environment
+func TestLoadPricingTiers(t *testing.T) {
+	t.Run("Unset", func(t *testing.T) {
+		t.Setenv("PRICING_TIERS", "")
+
+		strategy, err := loadPricingTiers()
+
+		require.NoError(t, err)
+		assert.Nil(t, strategy)
+	})
+
+	t.Run("Valid", func(t *testing.T) {
+		t.Setenv("PRICING_TIERS", `[{"durationMinutes":60,"rate":5},{"rate":3}]`)
+
+		strategy, err := loadPricingTiers()
+
+		require.NoError(t, err)
+		assert.Equal(t, []PricingTier{{DurationMinutes: 60, Rate: 5}, {Rate: 3}}, strategy.Tiers)
+	})
+
+	t.Run("Malformed JSON", func(t *testing.T) {
+		t.Setenv("PRICING_TIERS", `{"rate":5}`)
+
+		_, err := loadPricingTiers()
+
+		assert.ErrorContains(t, err, "invalid PRICING_TIERS")
+	})
+
+	t.Run("Non-monotonic tiers", func(t *testing.T) {
+		t.Setenv("PRICING_TIERS", `[{"durationMinutes":60,"rate":5},{"durationMinutes":60,"rate":3}]`)
+
+		_, err := loadPricingTiers()
+
+		assert.EqualError(t, err, "invalid PRICING_TIERS: tier 2: durationMinutes 60 must be greater than 60")
+	})
+}
@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/sony/gobreaker/v2"
+
+	"parking-lot/internal/logger"
+)
+
+// ErrReadOnly is returned without calling DynamoDB for writes while sustained write failures keep the
+// service read-only. It wraps ErrDynamoDBUnavailable so callers failing fast on it answer 503.
+var ErrReadOnly = fmt.Errorf("%w: service is read-only", ErrDynamoDBUnavailable)
+
+// defaultReadOnlyWriteFailures is how many consecutive failed writes switch the service to read-only mode
+const defaultReadOnlyWriteFailures = 5
+
+// newWriteBreaker creates the breaker that switches the service to read-only mode from the environment.
+//
+// READ_ONLY_WRITE_FAILURES is the number of consecutive failed writes that opens it (0 disables
+// read-only mode). It stays open for DYNAMODB_BREAKER_COOLDOWN_SECONDS before letting a trial write
+// through. Reads are never guarded, so lookups keep working while writes are down.
+func newWriteBreaker(log logger.Logger) (*gobreaker.CircuitBreaker[any], error) {
+	failures, err := envInt("READ_ONLY_WRITE_FAILURES", defaultReadOnlyWriteFailures)
+	if err != nil {
+		return nil, err
+	}
+	if failures == 0 {
+		return nil, nil
+	}
+	cooldownSeconds, err := envInt("DYNAMODB_BREAKER_COOLDOWN_SECONDS", int(defaultBreakerCooldown/time.Second))
+	if err != nil {
+		return nil, err
+	}
+
+	return newReadOnlyBreaker(uint32(failures), time.Duration(cooldownSeconds)*time.Second, log), nil
+}
+
+// newReadOnlyBreaker creates a write breaker that opens after the given number of consecutive failed writes
+func newReadOnlyBreaker(failures uint32, cooldown time.Duration, log logger.Logger) *gobreaker.CircuitBreaker[any] {
+	return gobreaker.NewCircuitBreaker[any](gobreaker.Settings{
+		Name:    "dynamodb-writes",
+		Timeout: cooldown,
+		ReadyToTrip: func(counts gobreaker.Counts) bool {
+			return counts.ConsecutiveFailures >= failures
+		},
+		IsSuccessful: isBreakerSuccess,
+		IsExcluded: func(err error) bool {
+			// Fast-fails of the DynamoDB breaker say nothing about writes in particular
+			return errors.Is(err, context.Canceled) || errors.Is(err, ErrDynamoDBUnavailable)
+		},
+		OnStateChange: func(name string, from, to gobreaker.State) {
+			log.Warn("DynamoDB write breaker changed state, read-only mode follows it",
+				logger.Field{Key: "from", Value: from.String()},
+				logger.Field{Key: "to", Value: to.String()},
+			)
+		},
+	})
+}
+
+// readOnlyClient wraps a DynamoDBClient so that writes fail fast while the write breaker is open
+type readOnlyClient struct {
+	DynamoDBClient
+	breaker *gobreaker.CircuitBreaker[any]
+}
+
+// newReadOnlyClient wraps client's writes with the write breaker
+func newReadOnlyClient(client DynamoDBClient, breaker *gobreaker.CircuitBreaker[any]) DynamoDBClient {
+	return &readOnlyClient{DynamoDBClient: client, breaker: breaker}
+}
+
+// callWrite runs a write through the write breaker, translating fast-fails to ErrReadOnly
+func callWrite[T any](breaker *gobreaker.CircuitBreaker[any], call func() (T, error)) (T, error) {
+	out, err := breaker.Execute(func() (any, error) {
+		return call()
+	})
+	if errors.Is(err, gobreaker.ErrOpenState) || errors.Is(err, gobreaker.ErrTooManyRequests) {
+		var zero T
+		return zero, ErrReadOnly
+	}
+	result, _ := out.(T)
+	return result, err
+}
+
+// PutItem calls PutItem through the write breaker
+func (c *readOnlyClient) PutItem(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error) {
+	return callWrite(c.breaker, func() (*dynamodb.PutItemOutput, error) {
+		return c.DynamoDBClient.PutItem(ctx, params, optFns...)
+	})
+}
+
+// DeleteItem calls DeleteItem through the write breaker
+func (c *readOnlyClient) DeleteItem(ctx context.Context, params *dynamodb.DeleteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DeleteItemOutput, error) {
+	return callWrite(c.breaker, func() (*dynamodb.DeleteItemOutput, error) {
+		return c.DynamoDBClient.DeleteItem(ctx, params, optFns...)
+	})
+}
+
+// UpdateItem calls UpdateItem through the write breaker
+func (c *readOnlyClient) UpdateItem(ctx context.Context, params *dynamodb.UpdateItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error) {
+	return callWrite(c.breaker, func() (*dynamodb.UpdateItemOutput, error) {
+		return c.DynamoDBClient.UpdateItem(ctx, params, optFns...)
+	})
+}
+
+// BatchWriteItem calls BatchWriteItem through the write breaker
+func (c *readOnlyClient) BatchWriteItem(ctx context.Context, params *dynamodb.BatchWriteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.BatchWriteItemOutput, error) {
+	return callWrite(c.breaker, func() (*dynamodb.BatchWriteItemOutput, error) {
+		return c.DynamoDBClient.BatchWriteItem(ctx, params, optFns...)
+	})
+}
+
+// TransactWriteItems calls TransactWriteItems through the write breaker
+func (c *readOnlyClient) TransactWriteItems(ctx context.Context, params *dynamodb.TransactWriteItemsInput, optFns ...func(*dynamodb.Options)) (*dynamodb.TransactWriteItemsOutput, error) {
+	return callWrite(c.breaker, func() (*dynamodb.TransactWriteItemsOutput, error) {
+		return c.DynamoDBClient.TransactWriteItems(ctx, params, optFns...)
+	})
+}
+
+// ReadOnly reports whether sustained write failures have switched the service to read-only mode,
+// in which writes fail fast with ErrReadOnly while reads keep working
+func (s *ParkingLotService) ReadOnly() bool {
+	return s.writeBreaker != nil && s.writeBreaker.State() == gobreaker.StateOpen
+}
@@ -0,0 +1,63 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"parking-lot/internal/logger"
+	"parking-lot/internal/mocks"
+	"parking-lot/internal/model"
+)
+
+// TestReadOnlyClient_WritesFailReadsWork tests that failing writes switch the service to read-only
+// mode while reads keep reaching DynamoDB
+func TestReadOnlyClient_WritesFailReadsWork(t *testing.T) {
+	ctx := context.Background()
+	mockClient := new(mocks.DynamoDBClient)
+	writeBreaker := newReadOnlyBreaker(2, time.Minute, logger.NewLogger())
+	service := &ParkingLotService{
+		ctx:          ctx,
+		client:       newReadOnlyClient(mockClient, writeBreaker),
+		tableName:    "testTable",
+		log:          logger.NewLogger(),
+		marshalMap:   attributevalue.MarshalMap,
+		unmarshalMap: attributevalue.UnmarshalMap,
+		writeBreaker: writeBreaker,
+	}
+
+	ticket := &model.ParkingTicket{TicketID: "test-id", Plate: "ABC-123", ParkingLot: 1, Status: model.TicketStatusIn}
+	item, _ := attributevalue.MarshalMap(ticket)
+	mockClient.On("PutItem", ctx, mock.Anything, mock.Anything).Return(nil, errors.New("write throttled")).Times(2)
+	mockClient.On("GetItem", ctx, mock.Anything, mock.Anything).Return(&dynamodb.GetItemOutput{Item: item}, nil)
+
+	for i := 0; i < 2; i++ {
+		err := service.UpdateTicket(ctx, ticket)
+		assert.Error(t, err)
+		assert.NotErrorIs(t, err, ErrReadOnly)
+	}
+	assert.True(t, service.ReadOnly())
+
+	// Writes now fail fast, reads still work
+	err := service.UpdateTicket(ctx, ticket)
+	assert.ErrorIs(t, err, ErrReadOnly)
+	assert.ErrorIs(t, err, ErrDynamoDBUnavailable)
+	found, ok := service.GetTicket(ctx, "test-id")
+	assert.True(t, ok)
+	assert.Equal(t, "ABC-123", found.Plate)
+
+	mockClient.AssertNumberOfCalls(t, "PutItem", 2)
+	mockClient.AssertNumberOfCalls(t, "GetItem", 1)
+}
+
+// TestReadOnly_Disabled tests that a service without a write breaker is never read-only
+func TestReadOnly_Disabled(t *testing.T) {
+	service := &ParkingLotService{}
+	assert.False(t, service.ReadOnly())
+}
@@ -0,0 +1,50 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"parking-lot/internal/logger"
+)
+
+// Default and largest delay before GetTicket reads a missing ticket again (READ_RETRY_DELAY_MS)
+const (
+	defaultReadRetryDelayMs = 100
+	maxReadRetryDelayMs     = 1000
+)
+
+// loadReadRetryDelay returns how long GetTicket waits before reading a missing ticket a second time:
+// zero, disabling the retry, unless READ_RETRY=true. READ_RETRY_DELAY_MS sets the delay and is capped
+// at one second so a lookup of a ticket that really does not exist stays fast.
+func loadReadRetryDelay() (time.Duration, error) {
+	if os.Getenv("READ_RETRY") != "true" {
+		return 0, nil
+	}
+	delayMs, err := envInt("READ_RETRY_DELAY_MS", defaultReadRetryDelayMs)
+	if err != nil {
+		return 0, err
+	}
+	if delayMs < 1 || delayMs > maxReadRetryDelayMs {
+		return 0, fmt.Errorf("invalid READ_RETRY_DELAY_MS %d: must be between 1 and %d", delayMs, maxReadRetryDelayMs)
+	}
+	return time.Duration(delayMs) * time.Millisecond, nil
+}
+
+// retryTicketRead reads a ticket that was not found once more after readRetryDelay, for a ticket
+// created so recently that an eventually consistent read missed it. A canceled request is not retried.
+func (s *ParkingLotService) retryTicketRead(ctx context.Context, log logger.Logger, ticketID string) (map[string]types.AttributeValue, error) {
+	log.Info("Ticket not found, retrying once", logger.Field{Key: "delay_ms", Value: s.readRetryDelay.Milliseconds()})
+
+	timer := time.NewTimer(s.readRetryDelay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-timer.C:
+	}
+	return s.getTicketItem(ctx, ticketID)
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"parking-lot/internal/logger"
+	"parking-lot/internal/mocks"
+	"parking-lot/internal/model"
+)
+
+// TestGetTicket_ReadRetry tests that a ticket missed by the first read is found by the retry,
+// and that nothing is retried while READ_RETRY is off
+func TestGetTicket_ReadRetry(t *testing.T) {
+	ctx := context.Background()
+	item, err := attributevalue.MarshalMap(&model.ParkingTicket{TicketID: "t1", Plate: "ABC-123", ParkingLot: 1})
+	require.NoError(t, err)
+	newService := func(delay time.Duration) (*ParkingLotService, *mocks.DynamoDBClient) {
+		mockClient := new(mocks.DynamoDBClient)
+		return &ParkingLotService{
+			ctx:            ctx,
+			client:         mockClient,
+			tableName:      "testTable",
+			log:            logger.NewLogger(),
+			marshalMap:     attributevalue.MarshalMap,
+			unmarshalMap:   attributevalue.UnmarshalMap,
+			readRetryDelay: delay,
+		}, mockClient
+	}
+
+	t.Run("Retry finds the ticket", func(t *testing.T) {
+		service, mockClient := newService(time.Millisecond)
+		mockClient.On("GetItem", ctx, mock.Anything, mock.Anything).Return(&dynamodb.GetItemOutput{}, nil).Once()
+		mockClient.On("GetItem", ctx, mock.Anything, mock.Anything).Return(&dynamodb.GetItemOutput{Item: item}, nil).Once()
+
+		ticket, ok := service.GetTicket(ctx, "t1")
+
+		require.True(t, ok)
+		assert.Equal(t, "ABC-123", ticket.Plate)
+		mockClient.AssertNumberOfCalls(t, "GetItem", 2)
+	})
+
+	t.Run("Retry disabled", func(t *testing.T) {
+		service, mockClient := newService(0)
+		mockClient.On("GetItem", ctx, mock.Anything, mock.Anything).Return(&dynamodb.GetItemOutput{}, nil).Once()
+
+		_, ok := service.GetTicket(ctx, "t1")
+
+		assert.False(t, ok)
+		mockClient.AssertNumberOfCalls(t, "GetItem", 1)
+	})
+}
+
+// TestLoadReadRetryDelay tests that the retry is off unless READ_RETRY=true and its delay is bounded
+func TestLoadReadRetryDelay(t *testing.T) {
+	tests := []struct {
+		name    string
+		retry   string
+		delay   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{name: "Disabled", retry: "", delay: "500", want: 0},
+		{name: "Default delay", retry: "true", want: defaultReadRetryDelayMs * time.Millisecond},
+		{name: "Configured delay", retry: "true", delay: "250", want: 250 * time.Millisecond},
+		{name: "Delay over the cap", retry: "true", delay: "5000", wantErr: true},
+		{name: "Zero delay", retry: "true", delay: "0", wantErr: true},
+		{name: "Invalid delay", retry: "true", delay: "soon", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("READ_RETRY", tt.retry)
+			t.Setenv("READ_RETRY_DELAY_MS", tt.delay)
+
+			delay, err := loadReadRetryDelay()
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, delay)
+		})
+	}
+}
@@ -0,0 +1,180 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"parking-lot/internal/logger"
+	"parking-lot/internal/model"
+)
+
+// reconcileAttempts is how many times a lot is recounted when its counter keeps changing meanwhile
+const reconcileAttempts = 3
+
+var (
+	// ErrOccupancyChanged is returned when a lot's counter changed during every recount, e.g. under
+	// a steady stream of entries
+	ErrOccupancyChanged = errors.New("occupancy changed while reconciling")
+	// ErrOccupancyNotSettable is returned when the service's occupancy counter cannot be reset
+	ErrOccupancyNotSettable = errors.New("occupancy counter cannot be reconciled")
+)
+
+// OccupancyReconciler is implemented by services that can correct occupancy counters that drifted
+// from the tickets actually parked
+type OccupancyReconciler interface {
+	// ReconcileOccupancy recounts the spaces held by a lot's parked tickets and resets the lot's
+	// counter to that count, which it returns. It fails with ErrOccupancyNotSettable when the
+	// counter cannot be reset.
+	ReconcileOccupancy(ctx context.Context, parkingLot int) (int, error)
+
+	// TrackedLots returns the lots whose occupancy is counted
+	TrackedLots() []int
+}
+
+// occupancySetter is implemented by occupancy counters that can be reset
+type occupancySetter interface {
+	// SetOccupied sets the spaces in use in a lot, but only while expected are in use,
+	// reporting whether it did
+	SetOccupied(ctx context.Context, parkingLot, expected, occupied int) (bool, error)
+}
+
+// ReconcileOccupancy recounts a lot's parked tickets through the parking lot index and resets its counter
+func (s *ParkingLotService) ReconcileOccupancy(ctx context.Context, parkingLot int) (int, error) {
+	return s.reconcileOccupancy(ctx, parkingLot, func(ctx context.Context, log logger.Logger) ([]*model.ParkingTicket, error) {
+		tickets, truncated, err := s.queryParkedTickets(ctx, log, parkingLot)
+		if truncated {
+			// A partial count would set the counter too low and let the lot overfill
+			return nil, ErrScanTruncated
+		}
+		return tickets, err
+	})
+}
+
+// reconcileOccupancy resets a lot's counter to the spaces of the tickets returned by parked. The counter
+// is only replaced when it did not change while counting, so entries and exits meanwhile are not lost;
+// otherwise the lot is counted again. An entry that reserved its spaces before the count but stored its
+// ticket after it is still missed until the next reconciliation.
+//
+// With DynamoDB the compare-and-set is a conditional update of the counter item every instance shares;
+// the in-memory counter is local to the process, like the tickets it counts.
+func (s *ParkingLotService) reconcileOccupancy(ctx context.Context, parkingLot int,
+	parked func(context.Context, logger.Logger) ([]*model.ParkingTicket, error)) (int, error) {
+	log := s.log.WithContext(ctx).WithFields(logger.Field{Key: "parking_lot", Value: parkingLot})
+
+	setter, ok := s.occupancy.(occupancySetter)
+	if !ok {
+		return 0, ErrOccupancyNotSettable
+	}
+
+	for attempt := 1; attempt <= reconcileAttempts; attempt++ {
+		previous, err := s.occupancy.Occupied(ctx, parkingLot)
+		if err != nil {
+			return 0, err
+		}
+
+		tickets, err := parked(ctx, log)
+		if err != nil {
+			return 0, err
+		}
+		occupied := 0
+		for _, ticket := range tickets {
+			occupied += ticket.Spaces()
+		}
+
+		set, err := setter.SetOccupied(ctx, parkingLot, previous, occupied)
+		if err != nil {
+			return 0, err
+		}
+		if set {
+			if occupied != previous {
+				log.Warn("Corrected drifted occupancy",
+					logger.Field{Key: "previous", Value: previous},
+					logger.Field{Key: "occupied", Value: occupied},
+				)
+			}
+			return occupied, nil
+		}
+		log.Info("Occupancy changed while counting, recounting", logger.Field{Key: "attempt", Value: attempt})
+	}
+
+	log.Warn("Gave up reconciling occupancy")
+	return 0, ErrOccupancyChanged
+}
+
+// TrackedLots returns the lots configured in LOT_CONFIG and any other lot this process counts spaces
+// in use for, in order
+func (s *ParkingLotService) TrackedLots() []int {
+	seen := make(map[int]bool, len(s.lots))
+	for parkingLot := range s.lots {
+		seen[parkingLot] = true
+	}
+	for parkingLot := range s.OccupancySnapshot() {
+		seen[parkingLot] = true
+	}
+
+	lots := make([]int, 0, len(seen))
+	for parkingLot := range seen {
+		lots = append(lots, parkingLot)
+	}
+	sort.Ints(lots)
+	return lots
+}
+
+// ReconcileInterval returns how often occupancy should be reconciled (RECONCILE_INTERVAL_MINUTES);
+// zero disables the periodic reconciliation
+func (s *ParkingLotService) ReconcileInterval() time.Duration {
+	return s.reconcileInterval
+}
+
+// RunOccupancyReconciler reconciles every tracked lot each interval until ctx is done. A lot that
+// fails is logged and retried on the next run.
+func RunOccupancyReconciler(ctx context.Context, reconciler OccupancyReconciler, interval time.Duration, log logger.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	log.Info("Reconciling occupancy periodically", logger.Field{Key: "interval", Value: interval.String()})
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		_ = ReconcileTrackedLots(ctx, reconciler, log)
+	}
+}
+
+// ReconcileTrackedLots reconciles every tracked lot once, e.g. on a schedule. A lot that fails is
+// logged and does not stop the others; the failures are returned together.
+func ReconcileTrackedLots(ctx context.Context, reconciler OccupancyReconciler, log logger.Logger) error {
+	var errs []error
+	for _, parkingLot := range reconciler.TrackedLots() {
+		if _, err := reconciler.ReconcileOccupancy(ctx, parkingLot); err != nil {
+			log.Error("Failed to reconcile occupancy",
+				logger.Field{Key: "parking_lot", Value: parkingLot},
+				logger.Field{Key: "error", Value: err.Error()},
+			)
+			errs = append(errs, fmt.Errorf("lot %d: %w", parkingLot, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ReconcileOccupancy recounts the tickets held in memory as parked in a lot and resets its counter
+func (m *MemoryParkingLotService) ReconcileOccupancy(ctx context.Context, parkingLot int) (int, error) {
+	return m.reconcileOccupancy(ctx, parkingLot, func(context.Context, logger.Logger) ([]*model.ParkingTicket, error) {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+
+		var tickets []*model.ParkingTicket
+		for _, ticket := range m.tickets {
+			if ticket.ParkingLot == parkingLot && ticket.Status == model.TicketStatusIn {
+				tickets = append(tickets, copyTicket(ticket))
+			}
+		}
+		return tickets, nil
+	})
+}
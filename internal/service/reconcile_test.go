@@ -0,0 +1,168 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"parking-lot/internal/mocks"
+	"parking-lot/internal/model"
+)
+
+// TestReconcileOccupancy tests that a drifted counter is reset to the spaces of the lot's parked tickets
+func TestReconcileOccupancy(t *testing.T) {
+	ctx := context.Background()
+	parked := func(ticketID string, spaces string) map[string]types.AttributeValue {
+		return map[string]types.AttributeValue{
+			"ticketId":   &types.AttributeValueMemberS{Value: ticketID},
+			"parkingLot": &types.AttributeValueMemberN{Value: "3"},
+			"entryTime":  &types.AttributeValueMemberS{Value: time.Now().Format(time.RFC3339)},
+			"spacesUsed": &types.AttributeValueMemberN{Value: spaces},
+			"status":     &types.AttributeValueMemberS{Value: "in"},
+		}
+	}
+	query := mock.MatchedBy(func(in *dynamodb.QueryInput) bool {
+		return *in.IndexName == parkingLotIndexName
+	})
+	output := &dynamodb.QueryOutput{Items: []map[string]types.AttributeValue{parked("t1", "1"), parked("t2", "2")}}
+	newService := func(mockClient *mocks.DynamoDBClient) (*ParkingLotService, *MemoryOccupancy) {
+		service := newEventsTestService(mockClient)
+		occupancy := NewMemoryOccupancy(func(int) int { return 0 })
+		service.occupancy = occupancy
+		// Seed a counter that drifted away from the three spaces actually in use
+		require.NoError(t, occupancy.Reserve(ctx, 3, 9))
+		return service, occupancy
+	}
+
+	t.Run("Drifted counter", func(t *testing.T) {
+		mockClient := new(mocks.DynamoDBClient)
+		service, occupancy := newService(mockClient)
+		mockClient.On("Query", ctx, query, mock.Anything).Return(output, nil).Once()
+
+		occupied, err := service.ReconcileOccupancy(ctx, 3)
+
+		require.NoError(t, err)
+		assert.Equal(t, 3, occupied)
+		counted, _ := occupancy.Occupied(ctx, 3)
+		assert.Equal(t, 3, counted)
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run("Entry while counting", func(t *testing.T) {
+		mockClient := new(mocks.DynamoDBClient)
+		service, occupancy := newService(mockClient)
+		// An entry reserves a space during the first count, so the counter is not replaced and the lot is counted again
+		mockClient.On("Query", ctx, query, mock.Anything).Run(func(mock.Arguments) {
+			require.NoError(t, occupancy.Reserve(ctx, 3, 1))
+		}).Return(output, nil).Once()
+		mockClient.On("Query", ctx, query, mock.Anything).Return(output, nil).Once()
+
+		occupied, err := service.ReconcileOccupancy(ctx, 3)
+
+		require.NoError(t, err)
+		assert.Equal(t, 3, occupied)
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run("Counter keeps changing", func(t *testing.T) {
+		mockClient := new(mocks.DynamoDBClient)
+		service, occupancy := newService(mockClient)
+		mockClient.On("Query", ctx, query, mock.Anything).Run(func(mock.Arguments) {
+			require.NoError(t, occupancy.Reserve(ctx, 3, 1))
+		}).Return(output, nil).Times(reconcileAttempts)
+
+		_, err := service.ReconcileOccupancy(ctx, 3)
+
+		assert.ErrorIs(t, err, ErrOccupancyChanged)
+		// The entries are kept on top of the drifted count
+		counted, _ := occupancy.Occupied(ctx, 3)
+		assert.Equal(t, 9+reconcileAttempts, counted)
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run("Count stopped at MAX_SCAN_PAGES", func(t *testing.T) {
+		mockClient := new(mocks.DynamoDBClient)
+		service, occupancy := newService(mockClient)
+		service.maxScanPages = 1
+		more := &dynamodb.QueryOutput{Items: output.Items, LastEvaluatedKey: map[string]types.AttributeValue{
+			"ticketId": &types.AttributeValueMemberS{Value: "t2"},
+		}}
+		mockClient.On("Query", ctx, query, mock.Anything).Return(more, nil).Once()
+
+		_, err := service.ReconcileOccupancy(ctx, 3)
+
+		assert.ErrorIs(t, err, ErrScanTruncated)
+		// A partial count leaves the counter alone
+		counted, _ := occupancy.Occupied(ctx, 3)
+		assert.Equal(t, 9, counted)
+		mockClient.AssertExpectations(t)
+	})
+}
+
+// TestReconcileOccupancy_Memory tests that only parked tickets of the lot are counted
+func TestReconcileOccupancy_Memory(t *testing.T) {
+	ctx := context.Background()
+	service, err := NewMemoryParkingLotService(ctx)
+	require.NoError(t, err)
+
+	_, _ = service.CreateTicket(ctx, "ABC-123", 3, 2)
+	_, exited := service.CreateTicket(ctx, "DEF-456", 3, 1)
+	_, _ = service.CreateTicket(ctx, "GHI-789", 4, 1)
+	exited.Status = model.TicketStatusOut
+	require.NoError(t, service.UpdateTicket(ctx, exited))
+	require.NoError(t, service.ReserveSpaces(ctx, 3, 5))
+
+	occupied, err := service.ReconcileOccupancy(ctx, 3)
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, occupied)
+	assert.Equal(t, map[int]int{3: 2}, service.OccupancySnapshot())
+	assert.Equal(t, []int{3}, service.TrackedLots())
+}
+
+// TestReconcileOccupancy_SharedCounter tests that the DynamoDB counter every instance shares is only
+// replaced while it still holds the value read before counting
+func TestReconcileOccupancy_SharedCounter(t *testing.T) {
+	ctx := context.Background()
+	mockClient := new(mocks.DynamoDBClient)
+	service := newEventsTestService(mockClient)
+	service.occupancy = &dynamoOccupancy{s: service}
+
+	counter := func(occupied string) *dynamodb.GetItemOutput {
+		return &dynamodb.GetItemOutput{Item: map[string]types.AttributeValue{
+			"occupied": &types.AttributeValueMemberN{Value: occupied},
+		}}
+	}
+	sets := func(expected string) interface{} {
+		return mock.MatchedBy(func(in *dynamodb.UpdateItemInput) bool {
+			return *in.UpdateExpression == "SET #occupied = :occupied" && *in.ConditionExpression == "#occupied = :expected" &&
+				in.ExpressionAttributeValues[":expected"].(*types.AttributeValueMemberN).Value == expected &&
+				in.ExpressionAttributeValues[":occupied"].(*types.AttributeValueMemberN).Value == "1"
+		})
+	}
+	parked := &dynamodb.QueryOutput{Items: []map[string]types.AttributeValue{{
+		"ticketId":   &types.AttributeValueMemberS{Value: "t1"},
+		"parkingLot": &types.AttributeValueMemberN{Value: "3"},
+		"entryTime":  &types.AttributeValueMemberS{Value: time.Now().Format(time.RFC3339)},
+		"status":     &types.AttributeValueMemberS{Value: "in"},
+	}}}
+
+	// Another instance's entry changes the counter from 9 to 10 while counting, so the lot is counted again
+	mockClient.On("GetItem", ctx, mock.Anything, mock.Anything).Return(counter("9"), nil).Once()
+	mockClient.On("Query", ctx, mock.Anything, mock.Anything).Return(parked, nil).Twice()
+	mockClient.On("UpdateItem", ctx, sets("9"), mock.Anything).Return(nil, &types.ConditionalCheckFailedException{}).Once()
+	mockClient.On("GetItem", ctx, mock.Anything, mock.Anything).Return(counter("10"), nil).Once()
+	mockClient.On("UpdateItem", ctx, sets("10"), mock.Anything).Return(&dynamodb.UpdateItemOutput{}, nil).Once()
+
+	occupied, err := service.ReconcileOccupancy(ctx, 3)
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, occupied)
+	mockClient.AssertExpectations(t)
+}
@@ -0,0 +1,129 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"parking-lot/internal/logger"
+	"parking-lot/internal/model"
+)
+
+var (
+	// ErrNotRefundable is returned when refunding a ticket that has not exited, was not charged
+	// or has already been refunded
+	ErrNotRefundable = errors.New("ticket is not refundable")
+	// ErrRefundWindowClosed is returned when refunding a ticket paid longer than
+	// REFUND_WINDOW_MINUTES ago, or any ticket when refunds are disabled
+	ErrRefundWindowClosed = errors.New("refund window has closed")
+	// ErrInvalidRefundAmount is returned when a refund is not positive or exceeds the charge
+	ErrInvalidRefundAmount = errors.New("refund amount must be positive and at most the charge")
+)
+
+// TicketRefunder is implemented by services that can refund a charge shortly after it was paid
+type TicketRefunder interface {
+	// RefundTicket refunds amount of an exited ticket's charge, or all of it when amount is zero,
+	// and records the refund on the ticket and in the event log. The refund window runs from the
+	// kiosk payment when the exit honored a kiosk quote, and from the exit otherwise.
+	RefundTicket(ctx context.Context, ticket *model.ParkingTicket, amount float32) error
+}
+
+// RefundTicket refunds part or all of the charge of a ticket stored in DynamoDB
+func (s *ParkingLotService) RefundTicket(ctx context.Context, ticket *model.ParkingTicket, amount float32) error {
+	return s.refundTicket(ctx, ticket, amount, s.storeRefund)
+}
+
+// refundTicket checks that a ticket can be refunded, deducts the refund from its charge and stores
+// it through store. The ticket is left unchanged when the refund is rejected or storing fails.
+func (s *ParkingLotService) refundTicket(ctx context.Context, ticket *model.ParkingTicket, amount float32,
+	store func(context.Context, *model.ParkingTicket) error) error {
+	log := s.log.WithContext(ctx).WithFields(
+		logger.Field{Key: "ticket_id", Value: ticket.TicketID},
+		logger.Field{Key: "charge", Value: ticket.Charge},
+	)
+
+	if ticket.Status != model.TicketStatusOut || ticket.ExitTime == nil || ticket.Charge <= 0 || ticket.RefundTime != nil {
+		log.Warn("Ticket is not refundable")
+		return ErrNotRefundable
+	}
+	now := s.now()
+	paid := ticket.PaymentTime(s.exitWindow)
+	if s.refundWindow <= 0 || now.Sub(*paid) > s.refundWindow {
+		log.Warn("Refund requested after the refund window", logger.Field{Key: "payment_time", Value: paid.Format(time.RFC3339)})
+		return ErrRefundWindowClosed
+	}
+	if amount == 0 {
+		amount = ticket.Charge
+	}
+	if amount < 0 || amount > ticket.Charge {
+		log.Warn("Invalid refund amount", logger.Field{Key: "amount", Value: amount})
+		return ErrInvalidRefundAmount
+	}
+
+	previous := *ticket
+	ticket.Charge -= amount
+	ticket.RefundAmount = amount
+	ticket.RefundTime = &now
+	if err := store(ctx, ticket); err != nil {
+		*ticket = previous
+		return err
+	}
+
+	log.Info("Refunded charge", logger.Field{Key: "amount", Value: amount})
+	s.recordEvent(ctx, model.TicketEvent{
+		TicketID: ticket.TicketID,
+		Type:     model.EventTypeRefund,
+		Time:     now,
+		Charge:   amount,
+	})
+	return nil
+}
+
+// storeRefund writes the refund of a ticket to DynamoDB. Only the refund attributes are updated,
+// so no exit is recorded again, and only while the stored ticket has not been refunded yet.
+func (s *ParkingLotService) storeRefund(ctx context.Context, ticket *model.ParkingTicket) error {
+	log := s.log.WithContext(ctx).WithFields(logger.Field{Key: "ticket_id", Value: ticket.TicketID})
+
+	item, err := s.marshalMap(ticket)
+	if err != nil {
+		log.Error("Failed to marshal ticket for refund", logger.Field{Key: "error", Value: err.Error()})
+		return fmt.Errorf("failed to marshal ticket for refund: %w", err)
+	}
+	// A full refund leaves no charge, which the marshaled item omits
+	charge, ok := item["charge"]
+	if !ok {
+		charge = &types.AttributeValueMemberN{Value: "0"}
+	}
+
+	_, err = s.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName:           aws.String(s.table(ctx)),
+		Key:                 s.itemKey(item),
+		UpdateExpression:    aws.String("SET charge = :charge, refundAmount = :amount, refundTime = :time"),
+		ConditionExpression: aws.String("attribute_not_exists(refundTime)"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":charge": charge,
+			":amount": item["refundAmount"],
+			":time":   item["refundTime"],
+		},
+	})
+	var conflict *types.ConditionalCheckFailedException
+	if errors.As(err, &conflict) {
+		log.Warn("Ticket was already refunded")
+		return ErrNotRefundable
+	}
+	if err != nil {
+		log.Error("Failed to store refund in DynamoDB", logger.Field{Key: "error", Value: err.Error()})
+		return fmt.Errorf("failed to store refund in DynamoDB: %w", err)
+	}
+	return nil
+}
+
+// RefundTicket refunds part or all of the charge of a ticket held in memory
+func (m *MemoryParkingLotService) RefundTicket(ctx context.Context, ticket *model.ParkingTicket, amount float32) error {
+	return m.refundTicket(ctx, ticket, amount, m.UpdateTicket)
+}
@@ -0,0 +1,173 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"parking-lot/internal/mocks"
+	"parking-lot/internal/model"
+)
+
+// TestRefundTicket tests that a refund within the window deducts the charge with a conditional update
+// and appends an event that replays to the same ticket
+func TestRefundTicket(t *testing.T) {
+	ctx := context.Background()
+	mockClient := new(mocks.DynamoDBClient)
+	service := newEventsTestService(mockClient)
+	exitTime := time.Date(2025, 3, 1, 11, 0, 0, 0, time.UTC)
+	service.clock = func() time.Time { return exitTime.Add(10 * time.Minute) }
+	service.refundWindow = 15 * time.Minute
+	ticket := &model.ParkingTicket{TicketID: "t1", Plate: "ABC-123", Status: model.TicketStatusOut, ExitTime: &exitTime, Charge: 7.5}
+
+	mockClient.On("UpdateItem", ctx, mock.MatchedBy(func(in *dynamodb.UpdateItemInput) bool {
+		return in.Key["ticketId"].(*types.AttributeValueMemberS).Value == "t1" &&
+			*in.ConditionExpression == "attribute_not_exists(refundTime)" &&
+			in.ExpressionAttributeValues[":charge"].(*types.AttributeValueMemberN).Value == "5" &&
+			in.ExpressionAttributeValues[":amount"].(*types.AttributeValueMemberN).Value == "2.5"
+	}), mock.Anything).Return(&dynamodb.UpdateItemOutput{}, nil).Once()
+	var recorded model.TicketEvent
+	mockClient.On("PutItem", ctx, mock.MatchedBy(func(in *dynamodb.PutItemInput) bool {
+		return *in.TableName == "testEvents"
+	}), mock.Anything).Run(func(args mock.Arguments) {
+		assert.NoError(t, attributevalue.UnmarshalMap(args.Get(1).(*dynamodb.PutItemInput).Item, &recorded))
+	}).Return(&dynamodb.PutItemOutput{}, nil).Once()
+
+	err := service.RefundTicket(ctx, ticket, 2.5)
+
+	assert.NoError(t, err)
+	assert.Equal(t, float32(5), ticket.Charge)
+	assert.Equal(t, float32(2.5), ticket.RefundAmount)
+	assert.Equal(t, model.EventTypeRefund, recorded.Type)
+
+	replayed := model.ParkingTicket{TicketID: "t1", Status: model.TicketStatusOut, Charge: 7.5}
+	recorded.Apply(&replayed)
+	assert.Equal(t, float32(5), replayed.Charge)
+	assert.Equal(t, float32(2.5), replayed.RefundAmount)
+	mockClient.AssertExpectations(t)
+}
+
+// TestRefundTicket_Rejected tests the refunds that are refused without touching DynamoDB
+func TestRefundTicket_Rejected(t *testing.T) {
+	ctx := context.Background()
+	exitTime := time.Date(2025, 3, 1, 11, 0, 0, 0, time.UTC)
+	exited := func() *model.ParkingTicket {
+		exit := exitTime
+		return &model.ParkingTicket{TicketID: "t1", Status: model.TicketStatusOut, ExitTime: &exit, Charge: 7.5}
+	}
+	refundTime := exitTime.Add(time.Minute)
+
+	tests := []struct {
+		name   string
+		window time.Duration
+		now    time.Time
+		ticket *model.ParkingTicket
+		amount float32
+		want   error
+	}{
+		{"Past the window", 15 * time.Minute, exitTime.Add(16 * time.Minute), exited(), 0, ErrRefundWindowClosed},
+		{"Refunds disabled", 0, exitTime.Add(time.Minute), exited(), 0, ErrRefundWindowClosed},
+		{"Still parked", 15 * time.Minute, exitTime, &model.ParkingTicket{TicketID: "t1", Status: model.TicketStatusIn}, 0, ErrNotRefundable},
+		{"Already refunded", 15 * time.Minute, exitTime.Add(time.Minute), func() *model.ParkingTicket {
+			ticket := exited()
+			ticket.RefundTime = &refundTime
+			return ticket
+		}(), 0, ErrNotRefundable},
+		{"More than the charge", 15 * time.Minute, exitTime.Add(time.Minute), exited(), 10, ErrInvalidRefundAmount},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := new(mocks.DynamoDBClient)
+			service := newEventsTestService(mockClient)
+			service.refundWindow = tt.window
+			service.clock = func() time.Time { return tt.now }
+			charge := tt.ticket.Charge
+
+			err := service.RefundTicket(ctx, tt.ticket, tt.amount)
+
+			assert.ErrorIs(t, err, tt.want)
+			assert.Equal(t, charge, tt.ticket.Charge)
+			mockClient.AssertNotCalled(t, "UpdateItem", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+// TestRefundTicket_KioskPayment tests that the refund window runs from the kiosk payment when the exit
+// honored a kiosk quote, and from the exit when the quote was stale
+func TestRefundTicket_KioskPayment(t *testing.T) {
+	ctx := context.Background()
+	quoteTime := time.Date(2025, 3, 1, 11, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		exitTime time.Time
+		want     error
+	}{
+		// Paid 20 minutes ago at the kiosk, though the vehicle left only 10 minutes ago
+		{"Past the window since payment", quoteTime.Add(10 * time.Minute), ErrRefundWindowClosed},
+		// The quote was stale at exit, so the driver paid at the barrier 8 minutes ago
+		{"Paid at exit", quoteTime.Add(12 * time.Minute), nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := new(mocks.DynamoDBClient)
+			service := newEventsTestService(mockClient)
+			service.eventsTableName = ""
+			service.exitWindow = 10 * time.Minute
+			service.refundWindow = 15 * time.Minute
+			service.clock = func() time.Time { return quoteTime.Add(20 * time.Minute) }
+			exitTime := tt.exitTime
+			ticket := &model.ParkingTicket{
+				TicketID: "t1", Status: model.TicketStatusOut, ExitTime: &exitTime, Charge: 7.5,
+				QuoteCharge: 7.5, QuoteTime: &quoteTime,
+			}
+			mockClient.On("UpdateItem", ctx, mock.Anything, mock.Anything).Return(&dynamodb.UpdateItemOutput{}, nil).Maybe()
+
+			err := service.RefundTicket(ctx, ticket, 0)
+
+			if tt.want != nil {
+				assert.ErrorIs(t, err, tt.want)
+				mockClient.AssertNotCalled(t, "UpdateItem", mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, float32(7.5), ticket.RefundAmount)
+		})
+	}
+}
+
+// TestRefundTicket_Reopened tests that reopening clears the previous exit's refund, so the ticket's
+// next exit can be refunded and no longer shows it
+func TestRefundTicket_Reopened(t *testing.T) {
+	ctx := context.Background()
+	mockClient := new(mocks.DynamoDBClient)
+	service := newEventsTestService(mockClient)
+	service.eventsTableName = ""
+	service.refundWindow = 15 * time.Minute
+	firstExit := time.Date(2025, 3, 1, 11, 0, 0, 0, time.UTC)
+	service.clock = func() time.Time { return firstExit.Add(time.Minute) }
+	ticket := &model.ParkingTicket{TicketID: "t1", Status: model.TicketStatusOut, ExitTime: &firstExit, Charge: 7.5}
+	mockClient.On("UpdateItem", ctx, mock.Anything, mock.Anything).Return(&dynamodb.UpdateItemOutput{}, nil).Twice()
+
+	assert.NoError(t, service.RefundTicket(ctx, ticket, 2.5))
+
+	ticket.Reopen()
+	assert.Zero(t, ticket.RefundAmount)
+	assert.Nil(t, ticket.RefundTime)
+	assert.Equal(t, float32(7.5), ticket.PriorCharge)
+
+	secondExit := firstExit.Add(time.Hour)
+	ticket.Status, ticket.ExitTime, ticket.Charge = model.TicketStatusOut, &secondExit, 5
+	service.clock = func() time.Time { return secondExit.Add(time.Minute) }
+
+	assert.NoError(t, service.RefundTicket(ctx, ticket, 1))
+	assert.Equal(t, float32(4), ticket.Charge)
+	assert.Equal(t, float32(1), ticket.RefundAmount)
+	mockClient.AssertExpectations(t)
+}
@@ -0,0 +1,178 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"parking-lot/internal/logger"
+	"parking-lot/internal/model"
+)
+
+// ErrNoActiveTicket is returned when reissuing a ticket for a plate that is not parked in any lot
+var ErrNoActiveTicket = errors.New("no active ticket for plate")
+
+// TicketReissuer is implemented by services that can replace the ID of a lost ticket
+type TicketReissuer interface {
+	// ReissueTicket moves the active ticket of a plate to a new ticket ID, keeping its entry time
+	// and session, so the lost ID no longer resolves. It returns the reissued ticket and the ID it replaced.
+	ReissueTicket(ctx context.Context, plate string) (*model.ParkingTicket, string, error)
+}
+
+// ReissueTicket moves the newest active ticket of a plate to a new ticket ID in DynamoDB.
+// Tables keyed by ticket ID store the ticket under the new key and delete the old item in one
+// transaction; tables keyed by plate keep the item and only change its ticketId attribute. Either write
+// fails with ErrTicketConflict when the ticket exited or, with versioned updates, changed since it was read.
+// The event log records an entry under the new ID and the deletion of the old one.
+func (s *ParkingLotService) ReissueTicket(ctx context.Context, plate string) (*model.ParkingTicket, string, error) {
+	log := s.log.WithContext(ctx).WithFields(logger.Plate(plate))
+	log.Info("Reissuing ticket")
+
+	ticket, err := s.findActivePlateTicket(ctx, plate)
+	if err != nil {
+		log.Error("Failed to look up active ticket", logger.Field{Key: "error", Value: err.Error()})
+		return nil, "", err
+	}
+	if ticket == nil {
+		log.Info("No active ticket found")
+		return nil, "", ErrNoActiveTicket
+	}
+
+	ticketID, err := s.newTicketID()
+	if err != nil {
+		log.Error("Failed to generate ticket ID", logger.Field{Key: "error", Value: err.Error()})
+		return nil, "", fmt.Errorf("failed to generate ticket ID: %w", err)
+	}
+	previous := ticket.TicketID
+	ticket.TicketID = ticketID.String()
+	log = log.WithFields(
+		logger.Field{Key: "previous_ticket_id", Value: previous},
+		logger.Field{Key: "ticket_id", Value: ticket.TicketID},
+	)
+
+	// Like UpdateTicket, versioned updates only reissue the ticket while it is at the version it was read at
+	expected := ticket.Version
+	if s.versionedUpdates {
+		ticket.Version++
+	}
+
+	item, err := s.marshalMap(ticket)
+	if err != nil {
+		ticket.TicketID, ticket.Version = previous, expected
+		log.Error("Failed to marshal reissued ticket", logger.Field{Key: "error", Value: err.Error()})
+		return nil, "", fmt.Errorf("failed to marshal ticket: %w", err)
+	}
+
+	// The old ticket is only replaced while it is still parked, so an exit racing the reissue is not undone
+	condition := "#status = :in"
+	names := map[string]string{"#status": "status"}
+	values := map[string]types.AttributeValue{
+		":in": &types.AttributeValueMemberS{Value: string(model.TicketStatusIn)},
+	}
+	if s.versionedUpdates {
+		versioned, versionNames, versionValues := versionCondition(expected)
+		condition += " AND " + *versioned
+		maps.Copy(names, versionNames)
+		maps.Copy(values, versionValues)
+	}
+
+	if s.compositeKey {
+		// The item keeps its plate and entry time key, so overwrite it while it still holds the old ID
+		values[":previous"] = &types.AttributeValueMemberS{Value: previous}
+		_, err = s.client.PutItem(ctx, &dynamodb.PutItemInput{
+			TableName:                 aws.String(s.table(ctx)),
+			Item:                      item,
+			ConditionExpression:       aws.String("ticketId = :previous AND " + condition),
+			ExpressionAttributeNames:  names,
+			ExpressionAttributeValues: values,
+		})
+	} else {
+		// Store the new item and delete the old one together, so exactly one of the IDs resolves
+		_, err = s.client.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{
+			TransactItems: []types.TransactWriteItem{
+				{Put: &types.Put{
+					TableName:           aws.String(s.table(ctx)),
+					Item:                item,
+					ConditionExpression: aws.String("attribute_not_exists(ticketId)"),
+				}},
+				{Delete: &types.Delete{
+					TableName: aws.String(s.table(ctx)),
+					Key: map[string]types.AttributeValue{
+						"ticketId": &types.AttributeValueMemberS{Value: previous},
+					},
+					ConditionExpression:       aws.String(condition),
+					ExpressionAttributeNames:  names,
+					ExpressionAttributeValues: values,
+				}},
+			},
+		})
+	}
+	var conflict *types.ConditionalCheckFailedException
+	var canceled *types.TransactionCanceledException
+	if errors.As(err, &conflict) || errors.As(err, &canceled) {
+		ticket.TicketID, ticket.Version = previous, expected
+		log.Warn("Ticket exited or was updated concurrently", logger.Field{Key: "version", Value: expected})
+		return nil, "", fmt.Errorf("failed to reissue ticket: %w", ErrTicketConflict)
+	}
+	if err != nil {
+		ticket.TicketID, ticket.Version = previous, expected
+		log.Error("Failed to store reissued ticket", logger.Field{Key: "error", Value: err.Error()})
+		return nil, "", fmt.Errorf("failed to store reissued ticket: %w", err)
+	}
+
+	// Rebuilds find the session under its new ID and skip the one it replaced
+	s.recordEvent(ctx, model.EntryEvent(ticket))
+	s.recordEvent(ctx, model.TicketEvent{
+		TicketID:   previous,
+		Type:       model.EventTypeDeletion,
+		Time:       s.now(),
+		Plate:      ticket.Plate,
+		ParkingLot: ticket.ParkingLot,
+		SpacesUsed: ticket.SpacesUsed,
+	})
+
+	log.Info("Reissued ticket")
+	return ticket, previous, nil
+}
+
+// findActivePlateTicket returns the newest ticket of a plate that is still in any lot
+func (s *ParkingLotService) findActivePlateTicket(ctx context.Context, plate string) (*model.ParkingTicket, error) {
+	tickets, err := s.queryActivePlateTickets(ctx, plate, 1)
+	if err != nil || len(tickets) == 0 {
+		return nil, err
+	}
+	return tickets[0], nil
+}
+
+// ReissueTicket moves the newest active ticket of a plate held in memory to a new ticket ID
+func (m *MemoryParkingLotService) ReissueTicket(ctx context.Context, plate string) (*model.ParkingTicket, string, error) {
+	ticketID, err := m.newTicketID()
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to generate ticket ID: %w", err)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var active *model.ParkingTicket
+	for _, ticket := range m.tickets {
+		if ticket.Plate == plate && ticket.Status == model.TicketStatusIn &&
+			(active == nil || ticket.EntryTime.After(active.EntryTime)) {
+			active = ticket
+		}
+	}
+	if active == nil {
+		return nil, "", ErrNoActiveTicket
+	}
+
+	previous := active.TicketID
+	delete(m.tickets, previous)
+	active.TicketID = ticketID.String()
+	m.tickets[active.TicketID] = active
+	return copyTicket(active), previous, nil
+}
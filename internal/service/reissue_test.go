@@ -0,0 +1,227 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"parking-lot/internal/logger"
+	"parking-lot/internal/mocks"
+	"parking-lot/internal/model"
+)
+
+// TestReissueTicket tests that the active ticket is stored under a new ID and the old item deleted
+// in one transaction, and that the event log moves the session to the new ID
+func TestReissueTicket(t *testing.T) {
+	ctx := context.Background()
+	mockClient := new(mocks.DynamoDBClient)
+	service := &ParkingLotService{
+		ctx:             ctx,
+		client:          mockClient,
+		tableName:       "testTable",
+		eventsTableName: "testEvents",
+		log:             logger.NewLogger(),
+		marshalMap:      attributevalue.MarshalMap,
+		unmarshalMap:    attributevalue.UnmarshalMap,
+	}
+	entryTime := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+	active := &model.ParkingTicket{
+		TicketID: "old-id", Plate: "ABC-123", ParkingLot: 2, EntryTime: entryTime,
+		Status: model.TicketStatusIn, SessionID: "session",
+	}
+	item, err := attributevalue.MarshalMap(active)
+	require.NoError(t, err)
+
+	mockClient.On("Query", ctx, mock.MatchedBy(func(in *dynamodb.QueryInput) bool {
+		return *in.IndexName == plateEntryTimeIndexName && !*in.ScanIndexForward &&
+			in.ExpressionAttributeValues[":status"].(*types.AttributeValueMemberS).Value == string(model.TicketStatusIn)
+	}), mock.Anything).Return(&dynamodb.QueryOutput{Items: []map[string]types.AttributeValue{item}}, nil).Once()
+	mockClient.On("TransactWriteItems", ctx, mock.MatchedBy(func(in *dynamodb.TransactWriteItemsInput) bool {
+		if len(in.TransactItems) != 2 {
+			return false
+		}
+		put, del := in.TransactItems[0].Put, in.TransactItems[1].Delete
+		return put != nil && del != nil &&
+			put.Item["ticketId"].(*types.AttributeValueMemberS).Value != "old-id" &&
+			*put.ConditionExpression == "attribute_not_exists(ticketId)" &&
+			put.Item["sessionId"].(*types.AttributeValueMemberS).Value == "session" &&
+			del.Key["ticketId"].(*types.AttributeValueMemberS).Value == "old-id"
+	}), mock.Anything).Return(&dynamodb.TransactWriteItemsOutput{}, nil).Once()
+	var events []model.TicketEvent
+	mockClient.On("PutItem", ctx, mock.MatchedBy(func(in *dynamodb.PutItemInput) bool {
+		return *in.TableName == "testEvents"
+	}), mock.Anything).Run(func(args mock.Arguments) {
+		var event model.TicketEvent
+		require.NoError(t, attributevalue.UnmarshalMap(args.Get(1).(*dynamodb.PutItemInput).Item, &event))
+		events = append(events, event)
+	}).Return(&dynamodb.PutItemOutput{}, nil).Twice()
+
+	ticket, previous, err := service.ReissueTicket(ctx, "ABC-123")
+
+	require.NoError(t, err)
+	assert.Equal(t, "old-id", previous)
+	assert.NotEqual(t, "old-id", ticket.TicketID)
+	assert.True(t, entryTime.Equal(ticket.EntryTime))
+	assert.Equal(t, "session", ticket.SessionID)
+	require.Len(t, events, 2)
+	assert.Equal(t, ticket.TicketID, events[0].TicketID)
+	assert.Equal(t, model.EventTypeEntry, events[0].Type)
+	assert.True(t, entryTime.Equal(events[0].Time))
+	assert.Equal(t, "session", events[0].SessionID)
+	assert.Equal(t, "old-id", events[1].TicketID)
+	assert.Equal(t, model.EventTypeDeletion, events[1].Type)
+	mockClient.AssertNotCalled(t, "DeleteItem", mock.Anything, mock.Anything, mock.Anything)
+	mockClient.AssertExpectations(t)
+}
+
+// TestReissueTicket_TransactionFails tests that a failed transaction leaves the ticket under its old ID
+// and records no events
+func TestReissueTicket_TransactionFails(t *testing.T) {
+	ctx := context.Background()
+	mockClient := new(mocks.DynamoDBClient)
+	service := &ParkingLotService{
+		ctx:             ctx,
+		client:          mockClient,
+		tableName:       "testTable",
+		eventsTableName: "testEvents",
+		log:             logger.NewLogger(),
+		marshalMap:      attributevalue.MarshalMap,
+		unmarshalMap:    attributevalue.UnmarshalMap,
+	}
+	item, err := attributevalue.MarshalMap(&model.ParkingTicket{
+		TicketID: "old-id", Plate: "ABC-123", ParkingLot: 2, EntryTime: time.Now(), Status: model.TicketStatusIn,
+	})
+	require.NoError(t, err)
+
+	mockClient.On("Query", ctx, mock.Anything, mock.Anything).
+		Return(&dynamodb.QueryOutput{Items: []map[string]types.AttributeValue{item}}, nil).Once()
+	mockClient.On("TransactWriteItems", ctx, mock.Anything, mock.Anything).
+		Return(nil, &types.TransactionCanceledException{Message: aws.String("ConditionalCheckFailed")}).Once()
+
+	_, _, err = service.ReissueTicket(ctx, "ABC-123")
+
+	assert.ErrorIs(t, err, ErrTicketConflict)
+	mockClient.AssertNotCalled(t, "PutItem", mock.Anything, mock.Anything, mock.Anything)
+	mockClient.AssertNotCalled(t, "DeleteItem", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestReissueTicket_CompositeKey tests that a table keyed by plate only overwrites the ticket while it is
+// still parked at the version it was read at, and that the entry event carries what earlier exits billed
+func TestReissueTicket_CompositeKey(t *testing.T) {
+	ctx := context.Background()
+	mockClient := new(mocks.DynamoDBClient)
+	service := &ParkingLotService{
+		ctx:              ctx,
+		client:           mockClient,
+		tableName:        "testTable",
+		eventsTableName:  "testEvents",
+		log:              logger.NewLogger(),
+		marshalMap:       attributevalue.MarshalMap,
+		unmarshalMap:     attributevalue.UnmarshalMap,
+		compositeKey:     true,
+		versionedUpdates: true,
+	}
+	item, err := attributevalue.MarshalMap(&model.ParkingTicket{
+		TicketID: "old-id", Plate: "ABC-123", ParkingLot: 2, EntryTime: time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC),
+		Status: model.TicketStatusIn, PriorCharge: 4.5, Version: 2,
+	})
+	require.NoError(t, err)
+
+	mockClient.On("Query", ctx, mock.Anything, mock.Anything).
+		Return(&dynamodb.QueryOutput{Items: []map[string]types.AttributeValue{item}}, nil).Once()
+	mockClient.On("PutItem", ctx, mock.MatchedBy(func(in *dynamodb.PutItemInput) bool {
+		return *in.TableName == "testTable" &&
+			*in.ConditionExpression == "ticketId = :previous AND #status = :in AND #version = :version" &&
+			in.ExpressionAttributeNames["#status"] == "status" &&
+			in.ExpressionAttributeNames["#version"] == "version" &&
+			in.ExpressionAttributeValues[":previous"].(*types.AttributeValueMemberS).Value == "old-id" &&
+			in.ExpressionAttributeValues[":in"].(*types.AttributeValueMemberS).Value == string(model.TicketStatusIn) &&
+			in.ExpressionAttributeValues[":version"].(*types.AttributeValueMemberN).Value == "2" &&
+			in.Item["version"].(*types.AttributeValueMemberN).Value == "3"
+	}), mock.Anything).Return(&dynamodb.PutItemOutput{}, nil).Once()
+	var events []model.TicketEvent
+	mockClient.On("PutItem", ctx, mock.MatchedBy(func(in *dynamodb.PutItemInput) bool {
+		return *in.TableName == "testEvents"
+	}), mock.Anything).Run(func(args mock.Arguments) {
+		var event model.TicketEvent
+		require.NoError(t, attributevalue.UnmarshalMap(args.Get(1).(*dynamodb.PutItemInput).Item, &event))
+		events = append(events, event)
+	}).Return(&dynamodb.PutItemOutput{}, nil).Twice()
+
+	ticket, _, err := service.ReissueTicket(ctx, "ABC-123")
+
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	var rebuilt model.ParkingTicket
+	events[0].Apply(&rebuilt)
+	assert.Equal(t, float32(4.5), rebuilt.PriorCharge)
+	assert.Equal(t, ticket.TicketID, rebuilt.TicketID)
+	assert.Equal(t, 3, rebuilt.Version)
+	mockClient.AssertExpectations(t)
+
+	t.Run("Exited concurrently", func(t *testing.T) {
+		mockClient.ExpectedCalls = nil
+		mockClient.Calls = nil
+		mockClient.On("Query", ctx, mock.Anything, mock.Anything).
+			Return(&dynamodb.QueryOutput{Items: []map[string]types.AttributeValue{item}}, nil).Once()
+		mockClient.On("PutItem", ctx, mock.Anything, mock.Anything).
+			Return(nil, &types.ConditionalCheckFailedException{Message: aws.String("The conditional request failed")}).Once()
+
+		_, _, err := service.ReissueTicket(ctx, "ABC-123")
+
+		assert.ErrorIs(t, err, ErrTicketConflict)
+		mockClient.AssertNumberOfCalls(t, "PutItem", 1)
+	})
+}
+
+// TestReissueTicket_NoActiveTicket tests that a plate that is not parked has nothing to reissue
+func TestReissueTicket_NoActiveTicket(t *testing.T) {
+	ctx := context.Background()
+	mockClient := new(mocks.DynamoDBClient)
+	service := &ParkingLotService{
+		ctx:          ctx,
+		client:       mockClient,
+		tableName:    "testTable",
+		log:          logger.NewLogger(),
+		marshalMap:   attributevalue.MarshalMap,
+		unmarshalMap: attributevalue.UnmarshalMap,
+	}
+	mockClient.On("Query", ctx, mock.Anything, mock.Anything).Return(&dynamodb.QueryOutput{}, nil).Once()
+
+	_, _, err := service.ReissueTicket(ctx, "ABC-123")
+
+	assert.ErrorIs(t, err, ErrNoActiveTicket)
+	mockClient.AssertNotCalled(t, "PutItem", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestMemoryParkingLotService_ReissueTicket tests that the newest active ticket of a plate gets a new ID
+func TestMemoryParkingLotService_ReissueTicket(t *testing.T) {
+	ctx := context.Background()
+	service, err := NewMemoryParkingLotService(ctx)
+	require.NoError(t, err)
+
+	ticketID, ticket := service.CreateTicket(ctx, "ABC-123", 1, 1)
+
+	reissued, previous, err := service.ReissueTicket(ctx, "ABC-123")
+	require.NoError(t, err)
+	assert.Equal(t, ticketID.String(), previous)
+	assert.NotEqual(t, previous, reissued.TicketID)
+	assert.Equal(t, ticket.SessionID, reissued.SessionID)
+	assert.True(t, ticket.EntryTime.Equal(reissued.EntryTime))
+
+	_, exists := service.GetTicket(ctx, previous)
+	assert.False(t, exists)
+	_, exists = service.GetTicket(ctx, reissued.TicketID)
+	assert.True(t, exists)
+
+	_, _, err = service.ReissueTicket(ctx, "XYZ-789")
+	assert.ErrorIs(t, err, ErrNoActiveTicket)
+}
@@ -0,0 +1,127 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"parking-lot/internal/env"
+	"parking-lot/internal/logger"
+	"parking-lot/internal/model"
+)
+
+// ErrDestructiveAdminDisabled is returned by destructive admin operations unless ALLOW_DESTRUCTIVE_ADMIN=true
+var ErrDestructiveAdminDisabled = errors.New("destructive admin operations are disabled")
+
+// destructiveAdminEnabled reports whether operations that wipe data may run.
+// Like table creation, it is only honoured outside of Lambda so a real deployment can never be wiped.
+func destructiveAdminEnabled() bool {
+	return os.Getenv("ALLOW_DESTRUCTIVE_ADMIN") == "true" && !env.IsLambda()
+}
+
+// ResetLot deletes every ticket in a lot and frees the spaces held by vehicles still parked there.
+// It is meant for cleaning test environments and fails with ErrDestructiveAdminDisabled otherwise.
+func (s *ParkingLotService) ResetLot(ctx context.Context, parkingLot int) error {
+	log := s.log.WithContext(ctx).WithFields(logger.Field{Key: "parking_lot", Value: parkingLot})
+
+	if !destructiveAdminEnabled() {
+		log.Warn("Refusing to reset parking lot, destructive admin operations are disabled")
+		return ErrDestructiveAdminDisabled
+	}
+	log.Warn("Resetting parking lot")
+
+	input := &dynamodb.QueryInput{
+		TableName:              aws.String(s.table(ctx)),
+		IndexName:              aws.String(parkingLotIndexName),
+		KeyConditionExpression: aws.String("parkingLot = :parkingLot"),
+		ProjectionExpression:   aws.String("ticketId, plate, entryTime, #status, spacesUsed, spotNumber"),
+		ExpressionAttributeNames: map[string]string{
+			"#status": "status", // status is a reserved word
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":parkingLot": &types.AttributeValueMemberN{Value: strconv.Itoa(parkingLot)},
+		},
+	}
+
+	// MAX_SCAN_PAGES does not apply: stopping early would leave the lot half reset. Each page is
+	// deleted before the next is read, so memory stays bounded by a page.
+	deleted, spaces := 0, 0
+	for {
+		result, err := s.client.Query(ctx, input)
+		if err != nil {
+			log.Error("Failed to query parking lot index", logger.Field{Key: "error", Value: err.Error()})
+			return fmt.Errorf("failed to query parking lot index: %w", err)
+		}
+
+		var keys []map[string]types.AttributeValue
+		for _, item := range result.Items {
+			ticket := &model.ParkingTicket{}
+			if err := s.unmarshalMap(item, ticket); err != nil {
+				return fmt.Errorf("failed to unmarshal ticket: %w", err)
+			}
+			keys = append(keys, s.itemKey(item))
+			if ticket.Status == model.TicketStatusIn {
+				spaces += ticket.Spaces()
+				s.ReleaseSpot(ctx, ticket)
+			}
+		}
+
+		if err := s.batchDelete(ctx, keys); err != nil {
+			log.Error("Failed to delete tickets", logger.Field{Key: "error", Value: err.Error()})
+			return err
+		}
+		deleted += len(keys)
+
+		if len(result.LastEvaluatedKey) == 0 {
+			break
+		}
+		input.ExclusiveStartKey = result.LastEvaluatedKey
+	}
+
+	if spaces > 0 {
+		s.ReleaseSpaces(ctx, parkingLot, spaces)
+	}
+
+	log.Warn("Parking lot reset", logger.Field{Key: "deleted", Value: deleted})
+	return nil
+}
+
+// batchDelete deletes the items with the given keys from the tickets table in chunks of batchWriteLimit,
+// resubmitting items DynamoDB reports as unprocessed
+func (s *ParkingLotService) batchDelete(ctx context.Context, keys []map[string]types.AttributeValue) error {
+	tableName := s.table(ctx)
+	for start := 0; start < len(keys); start += batchWriteLimit {
+		end := min(start+batchWriteLimit, len(keys))
+
+		requests := make([]types.WriteRequest, 0, end-start)
+		for _, key := range keys[start:end] {
+			requests = append(requests, types.WriteRequest{DeleteRequest: &types.DeleteRequest{Key: key}})
+		}
+		pending := map[string][]types.WriteRequest{tableName: requests}
+
+		backoff := unprocessedBackoff
+		for attempt := 0; len(pending) > 0; attempt++ {
+			if attempt > maxUnprocessedRetries {
+				return fmt.Errorf("failed to delete tickets: %d items still unprocessed", len(pending[tableName]))
+			}
+			if attempt > 0 {
+				// Unprocessed items usually mean throttling, so give the table a moment
+				time.Sleep(backoff)
+				backoff *= 2
+			}
+			result, err := s.client.BatchWriteItem(ctx, &dynamodb.BatchWriteItemInput{RequestItems: pending})
+			if err != nil {
+				return fmt.Errorf("failed to delete tickets: %w", err)
+			}
+			pending = result.UnprocessedItems
+		}
+	}
+	return nil
+}
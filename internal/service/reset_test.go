@@ -0,0 +1,162 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"parking-lot/internal/logger"
+	"parking-lot/internal/mocks"
+	"parking-lot/internal/model"
+)
+
+// TestResetLot_Disabled tests that resetting a lot is refused unless explicitly allowed outside of Lambda
+func TestResetLot_Disabled(t *testing.T) {
+	tests := []struct {
+		name      string
+		allow     string
+		execution string
+	}{
+		{name: "Flag off", allow: "", execution: ""},
+		{name: "Running in Lambda", allow: "true", execution: "AWS_Lambda_provided.al2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("ALLOW_DESTRUCTIVE_ADMIN", tt.allow)
+			t.Setenv("AWS_EXECUTION_ENV", tt.execution)
+			mockClient := new(mocks.DynamoDBClient)
+			service := &ParkingLotService{client: mockClient, tableName: "testTable", log: logger.NewLogger()}
+
+			err := service.ResetLot(context.Background(), 1)
+
+			assert.ErrorIs(t, err, ErrDestructiveAdminDisabled)
+			mockClient.AssertNotCalled(t, "Query", mock.Anything, mock.Anything, mock.Anything)
+			mockClient.AssertNotCalled(t, "BatchWriteItem", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+// TestResetLot tests that every ticket in the lot is deleted across query pages and batch chunks
+func TestResetLot(t *testing.T) {
+	t.Setenv("ALLOW_DESTRUCTIVE_ADMIN", "true")
+	t.Setenv("AWS_EXECUTION_ENV", "")
+	defer func(backoff time.Duration) { unprocessedBackoff = backoff }(unprocessedBackoff)
+	unprocessedBackoff = time.Millisecond
+
+	ctx := context.Background()
+	mockClient := new(mocks.DynamoDBClient)
+	service := &ParkingLotService{
+		client:       mockClient,
+		tableName:    "testTable",
+		log:          logger.NewLogger(),
+		unmarshalMap: attributevalue.UnmarshalMap,
+		occupancy:    NewMemoryOccupancy(func(int) int { return 0 }),
+	}
+	assert.NoError(t, service.occupancy.Reserve(ctx, 7, 5))
+
+	// 30 tickets over two query pages; the 3 parked vehicles hold 1, 1 and 2 spaces
+	items := make([]map[string]types.AttributeValue, 30)
+	for i := range items {
+		ticket := model.ParkingTicket{TicketID: fmt.Sprintf("ticket-%02d", i), ParkingLot: 7, Status: model.TicketStatusOut}
+		if i < 3 {
+			ticket.Status = model.TicketStatusIn
+		}
+		if i == 2 {
+			ticket.SpacesUsed = 2
+		}
+		items[i], _ = attributevalue.MarshalMap(ticket)
+	}
+	lastKey := map[string]types.AttributeValue{"ticketId": items[19]["ticketId"]}
+
+	isLotQuery := mock.MatchedBy(func(input *dynamodb.QueryInput) bool {
+		lot, ok := input.ExpressionAttributeValues[":parkingLot"].(*types.AttributeValueMemberN)
+		return *input.IndexName == parkingLotIndexName && ok && lot.Value == "7"
+	})
+	mockClient.On("Query", ctx, isLotQuery, mock.Anything).Return(&dynamodb.QueryOutput{
+		Items: items[:20], LastEvaluatedKey: lastKey,
+	}, nil).Once()
+	mockClient.On("Query", ctx, isLotQuery, mock.Anything).Return(&dynamodb.QueryOutput{Items: items[20:]}, nil).Once()
+
+	// The first batch leaves one delete unprocessed, which is resubmitted on its own
+	var batches [][]types.WriteRequest
+	recordBatch := func(args mock.Arguments) {
+		batches = append(batches, args.Get(1).(*dynamodb.BatchWriteItemInput).RequestItems["testTable"])
+	}
+	unprocessed := types.WriteRequest{DeleteRequest: &types.DeleteRequest{Key: map[string]types.AttributeValue{"ticketId": items[0]["ticketId"]}}}
+	mockClient.On("BatchWriteItem", ctx, mock.Anything, mock.Anything).Run(recordBatch).Return(&dynamodb.BatchWriteItemOutput{
+		UnprocessedItems: map[string][]types.WriteRequest{"testTable": {unprocessed}},
+	}, nil).Once()
+	mockClient.On("BatchWriteItem", ctx, mock.Anything, mock.Anything).Run(recordBatch).Return(&dynamodb.BatchWriteItemOutput{}, nil).Twice()
+
+	err := service.ResetLot(ctx, 7)
+
+	assert.NoError(t, err)
+	var batchSizes []int
+	deleted := map[string]bool{}
+	for _, batch := range batches {
+		batchSizes = append(batchSizes, len(batch))
+		for _, request := range batch {
+			deleted[request.DeleteRequest.Key["ticketId"].(*types.AttributeValueMemberS).Value] = true
+		}
+	}
+	assert.Equal(t, []int{20, 1, 10}, batchSizes)
+	assert.Len(t, deleted, 30)
+	occupied, _ := service.occupancy.Occupied(ctx, 7)
+	assert.Equal(t, 1, occupied)
+	mockClient.AssertExpectations(t)
+}
+
+// TestBatchDelete_Chunks tests that deletes are split into batches DynamoDB accepts
+func TestBatchDelete_Chunks(t *testing.T) {
+	ctx := context.Background()
+	mockClient := new(mocks.DynamoDBClient)
+	service := &ParkingLotService{client: mockClient, tableName: "testTable", log: logger.NewLogger()}
+
+	keys := make([]map[string]types.AttributeValue, 60)
+	for i := range keys {
+		keys[i] = map[string]types.AttributeValue{"ticketId": &types.AttributeValueMemberS{Value: fmt.Sprint(i)}}
+	}
+	for _, size := range []int{25, 25, 10} {
+		mockClient.On("BatchWriteItem", ctx, mock.MatchedBy(func(input *dynamodb.BatchWriteItemInput) bool {
+			return len(input.RequestItems["testTable"]) == size
+		}), mock.Anything).Return(&dynamodb.BatchWriteItemOutput{}, nil).Once()
+	}
+
+	assert.NoError(t, service.batchDelete(ctx, keys))
+	mockClient.AssertExpectations(t)
+}
+
+// TestMemoryParkingLotService_ResetLot tests resetting a lot in the in-memory service
+func TestMemoryParkingLotService_ResetLot(t *testing.T) {
+	ctx := context.Background()
+	service, err := NewMemoryParkingLotService(ctx)
+	assert.NoError(t, err)
+
+	assert.NoError(t, service.ReserveSpaces(ctx, 1, 1))
+	resetID, _ := service.CreateTicket(ctx, "ABC-123", 1, 1)
+	keptID, _ := service.CreateTicket(ctx, "XYZ-789", 2, 1)
+
+	t.Setenv("ALLOW_DESTRUCTIVE_ADMIN", "")
+	assert.ErrorIs(t, service.ResetLot(ctx, 1), ErrDestructiveAdminDisabled)
+	_, found := service.GetTicket(ctx, resetID.String())
+	assert.True(t, found)
+
+	t.Setenv("ALLOW_DESTRUCTIVE_ADMIN", "true")
+	t.Setenv("AWS_EXECUTION_ENV", "")
+	assert.NoError(t, service.ResetLot(ctx, 1))
+
+	_, found = service.GetTicket(ctx, resetID.String())
+	assert.False(t, found)
+	_, found = service.GetTicket(ctx, keptID.String())
+	assert.True(t, found)
+	occupied, _ := service.occupancy.Occupied(ctx, 1)
+	assert.Equal(t, 0, occupied)
+}
@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"parking-lot/internal/logger"
+	"parking-lot/internal/model"
+)
+
+// ErrScanTruncated is returned when reading the tickets an operation needs, e.g. every parked ticket
+// of a lot, stopped at MAX_SCAN_PAGES
+var ErrScanTruncated = errors.New("tickets exceed MAX_SCAN_PAGES")
+
+// RevenueEstimator is implemented by services that can estimate what parked vehicles owe
+type RevenueEstimator interface {
+	// EstimatedOutstandingRevenue returns what the vehicles parked in a lot would be charged
+	// if they all exited now, and whether only part of the lot was read because of MAX_SCAN_PAGES
+	EstimatedOutstandingRevenue(ctx context.Context, parkingLot int) (float32, bool, error)
+}
+
+// EstimatedOutstandingRevenue queries the parking lot index for the lot's parked vehicles and sums
+// the charge of each up to now, as read from the service clock. Coupons and quotes are not applied.
+func (s *ParkingLotService) EstimatedOutstandingRevenue(ctx context.Context, parkingLot int) (float32, bool, error) {
+	log := s.log.WithContext(ctx).WithFields(logger.Field{Key: "parking_lot", Value: parkingLot})
+	log.Info("Estimating outstanding revenue")
+
+	tickets, truncated, err := s.queryParkedTickets(ctx, log, parkingLot)
+	if err != nil {
+		return 0, false, err
+	}
+
+	revenue := s.outstandingCharges(tickets)
+	log.Info("Estimated outstanding revenue",
+		logger.Field{Key: "tickets", Value: len(tickets)},
+		logger.Field{Key: "revenue", Value: revenue},
+		logger.Field{Key: "truncated", Value: truncated},
+	)
+	return revenue, truncated, nil
+}
+
+// queryParkedTickets queries the parking lot index for the tickets of the vehicles parked in a lot,
+// reading only the attributes needed to bill them and count their spaces. It stops after
+// MAX_SCAN_PAGES pages, reporting that the tickets are truncated when more remain.
+func (s *ParkingLotService) queryParkedTickets(ctx context.Context, log logger.Logger, parkingLot int) ([]*model.ParkingTicket, bool, error) {
+	input := &dynamodb.QueryInput{
+		TableName:              aws.String(s.table(ctx)),
+		IndexName:              aws.String(parkingLotIndexName),
+		KeyConditionExpression: aws.String("parkingLot = :parkingLot"),
+		FilterExpression:       aws.String("#status = :status"),
+		ProjectionExpression:   aws.String("ticketId, parkingLot, entryTime, spacesUsed, pricingArm, #status"),
+		ExpressionAttributeNames: map[string]string{
+			"#status": "status", // status is a reserved word
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":parkingLot": &types.AttributeValueMemberN{Value: strconv.Itoa(parkingLot)},
+			":status":     &types.AttributeValueMemberS{Value: string(model.TicketStatusIn)},
+		},
+	}
+
+	var tickets []*model.ParkingTicket
+	for pages := 1; ; pages++ {
+		result, err := s.client.Query(ctx, input)
+		if err != nil {
+			log.Error("Failed to query parking lot index", logger.Field{Key: "error", Value: err.Error()})
+			return nil, false, fmt.Errorf("failed to query parking lot index: %w", err)
+		}
+
+		for _, item := range result.Items {
+			ticket := &model.ParkingTicket{}
+			if err := s.unmarshalMap(item, ticket); err != nil {
+				return nil, false, fmt.Errorf("failed to unmarshal ticket: %w", err)
+			}
+			tickets = append(tickets, ticket)
+		}
+
+		if len(result.LastEvaluatedKey) == 0 {
+			return tickets, false, nil
+		}
+		if s.maxScanPages > 0 && pages >= s.maxScanPages {
+			log.Warn("Stopped reading parked tickets at MAX_SCAN_PAGES", logger.Field{Key: "pages", Value: pages})
+			return tickets, true, nil
+		}
+		input.ExclusiveStartKey = result.LastEvaluatedKey
+	}
+}
+
+// outstandingCharges sums what the parked tickets would be charged if they exited now, billing each
+// with the pricing experiment arm it was assigned at entry and less what reopened tickets already paid
+func (s *ParkingLotService) outstandingCharges(tickets []*model.ParkingTicket) float32 {
+	var revenue float32
+	for _, ticket := range tickets {
+		if ticket.Status != model.TicketStatusIn {
+			continue
+		}
+		_, _, breakdown := s.calculateBreakdown(s.armPricingStrategy(ticket.PricingArm, ticket.ParkingLot), ticket.EntryTime, s.now())
+		revenue += breakdown.LessPriorCharge(ticket.PriorCharge).Total
+	}
+	return revenue
+}
+
+// EstimatedOutstandingRevenue sums the charges of the vehicles held in memory as parked in a lot
+func (m *MemoryParkingLotService) EstimatedOutstandingRevenue(ctx context.Context, parkingLot int) (float32, bool, error) {
+	m.mu.Lock()
+	var tickets []*model.ParkingTicket
+	for _, ticket := range m.tickets {
+		if ticket.ParkingLot == parkingLot {
+			tickets = append(tickets, copyTicket(ticket))
+		}
+	}
+	m.mu.Unlock()
+
+	return m.outstandingCharges(tickets), false, nil
+}
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"parking-lot/internal/mocks"
+	"parking-lot/internal/model"
+)
+
+// TestEstimatedOutstandingRevenue tests that the charges of a lot's parked tickets are summed up to now
+func TestEstimatedOutstandingRevenue(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	parked := func(ticketID string, parkedFor time.Duration) map[string]types.AttributeValue {
+		return map[string]types.AttributeValue{
+			"ticketId":   &types.AttributeValueMemberS{Value: ticketID},
+			"parkingLot": &types.AttributeValueMemberN{Value: "3"},
+			"entryTime":  &types.AttributeValueMemberS{Value: now.Add(-parkedFor).Format(time.RFC3339)},
+			"status":     &types.AttributeValueMemberS{Value: "in"},
+		}
+	}
+
+	mockClient := new(mocks.DynamoDBClient)
+	service := newEventsTestService(mockClient)
+	service.rates = model.RateSchedule{IncrementMinutes: 15, RatePerIncrement: 2.5, Currency: "USD"}
+	service.clock = func() time.Time { return now }
+
+	// The second page holds the third ticket
+	mockClient.On("Query", ctx, mock.MatchedBy(func(in *dynamodb.QueryInput) bool {
+		return *in.IndexName == parkingLotIndexName && in.ExclusiveStartKey == nil
+	}), mock.Anything).Return(&dynamodb.QueryOutput{
+		Items:            []map[string]types.AttributeValue{parked("t1", 10*time.Minute), parked("t2", 20*time.Minute)},
+		LastEvaluatedKey: map[string]types.AttributeValue{"ticketId": &types.AttributeValueMemberS{Value: "t2"}},
+	}, nil).Once()
+	mockClient.On("Query", ctx, mock.MatchedBy(func(in *dynamodb.QueryInput) bool {
+		return in.ExclusiveStartKey != nil
+	}), mock.Anything).Return(&dynamodb.QueryOutput{
+		Items: []map[string]types.AttributeValue{parked("t3", 40*time.Minute)},
+	}, nil).Once()
+
+	revenue, truncated, err := service.EstimatedOutstandingRevenue(ctx, 3)
+
+	require.NoError(t, err)
+	// One, two and three increments of 2.50
+	assert.Equal(t, float32(15), revenue)
+	assert.False(t, truncated)
+	mockClient.AssertExpectations(t)
+}
+
+// TestEstimatedOutstandingRevenue_MaxScanPages tests that reading a lot stops after MAX_SCAN_PAGES
+// pages and the estimate is reported as truncated
+func TestEstimatedOutstandingRevenue_MaxScanPages(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	mockClient := new(mocks.DynamoDBClient)
+	service := newEventsTestService(mockClient)
+	service.rates = model.RateSchedule{IncrementMinutes: 15, RatePerIncrement: 2.5, Currency: "USD"}
+	service.clock = func() time.Time { return now }
+	service.maxScanPages = 2
+
+	// Every page points at another one, as on a table too large to read in full
+	mockClient.On("Query", ctx, mock.Anything, mock.Anything).Return(&dynamodb.QueryOutput{
+		Items: []map[string]types.AttributeValue{{
+			"ticketId":   &types.AttributeValueMemberS{Value: "t1"},
+			"parkingLot": &types.AttributeValueMemberN{Value: "3"},
+			"entryTime":  &types.AttributeValueMemberS{Value: now.Add(-10 * time.Minute).Format(time.RFC3339)},
+			"status":     &types.AttributeValueMemberS{Value: "in"},
+		}},
+		LastEvaluatedKey: map[string]types.AttributeValue{"ticketId": &types.AttributeValueMemberS{Value: "t1"}},
+	}, nil).Twice()
+
+	revenue, truncated, err := service.EstimatedOutstandingRevenue(ctx, 3)
+
+	require.NoError(t, err)
+	assert.True(t, truncated)
+	// Only the tickets of the two pages read are charged
+	assert.Equal(t, float32(5), revenue)
+	mockClient.AssertExpectations(t)
+	mockClient.AssertNumberOfCalls(t, "Query", 2)
+}
+
+// TestEstimatedOutstandingRevenue_Memory tests that only parked tickets of the lot are summed
+func TestEstimatedOutstandingRevenue_Memory(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	service, err := NewMemoryParkingLotService(ctx)
+	require.NoError(t, err)
+	service.rates = model.RateSchedule{IncrementMinutes: 15, RatePerIncrement: 2.5, Currency: "USD"}
+	service.clock = func() time.Time { return now.Add(-40 * time.Minute) }
+
+	_, _ = service.CreateTicket(ctx, "ABC-123", 3, 1)
+	_, second := service.CreateTicket(ctx, "DEF-456", 3, 1)
+	_, _ = service.CreateTicket(ctx, "GHI-789", 4, 1)
+	second.Status = model.TicketStatusOut
+	require.NoError(t, service.UpdateTicket(ctx, second))
+	service.clock = func() time.Time { return now }
+
+	revenue, truncated, err := service.EstimatedOutstandingRevenue(ctx, 3)
+
+	require.NoError(t, err)
+	assert.False(t, truncated)
+	// The other lot's ticket and the exited ticket are left out
+	assert.Equal(t, float32(7.5), revenue)
+}
@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"parking-lot/internal/logger"
+	"parking-lot/internal/model"
+)
+
+// memorySnapshot is the JSON file the in-memory store is saved to on shutdown and loaded from on start
+type memorySnapshot struct {
+	SavedAt time.Time              `json:"savedAt"`
+	Tickets []*model.ParkingTicket `json:"tickets"`
+}
+
+// SnapshotPath returns the file the tickets are saved to on shutdown (INMEM_SNAPSHOT_PATH);
+// empty disables snapshots
+func (m *MemoryParkingLotService) SnapshotPath() string {
+	return m.snapshotPath
+}
+
+// SaveSnapshot writes every ticket held in memory to path as JSON. The file is replaced atomically,
+// so a crash while saving leaves the previous snapshot intact.
+func (m *MemoryParkingLotService) SaveSnapshot(path string) error {
+	m.mu.Lock()
+	snapshot := memorySnapshot{SavedAt: m.now(), Tickets: make([]*model.ParkingTicket, 0, len(m.tickets))}
+	for _, ticket := range m.tickets {
+		snapshot.Tickets = append(snapshot.Tickets, copyTicket(ticket))
+	}
+	m.mu.Unlock()
+	sort.Slice(snapshot.Tickets, func(i, j int) bool {
+		return snapshot.Tickets[i].EntryTime.Before(snapshot.Tickets[j].EntryTime)
+	})
+
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return fmt.Errorf("failed to marshal snapshot: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create snapshot file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to replace snapshot: %w", err)
+	}
+	return nil
+}
+
+// LoadSnapshot replaces the tickets held in memory with those saved at path, returning how many were
+// loaded, and claims the spaces and spots of the parked ones again; it is meant to run at start, while
+// the counters are empty. A missing file leaves the store empty; an unreadable one is reported without
+// changing the store.
+func (m *MemoryParkingLotService) LoadSnapshot(ctx context.Context, path string) (int, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to read snapshot: %w", err)
+	}
+
+	var snapshot memorySnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return 0, fmt.Errorf("failed to parse snapshot: %w", err)
+	}
+
+	tickets := make(map[string]*model.ParkingTicket, len(snapshot.Tickets))
+	occupied := make(map[int]int)
+	for _, ticket := range snapshot.Tickets {
+		if ticket == nil || ticket.TicketID == "" {
+			return 0, fmt.Errorf("failed to parse snapshot: ticket without an ID")
+		}
+		tickets[ticket.TicketID] = ticket
+		if ticket.Status == model.TicketStatusIn {
+			occupied[ticket.ParkingLot] += ticket.Spaces()
+		}
+	}
+
+	m.mu.Lock()
+	m.tickets = tickets
+	m.mu.Unlock()
+
+	// The counters and spots start empty, so claim what the parked tickets hold
+	if setter, ok := m.occupancy.(occupancySetter); ok {
+		for parkingLot, spaces := range occupied {
+			_, _ = setter.SetOccupied(ctx, parkingLot, 0, spaces)
+		}
+	}
+	if spots, ok := m.spots.(*MemorySpots); ok {
+		for _, ticket := range tickets {
+			if ticket.Status == model.TicketStatusIn && ticket.SpotNumber > 0 {
+				spots.claim(ticket.ParkingLot, ticket.SpotNumber)
+			}
+		}
+	}
+
+	m.log.WithContext(ctx).Info("Loaded in-memory snapshot",
+		logger.Field{Key: "path", Value: path},
+		logger.Field{Key: "tickets", Value: len(tickets)},
+		logger.Field{Key: "saved_at", Value: snapshot.SavedAt.Format(time.RFC3339)},
+	)
+	return len(tickets), nil
+}
@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"parking-lot/internal/model"
+)
+
+// TestMemorySnapshot tests that tickets, occupancy and spots survive a save and load
+func TestMemorySnapshot(t *testing.T) {
+	ctx := context.Background()
+	t.Setenv("LOT_CONFIG", `{"3":{"capacity":10,"spots":10}}`)
+	path := filepath.Join(t.TempDir(), "tickets.json")
+
+	saved, err := NewMemoryParkingLotService(ctx)
+	require.NoError(t, err)
+	require.NoError(t, saved.ReserveSpaces(ctx, 3, 2))
+	_, parked := saved.CreateTicket(ctx, "ABC-123", 3, 2)
+	require.NoError(t, saved.ReserveSpaces(ctx, 3, 1))
+	_, exited := saved.CreateTicket(ctx, "DEF-456", 3, 1)
+	exited.Status = model.TicketStatusOut
+	require.NoError(t, saved.UpdateTicket(ctx, exited))
+	saved.ReleaseSpaces(ctx, 3, 1)
+	saved.ReleaseSpot(ctx, exited)
+
+	require.NoError(t, saved.SaveSnapshot(path))
+
+	t.Setenv("INMEM_SNAPSHOT_PATH", path)
+	loaded, err := NewMemoryParkingLotService(ctx)
+	require.NoError(t, err)
+
+	ticket, ok := loaded.GetTicket(ctx, parked.TicketID)
+	require.True(t, ok)
+	assert.Equal(t, parked.Plate, ticket.Plate)
+	assert.True(t, parked.EntryTime.Equal(ticket.EntryTime))
+	assert.Equal(t, 1, ticket.SpotNumber)
+	_, ok = loaded.GetTicket(ctx, exited.TicketID)
+	assert.True(t, ok)
+	assert.Equal(t, map[int]int{3: 2}, loaded.OccupancySnapshot())
+
+	// The parked ticket's spot stays taken, so the next entry gets the one the exited ticket freed
+	_, next := loaded.CreateTicket(ctx, "GHI-789", 3, 1)
+	assert.Equal(t, 2, next.SpotNumber)
+}
+
+// TestMemorySnapshot_Unreadable tests that a missing or corrupt snapshot leaves the store empty
+func TestMemorySnapshot_Unreadable(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	corrupt := filepath.Join(dir, "corrupt.json")
+	require.NoError(t, os.WriteFile(corrupt, []byte(`{"tickets":[{"ticketId":`), 0o600))
+	noID := filepath.Join(dir, "no-id.json")
+	require.NoError(t, os.WriteFile(noID, []byte(`{"tickets":[{"plate":"ABC-123"}]}`), 0o600))
+
+	for name, path := range map[string]string{
+		"Missing":           filepath.Join(dir, "missing.json"),
+		"Corrupt":           corrupt,
+		"Ticket without ID": noID,
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv("INMEM_SNAPSHOT_PATH", path)
+
+			service, err := NewMemoryParkingLotService(ctx)
+
+			require.NoError(t, err)
+			assert.Empty(t, service.Snapshot())
+			assert.Equal(t, path, service.SnapshotPath())
+		})
+	}
+
+	t.Run("Load reports the error", func(t *testing.T) {
+		service, err := NewMemoryParkingLotService(ctx)
+		require.NoError(t, err)
+
+		_, err = service.LoadSnapshot(ctx, corrupt)
+		assert.Error(t, err)
+		loaded, err := service.LoadSnapshot(ctx, filepath.Join(dir, "missing.json"))
+		assert.NoError(t, err)
+		assert.Zero(t, loaded)
+	})
+}
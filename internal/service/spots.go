@@ -0,0 +1,206 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"parking-lot/internal/logger"
+	"parking-lot/internal/model"
+)
+
+// ErrNoFreeSpot is returned when every numbered spot in a lot is taken
+var ErrNoFreeSpot = errors.New("no free spot in parking lot")
+
+// SpotManager allocates the numbered spots of parking lots that assign them
+type SpotManager interface {
+	// Allocate claims the lowest free spot numbered 1 to spots in a lot,
+	// failing with ErrNoFreeSpot when all of them are taken
+	Allocate(ctx context.Context, parkingLot, spots int) (int, error)
+
+	// Free returns a spot to its lot
+	Free(ctx context.Context, parkingLot, spot int) error
+}
+
+// SpotReleaser is implemented by services that assign numbered spots at entry
+type SpotReleaser interface {
+	// ReleaseSpot frees the spot held by an exiting ticket, if any
+	ReleaseSpot(ctx context.Context, ticket *model.ParkingTicket)
+}
+
+// MemorySpots is an in-memory SpotManager.
+// Allocations are local to the process and are lost on restart.
+type MemorySpots struct {
+	mu    sync.Mutex
+	taken map[int]map[int]bool
+}
+
+// NewMemorySpots creates an in-memory spot manager with every spot free
+func NewMemorySpots() *MemorySpots {
+	return &MemorySpots{taken: make(map[int]map[int]bool)}
+}
+
+// Allocate claims the lowest free spot in a lot
+func (m *MemorySpots) Allocate(ctx context.Context, parkingLot, spots int) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.taken[parkingLot] == nil {
+		m.taken[parkingLot] = make(map[int]bool)
+	}
+	spot := lowestFreeSpot(spots, m.taken[parkingLot])
+	if spot == 0 {
+		return 0, ErrNoFreeSpot
+	}
+	m.taken[parkingLot][spot] = true
+	return spot, nil
+}
+
+// Free returns a spot to its lot
+func (m *MemorySpots) Free(ctx context.Context, parkingLot, spot int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.taken[parkingLot], spot)
+	return nil
+}
+
+// claim marks a spot of a lot as taken, e.g. by a ticket loaded from a snapshot
+func (m *MemorySpots) claim(parkingLot, spot int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.taken[parkingLot] == nil {
+		m.taken[parkingLot] = make(map[int]bool)
+	}
+	m.taken[parkingLot][spot] = true
+}
+
+// lowestFreeSpot returns the lowest spot numbered 1 to spots missing from taken, or 0 when all are taken
+func lowestFreeSpot(spots int, taken map[int]bool) int {
+	for spot := 1; spot <= spots; spot++ {
+		if !taken[spot] {
+			return spot
+		}
+	}
+	return 0
+}
+
+// spotsItemPrefix starts the key of the config item holding a lot's taken spots, e.g. "config#spots#382"
+const spotsItemPrefix = "config#spots#"
+
+// spotAllocateAttempts bounds how often an allocation is retried when another entry claims the same spot
+const spotAllocateAttempts = 5
+
+// dynamoSpots is a SpotManager keeping the taken spots of each lot as a number set in a config item
+// of the tickets table, so every instance shares the allocations
+type dynamoSpots struct {
+	s *ParkingLotService
+}
+
+// Allocate reads the lot's taken spots and claims the lowest free one with a conditional update.
+// When another entry claims the same spot first, the allocation is retried with a fresh read.
+func (d *dynamoSpots) Allocate(ctx context.Context, parkingLot, spots int) (int, error) {
+	key := d.s.configItemKey(spotsItemPrefix + strconv.Itoa(parkingLot))
+
+	for attempt := 0; attempt < spotAllocateAttempts; attempt++ {
+		result, err := d.s.client.GetItem(ctx, &dynamodb.GetItemInput{
+			TableName:      aws.String(d.s.table(ctx)),
+			Key:            key,
+			ConsistentRead: aws.Bool(true),
+		})
+		if err != nil {
+			return 0, fmt.Errorf("failed to read taken spots: %w", err)
+		}
+
+		taken := make(map[int]bool)
+		if set, ok := result.Item["taken"].(*types.AttributeValueMemberNS); ok {
+			for _, value := range set.Value {
+				if spot, err := strconv.Atoi(value); err == nil {
+					taken[spot] = true
+				}
+			}
+		}
+		spot := lowestFreeSpot(spots, taken)
+		if spot == 0 {
+			return 0, ErrNoFreeSpot
+		}
+
+		number := strconv.Itoa(spot)
+		_, err = d.s.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+			TableName:           aws.String(d.s.table(ctx)),
+			Key:                 key,
+			UpdateExpression:    aws.String("ADD taken :spots"),
+			ConditionExpression: aws.String("attribute_not_exists(taken) OR NOT contains(taken, :spot)"),
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":spots": &types.AttributeValueMemberNS{Value: []string{number}},
+				":spot":  &types.AttributeValueMemberN{Value: number},
+			},
+		})
+		var conflict *types.ConditionalCheckFailedException
+		if errors.As(err, &conflict) {
+			continue
+		}
+		if err != nil {
+			return 0, fmt.Errorf("failed to claim spot: %w", err)
+		}
+		return spot, nil
+	}
+	return 0, fmt.Errorf("failed to claim spot: still contended after %d attempts", spotAllocateAttempts)
+}
+
+// Free removes a spot from the lot's taken spots
+func (d *dynamoSpots) Free(ctx context.Context, parkingLot, spot int) error {
+	_, err := d.s.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName:        aws.String(d.s.table(ctx)),
+		Key:              d.s.configItemKey(spotsItemPrefix + strconv.Itoa(parkingLot)),
+		UpdateExpression: aws.String("DELETE taken :spots"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":spots": &types.AttributeValueMemberNS{Value: []string{strconv.Itoa(spot)}},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to free spot: %w", err)
+	}
+	return nil
+}
+
+// assignSpot allocates a numbered spot to a ticket entering a lot that assigns them
+func (s *ParkingLotService) assignSpot(ctx context.Context, log logger.Logger, ticket *model.ParkingTicket) error {
+	spots := s.LotConfig(ticket.ParkingLot).Spots
+	if s.spots == nil || spots == 0 {
+		return nil
+	}
+
+	spot, err := s.spots.Allocate(ctx, ticket.ParkingLot, spots)
+	if err != nil {
+		log.Warn("Failed to allocate spot", logger.Field{Key: "error", Value: err.Error()})
+		return err
+	}
+	ticket.SpotNumber = spot
+	log.Info("Allocated spot", logger.Field{Key: "spot", Value: spot})
+	return nil
+}
+
+// ReleaseSpot frees the spot held by an exiting ticket so the next entry can take it
+func (s *ParkingLotService) ReleaseSpot(ctx context.Context, ticket *model.ParkingTicket) {
+	if s.spots == nil || ticket.SpotNumber == 0 {
+		return
+	}
+
+	log := s.log.WithContext(ctx).WithFields(
+		logger.Field{Key: "parking_lot", Value: ticket.ParkingLot},
+		logger.Field{Key: "spot", Value: ticket.SpotNumber},
+	)
+	if err := s.spots.Free(ctx, ticket.ParkingLot, ticket.SpotNumber); err != nil {
+		log.Error("Failed to free spot", logger.Field{Key: "error", Value: err.Error()})
+		return
+	}
+	log.Info("Freed spot")
+}
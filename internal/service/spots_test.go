@@ -0,0 +1,184 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"parking-lot/internal/logger"
+	"parking-lot/internal/mocks"
+	"parking-lot/internal/model"
+)
+
+// TestMemorySpots tests allocating the lowest free spot and reusing freed ones
+func TestMemorySpots(t *testing.T) {
+	ctx := context.Background()
+	spots := NewMemorySpots()
+
+	first, err := spots.Allocate(ctx, 1, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, first)
+	second, err := spots.Allocate(ctx, 1, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, second)
+
+	_, err = spots.Allocate(ctx, 1, 2)
+	assert.ErrorIs(t, err, ErrNoFreeSpot)
+
+	// Lots are allocated independently
+	other, err := spots.Allocate(ctx, 2, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, other)
+
+	assert.NoError(t, spots.Free(ctx, 1, first))
+	again, err := spots.Allocate(ctx, 1, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, first, again)
+}
+
+// TestDynamoSpots_Allocate tests claiming the lowest free spot with a conditional update,
+// retrying when another entry claims it first
+func TestDynamoSpots_Allocate(t *testing.T) {
+	ctx := context.Background()
+	mockClient := new(mocks.DynamoDBClient)
+	service := &ParkingLotService{client: mockClient, tableName: "testTable", log: logger.NewLogger()}
+	spots := &dynamoSpots{s: service}
+
+	isSpotsKey := func(input *dynamodb.GetItemInput) bool {
+		key, ok := input.Key["ticketId"].(*types.AttributeValueMemberS)
+		return ok && key.Value == "config#spots#7" && *input.ConsistentRead
+	}
+	taken := func(numbers ...string) *dynamodb.GetItemOutput {
+		return &dynamodb.GetItemOutput{Item: map[string]types.AttributeValue{
+			"taken": &types.AttributeValueMemberNS{Value: numbers},
+		}}
+	}
+	claims := func(spot string) interface{} {
+		return mock.MatchedBy(func(input *dynamodb.UpdateItemInput) bool {
+			value, ok := input.ExpressionAttributeValues[":spot"].(*types.AttributeValueMemberN)
+			return ok && value.Value == spot && *input.UpdateExpression == "ADD taken :spots"
+		})
+	}
+
+	// Spot 2 is claimed by another entry between the read and the update
+	mockClient.On("GetItem", ctx, mock.MatchedBy(isSpotsKey), mock.Anything).Return(taken("1"), nil).Once()
+	mockClient.On("UpdateItem", ctx, claims("2"), mock.Anything).Return(nil, &types.ConditionalCheckFailedException{}).Once()
+	mockClient.On("GetItem", ctx, mock.MatchedBy(isSpotsKey), mock.Anything).Return(taken("1", "2"), nil).Once()
+	mockClient.On("UpdateItem", ctx, claims("3"), mock.Anything).Return(&dynamodb.UpdateItemOutput{}, nil).Once()
+
+	spot, err := spots.Allocate(ctx, 7, 3)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, spot)
+	mockClient.AssertExpectations(t)
+
+	// Once every spot is taken no update is attempted
+	mockClient.On("GetItem", ctx, mock.MatchedBy(isSpotsKey), mock.Anything).Return(taken("1", "2", "3"), nil).Once()
+	_, err = spots.Allocate(ctx, 7, 3)
+	assert.ErrorIs(t, err, ErrNoFreeSpot)
+	mockClient.AssertNumberOfCalls(t, "UpdateItem", 2)
+}
+
+// TestDynamoSpots_Free tests removing a spot from the lot's taken spots
+func TestDynamoSpots_Free(t *testing.T) {
+	ctx := context.Background()
+	mockClient := new(mocks.DynamoDBClient)
+	spots := &dynamoSpots{s: &ParkingLotService{client: mockClient, tableName: "testTable", log: logger.NewLogger()}}
+
+	mockClient.On("UpdateItem", ctx, mock.MatchedBy(func(input *dynamodb.UpdateItemInput) bool {
+		set, ok := input.ExpressionAttributeValues[":spots"].(*types.AttributeValueMemberNS)
+		return ok && set.Value[0] == "4" && *input.UpdateExpression == "DELETE taken :spots"
+	}), mock.Anything).Return(&dynamodb.UpdateItemOutput{}, nil).Once()
+
+	assert.NoError(t, spots.Free(ctx, 7, 4))
+	mockClient.AssertExpectations(t)
+}
+
+// TestMemoryParkingLotService_Spots tests that tickets get a spot in lots configured with spots,
+// that exiting frees it for the next entry, and that lots without spots assign none
+func TestMemoryParkingLotService_Spots(t *testing.T) {
+	t.Setenv("LOT_CONFIG", `{"1":{"spots":1}}`)
+	t.Setenv("GRACE_REENTRY_MINUTES", "")
+	ctx := context.Background()
+	service, err := NewMemoryParkingLotService(ctx)
+	assert.NoError(t, err)
+
+	_, first, err := service.CreateTicketChecked(ctx, "ABC-123", 1, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, first.SpotNumber)
+
+	_, _, err = service.CreateTicketChecked(ctx, "XYZ-789", 1, 1)
+	assert.ErrorIs(t, err, ErrNoFreeSpot)
+
+	service.ReleaseSpot(ctx, first)
+	_, next, err := service.CreateTicketChecked(ctx, "XYZ-789", 1, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, next.SpotNumber)
+
+	_, unnumbered, err := service.CreateTicketChecked(ctx, "DEF-456", 2, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, unnumbered.SpotNumber)
+}
+
+// TestReleaseSpot_Disabled tests that releasing is a no-op without a spot manager or a spot
+func TestReleaseSpot_Disabled(t *testing.T) {
+	mockClient := new(mocks.DynamoDBClient)
+	service := &ParkingLotService{client: mockClient, tableName: "testTable", log: logger.NewLogger()}
+
+	service.ReleaseSpot(context.Background(), &model.ParkingTicket{ParkingLot: 1, SpotNumber: 3})
+
+	service.spots = &dynamoSpots{s: service}
+	service.ReleaseSpot(context.Background(), &model.ParkingTicket{ParkingLot: 1})
+
+	mockClient.AssertNotCalled(t, "UpdateItem", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestCreateTicketChecked_ReleasesSpot tests that a ticket that is never stored gives its spot back
+func TestCreateTicketChecked_ReleasesSpot(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name       string
+		marshalErr error
+		putErr     error
+	}{
+		{name: "Marshal error", marshalErr: errors.New("unsupported value")},
+		{name: "Store error", putErr: errors.New("throttled")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := new(mocks.DynamoDBClient)
+			spots := NewMemorySpots()
+			service := &ParkingLotService{
+				client:       mockClient,
+				tableName:    "testTable",
+				log:          logger.NewLogger(),
+				lots:         map[int]model.LotConfig{1: {Spots: 1}},
+				spots:        spots,
+				unmarshalMap: attributevalue.UnmarshalMap,
+				marshalMap: func(v interface{}) (map[string]types.AttributeValue, error) {
+					if tt.marshalErr != nil {
+						return nil, tt.marshalErr
+					}
+					return attributevalue.MarshalMap(v)
+				},
+			}
+			if tt.putErr != nil {
+				mockClient.On("PutItem", ctx, mock.Anything, mock.Anything).Return(nil, tt.putErr).Once()
+			}
+
+			_, _, err := service.CreateTicketChecked(ctx, "ABC-123", 1, 1)
+
+			assert.Error(t, err)
+			spot, err := spots.Allocate(ctx, 1, 1)
+			assert.NoError(t, err)
+			assert.Equal(t, 1, spot)
+		})
+	}
+}
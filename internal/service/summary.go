@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"parking-lot/internal/logger"
+	"parking-lot/internal/model"
+)
+
+// ticketSummaryAttributes are the attributes read for a ticket summary
+var ticketSummaryAttributes = []string{"ticketId", "plate", "parkingLot", "status"}
+
+// TicketSummaryReader is implemented by services that can read a ticket's summary without the rest of it
+type TicketSummaryReader interface {
+	// GetTicketSummary returns the plate, lot and status of a ticket, or nil when there is no such ticket
+	GetTicketSummary(ctx context.Context, ticketID string) (*model.TicketSummary, error)
+}
+
+// GetTicketSummary reads only the summary attributes of a ticket with a ProjectionExpression,
+// so the lookup is not charged for the rest of the item
+func (s *ParkingLotService) GetTicketSummary(ctx context.Context, ticketID string) (*model.TicketSummary, error) {
+	log := s.log.WithContext(ctx).WithFields(logger.Field{Key: "ticket_id", Value: ticketID})
+	log.Info("Getting ticket summary")
+
+	item, err := s.getTicketItem(ctx, ticketID, ticketSummaryAttributes...)
+	if err != nil {
+		log.Error("Failed to get ticket summary", logger.Field{Key: "error", Value: err.Error()})
+		return nil, fmt.Errorf("failed to get ticket summary: %w", err)
+	}
+	if item == nil {
+		log.Info("Ticket not found")
+		return nil, nil
+	}
+
+	summary := &model.TicketSummary{}
+	if err := s.unmarshalMap(item, summary); err != nil {
+		log.Error("Failed to unmarshal ticket summary", logger.Field{Key: "error", Value: err.Error()})
+		return nil, fmt.Errorf("failed to unmarshal ticket summary: %w", err)
+	}
+	return summary, nil
+}
+
+// GetTicketSummary returns the summary of a ticket held in memory
+func (m *MemoryParkingLotService) GetTicketSummary(ctx context.Context, ticketID string) (*model.TicketSummary, error) {
+	ticket, ok := m.GetTicket(ctx, ticketID)
+	if !ok {
+		return nil, nil
+	}
+	return &model.TicketSummary{
+		TicketID:   ticket.TicketID,
+		Plate:      ticket.Plate,
+		ParkingLot: ticket.ParkingLot,
+		Status:     ticket.Status,
+	}, nil
+}
@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"parking-lot/internal/logger"
+	"parking-lot/internal/mocks"
+	"parking-lot/internal/model"
+)
+
+// projected reports whether a read projects exactly the ticket summary attributes
+func projected(expression *string, names map[string]string) bool {
+	if expression == nil || *expression != "#p0, #p1, #p2, #p3" {
+		return false
+	}
+	for i, attribute := range ticketSummaryAttributes {
+		if names["#p"+strconv.Itoa(i)] != attribute {
+			return false
+		}
+	}
+	return len(names) == len(ticketSummaryAttributes)
+}
+
+// TestGetTicketSummary tests that a ticket summary only reads the summary attributes
+func TestGetTicketSummary(t *testing.T) {
+	ctx := context.Background()
+	item := map[string]types.AttributeValue{
+		"ticketId":   &types.AttributeValueMemberS{Value: "t1"},
+		"plate":      &types.AttributeValueMemberS{Value: "ABC-123"},
+		"parkingLot": &types.AttributeValueMemberN{Value: "3"},
+		"status":     &types.AttributeValueMemberS{Value: "in"},
+	}
+	newService := func(client *mocks.DynamoDBClient, compositeKey bool) *ParkingLotService {
+		return &ParkingLotService{
+			ctx:          ctx,
+			client:       client,
+			tableName:    "testTable",
+			log:          logger.NewLogger(),
+			marshalMap:   attributevalue.MarshalMap,
+			unmarshalMap: attributevalue.UnmarshalMap,
+			compositeKey: compositeKey,
+		}
+	}
+	expected := &model.TicketSummary{TicketID: "t1", Plate: "ABC-123", ParkingLot: 3, Status: model.TicketStatusIn}
+
+	t.Run("Keyed by ticket ID", func(t *testing.T) {
+		mockClient := new(mocks.DynamoDBClient)
+		mockClient.On("GetItem", ctx, mock.MatchedBy(func(in *dynamodb.GetItemInput) bool {
+			return projected(in.ProjectionExpression, in.ExpressionAttributeNames)
+		}), mock.Anything).Return(&dynamodb.GetItemOutput{Item: item}, nil).Once()
+
+		summary, err := newService(mockClient, false).GetTicketSummary(ctx, "t1")
+
+		require.NoError(t, err)
+		assert.Equal(t, expected, summary)
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run("Composite key", func(t *testing.T) {
+		mockClient := new(mocks.DynamoDBClient)
+		mockClient.On("Query", ctx, mock.MatchedBy(func(in *dynamodb.QueryInput) bool {
+			return *in.IndexName == ticketIDIndexName && projected(in.ProjectionExpression, in.ExpressionAttributeNames)
+		}), mock.Anything).Return(&dynamodb.QueryOutput{Items: []map[string]types.AttributeValue{item}}, nil).Once()
+
+		summary, err := newService(mockClient, true).GetTicketSummary(ctx, "t1")
+
+		require.NoError(t, err)
+		assert.Equal(t, expected, summary)
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run("Not found", func(t *testing.T) {
+		mockClient := new(mocks.DynamoDBClient)
+		mockClient.On("GetItem", ctx, mock.Anything, mock.Anything).Return(&dynamodb.GetItemOutput{}, nil).Once()
+
+		summary, err := newService(mockClient, false).GetTicketSummary(ctx, "missing")
+
+		require.NoError(t, err)
+		assert.Nil(t, summary)
+	})
+}
+
+// TestGetTicketItem_FullRead tests that reads without attributes fetch the whole item
+func TestGetTicketItem_FullRead(t *testing.T) {
+	ctx := context.Background()
+	mockClient := new(mocks.DynamoDBClient)
+	service := &ParkingLotService{ctx: ctx, client: mockClient, tableName: "testTable", log: logger.NewLogger()}
+	mockClient.On("GetItem", ctx, mock.MatchedBy(func(in *dynamodb.GetItemInput) bool {
+		return in.ProjectionExpression == nil && in.ExpressionAttributeNames == nil
+	}), mock.Anything).Return(&dynamodb.GetItemOutput{}, nil).Once()
+
+	_, err := service.getTicketItem(ctx, "t1")
+
+	require.NoError(t, err)
+	mockClient.AssertExpectations(t)
+}
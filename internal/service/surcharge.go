@@ -0,0 +1,63 @@
+package service
+
+import (
+	"math"
+	"time"
+)
+
+// Default night window for NIGHT_SURCHARGE_PCT, as hours of the day in the billing timezone
+const (
+	defaultNightStartHour = 22
+	defaultNightEndHour   = 6
+)
+
+// surcharges raise the charge for time parked at night or on weekends (Saturday and Sunday).
+// Rates are fractions of the charge, e.g. 0.25 for a 25% surcharge; they add up for weekend nights.
+type surcharges struct {
+	night   float64
+	weekend float64
+	// nightHours is the night window, wrapping past midnight when it ends earlier than it starts
+	nightHours operatingHours
+}
+
+// rate returns the surcharge rate for time parked in the hour starting at t
+func (s *surcharges) rate(t time.Time) float64 {
+	rate := 0.0
+	if s.night > 0 && s.nightHours.contains(t.Hour()) {
+		rate += s.night
+	}
+	if weekday := t.Weekday(); s.weekend > 0 && (weekday == time.Saturday || weekday == time.Sunday) {
+		rate += s.weekend
+	}
+	return rate
+}
+
+// multiplier returns the factor the charge for a stay from entry to exit is raised by. Stays that span
+// a surcharge boundary are prorated: each minute is surcharged at the rate in force at that time.
+func (s *surcharges) multiplier(entry, exit time.Time, location *time.Location) float64 {
+	total := exit.Sub(entry)
+	if total <= 0 {
+		return 1
+	}
+
+	// Surcharges change on the hour at most, so walk the stay one hour of the billing timezone at a time
+	var weighted float64
+	for start := entry.In(location); start.Before(exit); {
+		end := time.Date(start.Year(), start.Month(), start.Day(), start.Hour()+1, 0, 0, 0, location)
+		if end.After(exit) {
+			end = exit
+		}
+		weighted += s.rate(start) * float64(end.Sub(start))
+		start = end
+	}
+	return 1 + weighted/float64(total)
+}
+
+// applySurcharges raises a charge for the night and weekend time of a stay, rounded to cents
+func (s *ParkingLotService) applySurcharges(charge float32, entry, exit time.Time) float32 {
+	if s.surcharges == nil || charge == 0 {
+		return charge
+	}
+	multiplier := s.surcharges.multiplier(entry, exit, s.billingLocation())
+	return float32(math.Round(float64(charge)*multiplier*100) / 100)
+}
@@ -0,0 +1,162 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"parking-lot/internal/logger"
+	"parking-lot/internal/model"
+)
+
+// TestCalculateCharge_Surcharges tests night and weekend surcharges, prorated across their boundaries
+func TestCalculateCharge_Surcharges(t *testing.T) {
+	jerusalem, err := time.LoadLocation("Asia/Jerusalem")
+	assert.NoError(t, err)
+
+	// 50% at night (22:00-06:00) and 20% on weekends; 2024-05-01 is a Wednesday and 2024-05-04 a Saturday
+	tests := []struct {
+		name     string
+		location *time.Location
+		entry    time.Time
+		exit     time.Time
+		expected float32
+	}{
+		{name: "Weekday daytime", entry: time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC), exit: time.Date(2024, 5, 1, 11, 0, 0, 0, time.UTC), expected: 10},
+		{name: "Weekend", entry: time.Date(2024, 5, 4, 10, 0, 0, 0, time.UTC), exit: time.Date(2024, 5, 4, 11, 0, 0, 0, time.UTC), expected: 12},
+		{name: "Weekend night", entry: time.Date(2024, 5, 4, 23, 0, 0, 0, time.UTC), exit: time.Date(2024, 5, 5, 0, 0, 0, 0, time.UTC), expected: 17},
+		// Half of the stay is at night, so half of the charge is surcharged
+		{name: "Spanning the start of the night", entry: time.Date(2024, 5, 1, 21, 0, 0, 0, time.UTC), exit: time.Date(2024, 5, 1, 23, 0, 0, 0, time.UTC), expected: 25},
+		// Friday 23:00 to Saturday 01:00: one night hour on a weekday, one on the weekend
+		{name: "Spanning the start of the weekend", entry: time.Date(2024, 5, 3, 23, 0, 0, 0, time.UTC), exit: time.Date(2024, 5, 4, 1, 0, 0, 0, time.UTC), expected: 32},
+		// 19:00 UTC is 22:00 in Jerusalem during daylight saving time
+		{name: "Billing timezone", location: jerusalem, entry: time.Date(2024, 5, 1, 19, 0, 0, 0, time.UTC), exit: time.Date(2024, 5, 1, 20, 0, 0, 0, time.UTC), expected: 15},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &ParkingLotService{
+				rates:    model.RateSchedule{IncrementMinutes: 15, RatePerIncrement: 2.5},
+				location: tt.location,
+				clock:    func() time.Time { return tt.exit },
+				surcharges: &surcharges{
+					night:      0.5,
+					weekend:    0.2,
+					nightHours: operatingHours{open: 22, close: 6},
+				},
+			}
+
+			_, charge := service.CalculateCharge(tt.entry)
+
+			assert.Equal(t, tt.expected, charge)
+		})
+	}
+}
+
+// TestCalculateCharge_SurchargesDailyMax tests that the daily maximum caps surcharged charges too
+func TestCalculateCharge_SurchargesDailyMax(t *testing.T) {
+	// $2.50 per 15 minutes capped at $20 a day, 50% more at night (22:00-06:00); 2024-05-01 is a Wednesday
+	tests := []struct {
+		name     string
+		entry    time.Time
+		exit     time.Time
+		expected ChargeBreakdown
+	}{
+		{name: "Under the cap", entry: time.Date(2024, 5, 1, 22, 0, 0, 0, time.UTC), exit: time.Date(2024, 5, 1, 23, 0, 0, 0, time.UTC), expected: ChargeBreakdown{BaseCharge: 10, Surcharge: 5, Total: 15}},
+		{name: "Surcharge cut back", entry: time.Date(2024, 5, 1, 22, 0, 0, 0, time.UTC), exit: time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC), expected: ChargeBreakdown{BaseCharge: 20, Total: 20}},
+		{name: "Base under the cap", entry: time.Date(2024, 5, 1, 22, 0, 0, 0, time.UTC), exit: time.Date(2024, 5, 1, 23, 30, 0, 0, time.UTC), expected: ChargeBreakdown{BaseCharge: 15, Surcharge: 5, Total: 20}},
+		{name: "Every started day", entry: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC), exit: time.Date(2024, 5, 2, 23, 0, 0, 0, time.UTC), expected: ChargeBreakdown{BaseCharge: 40, Total: 40}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &ParkingLotService{
+				rates: model.RateSchedule{IncrementMinutes: 15, RatePerIncrement: 2.5, DailyMax: 20},
+				clock: func() time.Time { return tt.exit },
+				surcharges: &surcharges{
+					night:      0.5,
+					nightHours: operatingHours{open: 22, close: 6},
+				},
+			}
+
+			_, _, breakdown := service.CalculateChargeBreakdown(1, tt.entry)
+
+			assert.Equal(t, tt.expected, breakdown)
+		})
+	}
+}
+
+// TestLoadSurcharges tests reading the surcharge rates and night hours
+func TestLoadSurcharges(t *testing.T) {
+	tests := []struct {
+		name      string
+		night     string
+		weekend   string
+		start     string
+		end       string
+		expected  *surcharges
+		expectErr bool
+	}{
+		{name: "Unset"},
+		{name: "Night with default hours", night: "25", expected: &surcharges{night: 0.25, nightHours: operatingHours{open: 22, close: 6}}},
+		{name: "Weekend with custom night hours", weekend: "10", start: "20", end: "5", expected: &surcharges{weekend: 0.1, nightHours: operatingHours{open: 20, close: 5}}},
+		{name: "Negative rate", night: "-5", expectErr: true},
+		{name: "Hour out of range", night: "25", start: "24", expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("NIGHT_SURCHARGE_PCT", tt.night)
+			t.Setenv("WEEKEND_SURCHARGE_PCT", tt.weekend)
+			t.Setenv("NIGHT_START_HOUR", tt.start)
+			t.Setenv("NIGHT_END_HOUR", tt.end)
+
+			service, err := newConfiguredService(context.Background(), logger.NewLogger())
+
+			if tt.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, service.surcharges)
+		})
+	}
+}
+
+// TestCalculateChargeBreakdown tests that the surcharge is itemized apart from the base charge
+// and that a minimum charge replaces both. Each component is rounded to cents and the total is their sum.
+func TestCalculateChargeBreakdown(t *testing.T) {
+	// 2024-05-04 is a Saturday, surcharged 20%
+	entry := time.Date(2024, 5, 4, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name      string
+		exit      time.Time
+		rate      float32
+		minCharge float32
+		expected  ChargeBreakdown
+	}{
+		{name: "Surcharged", exit: entry.Add(time.Hour), rate: 2.5, expected: ChargeBreakdown{BaseCharge: 10, Surcharge: 2, Total: 12}},
+		// The base charge of 1.332 is rounded before it is surcharged, so the total adds up to the components
+		{name: "Rounded components", exit: entry.Add(time.Hour), rate: 0.333, expected: ChargeBreakdown{BaseCharge: 1.33, Surcharge: 0.27, Total: 1.6}},
+		{name: "Minimum charge", exit: entry.Add(15 * time.Minute), rate: 2.5, minCharge: 5, expected: ChargeBreakdown{BaseCharge: 5, Total: 5}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &ParkingLotService{
+				rates:      model.RateSchedule{IncrementMinutes: 15, RatePerIncrement: tt.rate},
+				clock:      func() time.Time { return tt.exit },
+				minCharge:  tt.minCharge,
+				surcharges: &surcharges{weekend: 0.2},
+			}
+
+			_, _, breakdown := service.CalculateChargeBreakdown(1, entry)
+			_, _, charge := service.CalculateChargeDetailed(1, entry)
+
+			assert.Equal(t, tt.expected, breakdown)
+			assert.Equal(t, charge, breakdown.Total)
+		})
+	}
+}
@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"parking-lot/internal/env"
+	"parking-lot/internal/logger"
+)
+
+// tablePollInterval is how often EnsureTable checks whether a new table became active
+var tablePollInterval = time.Second
+
+// tableActiveTimeout bounds how long EnsureTable waits for a new table
+const tableActiveTimeout = 2 * time.Minute
+
+// autoCreateTableEnabled reports whether the table should be created on startup.
+// It is only honoured outside of Lambda so a real deployment never creates tables.
+func autoCreateTableEnabled() bool {
+	return os.Getenv("AUTO_CREATE_TABLE") == "true" && !env.IsLambda()
+}
+
+// EnsureTable creates the tickets table with its indexes, and the events table when configured,
+// if they do not exist and waits until they are active. It is intended for DynamoDB Local during development.
+func (s *ParkingLotService) EnsureTable(ctx context.Context) error {
+	if err := s.ensureTable(ctx, ticketsTableInput(s.tableName, s.compositeKey)); err != nil {
+		return err
+	}
+	if s.eventsTableName != "" {
+		return s.ensureTable(ctx, eventsTableInput(s.eventsTableName))
+	}
+	return nil
+}
+
+// ensureTable creates a single table when it does not exist and waits until it is active
+func (s *ParkingLotService) ensureTable(ctx context.Context, input *dynamodb.CreateTableInput) error {
+	log := s.log.WithContext(ctx).WithFields(logger.Field{Key: "table_name", Value: aws.ToString(input.TableName)})
+
+	_, err := s.client.DescribeTable(ctx, &dynamodb.DescribeTableInput{
+		TableName: input.TableName,
+	})
+	if err == nil {
+		log.Info("Table already exists")
+		return nil
+	}
+	var notFound *types.ResourceNotFoundException
+	if !errors.As(err, &notFound) {
+		log.Error("Failed to describe table", logger.Field{Key: "error", Value: err.Error()})
+		return fmt.Errorf("failed to describe table: %w", err)
+	}
+
+	log.Info("Creating table")
+	if _, err := s.client.CreateTable(ctx, input); err != nil {
+		log.Error("Failed to create table", logger.Field{Key: "error", Value: err.Error()})
+		return fmt.Errorf("failed to create table: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, tableActiveTimeout)
+	defer cancel()
+
+	ticker := time.NewTicker(tablePollInterval)
+	defer ticker.Stop()
+
+	for {
+		out, err := s.client.DescribeTable(ctx, &dynamodb.DescribeTableInput{
+			TableName: input.TableName,
+		})
+		if err == nil && out.Table != nil && out.Table.TableStatus == types.TableStatusActive {
+			log.Info("Table is active")
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("timed out waiting for table to become active: %w", ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// ticketsTableInput describes the tickets table, mirroring deployment/main.tf.
+// With compositeKey the table is keyed by plate and entryTime, and PlateIndex and PlateEntryTimeIndex
+// give way to TicketIdIndex.
+func ticketsTableInput(tableName string, compositeKey bool) *dynamodb.CreateTableInput {
+	index := func(name, hashKey, rangeKey string) types.GlobalSecondaryIndex {
+		keySchema := []types.KeySchemaElement{
+			{AttributeName: aws.String(hashKey), KeyType: types.KeyTypeHash},
+		}
+		if rangeKey != "" {
+			keySchema = append(keySchema, types.KeySchemaElement{AttributeName: aws.String(rangeKey), KeyType: types.KeyTypeRange})
+		}
+		return types.GlobalSecondaryIndex{
+			IndexName:  aws.String(name),
+			KeySchema:  keySchema,
+			Projection: &types.Projection{ProjectionType: types.ProjectionTypeAll},
+		}
+	}
+
+	keySchema := []types.KeySchemaElement{
+		{AttributeName: aws.String("ticketId"), KeyType: types.KeyTypeHash},
+	}
+	lookupIndexes := []types.GlobalSecondaryIndex{
+		index(plateIndexName, "plate", ""),
+		index(plateEntryTimeIndexName, "plate", "entryTime"),
+	}
+	if compositeKey {
+		keySchema = []types.KeySchemaElement{
+			{AttributeName: aws.String("plate"), KeyType: types.KeyTypeHash},
+			{AttributeName: aws.String("entryTime"), KeyType: types.KeyTypeRange},
+		}
+		lookupIndexes = []types.GlobalSecondaryIndex{index(ticketIDIndexName, "ticketId", "")}
+	}
+
+	return &dynamodb.CreateTableInput{
+		TableName:   aws.String(tableName),
+		BillingMode: types.BillingModePayPerRequest,
+		AttributeDefinitions: []types.AttributeDefinition{
+			{AttributeName: aws.String("ticketId"), AttributeType: types.ScalarAttributeTypeS},
+			{AttributeName: aws.String("plate"), AttributeType: types.ScalarAttributeTypeS},
+			{AttributeName: aws.String("parkingLot"), AttributeType: types.ScalarAttributeTypeN},
+			{AttributeName: aws.String("entryTime"), AttributeType: types.ScalarAttributeTypeS},
+			{AttributeName: aws.String("status"), AttributeType: types.ScalarAttributeTypeS},
+			{AttributeName: aws.String("charge"), AttributeType: types.ScalarAttributeTypeN},
+			{AttributeName: aws.String("exitTime"), AttributeType: types.ScalarAttributeTypeS},
+			{AttributeName: aws.String("sessionId"), AttributeType: types.ScalarAttributeTypeS},
+			{AttributeName: aws.String("transponderId"), AttributeType: types.ScalarAttributeTypeS},
+		},
+		KeySchema: keySchema,
+		GlobalSecondaryIndexes: append(lookupIndexes,
+			index(parkingLotIndexName, "parkingLot", ""),
+			index("EntryTimeIndex", "entryTime", ""),
+			index(statusIndexName, "status", "charge"),
+			index(exitTimeIndexName, "status", "exitTime"),
+			index(sessionIndexName, "sessionId", ""),
+			index(transponderIndexName, "transponderId", "entryTime"),
+		),
+	}
+}
+
+// eventsTableInput describes the event log table, mirroring deployment/main.tf
+func eventsTableInput(tableName string) *dynamodb.CreateTableInput {
+	return &dynamodb.CreateTableInput{
+		TableName:   aws.String(tableName),
+		BillingMode: types.BillingModePayPerRequest,
+		AttributeDefinitions: []types.AttributeDefinition{
+			{AttributeName: aws.String("ticketId"), AttributeType: types.ScalarAttributeTypeS},
+			{AttributeName: aws.String("sequence"), AttributeType: types.ScalarAttributeTypeN},
+		},
+		KeySchema: []types.KeySchemaElement{
+			{AttributeName: aws.String("ticketId"), KeyType: types.KeyTypeHash},
+			{AttributeName: aws.String("sequence"), KeyType: types.KeyTypeRange},
+		},
+	}
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"parking-lot/internal/logger"
+	"parking-lot/internal/mocks"
+)
+
+// TestEnsureTable_CreatesMissingTable tests that a missing table is created and awaited
+func TestEnsureTable_CreatesMissingTable(t *testing.T) {
+	tablePollInterval = time.Millisecond
+	defer func() { tablePollInterval = time.Second }()
+
+	ctx := context.Background()
+	mockClient := new(mocks.DynamoDBClient)
+	service := &ParkingLotService{
+		ctx:       ctx,
+		client:    mockClient,
+		tableName: "testTable",
+		log:       logger.NewLogger(),
+	}
+
+	mockClient.On("DescribeTable", mock.Anything, mock.Anything, mock.Anything).
+		Return(nil, &types.ResourceNotFoundException{}).Once()
+	mockClient.On("CreateTable", mock.Anything, mock.MatchedBy(func(in *dynamodb.CreateTableInput) bool {
+		return *in.TableName == "testTable" && *in.KeySchema[0].AttributeName == "ticketId" && len(in.GlobalSecondaryIndexes) > 0
+	}), mock.Anything).Return(&dynamodb.CreateTableOutput{}, nil).Once()
+	mockClient.On("DescribeTable", mock.Anything, mock.Anything, mock.Anything).
+		Return(&dynamodb.DescribeTableOutput{Table: &types.TableDescription{TableStatus: types.TableStatusCreating}}, nil).Once()
+	mockClient.On("DescribeTable", mock.Anything, mock.Anything, mock.Anything).
+		Return(&dynamodb.DescribeTableOutput{Table: &types.TableDescription{TableStatus: types.TableStatusActive}}, nil).Once()
+
+	err := service.EnsureTable(ctx)
+
+	assert.NoError(t, err)
+	mockClient.AssertExpectations(t)
+}
+
+// TestEnsureTable_ExistingTable tests that an existing table is left alone
+func TestEnsureTable_ExistingTable(t *testing.T) {
+	ctx := context.Background()
+	mockClient := new(mocks.DynamoDBClient)
+	service := &ParkingLotService{
+		ctx:       ctx,
+		client:    mockClient,
+		tableName: "testTable",
+		log:       logger.NewLogger(),
+	}
+
+	mockClient.On("DescribeTable", mock.Anything, mock.Anything, mock.Anything).
+		Return(&dynamodb.DescribeTableOutput{Table: &types.TableDescription{TableStatus: types.TableStatusActive}}, nil).Once()
+
+	err := service.EnsureTable(ctx)
+
+	assert.NoError(t, err)
+	mockClient.AssertNotCalled(t, "CreateTable", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// TestAutoCreateTableEnabled tests that table creation never runs inside Lambda
+func TestAutoCreateTableEnabled(t *testing.T) {
+	t.Setenv("AUTO_CREATE_TABLE", "true")
+	t.Setenv("AWS_EXECUTION_ENV", "")
+	assert.True(t, autoCreateTableEnabled())
+
+	t.Setenv("AWS_EXECUTION_ENV", "AWS_Lambda_provided.al2")
+	assert.False(t, autoCreateTableEnabled())
+}
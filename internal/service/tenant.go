@@ -0,0 +1,55 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+)
+
+// TenantTableHeader names the request header selecting a tenant's tickets table when MULTI_TENANT=true
+const TenantTableHeader = "X-Tenant-Table"
+
+// ErrTableNotAllowed is returned when a requested tenant table is missing from TENANT_TABLES
+var ErrTableNotAllowed = errors.New("tenant table is not allowed")
+
+// TenantRouter is implemented by services that can route a request to a tenant's tickets table
+type TenantRouter interface {
+	// TenantContext returns a context whose ticket operations use tableName
+	TenantContext(ctx context.Context, tableName string) (context.Context, error)
+}
+
+// tableKey is the context key carrying a request's tenant table
+type tableKey struct{}
+
+// loadTenantTables parses TENANT_TABLES, a comma-separated list of the tables tenants may select
+func loadTenantTables() map[string]bool {
+	tables := make(map[string]bool)
+	for _, name := range strings.Split(os.Getenv("TENANT_TABLES"), ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			tables[name] = true
+		}
+	}
+	return tables
+}
+
+// TenantContext returns a context routing ticket reads and writes to tableName.
+// An empty name, or any name while multi-tenancy is disabled, keeps the default table;
+// a name missing from TENANT_TABLES fails with ErrTableNotAllowed.
+func (s *ParkingLotService) TenantContext(ctx context.Context, tableName string) (context.Context, error) {
+	if !s.multiTenant || tableName == "" {
+		return ctx, nil
+	}
+	if !s.tenantTables[tableName] {
+		return ctx, ErrTableNotAllowed
+	}
+	return context.WithValue(ctx, tableKey{}, tableName), nil
+}
+
+// table returns the tickets table for the request, honouring a tenant table set by TenantContext
+func (s *ParkingLotService) table(ctx context.Context) string {
+	if tableName, ok := ctx.Value(tableKey{}).(string); ok {
+		return tableName
+	}
+	return s.tableName
+}
@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"parking-lot/internal/logger"
+	"parking-lot/internal/mocks"
+)
+
+// TestTenantContext tests that ticket reads hit the tenant table only when multi-tenancy is enabled
+func TestTenantContext(t *testing.T) {
+	tests := []struct {
+		name          string
+		multiTenant   bool
+		header        string
+		expectedTable string
+		expectedErr   error
+	}{
+		{name: "Disabled ignores header", header: "tenantA", expectedTable: "parkingTickets"},
+		{name: "Enabled without header", multiTenant: true, expectedTable: "parkingTickets"},
+		{name: "Enabled with allowed table", multiTenant: true, header: "tenantA", expectedTable: "tenantA"},
+		{name: "Enabled with unlisted table", multiTenant: true, header: "other", expectedErr: ErrTableNotAllowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("MULTI_TENANT", "")
+			if tt.multiTenant {
+				t.Setenv("MULTI_TENANT", "true")
+			}
+			t.Setenv("TENANT_TABLES", "tenantA, tenantB")
+
+			service, err := newConfiguredService(context.Background(), logger.NewLogger())
+			assert.NoError(t, err)
+			mockClient := new(mocks.DynamoDBClient)
+			service.client = mockClient
+			service.tableName = "parkingTickets"
+			service.unmarshalMap = attributevalue.UnmarshalMap
+
+			ctx, err := service.TenantContext(context.Background(), tt.header)
+			if tt.expectedErr != nil {
+				assert.ErrorIs(t, err, tt.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+
+			mockClient.On("GetItem", ctx, mock.MatchedBy(func(input *dynamodb.GetItemInput) bool {
+				return *input.TableName == tt.expectedTable
+			}), mock.Anything).Return(&dynamodb.GetItemOutput{}, nil).Once()
+
+			service.GetTicket(ctx, "ticket-1")
+
+			mockClient.AssertExpectations(t)
+		})
+	}
+}
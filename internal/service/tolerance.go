@@ -0,0 +1,78 @@
+package service
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+const (
+	// defaultZeroChargeThreshold is the stay below which no charge applies, so a vehicle exiting
+	// the instant it entered is not billed
+	defaultZeroChargeThreshold = time.Microsecond
+	// defaultBoundaryEpsilon is taken off every stay before it is billed. Clock readings are not exact,
+	// so a stay measured at exactly 15 minutes may come out a hair over; the epsilon keeps it in the
+	// first increment instead of starting the second.
+	defaultBoundaryEpsilon = time.Millisecond
+)
+
+// chargeTolerances are the small durations calculateCharge uses to absorb clock imprecision
+type chargeTolerances struct {
+	// zeroChargeThreshold is the stay below which no charge applies
+	zeroChargeThreshold time.Duration
+	// boundaryEpsilon is taken off every stay before it is billed; zero bills exact boundaries
+	boundaryEpsilon time.Duration
+}
+
+// loadChargeTolerances reads ZERO_CHARGE_THRESHOLD and CHARGE_BOUNDARY_EPSILON, durations such as
+// "1ms"; it returns nil, which uses the defaults, when neither is set
+func loadChargeTolerances() (*chargeTolerances, error) {
+	threshold, thresholdSet := os.LookupEnv("ZERO_CHARGE_THRESHOLD")
+	epsilon, epsilonSet := os.LookupEnv("CHARGE_BOUNDARY_EPSILON")
+	if !thresholdSet && !epsilonSet {
+		return nil, nil
+	}
+
+	tolerances := &chargeTolerances{
+		zeroChargeThreshold: defaultZeroChargeThreshold,
+		boundaryEpsilon:     defaultBoundaryEpsilon,
+	}
+	if thresholdSet {
+		d, err := parseTolerance("ZERO_CHARGE_THRESHOLD", threshold)
+		if err != nil {
+			return nil, err
+		}
+		tolerances.zeroChargeThreshold = d
+	}
+	if epsilonSet {
+		d, err := parseTolerance("CHARGE_BOUNDARY_EPSILON", epsilon)
+		if err != nil {
+			return nil, err
+		}
+		tolerances.boundaryEpsilon = d
+	}
+	return tolerances, nil
+}
+
+// parseTolerance parses a non-negative duration read from the environment variable key
+func parseTolerance(key, raw string) (time.Duration, error) {
+	d, err := time.ParseDuration(raw)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %q: %w", key, raw, err)
+	}
+	if d < 0 {
+		return 0, fmt.Errorf("invalid %s %q: must not be negative", key, raw)
+	}
+	return d, nil
+}
+
+// chargeTolerances returns the configured tolerances, or the defaults when none are configured
+func (s *ParkingLotService) chargeTolerances() chargeTolerances {
+	if s.tolerances == nil {
+		return chargeTolerances{
+			zeroChargeThreshold: defaultZeroChargeThreshold,
+			boundaryEpsilon:     defaultBoundaryEpsilon,
+		}
+	}
+	return *s.tolerances
+}
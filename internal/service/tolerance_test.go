@@ -0,0 +1,92 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCalculateCharge_BoundaryEpsilon tests billing at the end of the first 15-minute increment
+// with the default epsilon and with the epsilon disabled for exact-boundary billing
+func TestCalculateCharge_BoundaryEpsilon(t *testing.T) {
+	entryTime := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name           string
+		tolerances     *chargeTolerances
+		duration       time.Duration
+		expectedCharge float32
+	}{
+		{"Epsilon at exactly 15:00", nil, 15 * time.Minute, 2.5},
+		{"Epsilon just past 15:00", nil, 15*time.Minute + 500*time.Microsecond, 2.5},
+		{"Epsilon past the epsilon", nil, 15*time.Minute + 2*time.Millisecond, 5.0},
+		{"Exact boundary at exactly 15:00", &chargeTolerances{zeroChargeThreshold: defaultZeroChargeThreshold}, 15 * time.Minute, 2.5},
+		{"Exact boundary just past 15:00", &chargeTolerances{zeroChargeThreshold: defaultZeroChargeThreshold}, 15*time.Minute + 500*time.Microsecond, 5.0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			service := &ParkingLotService{tolerances: tc.tolerances}
+
+			_, charge := service.CalculateChargeBetween(1, entryTime, entryTime.Add(tc.duration))
+
+			assert.Equal(t, tc.expectedCharge, charge)
+		})
+	}
+}
+
+// TestCalculateCharge_ZeroChargeThreshold tests that stays under the threshold are free
+func TestCalculateCharge_ZeroChargeThreshold(t *testing.T) {
+	entryTime := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+	service := &ParkingLotService{tolerances: &chargeTolerances{zeroChargeThreshold: time.Second, boundaryEpsilon: defaultBoundaryEpsilon}}
+
+	_, charge := service.CalculateChargeBetween(1, entryTime, entryTime.Add(500*time.Millisecond))
+	assert.Equal(t, float32(0), charge)
+
+	_, charge = service.CalculateChargeBetween(1, entryTime, entryTime.Add(time.Second))
+	assert.Equal(t, float32(2.5), charge)
+}
+
+// TestLoadChargeTolerances tests reading the tolerances from the environment
+func TestLoadChargeTolerances(t *testing.T) {
+	t.Run("Unset uses the defaults", func(t *testing.T) {
+		tolerances, err := loadChargeTolerances()
+
+		require.NoError(t, err)
+		assert.Nil(t, tolerances)
+		assert.Equal(t, chargeTolerances{
+			zeroChargeThreshold: defaultZeroChargeThreshold,
+			boundaryEpsilon:     defaultBoundaryEpsilon,
+		}, (&ParkingLotService{}).chargeTolerances())
+	})
+
+	t.Run("Epsilon disabled", func(t *testing.T) {
+		t.Setenv("CHARGE_BOUNDARY_EPSILON", "0")
+
+		tolerances, err := loadChargeTolerances()
+
+		require.NoError(t, err)
+		assert.Equal(t, &chargeTolerances{zeroChargeThreshold: defaultZeroChargeThreshold}, tolerances)
+	})
+
+	t.Run("Threshold", func(t *testing.T) {
+		t.Setenv("ZERO_CHARGE_THRESHOLD", "2s")
+
+		tolerances, err := loadChargeTolerances()
+
+		require.NoError(t, err)
+		assert.Equal(t, &chargeTolerances{zeroChargeThreshold: 2 * time.Second, boundaryEpsilon: defaultBoundaryEpsilon}, tolerances)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		for _, value := range []string{"soon", "-1ms"} {
+			t.Setenv("CHARGE_BOUNDARY_EPSILON", value)
+
+			_, err := loadChargeTolerances()
+
+			assert.Error(t, err, value)
+		}
+	})
+}
@@ -0,0 +1,194 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"parking-lot/internal/timing"
+)
+
+// tracer creates spans for DynamoDB calls; it is a no-op unless telemetry is enabled
+var tracer = otel.Tracer("parking-lot/internal/service")
+
+// tracedClient wraps a DynamoDBClient and records a span for every call.
+// Call durations are also added to the request's Server-Timing db metric.
+type tracedClient struct {
+	DynamoDBClient
+}
+
+// newTracedClient wraps client with tracing
+func newTracedClient(client DynamoDBClient) DynamoDBClient {
+	return &tracedClient{DynamoDBClient: client}
+}
+
+// PutItem traces the PutItem call
+func (c *tracedClient) PutItem(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error) {
+	ctx, span := startDynamoDBSpan(ctx, "PutItem", params.TableName)
+	defer span.End()
+	defer timing.FromContext(ctx).Since(timing.DB, time.Now())
+
+	out, err := c.DynamoDBClient.PutItem(ctx, params, optFns...)
+	recordSpanError(span, err)
+	return out, err
+}
+
+// GetItem traces the GetItem call
+func (c *tracedClient) GetItem(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error) {
+	ctx, span := startDynamoDBSpan(ctx, "GetItem", params.TableName)
+	defer span.End()
+	defer timing.FromContext(ctx).Since(timing.DB, time.Now())
+
+	out, err := c.DynamoDBClient.GetItem(ctx, params, optFns...)
+	recordSpanError(span, err)
+	return out, err
+}
+
+// DeleteItem traces the DeleteItem call
+func (c *tracedClient) DeleteItem(ctx context.Context, params *dynamodb.DeleteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DeleteItemOutput, error) {
+	ctx, span := startDynamoDBSpan(ctx, "DeleteItem", params.TableName)
+	defer span.End()
+	defer timing.FromContext(ctx).Since(timing.DB, time.Now())
+
+	out, err := c.DynamoDBClient.DeleteItem(ctx, params, optFns...)
+	recordSpanError(span, err)
+	return out, err
+}
+
+// UpdateItem traces the UpdateItem call
+func (c *tracedClient) UpdateItem(ctx context.Context, params *dynamodb.UpdateItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error) {
+	ctx, span := startDynamoDBSpan(ctx, "UpdateItem", params.TableName)
+	defer span.End()
+	defer timing.FromContext(ctx).Since(timing.DB, time.Now())
+
+	out, err := c.DynamoDBClient.UpdateItem(ctx, params, optFns...)
+	recordSpanError(span, err)
+	return out, err
+}
+
+// Query traces the Query call
+func (c *tracedClient) Query(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+	ctx, span := startDynamoDBSpan(ctx, "Query", params.TableName)
+	defer span.End()
+	defer timing.FromContext(ctx).Since(timing.DB, time.Now())
+
+	out, err := c.DynamoDBClient.Query(ctx, params, optFns...)
+	recordSpanError(span, err)
+	return out, err
+}
+
+// CreateTable traces the CreateTable call
+func (c *tracedClient) CreateTable(ctx context.Context, params *dynamodb.CreateTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.CreateTableOutput, error) {
+	ctx, span := startDynamoDBSpan(ctx, "CreateTable", params.TableName)
+	defer span.End()
+	defer timing.FromContext(ctx).Since(timing.DB, time.Now())
+
+	out, err := c.DynamoDBClient.CreateTable(ctx, params, optFns...)
+	recordSpanError(span, err)
+	return out, err
+}
+
+// DescribeTable traces the DescribeTable call
+func (c *tracedClient) DescribeTable(ctx context.Context, params *dynamodb.DescribeTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DescribeTableOutput, error) {
+	ctx, span := startDynamoDBSpan(ctx, "DescribeTable", params.TableName)
+	defer span.End()
+	defer timing.FromContext(ctx).Since(timing.DB, time.Now())
+
+	out, err := c.DynamoDBClient.DescribeTable(ctx, params, optFns...)
+	recordSpanError(span, err)
+	return out, err
+}
+
+// Scan traces the Scan call
+func (c *tracedClient) Scan(ctx context.Context, params *dynamodb.ScanInput, optFns ...func(*dynamodb.Options)) (*dynamodb.ScanOutput, error) {
+	ctx, span := startDynamoDBSpan(ctx, "Scan", params.TableName)
+	defer span.End()
+	defer timing.FromContext(ctx).Since(timing.DB, time.Now())
+
+	out, err := c.DynamoDBClient.Scan(ctx, params, optFns...)
+	recordSpanError(span, err)
+	return out, err
+}
+
+// BatchWriteItem traces the BatchWriteItem call
+func (c *tracedClient) BatchWriteItem(ctx context.Context, params *dynamodb.BatchWriteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.BatchWriteItemOutput, error) {
+	ctx, span := startDynamoDBSpan(ctx, "BatchWriteItem", batchTableName(params.RequestItems))
+	defer span.End()
+	defer timing.FromContext(ctx).Since(timing.DB, time.Now())
+
+	out, err := c.DynamoDBClient.BatchWriteItem(ctx, params, optFns...)
+	recordSpanError(span, err)
+	return out, err
+}
+
+// BatchGetItem traces the BatchGetItem call
+func (c *tracedClient) BatchGetItem(ctx context.Context, params *dynamodb.BatchGetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.BatchGetItemOutput, error) {
+	ctx, span := startDynamoDBSpan(ctx, "BatchGetItem", batchTableName(params.RequestItems))
+	defer span.End()
+	defer timing.FromContext(ctx).Since(timing.DB, time.Now())
+
+	out, err := c.DynamoDBClient.BatchGetItem(ctx, params, optFns...)
+	recordSpanError(span, err)
+	return out, err
+}
+
+// TransactWriteItems traces the TransactWriteItems call
+func (c *tracedClient) TransactWriteItems(ctx context.Context, params *dynamodb.TransactWriteItemsInput, optFns ...func(*dynamodb.Options)) (*dynamodb.TransactWriteItemsOutput, error) {
+	ctx, span := startDynamoDBSpan(ctx, "TransactWriteItems", transactTableName(params.TransactItems))
+	defer span.End()
+	defer timing.FromContext(ctx).Since(timing.DB, time.Now())
+
+	out, err := c.DynamoDBClient.TransactWriteItems(ctx, params, optFns...)
+	recordSpanError(span, err)
+	return out, err
+}
+
+// batchTableName returns the table a batch request targets; batches used here only ever target one table
+func batchTableName[T any](requestItems map[string]T) *string {
+	for tableName := range requestItems {
+		return aws.String(tableName)
+	}
+	return nil
+}
+
+// transactTableName returns the table of the first write of a transaction; transactions used here only ever target one table
+func transactTableName(items []types.TransactWriteItem) *string {
+	for _, item := range items {
+		switch {
+		case item.Put != nil:
+			return item.Put.TableName
+		case item.Delete != nil:
+			return item.Delete.TableName
+		case item.Update != nil:
+			return item.Update.TableName
+		case item.ConditionCheck != nil:
+			return item.ConditionCheck.TableName
+		}
+	}
+	return nil
+}
+
+func startDynamoDBSpan(ctx context.Context, operation string, tableName *string) (context.Context, trace.Span) {
+	return tracer.Start(ctx, "DynamoDB."+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system", "dynamodb"),
+			attribute.String("db.operation", operation),
+			attribute.String("aws.dynamodb.table_names", aws.ToString(tableName)),
+		),
+	)
+}
+
+func recordSpanError(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+}
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"parking-lot/internal/logger"
+	"parking-lot/internal/model"
+)
+
+// PlateTracker is implemented by services that can find every lot a plate is parked in
+type PlateTracker interface {
+	// ActivePlateTickets returns the active tickets of a plate across all lots, newest entry first
+	ActivePlateTickets(ctx context.Context, plate string) ([]*model.ParkingTicket, error)
+}
+
+// ActivePlateTickets queries the plate index for the tickets of a plate that are still parked,
+// in any lot
+func (s *ParkingLotService) ActivePlateTickets(ctx context.Context, plate string) ([]*model.ParkingTicket, error) {
+	log := s.log.WithContext(ctx).WithFields(logger.Plate(plate))
+	log.Info("Looking up active tickets of plate")
+
+	tickets, err := s.queryActivePlateTickets(ctx, plate, 0)
+	if err != nil {
+		log.Error("Failed to query plate index", logger.Field{Key: "error", Value: err.Error()})
+		return nil, err
+	}
+
+	log.Info("Found active tickets of plate", logger.Field{Key: "tickets", Value: len(tickets)})
+	return tickets, nil
+}
+
+// queryActivePlateTickets returns up to limit active tickets of a plate, newest entry first;
+// a zero limit returns all of them
+func (s *ParkingLotService) queryActivePlateTickets(ctx context.Context, plate string, limit int) ([]*model.ParkingTicket, error) {
+	input := &dynamodb.QueryInput{
+		TableName:              aws.String(s.table(ctx)),
+		IndexName:              aws.String(plateEntryTimeIndexName),
+		KeyConditionExpression: aws.String("plate = :plate"),
+		FilterExpression:       aws.String("#status = :status"),
+		ExpressionAttributeNames: map[string]string{
+			"#status": "status", // status is a reserved word
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":plate":  &types.AttributeValueMemberS{Value: plate},
+			":status": &types.AttributeValueMemberS{Value: string(model.TicketStatusIn)},
+		},
+		ScanIndexForward: aws.Bool(false),
+	}
+	if s.compositeKey {
+		// Plate is the partition key of composite-key tables, so query the table itself
+		input.IndexName = nil
+	}
+
+	// Filters apply after the key lookup, so keep paging until the plate's tickets are exhausted.
+	// MAX_SCAN_PAGES does not apply, since only one plate's tickets are read, not a lot's.
+	var tickets []*model.ParkingTicket
+	for {
+		result, err := s.client.Query(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query plate index: %w", err)
+		}
+
+		for _, item := range result.Items {
+			ticket := &model.ParkingTicket{}
+			if err := s.unmarshalMap(item, ticket); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal ticket: %w", err)
+			}
+			if ticket.Status != model.TicketStatusIn {
+				continue
+			}
+			tickets = append(tickets, ticket)
+			if len(tickets) == limit {
+				return tickets, nil
+			}
+		}
+
+		if len(result.LastEvaluatedKey) == 0 {
+			return tickets, nil
+		}
+		input.ExclusiveStartKey = result.LastEvaluatedKey
+	}
+}
+
+// ActivePlateTickets returns the tickets of a plate held in memory as parked, newest entry first
+func (m *MemoryParkingLotService) ActivePlateTickets(ctx context.Context, plate string) ([]*model.ParkingTicket, error) {
+	m.mu.Lock()
+	var tickets []*model.ParkingTicket
+	for _, ticket := range m.tickets {
+		if ticket.Plate == plate && ticket.Status == model.TicketStatusIn {
+			tickets = append(tickets, copyTicket(ticket))
+		}
+	}
+	m.mu.Unlock()
+
+	sort.Slice(tickets, func(i, j int) bool { return tickets[i].EntryTime.After(tickets[j].EntryTime) })
+	return tickets, nil
+}
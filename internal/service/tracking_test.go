@@ -0,0 +1,64 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"parking-lot/internal/mocks"
+)
+
+// TestActivePlateTickets tests that every parked ticket of a plate is read from the plate index
+func TestActivePlateTickets(t *testing.T) {
+	ctx := context.Background()
+	ticket := func(ticketID, parkingLot, status string) map[string]types.AttributeValue {
+		return map[string]types.AttributeValue{
+			"ticketId":   &types.AttributeValueMemberS{Value: ticketID},
+			"plate":      &types.AttributeValueMemberS{Value: "ABC-123"},
+			"parkingLot": &types.AttributeValueMemberN{Value: parkingLot},
+			"entryTime":  &types.AttributeValueMemberS{Value: "2025-03-01T09:00:00Z"},
+			"status":     &types.AttributeValueMemberS{Value: status},
+		}
+	}
+	plateQuery := mock.MatchedBy(func(in *dynamodb.QueryInput) bool {
+		return *in.IndexName == plateEntryTimeIndexName && *in.FilterExpression == "#status = :status"
+	})
+
+	t.Run("Active in two lots", func(t *testing.T) {
+		mockClient := new(mocks.DynamoDBClient)
+		service := newEventsTestService(mockClient)
+		// The page limit cuts the filtered results short, so the second page is read too
+		mockClient.On("Query", ctx, plateQuery, mock.Anything).Return(&dynamodb.QueryOutput{
+			Items:            []map[string]types.AttributeValue{ticket("t1", "4", "in")},
+			LastEvaluatedKey: map[string]types.AttributeValue{"ticketId": &types.AttributeValueMemberS{Value: "t1"}},
+		}, nil).Once()
+		mockClient.On("Query", ctx, plateQuery, mock.Anything).Return(&dynamodb.QueryOutput{
+			Items: []map[string]types.AttributeValue{ticket("t2", "9", "in")},
+		}, nil).Once()
+
+		tickets, err := service.ActivePlateTickets(ctx, "ABC-123")
+
+		require.NoError(t, err)
+		if assert.Len(t, tickets, 2) {
+			assert.Equal(t, 4, tickets[0].ParkingLot)
+			assert.Equal(t, 9, tickets[1].ParkingLot)
+		}
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run("Not parked anywhere", func(t *testing.T) {
+		mockClient := new(mocks.DynamoDBClient)
+		service := newEventsTestService(mockClient)
+		mockClient.On("Query", ctx, plateQuery, mock.Anything).Return(&dynamodb.QueryOutput{}, nil).Once()
+
+		tickets, err := service.ActivePlateTickets(ctx, "ABC-123")
+
+		require.NoError(t, err)
+		assert.Empty(t, tickets)
+	})
+}
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"parking-lot/internal/logger"
+	"parking-lot/internal/model"
+)
+
+// transponderIndexName is the sparse global secondary index keyed by transponder ID and sorted by entry time
+const transponderIndexName = "TransponderIndex"
+
+// TransponderLookup is implemented by services that can find a parked vehicle by its transponder
+type TransponderLookup interface {
+	// FindTransponderTicket returns the active ticket entered with the transponder, or nil when there is none
+	FindTransponderTicket(ctx context.Context, transponderID string) (*model.ParkingTicket, error)
+}
+
+// FindTransponderTicket queries the transponder index, newest entry first, for a ticket still in a lot.
+// Only tickets entered with a transponder carry the attribute, so the index stays small.
+func (s *ParkingLotService) FindTransponderTicket(ctx context.Context, transponderID string) (*model.ParkingTicket, error) {
+	log := s.log.WithContext(ctx).WithFields(logger.Field{Key: "transponder_id", Value: transponderID})
+	log.Info("Looking up active ticket by transponder")
+
+	input := &dynamodb.QueryInput{
+		TableName:              aws.String(s.table(ctx)),
+		IndexName:              aws.String(transponderIndexName),
+		KeyConditionExpression: aws.String("transponderId = :transponderId"),
+		FilterExpression:       aws.String("#status = :status"),
+		ExpressionAttributeNames: map[string]string{
+			"#status": "status", // status is a reserved word
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":transponderId": &types.AttributeValueMemberS{Value: transponderID},
+			":status":        &types.AttributeValueMemberS{Value: string(model.TicketStatusIn)},
+		},
+		ScanIndexForward: aws.Bool(false),
+	}
+
+	// Filters apply after the key lookup, so keep paging until a match is found
+	for {
+		result, err := s.client.Query(ctx, input)
+		if err != nil {
+			log.Error("Failed to query transponder index", logger.Field{Key: "error", Value: err.Error()})
+			return nil, fmt.Errorf("failed to query transponder index: %w", err)
+		}
+
+		if len(result.Items) > 0 {
+			ticket := &model.ParkingTicket{}
+			if err := s.unmarshalMap(result.Items[0], ticket); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal ticket: %w", err)
+			}
+			log.Info("Found active ticket", logger.Field{Key: "ticket_id", Value: ticket.TicketID})
+			return ticket, nil
+		}
+
+		if len(result.LastEvaluatedKey) == 0 {
+			log.Info("No active ticket found")
+			return nil, nil
+		}
+		input.ExclusiveStartKey = result.LastEvaluatedKey
+	}
+}
+
+// FindTransponderTicket returns the active ticket held in memory that was entered with the transponder
+func (m *MemoryParkingLotService) FindTransponderTicket(ctx context.Context, transponderID string) (*model.ParkingTicket, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, ticket := range m.tickets {
+		if ticket.TransponderID == transponderID && ticket.Status == model.TicketStatusIn {
+			return copyTicket(ticket), nil
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,52 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"parking-lot/internal/logger"
+	"parking-lot/internal/mocks"
+	"parking-lot/internal/model"
+)
+
+// TestFindTransponderTicket tests that the transponder index is queried for a parked vehicle
+func TestFindTransponderTicket(t *testing.T) {
+	ctx := context.Background()
+	mockClient := new(mocks.DynamoDBClient)
+	service := &ParkingLotService{
+		ctx:          ctx,
+		client:       mockClient,
+		tableName:    "testTable",
+		log:          logger.NewLogger(),
+		marshalMap:   attributevalue.MarshalMap,
+		unmarshalMap: attributevalue.UnmarshalMap,
+	}
+	item, err := attributevalue.MarshalMap(&model.ParkingTicket{TicketID: "t1", TransponderID: "TRP-0042", Status: model.TicketStatusIn})
+	require.NoError(t, err)
+
+	mockClient.On("Query", ctx, mock.MatchedBy(func(in *dynamodb.QueryInput) bool {
+		return *in.IndexName == transponderIndexName &&
+			in.ExpressionAttributeValues[":transponderId"].(*types.AttributeValueMemberS).Value == "TRP-0042" &&
+			in.ExclusiveStartKey == nil
+	}), mock.Anything).Return(&dynamodb.QueryOutput{
+		LastEvaluatedKey: map[string]types.AttributeValue{"ticketId": &types.AttributeValueMemberS{Value: "t0"}},
+	}, nil).Once()
+	mockClient.On("Query", ctx, mock.MatchedBy(func(in *dynamodb.QueryInput) bool {
+		return in.ExclusiveStartKey != nil
+	}), mock.Anything).Return(&dynamodb.QueryOutput{Items: []map[string]types.AttributeValue{item}}, nil).Once()
+
+	ticket, err := service.FindTransponderTicket(ctx, "TRP-0042")
+
+	require.NoError(t, err)
+	if assert.NotNil(t, ticket) {
+		assert.Equal(t, "t1", ticket.TicketID)
+	}
+	mockClient.AssertExpectations(t)
+}
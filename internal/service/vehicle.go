@@ -0,0 +1,22 @@
+package service
+
+import (
+	"context"
+
+	"parking-lot/internal/model"
+)
+
+// vehicleKey is the context key carrying the vehicle described at entry
+type vehicleKey struct{}
+
+// WithVehicle returns a context whose created tickets record the vehicle's make, model, color and transponder
+func WithVehicle(ctx context.Context, vehicle model.Vehicle) context.Context {
+	return context.WithValue(ctx, vehicleKey{}, vehicle)
+}
+
+// describeVehicle records the vehicle set by WithVehicle, if any, on a new ticket
+func describeVehicle(ctx context.Context, ticket *model.ParkingTicket) {
+	if vehicle, ok := ctx.Value(vehicleKey{}).(model.Vehicle); ok {
+		ticket.SetVehicle(vehicle)
+	}
+}
@@ -0,0 +1,94 @@
+// Package telemetry provides optional OpenTelemetry tracing and metrics export
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// ServiceName is reported as the service.name resource attribute
+const ServiceName = "parking-lot"
+
+// Telemetry owns the OpenTelemetry providers installed by Setup
+type Telemetry struct {
+	tracerProvider *sdktrace.TracerProvider
+	meterProvider  *sdkmetric.MeterProvider
+}
+
+// Setup installs global OTLP trace and metric providers when OTEL_EXPORTER_OTLP_ENDPOINT is set.
+// Without an endpoint nothing is installed and the global no-op providers stay in place,
+// so instrumented code can always use otel.Tracer and otel.Meter.
+func Setup(ctx context.Context) (*Telemetry, error) {
+	if os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") == "" {
+		return &Telemetry{}, nil
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(
+		attribute.String("service.name", ServiceName),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create telemetry resource: %w", err)
+	}
+
+	// The exporters read the endpoint and headers from the standard OTEL_* variables
+	traceExporter, err := otlptracehttp.New(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP trace exporter: %w", err)
+	}
+	metricExporter, err := otlpmetrichttp.New(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP metric exporter: %w", err)
+	}
+
+	t := &Telemetry{
+		tracerProvider: sdktrace.NewTracerProvider(
+			sdktrace.WithBatcher(traceExporter),
+			sdktrace.WithResource(res),
+		),
+		meterProvider: sdkmetric.NewMeterProvider(
+			sdkmetric.WithReader(sdkmetric.NewPeriodicReader(metricExporter)),
+			sdkmetric.WithResource(res),
+		),
+	}
+	otel.SetTracerProvider(t.tracerProvider)
+	otel.SetMeterProvider(t.meterProvider)
+
+	return t, nil
+}
+
+// Enabled reports whether telemetry is being exported
+func (t *Telemetry) Enabled() bool {
+	return t != nil && t.tracerProvider != nil
+}
+
+// Flush exports all buffered spans and metrics
+func (t *Telemetry) Flush(ctx context.Context) error {
+	if !t.Enabled() {
+		return nil
+	}
+	return errors.Join(
+		t.tracerProvider.ForceFlush(ctx),
+		t.meterProvider.ForceFlush(ctx),
+	)
+}
+
+// Shutdown flushes and stops the providers
+func (t *Telemetry) Shutdown(ctx context.Context) error {
+	if !t.Enabled() {
+		return nil
+	}
+	return errors.Join(
+		t.tracerProvider.Shutdown(ctx),
+		t.meterProvider.Shutdown(ctx),
+	)
+}
@@ -0,0 +1,36 @@
+package telemetry
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSetup_Disabled tests that telemetry is a no-op without an endpoint
+func TestSetup_Disabled(t *testing.T) {
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "")
+
+	tel, err := Setup(context.Background())
+
+	require.NoError(t, err)
+	assert.False(t, tel.Enabled())
+	assert.NoError(t, tel.Flush(context.Background()))
+	assert.NoError(t, tel.Shutdown(context.Background()))
+}
+
+// TestSetup_Enabled tests that the OTLP providers are created when an endpoint is set
+func TestSetup_Enabled(t *testing.T) {
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "http://127.0.0.1:4318")
+
+	tel, err := Setup(context.Background())
+
+	require.NoError(t, err)
+	assert.True(t, tel.Enabled())
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	_ = tel.Shutdown(ctx) // nothing is listening, export errors are expected
+}
@@ -0,0 +1,131 @@
+// Package timing measures where request time is spent and reports it in the Server-Timing header
+package timing
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HeaderName is the response header carrying the measurements
+const HeaderName = "Server-Timing"
+
+// DB is the metric name for time spent in DynamoDB calls
+const DB = "db"
+
+// contextKey is a custom type for context keys to avoid collisions
+type contextKey string
+
+const recorderKey contextKey = "timingRecorder"
+
+// Recorder accumulates durations for a single request
+type Recorder struct {
+	mu      sync.Mutex
+	start   time.Time
+	names   []string
+	metrics map[string]time.Duration
+}
+
+// NewContext returns a context carrying a new Recorder started now
+func NewContext(ctx context.Context) (context.Context, *Recorder) {
+	r := &Recorder{
+		start:   time.Now(),
+		names:   []string{DB},
+		metrics: map[string]time.Duration{DB: 0},
+	}
+	return context.WithValue(ctx, recorderKey, r), r
+}
+
+// FromContext returns the request's Recorder, or nil when the request is not being timed
+func FromContext(ctx context.Context) *Recorder {
+	r, _ := ctx.Value(recorderKey).(*Recorder)
+	return r
+}
+
+// Add adds d to the named metric; it is a no-op on a nil Recorder
+func (r *Recorder) Add(name string, d time.Duration) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.metrics[name]; !ok {
+		r.names = append(r.names, name)
+	}
+	r.metrics[name] += d
+}
+
+// Since adds the time elapsed since start to the named metric, e.g. defer r.Since(timing.DB, time.Now())
+func (r *Recorder) Since(name string, start time.Time) {
+	r.Add(name, time.Since(start))
+}
+
+// Header formats the recorded metrics and the total request time, e.g. "db;dur=12.3, total;dur=45.6"
+func (r *Recorder) Header() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	parts := make([]string, 0, len(r.names)+1)
+	for _, name := range r.names {
+		parts = append(parts, formatMetric(name, r.metrics[name]))
+	}
+	parts = append(parts, formatMetric("total", time.Since(r.start)))
+	return strings.Join(parts, ", ")
+}
+
+// formatMetric renders a duration in milliseconds as Server-Timing expects
+func formatMetric(name string, d time.Duration) string {
+	return fmt.Sprintf("%s;dur=%.1f", name, float64(d.Microseconds())/1000)
+}
+
+// Middleware times every request and adds the Server-Timing header to its response.
+// The header is written just before the response headers are sent, since Gin
+// cannot change them once the handler has written the body.
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, r := NewContext(c.Request.Context())
+		c.Request = c.Request.WithContext(ctx)
+		w := &responseWriter{ResponseWriter: c.Writer, recorder: r}
+		c.Writer = w
+
+		c.Next()
+
+		// Gin flushes bodiless responses after the middleware chain returns
+		w.setHeader()
+	}
+}
+
+// responseWriter sets the Server-Timing header the first time the response is written
+type responseWriter struct {
+	gin.ResponseWriter
+	recorder *Recorder
+}
+
+func (w *responseWriter) setHeader() {
+	if !w.Written() {
+		w.Header().Set(HeaderName, w.recorder.Header())
+	}
+}
+
+// WriteHeaderNow sets the timing header before sending the status
+func (w *responseWriter) WriteHeaderNow() {
+	w.setHeader()
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+// Write sets the timing header before sending the body
+func (w *responseWriter) Write(data []byte) (int, error) {
+	w.setHeader()
+	return w.ResponseWriter.Write(data)
+}
+
+// WriteString sets the timing header before sending the body
+func (w *responseWriter) WriteString(s string) (int, error) {
+	w.setHeader()
+	return w.ResponseWriter.WriteString(s)
+}
@@ -0,0 +1,68 @@
+package timing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecorder_Header(t *testing.T) {
+	_, r := NewContext(context.Background())
+	r.Add(DB, 12*time.Millisecond+300*time.Microsecond)
+	r.Add(DB, 1*time.Millisecond)
+	r.Add("cache", 500*time.Microsecond)
+
+	assert.Regexp(t, `^db;dur=13\.3, cache;dur=0\.5, total;dur=\d+\.\d$`, r.Header())
+}
+
+func TestFromContext_NotTimed(t *testing.T) {
+	r := FromContext(context.Background())
+
+	assert.Nil(t, r)
+	assert.NotPanics(t, func() { r.Add(DB, time.Millisecond) })
+}
+
+func TestMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	testCases := []struct {
+		name    string
+		handler gin.HandlerFunc
+		status  int
+	}{
+		{
+			name: "JSON body",
+			handler: func(c *gin.Context) {
+				FromContext(c.Request.Context()).Add(DB, 2*time.Millisecond)
+				c.JSON(http.StatusOK, gin.H{"ok": true})
+			},
+			status: http.StatusOK,
+		},
+		{
+			name: "No content",
+			handler: func(c *gin.Context) {
+				c.Status(http.StatusNoContent)
+			},
+			status: http.StatusNoContent,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			router := gin.New()
+			router.Use(Middleware())
+			router.GET("/", tc.handler)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+
+			assert.Equal(t, tc.status, w.Code)
+			assert.Regexp(t, `^db;dur=\d+\.\d, total;dur=\d+\.\d$`, w.Header().Get(HeaderName))
+		})
+	}
+}
@@ -0,0 +1,179 @@
+// Package webhook delivers signed entry and exit events to an integrator's HTTP endpoint
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"parking-lot/internal/logger"
+)
+
+// SignatureHeader carries the hex HMAC-SHA256 of the request body, keyed with WEBHOOK_SECRET
+const SignatureHeader = "X-Parking-Signature"
+
+// Delivery settings
+const (
+	requestTimeout = 5 * time.Second
+	maxAttempts    = 3
+)
+
+// retryBackoff is the wait before the first retry; it doubles after each failed attempt
+var retryBackoff = 500 * time.Millisecond
+
+// EventType identifies what happened to a ticket
+type EventType string
+
+// Event types sent to the webhook
+const (
+	EventEntry EventType = "entry"
+	EventExit  EventType = "exit"
+)
+
+// Event is the JSON payload posted to the webhook
+type Event struct {
+	Type            EventType `json:"type"`
+	TicketID        string    `json:"ticketId"`
+	Plate           string    `json:"plate"`
+	ParkingLot      int       `json:"parkingLot"`
+	Time            time.Time `json:"time"`
+	Charge          *float32  `json:"charge,omitempty"`
+	DurationMinutes *int      `json:"durationMinutes,omitempty"`
+}
+
+// Notifier posts events to a webhook URL in the background
+type Notifier struct {
+	url    string
+	secret []byte
+	client *http.Client
+	log    logger.Logger
+	wg     sync.WaitGroup
+}
+
+// NewNotifier creates a notifier posting to url and signing with secret
+func NewNotifier(url, secret string, log logger.Logger) *Notifier {
+	return &Notifier{
+		url:    url,
+		secret: []byte(secret),
+		client: &http.Client{Timeout: requestTimeout},
+		log:    log,
+	}
+}
+
+// NewNotifierFromEnv creates a notifier from WEBHOOK_URL and WEBHOOK_SECRET.
+// It returns nil when WEBHOOK_URL is unset, and logs an error and returns nil when WEBHOOK_SECRET
+// is unset, since payloads signed with an empty key would prove nothing to the receiver.
+func NewNotifierFromEnv(log logger.Logger) *Notifier {
+	url := os.Getenv("WEBHOOK_URL")
+	if url == "" {
+		return nil
+	}
+	secret := os.Getenv("WEBHOOK_SECRET")
+	if secret == "" {
+		log.Error("WEBHOOK_URL is set without WEBHOOK_SECRET, webhooks are disabled")
+		return nil
+	}
+	return NewNotifier(url, secret, log)
+}
+
+// Notify delivers the event asynchronously. Delivery failures are logged and never returned.
+// It is safe to call on a nil notifier.
+func (n *Notifier) Notify(event Event) {
+	if n == nil {
+		return
+	}
+
+	body, err := json.Marshal(event)
+	if err != nil {
+		n.log.Error("Failed to marshal webhook event", logger.Field{Key: "error", Value: err.Error()})
+		return
+	}
+
+	n.wg.Add(1)
+	go func() {
+		defer n.wg.Done()
+		n.deliver(event, body)
+	}()
+}
+
+// Wait blocks until all pending deliveries have finished
+func (n *Notifier) Wait() {
+	if n == nil {
+		return
+	}
+	n.wg.Wait()
+}
+
+// Close waits for pending deliveries, so none are lost when the process is stopped or frozen.
+// The notifier stays usable afterwards.
+func (n *Notifier) Close() error {
+	n.Wait()
+	return nil
+}
+
+// deliver posts the body, retrying network errors and server errors with exponential backoff
+func (n *Notifier) deliver(event Event, body []byte) {
+	log := n.log.WithFields(
+		logger.Field{Key: "event_type", Value: string(event.Type)},
+		logger.Field{Key: "ticket_id", Value: event.TicketID},
+	)
+
+	backoff := retryBackoff
+	for attempt := 1; ; attempt++ {
+		retryable, err := n.post(body)
+		if err == nil {
+			log.Debug("Webhook delivered", logger.Field{Key: "attempt", Value: attempt})
+			return
+		}
+		if !retryable || attempt == maxAttempts {
+			log.Error("Failed to deliver webhook",
+				logger.Field{Key: "attempts", Value: attempt},
+				logger.Field{Key: "error", Value: err.Error()},
+			)
+			return
+		}
+		log.Warn("Webhook delivery failed, retrying",
+			logger.Field{Key: "attempt", Value: attempt},
+			logger.Field{Key: "error", Value: err.Error()},
+		)
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+// post sends a single signed request and reports whether a failure is worth retrying
+func (n *Notifier) post(body []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return false, fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(SignatureHeader, Sign(n.secret, body))
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return true, fmt.Errorf("failed to post webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		// Client errors will not succeed on retry
+		return resp.StatusCode >= 500, fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+	return false, nil
+}
+
+// Sign returns the signature header value for body, e.g. "sha256=3f2a..."
+func Sign(secret, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
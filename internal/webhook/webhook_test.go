@@ -0,0 +1,141 @@
+package webhook
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"parking-lot/internal/mocks"
+)
+
+// TestNotify tests that the webhook receives the signed event payload
+func TestNotify(t *testing.T) {
+	var (
+		body      []byte
+		signature string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		signature = r.Header.Get(SignatureHeader)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	charge := float32(7.5)
+	minutes := 45
+	exitTime := time.Date(2024, 5, 1, 10, 45, 0, 0, time.UTC)
+
+	notifier := NewNotifier(server.URL, "s3cret", mocks.NewLogger())
+	notifier.Notify(Event{
+		Type:            EventExit,
+		TicketID:        "ticket-1",
+		Plate:           "ABC-123",
+		ParkingLot:      382,
+		Time:            exitTime,
+		Charge:          &charge,
+		DurationMinutes: &minutes,
+	})
+	notifier.Wait()
+
+	assert.JSONEq(t, `{
+		"type": "exit",
+		"ticketId": "ticket-1",
+		"plate": "ABC-123",
+		"parkingLot": 382,
+		"time": "2024-05-01T10:45:00Z",
+		"charge": 7.5,
+		"durationMinutes": 45
+	}`, string(body))
+	assert.Equal(t, Sign([]byte("s3cret"), body), signature)
+	assert.Regexp(t, "^sha256=[0-9a-f]{64}$", signature)
+}
+
+// TestNotify_Retries tests that server errors are retried and client errors are not
+func TestNotify_Retries(t *testing.T) {
+	defer func(backoff time.Duration) { retryBackoff = backoff }(retryBackoff)
+	retryBackoff = time.Millisecond
+
+	tests := []struct {
+		name          string
+		status        int
+		expectedCalls int32
+		expectedLog   string
+	}{
+		{name: "Server error", status: http.StatusBadGateway, expectedCalls: maxAttempts, expectedLog: "Failed to deliver webhook"},
+		{name: "Client error", status: http.StatusBadRequest, expectedCalls: 1, expectedLog: "Failed to deliver webhook"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls.Add(1)
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			log := mocks.NewLogger()
+			notifier := NewNotifier(server.URL, "s3cret", log)
+			notifier.Notify(Event{Type: EventEntry, TicketID: "ticket-1"})
+			notifier.Wait()
+
+			assert.Equal(t, tt.expectedCalls, calls.Load())
+			assert.Len(t, log.Find(tt.expectedLog), 1)
+		})
+	}
+}
+
+// TestNotify_RecoversAfterRetry tests that a transient failure is retried until delivered
+func TestNotify_RecoversAfterRetry(t *testing.T) {
+	defer func(backoff time.Duration) { retryBackoff = backoff }(retryBackoff)
+	retryBackoff = time.Millisecond
+
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		var event Event
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&event))
+		assert.Equal(t, EventEntry, event.Type)
+	}))
+	defer server.Close()
+
+	log := mocks.NewLogger()
+	notifier := NewNotifier(server.URL, "", log)
+	notifier.Notify(Event{Type: EventEntry, TicketID: "ticket-1"})
+	notifier.Wait()
+
+	assert.Equal(t, int32(2), calls.Load())
+	assert.Len(t, log.Find("Webhook delivery failed, retrying"), 1)
+	assert.Empty(t, log.Find("Failed to deliver webhook"))
+}
+
+// TestNewNotifierFromEnv tests that the notifier is only created when WEBHOOK_URL and WEBHOOK_SECRET are set
+func TestNewNotifierFromEnv(t *testing.T) {
+	t.Setenv("WEBHOOK_URL", "")
+	t.Setenv("WEBHOOK_SECRET", "secret")
+	assert.Nil(t, NewNotifierFromEnv(mocks.NewLogger()))
+
+	// A nil notifier ignores events
+	var notifier *Notifier
+	notifier.Notify(Event{Type: EventEntry})
+	notifier.Wait()
+
+	t.Setenv("WEBHOOK_URL", "http://localhost:9000/hook")
+	assert.NotNil(t, NewNotifierFromEnv(mocks.NewLogger()))
+
+	// Without a secret the signature would prove nothing, so delivery is disabled
+	t.Setenv("WEBHOOK_SECRET", "")
+	log := mocks.NewLogger()
+	assert.Nil(t, NewNotifierFromEnv(log))
+	assert.Len(t, log.Find("WEBHOOK_URL is set without WEBHOOK_SECRET, webhooks are disabled"), 1)
+}
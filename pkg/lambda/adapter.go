@@ -2,9 +2,18 @@ package lambda
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 
@@ -16,92 +25,283 @@ import (
 	"parking-lot/internal/handler"
 	"parking-lot/internal/logger"
 	"parking-lot/internal/service"
+	"parking-lot/internal/telemetry"
+	"parking-lot/internal/timing"
 	"parking-lot/server/api"
+	"parking-lot/spec"
 )
 
-// contextKey is a custom type for context keys to avoid collisions
-type contextKey string
-
-// Context keys
-const (
-	requestIDKey contextKey = "requestID"
+var (
+	// coldStart reports whether the next proxied request is the first one handled by this process
+	coldStart atomic.Bool
+	// initDuration is how long the most recent NewAPIAdapter call took
+	initDuration atomic.Int64
 )
 
 // APIAdapter handles the integration with AWS Lambda
 type APIAdapter struct {
-	router *gin.Engine
-	log    logger.Logger
+	router    *gin.Engine
+	log       logger.Logger
+	telemetry *telemetry.Telemetry
+	service   service.ParkingLotServicer
+
+	// dumpEvents logs every redacted API Gateway event at debug level, for diagnosing integration issues
+	dumpEvents bool
+
+	// inFlight counts the requests the local server is handling
+	inFlight atomic.Int64
+	// shutdownTimeout is how long RunLocalServer waits for in-flight requests; zero uses defaultShutdownTimeout
+	shutdownTimeout time.Duration
+
+	// closers are closed by Cleanup, in the order they were registered
+	closersMu sync.Mutex
+	closers   []io.Closer
 }
 
+// flushTimeout bounds the telemetry export after each invocation, and cleanupTimeout everything
+// Cleanup does at shutdown
+const (
+	flushTimeout   = time.Second
+	cleanupTimeout = 2 * time.Second
+)
+
+// defaultShutdownTimeout is how long the local server drains in-flight requests before forcing connections closed
+const defaultShutdownTimeout = 5 * time.Second
+
 // NewAPIAdapter creates a new API adapter for Lambda
 func NewAPIAdapter() *APIAdapter {
+	start := time.Now()
+	coldStart.Store(true)
+	defer func() {
+		initDuration.Store(int64(time.Since(start)))
+	}()
+
 	// Initialize logger
 	log := logger.NewLogger()
 	log.Info("Initializing Lambda API adapter")
 
+	// Set up OpenTelemetry export; a no-op unless OTEL_EXPORTER_OTLP_ENDPOINT is set
+	tel, err := telemetry.Setup(context.Background())
+	if err != nil {
+		log.Error("Failed to set up telemetry", logger.Field{Key: "error", Value: err.Error()})
+	}
+
 	// Set Gin to release mode
 	gin.SetMode(gin.ReleaseMode)
 
-	// Create Gin router
+	// Create service and handler
+	var parkingService service.ParkingLotServicer
+	dynamoService, err := service.NewParkingLotServiceWithLogger(context.Background(), log)
+	if errors.Is(err, service.ErrInvalidConfig) {
+		// A setting is missing or malformed, so falling back to memory would hide the misconfiguration
+		log.Fatal("Error creating DynamoDB service", logger.Field{Key: "error", Value: err.Error()})
+	}
+	if err != nil {
+		// Log the error and create a fallback in-memory service for development
+		log.Error("Error creating DynamoDB service, falling back to in-memory",
+			logger.Field{Key: "error", Value: err.Error()})
+		memoryService, memErr := service.NewMemoryParkingLotServiceWithLogger(context.Background(), log)
+		if memErr != nil {
+			log.Fatal("Error creating in-memory service", logger.Field{Key: "error", Value: memErr.Error()})
+		}
+		parkingService = memoryService
+	} else {
+		parkingService = dynamoService
+	}
+	parkingHandler := handler.NewParkingHandler(parkingService)
+
+	// Create Gin router; panics are answered with the handler's error format
 	router := gin.New()
-	router.Use(gin.Recovery())
+	router.Use(gin.CustomRecovery(parkingHandler.Recover))
+
+	// Trim query parameters before anything reads them
+	router.Use(trimQuery)
 
 	// Add request ID middleware
-	router.Use(func(c *gin.Context) {
-		requestID := c.GetHeader("X-Request-ID")
-		if requestID == "" {
-			requestID = uuid.New().String()
-			c.Header("X-Request-ID", requestID)
+	router.Use(requestIDMiddleware)
+
+	// Report request and DynamoDB durations in the Server-Timing header
+	router.Use(timing.Middleware())
+
+	// Add logging middleware
+	router.Use(requestLogger(log))
+
+	router.NoRoute(parkingHandler.NotFound)
+
+	// Route requests to a tenant's table when MULTI_TENANT is enabled
+	router.Use(parkingHandler.TenantMiddleware())
+
+	// Log one completion line with a stable schema per entry and exit
+	router.Use(parkingHandler.OperationLogMiddleware())
+
+	quoteMethod, err := parseQuoteMethod(os.Getenv("QUOTE_METHOD"))
+	if err != nil {
+		log.Fatal("Invalid quote method", logger.Field{Key: "error", Value: err.Error()})
+	}
+
+	// Validate entry, exit and quote parameters against the OpenAPI spec, with GET /quote
+	// validated like the spec's POST /quote when QUOTE_METHOD=GET
+	var aliases map[string]string
+	if quoteMethod == http.MethodGet {
+		aliases = map[string]string{"GET /quote": "POST /quote"}
+	}
+	validator, err := parkingHandler.RequestValidator(spec.OpenAPI, os.Getenv("API_BASE_PATH"), aliases, "/entry", "/exit", "/quote")
+	if err != nil {
+		log.Fatal("Error loading OpenAPI spec", logger.Field{Key: "error", Value: err.Error()})
+	}
+	router.Use(validator)
+
+	// Register API handlers, with /quote also served on GET when QUOTE_METHOD=GET
+	registerRoutes(router, parkingHandler, os.Getenv("API_BASE_PATH"), quoteMethod)
+
+	// Create the Lambda adapter
+	adapter := &APIAdapter{
+		log:        log,
+		router:     router,
+		telemetry:  tel,
+		service:    parkingService,
+		dumpEvents: os.Getenv("DEBUG_DUMP_EVENT") == "true",
+	}
+
+	// Flush pending webhook deliveries and Kinesis records on cleanup
+	for _, closer := range parkingHandler.Closers() {
+		adapter.RegisterCloser(closer)
+	}
+	return adapter
+}
+
+// trimQuery trims whitespace around query parameter values, as ProxyRequest.Query does, so that
+// e.g. a ticket ID pasted with a trailing space still parses. It must run before anything reads
+// the query, since Gin caches the parsed parameters.
+func trimQuery(c *gin.Context) {
+	query := c.Request.URL.Query()
+	trimmed := false
+	for _, values := range query {
+		for i, value := range values {
+			if values[i] = strings.TrimSpace(value); values[i] != value {
+				trimmed = true
+			}
 		}
+	}
+	if trimmed {
+		c.Request.URL.RawQuery = query.Encode()
+	}
+	c.Next()
+}
 
-		// Store the request ID in the context
-		ctx := context.WithValue(c.Request.Context(), requestIDKey, requestID)
-		c.Request = c.Request.WithContext(ctx)
+// requestIDMiddleware stores the request's X-Request-ID, or a generated one, in the request context,
+// so handler and service logs derived with WithContext carry the same request ID
+func requestIDMiddleware(c *gin.Context) {
+	requestID := c.GetHeader("X-Request-ID")
+	if requestID == "" {
+		requestID = uuid.New().String()
+		c.Header("X-Request-ID", requestID)
+	}
 
-		c.Next()
-	})
+	ctx := logger.ContextWithRequestID(c.Request.Context(), requestID)
+	c.Request = c.Request.WithContext(ctx)
 
-	// Add logging middleware
-	router.Use(func(c *gin.Context) {
+	c.Next()
+}
+
+// requestLogger logs the start and completion of every request.
+// Sensitive headers such as Authorization are redacted before they reach the logs.
+func requestLogger(log logger.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
 		reqLog := log.WithContext(c.Request.Context()).WithFields(
 			logger.Field{Key: "method", Value: c.Request.Method},
-			logger.Field{Key: "path", Value: c.Request.URL.Path},
+			logger.Path(c.Request.URL.Path),
 			logger.Field{Key: "client_ip", Value: c.ClientIP()},
 		)
 
-		reqLog.Info("Request started")
+		reqLog.Info("Request started", logger.Headers(c.Request.Header))
 
 		c.Next()
 
 		reqLog.WithFields(
 			logger.Field{Key: "status", Value: c.Writer.Status()},
 		).Info("Request completed")
-	})
+	}
+}
 
-	router.NoRoute(func(c *gin.Context) {
-		c.JSON(404, api.ErrorResponse{
-			Message: "Not Found",
-		})
-	})
+// parseQuoteMethod returns the HTTP method /quote is served with: GET or POST, in any case.
+// An empty value uses the spec's POST.
+func parseQuoteMethod(raw string) (string, error) {
+	switch method := strings.ToUpper(strings.TrimSpace(raw)); method {
+	case "":
+		return http.MethodPost, nil
+	case http.MethodGet, http.MethodPost:
+		return method, nil
+	default:
+		return "", fmt.Errorf("QUOTE_METHOD must be GET or POST, got %q", raw)
+	}
+}
 
-	// Create service and handler
-	parkingService, err := service.NewParkingLotService(context.Background())
-	if err != nil {
-		// Log the error and create a fallback in-memory service for development
-		log.Error("Error creating DynamoDB service, falling back to in-memory",
-			logger.Field{Key: "error", Value: err.Error()})
-		parkingService = &service.ParkingLotService{} // Default constructor creates in-memory service
+// registerRoutes mounts the API at the root and, when basePath is set (e.g. /v1),
+// also under that prefix so versioned and unversioned clients share the same handlers.
+// With quoteMethod GET, /quote is served on GET as well as the spec's POST.
+func registerRoutes(router *gin.Engine, h *handler.ParkingHandler, basePath, quoteMethod string) {
+	mountRoutes(router, h, quoteMethod)
+
+	basePath = "/" + strings.Trim(basePath, "/")
+	if basePath != "/" {
+		mountRoutes(router.Group(basePath), h, quoteMethod)
 	}
-	parkingHandler := handler.NewParkingHandler(parkingService)
+}
 
-	// Register API handlers
-	api.RegisterHandlers(router, parkingHandler)
+// mountRoutes registers the public routes on router and the spec's /admin operations in an /admin group
+// guarded by ADMIN_API_KEY, so admin routes do not share the public routes' authentication
+func mountRoutes(router gin.IRouter, h *handler.ParkingHandler, quoteMethod string) {
+	options := api.GinServerOptions{ErrorHandler: h.ErrorHandler}
+	admin := router.Group("/admin", h.AdminKeyMiddleware())
+	api.RegisterHandlersWithOptions(adminRouter{IRouter: router, admin: admin}, h, options)
+
+	// Gateways that only allow GET for reads quote through GET /quote, bound like the spec's POST
+	if quoteMethod == http.MethodGet {
+		wrapper := api.ServerInterfaceWrapper{Handler: h, ErrorHandler: h.ErrorHandler}
+		router.GET("/quote", wrapper.PostQuote)
+	}
+}
 
-	// Create the Lambda adapter
-	return &APIAdapter{
-		log:    log,
-		router: router,
+// adminRouter registers the generated routes under /admin, e.g. /admin/maintenance, on the admin
+// group so they pass through its authentication, and every other route on the public router
+type adminRouter struct {
+	gin.IRouter
+	admin gin.IRouter
+}
+
+// route returns the router and relative path a generated route is registered with
+func (r adminRouter) route(path string) (gin.IRouter, string) {
+	if rest, ok := strings.CutPrefix(path, "/admin/"); ok {
+		return r.admin, "/" + rest
 	}
+	return r.IRouter, path
+}
+
+// GET registers a GET route on the router route selects
+func (r adminRouter) GET(path string, handlers ...gin.HandlerFunc) gin.IRoutes {
+	return r.Handle(http.MethodGet, path, handlers...)
+}
+
+// POST registers a POST route on the router route selects
+func (r adminRouter) POST(path string, handlers ...gin.HandlerFunc) gin.IRoutes {
+	return r.Handle(http.MethodPost, path, handlers...)
+}
+
+// PATCH registers a PATCH route on the router route selects
+func (r adminRouter) PATCH(path string, handlers ...gin.HandlerFunc) gin.IRoutes {
+	return r.Handle(http.MethodPatch, path, handlers...)
+}
+
+// DELETE registers a DELETE route on the router route selects
+func (r adminRouter) DELETE(path string, handlers ...gin.HandlerFunc) gin.IRoutes {
+	return r.Handle(http.MethodDelete, path, handlers...)
+}
+
+// Handle registers a route with a configured method on the router route selects
+func (r adminRouter) Handle(method, path string, handlers ...gin.HandlerFunc) gin.IRoutes {
+	router, path := r.route(path)
+	return router.Handle(method, path, handlers...)
 }
 
 // Router returns the Gin engine router for the adapter.
@@ -112,24 +312,39 @@ func (a *APIAdapter) Router() *gin.Engine {
 
 // ProxyWithContext handles Lambda requests
 func (a *APIAdapter) ProxyWithContext(ctx context.Context, req events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
-	// Extract or generate a request ID
-	requestID := req.Headers["X-Request-ID"]
+	// Extract or generate a request ID; header names arrive in whatever case the client used
+	proxyReq := ProxyRequest(req)
+	requestID := proxyReq.Header("X-Request-ID")
 	if requestID == "" {
 		requestID = uuid.New().String()
-		req.Headers["X-Request-ID"] = requestID
+		proxyReq.SetHeader("X-Request-ID", requestID)
 	}
 
 	// Create a logger with the request ID
 	reqLog := a.log.WithRequestID(requestID).WithFields(
-		logger.Field{Key: "path", Value: req.Path},
+		logger.Path(req.Path),
 		logger.Field{Key: "method", Value: req.HTTPMethod},
+		logger.Field{Key: "cold_start", Value: coldStart.Swap(false)},
+		logger.Field{Key: "init_duration_ms", Value: time.Duration(initDuration.Load()).Milliseconds()},
 	)
 
 	reqLog.Info("Lambda request received")
+	if a.dumpEvents {
+		reqLog.Debug("Lambda event", logger.Field{Key: "event", Value: dumpEvent(req)})
+	}
 
 	// Handle the request
 	adapter := ginadapter.New(a.router)
-	response, err := adapter.ProxyWithContext(ctx, req)
+	response, err := adapter.ProxyWithContext(ctx, events.APIGatewayProxyRequest(proxyReq))
+
+	if err != nil {
+		reqLog.Error("Lambda request error", logger.Field{Key: "error", Value: err.Error()})
+		// The inner adapter fails before any handler ran, e.g. on an undecodable body, and returns no body;
+		// answer with an error response instead of an empty one so the client sees what happened
+		if response.Body == "" {
+			response, err = internalErrorResponse(), nil
+		}
+	}
 
 	// Log the result
 	statusCode := response.StatusCode
@@ -137,10 +352,6 @@ func (a *APIAdapter) ProxyWithContext(ctx context.Context, req events.APIGateway
 		logger.Field{Key: "status_code", Value: statusCode},
 	).Info("Lambda request completed")
 
-	if err != nil {
-		reqLog.Error("Lambda request error", logger.Field{Key: "error", Value: err.Error()})
-	}
-
 	// Ensure the request ID is included in the response
 	if response.Headers == nil {
 		response.Headers = make(map[string]string)
@@ -150,49 +361,245 @@ func (a *APIAdapter) ProxyWithContext(ctx context.Context, req events.APIGateway
 	return response, err
 }
 
-// Cleanup performs cleanup operations for the adapter
+// internalErrorResponse is the response for a request the inner adapter could not proxy
+func internalErrorResponse() events.APIGatewayProxyResponse {
+	body, _ := json.Marshal(api.ErrorResponse{Code: handler.CodeInternalError, Message: "Internal server error"})
+	return events.APIGatewayProxyResponse{
+		StatusCode: http.StatusInternalServerError,
+		Headers:    map[string]string{"Content-Type": "application/json; charset=utf-8"},
+		Body:       string(body),
+	}
+}
+
+// RegisterCloser adds a resource for Cleanup to close when the process shuts down
+func (a *APIAdapter) RegisterCloser(closer io.Closer) {
+	a.closersMu.Lock()
+	defer a.closersMu.Unlock()
+	a.closers = append(a.closers, closer)
+}
+
+// Flush exports buffered telemetry before the Lambda environment is frozen. It runs after every
+// invocation, so it is bounded by flushTimeout and leaves the registered closers to Cleanup.
+func (a *APIAdapter) Flush(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, flushTimeout)
+	defer cancel()
+
+	if err := a.telemetry.Flush(ctx); err != nil {
+		a.log.Error("Failed to flush telemetry", logger.Field{Key: "error", Value: err.Error()})
+		return fmt.Errorf("failed to flush telemetry: %w", err)
+	}
+	return nil
+}
+
+// Cleanup flushes telemetry and closes every registered closer when the process shuts down, giving
+// up after cleanupTimeout. A failure does not stop the remaining resources from being closed; all
+// failures are logged and returned joined together.
 func (a *APIAdapter) Cleanup(ctx context.Context) error {
-	// Perform any necessary cleanup operations here
-	// For example, closing database connections or releasing resources
-	// Currently, there are no resources to clean up in this adapter
 	a.log.Info("Cleaning up Lambda API adapter")
-	return nil
+
+	ctx, cancel := context.WithTimeout(ctx, cleanupTimeout)
+	defer cancel()
+
+	var errs []error
+	if err := a.Flush(ctx); err != nil {
+		errs = append(errs, err)
+	}
+
+	a.closersMu.Lock()
+	closers := slices.Clone(a.closers)
+	a.closersMu.Unlock()
+
+	for _, closer := range closers {
+		if err := closeWithin(ctx, closer); err != nil {
+			resource := fmt.Sprintf("%T", closer)
+			a.log.Error("Failed to close resource",
+				logger.Field{Key: "resource", Value: resource},
+				logger.Field{Key: "error", Value: err.Error()},
+			)
+			errs = append(errs, fmt.Errorf("failed to close %s: %w", resource, err))
+		}
+	}
+
+	a.log.Info("Cleanup finished",
+		logger.Field{Key: "closers", Value: len(closers)},
+		logger.Field{Key: "failures", Value: len(errs)},
+	)
+	return errors.Join(errs...)
+}
+
+// closeWithin closes closer, returning ctx's error instead when ctx is done first. The close keeps
+// running in the background, since io.Closer cannot be interrupted.
+func closeWithin(ctx context.Context, closer io.Closer) error {
+	done := make(chan error, 1)
+	go func() {
+		done <- closer.Close()
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
 }
 
-// RunLocalServer starts the local server for testing with graceful shutdown
-func (a *APIAdapter) RunLocalServer(ctx context.Context) {
-	defer a.Cleanup(ctx)
+// RunLocalServer starts the local server for testing with graceful shutdown.
+// It returns an error when the server cannot be configured or bind its address,
+// and otherwise runs until an interrupt signal is received or ctx is done.
+func (a *APIAdapter) RunLocalServer(ctx context.Context) error {
+	defer a.Cleanup(context.WithoutCancel(ctx))
 
-	// Create a custom HTTP server
-	srv := &http.Server{
-		Addr:    ":8080",
-		Handler: a.router,
+	srv, listener, err := a.startLocalServer()
+	if err != nil {
+		a.log.Error("Failed to start local server", logger.Field{Key: "error", Value: err.Error()})
+		return err
 	}
 
 	// Create a channel to listen for interrupt signals
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(quit)
 
-	// Start the server in a goroutine
-	go func() {
-		a.log.Info("Starting local server on port 8080")
-		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			a.log.Error("Failed to start local server", logger.Field{Key: "error", Value: err.Error()})
-		}
-	}()
+	a.log.Info("Starting local server",
+		logger.Field{Key: "addr", Value: listener.Addr().String()},
+		logger.Field{Key: "h2c", Value: srv.Protocols != nil && srv.Protocols.UnencryptedHTTP2()},
+	)
+
+	// Correct drifted occupancy counters in the background until shutdown
+	reconcileCtx, stopReconciler := context.WithCancel(ctx)
+	defer stopReconciler()
+	a.startOccupancyReconciler(reconcileCtx)
 
 	// Wait for interrupt signal
-	<-quit
-	a.log.Info("Shutdown signal received, gracefully stopping server...")
+	select {
+	case <-quit:
+	case <-ctx.Done():
+	}
+	timeout := a.shutdownTimeout
+	if timeout <= 0 {
+		timeout = defaultShutdownTimeout
+	}
+	a.log.Info("Shutdown signal received, draining in-flight requests...",
+		logger.Field{Key: "in_flight", Value: a.inFlight.Load()},
+		logger.Field{Key: "timeout", Value: timeout.String()},
+	)
 
-	// Create a deadline for shutdown
-	shutdownCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	// Stop accepting connections and wait for in-flight requests up to the deadline
+	shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), timeout)
 	defer cancel()
 
-	// Attempt graceful shutdown
 	if err := srv.Shutdown(shutdownCtx); err != nil {
-		a.log.Error("Server forced to shutdown", logger.Field{Key: "error", Value: err.Error()})
+		// Requests still running now are cut off, so say how many
+		a.log.Error("Server forced to shutdown",
+			logger.Field{Key: "error", Value: err.Error()},
+			logger.Field{Key: "in_flight", Value: a.inFlight.Load()},
+		)
+		srv.Close()
 	}
 
+	a.logShutdownState()
 	a.log.Info("Server gracefully stopped")
+	return nil
+}
+
+// startLocalServer binds the local server address and serves in the background.
+// Binding happens before returning so an address already in use is reported to the caller.
+func (a *APIAdapter) startLocalServer() (*http.Server, net.Listener, error) {
+	srv, err := newLocalServer(a.trackInFlight(a.router))
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid local server configuration: %w", err)
+	}
+
+	listener, err := net.Listen("tcp", srv.Addr)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to listen on %s: %w", srv.Addr, err)
+	}
+
+	go func() {
+		if err := srv.Serve(listener); err != nil && err != http.ErrServerClosed {
+			a.log.Error("Local server stopped unexpectedly", logger.Field{Key: "error", Value: err.Error()})
+		}
+	}()
+
+	return srv, listener, nil
+}
+
+// startOccupancyReconciler reconciles the service's occupancy counters every RECONCILE_INTERVAL_MINUTES
+// until ctx is done. Lambda instances are frozen between invocations, so this only runs with the
+// long-lived local server.
+func (a *APIAdapter) startOccupancyReconciler(ctx context.Context) {
+	reconciler, ok := a.service.(service.OccupancyReconciler)
+	if !ok {
+		return
+	}
+	configured, ok := a.service.(interface{ ReconcileInterval() time.Duration })
+	if !ok || configured.ReconcileInterval() <= 0 {
+		return
+	}
+	go service.RunOccupancyReconciler(ctx, reconciler, configured.ReconcileInterval(), a.log)
+}
+
+// reconcileScheduled reconciles the occupancy of every tracked lot for a scheduled event, the Lambda
+// counterpart of the local server's periodic reconciliation
+func (a *APIAdapter) reconcileScheduled(ctx context.Context) error {
+	reconciler, ok := a.service.(service.OccupancyReconciler)
+	if !ok {
+		a.log.Warn("Scheduled occupancy reconciliation is not supported by the service")
+		return nil
+	}
+	a.log.Info("Reconciling occupancy on schedule")
+	return service.ReconcileTrackedLots(ctx, reconciler, a.log)
+}
+
+// trackInFlight counts the requests being handled in a.inFlight, so shutdown can report what it drains
+func (a *APIAdapter) trackInFlight(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		a.inFlight.Add(1)
+		defer a.inFlight.Add(-1)
+		next.ServeHTTP(w, r)
+	})
+}
+
+// logShutdownState logs the occupancy counted by this process and, for the in-memory store,
+// the tickets of vehicles that never exited, since both are lost when the server stops unless
+// INMEM_SNAPSHOT_PATH saves them
+func (a *APIAdapter) logShutdownState() {
+	if counter, ok := a.service.(interface{ OccupancySnapshot() map[int]int }); ok {
+		for parkingLot, occupied := range counter.OccupancySnapshot() {
+			a.log.Info("Occupancy at shutdown",
+				logger.Field{Key: "parking_lot", Value: parkingLot},
+				logger.Field{Key: "occupied", Value: occupied},
+			)
+		}
+	}
+
+	memoryService, ok := a.service.(*service.MemoryParkingLotService)
+	if !ok {
+		return
+	}
+	active := memoryService.Snapshot()
+	for _, ticket := range active {
+		a.log.Info("Active ticket at shutdown",
+			logger.Field{Key: "ticket_id", Value: ticket.TicketID},
+			logger.Plate(ticket.Plate),
+			logger.Field{Key: "parking_lot", Value: ticket.ParkingLot},
+			logger.Field{Key: "entry_time", Value: ticket.EntryTime},
+		)
+	}
+
+	if path := memoryService.SnapshotPath(); path != "" {
+		if err := memoryService.SaveSnapshot(path); err != nil {
+			a.log.Error("Failed to save in-memory snapshot",
+				logger.Field{Key: "path", Value: path},
+				logger.Field{Key: "error", Value: err.Error()},
+			)
+		} else {
+			a.log.Info("In-memory tickets saved",
+				logger.Field{Key: "path", Value: path},
+				logger.Field{Key: "active_tickets", Value: len(active)},
+			)
+			return
+		}
+	}
+	a.log.Info("In-memory tickets discarded", logger.Field{Key: "active_tickets", Value: len(active)})
 }
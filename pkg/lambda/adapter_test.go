@@ -6,14 +6,27 @@ package lambda
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 
+	"parking-lot/internal/handler"
 	"parking-lot/internal/logger"
+	"parking-lot/internal/mocks"
+	"parking-lot/internal/model"
+	"parking-lot/internal/service"
+	"parking-lot/internal/timing"
 	"parking-lot/server/api"
 )
 
@@ -28,14 +41,12 @@ func setupTestAdapter() *APIAdapter {
 			reqID = "generated-id"
 			c.Header("X-Request-ID", reqID)
 		}
-		ctx := context.WithValue(c.Request.Context(), requestIDKey, reqID)
+		ctx := logger.ContextWithRequestID(c.Request.Context(), reqID)
 		c.Request = c.Request.WithContext(ctx)
 		c.Next()
 	})
 	// NoRoute handler matching real adapter behavior
-	router.NoRoute(func(c *gin.Context) {
-		c.JSON(http.StatusNotFound, api.ErrorResponse{Message: "Not Found"})
-	})
+	router.NoRoute(handler.NewParkingHandler(new(mocks.ParkingService)).NotFound)
 	return &APIAdapter{
 		router: router,
 		log:    logger.NewLogger(),
@@ -58,6 +69,7 @@ func TestRealProxyWithContext_NotFound(t *testing.T) {
 	err = json.Unmarshal([]byte(resp.Body), &er)
 	assert.NoError(t, err)
 	assert.Equal(t, "Not Found", er.Message)
+	assert.Equal(t, handler.CodeNotFound, er.Code)
 
 	// Ensure request ID header is present in response
 	assert.NotEmpty(t, resp.Headers["X-Request-Id"])
@@ -79,8 +91,561 @@ func TestRealProxyWithContext_WithRequestID(t *testing.T) {
 	assert.Equal(t, reqID, resp.Headers["X-Request-Id"])
 }
 
+func TestProxyWithContext_AdapterError(t *testing.T) {
+	adapter := setupTestAdapter()
+	log := mocks.NewLogger()
+	adapter.log = log
+	// A body that is not valid base64 cannot be turned into an HTTP request
+	req := events.APIGatewayProxyRequest{
+		HTTPMethod:      "POST",
+		Path:            "/entry",
+		Headers:         map[string]string{"X-Request-ID": "test-id-789"},
+		Body:            "not base64!",
+		IsBase64Encoded: true,
+	}
+	resp, err := adapter.ProxyWithContext(context.Background(), req)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+	assert.Equal(t, "test-id-789", resp.Headers["X-Request-Id"])
+
+	var er api.ErrorResponse
+	assert.NoError(t, json.Unmarshal([]byte(resp.Body), &er))
+	assert.Equal(t, "Internal server error", er.Message)
+	assert.Equal(t, handler.CodeInternalError, er.Code)
+
+	assert.Len(t, log.Find("Lambda request error"), 1)
+	completed := log.Find("Lambda request completed")
+	if assert.Len(t, completed, 1) {
+		assert.Equal(t, http.StatusInternalServerError, completed[0].Fields["status_code"])
+	}
+}
+
 func TestRealCleanup(t *testing.T) {
 	adapter := setupTestAdapter()
 	err := adapter.Cleanup(context.Background())
 	assert.NoError(t, err)
 }
+
+// fakeCloser records that it was closed and fails with err, after block is closed when set
+type fakeCloser struct {
+	closed atomic.Bool
+	err    error
+	block  chan struct{}
+}
+
+// Close marks the closer closed and returns its error
+func (c *fakeCloser) Close() error {
+	if c.block != nil {
+		<-c.block
+	}
+	c.closed.Store(true)
+	return c.err
+}
+
+func TestCleanup_Closers(t *testing.T) {
+	adapter := setupTestAdapter()
+	log := mocks.NewLogger()
+	adapter.log = log
+	failing := &fakeCloser{err: errors.New("flush failed")}
+	healthy := &fakeCloser{}
+	adapter.RegisterCloser(failing)
+	adapter.RegisterCloser(healthy)
+
+	err := adapter.Cleanup(context.Background())
+
+	// A failing closer does not keep the others from being closed
+	assert.True(t, failing.closed.Load())
+	assert.True(t, healthy.closed.Load())
+	assert.ErrorIs(t, err, failing.err)
+	assert.ErrorContains(t, err, "failed to close *lambda.fakeCloser: flush failed")
+
+	failures := log.Find("Failed to close resource")
+	if assert.Len(t, failures, 1) {
+		assert.Equal(t, "*lambda.fakeCloser", failures[0].Fields["resource"])
+	}
+	finished := log.Find("Cleanup finished")
+	if assert.Len(t, finished, 1) {
+		assert.Equal(t, 2, finished[0].Fields["closers"])
+		assert.Equal(t, 1, finished[0].Fields["failures"])
+	}
+}
+
+func TestCleanup_Deadline(t *testing.T) {
+	adapter := setupTestAdapter()
+	stuck := &fakeCloser{block: make(chan struct{})}
+	defer close(stuck.block)
+	healthy := &fakeCloser{}
+	adapter.RegisterCloser(stuck)
+	adapter.RegisterCloser(healthy)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	err := adapter.Cleanup(ctx)
+
+	// A closer that hangs is abandoned at the deadline instead of holding up shutdown
+	assert.Less(t, time.Since(start), time.Second)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.False(t, stuck.closed.Load())
+}
+
+func TestProxyWithContext_ColdStart(t *testing.T) {
+	adapter := setupTestAdapter()
+	log := mocks.NewLogger()
+	adapter.log = log
+	coldStart.Store(true)
+
+	for i := 0; i < 2; i++ {
+		req := events.APIGatewayProxyRequest{
+			HTTPMethod: "GET",
+			Path:       "/nope",
+			Headers:    map[string]string{},
+		}
+		_, err := adapter.ProxyWithContext(context.Background(), req)
+		assert.NoError(t, err)
+	}
+
+	received := log.Find("Lambda request received")
+	if assert.Len(t, received, 2) {
+		assert.Equal(t, true, received[0].Fields["cold_start"])
+		assert.Equal(t, false, received[1].Fields["cold_start"])
+		assert.Contains(t, received[1].Fields, "init_duration_ms")
+	}
+}
+
+func TestProxyWithContext_ServerTiming(t *testing.T) {
+	adapter := setupTestAdapter()
+	adapter.router.Use(timing.Middleware())
+
+	mockService := new(mocks.ParkingService)
+	mockService.On("FindActiveTicket", mock.Anything, "TIME-1", 3).Return(nil, nil)
+	mockService.On("FindReentryTicket", mock.Anything, "TIME-1", 3).Return(nil, nil)
+	mockService.On("ReserveSpaces", mock.Anything, 3, 1).Return(nil)
+	mockService.On("CreateTicket", mock.Anything, "TIME-1", 3, 1).Return(uuid.New(), &model.ParkingTicket{})
+	mockService.On("Rates").Return(model.RateSchedule{IncrementMinutes: 15, RatePerIncrement: 2.5, Currency: "USD"})
+	api.RegisterHandlers(adapter.router, handler.NewParkingHandler(mockService))
+
+	req := events.APIGatewayProxyRequest{
+		HTTPMethod:            "POST",
+		Path:                  "/entry",
+		Headers:               map[string]string{},
+		QueryStringParameters: map[string]string{"plate": "TIME-1", "parkingLot": "3"},
+	}
+	resp, err := adapter.ProxyWithContext(context.Background(), req)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	header := resp.Headers["Server-Timing"]
+	if header == "" && len(resp.MultiValueHeaders["Server-Timing"]) > 0 {
+		header = resp.MultiValueHeaders["Server-Timing"][0]
+	}
+	assert.Regexp(t, `^db;dur=\d+\.\d, total;dur=\d+\.\d$`, header)
+	mockService.AssertExpectations(t)
+}
+
+func TestLogShutdownState_MemoryService(t *testing.T) {
+	adapter := setupTestAdapter()
+	log := mocks.NewLogger()
+	adapter.log = log
+
+	memoryService, err := service.NewMemoryParkingLotService(context.Background())
+	assert.NoError(t, err)
+	adapter.service = memoryService
+
+	assert.NoError(t, memoryService.ReserveSpaces(context.Background(), 5, 2))
+	_, ticket := memoryService.CreateTicket(context.Background(), "SHUT-1", 5, 2)
+
+	adapter.logShutdownState()
+
+	occupancy := log.Find("Occupancy at shutdown")
+	if assert.Len(t, occupancy, 1) {
+		assert.Equal(t, 5, occupancy[0].Fields["parking_lot"])
+		assert.Equal(t, 2, occupancy[0].Fields["occupied"])
+	}
+	active := log.Find("Active ticket at shutdown")
+	if assert.Len(t, active, 1) {
+		assert.Equal(t, ticket.TicketID, active[0].Fields["ticket_id"])
+	}
+}
+
+func TestLogShutdownState_Snapshot(t *testing.T) {
+	t.Setenv("INMEM_SNAPSHOT_PATH", filepath.Join(t.TempDir(), "tickets.json"))
+	adapter := setupTestAdapter()
+	log := mocks.NewLogger()
+	adapter.log = log
+
+	memoryService, err := service.NewMemoryParkingLotService(context.Background())
+	assert.NoError(t, err)
+	adapter.service = memoryService
+	_, ticket := memoryService.CreateTicket(context.Background(), "SHUT-1", 5, 1)
+
+	adapter.logShutdownState()
+
+	assert.Len(t, log.Find("In-memory tickets saved"), 1)
+	assert.Empty(t, log.Find("In-memory tickets discarded"))
+
+	// The next server picks the ticket up again
+	restarted, err := service.NewMemoryParkingLotService(context.Background())
+	assert.NoError(t, err)
+	_, ok := restarted.GetTicket(context.Background(), ticket.TicketID)
+	assert.True(t, ok)
+}
+
+func TestRequestIDMiddleware_ServiceLogs(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	log := mocks.NewLogger()
+	memoryService, err := service.NewMemoryParkingLotServiceWithLogger(context.Background(), log)
+	assert.NoError(t, err)
+
+	router := gin.New()
+	router.Use(requestIDMiddleware)
+	registerRoutes(router, handler.NewParkingHandler(memoryService), "", "")
+	adapter := &APIAdapter{router: router, log: logger.NewLogger()}
+
+	req := events.APIGatewayProxyRequest{
+		HTTPMethod:            "POST",
+		Path:                  "/entry",
+		Headers:               map[string]string{"X-Request-ID": "req-entry-1"},
+		QueryStringParameters: map[string]string{"plate": "LOG-123", "parkingLot": "1"},
+	}
+	resp, err := adapter.ProxyWithContext(context.Background(), req)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	entries := log.Entries()
+	if assert.NotEmpty(t, entries) {
+		for _, entry := range entries {
+			assert.Equal(t, "req-entry-1", entry.Fields["request_id"], entry.Message)
+		}
+	}
+}
+
+func TestRequestLogger_RedactsHeaders(t *testing.T) {
+	t.Setenv("LOG_REDACT_HEADERS", "Authorization, X-API-Key, X-Session-Token")
+	adapter := setupTestAdapter()
+	log := mocks.NewLogger()
+	adapter.router.Use(requestLogger(log))
+	adapter.router.GET("/ping", func(c *gin.Context) { c.Status(http.StatusNoContent) })
+
+	req := events.APIGatewayProxyRequest{
+		HTTPMethod: "GET",
+		Path:       "/ping",
+		Headers: map[string]string{
+			"x-api-key":       "sk-live-123456",
+			"Authorization":   "Bearer secret-token",
+			"X-Session-Token": "session-abc",
+			"User-Agent":      "kiosk/1.0",
+		},
+	}
+	resp, err := adapter.ProxyWithContext(context.Background(), req)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+	started := log.Find("Request started")
+	if !assert.Len(t, started, 1) {
+		return
+	}
+	headers := started[0].Fields["headers"].(map[string]string)
+	assert.Equal(t, logger.Redacted, headers["X-Api-Key"])
+	assert.Equal(t, logger.Redacted, headers["Authorization"])
+	assert.Equal(t, logger.Redacted, headers["X-Session-Token"])
+	assert.Equal(t, "kiosk/1.0", headers["User-Agent"])
+	for _, entry := range log.Entries() {
+		assert.NotContains(t, fmt.Sprint(entry.Fields), "sk-live-123456")
+		assert.NotContains(t, fmt.Sprint(entry.Fields), "secret-token")
+	}
+}
+
+func TestProxyWithContext_MasksPlatePath(t *testing.T) {
+	t.Setenv("LOG_PLATE_MASK", "true")
+	adapter := setupTestAdapter()
+	log := mocks.NewLogger()
+	adapter.log = log
+	adapter.dumpEvents = true
+	req := events.APIGatewayProxyRequest{
+		HTTPMethod:     "GET",
+		Path:           "/plate/AB123423/history",
+		Resource:       "/plate/{plate}/history",
+		PathParameters: map[string]string{"plate": "AB123423"},
+		RequestContext: events.APIGatewayProxyRequestContext{Path: "/prod/plate/AB123423/history"},
+	}
+
+	_, err := adapter.ProxyWithContext(context.Background(), req)
+	assert.NoError(t, err)
+
+	received := log.Find("Lambda request received")
+	if assert.Len(t, received, 1) {
+		assert.Equal(t, "/plate/AB****23/history", received[0].Fields["path"])
+	}
+	dumps := log.Find("Lambda event")
+	if assert.Len(t, dumps, 1) {
+		event := dumps[0].Fields["event"].(events.APIGatewayProxyRequest)
+		assert.Equal(t, "/plate/AB****23/history", event.Path)
+		assert.Equal(t, "/prod/plate/AB****23/history", event.RequestContext.Path)
+		assert.Equal(t, "AB****23", event.PathParameters["plate"])
+		assert.Equal(t, "/plate/{plate}/history", event.Resource)
+	}
+	for _, entry := range log.Entries() {
+		assert.NotContains(t, fmt.Sprint(entry.Fields), "AB123423")
+	}
+	// The request itself is proxied unchanged
+	assert.Equal(t, "AB123423", req.PathParameters["plate"])
+}
+
+func TestProxyWithContext_DumpEvent(t *testing.T) {
+	t.Setenv("LOG_REDACT_HEADERS", "")
+	t.Setenv("LOG_PLATE_MASK", "true")
+	req := events.APIGatewayProxyRequest{
+		HTTPMethod:            "POST",
+		Path:                  "/nope",
+		Headers:               map[string]string{"X-API-Key": "sk-live-123456", "User-Agent": "kiosk/1.0"},
+		QueryStringParameters: map[string]string{"plate": "AB123423", "parkingLot": "3"},
+		Body:                  strings.Repeat("x", dumpBodyLimit+10),
+	}
+
+	t.Run("Enabled", func(t *testing.T) {
+		adapter := setupTestAdapter()
+		log := mocks.NewLogger()
+		adapter.log = log
+		adapter.dumpEvents = true
+
+		_, err := adapter.ProxyWithContext(context.Background(), req)
+		assert.NoError(t, err)
+
+		dumps := log.Find("Lambda event")
+		if !assert.Len(t, dumps, 1) {
+			return
+		}
+		assert.Equal(t, "debug", dumps[0].Level)
+		event := dumps[0].Fields["event"].(events.APIGatewayProxyRequest)
+		assert.Equal(t, logger.Redacted, event.Headers["X-API-Key"])
+		assert.Equal(t, "kiosk/1.0", event.Headers["User-Agent"])
+		assert.Equal(t, "AB****23", event.QueryStringParameters["plate"])
+		assert.Equal(t, strings.Repeat("x", dumpBodyLimit)+"... (10 bytes truncated)", event.Body)
+
+		// The request itself is proxied unchanged
+		assert.Equal(t, "sk-live-123456", req.Headers["X-API-Key"])
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		adapter := setupTestAdapter()
+		log := mocks.NewLogger()
+		adapter.log = log
+
+		_, err := adapter.ProxyWithContext(context.Background(), req)
+		assert.NoError(t, err)
+
+		assert.NotEmpty(t, log.Find("Lambda request received"))
+		assert.Empty(t, log.Find("Lambda event"))
+	})
+}
+
+func TestTrimQuery(t *testing.T) {
+	ticketID := uuid.New()
+	adapter := setupTestAdapter()
+	adapter.router.Use(trimQuery)
+
+	mockService := new(mocks.ParkingService)
+	mockService.On("GetTicket", mock.Anything, ticketID.String()).Return(nil, false).Once()
+	api.RegisterHandlers(adapter.router, handler.NewParkingHandler(mockService))
+
+	tests := []struct {
+		name           string
+		ticketID       string
+		expectedStatus int
+	}{
+		// A ticket ID with surrounding whitespace binds and is looked up trimmed
+		{name: "Padded ticket ID", ticketID: "  " + ticketID.String() + " \t", expectedStatus: http.StatusNotFound},
+		{name: "Whitespace only", ticketID: "   ", expectedStatus: http.StatusBadRequest},
+		{name: "Padded nil UUID", ticketID: " " + uuid.Nil.String() + " ", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := events.APIGatewayProxyRequest{
+				HTTPMethod:            "POST",
+				Path:                  "/exit",
+				Headers:               map[string]string{},
+				QueryStringParameters: map[string]string{"ticketId": tt.ticketID},
+			}
+			resp, err := adapter.ProxyWithContext(context.Background(), req)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, resp.StatusCode, resp.Body)
+		})
+	}
+	mockService.AssertExpectations(t)
+}
+
+func TestRegisterRoutes_BasePath(t *testing.T) {
+	testCases := []struct {
+		name     string
+		basePath string
+		path     string
+		status   int
+	}{
+		{name: "Unprefixed without base path", basePath: "", path: "/exit", status: http.StatusOK},
+		{name: "Prefixed without base path", basePath: "", path: "/v1/exit", status: http.StatusNotFound},
+		{name: "Unprefixed with base path", basePath: "/v1", path: "/exit", status: http.StatusOK},
+		{name: "Prefixed with base path", basePath: "/v1", path: "/v1/exit", status: http.StatusOK},
+		{name: "Base path without slashes", basePath: "v1/", path: "/v1/exit", status: http.StatusOK},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			adapter := setupTestAdapter()
+			ticketID := uuid.New()
+			mockService := new(mocks.ParkingService)
+			exitTime := time.Now()
+			mockService.On("GetTicket", mock.Anything, ticketID.String()).Return(&model.ParkingTicket{
+				TicketID: ticketID.String(), Status: model.TicketStatusOut, EntryTime: exitTime, ExitTime: &exitTime, Charge: 5,
+			}, true).Maybe()
+			mockService.On("CalculateChargeBetween", 0, exitTime, exitTime).Return(0, float32(0)).Maybe()
+			registerRoutes(adapter.router, handler.NewParkingHandler(mockService), tc.basePath, "")
+
+			resp, err := adapter.ProxyWithContext(context.Background(), events.APIGatewayProxyRequest{
+				HTTPMethod:            "POST",
+				Path:                  tc.path,
+				Headers:               map[string]string{},
+				QueryStringParameters: map[string]string{"ticketId": ticketID.String()},
+			})
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.status, resp.StatusCode)
+		})
+	}
+}
+
+func TestRegisterRoutes_QuoteMethod(t *testing.T) {
+	for _, method := range []string{"GET", "POST"} {
+		t.Run(method, func(t *testing.T) {
+			adapter := setupTestAdapter()
+			memoryService, err := service.NewMemoryParkingLotService(context.Background())
+			assert.NoError(t, err)
+			ticketID, _ := memoryService.CreateTicket(context.Background(), "ABC-123", 1, 1)
+			registerRoutes(adapter.router, handler.NewParkingHandler(memoryService), "/v1", method)
+
+			for _, path := range []string{"/quote", "/v1/quote"} {
+				resp, err := adapter.ProxyWithContext(context.Background(), events.APIGatewayProxyRequest{
+					HTTPMethod:            method,
+					Path:                  path,
+					Headers:               map[string]string{},
+					QueryStringParameters: map[string]string{"ticketId": ticketID.String()},
+				})
+
+				assert.NoError(t, err)
+				assert.Equal(t, http.StatusOK, resp.StatusCode, path)
+
+				// The spec's POST /quote is served either way
+				resp, err = adapter.ProxyWithContext(context.Background(), events.APIGatewayProxyRequest{
+					HTTPMethod:            "POST",
+					Path:                  path,
+					Headers:               map[string]string{},
+					QueryStringParameters: map[string]string{"ticketId": ticketID.String()},
+				})
+
+				assert.NoError(t, err)
+				assert.Equal(t, http.StatusOK, resp.StatusCode, path)
+			}
+		})
+	}
+
+	// GET /quote is not routed unless configured
+	adapter := setupTestAdapter()
+	registerRoutes(adapter.router, handler.NewParkingHandler(new(mocks.ParkingService)), "", "POST")
+	resp, err := adapter.ProxyWithContext(context.Background(), events.APIGatewayProxyRequest{
+		HTTPMethod:            "GET",
+		Path:                  "/quote",
+		Headers:               map[string]string{},
+		QueryStringParameters: map[string]string{"ticketId": uuid.New().String()},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestParseQuoteMethod(t *testing.T) {
+	for raw, expected := range map[string]string{"": "POST", "GET": "GET", " get ": "GET", "post": "POST"} {
+		method, err := parseQuoteMethod(raw)
+		assert.NoError(t, err, raw)
+		assert.Equal(t, expected, method, raw)
+	}
+
+	_, err := parseQuoteMethod("PUT")
+	assert.Error(t, err)
+}
+
+func TestRegisterRoutes_ErrorResponse(t *testing.T) {
+	adapter := setupTestAdapter()
+	registerRoutes(adapter.router, handler.NewParkingHandler(new(mocks.ParkingService)), "/v1", "")
+
+	for _, path := range []string{"/ticket/not-a-uuid", "/v1/ticket/not-a-uuid"} {
+		resp, err := adapter.ProxyWithContext(context.Background(), events.APIGatewayProxyRequest{
+			HTTPMethod: "GET",
+			Path:       path,
+			Headers:    map[string]string{},
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.Contains(t, resp.Body, `"message":"Invalid format for parameter ticketId`)
+	}
+}
+
+func TestRegisterRoutes_AdminGroup(t *testing.T) {
+	t.Setenv("ADMIN_API_KEY", "admin-key")
+	t.Setenv("ADMIN_TOKEN", "bearer-token")
+	adapter := setupTestAdapter()
+	memoryService, err := service.NewMemoryParkingLotService(context.Background())
+	assert.NoError(t, err)
+	registerRoutes(adapter.router, handler.NewParkingHandler(memoryService), "/v1", "")
+
+	request := func(method, path string, query, headers map[string]string) events.APIGatewayProxyResponse {
+		if headers == nil {
+			headers = map[string]string{}
+		}
+		resp, err := adapter.ProxyWithContext(context.Background(), events.APIGatewayProxyRequest{
+			HTTPMethod:            method,
+			Path:                  path,
+			Headers:               headers,
+			QueryStringParameters: query,
+		})
+		assert.NoError(t, err)
+		return resp
+	}
+	maintenance := map[string]string{"enabled": "false"}
+	export := map[string]string{"from": "2025-03-01", "to": "2025-03-02"}
+
+	t.Run("Admin routes reject requests without the admin key", func(t *testing.T) {
+		for _, path := range []string{"/admin/maintenance", "/v1/admin/maintenance"} {
+			resp := request("POST", path, maintenance, nil)
+			assert.Equal(t, http.StatusUnauthorized, resp.StatusCode, path)
+		}
+		resp := request("POST", "/admin/block", nil, map[string]string{"Authorization": "Bearer bearer-token"})
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+
+	t.Run("Admin routes reject the public bearer token", func(t *testing.T) {
+		resp := request("POST", "/admin/maintenance", maintenance, map[string]string{"Authorization": "Bearer bearer-token"})
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+
+	t.Run("Admin routes accept the admin key", func(t *testing.T) {
+		for _, path := range []string{"/admin/maintenance", "/v1/admin/maintenance"} {
+			resp := request("POST", path, maintenance, map[string]string{handler.AdminKeyHeader: "admin-key"})
+			assert.Equal(t, http.StatusOK, resp.StatusCode, path)
+		}
+	})
+
+	t.Run("Public routes are unaffected", func(t *testing.T) {
+		resp := request("POST", "/entry", map[string]string{"plate": "PUB-123", "parkingLot": "1"}, nil)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		resp = request("GET", "/livez", nil, nil)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("Admin operations keep their public paths", func(t *testing.T) {
+		resp := request("GET", "/export", export, map[string]string{"Authorization": "Bearer bearer-token"})
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		resp = request("GET", "/admin/export", export, map[string]string{handler.AdminKeyHeader: "admin-key"})
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+}
@@ -0,0 +1,115 @@
+package lambda
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/aws/aws-lambda-go/events"
+
+	"parking-lot/internal/logger"
+)
+
+// dumpBodyLimit is the most bytes of a request body included in an event dump
+const dumpBodyLimit = 2048
+
+// dumpEvent returns a copy of an API Gateway event that is safe to log: sensitive headers are
+// redacted, plates in the query and path are masked when LOG_PLATE_MASK is enabled and the body is cut to dumpBodyLimit bytes
+func dumpEvent(req events.APIGatewayProxyRequest) events.APIGatewayProxyRequest {
+	sensitive := logger.SensitiveHeaders()
+
+	if req.Headers != nil {
+		headers := make(map[string]string, len(req.Headers))
+		for name, value := range req.Headers {
+			if sensitive[http.CanonicalHeaderKey(name)] {
+				value = logger.Redacted
+			}
+			headers[name] = value
+		}
+		req.Headers = headers
+	}
+	if req.MultiValueHeaders != nil {
+		headers := make(map[string][]string, len(req.MultiValueHeaders))
+		for name, values := range req.MultiValueHeaders {
+			if sensitive[http.CanonicalHeaderKey(name)] {
+				values = []string{logger.Redacted}
+			}
+			headers[name] = values
+		}
+		req.MultiValueHeaders = headers
+	}
+
+	if req.QueryStringParameters != nil {
+		query := make(map[string]string, len(req.QueryStringParameters))
+		for name, value := range req.QueryStringParameters {
+			query[name] = dumpQueryValue(name, value)
+		}
+		req.QueryStringParameters = query
+	}
+	if req.MultiValueQueryStringParameters != nil {
+		query := make(map[string][]string, len(req.MultiValueQueryStringParameters))
+		for name, values := range req.MultiValueQueryStringParameters {
+			masked := make([]string, len(values))
+			for i, value := range values {
+				masked[i] = dumpQueryValue(name, value)
+			}
+			query[name] = masked
+		}
+		req.MultiValueQueryStringParameters = query
+	}
+
+	req.Path = dumpPath(req.Path)
+	req.RequestContext.Path = dumpPath(req.RequestContext.Path)
+	req.PathParameters = dumpPathParameters(req.PathParameters)
+
+	if len(req.Body) > dumpBodyLimit {
+		req.Body = fmt.Sprintf("%s... (%d bytes truncated)", strings.ToValidUTF8(req.Body[:dumpBodyLimit], ""), len(req.Body)-dumpBodyLimit)
+	}
+	return req
+}
+
+// dumpQueryValue masks the plate query or path parameter the same way plates are masked in other logs
+func dumpQueryValue(name, value string) string {
+	if name != "plate" {
+		return value
+	}
+	return logger.Plate(value).Value.(string)
+}
+
+// dumpPath masks the plate segment of plate routes the same way plates are masked in other logs
+func dumpPath(path string) string {
+	return logger.Path(path).Value.(string)
+}
+
+// dumpPathParameters masks the plate path parameter
+func dumpPathParameters(params map[string]string) map[string]string {
+	if params == nil {
+		return nil
+	}
+	masked := make(map[string]string, len(params))
+	for name, value := range params {
+		masked[name] = dumpQueryValue(name, value)
+	}
+	return masked
+}
+
+// dumpEventV2 returns a copy of an HTTP API event that is safe to log, masked the same way as dumpEvent
+func dumpEventV2(req events.APIGatewayV2HTTPRequest) events.APIGatewayV2HTTPRequest {
+	v1 := dumpEvent(events.APIGatewayProxyRequest{
+		Headers:               req.Headers,
+		QueryStringParameters: req.QueryStringParameters,
+		Body:                  req.Body,
+	})
+	req.Headers = v1.Headers
+	req.QueryStringParameters = v1.QueryStringParameters
+	req.Body = v1.Body
+	req.RawPath = dumpPath(req.RawPath)
+	req.RequestContext.HTTP.Path = dumpPath(req.RequestContext.HTTP.Path)
+	req.PathParameters = dumpPathParameters(req.PathParameters)
+	// The raw query string and cookies repeat what the masked fields hold
+	req.RawQueryString = ""
+	if req.Cookies != nil {
+		req.Cookies = []string{logger.Redacted}
+	}
+	return req
+}
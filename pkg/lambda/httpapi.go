@@ -0,0 +1,128 @@
+package lambda
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	ginadapter "github.com/awslabs/aws-lambda-go-api-proxy/gin"
+	"github.com/google/uuid"
+
+	"parking-lot/internal/handler"
+	"parking-lot/internal/logger"
+	"parking-lot/server/api"
+)
+
+// eventKind holds the fields that tell the events a function receives apart: the version tells
+// REST API (1.0) and HTTP API (2.0) payloads apart, and REST API events do not set it; the source
+// is set by EventBridge on scheduled events
+type eventKind struct {
+	Version string `json:"version"`
+	Source  string `json:"source"`
+}
+
+// scheduledEventSource is the source of EventBridge scheduled events
+const scheduledEventSource = "aws.events"
+
+// Handle proxies a raw API Gateway event, detecting whether it is a REST API (payload 1.0) or an
+// HTTP API (payload 2.0) event, and returns the matching response type. It lets one function sit
+// behind either kind of API. EventBridge scheduled events reconcile the occupancy of every tracked lot.
+func (a *APIAdapter) Handle(ctx context.Context, event json.RawMessage) (any, error) {
+	var kind eventKind
+	if err := json.Unmarshal(event, &kind); err != nil {
+		return nil, fmt.Errorf("failed to parse API Gateway event: %w", err)
+	}
+
+	if kind.Source == scheduledEventSource {
+		return nil, a.reconcileScheduled(ctx)
+	}
+
+	if kind.Version == "2.0" {
+		var req events.APIGatewayV2HTTPRequest
+		if err := json.Unmarshal(event, &req); err != nil {
+			return nil, fmt.Errorf("failed to parse HTTP API event: %w", err)
+		}
+		return a.ProxyV2WithContext(ctx, req)
+	}
+
+	var req events.APIGatewayProxyRequest
+	if err := json.Unmarshal(event, &req); err != nil {
+		return nil, fmt.Errorf("failed to parse REST API event: %w", err)
+	}
+	return a.ProxyWithContext(ctx, req)
+}
+
+// ProxyV2WithContext handles an HTTP API (payload 2.0) request with the same router, request IDs
+// and logging as ProxyWithContext
+func (a *APIAdapter) ProxyV2WithContext(ctx context.Context, req events.APIGatewayV2HTTPRequest) (events.APIGatewayV2HTTPResponse, error) {
+	// HTTP APIs lowercase header names, but look the request ID up in any case to be safe
+	requestID := ""
+	for key, value := range req.Headers {
+		if strings.EqualFold(key, "X-Request-ID") {
+			requestID = strings.TrimSpace(value)
+			break
+		}
+	}
+	if requestID == "" {
+		requestID = uuid.New().String()
+		headers := make(map[string]string, len(req.Headers)+1)
+		for key, value := range req.Headers {
+			if !strings.EqualFold(key, "X-Request-ID") {
+				headers[key] = value
+			}
+		}
+		headers["x-request-id"] = requestID
+		req.Headers = headers
+	}
+
+	// Create a logger with the request ID
+	reqLog := a.log.WithRequestID(requestID).WithFields(
+		logger.Path(req.RawPath),
+		logger.Field{Key: "method", Value: req.RequestContext.HTTP.Method},
+		logger.Field{Key: "payload_version", Value: "2.0"},
+		logger.Field{Key: "cold_start", Value: coldStart.Swap(false)},
+		logger.Field{Key: "init_duration_ms", Value: time.Duration(initDuration.Load()).Milliseconds()},
+	)
+
+	reqLog.Info("Lambda request received")
+	if a.dumpEvents {
+		reqLog.Debug("Lambda event", logger.Field{Key: "event", Value: dumpEventV2(req)})
+	}
+
+	// Handle the request
+	adapter := ginadapter.NewV2(a.router)
+	response, err := adapter.ProxyWithContext(ctx, req)
+
+	if err != nil {
+		reqLog.Error("Lambda request error", logger.Field{Key: "error", Value: err.Error()})
+		if response.Body == "" {
+			response, err = internalErrorResponseV2(), nil
+		}
+	}
+
+	reqLog.WithFields(
+		logger.Field{Key: "status_code", Value: response.StatusCode},
+	).Info("Lambda request completed")
+
+	// Ensure the request ID is included in the response
+	if response.Headers == nil {
+		response.Headers = make(map[string]string)
+	}
+	response.Headers["X-Request-Id"] = requestID
+
+	return response, err
+}
+
+// internalErrorResponseV2 is the HTTP API response for a request the inner adapter could not proxy
+func internalErrorResponseV2() events.APIGatewayV2HTTPResponse {
+	body, _ := json.Marshal(api.ErrorResponse{Code: handler.CodeInternalError, Message: "Internal server error"})
+	return events.APIGatewayV2HTTPResponse{
+		StatusCode: http.StatusInternalServerError,
+		Headers:    map[string]string{"Content-Type": "application/json; charset=utf-8"},
+		Body:       string(body),
+	}
+}
@@ -0,0 +1,174 @@
+//go:build !integration
+// +build !integration
+
+package lambda
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"parking-lot/internal/handler"
+	"parking-lot/internal/mocks"
+	"parking-lot/internal/service"
+	"parking-lot/server/api"
+)
+
+// v2Request builds an HTTP API (payload 2.0) event
+func v2Request(method, path, query string, headers map[string]string) events.APIGatewayV2HTTPRequest {
+	return events.APIGatewayV2HTTPRequest{
+		Version:        "2.0",
+		RouteKey:       "$default",
+		RawPath:        path,
+		RawQueryString: query,
+		Headers:        headers,
+		RequestContext: events.APIGatewayV2HTTPRequestContext{
+			HTTP: events.APIGatewayV2HTTPRequestContextHTTPDescription{Method: method, Path: path},
+		},
+	}
+}
+
+func TestProxyV2WithContext(t *testing.T) {
+	adapter := setupTestAdapter()
+	log := mocks.NewLogger()
+	adapter.log = log
+	adapter.router.GET("/echo", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"plate": c.Query("plate"), "requestId": c.GetHeader("X-Request-ID")})
+	})
+
+	t.Run("Route", func(t *testing.T) {
+		req := v2Request("GET", "/echo", "plate=ABC-123", map[string]string{"x-request-id": "v2-id"})
+
+		resp, err := adapter.ProxyV2WithContext(context.Background(), req)
+
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.JSONEq(t, `{"plate":"ABC-123","requestId":"v2-id"}`, resp.Body)
+		assert.Equal(t, "v2-id", resp.Headers["X-Request-Id"])
+		assert.Contains(t, resp.Headers["Content-Type"], "application/json")
+		assert.False(t, resp.IsBase64Encoded)
+	})
+
+	t.Run("Not found", func(t *testing.T) {
+		resp, err := adapter.ProxyV2WithContext(context.Background(), v2Request("GET", "/nope", "", nil))
+
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+		var er api.ErrorResponse
+		require.NoError(t, json.Unmarshal([]byte(resp.Body), &er))
+		assert.Equal(t, handler.CodeNotFound, er.Code)
+		// A request ID is generated and reaches the router
+		assert.NotEmpty(t, resp.Headers["X-Request-Id"])
+	})
+
+	t.Run("Adapter error", func(t *testing.T) {
+		req := v2Request("POST", "/entry", "", map[string]string{"x-request-id": "v2-error"})
+		req.Body = "not base64!"
+		req.IsBase64Encoded = true
+
+		resp, err := adapter.ProxyV2WithContext(context.Background(), req)
+
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+		assert.Equal(t, "v2-error", resp.Headers["X-Request-Id"])
+		var er api.ErrorResponse
+		require.NoError(t, json.Unmarshal([]byte(resp.Body), &er))
+		assert.Equal(t, handler.CodeInternalError, er.Code)
+	})
+
+	received := log.Find("Lambda request received")
+	if assert.NotEmpty(t, received) {
+		assert.Equal(t, "/echo", received[0].Fields["path"])
+		assert.Equal(t, "GET", received[0].Fields["method"])
+		assert.Equal(t, "2.0", received[0].Fields["payload_version"])
+	}
+}
+
+func TestHandle_PayloadDetection(t *testing.T) {
+	adapter := setupTestAdapter()
+
+	t.Run("HTTP API", func(t *testing.T) {
+		event, err := json.Marshal(v2Request("GET", "/nope", "", map[string]string{"x-request-id": "v2-id"}))
+		require.NoError(t, err)
+
+		resp, err := adapter.Handle(context.Background(), event)
+
+		require.NoError(t, err)
+		v2, ok := resp.(events.APIGatewayV2HTTPResponse)
+		require.True(t, ok, "expected an HTTP API response, got %T", resp)
+		assert.Equal(t, http.StatusNotFound, v2.StatusCode)
+		assert.Equal(t, "v2-id", v2.Headers["X-Request-Id"])
+	})
+
+	t.Run("REST API", func(t *testing.T) {
+		event, err := json.Marshal(events.APIGatewayProxyRequest{
+			HTTPMethod: "GET",
+			Path:       "/nope",
+			Headers:    map[string]string{"X-Request-ID": "v1-id"},
+		})
+		require.NoError(t, err)
+
+		resp, err := adapter.Handle(context.Background(), event)
+
+		require.NoError(t, err)
+		v1, ok := resp.(events.APIGatewayProxyResponse)
+		require.True(t, ok, "expected a REST API response, got %T", resp)
+		assert.Equal(t, http.StatusNotFound, v1.StatusCode)
+		assert.Equal(t, "v1-id", v1.Headers["X-Request-Id"])
+	})
+
+	t.Run("Scheduled event", func(t *testing.T) {
+		t.Setenv("LOT_CONFIG", `{"3":{"capacity":10}}`)
+		ctx := context.Background()
+		memoryService, err := service.NewMemoryParkingLotService(ctx)
+		require.NoError(t, err)
+		_, _ = memoryService.CreateTicket(ctx, "ABC-123", 3, 2)
+		require.NoError(t, memoryService.ReserveSpaces(ctx, 3, 5))
+		scheduled := setupTestAdapter()
+		scheduled.service = memoryService
+
+		resp, err := scheduled.Handle(ctx, json.RawMessage(`{"version":"0","source":"aws.events","detail-type":"Scheduled Event","detail":{}}`))
+
+		require.NoError(t, err)
+		assert.Nil(t, resp)
+		assert.Equal(t, map[int]int{3: 2}, memoryService.OccupancySnapshot())
+	})
+
+	t.Run("Not JSON", func(t *testing.T) {
+		_, err := adapter.Handle(context.Background(), json.RawMessage(`not json`))
+
+		assert.Error(t, err)
+	})
+}
+
+func TestDumpEventV2(t *testing.T) {
+	req := v2Request("GET", "/ticket", "plate=ABC-123", map[string]string{"authorization": "Bearer secret"})
+	req.Cookies = []string{"session=abc"}
+
+	dumped := dumpEventV2(req)
+
+	assert.NotEqual(t, "Bearer secret", dumped.Headers["authorization"])
+	assert.Empty(t, dumped.RawQueryString)
+	assert.NotContains(t, dumped.Cookies, "session=abc")
+	// The original event is left untouched
+	assert.Equal(t, "Bearer secret", req.Headers["authorization"])
+
+	t.Run("Plate path", func(t *testing.T) {
+		t.Setenv("LOG_PLATE_MASK", "true")
+		req := v2Request("GET", "/plate/AB123423/active", "", nil)
+		req.PathParameters = map[string]string{"plate": "AB123423"}
+
+		dumped := dumpEventV2(req)
+
+		assert.Equal(t, "/plate/AB****23/active", dumped.RawPath)
+		assert.Equal(t, "/plate/AB****23/active", dumped.RequestContext.HTTP.Path)
+		assert.Equal(t, "AB****23", dumped.PathParameters["plate"])
+		assert.Equal(t, "AB123423", req.PathParameters["plate"])
+	})
+}
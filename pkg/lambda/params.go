@@ -0,0 +1,178 @@
+package lambda
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/oapi-codegen/runtime"
+	openapi_types "github.com/oapi-codegen/runtime/types"
+
+	"parking-lot/server/api"
+)
+
+// ProxyRequest wraps an API Gateway proxy request with normalized access to its headers,
+// query string and path parameters and binds them into the generated parameter structs
+type ProxyRequest events.APIGatewayProxyRequest
+
+// Header returns the first value of a header. API Gateway passes header names as the client sent
+// them, so the lookup ignores case and also checks the multi-value headers.
+func (r ProxyRequest) Header(name string) string {
+	for key, value := range r.Headers {
+		if strings.EqualFold(key, name) {
+			return strings.TrimSpace(value)
+		}
+	}
+	for key, values := range r.MultiValueHeaders {
+		if strings.EqualFold(key, name) && len(values) > 0 {
+			return strings.TrimSpace(values[0])
+		}
+	}
+	return ""
+}
+
+// SetHeader sets a header in both header maps, replacing any value stored under another casing
+func (r *ProxyRequest) SetHeader(name, value string) {
+	if r.Headers == nil {
+		r.Headers = make(map[string]string)
+	}
+	for key := range r.Headers {
+		if strings.EqualFold(key, name) {
+			delete(r.Headers, key)
+		}
+	}
+	r.Headers[http.CanonicalHeaderKey(name)] = value
+
+	for key := range r.MultiValueHeaders {
+		if strings.EqualFold(key, name) {
+			delete(r.MultiValueHeaders, key)
+		}
+	}
+	if r.MultiValueHeaders != nil {
+		r.MultiValueHeaders[http.CanonicalHeaderKey(name)] = []string{value}
+	}
+}
+
+// Query returns the query string parameters with surrounding whitespace trimmed. Multi-value
+// parameters take precedence since API Gateway keeps only the last value in the single-value map.
+func (r ProxyRequest) Query() url.Values {
+	query := make(url.Values)
+	for name, values := range r.MultiValueQueryStringParameters {
+		for _, value := range values {
+			query.Add(name, strings.TrimSpace(value))
+		}
+	}
+	for name, value := range r.QueryStringParameters {
+		if _, ok := query[name]; !ok {
+			query.Set(name, strings.TrimSpace(value))
+		}
+	}
+	return query
+}
+
+// PathParam returns a path parameter, unescaped when API Gateway passes it encoded
+func (r ProxyRequest) PathParam(name string) string {
+	value := r.PathParameters[name]
+	if unescaped, err := url.PathUnescape(value); err == nil {
+		value = unescaped
+	}
+	return strings.TrimSpace(value)
+}
+
+// EntryParams binds the parameters of POST /entry
+func (r ProxyRequest) EntryParams() (api.PostEntryParams, error) {
+	var params api.PostEntryParams
+	query := r.Query()
+	if err := bindQuery(query, "plate", false, &params.Plate); err != nil {
+		return params, err
+	}
+	if err := bindQuery(query, "parkingLot", true, &params.ParkingLot); err != nil {
+		return params, err
+	}
+	if err := bindQuery(query, "spaces", false, &params.Spaces); err != nil {
+		return params, err
+	}
+	if err := bindQuery(query, "make", false, &params.Make); err != nil {
+		return params, err
+	}
+	if err := bindQuery(query, "model", false, &params.Model); err != nil {
+		return params, err
+	}
+	if err := bindQuery(query, "color", false, &params.Color); err != nil {
+		return params, err
+	}
+	if err := bindQuery(query, "transponderId", false, &params.TransponderId); err != nil {
+		return params, err
+	}
+	if err := bindQuery(query, "expectedMinutes", false, &params.ExpectedMinutes); err != nil {
+		return params, err
+	}
+	return params, nil
+}
+
+// ExitParams binds the parameters of POST /exit
+func (r ProxyRequest) ExitParams() (api.PostExitParams, error) {
+	var params api.PostExitParams
+	query := r.Query()
+	if err := bindQuery(query, "ticketId", true, &params.TicketId); err != nil {
+		return params, err
+	}
+	if err := bindQuery(query, "couponCode", false, &params.CouponCode); err != nil {
+		return params, err
+	}
+	return params, nil
+}
+
+// QuoteParams binds the parameters of POST /quote
+func (r ProxyRequest) QuoteParams() (api.PostQuoteParams, error) {
+	var params api.PostQuoteParams
+	err := bindQuery(r.Query(), "ticketId", true, &params.TicketId)
+	return params, err
+}
+
+// MaintenanceParams binds the parameters of POST /admin/maintenance
+func (r ProxyRequest) MaintenanceParams() (api.PostAdminMaintenanceParams, error) {
+	var params api.PostAdminMaintenanceParams
+	err := bindQuery(r.Query(), "enabled", true, &params.Enabled)
+	return params, err
+}
+
+// ExportParams binds the parameters of GET /export
+func (r ProxyRequest) ExportParams() (api.GetExportParams, error) {
+	var params api.GetExportParams
+	query := r.Query()
+	if err := bindQuery(query, "from", true, &params.From); err != nil {
+		return params, err
+	}
+	if err := bindQuery(query, "to", true, &params.To); err != nil {
+		return params, err
+	}
+	if err := bindQuery(query, "format", false, &params.Format); err != nil {
+		return params, err
+	}
+	return params, nil
+}
+
+// TicketID binds the ticketId path parameter of GET /ticket/{ticketId}
+func (r ProxyRequest) TicketID() (openapi_types.UUID, error) {
+	var ticketID openapi_types.UUID
+	err := runtime.BindStyledParameterWithOptions("simple", "ticketId", r.PathParam("ticketId"), &ticketID,
+		runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		return ticketID, fmt.Errorf("invalid format for parameter ticketId: %w", err)
+	}
+	return ticketID, nil
+}
+
+// bindQuery binds a form-style query parameter the way the generated handlers do
+func bindQuery(query url.Values, name string, required bool, dest any) error {
+	if required && query.Get(name) == "" {
+		return fmt.Errorf("query argument %s is required, but not found", name)
+	}
+	if err := runtime.BindQueryParameter("form", true, required, name, query, dest); err != nil {
+		return fmt.Errorf("invalid format for parameter %s: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+//go:build !integration
+// +build !integration
+
+package lambda
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+
+	"parking-lot/server/api"
+)
+
+func TestProxyRequest_Header(t *testing.T) {
+	req := ProxyRequest{
+		Headers:           map[string]string{"x-request-id": " abc "},
+		MultiValueHeaders: map[string][]string{"X-Tenant-Table": {"tenantA", "tenantB"}},
+	}
+
+	assert.Equal(t, "abc", req.Header("X-Request-ID"))
+	assert.Equal(t, "tenantA", req.Header("x-tenant-table"))
+	assert.Empty(t, req.Header("Authorization"))
+
+	req.SetHeader("X-Request-ID", "def")
+	assert.Equal(t, map[string]string{"X-Request-Id": "def"}, req.Headers)
+	assert.Equal(t, []string{"def"}, req.MultiValueHeaders["X-Request-Id"])
+}
+
+func TestProxyRequest_Query(t *testing.T) {
+	req := ProxyRequest{
+		QueryStringParameters:           map[string]string{"plate": " ABC-123 ", "parkingLot": "9"},
+		MultiValueQueryStringParameters: map[string][]string{"parkingLot": {"3"}},
+	}
+
+	query := req.Query()
+
+	assert.Equal(t, "ABC-123", query.Get("plate"))
+	assert.Equal(t, "3", query.Get("parkingLot"))
+}
+
+func TestProxyRequest_EntryParams(t *testing.T) {
+	two, ninety, plate := 2, 90, "ABC-123"
+	tests := []struct {
+		name      string
+		query     map[string]string
+		expected  api.PostEntryParams
+		expectErr string
+	}{
+		{name: "Required only", query: map[string]string{"plate": "ABC-123", "parkingLot": "3"}, expected: api.PostEntryParams{Plate: &plate, ParkingLot: 3}},
+		{name: "With spaces", query: map[string]string{"plate": "ABC-123", "parkingLot": "3", "spaces": "2"}, expected: api.PostEntryParams{Plate: &plate, ParkingLot: 3, Spaces: &two}},
+		{name: "With expected stay", query: map[string]string{"plate": "ABC-123", "parkingLot": "3", "expectedMinutes": "90"}, expected: api.PostEntryParams{Plate: &plate, ParkingLot: 3, ExpectedMinutes: &ninety}},
+		// The plate is optional for anonymous entries; the handler rejects it unless ALLOW_ANONYMOUS=true
+		{name: "Missing plate", query: map[string]string{"parkingLot": "3"}, expected: api.PostEntryParams{ParkingLot: 3}},
+		// Sscanf would have read "3x" as lot 3
+		{name: "Trailing garbage", query: map[string]string{"plate": "ABC-123", "parkingLot": "3x"}, expectErr: "invalid format for parameter parkingLot"},
+		{name: "Invalid spaces", query: map[string]string{"plate": "ABC-123", "parkingLot": "3", "spaces": "two"}, expectErr: "invalid format for parameter spaces"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params, err := ProxyRequest{QueryStringParameters: tt.query}.EntryParams()
+
+			if tt.expectErr != "" {
+				assert.ErrorContains(t, err, tt.expectErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, params)
+		})
+	}
+}
+
+func TestProxyRequest_ExitParams(t *testing.T) {
+	ticketID := "123e4567-e89b-12d3-a456-426614174000"
+
+	params, err := ProxyRequest{QueryStringParameters: map[string]string{"ticketId": ticketID}}.ExitParams()
+	assert.NoError(t, err)
+	assert.Equal(t, ticketID, params.TicketId.String())
+	assert.Nil(t, params.CouponCode)
+
+	params, err = ProxyRequest{QueryStringParameters: map[string]string{"ticketId": ticketID, "couponCode": " SPRING10 "}}.ExitParams()
+	assert.NoError(t, err)
+	if assert.NotNil(t, params.CouponCode) {
+		assert.Equal(t, "SPRING10", *params.CouponCode)
+	}
+
+	_, err = ProxyRequest{QueryStringParameters: map[string]string{"ticketId": "not-a-uuid"}}.ExitParams()
+	assert.ErrorContains(t, err, "invalid format for parameter ticketId")
+}
+
+func TestProxyRequest_ExportParams(t *testing.T) {
+	params, err := ProxyRequest{QueryStringParameters: map[string]string{"from": "2024-05-01", "to": "2024-05-31", "format": "csv"}}.ExportParams()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "2024-05-01", params.From.String())
+	assert.Equal(t, "2024-05-31", params.To.String())
+	if assert.NotNil(t, params.Format) {
+		assert.Equal(t, api.Csv, *params.Format)
+	}
+
+	_, err = ProxyRequest{QueryStringParameters: map[string]string{"from": "2024-05-01"}}.ExportParams()
+	assert.ErrorContains(t, err, "to is required")
+}
+
+func TestProxyRequest_TicketID(t *testing.T) {
+	ticketID, err := ProxyRequest{PathParameters: map[string]string{"ticketId": "123e4567-e89b-12d3-a456-426614174000"}}.TicketID()
+	assert.NoError(t, err)
+	assert.Equal(t, "123e4567-e89b-12d3-a456-426614174000", ticketID.String())
+
+	_, err = ProxyRequest{}.TicketID()
+	assert.Error(t, err)
+}
+
+func TestProxyWithContext_HeaderCase(t *testing.T) {
+	adapter := setupTestAdapter()
+
+	// API Gateway passes header names as sent, e.g. lowercased by HTTP/2 clients
+	resp, err := adapter.ProxyWithContext(context.Background(), events.APIGatewayProxyRequest{
+		HTTPMethod: "GET",
+		Path:       "/nope",
+		Headers:    map[string]string{"x-request-id": "lower-id"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "lower-id", resp.Headers["X-Request-Id"])
+
+	// A request without any headers gets a generated ID instead of panicking
+	resp, err = adapter.ProxyWithContext(context.Background(), events.APIGatewayProxyRequest{HTTPMethod: "GET", Path: "/nope"})
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assert.NotEmpty(t, resp.Headers["X-Request-Id"])
+}
@@ -0,0 +1,52 @@
+package lambda
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+)
+
+// defaultLocalServerAddr is the address RunLocalServer listens on unless LOCAL_ADDR is set
+const defaultLocalServerAddr = ":8080"
+
+// newLocalServer creates the HTTP server used by RunLocalServer, tuned from the environment
+// to reproduce production-like connection handling when load-testing locally.
+//
+// LOCAL_ADDR sets the listen address (default ":8080"; ":0" picks a free port),
+// LOCAL_MAX_HEADER_BYTES limits the size of request headers, LOCAL_KEEP_ALIVE=false disables
+// keep-alive connections and LOCAL_H2C=true also serves HTTP/2 without TLS (h2c).
+func newLocalServer(handler http.Handler) (*http.Server, error) {
+	addr := os.Getenv("LOCAL_ADDR")
+	if addr == "" {
+		addr = defaultLocalServerAddr
+	}
+	srv := &http.Server{
+		Addr:    addr,
+		Handler: handler,
+	}
+
+	if raw := os.Getenv("LOCAL_MAX_HEADER_BYTES"); raw != "" {
+		maxHeaderBytes, err := strconv.Atoi(raw)
+		if err != nil || maxHeaderBytes <= 0 {
+			return nil, fmt.Errorf("invalid LOCAL_MAX_HEADER_BYTES %q", raw)
+		}
+		srv.MaxHeaderBytes = maxHeaderBytes
+	}
+
+	if raw := os.Getenv("LOCAL_KEEP_ALIVE"); raw != "" {
+		keepAlive, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid LOCAL_KEEP_ALIVE %q", raw)
+		}
+		srv.SetKeepAlivesEnabled(keepAlive)
+	}
+
+	if os.Getenv("LOCAL_H2C") == "true" {
+		srv.Protocols = new(http.Protocols)
+		srv.Protocols.SetHTTP1(true)
+		srv.Protocols.SetUnencryptedHTTP2(true)
+	}
+
+	return srv, nil
+}
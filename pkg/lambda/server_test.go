@@ -0,0 +1,223 @@
+//go:build !integration
+// +build !integration
+
+package lambda
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// serveLocal starts srv on a random port and returns its base URL
+func serveLocal(t *testing.T, srv *http.Server) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	go srv.Serve(listener)
+	t.Cleanup(func() { srv.Shutdown(context.Background()) })
+
+	return "http://" + listener.Addr().String()
+}
+
+func TestNewLocalServer_Tuned(t *testing.T) {
+	t.Setenv("LOCAL_MAX_HEADER_BYTES", "4096")
+	t.Setenv("LOCAL_KEEP_ALIVE", "false")
+	t.Setenv("LOCAL_H2C", "true")
+	t.Setenv("LOCAL_ADDR", "127.0.0.1:9090")
+
+	srv, err := newLocalServer(setupTestAdapter().Router())
+	require.NoError(t, err)
+
+	assert.Equal(t, "127.0.0.1:9090", srv.Addr)
+	assert.Equal(t, 4096, srv.MaxHeaderBytes)
+	require.NotNil(t, srv.Protocols)
+	assert.True(t, srv.Protocols.UnencryptedHTTP2())
+
+	url := serveLocal(t, srv)
+
+	t.Run("HTTP/1.1 without keep-alive", func(t *testing.T) {
+		resp, err := http.Get(url + "/nope")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+		assert.Equal(t, 1, resp.ProtoMajor)
+		assert.True(t, resp.Close)
+	})
+
+	t.Run("h2c", func(t *testing.T) {
+		protocols := new(http.Protocols)
+		protocols.SetUnencryptedHTTP2(true)
+		client := &http.Client{Transport: &http.Transport{Protocols: protocols}}
+
+		resp, err := client.Get(url + "/nope")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+		assert.Equal(t, 2, resp.ProtoMajor)
+	})
+}
+
+func TestNewLocalServer_Defaults(t *testing.T) {
+	t.Setenv("LOCAL_MAX_HEADER_BYTES", "")
+	t.Setenv("LOCAL_KEEP_ALIVE", "")
+	t.Setenv("LOCAL_H2C", "")
+	t.Setenv("LOCAL_ADDR", "")
+
+	srv, err := newLocalServer(setupTestAdapter().Router())
+	require.NoError(t, err)
+
+	assert.Equal(t, defaultLocalServerAddr, srv.Addr)
+	assert.Zero(t, srv.MaxHeaderBytes)
+	assert.Nil(t, srv.Protocols)
+}
+
+func TestNewLocalServer_InvalidConfig(t *testing.T) {
+	t.Setenv("LOCAL_MAX_HEADER_BYTES", "lots")
+
+	_, err := newLocalServer(setupTestAdapter().Router())
+
+	assert.EqualError(t, err, `invalid LOCAL_MAX_HEADER_BYTES "lots"`)
+}
+
+func TestStartLocalServer_EphemeralPort(t *testing.T) {
+	t.Setenv("LOCAL_ADDR", "127.0.0.1:0")
+
+	srv, listener, err := setupTestAdapter().startLocalServer()
+	require.NoError(t, err)
+	t.Cleanup(func() { srv.Shutdown(context.Background()) })
+
+	port := listener.Addr().(*net.TCPAddr).Port
+	assert.NotZero(t, port)
+
+	resp, err := http.Get(fmt.Sprintf("http://127.0.0.1:%d/nope", port))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestRunLocalServer(t *testing.T) {
+	t.Run("Address in use", func(t *testing.T) {
+		taken, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		defer taken.Close()
+		t.Setenv("LOCAL_ADDR", taken.Addr().String())
+
+		err = setupTestAdapter().RunLocalServer(context.Background())
+
+		assert.ErrorContains(t, err, "failed to listen on "+taken.Addr().String())
+	})
+
+	t.Run("Stops with context", func(t *testing.T) {
+		t.Setenv("LOCAL_ADDR", "127.0.0.1:0")
+		ctx, cancel := context.WithCancel(context.Background())
+
+		done := make(chan error, 1)
+		go func() { done <- setupTestAdapter().RunLocalServer(ctx) }()
+		cancel()
+
+		select {
+		case err := <-done:
+			assert.NoError(t, err)
+		case <-time.After(5 * time.Second):
+			t.Fatal("RunLocalServer did not stop after the context was canceled")
+		}
+	})
+
+	t.Run("Drains in-flight requests", func(t *testing.T) {
+		adapter, url := slowLocalAdapter(t, 300*time.Millisecond)
+		adapter.shutdownTimeout = 2 * time.Second
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error, 1)
+		go func() { done <- adapter.RunLocalServer(ctx) }()
+
+		status := make(chan int, 1)
+		go func() { status <- slowRequest(url) }()
+		require.Eventually(t, func() bool { return adapter.inFlight.Load() == 1 }, 2*time.Second, 5*time.Millisecond)
+
+		start := time.Now()
+		cancel()
+
+		assert.Equal(t, http.StatusOK, <-status)
+		select {
+		case err := <-done:
+			assert.NoError(t, err)
+			assert.Less(t, time.Since(start), adapter.shutdownTimeout)
+		case <-time.After(5 * time.Second):
+			t.Fatal("RunLocalServer did not stop after draining")
+		}
+		assert.Zero(t, adapter.inFlight.Load())
+	})
+
+	t.Run("Forces close after timeout", func(t *testing.T) {
+		adapter, url := slowLocalAdapter(t, 5*time.Second)
+		adapter.shutdownTimeout = 100 * time.Millisecond
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error, 1)
+		go func() { done <- adapter.RunLocalServer(ctx) }()
+
+		status := make(chan int, 1)
+		go func() { status <- slowRequest(url) }()
+		require.Eventually(t, func() bool { return adapter.inFlight.Load() == 1 }, 2*time.Second, 5*time.Millisecond)
+
+		start := time.Now()
+		cancel()
+
+		select {
+		case err := <-done:
+			assert.NoError(t, err)
+			assert.Less(t, time.Since(start), 2*time.Second)
+		case <-time.After(5 * time.Second):
+			t.Fatal("RunLocalServer did not force the slow request closed")
+		}
+		assert.Zero(t, <-status, "the cut-off request should fail")
+	})
+}
+
+// slowLocalAdapter returns a test adapter serving GET /slow, which takes delay, and the URL of that
+// route on the free port set as LOCAL_ADDR
+func slowLocalAdapter(t *testing.T, delay time.Duration) (*APIAdapter, string) {
+	free, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := free.Addr().String()
+	require.NoError(t, free.Close())
+	t.Setenv("LOCAL_ADDR", addr)
+
+	adapter := setupTestAdapter()
+	adapter.router.GET("/slow", func(c *gin.Context) {
+		select {
+		case <-time.After(delay):
+			c.Status(http.StatusOK)
+		case <-c.Request.Context().Done():
+		}
+	})
+	return adapter, "http://" + addr + "/slow"
+}
+
+// slowRequest calls url once the server accepts connections and returns the response status,
+// or zero when the request fails
+func slowRequest(url string) int {
+	for i := 0; i < 100; i++ {
+		resp, err := http.Get(url)
+		if err == nil {
+			resp.Body.Close()
+			return resp.StatusCode
+		}
+		var opErr *net.OpError
+		if !errors.As(err, &opErr) || opErr.Op != "dial" {
+			return 0
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return 0
+}
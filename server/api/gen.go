@@ -6,49 +6,462 @@ package api
 import (
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/oapi-codegen/runtime"
 	openapi_types "github.com/oapi-codegen/runtime/types"
 )
 
+const (
+	BearerAuthScopes = "bearerAuth.Scopes"
+)
+
+// Defines values for GetExportParamsFormat.
+const (
+	Csv GetExportParamsFormat = "csv"
+)
+
+// BatchEntry One vehicle entry, with the same fields as the POST /entry query parameters
+type BatchEntry struct {
+	// ParkingLot Number of the parking lot, between 1 and MAX_LOT
+	ParkingLot int `json:"parkingLot"`
+
+	// Plate License plate of the vehicle. Required unless ALLOW_ANONYMOUS is enabled.
+	Plate *string `json:"plate,omitempty"`
+
+	// Spaces Number of spaces the vehicle occupies. Defaults to 1.
+	Spaces *int `json:"spaces,omitempty"`
+}
+
+// BatchEntryRequest defines model for BatchEntryRequest.
+type BatchEntryRequest struct {
+	Entries []BatchEntry `json:"entries"`
+}
+
+// BatchEntryResponse defines model for BatchEntryResponse.
+type BatchEntryResponse struct {
+	// Results One result per entry, in request order
+	Results []BatchEntryResult `json:"results"`
+}
+
+// BatchEntryResult Outcome of one entry in a batch
+type BatchEntryResult struct {
+	Error *ErrorResponse `json:"error,omitempty"`
+
+	// Index Position of the entry in the request
+	Index int `json:"index"`
+
+	// Status HTTP status POST /entry would have answered the entry with
+	Status int            `json:"status"`
+	Ticket *EntryResponse `json:"ticket,omitempty"`
+}
+
+// Block defines model for Block.
+type Block struct {
+	BlockId openapi_types.UUID `json:"blockId"`
+
+	// EndTime End of the window the spaces are held back in
+	EndTime    time.Time `json:"endTime"`
+	ParkingLot int       `json:"parkingLot"`
+
+	// Spaces Number of spaces held back
+	Spaces int `json:"spaces"`
+
+	// StartTime Start of the window the spaces are held back in
+	StartTime time.Time `json:"startTime"`
+}
+
+// ChargeBreakdown Itemized charge; total is baseCharge plus surcharge less discount and priorCharge. Absent when the ticket had already exited.
+type ChargeBreakdown struct {
+	// BaseCharge Charge of the stay under the lot's pricing, or the quoted, minimum or maximum charge when one of them replaced it
+	BaseCharge float32 `json:"baseCharge"`
+
+	// Discount Amount a promo code took off
+	Discount float32 `json:"discount"`
+
+	// PriorCharge Amount earlier exits of a reopened ticket already billed for the stay, deducted from the total; absent when the ticket was never reopened
+	PriorCharge *float32 `json:"priorCharge,omitempty"`
+
+	// Surcharge Amount night and weekend surcharges added to the base charge
+	Surcharge float32 `json:"surcharge"`
+
+	// Total Charge billed; equal to the exit's charge
+	Total float32 `json:"total"`
+}
+
 // EntryResponse defines model for EntryResponse.
 type EntryResponse struct {
-	TicketId openapi_types.UUID `json:"ticketId"`
+	// EstimatedCharge Charge of a stay of expectedMinutes at the lot's current rates; absent when expectedMinutes was not given. The charge at exit is computed from the actual stay.
+	EstimatedCharge *float32 `json:"estimatedCharge,omitempty"`
+
+	// Plate Placeholder plate generated for an anonymous entry; absent when a plate was given
+	Plate *string `json:"plate,omitempty"`
+
+	// RateInfo Rate schedule applied to the parking session
+	RateInfo *RateInfo `json:"rateInfo,omitempty"`
+
+	// SessionId Parking session the ticket belongs to; stays the same from entry through exit and re-entry. Absent for tickets issued before sessions were introduced.
+	SessionId *openapi_types.UUID `json:"sessionId,omitempty"`
+
+	// SpotNumber Numbered spot assigned to the vehicle in lots configured with spots; absent otherwise
+	SpotNumber *int               `json:"spotNumber,omitempty"`
+	TicketId   openapi_types.UUID `json:"ticketId"`
 }
 
 // ErrorResponse defines model for ErrorResponse.
 type ErrorResponse struct {
-	Message string `json:"message"`
+	// Code Stable machine-readable error code, e.g. TICKET_NOT_FOUND, LOT_FULL or VALIDATION_FAILED
+	Code string `json:"code"`
+
+	// Details Individual request validation failures
+	Details *[]string `json:"details,omitempty"`
+	Message string    `json:"message"`
+
+	// TicketId ID of the ticket the error refers to
+	TicketId *openapi_types.UUID `json:"ticketId,omitempty"`
 }
 
 // ExitResponse defines model for ExitResponse.
 type ExitResponse struct {
-	Charge                float32 `json:"charge"`
-	ParkedDurationMinutes int     `json:"parkedDurationMinutes"`
+	// AlreadyExited True when the ticket had already exited and the recorded charge is returned
+	AlreadyExited bool    `json:"alreadyExited"`
+	Charge        float32 `json:"charge"`
+
+	// ChargeBreakdown Itemized charge; total is baseCharge plus surcharge less discount and priorCharge. Absent when the ticket had already exited.
+	ChargeBreakdown *ChargeBreakdown `json:"chargeBreakdown,omitempty"`
+
+	// ChargeFormatted Charge formatted for the lot's locale, e.g. for display on an exit kiosk
+	ChargeFormatted *string `json:"chargeFormatted,omitempty"`
+
+	// CouponCode Promo code that discounted the charge; absent when no coupon was applied
+	CouponCode *string `json:"couponCode,omitempty"`
+
+	// Currency ISO 4217 code of the charge, from the lot's configuration or the rate schedule
+	Currency *string `json:"currency,omitempty"`
+
+	// Flagged True when the stay exceeded MAX_PARK_DURATION, e.g. for towing or enforcement follow-up; absent otherwise. The charge only covers the allowed duration.
+	Flagged               *bool `json:"flagged,omitempty"`
+	ParkedDurationMinutes int   `json:"parkedDurationMinutes"`
+
+	// ParkedDurationSeconds Exact parked duration in seconds
+	ParkedDurationSeconds float64 `json:"parkedDurationSeconds"`
 	ParkingLot            int     `json:"parkingLot"`
 	Plate                 string  `json:"plate"`
+
+	// Reason Why the exit was flagged
+	Reason *string `json:"reason,omitempty"`
+
+	// SessionId Parking session the ticket belongs to; stays the same from entry through exit and re-entry. Absent for tickets issued before sessions were introduced.
+	SessionId *openapi_types.UUID `json:"sessionId,omitempty"`
+}
+
+// HealthResponse defines model for HealthResponse.
+type HealthResponse struct {
+	Status string `json:"status"`
+}
+
+// LotSessions defines model for LotSessions.
+type LotSessions struct {
+	ParkingLot int `json:"parkingLot"`
+
+	// Sessions Active tickets of the plate in the lot, newest entry first
+	Sessions []TicketResponse `json:"sessions"`
+}
+
+// MaintenanceResponse defines model for MaintenanceResponse.
+type MaintenanceResponse struct {
+	// Maintenance Whether new entries are currently rejected
+	Maintenance bool `json:"maintenance"`
+}
+
+// OccupancyResponse defines model for OccupancyResponse.
+type OccupancyResponse struct {
+	// Occupied Spaces held by the lot's parked tickets, which the occupancy counter was reset to
+	Occupied   int `json:"occupied"`
+	ParkingLot int `json:"parkingLot"`
+}
+
+// OutstandingRevenueResponse defines model for OutstandingRevenueResponse.
+type OutstandingRevenueResponse struct {
+	ParkingLot int `json:"parkingLot"`
+
+	// Revenue What the vehicles parked in the lot would be charged if they all exited now
+	Revenue float32 `json:"revenue"`
+
+	// Truncated Whether reading the parked vehicles stopped at MAX_SCAN_PAGES, so the revenue only covers part of the lot
+	Truncated bool `json:"truncated"`
+}
+
+// PlateActiveResponse defines model for PlateActiveResponse.
+type PlateActiveResponse struct {
+	// Lots Lots the plate is parked in, in ascending lot order
+	Lots  []LotSessions `json:"lots"`
+	Plate string        `json:"plate"`
+}
+
+// PlateCorrection defines model for PlateCorrection.
+type PlateCorrection struct {
+	// Plate Corrected license plate of the vehicle
+	Plate string `json:"plate"`
+}
+
+// PlateHistoryResponse defines model for PlateHistoryResponse.
+type PlateHistoryResponse struct {
+	Plate string `json:"plate"`
+
+	// Sessions Exited tickets of the plate, newest entry first
+	Sessions []TicketResponse `json:"sessions"`
+}
+
+// QuoteResponse defines model for QuoteResponse.
+type QuoteResponse struct {
+	Charge float32 `json:"charge"`
+
+	// ExitBy Exits until this time are charged the quoted amount; absent when quotes are not honored (EXIT_WINDOW_MINUTES=0)
+	ExitBy                *time.Time `json:"exitBy,omitempty"`
+	ParkedDurationMinutes int        `json:"parkedDurationMinutes"`
+
+	// QuotedAt When the charge was computed
+	QuotedAt time.Time `json:"quotedAt"`
+}
+
+// RateInfo Rate schedule applied to the parking session
+type RateInfo struct {
+	Currency         string   `json:"currency"`
+	DailyMax         *float32 `json:"dailyMax,omitempty"`
+	IncrementMinutes int      `json:"incrementMinutes"`
+	RatePerIncrement float32  `json:"ratePerIncrement"`
+}
+
+// ReissueResponse defines model for ReissueResponse.
+type ReissueResponse struct {
+	// PreviousTicketId Ticket ID that was replaced and no longer resolves
+	PreviousTicketId openapi_types.UUID `json:"previousTicketId"`
+	Ticket           TicketResponse     `json:"ticket"`
+}
+
+// TicketResponse defines model for TicketResponse.
+type TicketResponse struct {
+	// Charge Charge recorded at exit; absent while the vehicle is parked
+	Charge *float32 `json:"charge,omitempty"`
+
+	// Color Color of the vehicle given at entry; absent when none was given
+	Color     *string   `json:"color,omitempty"`
+	EntryTime time.Time `json:"entryTime"`
+
+	// ExitTime When the vehicle exited; absent while it is parked
+	ExitTime *time.Time `json:"exitTime,omitempty"`
+
+	// Make Make of the vehicle given at entry; absent when none was given
+	Make *string `json:"make,omitempty"`
+
+	// Model Model of the vehicle given at entry; absent when none was given
+	Model      *string `json:"model,omitempty"`
+	ParkingLot int     `json:"parkingLot"`
+	Plate      string  `json:"plate"`
+
+	// PriorCharge Charge billed at earlier exits of the session before the ticket was reopened; the charge only covers the stay beyond it. Absent when the ticket was never reopened.
+	PriorCharge *float32 `json:"priorCharge,omitempty"`
+
+	// RefundAmount Part of the charge refunded after exit; the charge already has it deducted. Absent when nothing was refunded.
+	RefundAmount *float32 `json:"refundAmount,omitempty"`
+
+	// SessionId Parking session the ticket belongs to; stays the same from entry through exit and re-entry. Absent for tickets issued before sessions were introduced.
+	SessionId *openapi_types.UUID `json:"sessionId,omitempty"`
+
+	// Status Either "in" while the vehicle is parked or "out" once it has exited
+	Status   string             `json:"status"`
+	TicketId openapi_types.UUID `json:"ticketId"`
+}
+
+// PostAdminBlockParams defines parameters for PostAdminBlock.
+type PostAdminBlockParams struct {
+	// ParkingLot Number of the parking lot, between 1 and MAX_LOT
+	ParkingLot int `form:"parkingLot" json:"parkingLot"`
+
+	// Spaces Number of spaces to hold back
+	Spaces int `form:"spaces" json:"spaces"`
+
+	// Start Start of the block. Defaults to now.
+	Start *time.Time `form:"start,omitempty" json:"start,omitempty"`
+
+	// End End of the block
+	End time.Time `form:"end" json:"end"`
+}
+
+// PostAdminMaintenanceParams defines parameters for PostAdminMaintenance.
+type PostAdminMaintenanceParams struct {
+	// Enabled Turn maintenance mode on (true) or off (false)
+	Enabled bool `form:"enabled" json:"enabled"`
+}
+
+// PostAdminOccupancyReconcileParams defines parameters for PostAdminOccupancyReconcile.
+type PostAdminOccupancyReconcileParams struct {
+	// ParkingLot Number of the parking lot, between 1 and MAX_LOT
+	ParkingLot int `form:"parkingLot" json:"parkingLot"`
+}
+
+// DeleteAdminTicketParams defines parameters for DeleteAdminTicket.
+type DeleteAdminTicketParams struct {
+	// Plate License plate whose active ticket is deleted
+	Plate string `form:"plate" json:"plate"`
 }
 
 // PostEntryParams defines parameters for PostEntry.
 type PostEntryParams struct {
-	Plate      string `form:"plate" json:"plate"`
-	ParkingLot int    `form:"parkingLot" json:"parkingLot"`
+	// Plate License plate of the vehicle. Required unless ALLOW_ANONYMOUS is enabled, in which case a missing plate issues an anonymous ticket with a placeholder plate.
+	Plate *string `form:"plate,omitempty" json:"plate,omitempty"`
+
+	// ParkingLot Number of the parking lot, between 1 and MAX_LOT
+	ParkingLot int `form:"parkingLot" json:"parkingLot"`
+
+	// Spaces Number of spaces the vehicle occupies (e.g. 2 for a truck). Defaults to 1.
+	Spaces *int `form:"spaces,omitempty" json:"spaces,omitempty"`
+
+	// Make Make of the vehicle, recorded on the ticket for security staff
+	Make *string `form:"make,omitempty" json:"make,omitempty"`
+
+	// Model Model of the vehicle, recorded on the ticket for security staff
+	Model *string `form:"model,omitempty" json:"model,omitempty"`
+
+	// Color Color of the vehicle, recorded on the ticket for security staff
+	Color *string `form:"color,omitempty" json:"color,omitempty"`
+
+	// TransponderId Toll-style transponder identifying the vehicle. The vehicle can exit by transponder, and an entry with a transponder but no plate gets a placeholder plate.
+	TransponderId *string `form:"transponderId,omitempty" json:"transponderId,omitempty"`
+
+	// ExpectedMinutes Stay the driver expects, in minutes. When given, the response previews its charge; billing at exit is unaffected.
+	ExpectedMinutes *int `form:"expectedMinutes,omitempty" json:"expectedMinutes,omitempty"`
 }
 
 // PostExitParams defines parameters for PostExit.
 type PostExitParams struct {
 	TicketId openapi_types.UUID `form:"ticketId" json:"ticketId"`
+
+	// CouponCode Promo code discounting the charge. Unknown and expired codes are ignored and the full charge applies.
+	CouponCode *string `form:"couponCode,omitempty" json:"couponCode,omitempty"`
+}
+
+// PostExitTransponderParams defines parameters for PostExitTransponder.
+type PostExitTransponderParams struct {
+	TransponderId string `form:"transponderId" json:"transponderId"`
+
+	// CouponCode Promo code discounting the charge. Unknown and expired codes are ignored and the full charge applies.
+	CouponCode *string `form:"couponCode,omitempty" json:"couponCode,omitempty"`
+}
+
+// GetExportParams defines parameters for GetExport.
+type GetExportParams struct {
+	// From First exit day to include (UTC)
+	From openapi_types.Date `form:"from" json:"from"`
+
+	// To Last exit day to include (UTC)
+	To openapi_types.Date `form:"to" json:"to"`
+
+	// Format Export format. Defaults to csv.
+	Format *GetExportParamsFormat `form:"format,omitempty" json:"format,omitempty"`
+}
+
+// GetExportParamsFormat defines parameters for GetExport.
+type GetExportParamsFormat string
+
+// GetPlatePlateHistoryParams defines parameters for GetPlatePlateHistory.
+type GetPlatePlateHistoryParams struct {
+	// TicketId ID of a ticket issued to the plate, authorizing a driver without the admin token
+	TicketId *openapi_types.UUID `form:"ticketId,omitempty" json:"ticketId,omitempty"`
+
+	// Limit Most sessions to return. Defaults to 10; larger values are clamped to MAX_PAGE_SIZE (default 100).
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
+}
+
+// PostQuoteParams defines parameters for PostQuote.
+type PostQuoteParams struct {
+	TicketId openapi_types.UUID `form:"ticketId" json:"ticketId"`
+}
+
+// GetRevenueOutstandingParams defines parameters for GetRevenueOutstanding.
+type GetRevenueOutstandingParams struct {
+	// ParkingLot Number of the parking lot, between 1 and MAX_LOT
+	ParkingLot int `form:"parkingLot" json:"parkingLot"`
+}
+
+// PostTicketReissueParams defines parameters for PostTicketReissue.
+type PostTicketReissueParams struct {
+	Plate string `form:"plate" json:"plate"`
+}
+
+// PostTicketTicketIdRefundParams defines parameters for PostTicketTicketIdRefund.
+type PostTicketTicketIdRefundParams struct {
+	// Amount Amount to refund. Defaults to the whole charge.
+	Amount *float32 `form:"amount,omitempty" json:"amount,omitempty"`
 }
 
+// PostEntryBatchJSONRequestBody defines body for PostEntryBatch for application/json ContentType.
+type PostEntryBatchJSONRequestBody = BatchEntryRequest
+
+// PatchTicketTicketIdJSONRequestBody defines body for PatchTicketTicketId for application/json ContentType.
+type PatchTicketTicketIdJSONRequestBody = PlateCorrection
+
 // ServerInterface represents all server handlers.
 type ServerInterface interface {
+	// Hold back spaces of a lot for an event
+	// (POST /admin/block)
+	PostAdminBlock(c *gin.Context, params PostAdminBlockParams)
+	// Turn maintenance mode on or off
+	// (POST /admin/maintenance)
+	PostAdminMaintenance(c *gin.Context, params PostAdminMaintenanceParams)
+	// Reset a lot's occupancy counter to its parked tickets
+	// (POST /admin/occupancy/reconcile)
+	PostAdminOccupancyReconcile(c *gin.Context, params PostAdminOccupancyReconcileParams)
+	// Delete the active ticket of a plate created in error
+	// (DELETE /admin/ticket)
+	DeleteAdminTicket(c *gin.Context, params DeleteAdminTicketParams)
 	// Record vehicle entry and generate ticket
 	// (POST /entry)
 	PostEntry(c *gin.Context, params PostEntryParams)
+	// Record several vehicle entries at once
+	// (POST /entry/batch)
+	PostEntryBatch(c *gin.Context)
 	// Calculate fee and complete vehicle exit
 	// (POST /exit)
 	PostExit(c *gin.Context, params PostExitParams)
+	// Complete the exit of the vehicle with a transponder
+	// (POST /exit/transponder)
+	PostExitTransponder(c *gin.Context, params PostExitTransponderParams)
+	// Export exited tickets as CSV
+	// (GET /export)
+	GetExport(c *gin.Context, params GetExportParams)
+	// Report that the process is alive
+	// (GET /livez)
+	GetLivez(c *gin.Context)
+	// List the lots a plate is parked in
+	// (GET /plate/{plate}/active)
+	GetPlatePlateActive(c *gin.Context, plate string)
+	// List the past parking sessions of a plate
+	// (GET /plate/{plate}/history)
+	GetPlatePlateHistory(c *gin.Context, plate string, params GetPlatePlateHistoryParams)
+	// Quote the charge for a parked vehicle at a kiosk
+	// (POST /quote)
+	PostQuote(c *gin.Context, params PostQuoteParams)
+	// Report whether the service can handle traffic
+	// (GET /readyz)
+	GetReadyz(c *gin.Context)
+	// Estimate the revenue owed by parked vehicles
+	// (GET /revenue/outstanding)
+	GetRevenueOutstanding(c *gin.Context, params GetRevenueOutstandingParams)
+	// Reissue the ticket of a parked vehicle
+	// (POST /ticket/reissue)
+	PostTicketReissue(c *gin.Context, params PostTicketReissueParams)
+	// Look up the current state of a ticket
+	// (GET /ticket/{ticketId})
+	GetTicketTicketId(c *gin.Context, ticketId openapi_types.UUID)
+	// Correct the plate of a ticket
+	// (PATCH /ticket/{ticketId})
+	PatchTicketTicketId(c *gin.Context, ticketId openapi_types.UUID)
+	// Refund the charge of a recently exited ticket
+	// (POST /ticket/{ticketId}/refund)
+	PostTicketTicketIdRefund(c *gin.Context, ticketId openapi_types.UUID, params PostTicketTicketIdRefundParams)
 }
 
 // ServerInterfaceWrapper converts contexts to parameters.
@@ -60,29 +473,124 @@ type ServerInterfaceWrapper struct {
 
 type MiddlewareFunc func(c *gin.Context)
 
-// PostEntry operation middleware
-func (siw *ServerInterfaceWrapper) PostEntry(c *gin.Context) {
+// PostAdminBlock operation middleware
+func (siw *ServerInterfaceWrapper) PostAdminBlock(c *gin.Context) {
 
 	var err error
 
+	c.Set(BearerAuthScopes, []string{})
+
 	// Parameter object where we will unmarshal all parameters from the context
-	var params PostEntryParams
+	var params PostAdminBlockParams
 
-	// ------------- Required query parameter "plate" -------------
+	// ------------- Required query parameter "parkingLot" -------------
 
-	if paramValue := c.Query("plate"); paramValue != "" {
+	if paramValue := c.Query("parkingLot"); paramValue != "" {
 
 	} else {
-		siw.ErrorHandler(c, fmt.Errorf("Query argument plate is required, but not found"), http.StatusBadRequest)
+		siw.ErrorHandler(c, fmt.Errorf("Query argument parkingLot is required, but not found"), http.StatusBadRequest)
 		return
 	}
 
-	err = runtime.BindQueryParameter("form", true, true, "plate", c.Request.URL.Query(), &params.Plate)
+	err = runtime.BindQueryParameter("form", true, true, "parkingLot", c.Request.URL.Query(), &params.ParkingLot)
 	if err != nil {
-		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter plate: %w", err), http.StatusBadRequest)
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter parkingLot: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Required query parameter "spaces" -------------
+
+	if paramValue := c.Query("spaces"); paramValue != "" {
+
+	} else {
+		siw.ErrorHandler(c, fmt.Errorf("Query argument spaces is required, but not found"), http.StatusBadRequest)
+		return
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "spaces", c.Request.URL.Query(), &params.Spaces)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter spaces: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "start" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "start", c.Request.URL.Query(), &params.Start)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter start: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Required query parameter "end" -------------
+
+	if paramValue := c.Query("end"); paramValue != "" {
+
+	} else {
+		siw.ErrorHandler(c, fmt.Errorf("Query argument end is required, but not found"), http.StatusBadRequest)
+		return
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "end", c.Request.URL.Query(), &params.End)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter end: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.PostAdminBlock(c, params)
+}
+
+// PostAdminMaintenance operation middleware
+func (siw *ServerInterfaceWrapper) PostAdminMaintenance(c *gin.Context) {
+
+	var err error
+
+	c.Set(BearerAuthScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PostAdminMaintenanceParams
+
+	// ------------- Required query parameter "enabled" -------------
+
+	if paramValue := c.Query("enabled"); paramValue != "" {
+
+	} else {
+		siw.ErrorHandler(c, fmt.Errorf("Query argument enabled is required, but not found"), http.StatusBadRequest)
+		return
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "enabled", c.Request.URL.Query(), &params.Enabled)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter enabled: %w", err), http.StatusBadRequest)
 		return
 	}
 
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.PostAdminMaintenance(c, params)
+}
+
+// PostAdminOccupancyReconcile operation middleware
+func (siw *ServerInterfaceWrapper) PostAdminOccupancyReconcile(c *gin.Context) {
+
+	var err error
+
+	c.Set(BearerAuthScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PostAdminOccupancyReconcileParams
+
 	// ------------- Required query parameter "parkingLot" -------------
 
 	if paramValue := c.Query("parkingLot"); paramValue != "" {
@@ -105,29 +613,31 @@ func (siw *ServerInterfaceWrapper) PostEntry(c *gin.Context) {
 		}
 	}
 
-	siw.Handler.PostEntry(c, params)
+	siw.Handler.PostAdminOccupancyReconcile(c, params)
 }
 
-// PostExit operation middleware
-func (siw *ServerInterfaceWrapper) PostExit(c *gin.Context) {
+// DeleteAdminTicket operation middleware
+func (siw *ServerInterfaceWrapper) DeleteAdminTicket(c *gin.Context) {
 
 	var err error
 
+	c.Set(BearerAuthScopes, []string{})
+
 	// Parameter object where we will unmarshal all parameters from the context
-	var params PostExitParams
+	var params DeleteAdminTicketParams
 
-	// ------------- Required query parameter "ticketId" -------------
+	// ------------- Required query parameter "plate" -------------
 
-	if paramValue := c.Query("ticketId"); paramValue != "" {
+	if paramValue := c.Query("plate"); paramValue != "" {
 
 	} else {
-		siw.ErrorHandler(c, fmt.Errorf("Query argument ticketId is required, but not found"), http.StatusBadRequest)
+		siw.ErrorHandler(c, fmt.Errorf("Query argument plate is required, but not found"), http.StatusBadRequest)
 		return
 	}
 
-	err = runtime.BindQueryParameter("form", true, true, "ticketId", c.Request.URL.Query(), &params.TicketId)
+	err = runtime.BindQueryParameter("form", true, true, "plate", c.Request.URL.Query(), &params.Plate)
 	if err != nil {
-		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter ticketId: %w", err), http.StatusBadRequest)
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter plate: %w", err), http.StatusBadRequest)
 		return
 	}
 
@@ -138,7 +648,536 @@ func (siw *ServerInterfaceWrapper) PostExit(c *gin.Context) {
 		}
 	}
 
-	siw.Handler.PostExit(c, params)
+	siw.Handler.DeleteAdminTicket(c, params)
+}
+
+// PostEntry operation middleware
+func (siw *ServerInterfaceWrapper) PostEntry(c *gin.Context) {
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PostEntryParams
+
+	// ------------- Optional query parameter "plate" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "plate", c.Request.URL.Query(), &params.Plate)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter plate: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Required query parameter "parkingLot" -------------
+
+	if paramValue := c.Query("parkingLot"); paramValue != "" {
+
+	} else {
+		siw.ErrorHandler(c, fmt.Errorf("Query argument parkingLot is required, but not found"), http.StatusBadRequest)
+		return
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "parkingLot", c.Request.URL.Query(), &params.ParkingLot)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter parkingLot: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "spaces" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "spaces", c.Request.URL.Query(), &params.Spaces)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter spaces: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "make" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "make", c.Request.URL.Query(), &params.Make)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter make: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "model" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "model", c.Request.URL.Query(), &params.Model)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter model: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "color" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "color", c.Request.URL.Query(), &params.Color)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter color: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "transponderId" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "transponderId", c.Request.URL.Query(), &params.TransponderId)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter transponderId: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "expectedMinutes" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "expectedMinutes", c.Request.URL.Query(), &params.ExpectedMinutes)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter expectedMinutes: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.PostEntry(c, params)
+}
+
+// PostEntryBatch operation middleware
+func (siw *ServerInterfaceWrapper) PostEntryBatch(c *gin.Context) {
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.PostEntryBatch(c)
+}
+
+// PostExit operation middleware
+func (siw *ServerInterfaceWrapper) PostExit(c *gin.Context) {
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PostExitParams
+
+	// ------------- Required query parameter "ticketId" -------------
+
+	if paramValue := c.Query("ticketId"); paramValue != "" {
+
+	} else {
+		siw.ErrorHandler(c, fmt.Errorf("Query argument ticketId is required, but not found"), http.StatusBadRequest)
+		return
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "ticketId", c.Request.URL.Query(), &params.TicketId)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter ticketId: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "couponCode" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "couponCode", c.Request.URL.Query(), &params.CouponCode)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter couponCode: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.PostExit(c, params)
+}
+
+// PostExitTransponder operation middleware
+func (siw *ServerInterfaceWrapper) PostExitTransponder(c *gin.Context) {
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PostExitTransponderParams
+
+	// ------------- Required query parameter "transponderId" -------------
+
+	if paramValue := c.Query("transponderId"); paramValue != "" {
+
+	} else {
+		siw.ErrorHandler(c, fmt.Errorf("Query argument transponderId is required, but not found"), http.StatusBadRequest)
+		return
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "transponderId", c.Request.URL.Query(), &params.TransponderId)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter transponderId: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "couponCode" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "couponCode", c.Request.URL.Query(), &params.CouponCode)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter couponCode: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.PostExitTransponder(c, params)
+}
+
+// GetExport operation middleware
+func (siw *ServerInterfaceWrapper) GetExport(c *gin.Context) {
+
+	var err error
+
+	c.Set(BearerAuthScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetExportParams
+
+	// ------------- Required query parameter "from" -------------
+
+	if paramValue := c.Query("from"); paramValue != "" {
+
+	} else {
+		siw.ErrorHandler(c, fmt.Errorf("Query argument from is required, but not found"), http.StatusBadRequest)
+		return
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "from", c.Request.URL.Query(), &params.From)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter from: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Required query parameter "to" -------------
+
+	if paramValue := c.Query("to"); paramValue != "" {
+
+	} else {
+		siw.ErrorHandler(c, fmt.Errorf("Query argument to is required, but not found"), http.StatusBadRequest)
+		return
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "to", c.Request.URL.Query(), &params.To)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter to: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "format" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "format", c.Request.URL.Query(), &params.Format)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter format: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.GetExport(c, params)
+}
+
+// GetLivez operation middleware
+func (siw *ServerInterfaceWrapper) GetLivez(c *gin.Context) {
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.GetLivez(c)
+}
+
+// GetPlatePlateActive operation middleware
+func (siw *ServerInterfaceWrapper) GetPlatePlateActive(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "plate" -------------
+	var plate string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "plate", c.Param("plate"), &plate, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter plate: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	c.Set(BearerAuthScopes, []string{})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.GetPlatePlateActive(c, plate)
+}
+
+// GetPlatePlateHistory operation middleware
+func (siw *ServerInterfaceWrapper) GetPlatePlateHistory(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "plate" -------------
+	var plate string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "plate", c.Param("plate"), &plate, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter plate: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	c.Set(BearerAuthScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetPlatePlateHistoryParams
+
+	// ------------- Optional query parameter "ticketId" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "ticketId", c.Request.URL.Query(), &params.TicketId)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter ticketId: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", c.Request.URL.Query(), &params.Limit)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter limit: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.GetPlatePlateHistory(c, plate, params)
+}
+
+// PostQuote operation middleware
+func (siw *ServerInterfaceWrapper) PostQuote(c *gin.Context) {
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PostQuoteParams
+
+	// ------------- Required query parameter "ticketId" -------------
+
+	if paramValue := c.Query("ticketId"); paramValue != "" {
+
+	} else {
+		siw.ErrorHandler(c, fmt.Errorf("Query argument ticketId is required, but not found"), http.StatusBadRequest)
+		return
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "ticketId", c.Request.URL.Query(), &params.TicketId)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter ticketId: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.PostQuote(c, params)
+}
+
+// GetReadyz operation middleware
+func (siw *ServerInterfaceWrapper) GetReadyz(c *gin.Context) {
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.GetReadyz(c)
+}
+
+// GetRevenueOutstanding operation middleware
+func (siw *ServerInterfaceWrapper) GetRevenueOutstanding(c *gin.Context) {
+
+	var err error
+
+	c.Set(BearerAuthScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetRevenueOutstandingParams
+
+	// ------------- Required query parameter "parkingLot" -------------
+
+	if paramValue := c.Query("parkingLot"); paramValue != "" {
+
+	} else {
+		siw.ErrorHandler(c, fmt.Errorf("Query argument parkingLot is required, but not found"), http.StatusBadRequest)
+		return
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "parkingLot", c.Request.URL.Query(), &params.ParkingLot)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter parkingLot: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.GetRevenueOutstanding(c, params)
+}
+
+// PostTicketReissue operation middleware
+func (siw *ServerInterfaceWrapper) PostTicketReissue(c *gin.Context) {
+
+	var err error
+
+	c.Set(BearerAuthScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PostTicketReissueParams
+
+	// ------------- Required query parameter "plate" -------------
+
+	if paramValue := c.Query("plate"); paramValue != "" {
+
+	} else {
+		siw.ErrorHandler(c, fmt.Errorf("Query argument plate is required, but not found"), http.StatusBadRequest)
+		return
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "plate", c.Request.URL.Query(), &params.Plate)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter plate: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.PostTicketReissue(c, params)
+}
+
+// GetTicketTicketId operation middleware
+func (siw *ServerInterfaceWrapper) GetTicketTicketId(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "ticketId" -------------
+	var ticketId openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "ticketId", c.Param("ticketId"), &ticketId, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter ticketId: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.GetTicketTicketId(c, ticketId)
+}
+
+// PatchTicketTicketId operation middleware
+func (siw *ServerInterfaceWrapper) PatchTicketTicketId(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "ticketId" -------------
+	var ticketId openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "ticketId", c.Param("ticketId"), &ticketId, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter ticketId: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	c.Set(BearerAuthScopes, []string{})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.PatchTicketTicketId(c, ticketId)
+}
+
+// PostTicketTicketIdRefund operation middleware
+func (siw *ServerInterfaceWrapper) PostTicketTicketIdRefund(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "ticketId" -------------
+	var ticketId openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "ticketId", c.Param("ticketId"), &ticketId, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter ticketId: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	c.Set(BearerAuthScopes, []string{})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PostTicketTicketIdRefundParams
+
+	// ------------- Optional query parameter "amount" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "amount", c.Request.URL.Query(), &params.Amount)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter amount: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.PostTicketTicketIdRefund(c, ticketId, params)
 }
 
 // GinServerOptions provides options for the Gin server.
@@ -168,6 +1207,23 @@ func RegisterHandlersWithOptions(router gin.IRouter, si ServerInterface, options
 		ErrorHandler:       errorHandler,
 	}
 
+	router.POST(options.BaseURL+"/admin/block", wrapper.PostAdminBlock)
+	router.POST(options.BaseURL+"/admin/maintenance", wrapper.PostAdminMaintenance)
+	router.POST(options.BaseURL+"/admin/occupancy/reconcile", wrapper.PostAdminOccupancyReconcile)
+	router.DELETE(options.BaseURL+"/admin/ticket", wrapper.DeleteAdminTicket)
 	router.POST(options.BaseURL+"/entry", wrapper.PostEntry)
+	router.POST(options.BaseURL+"/entry/batch", wrapper.PostEntryBatch)
 	router.POST(options.BaseURL+"/exit", wrapper.PostExit)
+	router.POST(options.BaseURL+"/exit/transponder", wrapper.PostExitTransponder)
+	router.GET(options.BaseURL+"/export", wrapper.GetExport)
+	router.GET(options.BaseURL+"/livez", wrapper.GetLivez)
+	router.GET(options.BaseURL+"/plate/:plate/active", wrapper.GetPlatePlateActive)
+	router.GET(options.BaseURL+"/plate/:plate/history", wrapper.GetPlatePlateHistory)
+	router.POST(options.BaseURL+"/quote", wrapper.PostQuote)
+	router.GET(options.BaseURL+"/readyz", wrapper.GetReadyz)
+	router.GET(options.BaseURL+"/revenue/outstanding", wrapper.GetRevenueOutstanding)
+	router.POST(options.BaseURL+"/ticket/reissue", wrapper.PostTicketReissue)
+	router.GET(options.BaseURL+"/ticket/:ticketId", wrapper.GetTicketTicketId)
+	router.PATCH(options.BaseURL+"/ticket/:ticketId", wrapper.PatchTicketTicketId)
+	router.POST(options.BaseURL+"/ticket/:ticketId/refund", wrapper.PostTicketTicketIdRefund)
 }
@@ -4,8 +4,10 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	openapi_types "github.com/oapi-codegen/runtime/types"
 	"github.com/stretchr/testify/assert"
 
 	"parking-lot/server/api"
@@ -15,6 +17,84 @@ import (
 type dummyServer struct {
 	lastEntryParams api.PostEntryParams
 	lastExitParams  api.PostExitParams
+	lastQuoteParams api.PostQuoteParams
+	batchCalled     bool
+
+	lastExportParams api.GetExportParams
+
+	lastActivePlate   string
+	lastHistoryPlate  string
+	lastHistoryParams api.GetPlatePlateHistoryParams
+
+	lastBlockParams       api.PostAdminBlockParams
+	lastMaintenanceParams api.PostAdminMaintenanceParams
+	lastTicketID          openapi_types.UUID
+	lastPatchedTicketID   openapi_types.UUID
+	lastReissueParams     api.PostTicketReissueParams
+	lastRefundParams      api.PostTicketTicketIdRefundParams
+	lastRevenueParams     api.GetRevenueOutstandingParams
+}
+
+func (d *dummyServer) GetTicketTicketId(c *gin.Context, ticketId openapi_types.UUID) {
+	d.lastTicketID = ticketId
+	c.JSON(http.StatusOK, gin.H{
+		"ticketId": ticketId.String(),
+	})
+}
+
+func (d *dummyServer) PatchTicketTicketId(c *gin.Context, ticketId openapi_types.UUID) {
+	d.lastPatchedTicketID = ticketId
+	c.JSON(http.StatusOK, gin.H{"ticketId": ticketId.String()})
+}
+
+func (d *dummyServer) PostTicketTicketIdRefund(c *gin.Context, ticketId openapi_types.UUID, params api.PostTicketTicketIdRefundParams) {
+	d.lastTicketID = ticketId
+	d.lastRefundParams = params
+	c.JSON(http.StatusOK, gin.H{"ticketId": ticketId.String()})
+}
+
+func (d *dummyServer) GetRevenueOutstanding(c *gin.Context, params api.GetRevenueOutstandingParams) {
+	d.lastRevenueParams = params
+	c.JSON(http.StatusOK, api.OutstandingRevenueResponse{ParkingLot: params.ParkingLot})
+}
+
+func (d *dummyServer) PostTicketReissue(c *gin.Context, params api.PostTicketReissueParams) {
+	d.lastReissueParams = params
+	c.JSON(http.StatusOK, api.ReissueResponse{})
+}
+
+func (d *dummyServer) GetExport(c *gin.Context, params api.GetExportParams) {
+	d.lastExportParams = params
+	c.String(http.StatusOK, "plate\n")
+}
+
+func (d *dummyServer) GetPlatePlateActive(c *gin.Context, plate string) {
+	d.lastActivePlate = plate
+	c.JSON(http.StatusOK, api.PlateActiveResponse{Plate: plate, Lots: []api.LotSessions{}})
+}
+
+func (d *dummyServer) GetPlatePlateHistory(c *gin.Context, plate string, params api.GetPlatePlateHistoryParams) {
+	d.lastHistoryPlate = plate
+	d.lastHistoryParams = params
+	c.JSON(http.StatusOK, api.PlateHistoryResponse{Plate: plate, Sessions: []api.TicketResponse{}})
+}
+
+func (d *dummyServer) PostAdminBlock(c *gin.Context, params api.PostAdminBlockParams) {
+	d.lastBlockParams = params
+	c.JSON(http.StatusOK, api.Block{ParkingLot: params.ParkingLot, Spaces: params.Spaces, EndTime: params.End})
+}
+
+func (d *dummyServer) PostAdminMaintenance(c *gin.Context, params api.PostAdminMaintenanceParams) {
+	d.lastMaintenanceParams = params
+	c.JSON(http.StatusOK, api.MaintenanceResponse{Maintenance: params.Enabled})
+}
+
+func (d *dummyServer) PostAdminOccupancyReconcile(c *gin.Context, params api.PostAdminOccupancyReconcileParams) {
+	c.JSON(http.StatusOK, api.OccupancyResponse{ParkingLot: params.ParkingLot})
+}
+
+func (d *dummyServer) DeleteAdminTicket(c *gin.Context, params api.DeleteAdminTicketParams) {
+	c.JSON(http.StatusOK, gin.H{"plate": params.Plate})
 }
 
 func (d *dummyServer) PostEntry(c *gin.Context, params api.PostEntryParams) {
@@ -25,6 +105,11 @@ func (d *dummyServer) PostEntry(c *gin.Context, params api.PostEntryParams) {
 	})
 }
 
+func (d *dummyServer) PostEntryBatch(c *gin.Context) {
+	d.batchCalled = true
+	c.JSON(http.StatusMultiStatus, api.BatchEntryResponse{})
+}
+
 func (d *dummyServer) PostExit(c *gin.Context, params api.PostExitParams) {
 	d.lastExitParams = params
 	c.JSON(http.StatusOK, gin.H{
@@ -32,6 +117,25 @@ func (d *dummyServer) PostExit(c *gin.Context, params api.PostExitParams) {
 	})
 }
 
+func (d *dummyServer) PostExitTransponder(c *gin.Context, params api.PostExitTransponderParams) {
+	c.JSON(http.StatusOK, gin.H{
+		"transponderId": params.TransponderId,
+	})
+}
+
+func (d *dummyServer) PostQuote(c *gin.Context, params api.PostQuoteParams) {
+	d.lastQuoteParams = params
+	c.JSON(http.StatusOK, api.QuoteResponse{})
+}
+
+func (d *dummyServer) GetLivez(c *gin.Context) {
+	c.JSON(http.StatusOK, api.HealthResponse{Status: "ok"})
+}
+
+func (d *dummyServer) GetReadyz(c *gin.Context) {
+	c.JSON(http.StatusOK, api.HealthResponse{Status: "ok"})
+}
+
 func setupRouter(si api.ServerInterface) *gin.Engine {
 	gin.SetMode(gin.TestMode)
 	r := gin.New()
@@ -41,13 +145,26 @@ func setupRouter(si api.ServerInterface) *gin.Engine {
 }
 
 func TestPostEntry_MissingPlate(t *testing.T) {
+	d := &dummyServer{}
+	r := setupRouter(d)
+	// The plate is optional so anonymous entries reach the handler, which decides whether to allow them
+	req := httptest.NewRequest("POST", "/entry?parkingLot=123", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Nil(t, d.lastEntryParams.Plate)
+	assert.Equal(t, 123, d.lastEntryParams.ParkingLot)
+}
+
+func TestPostEntry_MissingParkingLot(t *testing.T) {
 	r := setupRouter(&dummyServer{})
-	req := httptest.NewRequest("POST", "/entry", nil)
+	req := httptest.NewRequest("POST", "/entry?plate=foo", nil)
 	w := httptest.NewRecorder()
 	r.ServeHTTP(w, req)
 
 	assert.Equal(t, http.StatusBadRequest, w.Code)
-	assert.Contains(t, w.Body.String(), `plate is required`)
+	assert.Contains(t, w.Body.String(), `parkingLot is required`)
 }
 
 func TestPostEntry_InvalidParkingLot(t *testing.T) {
@@ -74,10 +191,114 @@ func TestPostEntry_Success(t *testing.T) {
 	assert.Contains(t, w.Body.String(), `"plate":"bar"`)
 	assert.Contains(t, w.Body.String(), `"parkingLot":123`)
 	// dummyServer should have lastEntryParams set
-	assert.Equal(t, "bar", d.lastEntryParams.Plate)
+	if assert.NotNil(t, d.lastEntryParams.Plate) {
+		assert.Equal(t, "bar", *d.lastEntryParams.Plate)
+	}
 	assert.Equal(t, 123, d.lastEntryParams.ParkingLot)
 }
 
+func TestPostEntry_Spaces(t *testing.T) {
+	d := &dummyServer{}
+	r := setupRouter(d)
+	req := httptest.NewRequest("POST", "/entry?plate=bar&parkingLot=123&spaces=2", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	if assert.NotNil(t, d.lastEntryParams.Spaces) {
+		assert.Equal(t, 2, *d.lastEntryParams.Spaces)
+	}
+
+	req = httptest.NewRequest("POST", "/entry?plate=bar&parkingLot=123&spaces=big", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `Invalid format for parameter spaces`)
+}
+
+func TestPostEntryBatch_Routed(t *testing.T) {
+	d := &dummyServer{}
+	r := setupRouter(d)
+	req := httptest.NewRequest("POST", "/entry/batch", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusMultiStatus, w.Code)
+	assert.True(t, d.batchCalled)
+}
+
+func TestPatchTicket_Routed(t *testing.T) {
+	d := &dummyServer{}
+	r := setupRouter(d)
+	req := httptest.NewRequest("PATCH", "/ticket/00000000-0000-0000-0000-000000000001", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "00000000-0000-0000-0000-000000000001", d.lastPatchedTicketID.String())
+}
+
+func TestPostTicketReissue_Routed(t *testing.T) {
+	d := &dummyServer{}
+	r := setupRouter(d)
+	req := httptest.NewRequest("POST", "/ticket/reissue?plate=ABC-123", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "ABC-123", d.lastReissueParams.Plate)
+}
+
+func TestPostTicketRefund_Params(t *testing.T) {
+	d := &dummyServer{}
+	r := setupRouter(d)
+	req := httptest.NewRequest("POST", "/ticket/00000000-0000-0000-0000-000000000001/refund?amount=2.5", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "00000000-0000-0000-0000-000000000001", d.lastTicketID.String())
+	if assert.NotNil(t, d.lastRefundParams.Amount) {
+		assert.Equal(t, float32(2.5), *d.lastRefundParams.Amount)
+	}
+}
+
+func TestGetPlateActive_Routed(t *testing.T) {
+	d := &dummyServer{}
+	r := setupRouter(d)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/plate/ABC%20123/active", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "ABC 123", d.lastActivePlate)
+	assert.Empty(t, d.lastHistoryPlate)
+}
+
+func TestGetPlateHistory_Params(t *testing.T) {
+	d := &dummyServer{}
+	r := setupRouter(d)
+	req := httptest.NewRequest("GET", "/plate/ABC%20123/history?limit=5", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "ABC 123", d.lastHistoryPlate)
+	if assert.NotNil(t, d.lastHistoryParams.Limit) {
+		assert.Equal(t, 5, *d.lastHistoryParams.Limit)
+	}
+}
+
+func TestGetPlateHistory_InvalidLimit(t *testing.T) {
+	r := setupRouter(&dummyServer{})
+	req := httptest.NewRequest("GET", "/plate/ABC-123/history?limit=many", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `Invalid format for parameter limit`)
+}
+
 func TestPostExit_MissingTicketID(t *testing.T) {
 	r := setupRouter(&dummyServer{})
 	req := httptest.NewRequest("POST", "/exit", nil)
@@ -101,12 +322,122 @@ func TestPostExit_InvalidTicketID(t *testing.T) {
 func TestPostExit_Success(t *testing.T) {
 	d := &dummyServer{}
 	r := setupRouter(d)
-	// Create a valid UUID
-	req := httptest.NewRequest("POST", "/exit?ticketId=00000000-0000-0000-0000-000000000000", nil)
+	// Any well-formed UUID binds; the handler itself rejects the nil UUID
+	req := httptest.NewRequest("POST", "/exit?ticketId=123e4567-e89b-12d3-a456-426614174000", nil)
 	w := httptest.NewRecorder()
 	r.ServeHTTP(w, req)
 
 	assert.Equal(t, http.StatusOK, w.Code)
 	// Response JSON should contain ticketId
-	assert.Contains(t, w.Body.String(), `"ticketId":"00000000-0000-0000-0000-000000000000"`)
+	assert.Contains(t, w.Body.String(), `"ticketId":"123e4567-e89b-12d3-a456-426614174000"`)
+}
+
+func TestPostAdminMaintenance_MissingEnabled(t *testing.T) {
+	r := setupRouter(&dummyServer{})
+	req := httptest.NewRequest("POST", "/admin/maintenance", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `enabled is required`)
+}
+
+func TestPostAdminMaintenance_Success(t *testing.T) {
+	d := &dummyServer{}
+	r := setupRouter(d)
+	req := httptest.NewRequest("POST", "/admin/maintenance?enabled=true", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.True(t, d.lastMaintenanceParams.Enabled)
+	assert.JSONEq(t, `{"maintenance":true}`, w.Body.String())
+}
+
+func TestPostAdminBlock_Params(t *testing.T) {
+	d := &dummyServer{}
+	r := setupRouter(d)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("POST", "/admin/block?parkingLot=3&spaces=40&end=2025-03-01T23:00:00Z", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, 3, d.lastBlockParams.ParkingLot)
+	assert.Equal(t, 40, d.lastBlockParams.Spaces)
+	assert.Nil(t, d.lastBlockParams.Start)
+	assert.True(t, time.Date(2025, 3, 1, 23, 0, 0, 0, time.UTC).Equal(d.lastBlockParams.End))
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("POST", "/admin/block?parkingLot=3&spaces=40", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `end is required`)
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("POST", "/admin/block?parkingLot=3&spaces=40&start=tonight&end=2025-03-01T23:00:00Z", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `Invalid format for parameter start`)
+}
+
+func TestGetTicket_InvalidTicketID(t *testing.T) {
+	r := setupRouter(&dummyServer{})
+	req := httptest.NewRequest("GET", "/ticket/not-a-uuid", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `Invalid format for parameter ticketId`)
+}
+
+func TestGetTicket_Success(t *testing.T) {
+	d := &dummyServer{}
+	r := setupRouter(d)
+	req := httptest.NewRequest("GET", "/ticket/00000000-0000-0000-0000-000000000001", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "00000000-0000-0000-0000-000000000001", d.lastTicketID.String())
+}
+
+func TestPostQuote_InvalidTicketID(t *testing.T) {
+	r := setupRouter(&dummyServer{})
+	req := httptest.NewRequest("POST", "/quote?ticketId=notuuid", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `Invalid format for parameter ticketId`)
+}
+
+func TestPostQuote_Success(t *testing.T) {
+	d := &dummyServer{}
+	r := setupRouter(d)
+	req := httptest.NewRequest("POST", "/quote?ticketId=00000000-0000-0000-0000-000000000002", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "00000000-0000-0000-0000-000000000002", d.lastQuoteParams.TicketId.String())
+}
+
+func TestGetExport_MissingFrom(t *testing.T) {
+	r := setupRouter(&dummyServer{})
+	req := httptest.NewRequest("GET", "/export?to=2024-05-31", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `from is required`)
+}
+
+func TestGetExport_Success(t *testing.T) {
+	d := &dummyServer{}
+	r := setupRouter(d)
+	req := httptest.NewRequest("GET", "/export?from=2024-05-01&to=2024-05-31&format=csv", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "2024-05-01", d.lastExportParams.From.String())
+	assert.Equal(t, "2024-05-31", d.lastExportParams.To.String())
+	assert.Equal(t, api.Csv, *d.lastExportParams.Format)
 }
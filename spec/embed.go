@@ -0,0 +1,9 @@
+// Package spec embeds the OpenAPI document the API server is generated from
+package spec
+
+import _ "embed"
+
+// OpenAPI is the raw OpenAPI document, used to validate requests at runtime
+//
+//go:embed openapi.yaml
+var OpenAPI []byte
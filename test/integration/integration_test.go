@@ -6,6 +6,7 @@ package integration
 import (
 	"context"
 	"fmt"
+	"net"
 	"net/http"
 	"os"
 	"testing"
@@ -40,9 +41,16 @@ func TestEndToEndFlow(t *testing.T) {
 	plate := fmt.Sprintf("TEST-%s", uuid.New().String()[:8])
 	parkingLot := 999
 
+	// Remove the test tickets afterwards when ALLOW_DESTRUCTIVE_ADMIN=true
+	t.Cleanup(func() {
+		if err := parkingService.ResetLot(context.Background(), parkingLot); err != nil {
+			t.Logf("Test tickets were not cleaned up: %v", err)
+		}
+	})
+
 	// Step 1: Create a ticket (Entry)
 	t.Log("Testing entry...")
-	ticketID, ticket := parkingService.CreateTicket(ctx, plate, parkingLot)
+	ticketID, ticket := parkingService.CreateTicket(ctx, plate, parkingLot, 1)
 	
 	assert.NotNil(t, ticketID)
 	assert.Equal(t, plate, ticket.Plate)
@@ -92,45 +100,29 @@ func TestAPIAdapter(t *testing.T) {
 	// Create the adapter
 	adapter := lambda.NewAPIAdapter() // Corrected constructor name
 	
-	// Start the server in a goroutine
-	serverReady := make(chan bool)
-	serverExit := make(chan bool)
-	
-	go func() {
-		// Start the server (simplified for testing)
-		server := &http.Server{
-			Addr:    ":8085", // Use a different port for tests
-			Handler: adapter.Router(), // Assuming Router() method returns the router
-		}
-		
-		// Signal that the server is ready
-		serverReady <- true
-		
-		// Wait for the exit signal
-		<-serverExit
-		
-		// Shut down the server
-		server.Shutdown(context.Background())
-	}()
-	
-	// Wait for the server to be ready
-	<-serverReady
-	
+	// Serve on LOCAL_ADDR, or on a free port so the test does not clash with other local services
+	addr := os.Getenv("LOCAL_ADDR")
+	if addr == "" {
+		addr = "127.0.0.1:0"
+	}
+	listener, err := net.Listen("tcp", addr)
+	require.NoError(t, err, "Failed to bind test server")
+
+	server := &http.Server{
+		Handler: adapter.Router(),
+	}
+	go server.Serve(listener)
+	defer server.Shutdown(context.Background())
+
 	// Make test requests
 	client := &http.Client{}
-	
-	// Give the server a moment to start
-	time.Sleep(100 * time.Millisecond)
-	
+
 	// Test with a real request
-	resp, err := client.Get("http://localhost:8085/healthz")
+	resp, err := client.Get("http://" + listener.Addr().String() + "/livez")
 	if err == nil {
 		defer resp.Body.Close()
 		assert.Equal(t, http.StatusOK, resp.StatusCode)
 	} else {
 		t.Logf("Error making request: %v", err)
 	}
-	
-	// Signal to stop the server
-	serverExit <- true
 }